@@ -54,6 +54,11 @@ function App() {
       console.log('✅ 系统健康检查完成');
     } catch (error) {
       console.error('❌ 系统健康检查失败:', error);
+      // 系统不健康时Master返回503，响应体中仍带有各项检查结果
+      if (error.response?.data?.data) {
+        setSystemHealth(error.response.data);
+        return;
+      }
       setSystemHealth({ success: false, message: t('system.connectionFailed') });
     }
   };
@@ -26,7 +26,8 @@ const Sidebar = ({ systemHealth, selectedWorker, workers, onWorkerSelect, onMenu
     switch (status) {
       case 'healthy': return 'text-success';
       case 'degraded': return 'text-warning';
-      case 'down': return 'text-error';
+      case 'down':
+      case 'unhealthy': return 'text-error';
       default: return 'text-text-secondary';
     }
   };
@@ -35,7 +36,8 @@ const Sidebar = ({ systemHealth, selectedWorker, workers, onWorkerSelect, onMenu
     switch (status) {
       case 'healthy': return t('status.health.healthy');
       case 'degraded': return t('status.health.degraded');
-      case 'down': return t('status.health.down');
+      case 'down':
+      case 'unhealthy': return t('status.health.down');
       default: return t('status.health.unknown');
     }
   };
@@ -79,9 +81,9 @@ const Sidebar = ({ systemHealth, selectedWorker, workers, onWorkerSelect, onMenu
         <div className="flex items-center justify-between">
           <span className="text-sm font-medium text-text-secondary">{t('sidebar.system.status')}</span>
           <div className="flex items-center space-x-2 px-2 py-1 rounded-full bg-bg">
-            <Circle className={`w-2.5 h-2.5 fill-current ${getStatusColor(systemHealth)}`} />
-            <span className={`text-xs font-semibold ${getStatusColor(systemHealth)}`}>
-              {getStatusText(systemHealth)}
+            <Circle className={`w-2.5 h-2.5 fill-current ${getStatusColor(systemHealth?.data?.status)}`} />
+            <span className={`text-xs font-semibold ${getStatusColor(systemHealth?.data?.status)}`}>
+              {getStatusText(systemHealth?.data?.status)}
             </span>
           </div>
         </div>
@@ -0,0 +1,174 @@
+// fleet-agent 运行在远程主机上，替Master执行docker命令并上报主机资源
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"whatsapp-aggregator/internal/hoststat"
+	"whatsapp-aggregator/internal/model"
+)
+
+// 单条docker命令默认和最长执行时间
+const (
+	defaultCommandTimeout = time.Minute
+	maxCommandTimeout     = 30 * time.Minute
+)
+
+func main() {
+	logger := slog.New(slog.NewJSONHandler(os.Stdout, nil))
+	slog.SetDefault(logger)
+
+	token := os.Getenv("AGENT_TOKEN")
+	if token == "" {
+		slog.Error("AGENT_TOKEN is required")
+		os.Exit(1)
+	}
+	addr := os.Getenv("AGENT_ADDR")
+	if addr == "" {
+		addr = ":7070"
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /status", handleStatus)
+	mux.HandleFunc("POST /docker", handleDocker)
+	mux.HandleFunc("POST /docker/stream", handleDockerStream)
+
+	srv := &http.Server{
+		Addr:              addr,
+		Handler:           requireToken(token, mux),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	go func() {
+		slog.Info("Agent listening", "addr", addr)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Failed to start agent", "error", err)
+			os.Exit(1)
+		}
+	}()
+
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		slog.Error("Agent forced to shutdown", "error", err)
+	}
+	slog.Info("Agent exited")
+}
+
+// requireToken 校验 X-Agent-Token
+func requireToken(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("X-Agent-Token")), []byte(token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid agent token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// handleStatus 上报主机资源和Worker容器数
+func handleStatus(w http.ResponseWriter, r *http.Request) {
+	status := hoststat.Collect()
+	out, err := exec.CommandContext(r.Context(), "docker", "ps", "-q", "--filter", "name=^/whatsapp-worker-").Output()
+	if err != nil {
+		writeJSON(w, http.StatusServiceUnavailable, map[string]string{"error": "docker unavailable: " + err.Error()})
+		return
+	}
+	status.Containers = len(strings.Fields(string(out)))
+	writeJSON(w, http.StatusOK, status)
+}
+
+// handleDocker 执行Master下发的docker命令，命令失败时通过exit_code返回而不是HTTP错误
+func handleDocker(w http.ResponseWriter, r *http.Request) {
+	var req model.AgentDockerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Args) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "args are required"})
+		return
+	}
+
+	timeout := defaultCommandTimeout
+	if req.TimeoutSeconds > 0 {
+		timeout = min(time.Duration(req.TimeoutSeconds)*time.Second, maxCommandTimeout)
+	}
+	ctx, cancel := context.WithTimeout(r.Context(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "docker", req.Args...)
+	if len(req.Stdin) > 0 {
+		cmd.Stdin = bytes.NewReader(req.Stdin)
+	}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	result := model.AgentDockerResult{}
+	if err := cmd.Run(); err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			result.ExitCode = exitErr.ExitCode()
+		} else {
+			result.ExitCode = -1
+			result.Error = err.Error()
+		}
+	}
+	result.Stdout = stdout.String()
+	result.Stderr = stderr.String()
+	slog.Info("Docker command executed", "command", req.Args[0], "exit_code", result.ExitCode)
+	writeJSON(w, http.StatusOK, result)
+}
+
+// handleDockerStream 执行长时间运行的docker命令（如 logs -f），逐块返回合并后的标准输出和标准错误，Master断开时终止命令
+func handleDockerStream(w http.ResponseWriter, r *http.Request) {
+	var req model.AgentDockerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Args) == 0 {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "args are required"})
+		return
+	}
+
+	out := &flushWriter{w: w}
+	out.flusher, _ = w.(http.Flusher)
+	cmd := exec.CommandContext(r.Context(), "docker", req.Args...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	err := cmd.Run()
+	slog.Info("Docker stream finished", "command", req.Args[0], "error", err)
+}
+
+// flushWriter 每次写入后立即刷新响应
+type flushWriter struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+}
+
+func (f *flushWriter) Write(p []byte) (int, error) {
+	n, err := f.w.Write(p)
+	if f.flusher != nil {
+		f.flusher.Flush()
+	}
+	return n, err
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
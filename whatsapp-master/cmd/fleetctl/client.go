@@ -0,0 +1,181 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// apiPrefix Master API 路径前缀
+const apiPrefix = "/api/v1"
+
+// apiResponse Master统一响应，data保留原始JSON按命令解码
+type apiResponse struct {
+	Success bool            `json:"success"`
+	Code    string          `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error"`
+	Warning string          `json:"warning"`
+	Meta    *model.ListMeta `json:"meta"`
+}
+
+// client Master API 客户端
+type client struct {
+	server  string
+	profile *profile
+	http    *http.Client
+}
+
+// newClient 创建客户端
+func newClient(p *profile) *client {
+	return &client{
+		server:  strings.TrimRight(p.Server, "/"),
+		profile: p,
+		http:    &http.Client{Timeout: 6 * time.Minute},
+	}
+}
+
+// newRequest 创建带鉴权请求头的请求
+func (c *client) newRequest(ctx context.Context, method, path string, query url.Values, body interface{}) (*http.Request, error) {
+	target := c.server + apiPrefix + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.profile.AdminToken != "" {
+		req.Header.Set("X-Admin-Token", c.profile.AdminToken)
+	}
+	if c.profile.APIKey != "" {
+		req.Header.Set("X-API-Key", c.profile.APIKey)
+	}
+	return req, nil
+}
+
+// do 调用Master接口并把data解码到out（out为nil时忽略），失败响应返回Master的错误信息
+func (c *client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) (*apiResponse, error) {
+	req, err := c.newRequest(ctx, method, path, query, body)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request to %s failed: %v", c.server, err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %v", err)
+	}
+	var result apiResponse
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("unexpected response (HTTP %d): %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+	if resp.StatusCode >= 300 || !result.Success {
+		msg := result.Message
+		if result.Error != "" {
+			msg += ": " + result.Error
+		}
+		return &result, fmt.Errorf("HTTP %d: %s", resp.StatusCode, msg)
+	}
+	if out != nil && len(result.Data) > 0 {
+		if err := json.Unmarshal(result.Data, out); err != nil {
+			return &result, fmt.Errorf("failed to decode response data: %v", err)
+		}
+	}
+	return &result, nil
+}
+
+// listAll 按游标翻页获取列表接口的全部数据
+func (c *client) listAll(ctx context.Context, path string, query url.Values) ([]json.RawMessage, error) {
+	if query == nil {
+		query = url.Values{}
+	}
+	query.Set("limit", "200")
+
+	items := make([]json.RawMessage, 0)
+	for {
+		var page []json.RawMessage
+		resp, err := c.do(ctx, http.MethodGet, path, query, nil, &page)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, page...)
+		if resp.Meta == nil || resp.Meta.NextCursor == "" {
+			return items, nil
+		}
+		query.Set("cursor", resp.Meta.NextCursor)
+	}
+}
+
+// stream 读取SSE接口，每收到一条data调用一次onData，直到连接关闭或ctx取消
+func (c *client) stream(ctx context.Context, path string, query url.Values, onData func(event, data string)) error {
+	req, err := c.newRequest(ctx, http.MethodGet, path, query, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	// 流式接口不设置整体超时
+	resp, err := (&http.Client{}).Do(req)
+	if err != nil {
+		return fmt.Errorf("request to %s failed: %v", c.server, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		raw, _ := io.ReadAll(resp.Body)
+		var result apiResponse
+		if json.Unmarshal(raw, &result) == nil && result.Message != "" {
+			return fmt.Errorf("HTTP %d: %s: %s", resp.StatusCode, result.Message, result.Error)
+		}
+		return fmt.Errorf("HTTP %d: %s", resp.StatusCode, strings.TrimSpace(string(raw)))
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 4*1024*1024)
+	event := ""
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			if len(data) > 0 {
+				onData(event, strings.Join(data, "\n"))
+			}
+			event, data = "", nil
+		case strings.HasPrefix(line, "event:"):
+			event = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return err
+	}
+	return nil
+}
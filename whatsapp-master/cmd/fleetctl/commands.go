@@ -0,0 +1,569 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// jobPollInterval 等待后台任务完成时的轮询间隔
+const jobPollInterval = 2 * time.Second
+
+// runAccounts accounts list / accounts get
+func runAccounts(e *env, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		fs := flag.NewFlagSet("accounts list", flag.ContinueOnError)
+		status := fs.String("status", "", "")
+		pool := fs.String("pool", "", "")
+		tag := fs.String("tag", "", "")
+		sortBy := fs.String("sort", "", "")
+		if _, err := parseArgs(fs, args[1:]); err != nil {
+			return err
+		}
+
+		query := url.Values{}
+		if *status != "" {
+			query.Set("filter[status]", *status)
+		}
+		if *pool != "" {
+			query.Set("filter[pool]", *pool)
+		}
+		if *tag != "" {
+			query.Set("filter[tags]", *tag)
+		}
+		if *sortBy != "" {
+			query.Set("sort", *sortBy)
+		}
+		items, err := e.client.listAll(e.ctx, "/accounts", query)
+		if err != nil {
+			return err
+		}
+
+		accounts := make([]*model.Account, 0, len(items))
+		rows := make([][]string, 0, len(items))
+		for _, item := range items {
+			var account model.Account
+			if err := json.Unmarshal(item, &account); err != nil {
+				return fmt.Errorf("failed to decode account: %v", err)
+			}
+			accounts = append(accounts, &account)
+			rows = append(rows, []string{
+				account.ID,
+				account.Status,
+				orDash(account.Phone),
+				orDash(account.Pool),
+				orDash(account.HostID),
+				orDash(formatWorkerVersion(&account)),
+				strconv.Itoa(account.MessagesSent),
+				strconv.Itoa(account.MessagesReceived),
+				formatTime(account.LastActivity),
+			})
+		}
+		return e.out.print(accounts, []string{"ID", "STATUS", "PHONE", "POOL", "HOST", "VERSION", "SENT", "RECEIVED", "LAST ACTIVITY"}, rows)
+
+	case "get":
+		positional, err := parseArgs(flag.NewFlagSet("accounts get", flag.ContinueOnError), args[1:])
+		if err != nil {
+			return err
+		}
+		if len(positional) != 1 {
+			return errUsage
+		}
+
+		var account model.Account
+		if _, err := e.client.do(e.ctx, http.MethodGet, "/accounts/"+url.PathEscape(positional[0]), nil, nil, &account); err != nil {
+			return err
+		}
+		return e.out.printFields(&account, [][2]string{
+			{"ID", account.ID},
+			{"Name", orDash(account.Name)},
+			{"Notes", orDash(account.Notes)},
+			{"Status", account.Status},
+			{"Phone", orDash(account.Phone)},
+			{"Pool", orDash(account.Pool)},
+			{"Tenant", orDash(account.TenantID)},
+			{"Host", orDash(account.HostID)},
+			{"Service URL", orDash(account.ServiceURL)},
+			{"Worker version", orDash(formatWorkerVersion(&account))},
+			{"Proxy", orDash(formatProxy(account.Proxy))},
+			{"Tags", orDash(strings.Join(account.Tags, ","))},
+			{"Disabled", strconv.FormatBool(account.Disabled)},
+			{"Messages sent", strconv.Itoa(account.MessagesSent)},
+			{"Messages received", strconv.Itoa(account.MessagesReceived)},
+			{"Session started", formatTime(account.SessionStartedAt)},
+			{"Restarts", strconv.Itoa(account.RestartCount)},
+			{"Last activity", formatTime(account.LastActivity)},
+			{"Created", formatTime(&account.CreatedAt)},
+		})
+	}
+	return errUsage
+}
+
+// formatWorkerVersion Worker上报的版本，API版本不兼容时标出
+func formatWorkerVersion(account *model.Account) string {
+	version := account.WorkerVersion
+	if version == "" && !account.WorkerIncompatible {
+		return ""
+	}
+	if version == "" {
+		version = "unknown"
+	}
+	if account.WorkerIncompatible {
+		return fmt.Sprintf("%s (incompatible, api %d)", version, account.WorkerAPIVersion)
+	}
+	return version
+}
+
+// formatProxy 账号绑定的代理，如 socks5://1.2.3.4:1080
+func formatProxy(proxy model.AccountProxy) string {
+	if proxy.IP == "" {
+		return ""
+	}
+	protocol := proxy.Protocol
+	if protocol == "" {
+		protocol = "socks5"
+	}
+	return fmt.Sprintf("%s://%s", protocol, net.JoinHostPort(proxy.IP, strconv.Itoa(proxy.Port)))
+}
+
+// runLogin 发起手机号登录并打印配对码
+func runLogin(e *env, args []string) error {
+	fs := flag.NewFlagSet("login", flag.ContinueOnError)
+	proxy := fs.String("proxy", "", "")
+	proxyUser := fs.String("proxy-user", "", "")
+	proxyPass := fs.String("proxy-pass", "", "")
+	proxyProtocol := fs.String("proxy-protocol", "", "")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errUsage
+	}
+
+	req := model.PhoneLoginRequest{
+		LoginPhone: positional[0],
+		SigninType: 40,
+	}
+	if *proxy != "" {
+		host, port, err := net.SplitHostPort(*proxy)
+		if err != nil {
+			return fmt.Errorf("invalid --proxy %q, expected HOST:PORT", *proxy)
+		}
+		portNumber, err := strconv.Atoi(port)
+		if err != nil {
+			return fmt.Errorf("invalid proxy port %q", port)
+		}
+		req.ProxyConfig = model.ProxyConfig{
+			IP:       host,
+			Port:     portNumber,
+			Username: *proxyUser,
+			Password: *proxyPass,
+			Protocol: *proxyProtocol,
+		}
+	}
+
+	var result struct {
+		Account     *model.Account         `json:"account"`
+		LoginResult map[string]interface{} `json:"login_result"`
+	}
+	if _, err := e.client.do(e.ctx, http.MethodPost, "/phone-login", nil, req, &result); err != nil {
+		return err
+	}
+
+	fields := [][2]string{}
+	if result.Account != nil {
+		fields = append(fields, [2]string{"Account", result.Account.ID}, [2]string{"Status", result.Account.Status})
+	}
+	for _, key := range []string{"status", "pairing_code", "message"} {
+		if value, ok := result.LoginResult[key].(string); ok && value != "" {
+			fields = append(fields, [2]string{strings.ReplaceAll(key, "_", " "), value})
+		}
+	}
+	return e.out.printFields(result, fields)
+}
+
+// runSend 发送文本消息
+func runSend(e *env, args []string) error {
+	positional, err := parseArgs(flag.NewFlagSet("send", flag.ContinueOnError), args)
+	if err != nil {
+		return err
+	}
+	if len(positional) < 3 {
+		return errUsage
+	}
+
+	req := model.MessageRequest{
+		AccountID: positional[0],
+		Contact:   positional[1],
+		Message:   strings.Join(positional[2:], " "),
+	}
+	var result map[string]interface{}
+	resp, err := e.client.do(e.ctx, http.MethodPost, "/send-message", nil, req, &result)
+	if err != nil {
+		return err
+	}
+	if resp.Warning != "" {
+		fmt.Fprintln(os.Stderr, "warning:", resp.Warning)
+	}
+	messageID, _ := result["message_id"].(string)
+	return e.out.printFields(result, [][2]string{
+		{"Account", req.AccountID},
+		{"Contact", req.Contact},
+		{"Message ID", orDash(messageID)},
+	})
+}
+
+// runLogs 打印Worker最近的日志，-f 时通过日志流持续跟随
+func runLogs(e *env, args []string) error {
+	fs := flag.NewFlagSet("logs", flag.ContinueOnError)
+	follow := fs.Bool("f", false, "")
+	fs.BoolVar(follow, "follow", false, "")
+	tail := fs.Int("tail", -1, "")
+	since := fs.String("since", "", "")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(positional) != 1 {
+		return errUsage
+	}
+
+	if *follow || *tail >= 0 || *since != "" {
+		query := url.Values{"follow": {strconv.FormatBool(*follow)}}
+		if *tail >= 0 {
+			query.Set("tail", strconv.Itoa(*tail))
+		}
+		if *since != "" {
+			query.Set("since", *since)
+		}
+		var endErr string
+		err := e.client.stream(e.ctx, "/accounts/"+url.PathEscape(positional[0])+"/logs/stream", query, func(event, data string) {
+			switch event {
+			case "log":
+				fmt.Println(data)
+			case "end":
+				var end struct {
+					Error string `json:"error"`
+				}
+				json.Unmarshal([]byte(data), &end)
+				endErr = end.Error
+			}
+		})
+		if err != nil {
+			return err
+		}
+		if endErr != "" {
+			return fmt.Errorf("log stream ended: %s", endErr)
+		}
+		return nil
+	}
+
+	var logs interface{}
+	if _, err := e.client.do(e.ctx, http.MethodGet, "/accounts/"+url.PathEscape(positional[0])+"/logs", nil, nil, &logs); err != nil {
+		return err
+	}
+	if e.out.format == outputJSON {
+		return printJSON(logs)
+	}
+
+	lines, ok := logs.([]interface{})
+	if !ok {
+		return printJSON(logs)
+	}
+	for _, line := range lines {
+		if text, ok := line.(string); ok {
+			fmt.Println(text)
+			continue
+		}
+		data, _ := json.Marshal(line)
+		fmt.Println(string(data))
+	}
+	return nil
+}
+
+// runEvents 持续打印实时事件，Ctrl-C 退出
+func runEvents(e *env, args []string) error {
+	fs := flag.NewFlagSet("events", flag.ContinueOnError)
+	account := fs.String("account", "", "")
+	types := fs.String("types", "", "")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	if *account != "" {
+		query.Set("account_id", *account)
+	}
+	if *types != "" {
+		query.Set("types", *types)
+	}
+	return e.client.stream(e.ctx, "/events", query, func(_, data string) {
+		if e.out.format == outputJSON {
+			fmt.Println(data)
+			return
+		}
+		var event model.Event
+		if err := json.Unmarshal([]byte(data), &event); err != nil {
+			fmt.Println(data)
+			return
+		}
+		detail := ""
+		if event.Data != nil {
+			raw, _ := json.Marshal(event.Data)
+			detail = string(raw)
+		}
+		fmt.Printf("%s  %-24s %-16s %s\n", event.Timestamp.Local().Format("15:04:05"), event.Type, orDash(event.AccountID), detail)
+	})
+}
+
+// runRestart 重启单个账号的Worker或全部Worker，--wait 等待任务完成
+func runRestart(e *env, args []string) error {
+	fs := flag.NewFlagSet("restart", flag.ContinueOnError)
+	all := fs.Bool("all", false, "")
+	wait := fs.Bool("wait", false, "")
+	positional, err := parseArgs(fs, args)
+	if err != nil {
+		return err
+	}
+
+	path := ""
+	switch {
+	case *all && len(positional) == 0:
+		path = "/system/restart-workers"
+	case !*all && len(positional) == 1:
+		path = "/accounts/" + url.PathEscape(positional[0]) + "/restart"
+	default:
+		return errUsage
+	}
+
+	var job model.Job
+	if _, err := e.client.do(e.ctx, http.MethodPost, path, nil, nil, &job); err != nil {
+		return err
+	}
+	if *wait {
+		for job.Status == "running" {
+			select {
+			case <-e.ctx.Done():
+				return e.ctx.Err()
+			case <-time.After(jobPollInterval):
+			}
+			if _, err := e.client.do(e.ctx, http.MethodGet, "/jobs/"+url.PathEscape(job.ID), nil, nil, &job); err != nil {
+				return err
+			}
+			if e.out.format == outputTable && job.Total > 0 {
+				fmt.Fprintf(os.Stderr, "\r%s: %d/%d", job.Status, job.Progress, job.Total)
+			}
+		}
+		if e.out.format == outputTable && job.Total > 0 {
+			fmt.Fprintln(os.Stderr)
+		}
+	}
+
+	if err := e.out.printFields(&job, [][2]string{
+		{"Job", job.ID},
+		{"Type", job.Type},
+		{"Status", job.Status},
+		{"Progress", fmt.Sprintf("%d/%d", job.Progress, job.Total)},
+		{"Error", orDash(job.Error)},
+	}); err != nil {
+		return err
+	}
+	if job.Status == "failed" {
+		return fmt.Errorf("job %s failed: %s", job.ID, job.Error)
+	}
+	return nil
+}
+
+// runStats 打印集群统计；指定日期、粒度或账号时打印每日统计时间序列，可用 -o csv 导出
+func runStats(e *env, args []string) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	from := fs.String("from", "", "")
+	to := fs.String("to", "", "")
+	granularity := fs.String("granularity", "", "")
+	account := fs.String("account", "", "")
+	if _, err := parseArgs(fs, args); err != nil {
+		return err
+	}
+
+	query := url.Values{}
+	for key, value := range map[string]string{"from": *from, "to": *to, "granularity": *granularity, "account_id": *account} {
+		if value != "" {
+			query.Set(key, value)
+		}
+	}
+
+	var stats model.FleetStats
+	if _, err := e.client.do(e.ctx, http.MethodGet, "/stats", query, nil, &stats); err != nil {
+		return err
+	}
+
+	if len(query) > 0 {
+		rows := make([][]string, 0, len(stats.Series))
+		for _, point := range stats.Series {
+			rows = append(rows, []string{
+				point.Period,
+				point.From,
+				point.To,
+				strconv.Itoa(point.Sent),
+				strconv.Itoa(point.Received),
+				strconv.Itoa(point.Failed),
+				strconv.FormatFloat(float64(point.UptimeSeconds)/3600, 'f', 1, 64),
+				strconv.Itoa(point.Accounts),
+			})
+		}
+		return e.out.print(stats.Series, []string{"PERIOD", "FROM", "TO", "SENT", "RECEIVED", "FAILED", "UPTIME HOURS", "ACCOUNTS"}, rows)
+	}
+
+	rows := [][]string{{"total", "-", strconv.Itoa(stats.TotalWorkers), strconv.Itoa(stats.OnlineWorkers), "-", "-", "-"}}
+	dimensions := make([]string, 0, len(stats.Breakdowns))
+	for dimension := range stats.Breakdowns {
+		dimensions = append(dimensions, dimension)
+	}
+	sort.Strings(dimensions)
+	for _, dimension := range dimensions {
+		buckets := stats.Breakdowns[dimension]
+		keys := make([]string, 0, len(buckets))
+		for key := range buckets {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			bucket := buckets[key]
+			rows = append(rows, []string{
+				dimension,
+				key,
+				strconv.Itoa(bucket.TotalWorkers),
+				strconv.Itoa(bucket.OnlineWorkers),
+				strconv.Itoa(bucket.LoggedInWorkers),
+				strconv.Itoa(bucket.MessagesSent),
+				strconv.Itoa(bucket.MessagesReceived),
+			})
+		}
+	}
+	if e.out.format == outputTable {
+		fmt.Printf("Messages sent today: %d\n\n", stats.TodayMessages)
+	}
+	return e.out.print(&stats, []string{"DIMENSION", "VALUE", "WORKERS", "ONLINE", "LOGGED IN", "SENT", "RECEIVED"}, rows)
+}
+
+// runProfile 管理保存的服务器配置
+func runProfile(cfg *cliConfig, out *printer, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	switch args[0] {
+	case "list", "ls":
+		names := make([]string, 0, len(cfg.Profiles))
+		for name := range cfg.Profiles {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		rows := make([][]string, 0, len(names))
+		for _, name := range names {
+			p := cfg.Profiles[name]
+			current := ""
+			if name == cfg.Current {
+				current = "*"
+			}
+			auth := "-"
+			switch {
+			case p.AdminToken != "":
+				auth = "admin token"
+			case p.APIKey != "":
+				auth = "api key"
+			}
+			rows = append(rows, []string{current, name, p.Server, auth})
+		}
+		// 不输出凭证
+		return out.print(rows, []string{"CURRENT", "NAME", "SERVER", "AUTH"}, rows)
+
+	case "set":
+		fs := flag.NewFlagSet("profile set", flag.ContinueOnError)
+		server := fs.String("server", "", "")
+		adminToken := fs.String("admin-token", "", "")
+		apiKey := fs.String("api-key", "", "")
+		positional, err := parseArgs(fs, args[1:])
+		if err != nil {
+			return err
+		}
+		if len(positional) != 1 {
+			return errUsage
+		}
+
+		name := positional[0]
+		p, exists := cfg.Profiles[name]
+		if !exists {
+			if *server == "" {
+				return fmt.Errorf("--server is required for a new profile")
+			}
+			p = &profile{}
+			cfg.Profiles[name] = p
+		}
+		if *server != "" {
+			p.Server = strings.TrimRight(*server, "/")
+		}
+		if *adminToken != "" {
+			p.AdminToken = *adminToken
+		}
+		if *apiKey != "" {
+			p.APIKey = *apiKey
+		}
+		if cfg.Current == "" {
+			cfg.Current = name
+		}
+		if err := saveConfig(cfg); err != nil {
+			return err
+		}
+		fmt.Printf("Profile %q saved\n", name)
+		return nil
+
+	case "use":
+		if len(args) != 2 {
+			return errUsage
+		}
+		if _, exists := cfg.Profiles[args[1]]; !exists {
+			return fmt.Errorf("profile %q not found", args[1])
+		}
+		cfg.Current = args[1]
+		if err := saveConfig(cfg); err != nil {
+			return err
+		}
+		fmt.Printf("Using profile %q\n", args[1])
+		return nil
+
+	case "delete", "rm":
+		if len(args) != 2 {
+			return errUsage
+		}
+		if _, exists := cfg.Profiles[args[1]]; !exists {
+			return fmt.Errorf("profile %q not found", args[1])
+		}
+		delete(cfg.Profiles, args[1])
+		if cfg.Current == args[1] {
+			cfg.Current = ""
+		}
+		if err := saveConfig(cfg); err != nil {
+			return err
+		}
+		fmt.Printf("Profile %q deleted\n", args[1])
+		return nil
+	}
+	return errUsage
+}
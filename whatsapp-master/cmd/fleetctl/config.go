@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// defaultServer 未配置时连接的Master地址
+const defaultServer = "http://localhost:8080"
+
+// profile 一个Master服务器的连接配置
+type profile struct {
+	Server     string `json:"server"`
+	AdminToken string `json:"admin_token,omitempty"`
+	APIKey     string `json:"api_key,omitempty"`
+}
+
+// cliConfig fleetctl 配置文件，保存多个服务器配置和当前使用的配置
+type cliConfig struct {
+	Current  string              `json:"current,omitempty"`
+	Profiles map[string]*profile `json:"profiles"`
+}
+
+// configPath 配置文件路径：FLEETCTL_CONFIG，否则为用户配置目录下的 fleetctl/config.json
+func configPath() (string, error) {
+	if path := os.Getenv("FLEETCTL_CONFIG"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot locate config directory: %v", err)
+	}
+	return filepath.Join(dir, "fleetctl", "config.json"), nil
+}
+
+// loadConfig 读取配置文件，文件不存在时返回空配置
+func loadConfig() (*cliConfig, error) {
+	cfg := &cliConfig{Profiles: make(map[string]*profile)}
+	path, err := configPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %v", path, err)
+	}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %v", path, err)
+	}
+	if cfg.Profiles == nil {
+		cfg.Profiles = make(map[string]*profile)
+	}
+	return cfg, nil
+}
+
+// saveConfig 写入配置文件，文件中包含凭证，仅当前用户可读写
+func saveConfig(cfg *cliConfig) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+	data, err := json.MarshalIndent(cfg, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write %s: %v", path, err)
+	}
+	return nil
+}
+
+// resolveProfile 按 命令行参数 > 环境变量 > 配置文件 的优先级确定连接配置
+func resolveProfile(cfg *cliConfig, name, server string) (*profile, error) {
+	if name == "" {
+		name = os.Getenv("FLEETCTL_PROFILE")
+	}
+	explicit := name != ""
+	if name == "" {
+		name = cfg.Current
+	}
+
+	resolved := &profile{}
+	if p, exists := cfg.Profiles[name]; exists {
+		*resolved = *p
+	} else if explicit {
+		return nil, fmt.Errorf("profile %q not found", name)
+	}
+
+	if value := os.Getenv("FLEETCTL_SERVER"); value != "" {
+		resolved.Server = value
+	}
+	if value := os.Getenv("FLEETCTL_ADMIN_TOKEN"); value != "" {
+		resolved.AdminToken = value
+	}
+	if value := os.Getenv("FLEETCTL_API_KEY"); value != "" {
+		resolved.APIKey = value
+	}
+	if server != "" {
+		resolved.Server = server
+	}
+	if resolved.Server == "" {
+		resolved.Server = defaultServer
+	}
+	return resolved, nil
+}
@@ -0,0 +1,137 @@
+// fleetctl 运维命令行工具，通过Master API管理账号、登录、发送消息和查看统计
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const usage = `Usage: fleetctl [global flags] <command> [flags] [args]
+
+Commands:
+  accounts list [--status S] [--pool P] [--tag T] [--sort -last_activity]
+                                            List accounts
+  accounts get ID                           Show one account
+  login PHONE [--proxy HOST:PORT] [--proxy-user U] [--proxy-pass P] [--proxy-protocol socks5]
+                                            Start a phone login and print the pairing code
+  send ACCOUNT CONTACT MESSAGE              Send a text message
+  logs ACCOUNT [-f] [--tail N] [--since 10m]
+                                            Print the worker's recent logs, -f follows the container logs
+  events [--account IDS] [--types TYPES]    Follow the live event stream
+  restart ACCOUNT | --all [--wait]          Restart one worker or all workers
+  stats [--from DATE] [--to DATE] [--granularity day|week|month] [--account ID]
+                                            Show fleet stats, or daily counters as a time series
+  profile list                              List saved server profiles
+  profile set NAME --server URL [--admin-token T] [--api-key K]
+                                            Create or update a profile
+  profile use NAME                          Make a profile the default
+  profile delete NAME                       Remove a profile
+
+Global flags:
+  --profile NAME     Server profile (env FLEETCTL_PROFILE, default: the profile selected with "profile use")
+  --server URL       Master URL, overrides the profile (env FLEETCTL_SERVER)
+  -o, --output FMT   table, json or csv (env FLEETCTL_OUTPUT, default table)
+
+Credentials can also be given with FLEETCTL_ADMIN_TOKEN and FLEETCTL_API_KEY.
+Profiles are stored in FLEETCTL_CONFIG (default: <user config dir>/fleetctl/config.json).
+`
+
+// env 命令执行环境
+type env struct {
+	ctx    context.Context
+	client *client
+	out    *printer
+}
+
+// commands 需要连接Master的命令
+var commands = map[string]func(*env, []string) error{
+	"accounts": runAccounts,
+	"login":    runLogin,
+	"send":     runSend,
+	"logs":     runLogs,
+	"events":   runEvents,
+	"restart":  runRestart,
+	"stats":    runStats,
+}
+
+// errUsage 参数错误，打印用法后退出
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		if errors.Is(err, errUsage) || errors.Is(err, flag.ErrHelp) {
+			fmt.Fprint(os.Stderr, usage)
+			os.Exit(2)
+		}
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+// run 解析全局参数并执行命令
+func run(args []string) error {
+	fs := flag.NewFlagSet("fleetctl", flag.ContinueOnError)
+	fs.Usage = func() {}
+	profileName := fs.String("profile", "", "")
+	server := fs.String("server", "", "")
+	output := os.Getenv("FLEETCTL_OUTPUT")
+	if output == "" {
+		output = outputTable
+	}
+	fs.StringVar(&output, "o", output, "")
+	fs.StringVar(&output, "output", output, "")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 || fs.Arg(0) == "help" {
+		return errUsage
+	}
+	if !validOutput(output) {
+		return fmt.Errorf("unknown output format %q (table, json or csv)", output)
+	}
+
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+
+	name, rest := fs.Arg(0), fs.Args()[1:]
+	out := &printer{format: output}
+	if name == "profile" {
+		return runProfile(cfg, out, rest)
+	}
+	command, exists := commands[name]
+	if !exists {
+		return fmt.Errorf("unknown command %q, run fleetctl help", name)
+	}
+
+	p, err := resolveProfile(cfg, *profileName, *server)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return command(&env{ctx: ctx, client: newClient(p), out: out}, rest)
+}
+
+// parseArgs 解析子命令参数，允许标志写在位置参数之后
+func parseArgs(fs *flag.FlagSet, args []string) ([]string, error) {
+	fs.Usage = func() {}
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
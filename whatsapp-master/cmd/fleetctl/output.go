@@ -0,0 +1,95 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// 输出格式
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputCSV   = "csv"
+)
+
+// printer 按输出格式打印结果
+type printer struct {
+	format string
+}
+
+// validOutput 是否为支持的输出格式
+func validOutput(format string) bool {
+	switch format {
+	case outputTable, outputJSON, outputCSV:
+		return true
+	}
+	return false
+}
+
+// print 打印结果：json格式输出data原样，table/csv格式输出headers和rows
+func (p *printer) print(data interface{}, headers []string, rows [][]string) error {
+	switch p.format {
+	case outputJSON:
+		return printJSON(data)
+	case outputCSV:
+		w := csv.NewWriter(os.Stdout)
+		w.Write(headers)
+		w.WriteAll(rows)
+		return w.Error()
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, row := range rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	return w.Flush()
+}
+
+// printFields 打印单个对象：json格式输出data原样，其余格式每行一个字段
+func (p *printer) printFields(data interface{}, fields [][2]string) error {
+	if p.format == outputJSON {
+		return printJSON(data)
+	}
+	rows := make([][]string, 0, len(fields))
+	for _, field := range fields {
+		rows = append(rows, []string{field[0], field[1]})
+	}
+	if p.format == outputCSV {
+		return p.print(data, []string{"FIELD", "VALUE"}, rows)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintf(w, "%s:\t%s\n", row[0], row[1])
+	}
+	return w.Flush()
+}
+
+// printJSON 以缩进JSON打印
+func printJSON(data interface{}) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(data)
+}
+
+// formatTime 表格中的时间，空值显示为 -
+func formatTime(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return "-"
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+// orDash 空字符串显示为 -
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+	return value
+}
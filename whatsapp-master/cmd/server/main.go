@@ -1,8 +1,11 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -10,7 +13,9 @@ import (
 
 	"whatsapp-aggregator/internal/config"
 	"whatsapp-aggregator/internal/handler"
+	"whatsapp-aggregator/internal/logging"
 	"whatsapp-aggregator/internal/service"
+	"whatsapp-aggregator/internal/tracing"
 )
 
 // @title WhatsApp Aggregator API
@@ -30,15 +35,62 @@ import (
 func main() {
 	// 加载配置
 	cfg := config.Load()
+	if err := logging.Setup(cfg.Log.Level, cfg.Log.Format); err != nil {
+		slog.Error("Invalid log configuration", "error", err)
+		os.Exit(1)
+	}
+	// 数据库迁移子命令，不启动服务
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrate(cfg.DB, os.Args[2:]))
+	}
+	if cfg.Tracing.Exporter == "otlp" {
+		headers, err := tracing.ParseHeaders(cfg.Tracing.Headers)
+		if err != nil {
+			slog.Error("Invalid OTEL_EXPORTER_OTLP_HEADERS", "error", err)
+			os.Exit(1)
+		}
+		err = tracing.Setup(tracing.Config{
+			Endpoint:       cfg.Tracing.Endpoint,
+			Headers:        headers,
+			ServiceName:    cfg.Tracing.ServiceName,
+			ServiceVersion: service.BuildVersion(),
+			SampleRatio:    cfg.Tracing.SampleRatio,
+		})
+		if err != nil {
+			slog.Error("Failed to enable tracing", "error", err)
+			os.Exit(1)
+		}
+	}
 
 	// 创建服务管理器
 	manager, err := service.NewManager(cfg)
 	if err != nil {
-		log.Fatalf("Failed to create service manager: %v", err)
+		slog.Error("Failed to create service manager", "error", err)
+		os.Exit(1)
 	}
-	defer manager.Close()
 
-	manager.StartStatusPoller(5 * time.Minute)
+	// 成为Leader时对账Worker并继续之前的活动，多副本时只有Leader执行后台任务
+	manager.StartLeaderElection()
+
+	manager.StartStatusPoller()
+	manager.StartSessionRefresher()
+	manager.StartSessionKeepAlive()
+	manager.StartContactSync()
+	manager.StartInboxCollector()
+	manager.StartAutoReplier()
+	manager.StartReceiptPoller()
+	manager.StartDeadLetterRetrier()
+	manager.StartSupervisor()
+	manager.StartWorkerCallRecorder()
+	manager.StartAlerter()
+	manager.StartAlertMonitor()
+	manager.StartStatsRecorder()
+	manager.StartWebhookDispatcher()
+	manager.StartJanitor()
+	manager.StartBackupScheduler()
+	manager.StartSessionCheckpointer()
+	manager.StartHostMonitor()
+	manager.StartConfigReloader()
 
 	// 创建HTTP处理器
 	h := handler.NewHandler(manager)
@@ -48,14 +100,21 @@ func main() {
 
 	// 启动服务器
 	serverAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
-	log.Printf("🚀 WhatsApp Aggregator Service starting on %s", serverAddr)
-	log.Printf("🛠️  Worker Mode: %s", cfg.Worker.Mode)
-	log.Printf("🌐 Dashboard: http://%s/dashboard", serverAddr)
+	slog.Info("WhatsApp Aggregator Service starting", "addr", serverAddr, "worker_mode", cfg.Worker.Mode, "dashboard", fmt.Sprintf("http://%s/dashboard", serverAddr))
+
+	srv := &http.Server{
+		Addr:    serverAddr,
+		Handler: router,
+	}
+	// 关闭事件流等长连接，否则Shutdown会一直等到超时
+	srv.RegisterOnShutdown(func() {
+		manager.Events().Close()
+	})
 
-	// 优雅关闭
 	go func() {
-		if err := router.Run(serverAddr); err != nil {
-			log.Fatalf("Failed to start server: %v", err)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Error("Failed to start server", "error", err)
+			os.Exit(1)
 		}
 	}()
 
@@ -64,6 +123,21 @@ func main() {
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
-	log.Println("🛑 Shutting down server...")
-	log.Println("✅ Server shutdown complete")
+	slog.Info("Shutting down server")
+
+	// 优雅关闭：先停止接收新请求，再排空后台任务
+	ctx, cancel := context.WithTimeout(context.Background(), time.Duration(cfg.Server.ShutdownTimeout)*time.Second)
+	defer cancel()
+
+	if err := srv.Shutdown(ctx); err != nil {
+		slog.Error("HTTP server shutdown error", "error", err)
+	}
+	if err := manager.Shutdown(ctx); err != nil {
+		slog.Error("Manager shutdown error", "error", err)
+	}
+	if err := tracing.Shutdown(ctx); err != nil {
+		slog.Error("Tracing shutdown error", "error", err)
+	}
+
+	slog.Info("Server shutdown complete")
 }
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"whatsapp-aggregator/internal/config"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+const migrateUsage = `Usage: server migrate <command>
+
+Commands:
+  status            Show the schema version and every migration
+  up                Apply all pending migrations
+  down [--steps N]  Roll back the N most recent migrations (default 1)
+
+The database is taken from the same DB_* environment variables as the server.
+`
+
+// runMigrate 执行 migrate 子命令，返回进程退出码
+func runMigrate(cfg config.DBConfig, args []string) int {
+	if len(args) == 0 {
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	db, err := service.OpenDatabase(cfg)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error: failed to open database:", err)
+		return 1
+	}
+
+	switch args[0] {
+	case "status":
+	case "up":
+		applied, err := service.MigrateUp(db)
+		printMigrations("Applied", applied)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			return 1
+		}
+	case "down":
+		fs := flag.NewFlagSet("migrate down", flag.ContinueOnError)
+		steps := fs.Int("steps", 1, "number of migrations to roll back")
+		if err := fs.Parse(args[1:]); err != nil {
+			return 2
+		}
+		rolledBack, err := service.MigrateDown(db, *steps)
+		printMigrations("Rolled back", rolledBack)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "error:", err)
+			return 1
+		}
+	default:
+		fmt.Fprint(os.Stderr, migrateUsage)
+		return 2
+	}
+
+	status, err := service.SchemaStatusOf(db)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		return 1
+	}
+	printSchemaStatus(status)
+	return 0
+}
+
+// printMigrations 打印本次执行或回滚的迁移
+func printMigrations(action string, items []model.MigrationStatus) {
+	if len(items) == 0 {
+		fmt.Printf("%s: none\n", action)
+		return
+	}
+	for _, item := range items {
+		fmt.Printf("%s: %d %s\n", action, item.Version, item.Name)
+	}
+}
+
+// printSchemaStatus 以表格打印迁移状态
+func printSchemaStatus(status *model.SchemaStatus) {
+	fmt.Printf("Schema version %d, latest %d, %d pending\n\n", status.CurrentVersion, status.LatestVersion, status.Pending)
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT\tREVERSIBLE")
+	for _, item := range status.Migrations {
+		appliedAt := "pending"
+		if item.AppliedAt != nil {
+			appliedAt = item.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%t\n", item.Version, item.Name, appliedAt, item.Reversible)
+	}
+	w.Flush()
+	if status.CurrentVersion > status.LatestVersion {
+		fmt.Printf("\nThe database was migrated by a newer build (version %d); this build will not start against it.\n", status.CurrentVersion)
+	}
+}
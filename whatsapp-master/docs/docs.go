@@ -26,7 +26,7 @@ const docTemplate = `{
     "paths": {
         "/accounts": {
             "get": {
-                "description": "Get all registered accounts",
+                "description": "List accounts, filtered, sorted and paged in the database. Without sort the accounts are ordered by created_at. filter[tags] matches accounts with any of the tags.",
                 "produces": [
                     "application/json"
                 ],
@@ -34,9 +34,104 @@ const docTemplate = `{
                     "Account"
                 ],
                 "summary": "List Accounts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of accounts to skip, instead of cursor",
+                        "name": "offset",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields (created_at, last_activity, status, name, phone, messages_sent, ...), prefix with - for descending (e.g. -last_activity)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by field, comma separated values",
+                        "name": "filter[status]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by pool",
+                        "name": "filter[pool]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Accounts with any of these tags",
+                        "name": "filter[tags]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by host",
+                        "name": "filter[host_id]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only accounts assigned to this operator or team",
+                        "name": "owner",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by owner operator",
+                        "name": "filter[owner_operator]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by owner team",
+                        "name": "filter[owner_team]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by owner email",
+                        "name": "filter[owner_email]",
+                        "in": "query"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Account"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
@@ -44,7 +139,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "Create a new WhatsApp account worker",
+                "description": "Create a new WhatsApp account worker. With async=true the account is registered with status creating and the request returns a create_account job immediately; poll GET /jobs/{id} for the spawn stage (pulling_image, restoring_backup, starting, unsealing_session, waiting_ready), the created account or the error. With restore_backup=true the session directory is replaced by the account's latest successful backup before the worker starts, so a logged-in session can be brought back on a new host; returns 404 when the account has no backup. Returns 503 when the port pool has no free port; extend worker.portRanges through PUT /config. When the worker does not become ready within WORKER_READY_TIMEOUT_SECONDS (or its container exits first), data carries the container state, exit code, recent logs and docker inspect output; the same diagnostics are kept in the account's status history.",
                 "consumes": [
                     "application/json"
                 ],
@@ -64,6 +159,12 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.LoginRequest"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Create the account in a background job",
+                        "name": "async",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -72,59 +173,164 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.WorkerStartupDiagnostics"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
                     }
                 }
             }
         },
-        "/accounts/{id}": {
-            "get": {
-                "description": "Get account details by ID",
+        "/accounts/assign": {
+            "post": {
+                "description": "Assign accounts to an operator and/or team. Only the provided fields change; an empty string unassigns. Fails without changes if any account does not exist.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Account"
                 ],
-                "summary": "Get Account",
+                "summary": "Assign Accounts",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Account ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Assign Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AssignAccountsRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Account"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "Delete an account by ID",
+            }
+        },
+        "/accounts/bulk": {
+            "post": {
+                "description": "Create one account per phone number (the account ID is the normalized number) with shared settings. proxies is a pool assigned round-robin in phone order. Numbers that already have an account or appear twice are skipped. Workers are started in a create_accounts job with at most WORKER_PROVISION_CONCURRENCY (or the lower concurrency) at a time; the request is rejected with 503 when the port pool has fewer free ports than accounts to create. The response lists every phone as pending or skipped; poll GET /jobs/{id} for the per-account results.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Account"
                 ],
-                "summary": "Delete Account",
+                "summary": "Bulk Create Accounts",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Account ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
+                        "description": "Bulk Create Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkCreateAccountsRequest"
+                        }
                     }
                 ],
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.BulkCreateAccountsResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
@@ -132,16 +338,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/accounts/{id}/close": {
-            "post": {
-                "description": "Close the account session",
+        "/accounts/{id}": {
+            "get": {
+                "description": "Get account details by ID",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
                     "Account"
                 ],
-                "summary": "Close Account",
+                "summary": "Get Account",
                 "parameters": [
                     {
                         "type": "string",
@@ -159,18 +365,16 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/accounts/{id}/contacts": {
-            "get": {
-                "description": "Get contacts for a specific account",
+            },
+            "delete": {
+                "description": "Delete an account by ID. With purge_session=true the account's session data is overwritten and removed right away; otherwise it is purged by the janitor SESSION_RETENTION_DAYS after deletion.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Contact"
+                    "Account"
                 ],
-                "summary": "Get Contacts",
+                "summary": "Delete Account",
                 "parameters": [
                     {
                         "type": "string",
@@ -178,19 +382,43 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Securely remove the session data now",
+                        "name": "purge_session",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.DeleteAccountResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
                     }
                 }
             },
-            "post": {
-                "description": "Add a new contact to the account",
+            "patch": {
+                "description": "Change the account's name, notes, tags, owner, status poll interval or hardware profile. Fields that are not sent stay unchanged; tags replace the whole list and owner replaces all owner fields. status_poll_seconds sets how often the worker status is polled while the account is stable (0 or at least 5; 0 uses statusPoll.intervalSeconds). hardware_profile_id binds a profile from /hardware-profiles (\"\" unbinds it); it takes effect the next time the worker is started or logs in.",
                 "consumes": [
                     "application/json"
                 ],
@@ -198,9 +426,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Contact"
+                    "Account"
                 ],
-                "summary": "Add Contact",
+                "summary": "Update Account",
                 "parameters": [
                     {
                         "type": "string",
@@ -210,18 +438,42 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Contact Info",
+                        "description": "Account fields",
                         "name": "request",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.AddContactRequest"
+                            "$ref": "#/definitions/model.UpdateAccountRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Account"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
@@ -229,16 +481,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/accounts/{id}/debug": {
+        "/accounts/{id}/auto-replies": {
             "get": {
-                "description": "Get debug info for a specific account",
+                "description": "List the account's auto-reply rules in matching order",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Debug"
+                    "AutoReply"
                 ],
-                "summary": "Get Debug Info",
+                "summary": "List Auto-Reply Rules",
                 "parameters": [
                     {
                         "type": "string",
@@ -246,28 +498,62 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.AutoReplyRule"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
-            }
-        },
-        "/accounts/{id}/debug/check-messages": {
+            },
             "post": {
-                "description": "Manually trigger message check",
+                "description": "Add an auto-reply rule to an account. Inbound messages collected by the inbox collector are matched against the account's enabled rules by priority (lowest first). keyword rules match when the message contains any keyword, ignoring case; regex rules match the pattern, and its named groups can be used in the reply next to {{contact}} and {{message}}. A rule is skipped during its quiet hours (local time, start equal to end means none) and once it has replied max_replies_per_contact times to the contact within AUTO_REPLY_WINDOW_HOURS. The first rule left sends its reply through the send queue.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Debug"
+                    "AutoReply"
                 ],
-                "summary": "Check Messages",
+                "summary": "Create Auto-Reply Rule",
                 "parameters": [
                     {
                         "type": "string",
@@ -275,11 +561,44 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Auto-Reply Rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AutoReplyRuleRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.AutoReplyRule"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
@@ -287,16 +606,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/accounts/{id}/debug/elements": {
+        "/accounts/{id}/auto-replies/log": {
             "get": {
-                "description": "Get debug elements of the page",
+                "description": "List the replies sent by the account's auto-reply rules, newest first, with their status (queued, sent, failed or dropped when the send queue was full)",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Debug"
+                    "AutoReply"
                 ],
-                "summary": "Get Debug Elements",
+                "summary": "List Auto-Replies",
                 "parameters": [
                     {
                         "type": "string",
@@ -304,28 +623,82 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields (created_at), prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only replies of this rule",
+                        "name": "filter[rule_id]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only replies to this contact",
+                        "name": "filter[contact]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "queued, sent, failed or dropped",
+                        "name": "filter[status]",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.AutoReplyLog"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/accounts/{id}/debug/html": {
-            "get": {
-                "description": "Get debug HTML of the page",
+        "/accounts/{id}/auto-replies/{rule_id}": {
+            "put": {
+                "description": "Replace an auto-reply rule of the account",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
-                    "text/html"
+                    "application/json"
                 ],
                 "tags": [
-                    "Debug"
+                    "AutoReply"
                 ],
-                "summary": "Get Debug HTML",
+                "summary": "Update Auto-Reply Rule",
                 "parameters": [
                     {
                         "type": "string",
@@ -333,31 +706,66 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "rule_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Auto-Reply Rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AutoReplyRuleRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "string"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.AutoReplyRule"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
                         }
                     }
                 }
-            }
-        },
-        "/accounts/{id}/groups": {
-            "post": {
-                "description": "Create a new group",
-                "consumes": [
-                    "application/json"
-                ],
+            },
+            "delete": {
+                "description": "Delete an auto-reply rule of the account; its reply log is kept",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Group"
+                    "AutoReply"
                 ],
-                "summary": "Create Group",
+                "summary": "Delete Auto-Reply Rule",
                 "parameters": [
                     {
                         "type": "string",
@@ -367,14 +775,11 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Group Info",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": true
-                        }
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "rule_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -383,13 +788,19 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
-                    }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
                 }
             }
         },
-        "/accounts/{id}/groups/participants": {
+        "/accounts/{id}/ban/release": {
             "post": {
-                "description": "Add participants to a group",
+                "description": "Take a quarantined account out of the banned status, for example after a successful appeal. The ban record is kept as released with the note. The account is enabled again unless it was disabled before the ban, and its status is refreshed from the worker.",
                 "consumes": [
                     "application/json"
                 ],
@@ -397,9 +808,9 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Group"
+                    "Account"
                 ],
-                "summary": "Add Group Participants",
+                "summary": "Release Ban",
                 "parameters": [
                     {
                         "type": "string",
@@ -409,19 +820,41 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Participants Info",
+                        "description": "Release Request",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.ReleaseBanRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.BanRecord"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
@@ -429,16 +862,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/accounts/{id}/login/refresh": {
-            "post": {
-                "description": "Refresh login session",
+        "/accounts/{id}/chats/{contact}/export": {
+            "get": {
+                "description": "Download the whole conversation with a contact cached in the master database as a JSON or CSV attachment, oldest message first, for archival or CRM import. With store=true the file is written to the object storage (STORAGE_DESTINATION) under exports/ and a signed download URL is returned instead. Only cached messages are exported; page through GET /accounts/{id}/chats/{contact}/messages first to backfill older history from the worker.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
-                    "Auth"
+                    "Message"
                 ],
-                "summary": "Refresh Login",
+                "summary": "Export Chat History",
                 "parameters": [
                     {
                         "type": "string",
@@ -446,11 +880,75 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contact phone number or chat ID",
+                        "name": "contact",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only messages at or after this time (RFC3339)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only messages at or before this time (RFC3339)",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Write the export to the object storage and return its key and a signed download URL instead of the attachment",
+                        "name": "store",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/model.Message"
+                            }
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.StoredObject"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
@@ -458,16 +956,17 @@ const docTemplate = `{
                 }
             }
         },
-        "/accounts/{id}/login/status": {
+        "/accounts/{id}/chats/{contact}/messages": {
             "get": {
-                "description": "Check login status for a specific account",
+                "description": "Get one page of the conversation with a contact, oldest message first. With refresh (default true) the worker scrapes the page from WhatsApp Web first and messages missing from the master database are cached there; if the worker is unavailable the cached history is returned with a warning. Pass next_before from the response as before to page back to older messages. format=csv returns the page as a CSV attachment.",
                 "produces": [
-                    "application/json"
+                    "application/json",
+                    "text/csv"
                 ],
                 "tags": [
-                    "Auth"
+                    "Message"
                 ],
-                "summary": "Check Login Status",
+                "summary": "Get Chat History",
                 "parameters": [
                     {
                         "type": "string",
@@ -475,11 +974,66 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contact phone number or chat ID",
+                        "name": "contact",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_before from the previous page",
+                        "name": "before",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Scrape the page from the worker first (default true)",
+                        "name": "refresh",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ChatHistory"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
@@ -487,28 +1041,112 @@ const docTemplate = `{
                 }
             }
         },
-        "/accounts/{id}/logout": {
+        "/accounts/{id}/clone": {
             "post": {
-                "description": "Logout from WhatsApp",
+                "description": "Create a new account with the source account's proxy binding, tags, pool, owner, worker resources and runtime, send limit, warmup profile, typing simulation, keepalive and status poll settings, and start its worker. Notes, phone, statistics and session history are not copied. tags replace the copied tags. With copy_session=true the new session directory is initialised from the source's latest successful backup before the worker starts (404 when the source has no backup); run POST /system/backups/run with the source account first for a fresh copy. With async=true the request returns a create_account job; poll GET /jobs/{id} for the result.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Account"
                 ],
-                "summary": "Logout",
+                "summary": "Clone Account",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Account ID",
+                        "description": "Source account ID",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "New account",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CloneAccountRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Clone the account in a background job",
+                        "name": "async",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Account"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.WorkerStartupDiagnostics"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
@@ -516,16 +1154,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/accounts/{id}/logs": {
-            "get": {
-                "description": "Get logs for a specific account",
+        "/accounts/{id}/close": {
+            "post": {
+                "description": "Close the account session",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Account"
                 ],
-                "summary": "Get Logs",
+                "summary": "Close Account",
                 "parameters": [
                     {
                         "type": "string",
@@ -545,16 +1183,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/accounts/{id}/messages": {
+        "/accounts/{id}/contacts": {
             "get": {
-                "description": "Get recent messages for a specific account",
+                "description": "Get contacts for a specific account",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Message"
+                    "Contact"
                 ],
-                "summary": "Get Messages",
+                "summary": "Get Contacts",
                 "parameters": [
                     {
                         "type": "string",
@@ -562,28 +1200,62 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.WorkerContact"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
-            }
-        },
-        "/accounts/{id}/proxy/detect": {
-            "get": {
-                "description": "Detect if proxy is working",
+            },
+            "post": {
+                "description": "Add a new contact to the account",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Proxy"
+                    "Contact"
                 ],
-                "summary": "Detect Proxy",
+                "summary": "Add Contact",
                 "parameters": [
                     {
                         "type": "string",
@@ -591,28 +1263,52 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "description": "Contact Info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AddContactRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.WorkerContact"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/accounts/{id}/proxy/external-ip": {
-            "get": {
-                "description": "Get external IP via proxy",
+        "/accounts/{id}/contacts/import": {
+            "post": {
+                "description": "Upload a CSV or XLSX file (or reference one stored with POST /storage/objects by storage_key) and add its contacts to the account through the worker. The first row is a header when it has a phone column (phone, number, mobile...); otherwise the columns are phone, first name, last name. Numbers are normalized to E.164 and invalid or duplicate rows are not sent. The worker is called in batches of CONTACT_IMPORT_BATCH_SIZE with CONTACT_IMPORT_INTERVAL_MS between batches. Returns the result of every row.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Proxy"
+                    "Contact"
                 ],
-                "summary": "Get External IP",
+                "summary": "Import Contacts",
                 "parameters": [
                     {
                         "type": "string",
@@ -620,11 +1316,53 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "CSV or XLSX file, required without storage_key",
+                        "name": "file",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Key of a CSV or XLSX file in the object storage",
+                        "name": "storage_key",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Country calling code for numbers without + or 00, e.g. 86",
+                        "name": "country_code",
+                        "in": "formData"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ContactImportResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/model.APIResponse"
                         }
@@ -632,16 +1370,16 @@ const docTemplate = `{
                 }
             }
         },
-        "/accounts/{id}/proxy/status": {
-            "get": {
-                "description": "Get proxy status for an account",
+        "/accounts/{id}/contacts/sync": {
+            "post": {
+                "description": "Fetch the account's contacts from the worker now and store them in the master database",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Proxy"
+                    "Contact"
                 ],
-                "summary": "Get Proxy Status",
+                "summary": "Sync Contacts",
                 "parameters": [
                     {
                         "type": "string",
@@ -655,22 +1393,34 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ContactSyncResult"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/accounts/{id}/proxy/switch": {
-            "post": {
-                "description": "Switch proxy for an account",
+        "/accounts/{id}/conversations": {
+            "get": {
+                "description": "List conversation ownership (bot or agent) for an account",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Proxy"
+                    "Conversation"
                 ],
-                "summary": "Switch Proxy",
+                "summary": "List Conversations",
                 "parameters": [
                     {
                         "type": "string",
@@ -680,35 +1430,71 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "Proxy Config",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.ProxyConfig"
-                        }
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "bot or agent",
+                        "name": "filter[handled_by]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Agent ID",
+                        "name": "filter[agent_id]",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Conversation"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/accounts/{id}/qr-code": {
+        "/accounts/{id}/conversations/{contact}": {
             "get": {
-                "description": "Get QR code for a specific account",
+                "description": "Get who is handling the conversation with a contact",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Conversation"
                 ],
-                "summary": "Get QR Code",
+                "summary": "Get Conversation",
                 "parameters": [
                     {
                         "type": "string",
@@ -716,28 +1502,50 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contact",
+                        "name": "contact",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Conversation"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/accounts/{id}/restart": {
+        "/accounts/{id}/conversations/{contact}/claim": {
             "post": {
-                "description": "Restart the worker container/process for an account (e.g., after image update)",
+                "description": "Assign a conversation to a human agent",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Account"
+                    "Conversation"
                 ],
-                "summary": "Restart Account Worker",
+                "summary": "Claim Conversation",
                 "parameters": [
                     {
                         "type": "string",
@@ -745,28 +1553,59 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contact",
+                        "name": "contact",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Claim Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ClaimConversationRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Conversation"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/accounts/{id}/status": {
-            "get": {
-                "description": "Get status for a specific account",
+        "/accounts/{id}/conversations/{contact}/release": {
+            "post": {
+                "description": "Hand a conversation back to the bot",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Account"
+                    "Conversation"
                 ],
-                "summary": "Get Account Status",
+                "summary": "Release Conversation",
                 "parameters": [
                     {
                         "type": "string",
@@ -774,28 +1613,55 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contact",
+                        "name": "contact",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Release Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.ReleaseConversationRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Conversation"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/accounts/{id}/stop": {
-            "post": {
-                "description": "Stop the worker process for an account",
+        "/accounts/{id}/debug": {
+            "get": {
+                "description": "Get debug info for a specific account",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Account"
+                    "Debug"
                 ],
-                "summary": "Stop Account Service",
+                "summary": "Get Debug Info",
                 "parameters": [
                     {
                         "type": "string",
@@ -815,16 +1681,25 @@ const docTemplate = `{
                 }
             }
         },
-        "/config": {
-            "get": {
-                "description": "Get current system configuration",
+        "/accounts/{id}/debug/check-messages": {
+            "post": {
+                "description": "Manually trigger message check",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Debug"
+                ],
+                "summary": "Check Messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
-                "summary": "Get Config",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -833,64 +1708,143 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "Update system configuration",
-                "consumes": [
-                    "application/json"
-                ],
+            }
+        },
+        "/accounts/{id}/debug/elements": {
+            "get": {
+                "description": "Get debug elements of the page",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Debug"
                 ],
-                "summary": "Update Config",
+                "summary": "Get Debug Elements",
                 "parameters": [
                     {
-                        "description": "Configuration",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/model.APIResponse"
                         }
                     }
+                }
+            }
+        },
+        "/accounts/{id}/debug/html": {
+            "get": {
+                "description": "Get debug HTML of the page",
+                "produces": [
+                    "text/html"
+                ],
+                "tags": [
+                    "Debug"
+                ],
+                "summary": "Get Debug HTML",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
+                            "type": "string"
                         }
                     }
                 }
             }
         },
-        "/health": {
+        "/accounts/{id}/diagnostics": {
             "get": {
-                "description": "Check system health status",
+                "description": "List diagnostic bundles uploaded by the account's worker",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Diagnostics"
+                ],
+                "summary": "List Diagnostic Bundles",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related incident ID",
+                        "name": "filter[incident_id]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "screenshot, crash_dump, html or other",
+                        "name": "filter[kind]",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get Health Status",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.DiagnosticBundle"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/phone-login": {
-            "post": {
-                "description": "Login with phone number",
+        "/accounts/{id}/disable": {
+            "put": {
+                "description": "Put an account into maintenance mode without stopping its worker. Sends through the account are rejected with 409 and it is left out of bulk sends and campaigns until re-enabled.",
                 "consumes": [
                     "application/json"
                 ],
@@ -898,17 +1852,23 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "Auth"
+                    "Account"
                 ],
-                "summary": "Phone Login",
+                "summary": "Disable Account",
                 "parameters": [
                     {
-                        "description": "Phone Login Request",
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Disable Request",
                         "name": "request",
                         "in": "body",
-                        "required": true,
                         "schema": {
-                            "$ref": "#/definitions/model.PhoneLoginRequest"
+                            "$ref": "#/definitions/model.DisableAccountRequest"
                         }
                     }
                 ],
@@ -916,76 +1876,215 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Account"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/send-message": {
-            "post": {
-                "description": "Send a WhatsApp message",
-                "consumes": [
-                    "application/json"
-                ],
+        "/accounts/{id}/enable": {
+            "put": {
+                "description": "Take an account out of maintenance mode so it can send and join bulk sends and campaigns again.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "Message"
+                    "Account"
                 ],
-                "summary": "Send Message",
+                "summary": "Enable Account",
                 "parameters": [
                     {
-                        "description": "Message Request",
-                        "name": "request",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/model.MessageRequest"
-                        }
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Account"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/stats": {
+        "/accounts/{id}/groups": {
             "get": {
-                "description": "Get system statistics",
+                "description": "List the account's groups from the master cache. The cache is refreshed from the worker when it is empty, older than GROUP_CACHE_SECONDS or refresh=true; if the refresh fails the cached groups are returned with a warning.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Group"
+                ],
+                "summary": "List Groups",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Refresh from the worker first",
+                        "name": "refresh",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Get System Stats",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/model.APIResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Group"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a new group. The new group is added to the master's group cache.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Group"
+                ],
+                "summary": "Create Group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Group Info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.CreateGroupResult"
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     }
                 }
             }
         },
-        "/system/restart-workers": {
+        "/accounts/{id}/groups/participants": {
             "post": {
-                "description": "Restart all active workers (e.g. after image update)",
+                "description": "Add participants to a group. The group's cached participants are refreshed.",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "System"
+                    "Group"
+                ],
+                "summary": "Add Group Participants",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Participants Info",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AddGroupParticipantsRequest"
+                        }
+                    }
                 ],
-                "summary": "Restart All Workers",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -995,146 +2094,12714 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
+        },
+        "/accounts/{id}/groups/{gid}": {
+            "get": {
+                "description": "Get a group with its participants and admins, refreshed from the worker when the cache is stale or refresh=true",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Group"
+                ],
+                "summary": "Get Group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "gid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Refresh from the worker first",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Group"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Change the group subject. The account must be a group admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Group"
+                ],
+                "summary": "Rename Group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "gid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.RenameGroupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Group"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/groups/{gid}/admins/demote": {
+            "post": {
+                "description": "Remove admin rights from participants. The account must be a group admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Group"
+                ],
+                "summary": "Demote Group Admins",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "gid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Participants",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.GroupParticipantsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Group"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/groups/{gid}/admins/promote": {
+            "post": {
+                "description": "Make participants group admins. The account must be a group admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Group"
+                ],
+                "summary": "Promote Group Admins",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "gid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Participants",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.GroupParticipantsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Group"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/groups/{gid}/invite-link": {
+            "get": {
+                "description": "Get the group's invite link from the worker. The account must be a group admin.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Group"
+                ],
+                "summary": "Get Group Invite Link",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "gid",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/groups/{gid}/participants/remove": {
+            "post": {
+                "description": "Remove participants from a group. The account must be a group admin.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Group"
+                ],
+                "summary": "Remove Group Participants",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Group ID",
+                        "name": "gid",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Participants",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.GroupParticipantsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Group"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/history": {
+            "get": {
+                "description": "List the account's status transitions (previous and new status, trigger source, reason and request ID), newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Get Account Status History",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending (default -created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "New status, comma separated",
+                        "name": "filter[status]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Trigger source: api, worker, supervisor, reconcile, shutdown, chaos",
+                        "name": "filter[source]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.StatusTransition"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/keepalive": {
+            "put": {
+                "description": "Opt an account in or out of the session keep-alive loop, which checks the worker's login status every SESSION_KEEPALIVE_INTERVAL_SECONDS and refreshes the session when it is disconnected or close to expiry. Opted-out accounts are also skipped by the quiet-hours refresher.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Set Account Keep-Alive",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Keep-alive setting",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SetKeepAliveRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Account"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/login/flow": {
+            "get": {
+                "description": "Get the state machine of the account's latest phone login: requesting_code, waiting_for_code, verifying, then logged_in, failed or cancelled. Each pairing code is valid for PHONE_LOGIN_CODE_TIMEOUT_SECONDS; an expired code or a worker that failed to initialize triggers a new attempt, up to PHONE_LOGIN_MAX_ATTEMPTS, and the whole flow fails after PHONE_LOGIN_TIMEOUT_SECONDS. Finished flows are kept until the next phone login of the account.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get Phone Login Flow",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.LoginFlow"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/login/refresh": {
+            "post": {
+                "description": "Refresh login session",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Refresh Login",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/login/status": {
+            "get": {
+                "description": "Check login status for a specific account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Check Login Status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/login/verify": {
+            "post": {
+                "description": "Report progress of a phone login. entered: the pairing code was typed on the phone; the flow moves to verifying and the code gets a fresh validity period so it is not replaced while WhatsApp confirms the link. resend: request a new pairing code now (counts as an attempt). cancel: stop tracking the login; the worker keeps running. 409 when the flow already finished.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Submit Login Verification",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Verification action",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.LoginVerifyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.LoginFlow"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/logout": {
+            "post": {
+                "description": "Logout from WhatsApp",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Logout",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/logs": {
+            "get": {
+                "description": "Get logs for a specific account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get Logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/logs/stream": {
+            "get": {
+                "description": "Stream the worker container's docker logs (stdout and stderr, with timestamps) as Server-Sent Events. Each line is a \"log\" event; an \"end\" event is sent when the logs end (the container exited, or follow=false), with the error if reading failed.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Stream Worker Logs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Number of recent lines to send first (default 100, max 10000)",
+                        "name": "tail",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Keep streaming new lines (default true)",
+                        "name": "follow",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only lines after this RFC3339 time or relative duration (e.g. 10m)",
+                        "name": "since",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "log lines",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/messages": {
+            "get": {
+                "description": "Get message history for a specific account from the master database (recent inbound messages are synced from the worker first)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Get Messages",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending (default -timestamp)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "inbound or outbound",
+                        "name": "filter[direction]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contact",
+                        "name": "filter[contact]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "sent, failed, received",
+                        "name": "filter[status]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Message"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/owner": {
+            "put": {
+                "description": "Attach owner metadata (operator, team, email, escalation webhook) to an account, replacing the previous owner. Accounts can be listed by owner with GET /accounts?owner=; incidents for the account are routed to the owner's channel, falling back to ALERT_WEBHOOK_URL.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Set Account Owner",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Account Owner",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AccountOwner"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Account"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/pairing-code": {
+            "get": {
+                "description": "Relay the pairing code of a phone login from the worker, to be entered on the phone under Linked devices \u003e Link with phone number. While a login flow is running the response includes when the code expires and which attempt it is; after it expires the master requests a new code automatically (see GET /accounts/{id}/login/flow). 404 when the worker has no code, e.g. before it is generated or after login.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get Pairing Code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.PairingCode"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/presence/{contact}": {
+            "get": {
+                "description": "Subscribe to a contact's presence through the worker and return whether they are online, their current chat state and when they were last seen. online is null and last_seen is empty when the contact hides them or WhatsApp has not reported them yet; ask again after a few seconds.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "Get Contact Presence",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contact phone number or chat ID",
+                        "name": "contact",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ContactPresence"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/proxy": {
+            "put": {
+                "description": "Store the proxy bound to an account. The binding is used for later logins and whenever the worker container is recreated (restart, recovery, rebalancing). An empty ip removes the binding. With apply=true the running worker switches to the proxy immediately, which restarts its WhatsApp client; if that fails the binding is still saved and a warning is returned.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Proxy"
+                ],
+                "summary": "Set Account Proxy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Proxy binding",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SetAccountProxyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Account"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/proxy/detect": {
+            "get": {
+                "description": "Detect if proxy is working",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Proxy"
+                ],
+                "summary": "Detect Proxy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProxyDetectResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/proxy/external-ip": {
+            "get": {
+                "description": "Get external IP via proxy",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Proxy"
+                ],
+                "summary": "Get External IP",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ExternalIPResult"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/proxy/status": {
+            "get": {
+                "description": "Get proxy status for an account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Proxy"
+                ],
+                "summary": "Get Proxy Status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.ProxyStatus"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/proxy/switch": {
+            "post": {
+                "description": "Switch proxy for an account. The worker restarts its browser through the new proxy, and the proxy is stored as the account's binding for later restarts.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Proxy"
+                ],
+                "summary": "Switch Proxy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Proxy Config",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SwitchProxyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/qr-code": {
+            "get": {
+                "description": "Get QR code for a specific account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get QR Code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/qr-code.png": {
+            "get": {
+                "description": "Fetch the current login QR code from the worker and render it as a PNG.",
+                "produces": [
+                    "image/png"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get QR Code Image",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Image size in pixels (128-1024, default 256)",
+                        "name": "size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/quota": {
+            "get": {
+                "description": "Get the per-minute rate limit and daily quota usage of an account. A zero limit means unlimited.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Get Send Quota",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SendQuota"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/restart": {
+            "post": {
+                "description": "Restart the worker container/process for an account (e.g., after image update). The restart runs as a background job; poll /jobs/{id} for the outcome.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Restart Account Worker",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/send-limit": {
+            "put": {
+                "description": "Override the token bucket limit for send requests of an account (requests per minute and burst). 0 falls back to SEND_LIMIT_ACCOUNT_PER_MINUTE / SEND_LIMIT_ACCOUNT_BURST. The setting is persisted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Set Account Send Limit",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Account Send Limit",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AccountSendLimit"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Account"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/session": {
+            "get": {
+                "description": "Get session age, drop history and whether a relogin is recommended before the session expires",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Get Session Health",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SessionHealth"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/sla": {
+            "get": {
+                "description": "Availability of the account over the last 24h, 7d and 30d derived from its status history, with its outages of the last 30 days (newest first). Time spent logged_in counts as uptime; creating, stopping and stopped are planned and not counted; every other status is downtime. Consecutive down statuses form one outage, which ends when the account is logged in again or stopped. availability is null when a window has no counted time.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Get Account SLA",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.AccountSLA"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/status": {
+            "get": {
+                "description": "Get status for a specific account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Get Account Status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/stop": {
+            "post": {
+                "description": "Stop the worker process for an account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Stop Account Service",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/typing": {
+            "post": {
+                "description": "Show \"typing…\" or \"recording audio…\" to a contact, or clear it with state=paused. With duration_ms the worker clears the state when it runs out; otherwise WhatsApp clears it when a message is sent or after about 25 seconds.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Send Typing State",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Typing Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.TypingRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ChatState"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/typing-simulation": {
+            "put": {
+                "description": "Turn human-like typing on or off for an account. When it is on, every text message from /send-message, bulk sends and campaigns first shows \"typing…\" to the contact and waits for a delay based on the message length (TYPING_CHARS_PER_SECOND, between TYPING_MIN_DELAY_MS and TYPING_MAX_DELAY_MS with TYPING_JITTER_PERCENT random variation). Retries and media messages are sent without the delay.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "Set Account Typing Simulation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Typing simulation setting",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SetTypingSimulationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Account"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/warmup": {
+            "get": {
+                "description": "Current warmup step of an account: its profile, age in days, today's allowance and usage, and when the next step starts. A daily_limit of 0 means unlimited.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Get Account Warmup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.WarmupStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Assign a warmup profile to an account (\"\" for WARMUP_DEFAULT_PROFILE, \"off\" to exempt it) and/or restart its warmup from today. The setting is persisted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Set Account Warmup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Warmup Settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.SetWarmupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.WarmupStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/accounts/{id}/worker-calls": {
+            "get": {
+                "description": "Every call made to the account's worker, newest first: API requests proxied to the worker (source proxy) and calls the master makes itself, such as sends and logins (source master). Each entry has the method, worker path, worker status code (0 when the worker could not be reached), latency, request body size and the SHA-256 of its first 64 KiB; bodies are not stored. Use it to reconstruct what an account did before a ban. Kept for AUDIT_WORKER_CALL_RETENTION_DAYS and at most AUDIT_WORKER_CALL_MAX_PER_ACCOUNT entries per account.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "List Worker Calls",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only calls at or after this time (RFC3339)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only calls before this time (RFC3339)",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending (default -created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "proxy or master",
+                        "name": "filter[source]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "HTTP method",
+                        "name": "filter[method]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Worker path, e.g. /api/send-message",
+                        "name": "filter[path]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Worker status code",
+                        "name": "filter[status]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Request ID",
+                        "name": "filter[request_id]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.WorkerCall"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/alerts/channels": {
+            "get": {
+                "description": "List notification channels for fleet alerts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alert"
+                ],
+                "summary": "List Alert Channels",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.AlertChannel"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add a notification channel for fleet alerts: slack (Incoming Webhook url), telegram (bot_token, chat_id), email (SMTP smtp_host, smtp_port, from, to) or webhook (url receiving the incident JSON). Credentials are write-only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alert"
+                ],
+                "summary": "Create Alert Channel",
+                "parameters": [
+                    {
+                        "description": "Alert Channel",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AlertChannelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.AlertChannel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/alerts/channels/{id}": {
+            "put": {
+                "description": "Replace an alert channel's settings. Omitted bot_token or smtp_password keep the stored credential.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alert"
+                ],
+                "summary": "Update Alert Channel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Alert Channel",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AlertChannelRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.AlertChannel"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an alert channel and detach it from routing rules",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alert"
+                ],
+                "summary": "Delete Alert Channel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/alerts/channels/{id}/test": {
+            "post": {
+                "description": "Send a test notification through the channel and report whether it was delivered",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alert"
+                ],
+                "summary": "Test Alert Channel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Channel ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.AlertTestResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/alerts/rules": {
+            "get": {
+                "description": "List alert routing rules",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alert"
+                ],
+                "summary": "List Alert Rules",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.AlertRule"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Route events to alert channels. A rule without events matches the ALERT_EVENTS types; account_ids, pool and owner_team narrow it to matching accounts. Rules apply in addition to the account owner channel and ALERT_WEBHOOK_URL.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alert"
+                ],
+                "summary": "Create Alert Rule",
+                "parameters": [
+                    {
+                        "description": "Alert Rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AlertRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.AlertRule"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/alerts/rules/{id}": {
+            "put": {
+                "description": "Replace an alert routing rule",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alert"
+                ],
+                "summary": "Update Alert Rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Alert Rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AlertRuleRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.AlertRule"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an alert routing rule",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Alert"
+                ],
+                "summary": "Delete Alert Rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/approvals": {
+            "get": {
+                "description": "Campaigns and bulk sends (including broadcasts) with more recipients than APPROVAL_THRESHOLD, newest first. Each entry carries the message template and a sample of rendered messages spread across the recipient list.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Approval"
+                ],
+                "summary": "List Approvals",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending (default -created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, approved, rejected or failed",
+                        "name": "filter[status]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "campaign or bulk",
+                        "name": "filter[kind]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campaign ID",
+                        "name": "filter[campaign_id]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Approval"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/approvals/{id}": {
+            "get": {
+                "description": "Get an approval with its sample messages and review result",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Approval"
+                ],
+                "summary": "Get Approval",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Approval ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Approval"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/approvals/{id}/approve": {
+            "post": {
+                "description": "Approve a pending campaign or bulk send and dispatch it: the campaign starts, a bulk send creates its batch (batch_id) from the original request. If dispatching fails the approval becomes failed; a campaign returns to draft and can be started again without a new approval.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Approval"
+                ],
+                "summary": "Approve Send",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Approval ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review note",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.ReviewApprovalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Approval"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/approvals/{id}/reject": {
+            "post": {
+                "description": "Reject a pending campaign or bulk send. Nothing is sent; a campaign returns to draft with the note in last_error.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Approval"
+                ],
+                "summary": "Reject Send",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Approval ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review note",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.ReviewApprovalRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Approval"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/audit": {
+            "get": {
+                "description": "List recorded POST/PUT/PATCH/DELETE calls with the caller, endpoint, redacted request summary and result, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "List Audit Log",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only entries at or after this time (RFC3339)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only entries before this time (RFC3339)",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending (default -created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "admin, api_key or anonymous",
+                        "name": "filter[actor]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant API key ID",
+                        "name": "filter[api_key_id]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "filter[tenant_id]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "HTTP method",
+                        "name": "filter[method]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Route template, e.g. /api/v1/accounts/:id/stop",
+                        "name": "filter[route]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "filter[account_id]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "HTTP status code",
+                        "name": "filter[status]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only successful or only failed calls",
+                        "name": "filter[success]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.AuditEntry"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/backups": {
+            "get": {
+                "description": "Session directory backups, newest first, including failed attempts. Each successful backup is a tar.gz of the account's session directory stored under key in destination. Create an account with restore_backup=true to start its worker from the latest successful backup.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "List Session Backups",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending (default -created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account IDs (comma separated)",
+                        "name": "filter[account_id]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "succeeded or failed",
+                        "name": "filter[status]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "scheduled or manual",
+                        "name": "filter[trigger]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.SessionBackup"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/bans": {
+            "get": {
+                "description": "Accounts quarantined because a ban signal was detected, newest first. A ban signal is a worker error or logout reason matching BAN_ERROR_PATTERNS, or BAN_LOGOUT_LOOP_COUNT session drops within BAN_LOGOUT_LOOP_WINDOW_MINUTES. evidence holds the failing worker call, the logout times, the recent status history and the worker status at detection, for the appeal.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "List Ban Records",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending (default -created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account IDs (comma separated)",
+                        "name": "filter[account_id]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "active or released",
+                        "name": "filter[status]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "worker_error, logout or logout_loop",
+                        "name": "filter[source]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.BanRecord"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/broadcast": {
+            "post": {
+                "description": "Send a message to a contact list using all logged-in accounts, or the ones matching senders (account_ids, pool, tags), as senders. Contacts are deduplicated (ignoring +, spaces, dashes and @c.us) and assigned round-robin. Returns a consolidated report; poll GET /broadcast/{id} for progress. Above APPROVAL_THRESHOLD recipients it returns 202 with a pending approval instead.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Broadcast Message",
+                "parameters": [
+                    {
+                        "description": "Broadcast Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BroadcastRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.BroadcastReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Approval"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/broadcast/{id}": {
+            "get": {
+                "description": "Progress of a broadcast with per-sender counts and failures grouped by error. Per-recipient results are available from GET /send-bulk/{id}. Like bulk batches, broadcasts are kept in memory only and are removed BULK_BATCH_RETENTION_HOURS after they finish.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Get Broadcast Report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Broadcast (bulk batch) ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.BroadcastReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/campaigns": {
+            "get": {
+                "description": "List campaigns with their progress",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Campaign"
+                ],
+                "summary": "List Campaigns",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "draft, pending_approval, running, paused or completed",
+                        "name": "filter[status]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Campaign"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a draft campaign: a named set of accounts, a target contact list and a message template. Use /campaigns/{id}/start to begin sending.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Campaign"
+                ],
+                "summary": "Create Campaign",
+                "parameters": [
+                    {
+                        "description": "Create Campaign Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateCampaignRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Campaign"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/campaigns/{id}": {
+            "get": {
+                "description": "Get a campaign and its per-status recipient counts",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Campaign"
+                ],
+                "summary": "Get Campaign",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Campaign ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Campaign"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/campaigns/{id}/pause": {
+            "post": {
+                "description": "Pause a running campaign. Messages already being sent are completed; the rest stay pending until the campaign is started again.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Campaign"
+                ],
+                "summary": "Pause Campaign",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Campaign ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Campaign"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/campaigns/{id}/recipients": {
+            "get": {
+                "description": "List recipients of a campaign with their send results",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Campaign"
+                ],
+                "summary": "List Campaign Recipients",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Campaign ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, sending, sent, failed or skipped",
+                        "name": "filter[status]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sending account",
+                        "name": "filter[account_id]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.CampaignRecipient"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/campaigns/{id}/start": {
+            "post": {
+                "description": "Start a draft campaign or resume a paused one. Pending recipients are distributed across the campaign's logged-in accounts; opted-out contacts are skipped. A draft with more recipients than APPROVAL_THRESHOLD becomes pending_approval and the response is 202 with the approval; it starts once approved.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Campaign"
+                ],
+                "summary": "Start Campaign",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Campaign ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Campaign"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Approval"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/chaos/accounts/{id}/delay": {
+            "post": {
+                "description": "Delay (and optionally fail) all master-to-worker calls of an account for a period of time",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chaos"
+                ],
+                "summary": "Inject Worker Delay",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fault",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ChaosDelayRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ChaosFault"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove an injected delay/failure from an account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chaos"
+                ],
+                "summary": "Clear Worker Fault",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/chaos/accounts/{id}/kill": {
+            "post": {
+                "description": "Kill the worker container of an account without updating its status, to exercise failure detection",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chaos"
+                ],
+                "summary": "Kill Worker",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/chaos/accounts/{id}/status": {
+            "post": {
+                "description": "Force an account into a status (e.g. disconnected) to test alerting and failover. The status poller may restore the real status.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chaos"
+                ],
+                "summary": "Force Account Status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ChaosStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Account"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/chaos/faults": {
+            "get": {
+                "description": "List active worker fault injections (requires CHAOS_ENABLED and X-Admin-Token)",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Chaos"
+                ],
+                "summary": "List Chaos Faults",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin token",
+                        "name": "X-Admin-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "filter[account_id]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.ChaosFault"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/config": {
+            "get": {
+                "description": "Get current system configuration",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get Config",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update and persist configuration, e.g. {\"rateLimit\":{\"globalPerMinute\":600},\"log\":{\"level\":\"debug\"}}. Hot-reloadable keys (worker.image, worker.portRanges, rateLimit.*, quota.*, retry.*, bulk.*, proxy.*, supervisor.*, statusPoll.*, typing.*, log.level) take effect immediately; server.* and the other worker.* keys are saved and applied on the next restart. Saved values override environment variables at startup.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Update Config",
+                "parameters": [
+                    {
+                        "description": "Configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ConfigUpdateResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/config/overrides": {
+            "get": {
+                "description": "List configuration values saved through PUT /config. They override environment variables at startup.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "List Config Overrides",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending, e.g. -updated_at",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "true or false",
+                        "name": "filter[hot_reload]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.ConfigOverride"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/config/overrides/{key}": {
+            "delete": {
+                "description": "Remove a saved configuration value and fall back to the environment variable. Hot-reloadable keys revert immediately; the others on the next restart.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Delete Config Override",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Config key, e.g. rateLimit.globalPerMinute",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ConfigUpdateResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/contacts": {
+            "get": {
+                "description": "Search contacts synced from the workers into the master database. q matches name, push name, number or WhatsApp ID.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Contact"
+                ],
+                "summary": "Search Contacts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Search text",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending (default name)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "filter[account_id]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Only groups or only individual contacts",
+                        "name": "filter[is_group]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Contact"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/content-policies": {
+            "get": {
+                "description": "List outbound content policies in creation order",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Content Policy"
+                ],
+                "summary": "List Content Policies",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.ContentPolicy"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add an outbound content policy checked before every text message and media caption is sent: keyword (blocked keywords, case-insensitive), url_allowlist (links must belong to domains or their subdomains), max_links (at most max_links links) or pii (built-in email, credit_card, phone, iban or custom regular expressions in patterns). Action reject refuses the send with 422, review holds the message in the moderation queue and returns 202.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Content Policy"
+                ],
+                "summary": "Create Content Policy",
+                "parameters": [
+                    {
+                        "description": "Content Policy",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ContentPolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ContentPolicy"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/content-policies/{id}": {
+            "put": {
+                "description": "Replace a content policy. Messages already in the moderation queue are not re-evaluated.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Content Policy"
+                ],
+                "summary": "Update Content Policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Policy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Content Policy",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ContentPolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ContentPolicy"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a content policy. Held messages stay in the moderation queue.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Content Policy"
+                ],
+                "summary": "Delete Content Policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Policy ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/dead-letters": {
+            "get": {
+                "description": "Messages whose send failed because the worker was unreachable or the proxy failed, newest first. Retried entries keep their history until the janitor removes them DEAD_LETTER_RETENTION_DAYS after delivery.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "List Dead Letters",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending (default -created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account IDs (comma separated)",
+                        "name": "filter[account_id]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, retrying, exhausted or resolved",
+                        "name": "filter[status]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campaign",
+                        "name": "filter[campaign]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.DeadLetter"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/dead-letters/{id}/retry": {
+            "post": {
+                "description": "Resend a dead-lettered message now and return the updated entry. A failed attempt is recorded in retries and error and the entry stays in the queue.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Retry Dead Letter",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Dead letter ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.DeadLetter"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/diagnostics/{id}": {
+            "get": {
+                "description": "Get a diagnostic bundle and its file list",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Diagnostics"
+                ],
+                "summary": "Get Diagnostic Bundle",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bundle ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.DiagnosticBundle"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a diagnostic bundle and its files before the retention period ends",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Diagnostics"
+                ],
+                "summary": "Delete Diagnostic Bundle",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bundle ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/diagnostics/{id}/files/{name}": {
+            "get": {
+                "description": "Download a single file of a diagnostic bundle",
+                "produces": [
+                    "application/octet-stream"
+                ],
+                "tags": [
+                    "Diagnostics"
+                ],
+                "summary": "Download Diagnostic File",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bundle ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "File name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    }
+                }
+            }
+        },
+        "/events": {
+            "get": {
+                "description": "Stream account status, login, QR code, message and conversation events in real time (Server-Sent Events)",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Stream Events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only events for these accounts (comma separated)",
+                        "name": "account_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only these event types (comma separated, e.g. account.status_changed,message.received)",
+                        "name": "types",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.Event"
+                        }
+                    }
+                }
+            }
+        },
+        "/events/ws": {
+            "get": {
+                "description": "Same events and filters as /events over a WebSocket, one JSON model.Event per text message. A {\"type\":\"ping\"} message is sent every 30 seconds. Browsers cannot set headers on WebSocket requests, so with MULTI_TENANT_ENABLED the credential can be passed as the api_key or admin_token query parameter. Cross-origin connections are rejected.",
+                "tags": [
+                    "System"
+                ],
+                "summary": "Stream Events (WebSocket)",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only events for these accounts (comma separated)",
+                        "name": "account_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only these event types (comma separated)",
+                        "name": "types",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/model.Event"
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql": {
+            "get": {
+                "description": "Run a GraphQL query passed as query parameters (variables is a JSON object). With a WebSocket upgrade using the graphql-transport-ws subprotocol, the connection accepts queries and subscriptions such as subscription { events(types: [\"account.status_changed\"]) { type accountId data timestamp account { name status } } }. Browsers cannot set headers on WebSocket requests, so with MULTI_TENANT_ENABLED the credential can be passed as the api_key or admin_token query parameter. Cross-origin connections are rejected.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "GraphQL Query (GET) and Subscriptions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "GraphQL query",
+                        "name": "query",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Variables as a JSON object",
+                        "name": "variables",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Operation to run when the query contains several",
+                        "name": "operationName",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Response"
+                        }
+                    },
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Response"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Run a GraphQL query against accounts, messages, stats and events, e.g. {\"query\":\"{ accounts(status: [\\\"logged_in\\\"]) { total nodes { id name messages(limit: 5) { nodes { contact body } } } } }\"}. The response is the standard GraphQL {data, errors} object rather than the API envelope; it is 400 when the query cannot be parsed or validated, or exceeds the depth (10) or complexity (20000) limit. Each field costs 1 and a field with a limit argument multiplies the cost of its sub-fields by the limit. Tenant API keys only see their own accounts, messages and events, and cannot query stats. Subscriptions use the graphql-transport-ws WebSocket protocol on GET /graphql; mutations are not supported. The schema is at GET /graphql/schema.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "GraphQL Query",
+                "parameters": [
+                    {
+                        "description": "GraphQL Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.GraphQLRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Response"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/graphql.Response"
+                        }
+                    }
+                }
+            }
+        },
+        "/graphql/schema": {
+            "get": {
+                "description": "The GraphQL schema in SDL, for code generators and IDE plugins that do not use introspection",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get GraphQL Schema",
+                "responses": {
+                    "200": {
+                        "description": "SDL",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/hardware-profiles": {
+            "get": {
+                "description": "List hardware fingerprint profiles ordered by name, with the number of accounts bound to each",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "HardwareProfile"
+                ],
+                "summary": "List Hardware Profiles",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.HardwareProfile"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Save a named browser fingerprint (OS, browser, user agent, screen resolution, locale, timezone). Accounts bound to it via hardware_profile_id get the same fingerprint every time their worker is started or logs in, so WhatsApp sees a stable device across restarts. Locale is a language tag such as en-US; timezone is an IANA name such as Asia/Shanghai.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "HardwareProfile"
+                ],
+                "summary": "Create Hardware Profile",
+                "parameters": [
+                    {
+                        "description": "Hardware Profile",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.HardwareProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.HardwareProfile"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/hardware-profiles/{id}": {
+            "get": {
+                "description": "Get a hardware fingerprint profile and the number of accounts bound to it",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "HardwareProfile"
+                ],
+                "summary": "Get Hardware Profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.HardwareProfile"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Replace all fields of a hardware fingerprint profile. Bound accounts pick up the new fingerprint the next time their worker is started or logs in; running workers are not restarted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "HardwareProfile"
+                ],
+                "summary": "Update Hardware Profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Hardware Profile",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.HardwareProfileRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.HardwareProfile"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete a hardware fingerprint profile. Profiles still bound to accounts cannot be deleted (409); unbind them first with PATCH /accounts/{id} and hardware_profile_id \"\".",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "HardwareProfile"
+                ],
+                "summary": "Delete Hardware Profile",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Profile ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check database, Docker daemon, session disk space, port pool utilization and goroutine count. The overall status is the worst check result; unhealthy returns 503.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get Health Status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.HealthStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.HealthStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/hosts": {
+            "get": {
+                "description": "List the master host and registered remote hosts with their status, resources and the number of workers placed on them. Requires the admin token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Host"
+                ],
+                "summary": "List Hosts",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending, e.g. -workers",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Host status",
+                        "name": "filter[status]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Host region",
+                        "name": "filter[region]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Host"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Register a remote host running fleet-agent. The returned token must be set as AGENT_TOKEN on the agent; it is only shown once. The master checks the agent immediately and schedules new workers on the host once it is online. region is the host's egress region; accounts whose proxy_region matches are placed on it according to SCHEDULER_REGION_AFFINITY. Requires the admin token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Host"
+                ],
+                "summary": "Register Host",
+                "parameters": [
+                    {
+                        "description": "Register Host Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateHostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.RegisteredHost"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/hosts/{id}": {
+            "get": {
+                "description": "Get a host with its status, resources and the number of workers placed on it. Requires the admin token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Host"
+                ],
+                "summary": "Get Host",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Host ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Host"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a remote host's address, agent URL, region or capacity, or cordon it to stop scheduling new workers on it. The master host is configured with SCHEDULER_LOCAL_* settings. Requires the admin token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Host"
+                ],
+                "summary": "Update Host",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Host ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Host Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateHostRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Host"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove a registered remote host. Hosts that still have workers placed on them must be evacuated first. Requires the admin token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Host"
+                ],
+                "summary": "Remove Host",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Host ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/hosts/{id}/evacuate": {
+            "post": {
+                "description": "Cordon the host and move its workers to other hosts in a background job; stopped accounts are rescheduled on their next start. Poll /jobs/{id} for progress. Requires the admin token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Host"
+                ],
+                "summary": "Evacuate Host",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Host ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/inbox": {
+            "get": {
+                "description": "Inbound messages of all accounts, collected from the workers in the background (INBOX_COLLECT_INTERVAL_SECONDS), newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "List Inbox",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only messages not yet marked read",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contact contains this text",
+                        "name": "contact",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only messages at or after this time (RFC3339)",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only messages before this time (RFC3339)",
+                        "name": "until",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending (default -timestamp)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account IDs (comma separated)",
+                        "name": "filter[account_id]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Message type",
+                        "name": "filter[type]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Message"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/inbox/read": {
+            "post": {
+                "description": "Mark inbound messages as read by message IDs, account, contact and/or receive time, so they no longer match unread=true",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Mark Inbox Read",
+                "parameters": [
+                    {
+                        "description": "Messages to mark",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.MarkInboxReadRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.MarkInboxReadResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs": {
+            "get": {
+                "description": "List background jobs (worker restarts, bulk sends, message retries, campaigns, janitor runs, account creation, worker upgrades) with their progress",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Job"
+                ],
+                "summary": "List Jobs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "restart_workers, restart_account, bulk_send, message_retry, campaign, janitor, create_account or upgrade_workers",
+                        "name": "filter[type]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "running, succeeded, failed, cancelled or interrupted",
+                        "name": "filter[status]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Job"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}": {
+            "get": {
+                "description": "Get a background job with its progress, result and error",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Job"
+                ],
+                "summary": "Get Job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/jobs/{id}/cancel": {
+            "post": {
+                "description": "Request cancellation of a running job. Work items already in progress are completed; the job then finishes with status cancelled.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Job"
+                ],
+                "summary": "Cancel Job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/retry": {
+            "post": {
+                "description": "Re-queue failed outbound text messages, optionally scoped by account and time window. Retries are throttled and run in the background.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Retry Failed Messages",
+                "parameters": [
+                    {
+                        "description": "Retry Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.RetryMessagesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.RetryMessagesResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/{id}/preview": {
+            "get": {
+                "description": "Get a sanitized, render-ready HTML preview of a stored message. WhatsApp formatting is converted to HTML and media comes with signed, expiring URLs.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Get Message Preview",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.MessagePreview"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/messages/{id}/status": {
+            "get": {
+                "description": "Delivery state of a message by the ID the master returned as message_id when sending: sent, delivered, read or failed, with the time each state was reached. Receipts come from worker callbacks and periodic polling (RECEIPT_POLL_INTERVAL_SECONDS).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Get Message Status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Message ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.MessageStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation": {
+            "get": {
+                "description": "Messages held by review content policies, newest first",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Content Policy"
+                ],
+                "summary": "List Moderation Queue",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending (default -created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending, sent, failed or rejected",
+                        "name": "filter[status]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account IDs (comma separated)",
+                        "name": "filter[account_id]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campaign",
+                        "name": "filter[campaign]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.ModerationItem"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/{id}": {
+            "get": {
+                "description": "Get a held message with the policies it violated and the review outcome",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Content Policy"
+                ],
+                "summary": "Get Moderation Item",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Moderation item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ModerationItem"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/{id}/approve": {
+            "post": {
+                "description": "Approve a held message and send it without re-checking content policies. The send still respects account disable and send quota; a failed send is recorded as status failed and is not retried.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Content Policy"
+                ],
+                "summary": "Approve Held Message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Moderation item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review note",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.ReviewModerationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ModerationItem"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "502": {
+                        "description": "Bad Gateway",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/moderation/{id}/reject": {
+            "post": {
+                "description": "Reject a held message; it is never sent",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Content Policy"
+                ],
+                "summary": "Reject Held Message",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Moderation item ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Review note",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.ReviewModerationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ModerationItem"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/owners": {
+            "get": {
+                "description": "List the operators and teams that accounts are assigned to, with the number of accounts each. Tenant API keys only see their own accounts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Account"
+                ],
+                "summary": "List Owners",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending, e.g. -accounts",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "operator or team",
+                        "name": "filter[kind]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.OwnerSummary"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/phone-login": {
+            "post": {
+                "description": "Login with phone number. Concurrent requests for the same number are deduplicated: later requests wait for the one in progress and return its result with joined=true instead of starting another worker. If the worker fails to start, data carries its startup diagnostics. With signin_type 40 the response includes login_flow, which tracks the pairing code until the account is logged in (GET /accounts/{id}/login/flow).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "Phone Login",
+                "parameters": [
+                    {
+                        "description": "Phone Login Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.PhoneLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.WorkerStartupDiagnostics"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/qr-login": {
+            "post": {
+                "description": "Assign a worker (the given account, an idle worker or a new account) and start a QR code login. The master polls the worker until the account is logged in; fetch the QR image from qr_code_url.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "QR Login",
+                "parameters": [
+                    {
+                        "description": "QR Login Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.QRLoginRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.QRLoginResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/send-bulk": {
+            "post": {
+                "description": "Fan out a templated message to a list of contacts across logged-in accounts. With more recipients than APPROVAL_THRESHOLD nothing is sent yet: the response is 202 with the pending approval, and the batch starts once it is approved via /approvals/{id}/approve.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Send Bulk Messages",
+                "parameters": [
+                    {
+                        "description": "Bulk Send Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.BulkSendRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key return the first response instead of sending again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.BulkBatch"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Approval"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/send-bulk/{id}": {
+            "get": {
+                "description": "Get progress and per-recipient results of a bulk send batch. Batches are kept in memory only: they are removed BULK_BATCH_RETENTION_HOURS after they finish and are lost when the Master restarts. The bulk_send job keeps the final counts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Get Bulk Batch",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Batch ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.BulkBatch"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/send-media": {
+            "post": {
+                "description": "Send an image, document or audio message. Accepts multipart/form-data with a \"file\" field, or JSON with base64 \"data\", an http(s) \"url\" or the \"storage_key\" of a file uploaded with POST /storage/objects. URLs resolving to loopback, private or link-local addresses (including after redirects) are rejected unless MEDIA_ALLOW_PRIVATE_URLS=true. Rate limit and quota headers behave as for /send-message.",
+                "consumes": [
+                    "application/json",
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Send Media Message",
+                "parameters": [
+                    {
+                        "description": "Media Message Request (JSON)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.MediaMessageRequest"
+                        }
+                    },
+                    {
+                        "type": "file",
+                        "description": "Media file (multipart)",
+                        "name": "file",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/send-message": {
+            "post": {
+                "description": "Send a WhatsApp message. {{contact}} and {{key}} placeholders in message are replaced from variables as in bulk sends. Responses carry X-RateLimit-* and X-Quota-* headers, include a warning when the remaining quota is low, and return 429 once it is exhausted. data.message_id identifies the message for /messages/{id}/status. With dry_run=true the request runs the same account, recipient, rate limit and quota checks and returns the rendered message without contacting the worker, using up quota or recording the message; the response status matches what a real send would return up to the worker call.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Send Message",
+                "parameters": [
+                    {
+                        "description": "Message Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.MessageRequest"
+                        }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Validate and render only, do not send",
+                        "name": "dry_run",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Retries with the same key return the first response instead of sending again",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "dry_run=true",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SendPreview"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/sessions": {
+            "get": {
+                "description": "List session health for all accounts. Use filter[relogin_recommended]=true to find sessions about to expire.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Auth"
+                ],
+                "summary": "List Session Health",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "true or false",
+                        "name": "filter[relogin_recommended]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.SessionHealth"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/stats": {
+            "get": {
+                "description": "Get system statistics with breakdowns by tag, pool, tenant, proxy region and host. availability (fleet-wide and per breakdown) is the 24h, 7d and 30d availability from the accounts' status history, as in GET /accounts/{id}/sla. todayMessages counts messages sent since local midnight. With from, to, granularity or account_id the response also contains a time series of the per-account daily counters (sent, received, failed, uptime_seconds); from defaults to 29 days before to, to defaults to today.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get System Stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "First day (YYYY-MM-DD)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last day (YYYY-MM-DD)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "day, week or month",
+                        "name": "granularity",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only count this account",
+                        "name": "account_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.FleetStats"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/objects": {
+            "post": {
+                "description": "Store a file in the object storage configured by STORAGE_DESTINATION (local directory, S3 or MinIO) and return its key and a signed download URL valid for STORAGE_URL_TTL_MINUTES. Reference the key as storage_key in POST /send-media or POST /accounts/{id}/contacts/import. Tenant uploads are stored under tenants/{tenant}/ and only that tenant can use them.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Upload Object",
+                "parameters": [
+                    {
+                        "type": "file",
+                        "description": "File to store, at most STORAGE_MAX_UPLOAD_MB",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.StoredObject"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Delete an object from the object storage. Deleting a missing object succeeds. Signed URLs issued for it stop working.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Delete Object",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Object key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/storage/objects/url": {
+            "get": {
+                "description": "Generate a fresh signed download URL for an object, e.g. after the URL returned by the upload or export expired. S3 destinations return a presigned S3 URL (at most 7 days); local directories return a link to GET /storage/{key} on this server built from STORAGE_BASE_URL. The object's existence is not checked.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Storage"
+                ],
+                "summary": "Get Object Download URL",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Object key",
+                        "name": "key",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.StoredObject"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/suppressions": {
+            "get": {
+                "description": "Global suppression list: contacts that replied an OPT_OUT_KEYWORDS keyword (source keyword) or were added by hand (source manual), newest first. No account sends to a listed contact.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Suppression"
+                ],
+                "summary": "List Suppressions",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending (default -created_at)",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Contact",
+                        "name": "filter[contact]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "keyword or manual",
+                        "name": "filter[source]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account that received the opt-out",
+                        "name": "filter[account_id]",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Campaign the opt-out is attributed to",
+                        "name": "filter[campaign]",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.OptOut"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Add contacts to the suppression list by hand. Contacts already on the list are skipped; the response lists the new entries.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Suppression"
+                ],
+                "summary": "Add Suppressions",
+                "parameters": [
+                    {
+                        "description": "Contacts",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.AddSuppressionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.OptOut"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/suppressions/{contact}": {
+            "get": {
+                "description": "Whether a contact is suppressed, with all of its opt-out entries",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Suppression"
+                ],
+                "summary": "Get Suppression",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact number",
+                        "name": "contact",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SuppressionStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Remove every opt-out entry of a contact, keyword and manual alike, so accounts can message it again. A later opt-out keyword adds it back.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Suppression"
+                ],
+                "summary": "Remove Suppression",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Contact number",
+                        "name": "contact",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/backups": {
+            "get": {
+                "description": "Get the backup schedule, destination, retention policy, the next scheduled run and the result of the last run",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get Backup Status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.BackupStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/system/backups/run": {
+            "post": {
+                "description": "Back up the session directories of the given accounts, or of all logged-in, enabled accounts when account_ids is empty, to BACKUP_DESTINATION, then apply the retention policy. Accounts that are not logged in are skipped so a backup never replaces a working session with an empty one. Runs as a backup job; poll /jobs/{id} for progress and the result.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Run Backup",
+                "parameters": [
+                    {
+                        "description": "Backup Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.RunBackupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/janitor": {
+            "get": {
+                "description": "Get janitor settings, the last cleanup report and total disk space reclaimed since startup",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get Janitor Status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.JanitorStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/system/janitor/run": {
+            "post": {
+                "description": "Remove exited fleet containers, session directories of accounts deleted longer than JANITOR_RETENTION_DAYS ago and expired diagnostic bundles. Use dry_run=true to only report what would be removed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Run Janitor",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only report, do not delete",
+                        "name": "dry_run",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.JanitorReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/system/leader": {
+            "get": {
+                "description": "Show whether this master replica is the leader, the current leader and its lease. With LEADER_ELECTION=db only the leader spawns, polls and supervises workers; followers serve reads and worker proxy traffic and forward other writes to the leader.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get Leader Status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.LeaderStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/system/migrations": {
+            "get": {
+                "description": "Show the schema version of the database, the latest version this build supports and every versioned migration with whether it has been applied and can be rolled back",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get Database Migrations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.SchemaStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/migrations/rollback": {
+            "post": {
+                "description": "Roll back the most recent applied migrations (default 1), newest first. Stops at a migration that cannot be rolled back. The running server may use the dropped tables, so deploy the matching older build afterwards.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Roll Back Database Migrations",
+                "parameters": [
+                    {
+                        "description": "Rollback Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.MigrationRollbackRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.MigrationResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/migrations/up": {
+            "post": {
+                "description": "Apply all pending migrations in version order, each in its own transaction. Needed when DB_AUTO_MIGRATE is false. Refused with 409 when the database was migrated by a newer build.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Apply Database Migrations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.MigrationResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/system/ports": {
+            "get": {
+                "description": "List worker ports allocated to accounts and ports skipped because another process holds them. Use probe=true to probe every unallocated port now.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Get Port Pool Status",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Probe all unallocated ports before reporting",
+                        "name": "probe",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.PortPoolStatus"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/system/pull-image": {
+            "post": {
+                "description": "Pull a worker image onto hosts ahead of time so spawning and upgrades don't wait on the download. Defaults to the current worker image and all reachable hosts. With prune, older versions of the image repository and dangling worker images are removed after a successful pull (images in use and the most recent JANITOR_KEEP_IMAGES versions are kept). Runs as a pull_image job; poll /jobs/{id} for per-layer progress in detail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Pre-pull Worker Image",
+                "parameters": [
+                    {
+                        "description": "Pull Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.PullImageRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/system/restart-workers": {
+            "post": {
+                "description": "Restart all active workers (e.g. after image update). The restart runs as a background job; poll /jobs/{id} for progress.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Restart All Workers",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/system/upgrade-workers": {
+            "post": {
+                "description": "Pull a new worker image, then recreate running workers in batches. After each batch the workers' status endpoints are checked; if any worker fails, the upgrade stops and all upgraded workers are rolled back to the previous image. Runs as an upgrade_workers job; poll /jobs/{id} for the stage and result.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "System"
+                ],
+                "summary": "Upgrade Workers",
+                "parameters": [
+                    {
+                        "description": "Upgrade Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpgradeWorkersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Job"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/tenants": {
+            "get": {
+                "description": "List tenants with their limits and current usage. Requires the admin token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenant"
+                ],
+                "summary": "List Tenants",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Tenant"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a tenant with optional limits on worker count and messages per day (0 = unlimited). Requires the admin token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenant"
+                ],
+                "summary": "Create Tenant",
+                "parameters": [
+                    {
+                        "description": "Create Tenant Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateTenantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Tenant"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/tenants/{id}": {
+            "get": {
+                "description": "Get a tenant with its limits and current usage. Tenant API keys may only read their own tenant.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenant"
+                ],
+                "summary": "Get Tenant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Tenant"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "Update a tenant's name or limits. Requires the admin token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenant"
+                ],
+                "summary": "Update Tenant",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Update Tenant Request",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateTenantRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Tenant"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/tenants/{id}/keys": {
+            "get": {
+                "description": "List a tenant's API keys without the secret part. Requires the admin token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenant"
+                ],
+                "summary": "List Tenant API Keys",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.TenantAPIKey"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Generate an API key scoped to the tenant, optionally bound to an operator or team (owner) whose accounts it may send through when API_KEY_OWNER_ENFORCEMENT is on. The key is only returned once; send it as X-API-Key. Requires the admin token.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenant"
+                ],
+                "summary": "Create Tenant API Key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Create API Key Request",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateAPIKeyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.CreatedAPIKey"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/tenants/{id}/keys/{key_id}": {
+            "delete": {
+                "description": "Revoke an API key; requests using it are rejected immediately. Requires the admin token.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tenant"
+                ],
+                "summary": "Revoke Tenant API Key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tenant ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "API Key ID",
+                        "name": "key_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/tracking/stats": {
+            "get": {
+                "description": "Get click-through statistics of tracked links, grouped by campaign and account",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Tracking"
+                ],
+                "summary": "Get Link Click Stats",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Campaign",
+                        "name": "campaign",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "account_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ClickStatsReport"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks": {
+            "get": {
+                "description": "List registered webhooks; tenants only see their own",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "List Webhooks",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort fields, prefix with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/model.Webhook"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Register a URL that receives events (e.g. message.delivered, message.read) as JSON POSTs signed with HMAC-SHA256 in the X-Fleet-Signature header. The secret is only returned on creation. Webhooks created with a tenant API key only receive events of that tenant's accounts. filter limits delivery to account_ids, message directions (inbound, outbound), contact patterns (* wildcard) and body keywords; include_media adds signed media URLs to message events; retry overrides max_attempts, backoff_ms and max_backoff_ms.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Create Webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.CreateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.CreatedWebhook"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/webhooks/{id}": {
+            "delete": {
+                "description": "Stop delivering events to a webhook",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Delete Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Change a webhook's events, filter, include_media or retry policy. Fields that are not sent stay unchanged; filter and retry replace the whole object. Tenants can only update their own webhooks.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Webhook"
+                ],
+                "summary": "Update Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook changes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.UpdateWebhookRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.Webhook"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/model.APIResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/worker/accounts/{id}/diagnostics": {
+            "post": {
+                "description": "Called by workers to push screenshots, crash dumps or page HTML for an account. Authenticated with the X-Worker-Token header that the master passes to each worker as WORKER_TOKEN.",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Diagnostics"
+                ],
+                "summary": "Upload Diagnostic Bundle",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Worker token",
+                        "name": "X-Worker-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "Diagnostic file, repeat for multiple files",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Related incident or event ID",
+                        "name": "incident_id",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "screenshot, crash_dump, html or other",
+                        "name": "kind",
+                        "in": "formData"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Free-form description",
+                        "name": "note",
+                        "in": "formData"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.DiagnosticBundle"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/worker/accounts/{id}/receipts": {
+            "post": {
+                "description": "Called by workers when WhatsApp acknowledges an outgoing message. Authenticated with the X-Worker-Token header. Status only moves forward (sent, delivered, read) and each change emits a message.delivered or message.read event.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "Message"
+                ],
+                "summary": "Report Message Receipts",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Account ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Worker token",
+                        "name": "X-Worker-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Receipts",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/model.ReportReceiptsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/model.APIResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/model.ReportReceiptsResult"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        }
+    },
     "definitions": {
+        "graphql.Error": {
+            "type": "object",
+            "properties": {
+                "locations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/graphql.Location"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "array",
+                    "items": {}
+                }
+            }
+        },
+        "graphql.Location": {
+            "type": "object",
+            "properties": {
+                "column": {
+                    "type": "integer"
+                },
+                "line": {
+                    "type": "integer"
+                }
+            }
+        },
+        "graphql.Response": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/graphql.Error"
+                    }
+                }
+            }
+        },
         "model.APIResponse": {
             "type": "object",
             "properties": {
-                "data": {},
+                "code": {
+                    "description": "稳定的消息代码，不随语言变化",
+                    "type": "string"
+                },
+                "data": {},
+                "error": {
+                    "type": "string"
+                },
+                "message": {
+                    "description": "按请求语言本地化的消息",
+                    "type": "string"
+                },
+                "meta": {
+                    "$ref": "#/definitions/model.ListMeta"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "warning": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Account": {
+            "type": "object",
+            "properties": {
+                "avg_session_hours": {
+                    "description": "历史会话平均时长",
+                    "type": "number"
+                },
+                "container_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "disabled": {
+                    "description": "维护模式：拒绝发送并排除在批量发送和活动之外，Worker保持运行",
+                    "type": "boolean"
+                },
+                "disabled_at": {
+                    "type": "string"
+                },
+                "disabled_reason": {
+                    "type": "string"
+                },
+                "hardware_profile_id": {
+                    "description": "绑定的硬件指纹配置，启动Worker和登录时注入",
+                    "type": "string"
+                },
+                "host_id": {
+                    "description": "Worker容器所在主机",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "keepalive_disabled": {
+                    "description": "不参与会话保活和主动刷新",
+                    "type": "boolean"
+                },
+                "last_activity": {
+                    "type": "string"
+                },
+                "last_restart_at": {
+                    "description": "最近一次自动重启时间",
+                    "type": "string"
+                },
+                "media_bytes_sent": {
+                    "type": "integer"
+                },
+                "media_sent": {
+                    "type": "integer"
+                },
+                "messages_received": {
+                    "type": "integer"
+                },
+                "messages_sent": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notes": {
+                    "description": "运维备注",
+                    "type": "string"
+                },
+                "owner_channel": {
+                    "description": "告警通知Webhook",
+                    "type": "string"
+                },
+                "owner_email": {
+                    "description": "负责人邮箱",
+                    "type": "string"
+                },
+                "owner_operator": {
+                    "description": "负责的运营人员",
+                    "type": "string"
+                },
+                "owner_team": {
+                    "description": "负责团队",
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "pod_name": {
+                    "type": "string"
+                },
+                "pool": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "proxy": {
+                    "description": "绑定的代理，登录和Worker重建时使用",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.AccountProxy"
+                        }
+                    ]
+                },
+                "proxy_region": {
+                    "type": "string"
+                },
+                "resources": {
+                    "description": "账号单独设置的Worker资源限制，未设置的项使用全局默认",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.WorkerResources"
+                        }
+                    ]
+                },
+                "restart_count": {
+                    "description": "自动恢复累计重启次数",
+                    "type": "integer"
+                },
+                "runtime": {
+                    "description": "账号额外的Worker环境变量、挂载卷和DNS，与全局默认合并",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.WorkerRuntime"
+                        }
+                    ]
+                },
+                "send_limit": {
+                    "description": "账号每分钟允许的发送请求数，0表示使用全局默认",
+                    "type": "integer"
+                },
+                "send_limit_burst": {
+                    "description": "账号突发容量，0表示使用全局默认",
+                    "type": "integer"
+                },
+                "service_url": {
+                    "type": "string"
+                },
+                "session_drops": {
+                    "description": "会话意外掉线次数",
+                    "type": "integer"
+                },
+                "session_refresh_at": {
+                    "description": "最近一次主动刷新会话时间",
+                    "type": "string"
+                },
+                "session_started_at": {
+                    "description": "当前登录会话开始时间",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "creating, starting, running, stopping, stopped, error, logged_in, logged_out, restarting, crash_looping, unreachable, banned",
+                    "type": "string"
+                },
+                "status_poll_seconds": {
+                    "description": "账号稳定时轮询Worker状态的间隔（秒），0表示使用全局配置",
+                    "type": "integer"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "typing_simulation": {
+                    "description": "发送文本前先显示正在输入并按消息长度等待",
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "warmup_profile": {
+                    "description": "预热方案，为空时使用默认方案，off表示不预热",
+                    "type": "string"
+                },
+                "warmup_started_at": {
+                    "description": "预热起点，为空时使用创建时间",
+                    "type": "string"
+                },
+                "worker_api_version": {
+                    "description": "Worker上报的API版本，旧版Worker不上报时为0",
+                    "type": "integer"
+                },
+                "worker_incompatible": {
+                    "description": "Worker的API版本不在Master支持的范围内",
+                    "type": "boolean"
+                },
+                "worker_version": {
+                    "description": "Worker上报的版本",
+                    "type": "string"
+                }
+            }
+        },
+        "model.AccountOwner": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "description": "告警通知Webhook地址，为空时使用全局 ALERT_WEBHOOK_URL",
+                    "type": "string"
+                },
+                "email": {
+                    "type": "string"
+                },
+                "operator": {
+                    "description": "负责的运营人员",
+                    "type": "string"
+                },
+                "team": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AccountProxy": {
+            "type": "object",
+            "properties": {
+                "ip": {
+                    "description": "IP或主机名",
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "protocol": {
+                    "description": "socks5、socks4、http、https，为空表示socks5",
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AccountSLA": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "outages": {
+                    "description": "最近30天的故障时段，最新的在前",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SLAOutage"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "windows": {
+                    "description": "24h, 7d, 30d",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.SLAWindow"
+                    }
+                }
+            }
+        },
+        "model.AccountSendLimit": {
+            "type": "object",
+            "properties": {
+                "burst": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "per_minute": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "model.AddContactRequest": {
+            "type": "object",
+            "required": [
+                "phone"
+            ],
+            "properties": {
+                "firstName": {
+                    "type": "string"
+                },
+                "lastName": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AddGroupParticipantsRequest": {
+            "type": "object",
+            "required": [
+                "groupId",
+                "participants"
+            ],
+            "properties": {
+                "groupId": {
+                    "type": "string"
+                },
+                "participants": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.AddSuppressionRequest": {
+            "type": "object",
+            "required": [
+                "contacts"
+            ],
+            "properties": {
+                "contacts": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "reason": {
+                    "type": "string",
+                    "maxLength": 500
+                }
+            }
+        },
+        "model.AlertChannel": {
+            "type": "object",
+            "properties": {
+                "chat_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "smtp_host": {
+                    "description": "email",
+                    "type": "string"
+                },
+                "smtp_port": {
+                    "type": "integer"
+                },
+                "smtp_username": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "description": "slack, telegram, email, webhook",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "description": "slack: Incoming Webhook地址；webhook: 接收Incident JSON的地址",
+                    "type": "string"
+                }
+            }
+        },
+        "model.AlertChannelRequest": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "bot_token": {
+                    "type": "string"
+                },
+                "chat_id": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "description": "默认true",
+                    "type": "boolean"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "smtp_host": {
+                    "type": "string"
+                },
+                "smtp_password": {
+                    "type": "string"
+                },
+                "smtp_port": {
+                    "description": "默认587",
+                    "type": "integer",
+                    "maximum": 65535,
+                    "minimum": 1
+                },
+                "smtp_username": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "slack",
+                        "telegram",
+                        "email",
+                        "webhook"
+                    ]
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AlertRule": {
+            "type": "object",
+            "properties": {
+                "account_ids": {
+                    "description": "为空时匹配所有账号",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "channels": {
+                    "description": "告警通道ID",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "description": "为空时匹配 ALERT_EVENTS 中的事件",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner_team": {
+                    "description": "只匹配该团队负责的账号",
+                    "type": "string"
+                },
+                "pool": {
+                    "description": "只匹配该账号池",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AlertRuleRequest": {
+            "type": "object",
+            "required": [
+                "channels"
+            ],
+            "properties": {
+                "account_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "channels": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "enabled": {
+                    "description": "默认true",
+                    "type": "boolean"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner_team": {
+                    "type": "string"
+                },
+                "pool": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AlertTestResult": {
+            "type": "object",
+            "properties": {
+                "channel_id": {
+                    "type": "string"
+                },
+                "delivered": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Approval": {
+            "type": "object",
+            "properties": {
+                "account_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "batch_id": {
+                    "description": "批准后创建的批量发送批次",
+                    "type": "string"
+                },
+                "campaign": {
+                    "description": "活动名称或批量发送的campaign标签",
+                    "type": "string"
+                },
+                "campaign_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "campaign, bulk",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "消息模板",
+                    "type": "string"
+                },
+                "note": {
+                    "description": "审批备注",
+                    "type": "string"
+                },
+                "recipients": {
+                    "type": "integer"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "sample": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AssignAccountsRequest": {
+            "type": "object",
+            "required": [
+                "account_ids"
+            ],
+            "properties": {
+                "account_ids": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "team": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AuditEntry": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "actor": {
+                    "description": "admin、api_key 或 anonymous（未开启鉴权）",
+                    "type": "string"
+                },
+                "api_key_id": {
+                    "type": "string"
+                },
+                "client_ip": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "request": {
+                    "description": "脱敏并截断后的请求体",
+                    "type": "string"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "result": {
+                    "description": "响应消息，失败时附带错误",
+                    "type": "string"
+                },
+                "route": {
+                    "description": "路由模板，如 /api/v1/accounts/:id/stop",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "tenant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AutoReplyLog": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "contact": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "inbound_message_id": {
+                    "type": "string"
+                },
+                "message_id": {
+                    "description": "回复消息的ID",
+                    "type": "string"
+                },
+                "reply": {
+                    "type": "string"
+                },
+                "rule_id": {
+                    "type": "string"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "queued, sent, failed, dropped",
+                    "type": "string"
+                }
+            }
+        },
+        "model.AutoReplyRule": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "include_groups": {
+                    "description": "是否回复群组消息",
+                    "type": "boolean"
+                },
+                "keywords": {
+                    "description": "match_type为keyword时使用",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "match_type": {
+                    "description": "keyword, regex",
+                    "type": "string"
+                },
+                "max_replies_per_contact": {
+                    "description": "每个联系人在 AUTO_REPLY_WINDOW_HOURS 内最多回复次数，0表示不限制",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pattern": {
+                    "description": "match_type为regex时使用",
+                    "type": "string"
+                },
+                "priority": {
+                    "description": "数值小的先匹配",
+                    "type": "integer"
+                },
+                "quiet_hour_end": {
+                    "description": "静默时段结束（不含）",
+                    "type": "integer"
+                },
+                "quiet_hour_start": {
+                    "description": "静默时段开始（本地时间，小时），与结束相同表示不静默",
+                    "type": "integer"
+                },
+                "reply": {
+                    "description": "回复模板，支持 {{contact}}、{{message}} 和正则命名分组 {{name}}",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AutoReplyRuleRequest": {
+            "type": "object",
+            "required": [
+                "keywords",
+                "match_type",
+                "reply"
+            ],
+            "properties": {
+                "enabled": {
+                    "description": "默认true",
+                    "type": "boolean"
+                },
+                "include_groups": {
+                    "type": "boolean"
+                },
+                "keywords": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "match_type": {
+                    "type": "string",
+                    "enum": [
+                        "keyword",
+                        "regex"
+                    ]
+                },
+                "max_replies_per_contact": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "pattern": {
+                    "type": "string",
+                    "maxLength": 1000
+                },
+                "priority": {
+                    "type": "integer"
+                },
+                "quiet_hour_end": {
+                    "type": "integer",
+                    "maximum": 23,
+                    "minimum": 0
+                },
+                "quiet_hour_start": {
+                    "type": "integer",
+                    "maximum": 23,
+                    "minimum": 0
+                },
+                "reply": {
+                    "type": "string",
+                    "maxLength": 4096
+                }
+            }
+        },
+        "model.BackupRunResult": {
+            "type": "object",
+            "properties": {
+                "backups": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.SessionBackup"
+                    }
+                },
+                "duration": {
+                    "type": "string"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "pruned": {
+                    "description": "按保留策略删除的旧备份数",
+                    "type": "integer"
+                },
+                "skipped": {
+                    "description": "未登录或已停用而跳过的账号",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.BackupStatus": {
+            "type": "object",
+            "properties": {
+                "destination": {
+                    "type": "string"
+                },
+                "last_run": {
+                    "$ref": "#/definitions/model.BackupRunResult"
+                },
+                "last_run_at": {
+                    "type": "string"
+                },
+                "next_run": {
+                    "type": "string"
+                },
+                "retention_count": {
+                    "type": "integer"
+                },
+                "retention_days": {
+                    "type": "integer"
+                },
+                "schedule": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BanRecord": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "evidence": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "previous_status": {
+                    "description": "隔离前的账号状态",
+                    "type": "string"
+                },
+                "release_note": {
+                    "type": "string"
+                },
+                "released_at": {
+                    "type": "string"
+                },
+                "signal": {
+                    "description": "匹配到的错误特征或掉线次数",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "worker_error, logout, logout_loop",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.BroadcastAccountReport": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "assigned": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "sent": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BroadcastFilter": {
+            "type": "object",
+            "properties": {
+                "account_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pool": {
+                    "type": "string"
+                },
+                "tags": {
+                    "description": "账号需包含全部标签",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.BroadcastReport": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BroadcastAccountReport"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duplicates": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "description": "失败原因 -\u003e 收件人数",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "description": "批量发送批次ID",
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "pending": {
+                    "type": "integer"
+                },
+                "sent": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total": {
+                    "description": "去重后的收件人数",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BroadcastRequest": {
+            "type": "object",
+            "required": [
+                "contacts",
+                "message"
+            ],
+            "properties": {
+                "campaign": {
+                    "type": "string"
+                },
+                "concurrency": {
+                    "type": "integer"
+                },
+                "contacts": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "interval_ms": {
+                    "type": "integer"
+                },
+                "message": {
+                    "description": "支持 {{contact}} 变量",
+                    "type": "string"
+                },
+                "senders": {
+                    "$ref": "#/definitions/model.BroadcastFilter"
+                },
+                "track_links": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.BulkAccountResult": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "$ref": "#/definitions/model.Account"
+                },
+                "account_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending, created, failed, skipped",
+                    "type": "string"
+                }
+            }
+        },
+        "model.BulkBatch": {
+            "type": "object",
+            "properties": {
+                "account_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "campaign": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "duplicates": {
+                    "description": "广播时去掉的重复收件人数",
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BulkResult"
+                    }
+                },
+                "sent": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "running, completed, cancelled, interrupted",
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BulkCreateAccountsRequest": {
+            "type": "object",
+            "required": [
+                "phones"
+            ],
+            "properties": {
+                "concurrency": {
+                    "description": "同时启动的Worker数，不超过 WORKER_PROVISION_CONCURRENCY",
+                    "type": "integer"
+                },
+                "hardware_info": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "hardware_profile_id": {
+                    "description": "所有账号绑定同一硬件指纹配置",
+                    "type": "string"
+                },
+                "host_id": {
+                    "type": "string"
+                },
+                "owner": {
+                    "$ref": "#/definitions/model.AccountOwner"
+                },
+                "phones": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pool": {
+                    "type": "string"
+                },
+                "proxies": {
+                    "description": "代理池，按号码顺序轮流分配给账号",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ProxyConfig"
+                    }
+                },
+                "resources": {
+                    "$ref": "#/definitions/model.WorkerResources"
+                },
+                "runtime": {
+                    "$ref": "#/definitions/model.WorkerRuntime"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tenant_id": {
+                    "description": "使用租户API Key时由调用方租户决定",
+                    "type": "string"
+                }
+            }
+        },
+        "model.BulkCreateAccountsResult": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.BulkAccountResult"
+                    }
+                },
+                "created": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "job": {
+                    "$ref": "#/definitions/model.Job"
+                },
+                "skipped": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.BulkRecipient": {
+            "type": "object",
+            "required": [
+                "contact"
+            ],
+            "properties": {
+                "contact": {
+                    "type": "string"
+                },
+                "variables": {
+                    "description": "模板变量，替换消息中的 {{key}}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.BulkResult": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "contact": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending, sending, sent, failed",
+                    "type": "string"
+                }
+            }
+        },
+        "model.BulkSendRequest": {
+            "type": "object",
+            "required": [
+                "message",
+                "recipients"
+            ],
+            "properties": {
+                "account_ids": {
+                    "description": "为空时使用所有已登录账号",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "campaign": {
+                    "type": "string"
+                },
+                "concurrency": {
+                    "description": "全局并发发送数",
+                    "type": "integer"
+                },
+                "interval_ms": {
+                    "description": "同一账号两次发送的最小间隔",
+                    "type": "integer"
+                },
+                "message": {
+                    "description": "消息模板",
+                    "type": "string"
+                },
+                "recipients": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.BulkRecipient"
+                    }
+                },
+                "track_links": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.Campaign": {
+            "type": "object",
+            "properties": {
+                "account_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "interval_ms": {
+                    "description": "同一账号两次发送的最小间隔",
+                    "type": "integer"
+                },
+                "job_id": {
+                    "description": "最近一次发送的任务ID",
+                    "type": "string"
+                },
+                "last_error": {
+                    "description": "最近一次无法继续发送的原因",
+                    "type": "string"
+                },
+                "message": {
+                    "description": "消息模板，支持 {{contact}} 和收件人变量",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "同时作为消息的campaign标签，用于指标和退订归属",
+                    "type": "string"
+                },
+                "progress": {
+                    "$ref": "#/definitions/model.CampaignProgress"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "draft, pending_approval, running, paused, completed",
+                    "type": "string"
+                },
+                "track_links": {
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CampaignProgress": {
+            "type": "object",
+            "properties": {
+                "failed": {
+                    "type": "integer"
+                },
+                "pending": {
+                    "type": "integer"
+                },
+                "sending": {
+                    "type": "integer"
+                },
+                "sent": {
+                    "type": "integer"
+                },
+                "skipped": {
+                    "description": "已退订的联系人",
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.CampaignRecipient": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "campaign_id": {
+                    "type": "string"
+                },
+                "contact": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "pending, sending, sent, failed, skipped",
+                    "type": "string"
+                },
+                "variables": {
+                    "$ref": "#/definitions/model.StringMap"
+                },
+                "worker_message_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChaosDelayRequest": {
+            "type": "object",
+            "required": [
+                "duration_seconds"
+            ],
+            "properties": {
+                "delay_ms": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "duration_seconds": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "error_rate": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                }
+            }
+        },
+        "model.ChaosFault": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "delay_ms": {
+                    "description": "每次Worker调用前的额外延迟",
+                    "type": "integer"
+                },
+                "error_rate": {
+                    "description": "Worker调用直接失败的概率（0-1）",
+                    "type": "number"
+                },
+                "expires_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChaosStatusRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChatHistory": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "cached": {
+                    "description": "本次从Worker抓取并新写入数据库的消息数",
+                    "type": "integer"
+                },
+                "contact": {
+                    "type": "string"
+                },
+                "has_more": {
+                    "description": "是否还有更早的消息",
+                    "type": "boolean"
+                },
+                "messages": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Message"
+                    }
+                },
+                "next_before": {
+                    "description": "下一页（更早的消息）的 before 参数",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ChatState": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "contact": {
+                    "type": "string"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ClaimConversationRequest": {
+            "type": "object",
+            "required": [
+                "agent_id"
+            ],
+            "properties": {
+                "agent_id": {
+                    "type": "string"
+                },
+                "force": {
+                    "description": "强制从其他坐席转移",
+                    "type": "boolean"
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ClickStats": {
+            "type": "object",
+            "properties": {
+                "click_through_rate": {
+                    "type": "number"
+                },
+                "clicked_messages": {
+                    "description": "至少有一次点击的消息数",
+                    "type": "integer"
+                },
+                "clicks": {
+                    "description": "总点击次数",
+                    "type": "integer"
+                },
+                "links": {
+                    "description": "跟踪链接数",
+                    "type": "integer"
+                },
+                "messages": {
+                    "description": "含跟踪链接的消息数",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ClickStatsReport": {
+            "type": "object",
+            "properties": {
+                "by_account": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.ClickStats"
+                    }
+                },
+                "by_campaign": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.ClickStats"
+                    }
+                },
+                "total": {
+                    "$ref": "#/definitions/model.ClickStats"
+                }
+            }
+        },
+        "model.CloneAccountRequest": {
+            "type": "object",
+            "required": [
+                "account_id"
+            ],
+            "properties": {
+                "account_id": {
+                    "description": "新账号ID",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "copy_session": {
+                    "description": "用源账号最近一次成功的备份初始化新账号的会话目录",
+                    "type": "boolean"
+                },
+                "host_id": {
+                    "description": "指定Worker运行的主机，为空时由调度器选择",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "新账号名称，默认为账号ID",
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "tags": {
+                    "description": "替换复制的标签",
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.ConfigOverride": {
+            "type": "object",
+            "properties": {
+                "hot_reload": {
+                    "description": "为false时需要重启Master才能生效",
+                    "type": "boolean"
+                },
+                "key": {
+                    "description": "如 rateLimit.globalPerMinute",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "value": {}
+            }
+        },
+        "model.ConfigUpdateResult": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "description": "已立即生效的配置项",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "pending_restart": {
+                    "description": "已保存但需要重启Master才能生效的配置项",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.Contact": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "is_group": {
+                    "type": "boolean"
+                },
+                "is_my_contact": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "number": {
+                    "type": "string"
+                },
+                "push_name": {
+                    "type": "string"
+                },
+                "synced_at": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "wid": {
+                    "description": "WhatsApp ID，如 8613800000000@c.us",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ContactImportResult": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "added": {
+                    "type": "integer"
+                },
+                "duplicates": {
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "invalid": {
+                    "type": "integer"
+                },
+                "rows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ContactImportRow"
+                    }
+                },
+                "skipped": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ContactImportRow": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "first_name": {
+                    "type": "string"
+                },
+                "last_name": {
+                    "type": "string"
+                },
+                "number": {
+                    "description": "规范化后的 E.164 号码",
+                    "type": "string"
+                },
+                "phone": {
+                    "description": "文件中的原始号码",
+                    "type": "string"
+                },
+                "row": {
+                    "description": "文件中的行号，从1开始，包含表头",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "added, invalid, duplicate, failed, skipped",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ContactPresence": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "checked_at": {
+                    "type": "string"
+                },
+                "contact": {
+                    "type": "string"
+                },
+                "last_seen": {
+                    "description": "对方公开时的最后在线时间",
+                    "type": "string"
+                },
+                "online": {
+                    "description": "对方隐藏在线状态或尚未收到时为null",
+                    "type": "boolean"
+                },
+                "state": {
+                    "description": "available, unavailable, typing, recording",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ContactSyncResult": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "removed": {
+                    "description": "Worker上已不存在而删除的联系人数",
+                    "type": "integer"
+                },
+                "synced": {
+                    "description": "Worker返回并写入的联系人数",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ContentPolicy": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "description": "reject, review",
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "domains": {
+                    "description": "type为url_allowlist时允许的域名",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "keywords": {
+                    "description": "type为keyword时的屏蔽词",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_links": {
+                    "description": "type为max_links时每条消息最多的链接数",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "patterns": {
+                    "description": "type为pii时的内置名称或正则表达式",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "description": "keyword, url_allowlist, max_links, pii",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ContentPolicyRequest": {
+            "type": "object",
+            "required": [
+                "action",
+                "domains",
+                "keywords",
+                "patterns",
+                "type"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "reject",
+                        "review"
+                    ]
+                },
+                "domains": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "enabled": {
+                    "description": "默认true",
+                    "type": "boolean"
+                },
+                "keywords": {
+                    "type": "array",
+                    "maxItems": 500,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_links": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "patterns": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "keyword",
+                        "url_allowlist",
+                        "max_links",
+                        "pii"
+                    ]
+                }
+            }
+        },
+        "model.ContentViolation": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "detail": {
+                    "description": "命中的屏蔽词、链接或个人信息类型",
+                    "type": "string"
+                },
+                "policy_id": {
+                    "type": "string"
+                },
+                "policy_name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Conversation": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "agent_id": {
+                    "type": "string"
+                },
+                "assigned_at": {
+                    "type": "string"
+                },
+                "contact": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "handled_by": {
+                    "description": "bot, agent",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateAPIKeyRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "owner": {
+                    "description": "运营人员或团队名",
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateCampaignRequest": {
+            "type": "object",
+            "required": [
+                "account_ids",
+                "message",
+                "name",
+                "recipients"
+            ],
+            "properties": {
+                "account_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "interval_ms": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "recipients": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/model.BulkRecipient"
+                    }
+                },
+                "track_links": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.CreateGroupRequest": {
+            "type": "object",
+            "required": [
+                "name",
+                "participants"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "participants": {
+                    "description": "成员ID，如 8613800000000@c.us",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.CreateGroupResult": {
+            "type": "object",
+            "properties": {
+                "gid": {
+                    "description": "新群组ID，Worker返回 {_serialized: ...}",
+                    "type": "object"
+                },
+                "participants": {
+                    "description": "每个成员的添加结果",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateHostRequest": {
+            "type": "object",
+            "required": [
+                "address",
+                "agent_url",
+                "id"
+            ],
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "agent_url": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max_workers": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateTenantRequest": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "max_messages_per_day": {
+                    "type": "integer"
+                },
+                "max_workers": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreateWebhookRequest": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "events": {
+                    "description": "如 message.delivered、message.read，为空表示所有事件",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "filter": {
+                    "$ref": "#/definitions/model.WebhookFilter"
+                },
+                "include_media": {
+                    "type": "boolean"
+                },
+                "retry": {
+                    "$ref": "#/definitions/model.WebhookRetryPolicy"
+                },
+                "secret": {
+                    "description": "为空时自动生成",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreatedAPIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "last_used_at": {
+                    "description": "最近使用时间，每分钟最多更新一次",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner": {
+                    "description": "绑定的运营人员或团队，开启 API_KEY_OWNER_ENFORCEMENT 后只能通过其名下账号发送",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Key的前几位，用于识别",
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.CreatedWebhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "description": "订阅的事件类型，为空表示所有事件",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "filter": {
+                    "description": "投递前的过滤条件",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.WebhookFilter"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "string"
+                },
+                "include_media": {
+                    "description": "消息事件附带签名的媒体链接",
+                    "type": "boolean"
+                },
+                "retry": {
+                    "description": "投递失败时的重试策略",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.WebhookRetryPolicy"
+                        }
+                    ]
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.DeadLetter": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "campaign": {
+                    "type": "string"
+                },
+                "contact": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "description": "最近一次失败的原因",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_retry_at": {
+                    "type": "string"
+                },
+                "message_id": {
+                    "description": "对应的消息记录，重试成功后状态同步更新",
+                    "type": "string"
+                },
+                "next_retry_at": {
+                    "description": "下次自动重试时间，未开启自动重试时为空",
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "retries": {
+                    "description": "进入死信队列后的重试次数",
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "chat, image, document, audio",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "voice": {
+                    "description": "音频作为语音消息发送",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.DeleteAccountResult": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "purge_error": {
+                    "description": "清除失败时由清理任务按保留策略重试",
+                    "type": "string"
+                },
+                "reclaimed_bytes": {
+                    "description": "清除会话数据回收的空间，远程主机上无法统计",
+                    "type": "integer"
+                },
+                "session_purged": {
+                    "description": "会话数据是否已清除",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.DiagnosticBundle": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "files": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.DiagnosticFile"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "incident_id": {
+                    "description": "关联的故障或事件ID",
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "screenshot, crash_dump, html, other",
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "size": {
+                    "description": "所有文件的总字节数",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.DiagnosticFile": {
+            "type": "object",
+            "properties": {
+                "mime_type": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.DisableAccountRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "description": "停用原因，如 maintenance",
+                    "type": "string"
+                }
+            }
+        },
+        "model.Event": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "data": {},
+                "timestamp": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ExternalIPResult": {
+            "type": "object",
+            "properties": {
+                "ip": {
+                    "description": "查询失败时为错误描述",
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.FleetStats": {
+            "type": "object",
+            "properties": {
+                "activeContacts": {
+                    "type": "integer"
+                },
+                "availability": {
+                    "description": "24h, 7d, 30d，全部账号的可用性",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.SLAWindow"
+                    }
+                },
+                "breakdowns": {
+                    "description": "tag, pool, tenant, proxy_region, host",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "object",
+                        "additionalProperties": {
+                            "$ref": "#/definitions/model.StatsBucket"
+                        }
+                    }
+                },
+                "onlineWorkers": {
+                    "type": "integer"
+                },
+                "series": {
+                    "description": "指定 from/to/granularity 时返回的时间序列",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.StatsPoint"
+                    }
+                },
+                "todayMessages": {
+                    "type": "integer"
+                },
+                "totalWorkers": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.GraphQLRequest": {
+            "type": "object",
+            "required": [
+                "query"
+            ],
+            "properties": {
+                "operationName": {
+                    "type": "string"
+                },
+                "query": {
+                    "type": "string"
+                },
+                "variables": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "model.Group": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "gid": {
+                    "description": "群组ID，如 120363000000000000@g.us",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "invite_link": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                },
+                "participant_count": {
+                    "type": "integer"
+                },
+                "participants": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.GroupMember"
+                    }
+                },
+                "synced_at": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.GroupMember": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "is_admin": {
+                    "type": "boolean"
+                },
+                "is_super_admin": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.GroupParticipantsRequest": {
+            "type": "object",
+            "required": [
+                "participants"
+            ],
+            "properties": {
+                "participants": {
+                    "description": "成员ID，如 8613800000000@c.us",
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.HardwareInfo": {
+            "type": "object",
+            "properties": {
+                "browser": {
+                    "type": "string"
+                },
+                "locale": {
+                    "description": "BCP 47 语言标签，如 zh-CN",
+                    "type": "string"
+                },
+                "os": {
+                    "type": "string"
+                },
+                "screen_height": {
+                    "type": "integer"
+                },
+                "screen_width": {
+                    "type": "integer"
+                },
+                "timezone": {
+                    "description": "IANA时区，如 Asia/Shanghai",
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.HardwareProfile": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "description": "绑定该配置的账号数",
+                    "type": "integer"
+                },
+                "browser": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "locale": {
+                    "description": "BCP 47 语言标签，如 zh-CN",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "os": {
+                    "type": "string"
+                },
+                "screen_height": {
+                    "type": "integer"
+                },
+                "screen_width": {
+                    "type": "integer"
+                },
+                "timezone": {
+                    "description": "IANA时区，如 Asia/Shanghai",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_agent": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.HardwareProfileRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "browser": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "locale": {
+                    "type": "string",
+                    "maxLength": 35
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                },
+                "os": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "screen_height": {
+                    "type": "integer",
+                    "maximum": 4320,
+                    "minimum": 240
+                },
+                "screen_width": {
+                    "type": "integer",
+                    "maximum": 7680,
+                    "minimum": 320
+                },
+                "timezone": {
+                    "type": "string",
+                    "maxLength": 64
+                },
+                "user_agent": {
+                    "type": "string",
+                    "maxLength": 512
+                }
+            }
+        },
+        "model.HealthCheck": {
+            "type": "object",
+            "properties": {
+                "details": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "name": {
+                    "description": "database、docker、disk、port_pool、hosts、worker_versions、goroutines",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "healthy、degraded 或 unhealthy",
+                    "type": "string"
+                }
+            }
+        },
+        "model.HealthStatus": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Account"
+                    }
+                },
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.HealthCheck"
+                    }
+                },
+                "logged_in_count": {
+                    "type": "integer"
+                },
+                "running_count": {
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "healthy、degraded 或 unhealthy，取各项检查中最差的结果",
+                    "type": "string"
+                },
+                "system_info": {
+                    "$ref": "#/definitions/model.SystemInfo"
+                },
+                "total_count": {
+                    "type": "integer"
+                },
+                "uptime": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Host": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Master访问该主机上Worker映射端口使用的地址",
+                    "type": "string"
+                },
+                "agent_url": {
+                    "description": "fleet-agent 地址，本机为空",
+                    "type": "string"
+                },
+                "containers": {
+                    "description": "主机上的Worker容器数",
+                    "type": "integer"
+                },
+                "cordoned": {
+                    "description": "停止调度新账号到该主机",
+                    "type": "boolean"
+                },
+                "cpus": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "load1": {
+                    "description": "1分钟平均负载",
+                    "type": "number"
+                },
+                "max_workers": {
+                    "description": "最多放置的账号数，0表示不限制",
+                    "type": "integer"
+                },
+                "memory_available": {
+                    "description": "字节",
+                    "type": "integer"
+                },
+                "memory_total": {
+                    "description": "字节",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "region": {
+                    "description": "主机的出口地区，调度时与账号的 proxy_region 匹配",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "workers": {
+                    "description": "放置在该主机上的账号数",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.JanitorReport": {
+            "type": "object",
+            "properties": {
+                "audit_entries_removed": {
+                    "type": "integer"
+                },
+                "containers_removed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dead_letters_removed": {
+                    "type": "integer"
+                },
+                "diagnostic_bundles_removed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "idempotency_keys_removed": {
+                    "type": "integer"
+                },
+                "images_removed": {
+                    "description": "主机ID/镜像，悬空镜像以镜像ID表示",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "media_files_removed": {
+                    "type": "integer"
+                },
+                "reclaimed_bytes": {
+                    "type": "integer"
+                },
+                "sessions_removed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "worker_calls_removed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.JanitorStatus": {
+            "type": "object",
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                },
+                "interval_minutes": {
+                    "type": "integer"
+                },
+                "last_reconcile": {
+                    "$ref": "#/definitions/model.ReconcileReport"
+                },
+                "last_run": {
+                    "$ref": "#/definitions/model.JanitorReport"
+                },
+                "retention_days": {
+                    "type": "integer"
+                },
+                "runs": {
+                    "type": "integer"
+                },
+                "session_retention_days": {
+                    "type": "integer"
+                },
+                "total_reclaimed_bytes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Job": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "detail": {
+                    "description": "当前阶段的进度说明，如拉取镜像时已完成的镜像层",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "progress": {
+                    "description": "已完成的工作项",
+                    "type": "integer"
+                },
+                "result": {
+                    "type": "string"
+                },
+                "stage": {
+                    "description": "当前执行阶段，如创建账号时的 pulling_image, starting, waiting_ready",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "running, succeeded, failed, cancelled, interrupted",
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "total": {
+                    "description": "工作项总数，0表示未知",
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "restart_workers, restart_account, bulk_send, message_retry, campaign, janitor, create_account, upgrade_workers, pull_image, backup",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LeaderStatus": {
+            "type": "object",
+            "properties": {
+                "election": {
+                    "description": "off 或 db",
+                    "type": "string"
+                },
+                "is_leader": {
+                    "type": "boolean"
+                },
+                "last_sync_at": {
+                    "description": "作为Follower最近一次从数据库同步账号的时间",
+                    "type": "string"
+                },
+                "leader_id": {
+                    "type": "string"
+                },
+                "leader_since": {
+                    "type": "string"
+                },
+                "leader_url": {
+                    "type": "string"
+                },
+                "lease_expires_at": {
+                    "type": "string"
+                },
+                "replica_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ListMeta": {
+            "type": "object",
+            "properties": {
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.LoginFlow": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "attempt": {
+                    "description": "第几次请求配对码，从1开始",
+                    "type": "integer"
+                },
+                "code_expires_at": {
+                    "description": "超过该时间仍未登录则请求新的配对码",
+                    "type": "string"
+                },
+                "code_issued_at": {
+                    "type": "string"
+                },
+                "deadline": {
+                    "description": "整个流程的截止时间",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "max_attempts": {
+                    "type": "integer"
+                },
+                "pairing_code": {
+                    "type": "string"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "state": {
+                    "description": "requesting_code, waiting_for_code, verifying, logged_in, failed, cancelled",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.LoginRequest": {
+            "type": "object",
+            "required": [
+                "account_id"
+            ],
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "cache_login": {
+                    "type": "boolean"
+                },
+                "hardware_info": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "hardware_profile_id": {
+                    "description": "绑定的硬件指纹配置，代替 hardware_info",
+                    "type": "string"
+                },
+                "host_id": {
+                    "description": "指定Worker运行的主机，为空时由调度器选择负载最低的主机",
+                    "type": "string"
+                },
+                "login_method": {
+                    "description": "qr, phone",
+                    "type": "string"
+                },
+                "owner": {
+                    "$ref": "#/definitions/model.AccountOwner"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "pool": {
+                    "type": "string"
+                },
+                "proxy_config": {
+                    "$ref": "#/definitions/model.ProxyConfig"
+                },
+                "resources": {
+                    "description": "覆盖该账号Worker容器的资源限制",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.WorkerResources"
+                        }
+                    ]
+                },
+                "restore_backup": {
+                    "description": "启动Worker前用该账号最近一次成功的备份恢复会话目录",
+                    "type": "boolean"
+                },
+                "runtime": {
+                    "description": "该账号Worker容器额外的环境变量、挂载卷和DNS",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.WorkerRuntime"
+                        }
+                    ]
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tenant_id": {
+                    "description": "使用租户API Key时由调用方租户决定",
+                    "type": "string"
+                }
+            }
+        },
+        "model.LoginVerifyRequest": {
+            "type": "object",
+            "required": [
+                "action"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "entered",
+                        "resend",
+                        "cancel"
+                    ]
+                }
+            }
+        },
+        "model.MarkInboxReadRequest": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "contact": {
+                    "type": "string"
+                },
+                "message_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "until": {
+                    "description": "只标记该时间之前收到的消息",
+                    "type": "string"
+                }
+            }
+        },
+        "model.MarkInboxReadResult": {
+            "type": "object",
+            "properties": {
+                "marked": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.MediaMessageRequest": {
+            "type": "object",
+            "required": [
+                "account_id",
+                "contact",
+                "media_type"
+            ],
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "caption": {
+                    "type": "string"
+                },
+                "contact": {
+                    "type": "string"
+                },
+                "data": {
+                    "description": "base64 编码的文件内容",
+                    "type": "string"
+                },
+                "filename": {
+                    "type": "string"
+                },
+                "media_type": {
+                    "type": "string",
+                    "enum": [
+                        "image",
+                        "document",
+                        "audio"
+                    ]
+                },
+                "mime_type": {
+                    "type": "string"
+                },
+                "storage_key": {
+                    "description": "对象存储中已上传文件的键，见 POST /storage/objects",
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                },
+                "voice": {
+                    "description": "音频作为语音消息发送",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.Message": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "attempts": {
+                    "description": "出站消息的发送尝试次数",
+                    "type": "integer"
+                },
+                "body": {
+                    "type": "string"
+                },
+                "campaign": {
+                    "type": "string"
+                },
+                "contact": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "description": "出站消息送达对方设备的时间",
+                    "type": "string"
+                },
+                "direction": {
+                    "description": "inbound, outbound",
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "media_size": {
+                    "type": "integer"
+                },
+                "mime_type": {
+                    "type": "string"
+                },
+                "preview": {
+                    "type": "string"
+                },
+                "read_at": {
+                    "description": "入站消息在收件箱中标记已读的时间",
+                    "type": "string"
+                },
+                "seen_at": {
+                    "description": "出站消息被对方阅读的时间",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "sent, delivered, read, failed, retrying, received",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "chat, image, document, audio ...",
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "worker_message_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.MessagePreview": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "contact": {
+                    "type": "string"
+                },
+                "direction": {
+                    "type": "string"
+                },
+                "file_name": {
+                    "type": "string"
+                },
+                "html": {
+                    "description": "已转义的HTML片段",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "media_url": {
+                    "description": "签名的媒体原文件链接",
+                    "type": "string"
+                },
+                "mime_type": {
+                    "type": "string"
+                },
+                "text": {
+                    "description": "纯文本预览",
+                    "type": "string"
+                },
+                "thumbnail_url": {
+                    "description": "签名的缩略图链接（仅图片）",
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "url_expires_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.MessageReceipt": {
+            "type": "object",
+            "required": [
+                "id"
+            ],
+            "properties": {
+                "ack": {
+                    "type": "integer"
+                },
+                "id": {
+                    "description": "Worker消息ID",
+                    "type": "string"
+                }
+            }
+        },
+        "model.MessageRequest": {
+            "type": "object",
+            "required": [
+                "account_id",
+                "contact",
+                "message"
+            ],
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "campaign": {
+                    "description": "营销活动标识，用于统计",
+                    "type": "string"
+                },
+                "contact": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "track_links": {
+                    "description": "是否改写链接以跟踪点击，为空时使用全局配置",
+                    "type": "boolean"
+                },
+                "variables": {
+                    "description": "模板变量，替换消息中的 {{key}}，{{contact}} 始终可用",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.MessageStatus": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "contact": {
+                    "type": "string"
+                },
+                "delivered_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "seen_at": {
+                    "type": "string"
+                },
+                "sent_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "sent, delivered, read, failed, retrying",
+                    "type": "string"
+                },
+                "worker_message_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.MigrationResult": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.MigrationStatus"
+                    }
+                },
+                "rolled_back": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.MigrationStatus"
+                    }
+                },
+                "schema": {
+                    "$ref": "#/definitions/model.SchemaStatus"
+                }
+            }
+        },
+        "model.MigrationRollbackRequest": {
+            "type": "object",
+            "properties": {
+                "steps": {
+                    "description": "回滚的迁移数，默认1",
+                    "type": "integer",
+                    "minimum": 1
+                }
+            }
+        },
+        "model.MigrationStatus": {
+            "type": "object",
+            "properties": {
+                "applied": {
+                    "type": "boolean"
+                },
+                "applied_at": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "reversible": {
+                    "description": "是否可以回滚",
+                    "type": "boolean"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ModerationItem": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "campaign": {
+                    "type": "string"
+                },
+                "contact": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "description": "渲染模板后的正文",
+                    "type": "string"
+                },
+                "message_id": {
+                    "description": "审核通过后发送的消息ID",
+                    "type": "string"
+                },
+                "note": {
+                    "description": "审核备注",
+                    "type": "string"
+                },
+                "reviewed_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "track_links": {
+                    "type": "boolean"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "violations": {
+                    "type": "array",
+                    "items": {
+                        "type": "object"
+                    }
+                }
+            }
+        },
+        "model.OptOut": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "description": "收到退订回复的账号，手动添加时为空",
+                    "type": "string"
+                },
+                "campaign": {
+                    "description": "退订归属的最近一次活动",
+                    "type": "string"
+                },
+                "contact": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "keyword": {
+                    "type": "string"
+                },
+                "message_id": {
+                    "description": "触发退订的入站消息",
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "手动添加的原因",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "keyword, manual",
+                    "type": "string"
+                }
+            }
+        },
+        "model.OwnerSummary": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "description": "operator, team",
+                    "type": "string"
+                },
+                "owner": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PairingCode": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "attempt": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "description": "没有进行中的登录流程时为空",
+                    "type": "string"
+                },
+                "max_attempts": {
+                    "type": "integer"
+                },
+                "phone": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PhoneLoginRequest": {
+            "type": "object",
+            "required": [
+                "login_phone"
+            ],
+            "properties": {
+                "hardware_info": {
+                    "$ref": "#/definitions/model.HardwareInfo"
+                },
+                "hardware_profile_id": {
+                    "description": "新建账号时绑定的硬件指纹配置",
+                    "type": "string"
+                },
+                "is_cache_login": {
+                    "type": "boolean"
+                },
+                "login_phone": {
+                    "type": "string"
+                },
+                "signin_type": {
+                    "description": "30: qr, 40: phone",
+                    "type": "integer"
+                },
+                "socks5": {
+                    "$ref": "#/definitions/model.ProxyConfig"
+                }
+            }
+        },
+        "model.PortAllocation": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "checked_at": {
+                    "description": "最近一次探测到被占用的时间",
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "state": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PortPoolStatus": {
+            "type": "object",
+            "properties": {
+                "allocated": {
+                    "type": "integer"
+                },
+                "available": {
+                    "type": "integer"
+                },
+                "end_port": {
+                    "description": "最后一段的结束端口",
+                    "type": "integer"
+                },
+                "occupied": {
+                    "type": "integer"
+                },
+                "ports": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PortAllocation"
+                    }
+                },
+                "ranges": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.PortRange"
+                    }
+                },
+                "start_port": {
+                    "description": "第一段的起始端口",
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.PortRange": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "integer"
+                },
+                "start": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ProxyConfig": {
+            "type": "object",
+            "properties": {
+                "ip": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer"
+                },
+                "protocol": {
+                    "description": "socks5、socks4、http、https，默认socks5",
+                    "type": "string"
+                },
+                "region": {
+                    "type": "string"
+                },
+                "resource_code": {
+                    "type": "string"
+                },
+                "resource_name": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ProxyDetectResult": {
+            "type": "object",
+            "properties": {
+                "detected": {
+                    "type": "boolean"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.ProxyStatus": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/model.WorkerProxyConfig"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "local_forwarder": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.PullImageRequest": {
+            "type": "object",
+            "properties": {
+                "host_ids": {
+                    "description": "为空时拉取到所有未离线的主机",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "image": {
+                    "description": "为空时使用当前Worker镜像",
+                    "type": "string"
+                },
+                "prune": {
+                    "description": "拉取成功后清理该主机上Worker镜像的旧版本和悬空镜像",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.QRLoginRequest": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "description": "为空时复用空闲Worker或自动生成账号ID",
+                    "type": "string"
+                },
+                "hardware_info": {
+                    "$ref": "#/definitions/model.HardwareInfo"
+                },
+                "hardware_profile_id": {
+                    "description": "新建账号时绑定的硬件指纹配置",
+                    "type": "string"
+                },
+                "is_cache_login": {
+                    "type": "boolean"
+                },
+                "owner": {
+                    "$ref": "#/definitions/model.AccountOwner"
+                },
+                "pool": {
+                    "type": "string"
+                },
+                "socks5": {
+                    "$ref": "#/definitions/model.ProxyConfig"
+                },
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "tenant_id": {
+                    "description": "使用租户API Key时由调用方租户决定",
+                    "type": "string"
+                }
+            }
+        },
+        "model.QRLoginResult": {
+            "type": "object",
+            "properties": {
+                "account": {
+                    "$ref": "#/definitions/model.Account"
+                },
+                "login_result": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "qr_code_url": {
+                    "description": "渲染好的二维码图片地址",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ReconcileReport": {
+            "type": "object",
+            "properties": {
+                "adopted": {
+                    "description": "重新接管容器的账号",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "finished_at": {
+                    "type": "string"
+                },
+                "orphans_removed": {
+                    "description": "删除的无主容器",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "ports_released": {
+                    "description": "容器已不存在、释放端口的账号",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "started_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RegisteredHost": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "description": "Master访问该主机上Worker映射端口使用的地址",
+                    "type": "string"
+                },
+                "agent_url": {
+                    "description": "fleet-agent 地址，本机为空",
+                    "type": "string"
+                },
+                "containers": {
+                    "description": "主机上的Worker容器数",
+                    "type": "integer"
+                },
+                "cordoned": {
+                    "description": "停止调度新账号到该主机",
+                    "type": "boolean"
+                },
+                "cpus": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "load1": {
+                    "description": "1分钟平均负载",
+                    "type": "number"
+                },
+                "max_workers": {
+                    "description": "最多放置的账号数，0表示不限制",
+                    "type": "integer"
+                },
+                "memory_available": {
+                    "description": "字节",
+                    "type": "integer"
+                },
+                "memory_total": {
+                    "description": "字节",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "region": {
+                    "description": "主机的出口地区，调度时与账号的 proxy_region 匹配",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "workers": {
+                    "description": "放置在该主机上的账号数",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ReleaseBanRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "description": "申诉结果等备注",
+                    "type": "string"
+                }
+            }
+        },
+        "model.ReleaseConversationRequest": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "description": "指定时校验当前归属",
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RenameGroupRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ReportReceiptsRequest": {
+            "type": "object",
+            "required": [
+                "receipts"
+            ],
+            "properties": {
+                "receipts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.MessageReceipt"
+                    }
+                }
+            }
+        },
+        "model.ReportReceiptsResult": {
+            "type": "object",
+            "properties": {
+                "updated": {
+                    "description": "状态发生变化的消息数",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.RetryMessagesRequest": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "description": "为空时重试所有账号",
+                    "type": "string"
+                },
+                "limit": {
+                    "description": "本次最多重试条数",
+                    "type": "integer"
+                },
+                "since": {
+                    "description": "失败消息的时间窗口起点",
+                    "type": "string"
+                },
+                "until": {
+                    "description": "失败消息的时间窗口终点",
+                    "type": "string"
+                }
+            }
+        },
+        "model.RetryMessagesResult": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "type": "string"
+                },
+                "message_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "queued": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ReviewApprovalRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "maxLength": 1000
+                }
+            }
+        },
+        "model.ReviewModerationRequest": {
+            "type": "object",
+            "properties": {
+                "note": {
+                    "type": "string",
+                    "maxLength": 1000
+                }
+            }
+        },
+        "model.RunBackupRequest": {
+            "type": "object",
+            "properties": {
+                "account_ids": {
+                    "description": "为空时备份所有已登录的账号",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.SLAOutage": {
+            "type": "object",
+            "properties": {
+                "duration_seconds": {
+                    "type": "integer"
+                },
+                "end": {
+                    "description": "故障仍在持续时为空",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "start": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "故障开始时的状态",
+                    "type": "string"
+                }
+            }
+        },
+        "model.SLAWindow": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "description": "在线时间占比（百分比），窗口内没有可统计的时间时为null",
+                    "type": "number"
+                },
+                "downtime_seconds": {
+                    "type": "integer"
+                },
+                "outages": {
+                    "description": "与窗口有重叠的故障次数",
+                    "type": "integer"
+                },
+                "uptime_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.SchemaStatus": {
+            "type": "object",
+            "properties": {
+                "current_version": {
+                    "description": "数据库已执行的最高版本",
+                    "type": "integer"
+                },
+                "latest_version": {
+                    "description": "当前程序包含的最高版本",
+                    "type": "integer"
+                },
+                "migrations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.MigrationStatus"
+                    }
+                },
+                "pending": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.SendPreview": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "account_status": {
+                    "type": "string"
+                },
+                "campaign": {
+                    "type": "string"
+                },
+                "contact": {
+                    "description": "规范化后的收件人",
+                    "type": "string"
+                },
+                "content_violations": {
+                    "description": "违反的内容策略，真实发送时会被拒绝或放入审核队列",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ContentViolation"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "message": {
+                    "description": "渲染模板后的正文，链接尚未改写",
+                    "type": "string"
+                },
+                "quota": {
+                    "description": "不含本次发送的额度",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.SendQuota"
+                        }
+                    ]
+                },
+                "tracked_links": {
+                    "description": "发送时会改写为跟踪地址的链接",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "unresolved_variables": {
+                    "description": "没有对应变量、会原样发送的占位符",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.SendQuota": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "daily_quota": {
+                    "type": "integer"
+                },
+                "quota_remaining": {
+                    "type": "integer"
+                },
+                "quota_reset": {
+                    "type": "string"
+                },
+                "rate_limit": {
+                    "type": "integer"
+                },
+                "rate_remaining": {
+                    "type": "integer"
+                },
+                "rate_reset": {
+                    "type": "string"
+                },
+                "warmup_limit": {
+                    "description": "预热阶段的当日上限",
+                    "type": "integer"
+                },
+                "warmup_remaining": {
+                    "type": "integer"
+                },
+                "warning": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.SessionBackup": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "destination": {
+                    "description": "不含凭证的备份目的地",
+                    "type": "string"
+                },
                 "error": {
                     "type": "string"
                 },
-                "message": {
+                "host_id": {
+                    "description": "备份时Worker所在主机",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "description": "目的地中的备份文件，如 账号ID/20240101T030000Z.tar.gz",
+                    "type": "string"
+                },
+                "sha256": {
+                    "description": "恢复时校验",
+                    "type": "string"
+                },
+                "size_bytes": {
+                    "description": "压缩后的大小",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "succeeded, failed",
+                    "type": "string"
+                },
+                "trigger": {
+                    "description": "scheduled, manual",
+                    "type": "string"
+                }
+            }
+        },
+        "model.SessionHealth": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "avg_session_hours": {
+                    "type": "number"
+                },
+                "expected_expiry_at": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "relogin_recommended": {
+                    "type": "boolean"
+                },
+                "session_age_hours": {
+                    "type": "number"
+                },
+                "session_drops": {
+                    "type": "integer"
+                },
+                "session_refresh_at": {
+                    "type": "string"
+                },
+                "session_started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.SetAccountProxyRequest": {
+            "type": "object",
+            "properties": {
+                "apply": {
+                    "description": "立即切换运行中Worker的代理，会重启WhatsApp客户端",
+                    "type": "boolean"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer",
+                    "maximum": 65535,
+                    "minimum": 1
+                },
+                "protocol": {
+                    "type": "string",
+                    "enum": [
+                        "socks5",
+                        "socks4",
+                        "http",
+                        "https"
+                    ]
+                },
+                "region": {
+                    "description": "同时更新 proxy_region",
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.SetKeepAliveRequest": {
+            "type": "object",
+            "required": [
+                "enabled"
+            ],
+            "properties": {
+                "enabled": {
+                    "description": "false表示不参与会话保活和主动刷新",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.SetTypingSimulationRequest": {
+            "type": "object",
+            "required": [
+                "enabled"
+            ],
+            "properties": {
+                "enabled": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.SetWarmupRequest": {
+            "type": "object",
+            "properties": {
+                "profile": {
+                    "description": "方案名，空字符串表示默认方案，off表示不预热",
+                    "type": "string"
+                },
+                "restart": {
+                    "description": "从今天起重新开始预热",
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.StatsBucket": {
+            "type": "object",
+            "properties": {
+                "availability": {
+                    "description": "24h, 7d, 30d，由分组内账号的在线和故障时间合计",
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/model.SLAWindow"
+                    }
+                },
+                "loggedInWorkers": {
+                    "type": "integer"
+                },
+                "messagesReceived": {
+                    "type": "integer"
+                },
+                "messagesSent": {
+                    "type": "integer"
+                },
+                "onlineWorkers": {
+                    "type": "integer"
+                },
+                "totalWorkers": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.StatsPoint": {
+            "type": "object",
+            "properties": {
+                "accounts": {
+                    "description": "该周期内有统计数据的账号数",
+                    "type": "integer"
+                },
+                "failed": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "period": {
+                    "description": "day: 2006-01-02，week: 周一日期，month: 2006-01",
+                    "type": "string"
+                },
+                "received": {
+                    "type": "integer"
+                },
+                "sent": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "uptime_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.StatusTransition": {
+            "type": "object",
+            "properties": {
+                "account_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "details": {
+                    "description": "附加信息，如Worker启动失败时的诊断",
+                    "type": "object"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "previous": {
+                    "type": "string"
+                },
+                "reason": {
+                    "description": "触发原因，如启动失败的错误信息",
+                    "type": "string"
+                },
+                "request_id": {
+                    "description": "由API请求触发时的请求ID",
+                    "type": "string"
+                },
+                "source": {
+                    "description": "触发来源：api、worker、supervisor、reconcile、shutdown、chaos、breaker、ban_detector",
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.StoredObject": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "description": "下载地址过期时间",
+                    "type": "string"
+                },
+                "key": {
+                    "description": "对象键，发送媒体和导入联系人时通过 storage_key 引用",
+                    "type": "string"
+                },
+                "size": {
+                    "description": "字节数，只在写入时返回",
+                    "type": "integer"
+                },
+                "url": {
+                    "description": "签名下载地址，无需API凭证即可访问",
+                    "type": "string"
+                }
+            }
+        },
+        "model.StringMap": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "model.SuppressionStatus": {
+            "type": "object",
+            "properties": {
+                "contact": {
+                    "type": "string"
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.OptOut"
+                    }
+                },
+                "suppressed": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "model.SwitchProxyRequest": {
+            "type": "object",
+            "required": [
+                "port"
+            ],
+            "properties": {
+                "host": {
+                    "type": "string"
+                },
+                "ip": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "port": {
+                    "type": "integer",
+                    "maximum": 65535,
+                    "minimum": 1
+                },
+                "protocol": {
+                    "description": "默认socks5",
+                    "type": "string",
+                    "enum": [
+                        "socks5",
+                        "socks4",
+                        "http",
+                        "https"
+                    ]
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.SystemInfo": {
+            "type": "object",
+            "properties": {
+                "environment": {
+                    "type": "string"
+                },
+                "go_version": {
+                    "type": "string"
+                },
+                "goroutines": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "string"
+                },
+                "worker_mode": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.Tenant": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max_messages_per_day": {
+                    "description": "所有账号每日发送总数上限，0表示不限制",
+                    "type": "integer"
+                },
+                "max_workers": {
+                    "description": "最多可创建的账号数，0表示不限制",
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "usage": {
+                    "$ref": "#/definitions/model.TenantUsage"
+                }
+            }
+        },
+        "model.TenantAPIKey": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "string"
                 },
-                "success": {
-                    "type": "boolean"
+                "last_used_at": {
+                    "description": "最近使用时间，每分钟最多更新一次",
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner": {
+                    "description": "绑定的运营人员或团队，开启 API_KEY_OWNER_ENFORCEMENT 后只能通过其名下账号发送",
+                    "type": "string"
+                },
+                "prefix": {
+                    "description": "Key的前几位，用于识别",
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "string"
+                },
+                "tenant_id": {
+                    "type": "string"
                 }
             }
         },
-        "model.AddContactRequest": {
+        "model.TenantUsage": {
+            "type": "object",
+            "properties": {
+                "messages_today": {
+                    "type": "integer"
+                },
+                "workers": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.TypingRequest": {
             "type": "object",
             "required": [
-                "phone"
+                "contact"
             ],
             "properties": {
-                "firstName": {
+                "contact": {
                     "type": "string"
                 },
-                "lastName": {
-                    "type": "string"
+                "duration_ms": {
+                    "description": "大于0时到时自动清除，否则保持到发送消息或WhatsApp超时",
+                    "type": "integer",
+                    "maximum": 60000,
+                    "minimum": 0
                 },
-                "phone": {
+                "state": {
+                    "description": "默认typing，paused清除状态",
+                    "type": "string",
+                    "enum": [
+                        "typing",
+                        "recording",
+                        "paused"
+                    ]
+                }
+            }
+        },
+        "model.UpdateAccountRequest": {
+            "type": "object",
+            "properties": {
+                "hardware_profile_id": {
+                    "description": "绑定硬件指纹配置，空字符串解除绑定；下次启动Worker或登录时生效",
                     "type": "string"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                },
+                "notes": {
+                    "description": "空字符串清除备注",
+                    "type": "string",
+                    "maxLength": 2000
+                },
+                "owner": {
+                    "description": "替换负责人信息",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.AccountOwner"
+                        }
+                    ]
+                },
+                "status_poll_seconds": {
+                    "description": "账号稳定时的状态轮询间隔（秒），0恢复全局配置",
+                    "type": "integer",
+                    "maximum": 86400
+                },
+                "tags": {
+                    "description": "替换全部标签，空数组清除标签",
+                    "type": "array",
+                    "maxItems": 50,
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         },
-        "model.HardwareInfo": {
+        "model.UpdateHostRequest": {
             "type": "object",
             "properties": {
-                "browser": {
+                "address": {
                     "type": "string"
                 },
-                "os": {
+                "agent_url": {
+                    "type": "string"
+                },
+                "cordoned": {
+                    "type": "boolean"
+                },
+                "max_workers": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "region": {
+                    "description": "只影响之后的调度，已放置的账号不会迁移",
                     "type": "string"
                 }
             }
         },
-        "model.LoginRequest": {
+        "model.UpdateTenantRequest": {
+            "type": "object",
+            "properties": {
+                "max_messages_per_day": {
+                    "type": "integer"
+                },
+                "max_workers": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.UpdateWebhookRequest": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "filter": {
+                    "description": "替换全部过滤条件",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.WebhookFilter"
+                        }
+                    ]
+                },
+                "include_media": {
+                    "type": "boolean"
+                },
+                "retry": {
+                    "description": "替换重试策略",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.WebhookRetryPolicy"
+                        }
+                    ]
+                }
+            }
+        },
+        "model.UpgradeWorkersRequest": {
             "type": "object",
             "required": [
-                "account_id"
+                "image"
             ],
+            "properties": {
+                "batch_size": {
+                    "description": "每批升级的Worker数量，默认 WORKER_UPGRADE_BATCH_SIZE",
+                    "type": "integer"
+                },
+                "image": {
+                    "description": "新镜像，如 whatsapp-node-service:1.4.0",
+                    "type": "string"
+                },
+                "settle_seconds": {
+                    "description": "每批启动后等待多久再做健康检查，默认 WORKER_UPGRADE_SETTLE_SECONDS",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.WarmupStatus": {
+            "type": "object",
             "properties": {
                 "account_id": {
                     "type": "string"
                 },
-                "cache_login": {
+                "age_days": {
+                    "description": "从0开始的预热天数",
+                    "type": "integer"
+                },
+                "completed": {
+                    "description": "已进入不限制的阶段",
                     "type": "boolean"
                 },
-                "hardware_info": {
-                    "type": "object",
-                    "additionalProperties": true
+                "daily_limit": {
+                    "description": "当日允许发送条数，0表示不限制",
+                    "type": "integer"
                 },
-                "login_method": {
-                    "description": "qr, phone",
+                "enabled": {
+                    "description": "预热是否对该账号生效",
+                    "type": "boolean"
+                },
+                "next_limit": {
+                    "description": "下一阶段的每日上限，0表示不限制",
+                    "type": "integer"
+                },
+                "next_step_at": {
                     "type": "string"
                 },
-                "phone": {
+                "profile": {
                     "type": "string"
                 },
-                "proxy_config": {
-                    "$ref": "#/definitions/model.ProxyConfig"
+                "remaining": {
+                    "type": "integer"
+                },
+                "sent_today": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.WarmupStep"
+                    }
                 }
             }
         },
-        "model.MessageRequest": {
+        "model.WarmupStep": {
+            "type": "object",
+            "properties": {
+                "from_day": {
+                    "type": "integer"
+                },
+                "per_day": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.Webhook": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "events": {
+                    "description": "订阅的事件类型，为空表示所有事件",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "filter": {
+                    "description": "投递前的过滤条件",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.WebhookFilter"
+                        }
+                    ]
+                },
+                "id": {
+                    "type": "string"
+                },
+                "include_media": {
+                    "description": "消息事件附带签名的媒体链接",
+                    "type": "boolean"
+                },
+                "retry": {
+                    "description": "投递失败时的重试策略",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.WebhookRetryPolicy"
+                        }
+                    ]
+                },
+                "tenant_id": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.WebhookFilter": {
+            "type": "object",
+            "properties": {
+                "account_ids": {
+                    "description": "只投递这些账号的事件",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "contacts": {
+                    "description": "联系人匹配任一模式，* 匹配任意字符，如 86138*",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "directions": {
+                    "description": "inbound、outbound",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "keywords": {
+                    "description": "消息内容包含任一关键词（不区分大小写）",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.WebhookRetryPolicy": {
+            "type": "object",
+            "properties": {
+                "backoff_ms": {
+                    "description": "首次重试前的等待，之后每次翻倍，默认1000",
+                    "type": "integer"
+                },
+                "max_attempts": {
+                    "description": "最多尝试次数，默认 WEBHOOK_MAX_ATTEMPTS",
+                    "type": "integer"
+                },
+                "max_backoff_ms": {
+                    "description": "单次等待的上限，默认不限制",
+                    "type": "integer"
+                }
+            }
+        },
+        "model.WorkerCall": {
             "type": "object",
-            "required": [
-                "account_id",
-                "contact",
-                "message"
-            ],
             "properties": {
                 "account_id": {
                     "type": "string"
                 },
-                "contact": {
+                "created_at": {
                     "type": "string"
                 },
-                "message": {
+                "error": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "latency_ms": {
+                    "type": "integer"
+                },
+                "method": {
+                    "type": "string"
+                },
+                "path": {
+                    "description": "Worker接口路径，如 /api/send-message",
+                    "type": "string"
+                },
+                "payload_hash": {
+                    "description": "请求体前64KiB的SHA-256，相同请求的哈希相同",
+                    "type": "string"
+                },
+                "payload_size": {
+                    "description": "请求体字节数",
+                    "type": "integer"
+                },
+                "request_id": {
+                    "type": "string"
+                },
+                "source": {
+                    "description": "proxy：代理转发的API请求；master：Master发起的调用，如发送消息和登录",
                     "type": "string"
+                },
+                "status": {
+                    "description": "Worker响应状态码，未连上Worker时为0",
+                    "type": "integer"
                 }
             }
         },
-        "model.PhoneLoginRequest": {
+        "model.WorkerContact": {
             "type": "object",
-            "required": [
-                "login_phone"
-            ],
             "properties": {
-                "hardware_info": {
-                    "$ref": "#/definitions/model.HardwareInfo"
+                "id": {
+                    "description": "WhatsApp ID，如 8613800000000@c.us",
+                    "type": "string"
                 },
-                "is_cache_login": {
+                "isGroup": {
                     "type": "boolean"
                 },
-                "login_phone": {
+                "isMyContact": {
+                    "type": "boolean"
+                },
+                "name": {
                     "type": "string"
                 },
-                "signin_type": {
-                    "description": "30: qr, 40: phone",
-                    "type": "integer"
+                "number": {
+                    "type": "string"
                 },
-                "socks5": {
-                    "$ref": "#/definitions/model.ProxyConfig"
+                "pushname": {
+                    "type": "string"
                 }
             }
         },
-        "model.ProxyConfig": {
+        "model.WorkerProxyConfig": {
             "type": "object",
             "properties": {
                 "ip": {
                     "type": "string"
                 },
-                "password": {
+                "port": {
                     "type": "string"
                 },
-                "port": {
+                "pwd": {
+                    "type": "string"
+                },
+                "scheme": {
+                    "type": "string"
+                },
+                "user": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.WorkerResources": {
+            "type": "object",
+            "properties": {
+                "cpus": {
+                    "description": "CPU核数上限，如 1.5",
+                    "type": "string"
+                },
+                "memory": {
+                    "description": "内存上限，如 512m、1g",
+                    "type": "string"
+                },
+                "pids_limit": {
+                    "description": "进程数上限",
                     "type": "integer"
                 },
-                "region": {
+                "restart_policy": {
+                    "description": "no、always、unless-stopped、on-failure[:N]",
                     "type": "string"
+                }
+            }
+        },
+        "model.WorkerRuntime": {
+            "type": "object",
+            "properties": {
+                "dns": {
+                    "description": "DNS服务器，设置后替换全局设置",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "resource_code": {
+                "env": {
+                    "description": "环境变量，如 TZ、LANG，同名时覆盖全局设置",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/model.StringMap"
+                        }
+                    ]
+                },
+                "volumes": {
+                    "description": "docker -v 格式：宿主机路径或卷名:容器路径[:ro]，同一容器路径时覆盖全局设置",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "model.WorkerStartupDiagnostics": {
+            "type": "object",
+            "properties": {
+                "container": {
                     "type": "string"
                 },
-                "resource_name": {
+                "errors": {
+                    "description": "采集诊断时的错误",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "exit_code": {
+                    "description": "容器已退出时的退出码",
+                    "type": "integer"
+                },
+                "host_id": {
                     "type": "string"
                 },
-                "username": {
+                "inspect": {
+                    "description": "docker inspect 输出",
+                    "type": "object"
+                },
+                "logs": {
+                    "description": "容器最近的日志",
                     "type": "string"
+                },
+                "oom_killed": {
+                    "type": "boolean"
+                },
+                "state": {
+                    "description": "容器状态：running、exited、restarting等",
+                    "type": "string"
+                },
+                "state_error": {
+                    "description": "docker记录的启动错误",
+                    "type": "string"
+                },
+                "timeout_seconds": {
+                    "type": "integer"
                 }
             }
         }
@@ -0,0 +1,78 @@
+// Package backup 会话备份的存储目的地：本地目录、S3兼容对象存储（由 storage 包实现）和SFTP服务器。
+// 备份按键（如 账号ID/时间.tar.gz）整体写入和读取，列表和保留策略由调用方的备份记录维护
+package backup
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"whatsapp-aggregator/internal/storage"
+)
+
+// Config 备份目的地配置
+type Config struct {
+	Destination string // 本地目录、s3://bucket/prefix 或 sftp://user@host:port/path
+
+	S3Endpoint        string // S3兼容服务地址，为空时使用AWS区域地址
+	S3Region          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+
+	SFTPKeyFile        string // SFTP登录使用的私钥
+	SFTPKnownHostsFile string // 校验服务器主机密钥，为空时首次连接自动信任
+}
+
+// Store 备份存储
+type Store interface {
+	// Put 写入备份，已存在时覆盖
+	Put(ctx context.Context, key string, data []byte) error
+	// Get 读取备份
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete 删除备份，不存在时不报错
+	Delete(ctx context.Context, key string) error
+	// String 不含凭证的目的地描述，记录在备份记录上
+	String() string
+}
+
+// Open 按 Destination 的协议创建存储，没有协议时视为本地目录
+func Open(cfg Config) (Store, error) {
+	dest := strings.TrimSpace(cfg.Destination)
+	if dest == "" {
+		return nil, fmt.Errorf("backup destination is empty")
+	}
+	if !strings.Contains(dest, "://") {
+		return openStorage(cfg)
+	}
+
+	u, err := url.Parse(dest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backup destination: %v", err)
+	}
+	switch u.Scheme {
+	case "file", "s3":
+		return openStorage(cfg)
+	case "sftp":
+		return newSFTPStore(u, cfg)
+	default:
+		return nil, fmt.Errorf("unsupported backup destination scheme %q, expected a directory, s3:// or sftp://", u.Scheme)
+	}
+}
+
+// openStorage 本地目录和S3使用对象存储的实现
+func openStorage(cfg Config) (Store, error) {
+	return storage.Open(storage.Config{
+		Destination:       cfg.Destination,
+		S3Endpoint:        cfg.S3Endpoint,
+		S3Region:          cfg.S3Region,
+		S3AccessKeyID:     cfg.S3AccessKeyID,
+		S3SecretAccessKey: cfg.S3SecretAccessKey,
+		EnvPrefix:         "BACKUP",
+	})
+}
+
+// validKey 键只能包含相对路径，不能跳出目的地
+func validKey(key string) error {
+	return storage.ValidKey(key)
+}
@@ -0,0 +1,153 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+)
+
+// sftpStore 通过 OpenSSH 的 sftp 命令以批处理模式把备份上传到SFTP服务器，只支持密钥登录
+type sftpStore struct {
+	target     string // user@host
+	port       string
+	dir        string
+	keyFile    string
+	knownHosts string
+}
+
+func newSFTPStore(u *url.URL, cfg Config) (*sftpStore, error) {
+	if u.Hostname() == "" {
+		return nil, fmt.Errorf("sftp backup destination needs a host, e.g. sftp://user@host:22/backups")
+	}
+	if cfg.SFTPKeyFile == "" {
+		return nil, fmt.Errorf("BACKUP_SFTP_KEY_FILE is required for sftp backups")
+	}
+	if _, err := exec.LookPath("sftp"); err != nil {
+		return nil, fmt.Errorf("sftp backups need the OpenSSH sftp client: %v", err)
+	}
+
+	target := u.Hostname()
+	if u.User != nil && u.User.Username() != "" {
+		target = u.User.Username() + "@" + target
+	}
+	port := u.Port()
+	if port == "" {
+		port = "22"
+	}
+	dir := strings.TrimRight(u.Path, "/")
+	if dir == "" {
+		dir = "."
+	}
+	return &sftpStore{target: target, port: port, dir: dir, keyFile: cfg.SFTPKeyFile, knownHosts: cfg.SFTPKnownHostsFile}, nil
+}
+
+// Put 上传到临时文件后重命名，中断时不会留下不完整的备份
+func (s *sftpStore) Put(ctx context.Context, key string, data []byte) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	local, err := os.CreateTemp("", "session-backup-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file: %v", err)
+	}
+	defer os.Remove(local.Name())
+	_, err = local.Write(data)
+	if closeErr := local.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write temp file: %v", err)
+	}
+
+	remote := path.Join(s.dir, key)
+	var batch strings.Builder
+	// 以 - 开头的命令失败时继续执行，目录已存在时mkdir会失败
+	dir := s.dir
+	for _, part := range strings.Split(path.Dir(key), "/") {
+		if part == "." {
+			continue
+		}
+		dir = path.Join(dir, part)
+		fmt.Fprintf(&batch, "-mkdir %s\n", quoteSFTP(dir))
+	}
+	fmt.Fprintf(&batch, "put %s %s\n", quoteSFTP(local.Name()), quoteSFTP(remote+".tmp"))
+	fmt.Fprintf(&batch, "-rm %s\n", quoteSFTP(remote))
+	fmt.Fprintf(&batch, "rename %s %s\n", quoteSFTP(remote+".tmp"), quoteSFTP(remote))
+	if err := s.run(ctx, batch.String()); err != nil {
+		return fmt.Errorf("failed to upload backup: %v", err)
+	}
+	return nil
+}
+
+func (s *sftpStore) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+	local, err := os.CreateTemp("", "session-backup-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %v", err)
+	}
+	local.Close()
+	defer os.Remove(local.Name())
+
+	batch := fmt.Sprintf("get %s %s\n", quoteSFTP(path.Join(s.dir, key)), quoteSFTP(local.Name()))
+	if err := s.run(ctx, batch); err != nil {
+		return nil, fmt.Errorf("failed to download backup: %v", err)
+	}
+	data, err := os.ReadFile(local.Name())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read downloaded backup: %v", err)
+	}
+	return data, nil
+}
+
+func (s *sftpStore) Delete(ctx context.Context, key string) error {
+	if err := validKey(key); err != nil {
+		return err
+	}
+	batch := fmt.Sprintf("-rm %s\n", quoteSFTP(path.Join(s.dir, key)))
+	if err := s.run(ctx, batch); err != nil {
+		return fmt.Errorf("failed to delete backup: %v", err)
+	}
+	return nil
+}
+
+func (s *sftpStore) String() string {
+	return fmt.Sprintf("sftp://%s:%s%s", s.target, s.port, s.dir)
+}
+
+// run 以批处理模式执行sftp命令，任一非 - 开头的命令失败时返回错误
+func (s *sftpStore) run(ctx context.Context, batch string) error {
+	args := []string{
+		"-b", "-",
+		"-P", s.port,
+		"-i", s.keyFile,
+		"-o", "BatchMode=yes",
+		"-o", "IdentitiesOnly=yes",
+	}
+	if s.knownHosts != "" {
+		args = append(args, "-o", "StrictHostKeyChecking=yes", "-o", "UserKnownHostsFile="+s.knownHosts)
+	} else {
+		args = append(args, "-o", "StrictHostKeyChecking=accept-new")
+	}
+	args = append(args, s.target)
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "sftp", args...)
+	cmd.Stdin = strings.NewReader(batch)
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("%v, output: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// quoteSFTP 为sftp批处理命令的参数加引号
+func quoteSFTP(arg string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(arg) + `"`
+}
@@ -1,21 +1,75 @@
 package config
 
 import (
+	"maps"
 	"os"
+	"path/filepath"
+	"slices"
+	"sort"
 	"strconv"
+	"strings"
 )
 
+// defaultWorkerHealthCmd Worker镜像中没有curl，使用node请求 /api/health
+const defaultWorkerHealthCmd = `node -e "fetch('http://127.0.0.1:'+(process.env.PORT||4000)+'/api/health').then(r=>process.exit(r.ok?0:1),()=>process.exit(1))"`
+
 // Config 应用配置
 type Config struct {
-	Server ServerConfig
-	Worker WorkerConfig
-	DB     DBConfig
+	Server      ServerConfig
+	Worker      WorkerConfig
+	DB          DBConfig
+	Bulk        BulkConfig
+	Media       MediaConfig
+	Tracking    TrackingConfig
+	Retry       RetryConfig
+	DeadLetter  DeadLetterConfig
+	Quota       QuotaConfig
+	RateLimit   RateLimitConfig
+	Warmup      WarmupConfig
+	Session     SessionConfig
+	QRLogin     QRLoginConfig
+	PhoneLogin  PhoneLoginConfig
+	Contact     ContactConfig
+	Inbox       InboxConfig
+	AutoReply   AutoReplyConfig
+	StatusPoll  StatusPollConfig
+	Receipt     ReceiptConfig
+	Webhook     WebhookConfig
+	Group       GroupConfig
+	Supervisor  SupervisorConfig
+	Upgrade     UpgradeConfig
+	Alert       AlertConfig
+	Janitor     JanitorConfig
+	Diagnostics DiagnosticsConfig
+	Tenant      TenantConfig
+	Audit       AuditConfig
+	Idempotency IdempotencyConfig
+	Health      HealthConfig
+	Stats       StatsConfig
+	Scheduler   SchedulerConfig
+	Chaos       ChaosConfig
+	Proxy       ProxyConfig
+	Log         LogConfig
+	Tracing     TracingConfig
+	Secrets     SecretsConfig
+	SessionSeal SessionSealConfig
+	Hooks       HooksConfig
+	Typing      TypingConfig
+	Leader      LeaderConfig
+	Ban         BanConfig
+	WorkerHTTP  WorkerHTTPConfig
+	Backup      BackupConfig
+	Storage     StorageConfig
 }
 
 // ServerConfig 服务器配置
 type ServerConfig struct {
-	Host string
-	Port int
+	Host            string
+	Port            int
+	ShutdownTimeout int    // 优雅关闭的最长等待时间（秒）
+	Environment     string // 运行环境名称，在健康检查中展示
+
+	ConfigReloadSeconds int // 从数据库重新加载配置项的间隔（秒），0表示只在启动时加载
 }
 
 // WorkerConfig Worker运行模式配置
@@ -26,20 +80,462 @@ type WorkerConfig struct {
 	BasePort  int    // for local/docker
 	PortRange int    // for local/docker
 	Namespace string // for k8s
+
+	PortRanges string // 多段Worker端口，如 4000-4999,6000-6499，配置后代替 BasePort/PortRange 分配宿主机端口
+
+	StopOnShutdown bool   // 关闭Master时是否同时停止Worker，默认保持Worker运行
+	SessionDir     string // Worker会话目录在宿主机上的路径，按账号ID分子目录挂载
+	MasterURL      string // Worker回调Master的地址，为空时使用 host.docker.internal 和服务端口
+
+	ProvisionConcurrency int // 批量创建账号时最多同时启动的Worker数
+
+	// Worker容器默认资源限制，账号可在创建时单独覆盖，为空或0表示不限制
+	Memory        string // docker --memory，如 1g
+	CPUs          string // docker --cpus，如 1.5
+	PidsLimit     int    // docker --pids-limit
+	RestartPolicy string // docker --restart：no、always、unless-stopped、on-failure[:N]
+
+	// Worker容器额外的运行参数，账号可在创建时追加或覆盖
+	Env     []string // 环境变量，KEY=VALUE
+	Volumes []string // 挂载卷，docker -v 格式
+	DNS     []string // DNS服务器
+
+	// Worker容器的Docker健康检查，Supervisor据此重启HTTP仍可访问但已不健康的Worker
+	HealthCmd         string // docker --health-cmd，none表示不设置健康检查
+	HealthInterval    int    // 健康检查间隔（秒）
+	HealthTimeout     int    // 单次检查超时（秒）
+	HealthRetries     int    // 连续失败多少次后标记为unhealthy
+	HealthStartPeriod int    // 容器启动后的宽限期（秒），期间失败不计数
+
+	// Worker通过 /api/status 上报的API版本需在此范围内，VersionCheck为block时拒绝启动不兼容的Worker
+	APIVersionMin int
+	APIVersionMax int
+	VersionCheck  string // warn、block 或 off
+
+	MockLoginDelayMs int // WORKER_MODE=mock 时模拟Worker从发起登录到登录成功的时间（毫秒）
+
+	ReadyTimeoutSeconds int // 启动容器后等待Worker就绪的时间（秒）
+	StartupLogLines     int // Worker未能就绪时随诊断信息采集的日志行数
 }
 
 // DBConfig 数据库配置
 type DBConfig struct {
-	Type string
-	Name string
+	Type     string // sqlite, postgres, mysql
+	Name     string // sqlite为文件路径，postgres/mysql为数据库名
+	DSN      string `json:"-"` // 完整连接串，设置后忽略Host等字段
+	Host     string
+	Port     int
+	User     string
+	Password string `json:"-"`
+	SSLMode  string // postgres
+
+	MaxOpenConns    int
+	MaxIdleConns    int
+	ConnMaxLifetime int // 连接最长复用时间（分钟）
+
+	AutoMigrate bool // 启动时执行待执行的迁移，关闭时需通过 migrate up 手动执行
+}
+
+// BulkConfig 批量发送配置
+type BulkConfig struct {
+	Concurrency int // 默认全局并发发送数
+	IntervalMs  int // 默认同一账号发送间隔（毫秒）
+
+	ApprovalThreshold  int // 收件人数超过该值的活动和批量发送需要审批，0表示不需要
+	ApprovalSampleSize int // 审批时展示的示例消息数
+
+	BatchRetentionHours int // 已结束的批次在内存中保留的小时数
+}
+
+// MediaConfig 媒体消息配置
+type MediaConfig struct {
+	Dir           string // 媒体存储目录
+	MaxSizeMB     int    // 单个媒体文件大小上限
+	RetentionDays int    // 已发送媒体的保留天数，由清理任务删除过期文件，0表示永久保留
+
+	AllowPrivateURLs bool // 是否允许从回环、私有和链路本地地址下载媒体URL
+
+	BaseURL       string // 媒体签名链接的访问地址前缀
+	SigningKey    string `json:"-"` // 媒体链接签名密钥，为空时启动时随机生成
+	URLTTLMinutes int    // 媒体签名链接有效期（分钟）
+}
+
+// TrackingConfig 链接点击跟踪配置
+type TrackingConfig struct {
+	Enabled bool   // 默认是否改写出站消息中的链接
+	BaseURL string // 跳转地址前缀，需能被消息接收方访问
+}
+
+// RetryConfig 发送失败重试配置
+type RetryConfig struct {
+	MaxAttempts  int // 单条消息最多尝试次数（含首次）
+	BackoffMs    int // 首次重试前等待时间，之后指数递增
+	MaxBackoffMs int // 重试等待时间上限
+}
+
+// DeadLetterConfig 发送失败死信队列配置
+type DeadLetterConfig struct {
+	Enabled           bool // Worker不可达或代理错误导致发送失败时是否写入死信队列
+	AutoRetry         bool // 是否按退避策略自动重试
+	MaxRetries        int  // 自动重试次数上限，之后只能手动重试
+	BackoffSeconds    int  // 首次自动重试前等待时间，之后指数递增
+	MaxBackoffSeconds int  // 自动重试等待时间上限
+	RetentionDays     int  // 重试成功的死信保留天数，0表示永久保留
+}
+
+// QuotaConfig 账号发送限流与配额配置
+type QuotaConfig struct {
+	RatePerMinute int     // 每个账号每分钟最多发送条数，0表示不限制
+	DailyQuota    int     // 每个账号每日最多发送条数，0表示不限制
+	WarnRatio     float64 // 剩余额度低于上限的该比例时在响应中附带警告
+}
+
+// RateLimitConfig 发送接口的令牌桶限流配置，速率为0表示不限制
+type RateLimitConfig struct {
+	GlobalPerMinute  int // 所有账号合计每分钟允许的发送请求数
+	GlobalBurst      int // 全局突发容量，0表示等于每分钟速率
+	AccountPerMinute int // 每个账号默认每分钟允许的发送请求数，可按账号覆盖
+	AccountBurst     int // 账号默认突发容量，0表示等于每分钟速率
+}
+
+// WarmupConfig 新账号预热配置：按账号使用天数逐步放开每日发送量
+type WarmupConfig struct {
+	Enabled        bool                    // 是否在发送时执行预热限额
+	DefaultProfile string                  // 账号未指定预热方案时使用的方案
+	Profiles       map[string][]WarmupStep // 方案名 -> 按起始天数升序的阶段
+}
+
+// WarmupStep 预热阶段：从账号第 FromDay 天（从0开始）起每天最多发送 PerDay 条，0表示不限制
+type WarmupStep struct {
+	FromDay int `json:"from_day"`
+	PerDay  int `json:"per_day"`
+}
+
+// SessionConfig 登录会话过期预测与主动刷新配置
+type SessionConfig struct {
+	MaxAgeHours     int     // 会话最长预期存活时间，超过后建议重新登录
+	ExpiryRatio     float64 // 会话时长达到历史平均时长的比例时建议重新登录
+	RefreshEnabled  bool    // 是否在静默时段主动刷新即将过期的会话
+	QuietHourStart  int     // 静默时段开始（本地时间，小时）
+	QuietHourEnd    int     // 静默时段结束（本地时间，小时，不含）
+	RefreshInterval int     // 主动刷新任务检查间隔（分钟）
+
+	KeepAliveInterval        int // 保活任务检查已登录Worker登录状态的间隔（秒），0表示关闭
+	KeepAliveRefreshCooldown int // 保活任务对同一账号两次刷新会话的最小间隔（分钟）
+}
+
+// QRLoginConfig 扫码登录配置
+type QRLoginConfig struct {
+	PollSeconds    int // 等待扫码期间轮询Worker状态的间隔（秒）
+	TimeoutSeconds int // 超过该时间仍未登录则停止轮询（秒）
+}
+
+// PhoneLoginConfig 手机号配对码登录流程配置
+type PhoneLoginConfig struct {
+	PollSeconds        int // 登录流程中轮询Worker登录状态的间隔（秒）
+	CodeTimeoutSeconds int // 配对码的有效时间（秒），过期仍未登录则请求新的配对码
+	MaxAttempts        int // 最多请求配对码的次数，用完后登录流程失败
+	TimeoutSeconds     int // 整个登录流程的超时时间（秒）
+}
+
+// ContactConfig 联系人同步与收件人校验配置
+type ContactConfig struct {
+	SyncEnabled  bool   // 是否定期从Worker同步联系人
+	SyncInterval int    // 同步间隔（分钟）
+	Validation   string // 发送前校验收件人是否在已同步的联系人中：off, warn, strict
+
+	DefaultCountryCode string // 登录号码和收件人号码没有 + 或 00 前缀时使用的国家代码，为空时号码必须包含国家代码
+
+	OptOutKeywords []string // 入站消息完全匹配这些关键字时（不区分大小写）将联系人加入抑制名单
+
+	// 从CSV/XLSX导入联系人
+	ImportMaxRows    int // 单个文件最多导入的行数
+	ImportMaxSizeMB  int // 上传文件大小上限
+	ImportBatchSize  int // 每批并发调用Worker添加联系人的数量
+	ImportIntervalMs int // 批次之间的间隔（毫秒）
+}
+
+// InboxConfig 入站消息收集配置
+type InboxConfig struct {
+	CollectEnabled     bool // 是否定期从Worker拉取入站消息
+	CollectInterval    int  // 拉取间隔（秒）
+	CollectConcurrency int  // 同时拉取的账号数
+}
+
+// AutoReplyConfig 自动回复规则配置，规则只对收件箱收集到的入站消息生效
+type AutoReplyConfig struct {
+	Enabled       bool // 是否执行自动回复规则
+	QueueSize     int  // 待发送回复的队列长度，队列满时丢弃回复
+	Concurrency   int  // 同时发送回复的数量
+	MaxAgeMinutes int  // 只回复该时间内收到的消息，避免收集中断后回复积压的旧消息
+	WindowHours   int  // 每个联系人最多回复次数的统计窗口（小时）
+}
+
+// StatusPollConfig 向Worker轮询账号状态的配置，账号可单独设置稳定状态下的轮询间隔
+type StatusPollConfig struct {
+	IntervalSeconds      int // 账号稳定时的轮询间隔（秒）
+	LoginIntervalSeconds int // 账号处于启动或等待扫码、配对码等登录过程中的轮询间隔（秒）
+}
+
+// ReceiptConfig 出站消息回执跟踪配置
+type ReceiptConfig struct {
+	PollInterval int // 向Worker轮询未读回执的间隔（秒），0表示只依赖Worker回调
+	PollWindow   int // 只轮询最近多少小时内发送的消息
+}
+
+// WebhookConfig 事件Webhook投递配置
+type WebhookConfig struct {
+	Timeout     int // 单次投递超时（秒）
+	MaxAttempts int // 投递失败时的最多尝试次数
+}
+
+// GroupConfig 群组缓存配置
+type GroupConfig struct {
+	CacheSeconds int // 群组缓存超过该时间后查询时从Worker刷新
+}
+
+// SupervisorConfig Worker自动恢复配置
+type SupervisorConfig struct {
+	Enabled          bool // 是否在健康检查连续失败后自动重启Worker
+	Interval         int  // 健康检查间隔（秒）
+	FailureThreshold int  // 连续失败多少次后重启
+	MaxRestarts      int  // 连续重启次数上限，达到后标记为crash_looping并停止重启
+	BackoffSeconds   int  // 首次重启后的等待时间，之后指数递增
+	MaxBackoff       int  // 重启等待时间上限（秒）
+}
+
+// UpgradeConfig Worker镜像滚动升级配置
+type UpgradeConfig struct {
+	BatchSize     int // 每批升级的Worker数量，请求未指定时使用
+	SettleSeconds int // 每批启动后等待多久再做健康检查
+}
+
+// AlertConfig 故障告警配置
+type AlertConfig struct {
+	WebhookURL     string   `json:"-"` // 账号未设置负责人告警通道时使用的默认Webhook
+	Events         []string // 需要告警的事件类型
+	CheckInterval  int      // 代理连通性和磁盘空间检查间隔（秒），0表示不检查
+	TelegramAPIURL string   // Telegram Bot API地址
+}
+
+// JanitorConfig 退出容器与废弃会话目录清理配置
+type JanitorConfig struct {
+	Enabled       bool // 是否定期清理
+	Interval      int  // 清理间隔（分钟）
+	RetentionDays int  // 没有对应账号的会话目录超过该天数未修改时清理
+
+	SessionRetentionDays int // 账号删除超过该天数后清除其会话数据（含远程主机），小于0表示不自动清除
+
+	ReconcileOnStartup bool // 启动时是否将Worker容器与账号表对账
+
+	ImageCleanup bool // 是否删除Worker镜像的旧版本和悬空镜像
+	KeepImages   int  // 除当前镜像外保留的最近版本数，用于回滚
+}
+
+// DiagnosticsConfig Worker上传的诊断包存储配置
+type DiagnosticsConfig struct {
+	Dir           string // 诊断包存储目录，按账号ID分子目录
+	RetentionDays int    // 诊断包保留天数，由清理任务删除过期的诊断包
+	MaxUploadMB   int    // 单次上传大小上限
+}
+
+// AuditConfig 写操作审计日志配置
+type AuditConfig struct {
+	Enabled       bool // 是否记录 POST/PUT/PATCH/DELETE 请求
+	RetentionDays int  // 审计记录保留天数，由清理任务删除过期记录，0表示永久保留
+
+	WorkerCalls             bool // 是否记录对Worker的每次调用（代理转发和Master发起的调用）
+	WorkerCallRetentionDays int  // Worker调用记录保留天数，0表示不按时间删除
+	WorkerCallMaxPerAccount int  // 每个账号最多保留的Worker调用记录数，超出时由清理任务删除最早的记录，0表示不限制
+}
+
+// IdempotencyConfig 发送接口 Idempotency-Key 配置
+type IdempotencyConfig struct {
+	TTLHours int // 幂等键及其响应的保留时长（小时），0表示不支持幂等键
+}
+
+// HealthConfig 健康检查的降级阈值
+type HealthConfig struct {
+	DiskMinFreePercent  int // 会话目录所在磁盘剩余空间低于该百分比时降级
+	PortPoolWarnPercent int // 端口池使用率达到该百分比时降级
+	PortPoolWarnDays    int // 按最近7天的创建速度预计端口池在该天数内耗尽时降级，0表示不检查
+	MaxGoroutines       int // goroutine数量超过该值时降级，0表示不检查
+}
+
+// StatsConfig 账号每日统计配置
+type StatsConfig struct {
+	UptimeSampleSeconds int // 在线时长采样间隔（秒），0表示不统计在线时长
+	RetentionDays       int // 每日统计保留天数，0表示永久保留
+}
+
+// SchedulerConfig 多主机Worker调度配置
+type SchedulerConfig struct {
+	LocalEnabled       bool // 是否把Master所在主机作为调度目标
+	LocalMaxWorkers    int  // Master所在主机最多运行的Worker数，0表示不限制
+	HeartbeatSeconds   int  // 主机心跳检查间隔（秒）
+	FailureThreshold   int  // 心跳连续失败多少次后将主机标记为离线
+	RebalanceOnFailure bool // 主机离线后是否把其上的账号迁移到其他主机
+
+	LocalRegion    string // Master所在主机的出口地区
+	RegionAffinity string // 按账号代理地区选择主机：off、prefer（优先同地区，没有时放到其他主机）、require（只放到同地区主机）
+}
+
+// TenantConfig 多租户隔离配置
+type TenantConfig struct {
+	Enabled    bool   // 开启后所有API需携带租户 X-API-Key 或管理员 X-Admin-Token
+	AdminToken string `json:"-"` // 管理员token，可访问所有租户和管理接口
+
+	EnforceKeyOwner bool // 绑定了负责人的API Key只能通过该负责人名下的账号发送消息
+}
+
+// ChaosConfig 故障注入配置（仅用于测试环境）
+type ChaosConfig struct {
+	Enabled    bool   // 是否开启故障注入接口
+	AdminToken string `json:"-"` // 调用故障注入接口需携带的 X-Admin-Token
+}
+
+// ProxyConfig Worker反向代理配置
+type ProxyConfig struct {
+	Timeout       int            // 默认超时（秒），0表示不限制
+	RouteTimeouts map[string]int // 按Worker路径覆盖超时（秒），0表示不限制，用于流式接口
+
+	RetryAttempts  int // GET请求连接失败或Worker返回502/503/504时的重试次数
+	RetryBackoffMs int // 首次重试前的等待时间，之后每次翻倍
+
+	BreakerThreshold int // 连续失败多少次后熔断，账号标记为unreachable，0表示不熔断
+	BreakerCooldown  int // 熔断后多久（秒）放行一个探测请求
+
+	MaxConcurrent       int // 每个账号同时转发到Worker的请求数上限，0表示不限制
+	QueueSize           int // 达到上限后每个账号最多排队等待的请求数，超出返回429
+	QueueTimeoutSeconds int // 排队等待的最长时间（秒），超时返回429
+}
+
+// LogConfig 日志配置
+type LogConfig struct {
+	Level  string // debug, info, warn, error，运行时可通过配置接口修改
+	Format string // text, json
+}
+
+// TracingConfig 链路追踪配置，沿用 OpenTelemetry 标准环境变量
+type TracingConfig struct {
+	Exporter    string  // otlp 开启导出，none 关闭
+	Endpoint    string  // OTLP/HTTP traces 接收地址
+	Headers     string  // 导出请求附加的头，格式 key1=value1,key2=value2
+	ServiceName string  // 上报的服务名
+	SampleRatio float64 // 根Span采样比例（0-1），子Span跟随上游的采样决定
+}
+
+// SecretsConfig 数据库中凭证（代理密码、Worker和主机令牌、Webhook密钥、告警通道凭证）的加密配置
+type SecretsConfig struct {
+	Key          string   // AES-256密钥，base64或十六进制编码的32字节，为空时凭证以明文保存
+	KeyFile      string   // 从文件读取密钥，如KMS或Vault注入的挂载文件，优先于Key
+	PreviousKeys []string // 轮换前的旧密钥，只用于解密，启动时数据会用新密钥重新加密
+}
+
+// SessionSealConfig 会话目录静态加密配置：配置密钥后会话以加密归档保存在主机上，Worker运行时解密到内存文件系统（tmpfs），停止时重新加密
+type SessionSealConfig struct {
+	Key               string   `json:"-"` // AES-256密钥，base64或十六进制编码的32字节，都未配置时会话目录以明文保存
+	KeyFile           string   // 从文件读取密钥，如KMS或Vault注入的挂载文件，优先于Key
+	KeyCommand        string   // 启动时以 sh -c 执行，标准输出作为密钥，如 aws kms decrypt 或 vault kv get，优先于KeyFile
+	PreviousKeys      []string `json:"-"` // 轮换前的旧密钥，只用于解密，下次加密时使用新密钥
+	TmpfsSize         string   // 容器内会话 tmpfs 的大小上限，如 512m
+	CheckpointMinutes int      // 定期加密保存运行中Worker的会话（分钟），容器意外退出时最多丢失这段时间的变化，0表示只在停止和登录后保存
+}
+
+// HooksConfig Worker生命周期钩子：值为 http(s) 地址时以JSON POST调用，否则作为 sh -c 命令执行，为空表示不启用
+type HooksConfig struct {
+	PreSpawn  string `json:"-"` // 启动Worker容器前执行，失败时中止启动
+	PostReady string `json:"-"` // Worker就绪后在后台执行
+	PreDelete string `json:"-"` // 删除账号前执行，失败时中止删除
+	PostLogin string `json:"-"` // 账号登录成功后在后台执行
+	Timeout   int    // 单个钩子的超时时间（秒）
+	Secret    string `json:"-"` // Webhook钩子请求体的HMAC-SHA256签名密钥，为空时不签名
+}
+
+// TypingConfig 发送文本前模拟人工输入：按消息长度计算输入时长，账号单独开启
+type TypingConfig struct {
+	CharsPerSecond int // 模拟的输入速度（字符/秒）
+	MinDelayMs     int // 最短输入时长（毫秒）
+	MaxDelayMs     int // 最长输入时长（毫秒），长消息也不超过该值
+	JitterPercent  int // 输入时长的随机浮动百分比
+}
+
+// LeaderConfig 多副本共享数据库时的Leader选举，只有Leader启动Worker和执行后台任务
+type LeaderConfig struct {
+	Election     string // off 表示单实例，始终为Leader；db 表示通过数据库租约选举
+	ReplicaID    string // 本副本的唯一标识，为空时使用主机名
+	AdvertiseURL string // 其他副本转发写请求到本副本的地址，为空时使用 http://主机名:端口
+	LeaseSeconds int    // 租约有效期（秒），Leader失联超过该时间后由其他副本接管
+	RenewSeconds int    // 续约和竞选的间隔（秒），需小于租约有效期
+}
+
+// BanConfig 封号检测与隔离配置
+type BanConfig struct {
+	Enabled             bool     // 检测到封号信号时自动隔离账号
+	ErrorPatterns       []string // Worker错误信息或注销原因中表示封号的特征，不区分大小写
+	LogoutLoopCount     int      // 窗口内已登录账号掉线多少次视为封号，0表示不按掉线次数判断
+	LogoutLoopWindowMin int      // 统计掉线次数的窗口（分钟）
+}
+
+// WorkerHTTPConfig Master调用Worker共用的HTTP连接池
+type WorkerHTTPConfig struct {
+	TimeoutSeconds         int // Master直接调用Worker接口（发送、状态、登录等）的超时，不影响代理的流式请求
+	DialTimeoutSeconds     int // 建立连接的超时
+	MaxIdleConns           int // 所有Worker合计保留的空闲连接数
+	MaxIdleConnsPerHost    int // 每个Worker保留的空闲连接数
+	MaxConnsPerHost        int // 每个Worker的最大连接数，0表示不限制
+	IdleConnTimeoutSeconds int // 空闲连接保留时间（秒）
+
+	TLSEnabled            bool   // 通过https访问Worker，Worker需在端口上提供TLS
+	TLSCAFile             string // 校验Worker证书的CA，为空时使用系统根证书
+	TLSCertFile           string // 双向TLS时Master的客户端证书
+	TLSKeyFile            string // 双向TLS时Master的客户端私钥
+	TLSServerName         string // 校验证书时使用的主机名，为空时使用连接地址
+	TLSInsecureSkipVerify bool   // 不校验Worker证书，仅用于测试
+}
+
+// BackupConfig 会话目录定期备份配置
+type BackupConfig struct {
+	Schedule       string // 5段cron表达式或 @hourly、@daily 等别名，为空表示不定期备份
+	Destination    string // 备份目的地：本地目录、s3://bucket/prefix 或 sftp://user@host:port/path
+	RetentionCount int    // 每个账号保留的最近备份数，0表示不按数量清理
+	RetentionDays  int    // 备份保留天数，0表示不按时间清理；每个账号最新的备份始终保留
+	Concurrency    int    // 同时备份的账号数
+
+	S3Endpoint        string // S3兼容服务地址（如MinIO），为空时使用AWS区域地址
+	S3Region          string
+	S3AccessKeyID     string `json:"-"`
+	S3SecretAccessKey string `json:"-"`
+
+	SFTPKeyFile        string // SFTP登录使用的私钥文件
+	SFTPKnownHostsFile string // SFTP服务器的known_hosts文件，为空时首次连接自动信任
+}
+
+// StorageConfig 对象存储配置，用于媒体发送、聊天导出、联系人导入文件和 BACKUP_DESTINATION=storage 的备份
+type StorageConfig struct {
+	Destination string // 本地目录或 s3://bucket/prefix
+
+	S3Endpoint        string // S3兼容服务地址（如MinIO），为空时使用AWS区域地址
+	S3Region          string
+	S3AccessKeyID     string `json:"-"`
+	S3SecretAccessKey string `json:"-"`
+
+	BaseURL       string // 本地目录签名下载链接的访问地址前缀，S3使用预签名地址
+	SigningKey    string `json:"-"` // 本地目录下载链接签名密钥，为空时启动时随机生成
+	URLTTLMinutes int    // 签名下载链接有效期（分钟），S3最长7天
+	MaxUploadMB   int    // POST /storage/objects 单个文件大小上限
 }
 
 // Load 加载配置
 func Load() *Config {
 	return &Config{
 		Server: ServerConfig{
-			Host: getEnv("SERVER_HOST", "0.0.0.0"),
-			Port: getEnvInt("SERVER_PORT", 8080),
+			Host:            getEnv("SERVER_HOST", "0.0.0.0"),
+			Port:            getEnvInt("SERVER_PORT", 8080),
+			ShutdownTimeout: getEnvInt("SHUTDOWN_TIMEOUT", 30),
+			Environment:     getEnv("APP_ENV", "development"),
+
+			ConfigReloadSeconds: getEnvInt("CONFIG_RELOAD_SECONDS", 30),
 		},
 		Worker: WorkerConfig{
 			Mode:      getEnv("WORKER_MODE", "local"),
@@ -48,12 +544,384 @@ func Load() *Config {
 			BasePort:  getEnvInt("WORKER_BASE_PORT", 4000),
 			PortRange: getEnvInt("WORKER_PORT_RANGE", 1000),
 			Namespace: getEnv("K8S_NAMESPACE", "whatsapp"),
+
+			PortRanges: getEnv("WORKER_PORT_RANGES", ""),
+
+			StopOnShutdown: getEnvBool("WORKER_STOP_ON_SHUTDOWN", false),
+			SessionDir:     getEnv("SESSION_DIR", filepath.Join(os.Getenv("PWD"), "whatsapp-session")),
+			MasterURL:      getEnv("MASTER_URL", ""),
+
+			ProvisionConcurrency: getEnvInt("WORKER_PROVISION_CONCURRENCY", 4),
+
+			Memory:        getEnv("WORKER_MEMORY", ""),
+			CPUs:          getEnv("WORKER_CPUS", ""),
+			PidsLimit:     getEnvInt("WORKER_PIDS_LIMIT", 0),
+			RestartPolicy: getEnv("WORKER_RESTART_POLICY", ""),
+
+			Env:     getEnvList("WORKER_ENV", ""),
+			Volumes: getEnvList("WORKER_VOLUMES", ""),
+			DNS:     getEnvList("WORKER_DNS", ""),
+
+			HealthCmd:         getEnv("WORKER_HEALTH_CMD", defaultWorkerHealthCmd),
+			HealthInterval:    getEnvInt("WORKER_HEALTH_INTERVAL_SECONDS", 30),
+			HealthTimeout:     getEnvInt("WORKER_HEALTH_TIMEOUT_SECONDS", 10),
+			HealthRetries:     getEnvInt("WORKER_HEALTH_RETRIES", 3),
+			HealthStartPeriod: getEnvInt("WORKER_HEALTH_START_PERIOD_SECONDS", 120),
+
+			APIVersionMin: getEnvInt("WORKER_API_VERSION_MIN", 1),
+			APIVersionMax: getEnvInt("WORKER_API_VERSION_MAX", 1),
+			VersionCheck:  getEnv("WORKER_VERSION_CHECK", "warn"),
+
+			MockLoginDelayMs: getEnvInt("WORKER_MOCK_LOGIN_DELAY_MS", 3000),
+
+			ReadyTimeoutSeconds: getEnvInt("WORKER_READY_TIMEOUT_SECONDS", 60),
+			StartupLogLines:     getEnvInt("WORKER_STARTUP_LOG_LINES", 50),
 		},
 		DB: DBConfig{
-			Type: getEnv("DB_TYPE", "sqlite"),
-			Name: getEnv("DB_NAME", "./data/whatsapp_aggregator.db"),
+			Type:     getEnv("DB_TYPE", "sqlite"),
+			Name:     getEnv("DB_NAME", "./data/whatsapp_aggregator.db"),
+			DSN:      getEnv("DB_DSN", ""),
+			Host:     getEnv("DB_HOST", "localhost"),
+			Port:     getEnvInt("DB_PORT", 0),
+			User:     getEnv("DB_USER", ""),
+			Password: getEnv("DB_PASSWORD", ""),
+			SSLMode:  getEnv("DB_SSLMODE", "disable"),
+
+			MaxOpenConns:    getEnvInt("DB_MAX_OPEN_CONNS", 20),
+			MaxIdleConns:    getEnvInt("DB_MAX_IDLE_CONNS", 5),
+			ConnMaxLifetime: getEnvInt("DB_CONN_MAX_LIFETIME_MINUTES", 30),
+
+			AutoMigrate: getEnvBool("DB_AUTO_MIGRATE", true),
+		},
+		Bulk: BulkConfig{
+			Concurrency: getEnvInt("BULK_CONCURRENCY", 5),
+			IntervalMs:  getEnvInt("BULK_INTERVAL_MS", 1000),
+
+			ApprovalThreshold:  getEnvInt("APPROVAL_THRESHOLD", 0),
+			ApprovalSampleSize: getEnvInt("APPROVAL_SAMPLE_SIZE", 5),
+
+			BatchRetentionHours: getEnvInt("BULK_BATCH_RETENTION_HOURS", 24),
+		},
+		Media: MediaConfig{
+			Dir:       getEnv("MEDIA_DIR", "./data/media"),
+			MaxSizeMB: getEnvInt("MEDIA_MAX_SIZE_MB", 16),
+
+			RetentionDays:    getEnvInt("MEDIA_RETENTION_DAYS", 30),
+			AllowPrivateURLs: getEnvBool("MEDIA_ALLOW_PRIVATE_URLS", false),
+
+			BaseURL:       getEnv("MEDIA_BASE_URL", "http://localhost:8080"),
+			SigningKey:    getEnv("MEDIA_SIGNING_KEY", ""),
+			URLTTLMinutes: getEnvInt("MEDIA_URL_TTL_MINUTES", 60),
+		},
+		Tracking: TrackingConfig{
+			Enabled: getEnvBool("LINK_TRACKING_ENABLED", false),
+			BaseURL: getEnv("LINK_TRACKING_BASE_URL", "http://localhost:8080"),
+		},
+		Retry: RetryConfig{
+			MaxAttempts:  getEnvInt("SEND_RETRY_MAX_ATTEMPTS", 3),
+			BackoffMs:    getEnvInt("SEND_RETRY_BACKOFF_MS", 1000),
+			MaxBackoffMs: getEnvInt("SEND_RETRY_MAX_BACKOFF_MS", 30000),
+		},
+		DeadLetter: DeadLetterConfig{
+			Enabled:           getEnvBool("DEAD_LETTER_ENABLED", true),
+			AutoRetry:         getEnvBool("DEAD_LETTER_AUTO_RETRY", false),
+			MaxRetries:        getEnvInt("DEAD_LETTER_MAX_RETRIES", 5),
+			BackoffSeconds:    getEnvInt("DEAD_LETTER_BACKOFF_SECONDS", 60),
+			MaxBackoffSeconds: getEnvInt("DEAD_LETTER_MAX_BACKOFF_SECONDS", 3600),
+			RetentionDays:     getEnvInt("DEAD_LETTER_RETENTION_DAYS", 30),
+		},
+		Quota: QuotaConfig{
+			RatePerMinute: getEnvInt("SEND_RATE_PER_MINUTE", 60),
+			DailyQuota:    getEnvInt("SEND_DAILY_QUOTA", 0),
+			WarnRatio:     getEnvFloat("SEND_QUOTA_WARN_RATIO", 0.2),
+		},
+		RateLimit: RateLimitConfig{
+			GlobalPerMinute:  getEnvInt("SEND_LIMIT_GLOBAL_PER_MINUTE", 0),
+			GlobalBurst:      getEnvInt("SEND_LIMIT_GLOBAL_BURST", 0),
+			AccountPerMinute: getEnvInt("SEND_LIMIT_ACCOUNT_PER_MINUTE", 0),
+			AccountBurst:     getEnvInt("SEND_LIMIT_ACCOUNT_BURST", 0),
+		},
+		Warmup: WarmupConfig{
+			Enabled:        getEnvBool("WARMUP_ENABLED", false),
+			DefaultProfile: getEnv("WARMUP_DEFAULT_PROFILE", "default"),
+			Profiles:       parseWarmupProfiles(getEnv("WARMUP_PROFILES", "default=0:20,3:50,7:100,14:200,30:0;gentle=0:10,7:25,14:50,30:100,45:0")),
+		},
+		Session: SessionConfig{
+			MaxAgeHours:     getEnvInt("SESSION_MAX_AGE_HOURS", 336),
+			ExpiryRatio:     getEnvFloat("SESSION_EXPIRY_RATIO", 0.8),
+			RefreshEnabled:  getEnvBool("SESSION_REFRESH_ENABLED", false),
+			QuietHourStart:  getEnvInt("SESSION_QUIET_HOUR_START", 2),
+			QuietHourEnd:    getEnvInt("SESSION_QUIET_HOUR_END", 5),
+			RefreshInterval: getEnvInt("SESSION_REFRESH_INTERVAL_MINUTES", 30),
+
+			KeepAliveInterval:        getEnvInt("SESSION_KEEPALIVE_INTERVAL_SECONDS", 300),
+			KeepAliveRefreshCooldown: getEnvInt("SESSION_KEEPALIVE_REFRESH_COOLDOWN_MINUTES", 30),
+		},
+		QRLogin: QRLoginConfig{
+			PollSeconds:    getEnvInt("QR_LOGIN_POLL_SECONDS", 3),
+			TimeoutSeconds: getEnvInt("QR_LOGIN_TIMEOUT_SECONDS", 300),
+		},
+		PhoneLogin: PhoneLoginConfig{
+			PollSeconds:        getEnvInt("PHONE_LOGIN_POLL_SECONDS", 3),
+			CodeTimeoutSeconds: getEnvInt("PHONE_LOGIN_CODE_TIMEOUT_SECONDS", 180),
+			MaxAttempts:        getEnvInt("PHONE_LOGIN_MAX_ATTEMPTS", 3),
+			TimeoutSeconds:     getEnvInt("PHONE_LOGIN_TIMEOUT_SECONDS", 900),
+		},
+		Contact: ContactConfig{
+			SyncEnabled:  getEnvBool("CONTACT_SYNC_ENABLED", true),
+			SyncInterval: getEnvInt("CONTACT_SYNC_INTERVAL_MINUTES", 60),
+			Validation:   getEnv("CONTACT_VALIDATION", "warn"),
+
+			DefaultCountryCode: strings.TrimPrefix(getEnv("PHONE_DEFAULT_COUNTRY_CODE", ""), "+"),
+
+			OptOutKeywords: getEnvList("OPT_OUT_KEYWORDS", "stop,unsubscribe,opt out,optout,退订,取消订阅"),
+
+			ImportMaxRows:    getEnvInt("CONTACT_IMPORT_MAX_ROWS", 5000),
+			ImportMaxSizeMB:  getEnvInt("CONTACT_IMPORT_MAX_SIZE_MB", 5),
+			ImportBatchSize:  getEnvInt("CONTACT_IMPORT_BATCH_SIZE", 5),
+			ImportIntervalMs: getEnvInt("CONTACT_IMPORT_INTERVAL_MS", 1000),
+		},
+		Inbox: InboxConfig{
+			CollectEnabled:     getEnvBool("INBOX_COLLECT_ENABLED", true),
+			CollectInterval:    getEnvInt("INBOX_COLLECT_INTERVAL_SECONDS", 60),
+			CollectConcurrency: getEnvInt("INBOX_COLLECT_CONCURRENCY", 4),
+		},
+		AutoReply: AutoReplyConfig{
+			Enabled:       getEnvBool("AUTO_REPLY_ENABLED", true),
+			QueueSize:     getEnvInt("AUTO_REPLY_QUEUE_SIZE", 1000),
+			Concurrency:   getEnvInt("AUTO_REPLY_CONCURRENCY", 4),
+			MaxAgeMinutes: getEnvInt("AUTO_REPLY_MAX_AGE_MINUTES", 10),
+			WindowHours:   getEnvInt("AUTO_REPLY_WINDOW_HOURS", 24),
+		},
+		StatusPoll: StatusPollConfig{
+			IntervalSeconds:      getEnvInt("STATUS_POLL_INTERVAL_SECONDS", 300),
+			LoginIntervalSeconds: getEnvInt("STATUS_POLL_LOGIN_INTERVAL_SECONDS", 5),
+		},
+		Receipt: ReceiptConfig{
+			PollInterval: getEnvInt("RECEIPT_POLL_INTERVAL_SECONDS", 120),
+			PollWindow:   getEnvInt("RECEIPT_POLL_WINDOW_HOURS", 24),
+		},
+		Webhook: WebhookConfig{
+			Timeout:     getEnvInt("WEBHOOK_TIMEOUT_SECONDS", 10),
+			MaxAttempts: getEnvInt("WEBHOOK_MAX_ATTEMPTS", 3),
+		},
+		Group: GroupConfig{
+			CacheSeconds: getEnvInt("GROUP_CACHE_SECONDS", 300),
+		},
+		Supervisor: SupervisorConfig{
+			Enabled:          getEnvBool("SUPERVISOR_ENABLED", true),
+			Interval:         getEnvInt("SUPERVISOR_INTERVAL_SECONDS", 30),
+			FailureThreshold: getEnvInt("SUPERVISOR_FAILURE_THRESHOLD", 3),
+			MaxRestarts:      getEnvInt("SUPERVISOR_MAX_RESTARTS", 5),
+			BackoffSeconds:   getEnvInt("SUPERVISOR_BACKOFF_SECONDS", 10),
+			MaxBackoff:       getEnvInt("SUPERVISOR_MAX_BACKOFF_SECONDS", 300),
+		},
+		Upgrade: UpgradeConfig{
+			BatchSize:     getEnvInt("WORKER_UPGRADE_BATCH_SIZE", 5),
+			SettleSeconds: getEnvInt("WORKER_UPGRADE_SETTLE_SECONDS", 15),
+		},
+		Alert: AlertConfig{
+			WebhookURL:     getEnv("ALERT_WEBHOOK_URL", ""),
+			Events:         getEnvList("ALERT_EVENTS", "worker.crash_looping,worker.restart_failed,account.logged_out,account.banned,host.offline,proxy.down,disk.low"),
+			CheckInterval:  getEnvInt("ALERT_CHECK_INTERVAL_SECONDS", 300),
+			TelegramAPIURL: getEnv("ALERT_TELEGRAM_API_URL", "https://api.telegram.org"),
+		},
+		Janitor: JanitorConfig{
+			Enabled:       getEnvBool("JANITOR_ENABLED", true),
+			Interval:      getEnvInt("JANITOR_INTERVAL_MINUTES", 360),
+			RetentionDays: getEnvInt("JANITOR_RETENTION_DAYS", 7),
+
+			SessionRetentionDays: getEnvInt("SESSION_RETENTION_DAYS", getEnvInt("JANITOR_RETENTION_DAYS", 7)),
+
+			ReconcileOnStartup: getEnvBool("JANITOR_RECONCILE_ON_STARTUP", true),
+
+			ImageCleanup: getEnvBool("JANITOR_IMAGE_CLEANUP", true),
+			KeepImages:   getEnvInt("JANITOR_KEEP_IMAGES", 1),
+		},
+		Diagnostics: DiagnosticsConfig{
+			Dir:           getEnv("DIAGNOSTICS_DIR", filepath.Join(os.Getenv("PWD"), "diagnostics")),
+			RetentionDays: getEnvInt("DIAGNOSTICS_RETENTION_DAYS", 14),
+			MaxUploadMB:   getEnvInt("DIAGNOSTICS_MAX_UPLOAD_MB", 20),
+		},
+		Tenant: TenantConfig{
+			Enabled:    getEnvBool("MULTI_TENANT_ENABLED", false),
+			AdminToken: getEnv("ADMIN_TOKEN", ""),
+
+			EnforceKeyOwner: getEnvBool("API_KEY_OWNER_ENFORCEMENT", false),
+		},
+		Health: HealthConfig{
+			DiskMinFreePercent:  getEnvInt("HEALTH_DISK_MIN_FREE_PERCENT", 10),
+			PortPoolWarnPercent: getEnvInt("HEALTH_PORT_POOL_WARN_PERCENT", 90),
+			PortPoolWarnDays:    getEnvInt("HEALTH_PORT_POOL_WARN_DAYS", 7),
+			MaxGoroutines:       getEnvInt("HEALTH_MAX_GOROUTINES", 10000),
 		},
+		Stats: StatsConfig{
+			UptimeSampleSeconds: getEnvInt("STATS_UPTIME_SAMPLE_SECONDS", 60),
+			RetentionDays:       getEnvInt("STATS_RETENTION_DAYS", 400),
+		},
+		Scheduler: SchedulerConfig{
+			LocalEnabled:       getEnvBool("SCHEDULER_LOCAL_ENABLED", true),
+			LocalMaxWorkers:    getEnvInt("SCHEDULER_LOCAL_MAX_WORKERS", 0),
+			HeartbeatSeconds:   getEnvInt("HOST_HEARTBEAT_SECONDS", 15),
+			FailureThreshold:   getEnvInt("HOST_FAILURE_THRESHOLD", 3),
+			RebalanceOnFailure: getEnvBool("HOST_REBALANCE_ON_FAILURE", true),
+
+			LocalRegion:    getEnv("SCHEDULER_LOCAL_REGION", ""),
+			RegionAffinity: getEnv("SCHEDULER_REGION_AFFINITY", "prefer"),
+		},
+		Audit: AuditConfig{
+			Enabled:       getEnvBool("AUDIT_ENABLED", true),
+			RetentionDays: getEnvInt("AUDIT_RETENTION_DAYS", 90),
+
+			WorkerCalls:             getEnvBool("AUDIT_WORKER_CALLS", true),
+			WorkerCallRetentionDays: getEnvInt("AUDIT_WORKER_CALL_RETENTION_DAYS", 14),
+			WorkerCallMaxPerAccount: getEnvInt("AUDIT_WORKER_CALL_MAX_PER_ACCOUNT", 5000),
+		},
+		Idempotency: IdempotencyConfig{
+			TTLHours: getEnvInt("IDEMPOTENCY_TTL_HOURS", 24),
+		},
+		Chaos: ChaosConfig{
+			Enabled:    getEnvBool("CHAOS_ENABLED", false),
+			AdminToken: getEnv("CHAOS_ADMIN_TOKEN", ""),
+		},
+		Proxy: ProxyConfig{
+			Timeout:       getEnvInt("PROXY_TIMEOUT_SECONDS", 30),
+			RouteTimeouts: parseRouteTimeouts(getEnv("PROXY_ROUTE_TIMEOUTS", "/api/logs=0,/api/messages/stream=0,/api/debug/html=60")),
+
+			RetryAttempts:  getEnvInt("PROXY_RETRY_ATTEMPTS", 2),
+			RetryBackoffMs: getEnvInt("PROXY_RETRY_BACKOFF_MS", 200),
+
+			BreakerThreshold: getEnvInt("PROXY_BREAKER_THRESHOLD", 5),
+			BreakerCooldown:  getEnvInt("PROXY_BREAKER_COOLDOWN_SECONDS", 30),
+
+			MaxConcurrent:       getEnvInt("PROXY_MAX_CONCURRENT", 4),
+			QueueSize:           getEnvInt("PROXY_QUEUE_SIZE", 20),
+			QueueTimeoutSeconds: getEnvInt("PROXY_QUEUE_TIMEOUT_SECONDS", 30),
+		},
+		Log: LogConfig{
+			Level:  getEnv("LOG_LEVEL", "info"),
+			Format: getEnv("LOG_FORMAT", "text"),
+		},
+		Hooks: HooksConfig{
+			PreSpawn:  getEnv("HOOK_PRE_SPAWN", ""),
+			PostReady: getEnv("HOOK_POST_READY", ""),
+			PreDelete: getEnv("HOOK_PRE_DELETE", ""),
+			PostLogin: getEnv("HOOK_POST_LOGIN", ""),
+			Timeout:   getEnvInt("HOOK_TIMEOUT_SECONDS", 30),
+			Secret:    getEnv("HOOK_SECRET", ""),
+		},
+		Typing: TypingConfig{
+			CharsPerSecond: getEnvInt("TYPING_CHARS_PER_SECOND", 6),
+			MinDelayMs:     getEnvInt("TYPING_MIN_DELAY_MS", 1500),
+			MaxDelayMs:     getEnvInt("TYPING_MAX_DELAY_MS", 10000),
+			JitterPercent:  getEnvInt("TYPING_JITTER_PERCENT", 20),
+		},
+		Leader: LeaderConfig{
+			Election:     getEnv("LEADER_ELECTION", "off"),
+			ReplicaID:    getEnv("REPLICA_ID", ""),
+			AdvertiseURL: getEnv("REPLICA_ADVERTISE_URL", ""),
+			LeaseSeconds: getEnvInt("LEADER_LEASE_SECONDS", 15),
+			RenewSeconds: getEnvInt("LEADER_RENEW_SECONDS", 5),
+		},
+		Backup: BackupConfig{
+			Schedule:       getEnv("BACKUP_SCHEDULE", ""),
+			Destination:    getEnv("BACKUP_DESTINATION", filepath.Join(os.Getenv("PWD"), "backups")),
+			RetentionCount: getEnvInt("BACKUP_RETENTION_COUNT", 7),
+			RetentionDays:  getEnvInt("BACKUP_RETENTION_DAYS", 30),
+			Concurrency:    getEnvInt("BACKUP_CONCURRENCY", 4),
+
+			S3Endpoint:        getEnv("BACKUP_S3_ENDPOINT", ""),
+			S3Region:          getEnv("BACKUP_S3_REGION", "us-east-1"),
+			S3AccessKeyID:     getEnv("BACKUP_S3_ACCESS_KEY_ID", ""),
+			S3SecretAccessKey: getEnv("BACKUP_S3_SECRET_ACCESS_KEY", ""),
+
+			SFTPKeyFile:        getEnv("BACKUP_SFTP_KEY_FILE", ""),
+			SFTPKnownHostsFile: getEnv("BACKUP_SFTP_KNOWN_HOSTS_FILE", ""),
+		},
+		Storage: StorageConfig{
+			Destination: getEnv("STORAGE_DESTINATION", "./data/storage"),
+
+			S3Endpoint:        getEnv("STORAGE_S3_ENDPOINT", ""),
+			S3Region:          getEnv("STORAGE_S3_REGION", "us-east-1"),
+			S3AccessKeyID:     getEnv("STORAGE_S3_ACCESS_KEY_ID", ""),
+			S3SecretAccessKey: getEnv("STORAGE_S3_SECRET_ACCESS_KEY", ""),
+
+			BaseURL:       getEnv("STORAGE_BASE_URL", "http://localhost:8080"),
+			SigningKey:    getEnv("STORAGE_SIGNING_KEY", ""),
+			URLTTLMinutes: getEnvInt("STORAGE_URL_TTL_MINUTES", 60),
+			MaxUploadMB:   getEnvInt("STORAGE_MAX_UPLOAD_MB", 64),
+		},
+		WorkerHTTP: WorkerHTTPConfig{
+			TimeoutSeconds:         getEnvInt("WORKER_HTTP_TIMEOUT_SECONDS", 60),
+			DialTimeoutSeconds:     getEnvInt("WORKER_HTTP_DIAL_TIMEOUT_SECONDS", 5),
+			MaxIdleConns:           getEnvInt("WORKER_HTTP_MAX_IDLE_CONNS", 200),
+			MaxIdleConnsPerHost:    getEnvInt("WORKER_HTTP_MAX_IDLE_CONNS_PER_HOST", 10),
+			MaxConnsPerHost:        getEnvInt("WORKER_HTTP_MAX_CONNS_PER_HOST", 0),
+			IdleConnTimeoutSeconds: getEnvInt("WORKER_HTTP_IDLE_CONN_TIMEOUT_SECONDS", 90),
+
+			TLSEnabled:            getEnvBool("WORKER_TLS_ENABLED", false),
+			TLSCAFile:             getEnv("WORKER_TLS_CA_FILE", ""),
+			TLSCertFile:           getEnv("WORKER_TLS_CERT_FILE", ""),
+			TLSKeyFile:            getEnv("WORKER_TLS_KEY_FILE", ""),
+			TLSServerName:         getEnv("WORKER_TLS_SERVER_NAME", ""),
+			TLSInsecureSkipVerify: getEnvBool("WORKER_TLS_INSECURE_SKIP_VERIFY", false),
+		},
+		Ban: BanConfig{
+			Enabled:             getEnvBool("BAN_DETECTION_ENABLED", true),
+			ErrorPatterns:       getEnvList("BAN_ERROR_PATTERNS", "banned,TOS_BLOCK,SMB_TOS_BLOCK,not allowed to use WhatsApp"),
+			LogoutLoopCount:     getEnvInt("BAN_LOGOUT_LOOP_COUNT", 3),
+			LogoutLoopWindowMin: getEnvInt("BAN_LOGOUT_LOOP_WINDOW_MINUTES", 60),
+		},
+		Secrets: SecretsConfig{
+			Key:          getEnv("SECRETS_ENCRYPTION_KEY", ""),
+			KeyFile:      getEnv("SECRETS_ENCRYPTION_KEY_FILE", ""),
+			PreviousKeys: getEnvList("SECRETS_PREVIOUS_KEYS", ""),
+		},
+		SessionSeal: SessionSealConfig{
+			Key:               getEnv("SESSION_ENCRYPTION_KEY", ""),
+			KeyFile:           getEnv("SESSION_ENCRYPTION_KEY_FILE", ""),
+			KeyCommand:        getEnv("SESSION_ENCRYPTION_KEY_COMMAND", ""),
+			PreviousKeys:      getEnvList("SESSION_ENCRYPTION_PREVIOUS_KEYS", ""),
+			TmpfsSize:         getEnv("SESSION_TMPFS_SIZE", "512m"),
+			CheckpointMinutes: getEnvInt("SESSION_CHECKPOINT_MINUTES", 15),
+		},
+		Tracing: TracingConfig{
+			Exporter:    getEnv("OTEL_TRACES_EXPORTER", "none"),
+			Endpoint:    tracesEndpoint(),
+			Headers:     getEnv("OTEL_EXPORTER_OTLP_HEADERS", ""),
+			ServiceName: getEnv("OTEL_SERVICE_NAME", "whatsapp-master"),
+			SampleRatio: getEnvFloat("OTEL_TRACES_SAMPLER_ARG", 1.0),
+		},
+	}
+}
+
+// Clone 深拷贝配置，热更新时修改副本后整体替换，已发布的配置不再修改
+func (c *Config) Clone() *Config {
+	clone := *c
+	clone.Worker.Env = slices.Clone(c.Worker.Env)
+	clone.Worker.Volumes = slices.Clone(c.Worker.Volumes)
+	clone.Worker.DNS = slices.Clone(c.Worker.DNS)
+	clone.Contact.OptOutKeywords = slices.Clone(c.Contact.OptOutKeywords)
+	clone.Alert.Events = slices.Clone(c.Alert.Events)
+	clone.Proxy.RouteTimeouts = maps.Clone(c.Proxy.RouteTimeouts)
+	clone.Secrets.PreviousKeys = slices.Clone(c.Secrets.PreviousKeys)
+	clone.SessionSeal.PreviousKeys = slices.Clone(c.SessionSeal.PreviousKeys)
+	clone.Ban.ErrorPatterns = slices.Clone(c.Ban.ErrorPatterns)
+	if c.Warmup.Profiles != nil {
+		clone.Warmup.Profiles = make(map[string][]WarmupStep, len(c.Warmup.Profiles))
+		for name, steps := range c.Warmup.Profiles {
+			clone.Warmup.Profiles[name] = slices.Clone(steps)
+		}
 	}
+	return &clone
+}
+
+// tracesEndpoint OTEL_EXPORTER_OTLP_TRACES_ENDPOINT 优先，否则在 OTEL_EXPORTER_OTLP_ENDPOINT 后追加 /v1/traces
+func tracesEndpoint() string {
+	if endpoint := os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"); endpoint != "" {
+		return endpoint
+	}
+	return strings.TrimRight(getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318"), "/") + "/v1/traces"
 }
 
 // getEnv 获取环境变量，如果不存在则返回默认值
@@ -83,3 +951,70 @@ func getEnvBool(key string, defaultValue bool) bool {
 	}
 	return defaultValue
 }
+
+// getEnvFloat 获取浮点型环境变量
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvList 获取逗号分隔的列表型环境变量
+func getEnvList(key, defaultValue string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(getEnv(key, defaultValue), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// parseRouteTimeouts 解析 "path=seconds,path=seconds" 格式的按路径超时配置
+func parseRouteTimeouts(value string) map[string]int {
+	timeouts := make(map[string]int)
+	for _, item := range strings.Split(value, ",") {
+		path, seconds, ok := strings.Cut(strings.TrimSpace(item), "=")
+		if !ok {
+			continue
+		}
+		if n, err := strconv.Atoi(strings.TrimSpace(seconds)); err == nil {
+			timeouts[strings.TrimSpace(path)] = n
+		}
+	}
+	return timeouts
+}
+
+// parseWarmupProfiles 解析 "name=day:perDay,day:perDay;name=..." 格式的预热方案，
+// 每个方案按起始天数排序，缺少第0天的阶段或格式错误的方案被忽略
+func parseWarmupProfiles(value string) map[string][]WarmupStep {
+	profiles := make(map[string][]WarmupStep)
+	for _, item := range strings.Split(value, ";") {
+		name, rawSteps, ok := strings.Cut(strings.TrimSpace(item), "=")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			continue
+		}
+
+		steps := make([]WarmupStep, 0)
+		valid := true
+		for _, rawStep := range strings.Split(rawSteps, ",") {
+			day, perDay, ok := strings.Cut(strings.TrimSpace(rawStep), ":")
+			d, dayErr := strconv.Atoi(strings.TrimSpace(day))
+			n, perDayErr := strconv.Atoi(strings.TrimSpace(perDay))
+			if !ok || dayErr != nil || perDayErr != nil || d < 0 || n < 0 {
+				valid = false
+				break
+			}
+			steps = append(steps, WarmupStep{FromDay: d, PerDay: n})
+		}
+		sort.Slice(steps, func(i, j int) bool { return steps[i].FromDay < steps[j].FromDay })
+		if valid && len(steps) > 0 && steps[0].FromDay == 0 {
+			profiles[name] = steps
+		}
+	}
+	return profiles
+}
@@ -0,0 +1,421 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/gqlerrors"
+	"github.com/graphql-go/graphql/language/ast"
+	"github.com/graphql-go/graphql/language/location"
+	"github.com/graphql-go/graphql/language/parser"
+	"github.com/graphql-go/graphql/language/source"
+)
+
+// Params 一次GraphQL请求
+type Params struct {
+	Query         string
+	Variables     map[string]interface{}
+	OperationName string
+}
+
+// Response GraphQL响应，data 为 null 表示请求在执行前就失败了
+type Response struct {
+	Data   interface{} `json:"data"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// errorResponse 执行前失败的响应
+func errorResponse(err error) *Response {
+	var gqlErr *Error
+	if !errors.As(err, &gqlErr) {
+		gqlErr = &Error{Message: err.Error()}
+	}
+	return &Response{Errors: []*Error{gqlErr}}
+}
+
+// request 解析和校验通过的请求
+type request struct {
+	params    Params
+	doc       *ast.Document
+	op        *ast.OperationDefinition
+	fragments map[string]*ast.FragmentDefinition
+}
+
+// Execute 执行查询操作，订阅操作需使用 Subscribe
+func (s *Schema) Execute(ctx context.Context, params Params) *Response {
+	req, err := s.prepare(params)
+	if err != nil {
+		return errorResponse(err)
+	}
+	switch req.op.Operation {
+	case ast.OperationTypeSubscription:
+		return errorResponse(&Error{Message: "Subscriptions must be sent over the WebSocket transport", Locations: locationOf(req.op)})
+	case ast.OperationTypeMutation:
+		return errorResponse(&Error{Message: "Mutations are not supported; use the REST API", Locations: locationOf(req.op)})
+	}
+	return s.execute(ctx, req)
+}
+
+func (s *Schema) execute(ctx context.Context, req *request) *Response {
+	result := gql.Execute(gql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           req.doc,
+		OperationName: req.params.OperationName,
+		Args:          req.params.Variables,
+		Context:       ctx,
+	})
+	return executedResponse(ctx, result)
+}
+
+// executedResponse 转换执行结果。变量缺失或类型不符时 graphql-go 在执行前失败，错误没有路径，Data 保持为nil；
+// 非空字段的null传播到根时data输出为null，但Data不为nil，以区别于执行前的失败
+func executedResponse(ctx context.Context, result *gql.Result) *Response {
+	resp := &Response{Data: result.Data, Errors: convertErrors(result.Errors)}
+	if resp.Data != nil {
+		return resp
+	}
+	for _, err := range resp.Errors {
+		if len(err.Path) > 0 || ctx.Err() != nil {
+			resp.Data = json.RawMessage("null")
+			break
+		}
+	}
+	return resp
+}
+
+// Subscribe 执行订阅操作，根字段的事件源每推送一个值就输出一个响应，ctx结束或事件源关闭时关闭通道；
+// 查询操作只输出一个响应，便于WebSocket连接上同时执行查询和订阅
+func (s *Schema) Subscribe(ctx context.Context, params Params) (<-chan *Response, error) {
+	req, err := s.prepare(params)
+	if err != nil {
+		return nil, err
+	}
+	switch req.op.Operation {
+	case ast.OperationTypeQuery:
+		out := make(chan *Response, 1)
+		out <- s.execute(ctx, req)
+		close(out)
+		return out, nil
+	case ast.OperationTypeMutation:
+		return nil, &Error{Message: "Mutations are not supported; use the REST API", Locations: locationOf(req.op)}
+	}
+	if req.rootFields(req.op.SelectionSet) != 1 {
+		return nil, &Error{Message: "A subscription must select exactly one top level field", Locations: locationOf(req.op)}
+	}
+
+	// 事件源启动失败时同步返回错误，而不是作为第一个响应推送
+	started := make(chan error, 1)
+	ctx, cancel := context.WithCancel(context.WithValue(ctx, subscribeStartedKey{}, started))
+	results := gql.ExecuteSubscription(gql.ExecuteParams{
+		Schema:        s.schema,
+		AST:           req.doc,
+		OperationName: params.OperationName,
+		Args:          params.Variables,
+		Context:       ctx,
+	})
+	stop := func() {
+		cancel()
+		// graphql-go 的执行协程在通道无人接收时会阻塞，取消后排空
+		go func() {
+			for range results {
+			}
+		}()
+	}
+	select {
+	case err := <-started:
+		if err != nil {
+			stop()
+			return nil, &Error{Message: err.Error(), Locations: locationOf(req.op)}
+		}
+	case result, ok := <-results:
+		// 事件源启动前执行就失败了，如变量缺失
+		stop()
+		if ok && len(result.Errors) > 0 {
+			return nil, convertErrors(result.Errors)[0]
+		}
+		return nil, &Error{Message: "Subscription ended before it started", Locations: locationOf(req.op)}
+	}
+
+	out := make(chan *Response)
+	go func() {
+		defer close(out)
+		defer cancel()
+		for result := range results {
+			select {
+			case out <- executedResponse(ctx, result):
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return out, nil
+}
+
+type subscribeStartedKey struct{}
+
+// trackSubscribe 包装订阅根字段的事件源，把启动结果通知给 Subscribe
+func trackSubscribe(subscribe gql.FieldResolveFn) gql.FieldResolveFn {
+	return func(p gql.ResolveParams) (interface{}, error) {
+		source, err := subscribe(p)
+		if started, ok := p.Context.Value(subscribeStartedKey{}).(chan error); ok {
+			select {
+			case started <- err:
+			default:
+			}
+		}
+		return source, err
+	}
+}
+
+// rootFields 选择集中根字段的数量，片段展开后计算
+func (r *request) rootFields(set *ast.SelectionSet) int {
+	if set == nil {
+		return 0
+	}
+	count := 0
+	for _, sel := range set.Selections {
+		switch node := sel.(type) {
+		case *ast.Field:
+			count++
+		case *ast.InlineFragment:
+			count += r.rootFields(node.SelectionSet)
+		case *ast.FragmentSpread:
+			if frag, exists := r.fragments[node.Name.Value]; exists {
+				count += r.rootFields(frag.SelectionSet)
+			}
+		}
+	}
+	return count
+}
+
+// prepare 解析和校验查询、选择操作，并在执行任何解析函数前检查嵌套深度和复杂度
+func (s *Schema) prepare(params Params) (*request, error) {
+	if strings.TrimSpace(params.Query) == "" {
+		return nil, &Error{Message: "Must provide query string"}
+	}
+	doc, err := parser.Parse(parser.ParseParams{Source: source.NewSource(&source.Source{
+		Body: []byte(params.Query),
+		Name: "GraphQL request",
+	})})
+	if err != nil {
+		return nil, convertErrors(gqlerrors.FormatErrors(err))[0]
+	}
+	if result := gql.ValidateDocument(&s.schema, doc, nil); !result.IsValid {
+		return nil, convertErrors(result.Errors)[0]
+	}
+
+	req := &request{params: params, doc: doc, fragments: make(map[string]*ast.FragmentDefinition)}
+	for _, def := range doc.Definitions {
+		switch def := def.(type) {
+		case *ast.OperationDefinition:
+			if params.OperationName == "" || def.Name != nil && def.Name.Value == params.OperationName {
+				if req.op != nil {
+					return nil, &Error{Message: "Must provide operation name if query contains multiple operations"}
+				}
+				req.op = def
+			}
+		case *ast.FragmentDefinition:
+			req.fragments[def.Name.Value] = def
+		}
+	}
+	if req.op == nil {
+		return nil, &Error{Message: fmt.Sprintf("Unknown operation named %q", params.OperationName)}
+	}
+
+	var root *gql.Object
+	switch req.op.Operation {
+	case ast.OperationTypeQuery:
+		root = s.schema.QueryType()
+	case ast.OperationTypeSubscription:
+		root = s.schema.SubscriptionType()
+	default:
+		return req, nil
+	}
+	if root == nil {
+		return nil, &Error{Message: fmt.Sprintf("Schema does not support %s operations", req.op.Operation), Locations: locationOf(req.op)}
+	}
+
+	limits := queryLimits{maxDepth: s.MaxDepth, maxComplexity: s.MaxComplexity}
+	if limits.maxDepth <= 0 {
+		limits.maxDepth = DefaultMaxDepth
+	}
+	if limits.maxComplexity <= 0 {
+		limits.maxComplexity = DefaultMaxComplexity
+	}
+	if _, err := s.complexity(req, root, req.op.SelectionSet, 1, limits); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// queryLimits 校验时检查的嵌套深度和复杂度上限
+type queryLimits struct {
+	maxDepth      int
+	maxComplexity int
+}
+
+// complexity 检查嵌套深度并返回选择集的复杂度：每个字段计1，带 limit 参数的字段其子选择的复杂度乘以 limit。
+// 复杂度超过上限时立即返回，片段反复展开的查询因此也不会耗尽校验时间
+func (s *Schema) complexity(req *request, parent gql.Type, set *ast.SelectionSet, depth int, limits queryLimits) (int, error) {
+	if set == nil {
+		return 0, nil
+	}
+	if depth > limits.maxDepth {
+		return 0, &Error{Message: fmt.Sprintf("Query exceeds the maximum depth of %d", limits.maxDepth)}
+	}
+	total := 0
+	add := func(cost int) error {
+		total += cost
+		if total > limits.maxComplexity {
+			return &Error{Message: fmt.Sprintf("Query exceeds the maximum complexity of %d", limits.maxComplexity)}
+		}
+		return nil
+	}
+	for _, sel := range set.Selections {
+		var cost int
+		var err error
+		switch node := sel.(type) {
+		case *ast.Field:
+			cost = 1
+			field := fieldDefinition(parent, node.Name.Value)
+			if field != nil && node.SelectionSet != nil {
+				childLimits := limits
+				if field == gql.SchemaMetaFieldDef || field == gql.TypeMetaFieldDef {
+					// 标准内省查询的 ofType 嵌套超过深度上限，内省结果的大小只取决于Schema，不限制深度
+					childLimits.maxDepth = math.MaxInt
+				}
+				childCost, err := s.complexity(req, gql.GetNamed(field.Type).(gql.Type), node.SelectionSet, depth+1, childLimits)
+				if err != nil {
+					return 0, err
+				}
+				// 先比较再相乘，避免溢出
+				if size := req.pageSize(field, node); size > 1 && childCost > limits.maxComplexity/size {
+					cost += limits.maxComplexity
+				} else {
+					cost += max(size, 1) * childCost
+				}
+			}
+		case *ast.InlineFragment:
+			typ := parent
+			if node.TypeCondition != nil {
+				typ = s.schema.Type(node.TypeCondition.Name.Value)
+			}
+			cost, err = s.complexity(req, typ, node.SelectionSet, depth, limits)
+		case *ast.FragmentSpread:
+			if frag, exists := req.fragments[node.Name.Value]; exists {
+				cost, err = s.complexity(req, s.schema.Type(frag.TypeCondition.Name.Value), frag.SelectionSet, depth, limits)
+			}
+		}
+		if err != nil {
+			return 0, err
+		}
+		if err := add(cost); err != nil {
+			return 0, err
+		}
+	}
+	return total, nil
+}
+
+// fieldDefinition 查找类型上的字段，包括内省字段
+func fieldDefinition(parent gql.Type, name string) *gql.FieldDefinition {
+	switch name {
+	case gql.SchemaMetaFieldDef.Name:
+		return gql.SchemaMetaFieldDef
+	case gql.TypeMetaFieldDef.Name:
+		return gql.TypeMetaFieldDef
+	case gql.TypeNameMetaFieldDef.Name:
+		return gql.TypeNameMetaFieldDef
+	}
+	if fielder, ok := parent.(interface{ Fields() gql.FieldDefinitionMap }); ok {
+		return fielder.Fields()[name]
+	}
+	return nil
+}
+
+// pageSize 字段 limit 参数的值（字面量、变量或默认值），没有该参数时返回1
+func (r *request) pageSize(field *gql.FieldDefinition, node *ast.Field) int {
+	var arg *gql.Argument
+	for _, candidate := range field.Args {
+		if candidate.Name() == "limit" {
+			arg = candidate
+		}
+	}
+	if arg == nil {
+		return 1
+	}
+
+	value := arg.DefaultValue
+	for _, given := range node.Arguments {
+		if given.Name.Value != "limit" {
+			continue
+		}
+		switch v := given.Value.(type) {
+		case *ast.IntValue:
+			value = v.Value
+		case *ast.Variable:
+			if provided, exists := r.params.Variables[v.Name.Value]; exists && provided != nil {
+				value = provided
+				break
+			}
+			for _, def := range r.op.VariableDefinitions {
+				if def.Variable.Name.Value == v.Name.Value && def.DefaultValue != nil {
+					value = def.DefaultValue.GetValue()
+				}
+			}
+		}
+	}
+	size, ok := toInt(value)
+	if !ok || size < 1 {
+		return 1
+	}
+	return size
+}
+
+func toInt(value interface{}) (int, bool) {
+	switch v := value.(type) {
+	case int:
+		return v, true
+	case int64:
+		return int(v), true
+	case float64:
+		// 变量来自JSON解码，整数也是float64
+		if v == math.Trunc(v) && math.Abs(v) <= math.MaxInt32 {
+			return int(v), true
+		}
+	case string:
+		// 字面量的值以字符串保存
+		n, err := strconv.Atoi(v)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// convertErrors 把 graphql-go 的错误转换为响应中的错误
+func convertErrors(errs []gqlerrors.FormattedError) []*Error {
+	if len(errs) == 0 {
+		return nil
+	}
+	out := make([]*Error, len(errs))
+	for i, err := range errs {
+		out[i] = &Error{Message: err.Message, Path: err.Path}
+		for _, loc := range err.Locations {
+			out[i].Locations = append(out[i].Locations, Location{Line: loc.Line, Column: loc.Column})
+		}
+	}
+	return out
+}
+
+// locationOf 节点在查询文本中的位置
+func locationOf(node ast.Node) []Location {
+	loc := node.GetLoc()
+	if loc == nil || loc.Source == nil {
+		return nil
+	}
+	l := location.GetLocation(loc.Source, loc.Start)
+	return []Location{{Line: l.Line, Column: l.Column}}
+}
@@ -0,0 +1,572 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	gql "github.com/graphql-go/graphql"
+)
+
+type testUser struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	Secret    string    `json:"-"`
+}
+
+var testUsers = []*testUser{
+	{ID: "1", Name: "alice", CreatedAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), Secret: "s1"},
+	{ID: "2", Name: "bob", CreatedAt: time.Date(2024, 2, 3, 4, 5, 6, 0, time.UTC), Secret: "s2"},
+	{ID: "3", Name: "carol", CreatedAt: time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC), Secret: "s3"},
+}
+
+// newTestSchema 测试用Schema：用户列表、好友关系、出错字段和计数订阅
+func newTestSchema() *Schema {
+	userType := gql.NewObject(gql.ObjectConfig{Name: "User", Fields: gql.Fields{
+		"id":        &gql.Field{Type: gql.NewNonNull(gql.ID)},
+		"name":      &gql.Field{Type: gql.String},
+		"createdAt": &gql.Field{Type: Time},
+		"secret":    &gql.Field{Type: gql.String},
+		"broken": &gql.Field{Type: gql.NewNonNull(gql.String), Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return nil, errors.New("broken field")
+		}},
+	}})
+	userType.AddFieldConfig("friends", &gql.Field{Type: NonNullList(userType),
+		Args: gql.FieldConfigArgument{"limit": &gql.ArgumentConfig{Type: gql.Int, DefaultValue: 2}},
+		Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			var friends []*testUser
+			for _, u := range testUsers {
+				if u != p.Source.(*testUser) && len(friends) < p.Args["limit"].(int) {
+					friends = append(friends, u)
+				}
+			}
+			return friends, nil
+		}})
+
+	query := gql.NewObject(gql.ObjectConfig{Name: "Query", Fields: gql.Fields{
+		"users": &gql.Field{Type: NonNullList(userType),
+			Args: gql.FieldConfigArgument{"limit": &gql.ArgumentConfig{Type: gql.Int, DefaultValue: 10}},
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				limit := p.Args["limit"].(int)
+				return testUsers[:min(limit, len(testUsers))], nil
+			}},
+		"user": &gql.Field{Type: userType,
+			Args: gql.FieldConfigArgument{"id": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)}},
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				for _, u := range testUsers {
+					if u.ID == p.Args["id"] {
+						return u, nil
+					}
+				}
+				return nil, nil
+			}},
+		"greeting": &gql.Field{Type: gql.String,
+			Args: gql.FieldConfigArgument{
+				"name": &gql.ArgumentConfig{Type: gql.String, DefaultValue: "world"},
+				"tags": &gql.ArgumentConfig{Type: gql.NewList(gql.String)},
+			},
+			Resolve: func(p gql.ResolveParams) (interface{}, error) {
+				greeting := "hello " + p.Args["name"].(string)
+				if tags, ok := p.Args["tags"].([]interface{}); ok {
+					for _, tag := range tags {
+						greeting += " #" + tag.(string)
+					}
+				}
+				return greeting, nil
+			}},
+		"config": &gql.Field{Type: JSON, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return map[string]interface{}{"a": 1}, nil
+		}},
+		"fail": &gql.Field{Type: gql.String, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return nil, errors.New("resolver failed")
+		}},
+		"panic": &gql.Field{Type: gql.String, Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			panic("boom")
+		}},
+	}})
+
+	subscription := gql.NewObject(gql.ObjectConfig{Name: "Subscription", Fields: gql.Fields{
+		"count": &gql.Field{Type: gql.NewNonNull(gql.Int),
+			Args: gql.FieldConfigArgument{"to": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.Int)}},
+			Subscribe: func(p gql.ResolveParams) (interface{}, error) {
+				to := p.Args["to"].(int)
+				if to < 0 {
+					return nil, errors.New("to must not be negative")
+				}
+				ch := make(chan interface{})
+				go func() {
+					defer close(ch)
+					for i := 1; i <= to; i++ {
+						select {
+						case ch <- i:
+						case <-p.Context.Done():
+							return
+						}
+					}
+				}()
+				return ch, nil
+			}},
+	}})
+
+	schema, err := NewSchema(gql.SchemaConfig{Query: query, Subscription: subscription})
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+// jsonEqual 比较两段JSON是否表示同一个值，graphql-go 的结果以map保存，对象的键顺序不固定
+func jsonEqual(t *testing.T, got, want string) bool {
+	t.Helper()
+	var gotValue, wantValue interface{}
+	if err := json.Unmarshal([]byte(got), &gotValue); err != nil {
+		t.Fatalf("unmarshal %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &wantValue); err != nil {
+		t.Fatalf("unmarshal %s: %v", want, err)
+	}
+	return reflect.DeepEqual(gotValue, wantValue)
+}
+
+// execute 执行查询并把响应数据编码为JSON
+func execute(t *testing.T, schema *Schema, query string, variables map[string]interface{}) (string, []*Error) {
+	t.Helper()
+	resp := schema.Execute(context.Background(), Params{Query: query, Variables: variables})
+	data, err := json.Marshal(resp.Data)
+	if err != nil {
+		t.Fatalf("marshal data: %v", err)
+	}
+	return string(data), resp.Errors
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		want      string
+	}{
+		{
+			name:  "default resolver reads json tags",
+			query: `{ user(id: "1") { name id createdAt secret } }`,
+			want:  `{"user":{"name":"alice","id":"1","createdAt":"2024-01-02T03:04:05Z","secret":null}}`,
+		},
+		{
+			name:  "aliases and typename",
+			query: `{ first: user(id: "1") { __typename name } second: user(id: 2) { name } }`,
+			want:  `{"first":{"__typename":"User","name":"alice"},"second":{"name":"bob"}}`,
+		},
+		{
+			name:  "argument defaults",
+			query: `{ greeting users { id } }`,
+			want:  `{"greeting":"hello world","users":[{"id":"1"},{"id":"2"},{"id":"3"}]}`,
+		},
+		{
+			name:  "literal arguments and single value as list",
+			query: `{ a: greeting(name: "bob", tags: ["x", "y"]) b: greeting(tags: "z") users(limit: 1) { friends(limit: 1) { name } } }`,
+			want:  `{"a":"hello bob #x #y","b":"hello world #z","users":[{"friends":[{"name":"bob"}]}]}`,
+		},
+		{
+			name:      "variables with defaults",
+			query:     `query Q($id: ID!, $name: String = "carol", $limit: Int) { user(id: $id) { name } greeting(name: $name) users(limit: $limit) { id } }`,
+			variables: map[string]interface{}{"id": "2", "limit": float64(2)},
+			want:      `{"user":{"name":"bob"},"greeting":"hello carol","users":[{"id":"1"},{"id":"2"}]}`,
+		},
+		{
+			name:      "variables inside list literals",
+			query:     `query ($tag: String) { greeting(tags: ["a", $tag]) }`,
+			variables: map[string]interface{}{"tag": "b"},
+			want:      `{"greeting":"hello world #a #b"}`,
+		},
+		{
+			name:  "missing object resolves to null",
+			query: `{ user(id: "9") { name } }`,
+			want:  `{"user":null}`,
+		},
+		{
+			name:  "JSON scalar",
+			query: `{ config }`,
+			want:  `{"config":{"a":1}}`,
+		},
+		{
+			name: "fragments merge fields",
+			query: `
+				{ user(id: "1") { ...Names ... on User { id } name } }
+				fragment Names on User { name friends { ...Ids } }
+				fragment Ids on User { id }
+			`,
+			want: `{"user":{"name":"alice","friends":[{"id":"2"},{"id":"3"}],"id":"1"}}`,
+		},
+		{
+			name:      "skip and include",
+			query:     `query ($yes: Boolean!) { user(id: "1") { id @skip(if: true) name @include(if: $yes) ... @include(if: false) { createdAt } ...F @skip(if: $yes) } } fragment F on User { secret }`,
+			variables: map[string]interface{}{"yes": true},
+			want:      `{"user":{"name":"alice"}}`,
+		},
+	}
+	schema := newTestSchema()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, errs := execute(t, schema, tt.query, tt.variables)
+			if len(errs) > 0 {
+				t.Fatalf("errors: %+v", errs[0])
+			}
+			if !jsonEqual(t, data, tt.want) {
+				t.Errorf("data = %s\nwant   %s", data, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteOperationName(t *testing.T) {
+	schema := newTestSchema()
+	query := `query A { greeting } query B { config }`
+
+	resp := schema.Execute(context.Background(), Params{Query: query, OperationName: "B"})
+	data, _ := json.Marshal(resp.Data)
+	if !jsonEqual(t, string(data), `{"config":{"a":1}}`) {
+		t.Errorf("data = %s", data)
+	}
+
+	resp = schema.Execute(context.Background(), Params{Query: query})
+	if resp.Data != nil || len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, "Must provide operation name") {
+		t.Errorf("response = %+v", resp)
+	}
+	resp = schema.Execute(context.Background(), Params{Query: query, OperationName: "C"})
+	if resp.Data != nil || len(resp.Errors) != 1 || !strings.Contains(resp.Errors[0].Message, `Unknown operation named "C"`) {
+		t.Errorf("response = %+v", resp)
+	}
+}
+
+func TestExecuteFieldErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    string
+		message string
+		path    string
+	}{
+		{
+			name:    "resolver error keeps sibling fields",
+			query:   `{ fail greeting }`,
+			want:    `{"fail":null,"greeting":"hello world"}`,
+			message: "resolver failed",
+			path:    `["fail"]`,
+		},
+		{
+			name:    "panic becomes field error",
+			query:   `{ panic greeting }`,
+			want:    `{"panic":null,"greeting":"hello world"}`,
+			message: "boom",
+			path:    `["panic"]`,
+		},
+		{
+			name:    "non-null error propagates to nullable parent",
+			query:   `{ user(id: "1") { name broken } greeting }`,
+			want:    `{"user":null,"greeting":"hello world"}`,
+			message: "broken field",
+			path:    `["user","broken"]`,
+		},
+		{
+			name:    "non-null error in list item propagates to root",
+			query:   `{ users(limit: 2) { broken } }`,
+			want:    `null`,
+			message: "broken field",
+			path:    `["users",0,"broken"]`,
+		},
+	}
+	schema := newTestSchema()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, errs := execute(t, schema, tt.query, nil)
+			if !jsonEqual(t, data, tt.want) {
+				t.Errorf("data = %s\nwant   %s", data, tt.want)
+			}
+			if len(errs) != 1 {
+				t.Fatalf("errors = %d, want 1", len(errs))
+			}
+			if !strings.Contains(errs[0].Message, tt.message) {
+				t.Errorf("message = %q, want it to contain %q", errs[0].Message, tt.message)
+			}
+			if path, _ := json.Marshal(errs[0].Path); string(path) != tt.path {
+				t.Errorf("path = %s, want %s", path, tt.path)
+			}
+			if len(errs[0].Locations) != 1 {
+				t.Errorf("locations = %+v, want one location", errs[0].Locations)
+			}
+		})
+	}
+}
+
+func TestExecuteRequestErrors(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		message   string
+	}{
+		{"empty query", "  ", nil, "Must provide query string"},
+		{"syntax error", "{ users { id }", nil, "Syntax Error"},
+		{"unknown field", `{ user(id: "1") { email } }`, nil, `Cannot query field "email" on type "User".`},
+		{"unknown argument", `{ greeting(lang: "en") }`, nil, `Unknown argument "lang" on field "greeting" of type "Query".`},
+		{"missing required argument", `{ user { id } }`, nil, `Field "user" argument "id" of type "ID!" is required`},
+		{"selection on scalar", `{ greeting { length } }`, nil, `Field "greeting" of type "String" must not have a sub selection.`},
+		{"missing selection on object", `{ users }`, nil, `Field "users" of type "[User!]!" must have a sub selection.`},
+		{"unknown fragment", `{ users { ...Missing } }`, nil, `Unknown fragment "Missing".`},
+		{"fragment cycle", `{ users { ...A } } fragment A on User { friends { ...B } } fragment B on User { ...A }`, nil, `Cannot spread fragment "A" within itself via B.`},
+		{"fragment on wrong type", `{ users { ...Q } } fragment Q on Query { greeting }`, nil, `Fragment "Q" cannot be spread here as objects of type "User" can never be of type "Query".`},
+		{"invalid argument value", `{ greeting(name: 1) }`, nil, `Argument "name" has invalid value 1.`},
+		{"invalid ID argument", `{ user(id: 1.5) { id } }`, nil, `Argument "id" has invalid value 1.5.`},
+		{"undefined variable", `{ greeting(name: $name) }`, nil, `Variable "$name" is not defined`},
+		{"missing required variable", `query ($id: ID!) { user(id: $id) { id } }`, nil, `Variable "$id" of required type "ID!" was not provided.`},
+		{"mutation", `mutation { greeting }`, nil, "Mutations are not supported"},
+		{"subscription over HTTP", `subscription { count(to: 1) }`, nil, "Subscriptions must be sent over the WebSocket transport"},
+	}
+	schema := newTestSchema()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := schema.Execute(context.Background(), Params{Query: tt.query, Variables: tt.variables})
+			if resp.Data != nil {
+				t.Errorf("data = %+v, want nil", resp.Data)
+			}
+			if len(resp.Errors) != 1 {
+				t.Fatalf("errors = %d, want 1", len(resp.Errors))
+			}
+			if !strings.Contains(resp.Errors[0].Message, tt.message) {
+				t.Errorf("message = %q, want it to contain %q", resp.Errors[0].Message, tt.message)
+			}
+		})
+	}
+}
+
+func TestExecuteDepthLimit(t *testing.T) {
+	schema := newTestSchema()
+	schema.MaxDepth = 3
+
+	if _, errs := execute(t, schema, `{ users { friends { name } } }`, nil); len(errs) > 0 {
+		t.Fatalf("query at the depth limit failed: %+v", errs[0])
+	}
+	_, errs := execute(t, schema, `{ users { friends { friends { name } } } }`, nil)
+	if len(errs) != 1 || errs[0].Message != "Query exceeds the maximum depth of 3" {
+		t.Fatalf("errors = %+v, want depth error", errs)
+	}
+	// 片段内的字段同样计入深度
+	_, errs = execute(t, schema, `{ users { ...F } } fragment F on User { friends { friends { id } } }`, nil)
+	if len(errs) != 1 || errs[0].Message != "Query exceeds the maximum depth of 3" {
+		t.Fatalf("errors = %+v, want depth error", errs)
+	}
+}
+
+func TestExecuteComplexityLimit(t *testing.T) {
+	schema := newTestSchema()
+	schema.MaxComplexity = 100
+
+	tests := []struct {
+		name      string
+		query     string
+		variables map[string]interface{}
+		exceeds   bool
+	}{
+		// users: 1 + 10*(1 + friends: 1 + 2*1) = 41
+		{name: "default page sizes", query: `{ users { id friends { id } } }`},
+		// users: 1 + 10*(1 + 10*1) = 111
+		{name: "literal limit multiplies children", query: `{ users { id friends(limit: 10) { id } } }`, exceeds: true},
+		{name: "variable limit", query: `query ($n: Int) { users(limit: $n) { id name } }`, variables: map[string]interface{}{"n": float64(50)}, exceeds: true},
+		{name: "small variable limit", query: `query ($n: Int) { users(limit: $n) { id name } }`, variables: map[string]interface{}{"n": float64(5)}},
+		{name: "variable default limit", query: `query ($n: Int = 1000) { users(limit: $n) { id } }`, exceeds: true},
+		{name: "aliases add up", query: `{ a: users { id } b: users { id } c: users { id } d: users { id } e: users { id } }`},
+		{name: "many aliases exceed", query: `{ a: users { id name } b: users { id name } c: users { id name } d: users { id name } e: users { id name } }`, exceeds: true},
+		// 每层片段展开两次，展开后的字段数按指数增长
+		{
+			name: "fragment fan-out",
+			query: `{ user(id: "1") { ...A } }
+				fragment A on User { a1: friends(limit: 1) { ...B } a2: friends(limit: 1) { ...B } }
+				fragment B on User { b1: friends(limit: 1) { ...C } b2: friends(limit: 1) { ...C } }
+				fragment C on User { c1: friends(limit: 1) { ...D } c2: friends(limit: 1) { ...D } }
+				fragment D on User { d1: friends(limit: 1) { ...E } d2: friends(limit: 1) { ...E } }
+				fragment E on User { e1: friends(limit: 1) { ...F } e2: friends(limit: 1) { ...F } }
+				fragment F on User { id name }`,
+			exceeds: true,
+		},
+		{name: "huge limit does not overflow", query: `{ users(limit: 2147483647) { friends(limit: 2147483647) { friends(limit: 2147483647) { id } } } }`, exceeds: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := schema.Execute(context.Background(), Params{Query: tt.query, Variables: tt.variables})
+			exceeded := len(resp.Errors) == 1 && resp.Errors[0].Message == "Query exceeds the maximum complexity of 100"
+			if exceeded != tt.exceeds {
+				t.Errorf("exceeded = %v, want %v (errors: %+v)", exceeded, tt.exceeds, resp.Errors)
+			}
+			if !tt.exceeds && len(resp.Errors) > 0 {
+				t.Errorf("errors: %+v", resp.Errors[0])
+			}
+		})
+	}
+}
+
+func TestExecuteDefaultComplexityLimit(t *testing.T) {
+	schema := newTestSchema()
+	// 默认上限下常规分页查询可以执行
+	if _, errs := execute(t, schema, `{ users(limit: 100) { id name friends(limit: 20) { id name } } }`, nil); len(errs) > 0 {
+		t.Fatalf("errors: %+v", errs[0])
+	}
+	_, errs := execute(t, schema, `{ users(limit: 1000) { friends(limit: 1000) { id } } }`, nil)
+	if len(errs) != 1 || !strings.Contains(errs[0].Message, "maximum complexity") {
+		t.Fatalf("errors = %+v, want complexity error", errs)
+	}
+}
+
+func TestIntrospection(t *testing.T) {
+	schema := newTestSchema()
+	schema.MaxDepth = 3
+	// 与GraphiQL等工具发出的内省查询相同，TypeRef 的 ofType 嵌套超过深度上限
+	query := `
+		query IntrospectionQuery {
+			__schema { queryType { name } subscriptionType { name } types { ...FullType } }
+		}
+		fragment FullType on __Type {
+			kind name
+			fields(includeDeprecated: true) { name args { name type { ...TypeRef } defaultValue } type { ...TypeRef } }
+		}
+		fragment TypeRef on __Type {
+			kind name
+			ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name ofType { kind name } } } } } }
+		}`
+	resp := schema.Execute(context.Background(), Params{Query: query})
+	if len(resp.Errors) > 0 {
+		t.Fatalf("errors: %+v", resp.Errors[0])
+	}
+	data, _ := json.Marshal(resp.Data)
+	var result struct {
+		Schema struct {
+			QueryType struct{ Name string } `json:"queryType"`
+			Types     []struct {
+				Name   string
+				Fields []struct{ Name string }
+			}
+		} `json:"__schema"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		t.Fatalf("unmarshal: %v", err)
+	}
+	if result.Schema.QueryType.Name != "Query" {
+		t.Errorf("queryType = %q", result.Schema.QueryType.Name)
+	}
+	found := false
+	for _, typ := range result.Schema.Types {
+		if typ.Name == "User" {
+			found = len(typ.Fields) == 6
+		}
+	}
+	if !found {
+		t.Errorf("User type with 6 fields not found in %s", data)
+	}
+
+	// 普通字段仍受深度限制
+	_, errs := execute(t, schema, `{ __type(name: "User") { name } users { friends { friends { id } } } }`, nil)
+	if len(errs) != 1 || errs[0].Message != "Query exceeds the maximum depth of 3" {
+		t.Fatalf("errors = %+v, want depth error", errs)
+	}
+}
+
+func TestSDL(t *testing.T) {
+	sdl := newTestSchema().SDL()
+	for _, want := range []string{
+		"schema {\n  query: Query\n  subscription: Subscription\n}\n",
+		"\"RFC 3339 时间\"\nscalar Time\n",
+		"  greeting(name: String = \"world\", tags: [String]): String\n",
+		"  friends(limit: Int = 2): [User!]!\n",
+		"type Subscription {\n  count(to: Int!): Int!\n}\n",
+	} {
+		if !strings.Contains(sdl, want) {
+			t.Errorf("SDL missing %q:\n%s", want, sdl)
+		}
+	}
+	if sdl != newTestSchema().SDL() {
+		t.Error("SDL output is not stable")
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	schema := newTestSchema()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	out, err := schema.Subscribe(ctx, Params{Query: `subscription ($to: Int!) { n: count(to: $to) }`, Variables: map[string]interface{}{"to": float64(3)}})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	var got []string
+	for resp := range out {
+		data, _ := json.Marshal(resp.Data)
+		got = append(got, string(data))
+	}
+	if want := `{"n":1},{"n":2},{"n":3}`; strings.Join(got, ",") != want {
+		t.Errorf("events = %s, want %s", strings.Join(got, ","), want)
+	}
+}
+
+func TestSubscribeQuery(t *testing.T) {
+	out, err := newTestSchema().Subscribe(context.Background(), Params{Query: `{ greeting }`})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	resp, ok := <-out
+	if !ok {
+		t.Fatal("no response")
+	}
+	if data, _ := json.Marshal(resp.Data); string(data) != `{"greeting":"hello world"}` {
+		t.Errorf("data = %s", data)
+	}
+	if _, ok := <-out; ok {
+		t.Error("query response channel not closed")
+	}
+}
+
+func TestSubscribeCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	out, err := newTestSchema().Subscribe(ctx, Params{Query: `subscription { count(to: 1000000) }`})
+	if err != nil {
+		t.Fatalf("subscribe: %v", err)
+	}
+	<-out
+	cancel()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-out:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("subscription channel not closed after cancel")
+		}
+	}
+}
+
+func TestSubscribeErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		message string
+	}{
+		{"two root fields", `subscription { a: count(to: 1) b: count(to: 2) }`, "exactly one top level field"},
+		{"event source error", `subscription { count(to: -1) }`, "to must not be negative"},
+		{"unknown field", `subscription { ticks }`, `Cannot query field "ticks" on type "Subscription".`},
+		{"selection on scalar", `subscription { count(to: 1) { value } }`, "must not have a sub selection"},
+	}
+	schema := newTestSchema()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := schema.Subscribe(context.Background(), Params{Query: tt.query})
+			if err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.message)
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+// Package graphql 在 graphql-go 之上为面板查询提供GraphQL服务
+//
+// 解析、校验和执行由 github.com/graphql-go/graphql 完成，本包补充查询深度和复杂度限制、
+// 订阅的执行方式、Time 和 JSON 标量、按json标签读取结构体字段的默认解析函数，以及 SDL 输出。
+package graphql
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	gql "github.com/graphql-go/graphql"
+	"github.com/graphql-go/graphql/language/ast"
+)
+
+// Schema 查询和订阅的Schema及其限制
+type Schema struct {
+	schema        gql.Schema
+	MaxDepth      int // 字段嵌套的最大深度，0表示使用默认值
+	MaxComplexity int // 查询复杂度上限，0表示使用默认值
+}
+
+// DefaultMaxDepth 未设置MaxDepth时的嵌套深度上限
+const DefaultMaxDepth = 10
+
+// DefaultMaxComplexity 未设置MaxComplexity时的复杂度上限，约为一次查询最多解析的字段数
+const DefaultMaxComplexity = 20000
+
+// NewSchema 创建Schema。未设置Resolve的字段按字段名从父对象读取，结构体字段按json标签匹配；
+// 订阅根字段未设置Resolve时直接输出事件源推送的值
+func NewSchema(config gql.SchemaConfig) (*Schema, error) {
+	schema, err := gql.NewSchema(config)
+	if err != nil {
+		return nil, err
+	}
+	for name, t := range schema.TypeMap() {
+		obj, ok := t.(*gql.Object)
+		if !ok || strings.HasPrefix(name, "__") {
+			continue
+		}
+		subscription := obj == schema.SubscriptionType()
+		for _, field := range obj.Fields() {
+			if subscription && field.Subscribe != nil {
+				field.Subscribe = trackSubscribe(field.Subscribe)
+			}
+			if field.Resolve != nil {
+				continue
+			}
+			if subscription {
+				field.Resolve = func(p gql.ResolveParams) (interface{}, error) { return p.Source, nil }
+			} else {
+				field.Resolve = defaultResolve
+			}
+		}
+	}
+	return &Schema{schema: schema}, nil
+}
+
+// Location 查询文本中的位置
+type Location struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// Error GraphQL错误，path为出错字段在结果中的路径
+type Error struct {
+	Message   string        `json:"message"`
+	Locations []Location    `json:"locations,omitempty"`
+	Path      []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string { return e.Message }
+
+// 自定义标量，String、Int、Float、Boolean、ID 使用 graphql-go 内置的标量
+
+var (
+	Time = gql.NewScalar(gql.ScalarConfig{
+		Name:        "Time",
+		Description: "RFC 3339 时间",
+		Serialize: func(value interface{}) interface{} {
+			switch v := value.(type) {
+			case time.Time:
+				return v.Format(time.RFC3339Nano)
+			case *time.Time:
+				if v == nil {
+					return nil
+				}
+				return v.Format(time.RFC3339Nano)
+			}
+			return nil
+		},
+		ParseValue: func(value interface{}) interface{} {
+			if s, ok := value.(string); ok {
+				if t, err := time.Parse(time.RFC3339, s); err == nil {
+					return t
+				}
+			}
+			return nil
+		},
+		ParseLiteral: func(valueAST ast.Value) interface{} {
+			if s, ok := valueAST.(*ast.StringValue); ok {
+				if t, err := time.Parse(time.RFC3339, s.Value); err == nil {
+					return t
+				}
+			}
+			return nil
+		},
+	})
+
+	JSON = gql.NewScalar(gql.ScalarConfig{
+		Name:         "JSON",
+		Description:  "任意JSON值，原样输出",
+		Serialize:    func(value interface{}) interface{} { return value },
+		ParseValue:   func(value interface{}) interface{} { return value },
+		ParseLiteral: func(valueAST ast.Value) interface{} { return valueAST.GetValue() },
+	})
+)
+
+// NonNullList 元素和列表都非空的列表类型，即 [T!]!
+func NonNullList(t gql.Type) *gql.NonNull { return gql.NewNonNull(gql.NewList(gql.NewNonNull(t))) }
+
+// defaultResolve 从map或结构体读取字段，结构体字段按json标签匹配，GraphQL的驼峰名同时匹配蛇形标签（accountId 对应 account_id）
+func defaultResolve(p gql.ResolveParams) (interface{}, error) {
+	return resolveName(p.Source, p.Info.FieldName), nil
+}
+
+func resolveName(source interface{}, name string) interface{} {
+	if isNil(source) {
+		return nil
+	}
+	if m, ok := source.(map[string]interface{}); ok {
+		if value, exists := m[name]; exists {
+			return value
+		}
+		return m[snakeCase(name)]
+	}
+	if m, ok := source.(map[string]string); ok {
+		if value, exists := m[name]; exists {
+			return value
+		}
+		if value, exists := m[snakeCase(name)]; exists {
+			return value
+		}
+		return nil
+	}
+
+	rv := reflect.ValueOf(source)
+	for rv.Kind() == reflect.Ptr {
+		rv = rv.Elem()
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil
+	}
+	index, ok := structFieldIndex(rv.Type(), name)
+	if !ok {
+		return nil
+	}
+	field, err := rv.FieldByIndexErr(index)
+	if err != nil {
+		return nil
+	}
+	return field.Interface()
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface, reflect.Func, reflect.Chan:
+		return rv.IsNil()
+	}
+	return false
+}
+
+// structFields 结构体类型的GraphQL名到字段索引的缓存
+var structFields sync.Map
+
+func structFieldIndex(t reflect.Type, name string) ([]int, bool) {
+	cached, ok := structFields.Load(t)
+	if !ok {
+		fields := make(map[string][]int)
+		for _, field := range reflect.VisibleFields(t) {
+			if !field.IsExported() || field.Anonymous {
+				continue
+			}
+			tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+			if tag == "-" {
+				continue
+			}
+			if tag == "" {
+				tag = field.Name
+			}
+			fields[tag] = field.Index
+			if _, exists := fields[strings.ToLower(field.Name)]; !exists {
+				fields[strings.ToLower(field.Name)] = field.Index
+			}
+		}
+		cached, _ = structFields.LoadOrStore(t, fields)
+	}
+	fields := cached.(map[string][]int)
+	if index, exists := fields[name]; exists {
+		return index, true
+	}
+	if index, exists := fields[snakeCase(name)]; exists {
+		return index, true
+	}
+	index, exists := fields[strings.ToLower(name)]
+	return index, exists
+}
+
+// snakeCase 驼峰名转为蛇形，如 createdAt -> created_at
+func snakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// SDL 以Schema定义语言输出，供客户端生成类型和查看字段说明，类型和字段按名称排序
+func (s *Schema) SDL() string {
+	types := make(map[string]gql.Type)
+	var collect func(t gql.Type)
+	collect = func(t gql.Type) {
+		named := gql.GetNamed(t).(gql.Type)
+		if _, seen := types[named.Name()]; seen {
+			return
+		}
+		types[named.Name()] = named
+		if obj, ok := named.(*gql.Object); ok {
+			for _, field := range obj.Fields() {
+				collect(field.Type)
+				for _, arg := range field.Args {
+					collect(arg.Type)
+				}
+			}
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("schema {\n")
+	if query := s.schema.QueryType(); query != nil {
+		collect(query)
+		b.WriteString("  query: " + query.Name() + "\n")
+	}
+	if subscription := s.schema.SubscriptionType(); subscription != nil {
+		collect(subscription)
+		b.WriteString("  subscription: " + subscription.Name() + "\n")
+	}
+	b.WriteString("}\n")
+
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	builtin := map[string]bool{"String": true, "Int": true, "Float": true, "Boolean": true, "ID": true}
+	for _, name := range names {
+		switch t := types[name].(type) {
+		case *gql.Scalar:
+			if builtin[name] {
+				continue
+			}
+			b.WriteString("\n")
+			writeDescription(&b, "", t.Description())
+			b.WriteString("scalar " + name + "\n")
+		case *gql.Object:
+			b.WriteString("\n")
+			writeDescription(&b, "", t.Description())
+			b.WriteString("type " + name + " {\n")
+			fields := t.Fields()
+			fieldNames := make([]string, 0, len(fields))
+			for fieldName := range fields {
+				fieldNames = append(fieldNames, fieldName)
+			}
+			sort.Strings(fieldNames)
+			for _, fieldName := range fieldNames {
+				field := fields[fieldName]
+				writeDescription(&b, "  ", field.Description)
+				b.WriteString("  " + field.Name)
+				if len(field.Args) > 0 {
+					// graphql-go 按map保存参数，输出时按名称排序
+					sorted := slices.Clone(field.Args)
+					slices.SortFunc(sorted, func(a, b *gql.Argument) int { return strings.Compare(a.Name(), b.Name()) })
+					args := make([]string, len(sorted))
+					for i, arg := range sorted {
+						args[i] = arg.Name() + ": " + arg.Type.String()
+						if arg.DefaultValue != nil {
+							args[i] += " = " + literal(arg.DefaultValue)
+						}
+					}
+					b.WriteString("(" + strings.Join(args, ", ") + ")")
+				}
+				b.WriteString(": " + field.Type.String())
+				if field.DeprecationReason != "" {
+					b.WriteString(" @deprecated(reason: " + strconv.Quote(field.DeprecationReason) + ")")
+				}
+				b.WriteString("\n")
+			}
+			b.WriteString("}\n")
+		}
+	}
+	return b.String()
+}
+
+func writeDescription(b *strings.Builder, indent, description string) {
+	if description == "" {
+		return
+	}
+	b.WriteString(indent + strconv.Quote(description) + "\n")
+}
+
+// literal 默认值的GraphQL字面量表示
+func literal(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return strconv.Quote(v)
+	case []interface{}:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = literal(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case []string:
+		items := make([]string, len(v))
+		for i, item := range v {
+			items[i] = strconv.Quote(item)
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	}
+	return fmt.Sprint(value)
+}
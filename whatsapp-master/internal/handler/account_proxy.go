@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// SetAccountProxy 设置账号绑定的代理
+// @Summary Set Account Proxy
+// @Description Store the proxy bound to an account. The binding is used for later logins and whenever the worker container is recreated (restart, recovery, rebalancing). An empty ip removes the binding. With apply=true the running worker switches to the proxy immediately, which restarts its WhatsApp client; if that fails the binding is still saved and a warning is returned.
+// @Tags Proxy
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param request body model.SetAccountProxyRequest true "Proxy binding"
+// @Success 200 {object} model.APIResponse{data=model.Account}
+// @Router /accounts/{id}/proxy [put]
+func (h *Handler) SetAccountProxy(c *gin.Context) {
+	var req model.SetAccountProxyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if !h.accountExists(c, c.Param("id")) {
+		return
+	}
+
+	account, err := h.manager.SetAccountProxy(c.Request.Context(), c.Param("id"), &req)
+	if account == nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to set account proxy",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	resp := model.APIResponse{
+		Success: true,
+		Message: "Account proxy updated successfully",
+		Data:    account,
+	}
+	if err != nil {
+		resp.Warning = "proxy binding saved but the worker could not switch to it: " + err.Error()
+	}
+	respond(c, http.StatusOK, resp)
+}
@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// alertErrorStatus 告警通道或规则不存在时返回404，其余为请求参数错误
+func alertErrorStatus(err error) int {
+	if errors.Is(err, service.ErrAlertChannelNotFound) || errors.Is(err, service.ErrAlertRuleNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+// CreateAlertChannel 创建告警通道
+// @Summary Create Alert Channel
+// @Description Add a notification channel for fleet alerts: slack (Incoming Webhook url), telegram (bot_token, chat_id), email (SMTP smtp_host, smtp_port, from, to) or webhook (url receiving the incident JSON). Credentials are write-only.
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param request body model.AlertChannelRequest true "Alert Channel"
+// @Success 200 {object} model.APIResponse{data=model.AlertChannel}
+// @Router /alerts/channels [post]
+func (h *Handler) CreateAlertChannel(c *gin.Context) {
+	var req model.AlertChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	channel, err := h.manager.CreateAlertChannel(&req)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to save alert channel",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Alert channel saved successfully",
+		Data:    channel,
+	})
+}
+
+// UpdateAlertChannel 更新告警通道
+// @Summary Update Alert Channel
+// @Description Replace an alert channel's settings. Omitted bot_token or smtp_password keep the stored credential.
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param id path string true "Channel ID"
+// @Param request body model.AlertChannelRequest true "Alert Channel"
+// @Success 200 {object} model.APIResponse{data=model.AlertChannel}
+// @Router /alerts/channels/{id} [put]
+func (h *Handler) UpdateAlertChannel(c *gin.Context) {
+	var req model.AlertChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	channel, err := h.manager.UpdateAlertChannel(c.Param("id"), &req)
+	if err != nil {
+		respond(c, alertErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to save alert channel",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Alert channel saved successfully",
+		Data:    channel,
+	})
+}
+
+// ListAlertChannels 列出告警通道
+// @Summary List Alert Channels
+// @Description List notification channels for fleet alerts
+// @Tags Alert
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending"
+// @Success 200 {object} model.APIResponse{data=[]model.AlertChannel}
+// @Router /alerts/channels [get]
+func (h *Handler) ListAlertChannels(c *gin.Context) {
+	respondList(c, h.manager.ListAlertChannels(), "Alert channels retrieved successfully")
+}
+
+// DeleteAlertChannel 删除告警通道
+// @Summary Delete Alert Channel
+// @Description Delete an alert channel and detach it from routing rules
+// @Tags Alert
+// @Produce json
+// @Param id path string true "Channel ID"
+// @Success 200 {object} model.APIResponse
+// @Router /alerts/channels/{id} [delete]
+func (h *Handler) DeleteAlertChannel(c *gin.Context) {
+	if err := h.manager.DeleteAlertChannel(c.Param("id")); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrAlertChannelNotFound) {
+			status = http.StatusNotFound
+		}
+		respond(c, status, model.APIResponse{
+			Success: false,
+			Message: "Failed to delete alert channel",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Alert channel deleted successfully",
+	})
+}
+
+// TestAlertChannel 发送测试告警
+// @Summary Test Alert Channel
+// @Description Send a test notification through the channel and report whether it was delivered
+// @Tags Alert
+// @Produce json
+// @Param id path string true "Channel ID"
+// @Success 200 {object} model.APIResponse{data=model.AlertTestResult}
+// @Router /alerts/channels/{id}/test [post]
+func (h *Handler) TestAlertChannel(c *gin.Context) {
+	result, err := h.manager.TestAlertChannel(c.Param("id"))
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Alert channel not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if !result.Delivered {
+		respond(c, http.StatusBadGateway, model.APIResponse{
+			Success: false,
+			Message: "Test alert failed",
+			Error:   result.Error,
+			Data:    result,
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Test alert sent",
+		Data:    result,
+	})
+}
+
+// CreateAlertRule 创建告警路由规则
+// @Summary Create Alert Rule
+// @Description Route events to alert channels. A rule without events matches the ALERT_EVENTS types; account_ids, pool and owner_team narrow it to matching accounts. Rules apply in addition to the account owner channel and ALERT_WEBHOOK_URL.
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param request body model.AlertRuleRequest true "Alert Rule"
+// @Success 200 {object} model.APIResponse{data=model.AlertRule}
+// @Router /alerts/rules [post]
+func (h *Handler) CreateAlertRule(c *gin.Context) {
+	var req model.AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	rule, err := h.manager.CreateAlertRule(&req)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to save alert rule",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Alert rule saved successfully",
+		Data:    rule,
+	})
+}
+
+// UpdateAlertRule 更新告警路由规则
+// @Summary Update Alert Rule
+// @Description Replace an alert routing rule
+// @Tags Alert
+// @Accept json
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Param request body model.AlertRuleRequest true "Alert Rule"
+// @Success 200 {object} model.APIResponse{data=model.AlertRule}
+// @Router /alerts/rules/{id} [put]
+func (h *Handler) UpdateAlertRule(c *gin.Context) {
+	var req model.AlertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	rule, err := h.manager.UpdateAlertRule(c.Param("id"), &req)
+	if err != nil {
+		respond(c, alertErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to save alert rule",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Alert rule saved successfully",
+		Data:    rule,
+	})
+}
+
+// ListAlertRules 列出告警路由规则
+// @Summary List Alert Rules
+// @Description List alert routing rules
+// @Tags Alert
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending"
+// @Success 200 {object} model.APIResponse{data=[]model.AlertRule}
+// @Router /alerts/rules [get]
+func (h *Handler) ListAlertRules(c *gin.Context) {
+	respondList(c, h.manager.ListAlertRules(), "Alert rules retrieved successfully")
+}
+
+// DeleteAlertRule 删除告警路由规则
+// @Summary Delete Alert Rule
+// @Description Delete an alert routing rule
+// @Tags Alert
+// @Produce json
+// @Param id path string true "Rule ID"
+// @Success 200 {object} model.APIResponse
+// @Router /alerts/rules/{id} [delete]
+func (h *Handler) DeleteAlertRule(c *gin.Context) {
+	if err := h.manager.DeleteAlertRule(c.Param("id")); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrAlertRuleNotFound) {
+			status = http.StatusNotFound
+		}
+		respond(c, status, model.APIResponse{
+			Success: false,
+			Message: "Failed to delete alert rule",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Alert rule deleted successfully",
+	})
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// respondApprovalRequired 发送超过审批阈值时返回202和待审批记录
+func respondApprovalRequired(c *gin.Context, err error) bool {
+	var required *service.ApprovalRequiredError
+	if !errors.As(err, &required) {
+		return false
+	}
+	respond(c, http.StatusAccepted, model.APIResponse{
+		Success: true,
+		Message: "Send is waiting for approval",
+		Data:    required.Approval,
+		Warning: err.Error(),
+	})
+	return true
+}
+
+// approvalErrorStatus 审批不存在时返回404，已处理过返回409
+func approvalErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrApprovalNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrApprovalNotPending):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// ListApprovals 审批队列
+// @Summary List Approvals
+// @Description Campaigns and bulk sends (including broadcasts) with more recipients than APPROVAL_THRESHOLD, newest first. Each entry carries the message template and a sample of rendered messages spread across the recipient list.
+// @Tags Approval
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending (default -created_at)"
+// @Param filter[status] query string false "pending, approved, rejected or failed"
+// @Param filter[kind] query string false "campaign or bulk"
+// @Param filter[campaign_id] query string false "Campaign ID"
+// @Success 200 {object} model.APIResponse{data=[]model.Approval}
+// @Router /approvals [get]
+func (h *Handler) ListApprovals(c *gin.Context) {
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	approvals, total, err := h.manager.ListApprovals(q)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list approvals",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, approvals, buildListMeta(q, total, len(approvals)), "Approvals retrieved successfully")
+}
+
+// GetApproval 查询审批
+// @Summary Get Approval
+// @Description Get an approval with its sample messages and review result
+// @Tags Approval
+// @Produce json
+// @Param id path string true "Approval ID"
+// @Success 200 {object} model.APIResponse{data=model.Approval}
+// @Failure 404 {object} model.APIResponse
+// @Router /approvals/{id} [get]
+func (h *Handler) GetApproval(c *gin.Context) {
+	approval, err := h.manager.GetApproval(c.Param("id"))
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Approval not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Approval retrieved successfully",
+		Data:    approval,
+	})
+}
+
+// ApproveSend 批准发送
+// @Summary Approve Send
+// @Description Approve a pending campaign or bulk send and dispatch it: the campaign starts, a bulk send creates its batch (batch_id) from the original request. If dispatching fails the approval becomes failed; a campaign returns to draft and can be started again without a new approval.
+// @Tags Approval
+// @Accept json
+// @Produce json
+// @Param id path string true "Approval ID"
+// @Param request body model.ReviewApprovalRequest false "Review note"
+// @Success 200 {object} model.APIResponse{data=model.Approval}
+// @Failure 404 {object} model.APIResponse
+// @Failure 409 {object} model.APIResponse
+// @Router /approvals/{id}/approve [post]
+func (h *Handler) ApproveSend(c *gin.Context) {
+	var req model.ReviewApprovalRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	approval, err := h.manager.ApproveSend(c.Param("id"), req.Note)
+	if approval == nil {
+		respond(c, approvalErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to approve send",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		respond(c, http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: "Approved send failed to start",
+			Data:    approval,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Send approved",
+		Data:    approval,
+	})
+}
+
+// RejectSend 拒绝发送
+// @Summary Reject Send
+// @Description Reject a pending campaign or bulk send. Nothing is sent; a campaign returns to draft with the note in last_error.
+// @Tags Approval
+// @Accept json
+// @Produce json
+// @Param id path string true "Approval ID"
+// @Param request body model.ReviewApprovalRequest false "Review note"
+// @Success 200 {object} model.APIResponse{data=model.Approval}
+// @Failure 404 {object} model.APIResponse
+// @Failure 409 {object} model.APIResponse
+// @Router /approvals/{id}/reject [post]
+func (h *Handler) RejectSend(c *gin.Context) {
+	var req model.ReviewApprovalRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	approval, err := h.manager.RejectSend(c.Param("id"), req.Note)
+	if err != nil {
+		respond(c, approvalErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to reject send",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Send rejected",
+		Data:    approval,
+	})
+}
@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/logging"
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+)
+
+const (
+	// auditResponseKey gin上下文中保存响应内容的键，审计记录从中取出结果消息
+	auditResponseKey = "audit_response"
+	// maxAuditBody 审计时最多读取的请求体字节数，超过时只记录大小
+	maxAuditBody = 8 << 10
+	// maxAuditSummary 审计记录中请求摘要的最大长度
+	maxAuditSummary = 1000
+)
+
+// auditBody 审计读取部分请求体后，拼接剩余内容还原请求体
+type auditBody struct {
+	io.Reader
+	io.Closer
+}
+
+// readOnlyPostRoutes 使用POST提交但只读取数据的接口，不记录审计
+var readOnlyPostRoutes = map[string]bool{
+	"POST /api/v1/graphql": true,
+}
+
+// auditLog 记录所有写操作（POST/PUT/PATCH/DELETE）的调用方、接口、请求摘要和结果
+func (h *Handler) auditLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		switch c.Request.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		default:
+			c.Next()
+			return
+		}
+		if readOnlyPostRoutes[c.Request.Method+" "+c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		started := time.Now()
+		summary, body := auditRequestSummary(c)
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		entry := &model.AuditEntry{
+			RequestID:  middleware.GetRequestID(c),
+			Actor:      auditActor(c),
+			APIKeyID:   middleware.APIKeyID(c),
+			Method:     c.Request.Method,
+			Route:      route,
+			Path:       c.Request.URL.RequestURI(),
+			Request:    summary,
+			Status:     c.Writer.Status(),
+			Success:    c.Writer.Status() < http.StatusBadRequest,
+			Result:     auditResult(c),
+			ClientIP:   c.ClientIP(),
+			DurationMs: time.Since(started).Milliseconds(),
+			CreatedAt:  started,
+		}
+		entry.TenantID, _ = middleware.TenantID(c)
+		if strings.Contains(route, "/accounts/:id") {
+			entry.AccountID = c.Param("id")
+		} else if accountID, ok := body["account_id"].(string); ok {
+			entry.AccountID = accountID
+		}
+		h.manager.RecordAudit(entry)
+	}
+}
+
+// auditActor 调用方身份：租户API Key、管理员token，未开启鉴权时为anonymous
+func auditActor(c *gin.Context) string {
+	switch {
+	case middleware.APIKeyID(c) != "":
+		return "api_key"
+	case middleware.IsAdmin(c):
+		return "admin"
+	default:
+		return "anonymous"
+	}
+}
+
+// auditResult 取出响应消息，失败时附带错误；代理到Worker的请求只记录HTTP状态
+func auditResult(c *gin.Context) string {
+	value, ok := c.Get(auditResponseKey)
+	if !ok {
+		return http.StatusText(c.Writer.Status())
+	}
+	resp := value.(model.APIResponse)
+	if resp.Error != "" {
+		return resp.Message + ": " + resp.Error
+	}
+	return resp.Message
+}
+
+// auditRequestSummary 读取请求体生成脱敏后的摘要，JSON以外的请求体只记录类型和大小
+func auditRequestSummary(c *gin.Context) (string, map[string]interface{}) {
+	if c.Request.Body == nil || c.Request.ContentLength == 0 {
+		return "", nil
+	}
+
+	contentType := c.ContentType()
+	if contentType != "application/json" {
+		return fmt.Sprintf("%s (%d bytes)", contentType, c.Request.ContentLength), nil
+	}
+
+	prefix, _ := io.ReadAll(io.LimitReader(c.Request.Body, maxAuditBody+1))
+	c.Request.Body = auditBody{Reader: io.MultiReader(bytes.NewReader(prefix), c.Request.Body), Closer: c.Request.Body}
+	if len(prefix) > maxAuditBody {
+		return fmt.Sprintf("%s (more than %d bytes)", contentType, maxAuditBody), nil
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(prefix, &body); err != nil {
+		return fmt.Sprintf("invalid JSON (%d bytes)", len(prefix)), nil
+	}
+	raw, _ := json.Marshal(logging.Redact(body))
+	if len(raw) > maxAuditSummary {
+		return string(raw[:maxAuditSummary]) + "...(truncated)", body
+	}
+	return string(raw), body
+}
+
+// ListAuditLog 查询审计日志
+// @Summary List Audit Log
+// @Description List recorded POST/PUT/PATCH/DELETE calls with the caller, endpoint, redacted request summary and result, newest first
+// @Tags System
+// @Produce json
+// @Param since query string false "Only entries at or after this time (RFC3339)"
+// @Param until query string false "Only entries before this time (RFC3339)"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending (default -created_at)"
+// @Param filter[actor] query string false "admin, api_key or anonymous"
+// @Param filter[api_key_id] query string false "Tenant API key ID"
+// @Param filter[tenant_id] query string false "Tenant ID"
+// @Param filter[method] query string false "HTTP method"
+// @Param filter[route] query string false "Route template, e.g. /api/v1/accounts/:id/stop"
+// @Param filter[account_id] query string false "Account ID"
+// @Param filter[status] query string false "HTTP status code"
+// @Param filter[success] query bool false "Only successful or only failed calls"
+// @Success 200 {object} model.APIResponse{data=[]model.AuditEntry}
+// @Router /audit [get]
+func (h *Handler) ListAuditLog(c *gin.Context) {
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	since, until, err := parseTimeRange(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid time range",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	entries, total, err := h.manager.ListAuditEntries(q, since, until)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list audit log",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, entries, buildListMeta(q, total, len(entries)), "Audit log retrieved successfully")
+}
+
+// ListWorkerCalls 查询账号的Worker调用记录
+// @Summary List Worker Calls
+// @Description Every call made to the account's worker, newest first: API requests proxied to the worker (source proxy) and calls the master makes itself, such as sends and logins (source master). Each entry has the method, worker path, worker status code (0 when the worker could not be reached), latency, request body size and the SHA-256 of its first 64 KiB; bodies are not stored. Use it to reconstruct what an account did before a ban. Kept for AUDIT_WORKER_CALL_RETENTION_DAYS and at most AUDIT_WORKER_CALL_MAX_PER_ACCOUNT entries per account.
+// @Tags Account
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param since query string false "Only calls at or after this time (RFC3339)"
+// @Param until query string false "Only calls before this time (RFC3339)"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending (default -created_at)"
+// @Param filter[source] query string false "proxy or master"
+// @Param filter[method] query string false "HTTP method"
+// @Param filter[path] query string false "Worker path, e.g. /api/send-message"
+// @Param filter[status] query string false "Worker status code"
+// @Param filter[request_id] query string false "Request ID"
+// @Success 200 {object} model.APIResponse{data=[]model.WorkerCall}
+// @Failure 404 {object} model.APIResponse
+// @Router /accounts/{id}/worker-calls [get]
+func (h *Handler) ListWorkerCalls(c *gin.Context) {
+	accountID := c.Param("id")
+	if _, err := h.manager.GetAccount(accountID); err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	since, until, err := parseTimeRange(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid time range",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	calls, total, err := h.manager.ListWorkerCalls(accountID, q, since, until)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list worker calls",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, calls, buildListMeta(q, total, len(calls)), "Worker calls retrieved successfully")
+}
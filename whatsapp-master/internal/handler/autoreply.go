@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// autoReplyErrorStatus 规则不存在返回404，其余为校验错误
+func autoReplyErrorStatus(err error) int {
+	if errors.Is(err, service.ErrAutoReplyRuleNotFound) {
+		return http.StatusNotFound
+	}
+	return http.StatusBadRequest
+}
+
+// CreateAutoReplyRule 创建自动回复规则
+// @Summary Create Auto-Reply Rule
+// @Description Add an auto-reply rule to an account. Inbound messages collected by the inbox collector are matched against the account's enabled rules by priority (lowest first). keyword rules match when the message contains any keyword, ignoring case; regex rules match the pattern, and its named groups can be used in the reply next to {{contact}} and {{message}}. A rule is skipped during its quiet hours (local time, start equal to end means none) and once it has replied max_replies_per_contact times to the contact within AUTO_REPLY_WINDOW_HOURS. The first rule left sends its reply through the send queue.
+// @Tags AutoReply
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param request body model.AutoReplyRuleRequest true "Auto-Reply Rule"
+// @Success 200 {object} model.APIResponse{data=model.AutoReplyRule}
+// @Failure 400 {object} model.APIResponse
+// @Failure 404 {object} model.APIResponse
+// @Router /accounts/{id}/auto-replies [post]
+func (h *Handler) CreateAutoReplyRule(c *gin.Context) {
+	var req model.AutoReplyRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if !h.accountExists(c, c.Param("id")) {
+		return
+	}
+
+	rule, err := h.manager.CreateAutoReplyRule(c.Param("id"), &req)
+	if err != nil {
+		respond(c, autoReplyErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to save auto-reply rule",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Auto-reply rule saved successfully",
+		Data:    rule,
+	})
+}
+
+// UpdateAutoReplyRule 更新自动回复规则
+// @Summary Update Auto-Reply Rule
+// @Description Replace an auto-reply rule of the account
+// @Tags AutoReply
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param rule_id path string true "Rule ID"
+// @Param request body model.AutoReplyRuleRequest true "Auto-Reply Rule"
+// @Success 200 {object} model.APIResponse{data=model.AutoReplyRule}
+// @Failure 400 {object} model.APIResponse
+// @Failure 404 {object} model.APIResponse
+// @Router /accounts/{id}/auto-replies/{rule_id} [put]
+func (h *Handler) UpdateAutoReplyRule(c *gin.Context) {
+	var req model.AutoReplyRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	rule, err := h.manager.UpdateAutoReplyRule(c.Param("id"), c.Param("rule_id"), &req)
+	if err != nil {
+		respond(c, autoReplyErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to save auto-reply rule",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Auto-reply rule saved successfully",
+		Data:    rule,
+	})
+}
+
+// ListAutoReplyRules 列出自动回复规则
+// @Summary List Auto-Reply Rules
+// @Description List the account's auto-reply rules in matching order
+// @Tags AutoReply
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending"
+// @Success 200 {object} model.APIResponse{data=[]model.AutoReplyRule}
+// @Router /accounts/{id}/auto-replies [get]
+func (h *Handler) ListAutoReplyRules(c *gin.Context) {
+	if !h.accountExists(c, c.Param("id")) {
+		return
+	}
+	rules, err := h.manager.ListAutoReplyRules(c.Param("id"))
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to list auto-reply rules",
+			Error:   err.Error(),
+		})
+		return
+	}
+	respondList(c, rules, "Auto-reply rules retrieved successfully")
+}
+
+// DeleteAutoReplyRule 删除自动回复规则
+// @Summary Delete Auto-Reply Rule
+// @Description Delete an auto-reply rule of the account; its reply log is kept
+// @Tags AutoReply
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param rule_id path string true "Rule ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 404 {object} model.APIResponse
+// @Router /accounts/{id}/auto-replies/{rule_id} [delete]
+func (h *Handler) DeleteAutoReplyRule(c *gin.Context) {
+	if err := h.manager.DeleteAutoReplyRule(c.Param("id"), c.Param("rule_id")); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrAutoReplyRuleNotFound) {
+			status = http.StatusNotFound
+		}
+		respond(c, status, model.APIResponse{
+			Success: false,
+			Message: "Failed to delete auto-reply rule",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Auto-reply rule deleted successfully",
+	})
+}
+
+// ListAutoReplyLogs 查询自动回复记录
+// @Summary List Auto-Replies
+// @Description List the replies sent by the account's auto-reply rules, newest first, with their status (queued, sent, failed or dropped when the send queue was full)
+// @Tags AutoReply
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields (created_at), prefix with - for descending"
+// @Param filter[rule_id] query string false "Only replies of this rule"
+// @Param filter[contact] query string false "Only replies to this contact"
+// @Param filter[status] query string false "queued, sent, failed or dropped"
+// @Success 200 {object} model.APIResponse{data=[]model.AutoReplyLog}
+// @Router /accounts/{id}/auto-replies/log [get]
+func (h *Handler) ListAutoReplyLogs(c *gin.Context) {
+	if !h.accountExists(c, c.Param("id")) {
+		return
+	}
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	logs, total, err := h.manager.ListAutoReplyLogs(c.Param("id"), q)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list auto-replies",
+			Error:   err.Error(),
+		})
+		return
+	}
+	respondPage(c, logs, buildListMeta(q, total, len(logs)), "Auto-replies retrieved successfully")
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+)
+
+// ListBackups 会话备份记录
+// @Summary List Session Backups
+// @Description Session directory backups, newest first, including failed attempts. Each successful backup is a tar.gz of the account's session directory stored under key in destination. Create an account with restore_backup=true to start its worker from the latest successful backup.
+// @Tags Account
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending (default -created_at)"
+// @Param filter[account_id] query string false "Account IDs (comma separated)"
+// @Param filter[status] query string false "succeeded or failed"
+// @Param filter[trigger] query string false "scheduled or manual"
+// @Success 200 {object} model.APIResponse{data=[]model.SessionBackup}
+// @Router /backups [get]
+func (h *Handler) ListBackups(c *gin.Context) {
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	filter := &model.BackupFilter{}
+	if tenantID, scoped := middleware.TenantID(c); scoped {
+		filter.AccountIDs = h.manager.TenantAccountIDs(tenantID)
+	}
+
+	records, total, err := h.manager.ListBackups(filter, q)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list backups",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, records, buildListMeta(q, total, len(records)), "Backups retrieved successfully")
+}
+
+// GetBackupStatus 获取定期备份状态
+// @Summary Get Backup Status
+// @Description Get the backup schedule, destination, retention policy, the next scheduled run and the result of the last run
+// @Tags System
+// @Produce json
+// @Success 200 {object} model.APIResponse{data=model.BackupStatus}
+// @Router /system/backups [get]
+func (h *Handler) GetBackupStatus(c *gin.Context) {
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Backup status retrieved successfully",
+		Data:    h.manager.GetBackupStatus(),
+	})
+}
+
+// RunBackup 立即备份会话目录
+// @Summary Run Backup
+// @Description Back up the session directories of the given accounts, or of all logged-in, enabled accounts when account_ids is empty, to BACKUP_DESTINATION, then apply the retention policy. Accounts that are not logged in are skipped so a backup never replaces a working session with an empty one. Runs as a backup job; poll /jobs/{id} for progress and the result.
+// @Tags System
+// @Accept json
+// @Produce json
+// @Param request body model.RunBackupRequest false "Backup Request"
+// @Success 202 {object} model.APIResponse{data=model.Job}
+// @Failure 409 {object} model.APIResponse
+// @Router /system/backups/run [post]
+func (h *Handler) RunBackup(c *gin.Context) {
+	var req model.RunBackupRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	job, err := h.manager.RunBackup(req.AccountIDs, model.BackupTriggerManual)
+	if err != nil {
+		respond(c, http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: "Failed to start backup",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusAccepted, model.APIResponse{
+		Success: true,
+		Message: "Backup started",
+		Data:    job,
+	})
+}
@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// ListBans 封号记录
+// @Summary List Ban Records
+// @Description Accounts quarantined because a ban signal was detected, newest first. A ban signal is a worker error or logout reason matching BAN_ERROR_PATTERNS, or BAN_LOGOUT_LOOP_COUNT session drops within BAN_LOGOUT_LOOP_WINDOW_MINUTES. evidence holds the failing worker call, the logout times, the recent status history and the worker status at detection, for the appeal.
+// @Tags Account
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending (default -created_at)"
+// @Param filter[account_id] query string false "Account IDs (comma separated)"
+// @Param filter[status] query string false "active or released"
+// @Param filter[source] query string false "worker_error, logout or logout_loop"
+// @Success 200 {object} model.APIResponse{data=[]model.BanRecord}
+// @Router /bans [get]
+func (h *Handler) ListBans(c *gin.Context) {
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	filter := &model.BanFilter{}
+	if tenantID, scoped := middleware.TenantID(c); scoped {
+		filter.AccountIDs = h.manager.TenantAccountIDs(tenantID)
+	}
+
+	records, total, err := h.manager.ListBans(filter, q)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list ban records",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, records, buildListMeta(q, total, len(records)), "Ban records retrieved successfully")
+}
+
+// ReleaseBan 解除账号隔离
+// @Summary Release Ban
+// @Description Take a quarantined account out of the banned status, for example after a successful appeal. The ban record is kept as released with the note. The account is enabled again unless it was disabled before the ban, and its status is refreshed from the worker.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param request body model.ReleaseBanRequest false "Release Request"
+// @Success 200 {object} model.APIResponse{data=model.BanRecord}
+// @Failure 404 {object} model.APIResponse
+// @Failure 409 {object} model.APIResponse
+// @Router /accounts/{id}/ban/release [post]
+func (h *Handler) ReleaseBan(c *gin.Context) {
+	var req model.ReleaseBanRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	if _, err := h.manager.GetAccount(c.Param("id")); err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	record, err := h.manager.ReleaseBan(c.Request.Context(), c.Param("id"), req.Note)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrAccountNotBanned) {
+			status = http.StatusConflict
+		}
+		respond(c, status, model.APIResponse{
+			Success: false,
+			Message: "Failed to release ban",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Ban released successfully",
+		Data:    record,
+	})
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/validation"
+)
+
+// registerFieldNames 让校验错误使用请求中的JSON字段名而不是Go字段名
+func registerFieldNames() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(validation.JSONFieldName)
+	}
+}
+
+// respondBindError 请求绑定失败时返回400，data中列出每个出错的字段、失败的规则和收到的值类型
+func respondBindError(c *gin.Context, err error) {
+	respond(c, http.StatusBadRequest, model.APIResponse{
+		Success: false,
+		Message: "Invalid request format",
+		Data:    validation.FromBindError(err),
+		Error:   err.Error(),
+	})
+}
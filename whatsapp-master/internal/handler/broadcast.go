@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+)
+
+// Broadcast 全局广播
+// @Summary Broadcast Message
+// @Description Send a message to a contact list using all logged-in accounts, or the ones matching senders (account_ids, pool, tags), as senders. Contacts are deduplicated (ignoring +, spaces, dashes and @c.us) and assigned round-robin. Returns a consolidated report; poll GET /broadcast/{id} for progress. Above APPROVAL_THRESHOLD recipients it returns 202 with a pending approval instead.
+// @Tags Message
+// @Accept json
+// @Produce json
+// @Param request body model.BroadcastRequest true "Broadcast Request"
+// @Success 200 {object} model.APIResponse{data=model.BroadcastReport}
+// @Success 202 {object} model.APIResponse{data=model.Approval}
+// @Failure 400 {object} model.APIResponse
+// @Router /broadcast [post]
+func (h *Handler) Broadcast(c *gin.Context) {
+	var req model.BroadcastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if !h.normalizePhones(c, &req) {
+		return
+	}
+
+	tenantID, _ := middleware.TenantID(c)
+	var accountIDs []string
+	if req.Senders != nil {
+		accountIDs = req.Senders.AccountIDs
+	}
+	if !h.allowSend(c, "Failed to start broadcast", accountIDs...) {
+		return
+	}
+
+	report, err := h.manager.Broadcast(&req, tenantID)
+	if respondApprovalRequired(c, err) {
+		return
+	}
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to start broadcast",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Broadcast started",
+		Data:    report,
+	})
+}
+
+// GetBroadcastReport 获取广播汇总报告
+// @Summary Get Broadcast Report
+// @Description Progress of a broadcast with per-sender counts and failures grouped by error. Per-recipient results are available from GET /send-bulk/{id}. Like bulk batches, broadcasts are kept in memory only and are removed BULK_BATCH_RETENTION_HOURS after they finish.
+// @Tags Message
+// @Produce json
+// @Param id path string true "Broadcast (bulk batch) ID"
+// @Success 200 {object} model.APIResponse{data=model.BroadcastReport}
+// @Failure 404 {object} model.APIResponse
+// @Router /broadcast/{id} [get]
+func (h *Handler) GetBroadcastReport(c *gin.Context) {
+	report, err := h.manager.BroadcastReport(c.Param("id"))
+	if err == nil {
+		accountIDs := make([]string, 0, len(report.Accounts))
+		for _, account := range report.Accounts {
+			accountIDs = append(accountIDs, account.AccountID)
+		}
+		if !h.ownsAccounts(c, accountIDs) {
+			err = fmt.Errorf("broadcast %s not found", report.ID)
+		}
+	}
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Broadcast not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Broadcast report retrieved successfully",
+		Data:    report,
+	})
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+)
+
+// SendBulk 批量发送消息
+// @Summary Send Bulk Messages
+// @Description Fan out a templated message to a list of contacts across logged-in accounts. With more recipients than APPROVAL_THRESHOLD nothing is sent yet: the response is 202 with the pending approval, and the batch starts once it is approved via /approvals/{id}/approve.
+// @Tags Message
+// @Accept json
+// @Produce json
+// @Param request body model.BulkSendRequest true "Bulk Send Request"
+// @Param Idempotency-Key header string false "Retries with the same key return the first response instead of sending again"
+// @Success 200 {object} model.APIResponse{data=model.BulkBatch}
+// @Success 202 {object} model.APIResponse{data=model.Approval}
+// @Router /send-bulk [post]
+func (h *Handler) SendBulk(c *gin.Context) {
+	var req model.BulkSendRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if !h.normalizePhones(c, &req) {
+		return
+	}
+
+	if tenantID, scoped := middleware.TenantID(c); scoped {
+		// 未指定账号时只使用本租户的账号
+		if len(req.AccountIDs) == 0 {
+			req.AccountIDs = h.manager.TenantAccountIDs(tenantID)
+			if owner := h.sendOwner(c); owner != "" {
+				// API Key绑定了负责人时只使用其名下的账号
+				owned := make([]string, 0, len(req.AccountIDs))
+				for _, accountID := range req.AccountIDs {
+					if h.manager.AccountOwnedBy(accountID, owner) {
+						owned = append(owned, accountID)
+					}
+				}
+				req.AccountIDs = owned
+			}
+			if len(req.AccountIDs) == 0 {
+				respond(c, http.StatusBadRequest, model.APIResponse{
+					Success: false,
+					Message: "Failed to start bulk send",
+					Error:   "tenant has no accounts",
+				})
+				return
+			}
+		}
+		for _, accountID := range req.AccountIDs {
+			if !h.authorizeAccount(c, accountID) {
+				return
+			}
+		}
+	}
+
+	if !h.allowSend(c, "Failed to start bulk send", req.AccountIDs...) {
+		return
+	}
+
+	batch, err := h.manager.SendBulk(&req)
+	if respondApprovalRequired(c, err) {
+		return
+	}
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to start bulk send",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Bulk send started",
+		Data:    batch,
+	})
+}
+
+// GetBulkBatch 获取批量发送结果
+// @Summary Get Bulk Batch
+// @Description Get progress and per-recipient results of a bulk send batch. Batches are kept in memory only: they are removed BULK_BATCH_RETENTION_HOURS after they finish and are lost when the Master restarts. The bulk_send job keeps the final counts.
+// @Tags Message
+// @Produce json
+// @Param id path string true "Batch ID"
+// @Success 200 {object} model.APIResponse{data=model.BulkBatch}
+// @Failure 404 {object} model.APIResponse
+// @Router /send-bulk/{id} [get]
+func (h *Handler) GetBulkBatch(c *gin.Context) {
+	batch, err := h.manager.GetBulkBatch(c.Param("id"))
+	if err == nil && !h.ownsAccounts(c, batch.AccountIDs) {
+		err = fmt.Errorf("bulk batch %s not found", batch.ID)
+	}
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Bulk batch not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Bulk batch retrieved successfully",
+		Data:    batch,
+	})
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// CreateCampaign 创建活动
+// @Summary Create Campaign
+// @Description Create a draft campaign: a named set of accounts, a target contact list and a message template. Use /campaigns/{id}/start to begin sending.
+// @Tags Campaign
+// @Accept json
+// @Produce json
+// @Param request body model.CreateCampaignRequest true "Create Campaign Request"
+// @Success 200 {object} model.APIResponse{data=model.Campaign}
+// @Router /campaigns [post]
+func (h *Handler) CreateCampaign(c *gin.Context) {
+	var req model.CreateCampaignRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if !h.normalizePhones(c, &req) {
+		return
+	}
+
+	campaign, err := h.manager.CreateCampaign(&req)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to create campaign",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Campaign created successfully",
+		Data:    campaign,
+	})
+}
+
+// ListCampaigns 列出活动
+// @Summary List Campaigns
+// @Description List campaigns with their progress
+// @Tags Campaign
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending"
+// @Param filter[status] query string false "draft, pending_approval, running, paused or completed"
+// @Success 200 {object} model.APIResponse{data=[]model.Campaign}
+// @Router /campaigns [get]
+func (h *Handler) ListCampaigns(c *gin.Context) {
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	campaigns, total, err := h.manager.ListCampaigns(q)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list campaigns",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, campaigns, buildListMeta(q, total, len(campaigns)), "Campaigns retrieved successfully")
+}
+
+// GetCampaign 获取活动进度
+// @Summary Get Campaign
+// @Description Get a campaign and its per-status recipient counts
+// @Tags Campaign
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} model.APIResponse{data=model.Campaign}
+// @Router /campaigns/{id} [get]
+func (h *Handler) GetCampaign(c *gin.Context) {
+	campaign, err := h.manager.GetCampaign(c.Param("id"))
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Campaign not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Campaign retrieved successfully",
+		Data:    campaign,
+	})
+}
+
+// ListCampaignRecipients 列出活动收件人
+// @Summary List Campaign Recipients
+// @Description List recipients of a campaign with their send results
+// @Tags Campaign
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending"
+// @Param filter[status] query string false "pending, sending, sent, failed or skipped"
+// @Param filter[account_id] query string false "Sending account"
+// @Success 200 {object} model.APIResponse{data=[]model.CampaignRecipient}
+// @Router /campaigns/{id}/recipients [get]
+func (h *Handler) ListCampaignRecipients(c *gin.Context) {
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	recipients, total, err := h.manager.ListCampaignRecipients(c.Param("id"), q)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list campaign recipients",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, recipients, buildListMeta(q, total, len(recipients)), "Campaign recipients retrieved successfully")
+}
+
+// StartCampaign 开始或继续活动
+// @Summary Start Campaign
+// @Description Start a draft campaign or resume a paused one. Pending recipients are distributed across the campaign's logged-in accounts; opted-out contacts are skipped. A draft with more recipients than APPROVAL_THRESHOLD becomes pending_approval and the response is 202 with the approval; it starts once approved.
+// @Tags Campaign
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} model.APIResponse{data=model.Campaign}
+// @Success 202 {object} model.APIResponse{data=model.Approval}
+// @Router /campaigns/{id}/start [post]
+func (h *Handler) StartCampaign(c *gin.Context) {
+	campaign, err := h.manager.StartCampaign(c.Param("id"))
+	if respondApprovalRequired(c, err) {
+		return
+	}
+	if err != nil {
+		respond(c, http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: "Failed to start campaign",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Campaign started",
+		Data:    campaign,
+	})
+}
+
+// PauseCampaign 暂停活动
+// @Summary Pause Campaign
+// @Description Pause a running campaign. Messages already being sent are completed; the rest stay pending until the campaign is started again.
+// @Tags Campaign
+// @Produce json
+// @Param id path string true "Campaign ID"
+// @Success 200 {object} model.APIResponse{data=model.Campaign}
+// @Router /campaigns/{id}/pause [post]
+func (h *Handler) PauseCampaign(c *gin.Context) {
+	campaign, err := h.manager.PauseCampaign(c.Param("id"))
+	if err != nil {
+		respond(c, http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: "Failed to pause campaign",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Campaign paused",
+		Data:    campaign,
+	})
+}
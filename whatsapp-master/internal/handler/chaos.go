@@ -0,0 +1,137 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// ListChaosFaults 列出生效中的故障注入
+// @Summary List Chaos Faults
+// @Description List active worker fault injections (requires CHAOS_ENABLED and X-Admin-Token)
+// @Tags Chaos
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending"
+// @Param filter[account_id] query string false "Account ID"
+// @Success 200 {object} model.APIResponse{data=[]model.ChaosFault}
+// @Router /chaos/faults [get]
+func (h *Handler) ListChaosFaults(c *gin.Context) {
+	respondList(c, h.manager.ListWorkerFaults(), "Chaos faults retrieved successfully")
+}
+
+// InjectChaosDelay 为账号的Worker调用注入延迟或随机失败
+// @Summary Inject Worker Delay
+// @Description Delay (and optionally fail) all master-to-worker calls of an account for a period of time
+// @Tags Chaos
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Account ID"
+// @Param request body model.ChaosDelayRequest true "Fault"
+// @Success 200 {object} model.APIResponse{data=model.ChaosFault}
+// @Router /chaos/accounts/{id}/delay [post]
+func (h *Handler) InjectChaosDelay(c *gin.Context) {
+	var req model.ChaosDelayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	fault, err := h.manager.InjectWorkerFault(c.Param("id"), &req)
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Fault injected successfully",
+		Data:    fault,
+	})
+}
+
+// ClearChaosFault 清除账号上的故障注入
+// @Summary Clear Worker Fault
+// @Description Remove an injected delay/failure from an account
+// @Tags Chaos
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Account ID"
+// @Success 200 {object} model.APIResponse
+// @Router /chaos/accounts/{id}/delay [delete]
+func (h *Handler) ClearChaosFault(c *gin.Context) {
+	h.manager.ClearWorkerFault(c.Param("id"))
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Fault cleared successfully",
+	})
+}
+
+// KillChaosWorker 强制杀掉账号的Worker容器
+// @Summary Kill Worker
+// @Description Kill the worker container of an account without updating its status, to exercise failure detection
+// @Tags Chaos
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Account ID"
+// @Success 200 {object} model.APIResponse
+// @Router /chaos/accounts/{id}/kill [post]
+func (h *Handler) KillChaosWorker(c *gin.Context) {
+	if err := h.manager.KillWorker(c.Param("id")); err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to kill worker",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Worker killed successfully",
+	})
+}
+
+// ForceChaosStatus 强制切换账号状态
+// @Summary Force Account Status
+// @Description Force an account into a status (e.g. disconnected) to test alerting and failover. The status poller may restore the real status.
+// @Tags Chaos
+// @Accept json
+// @Produce json
+// @Param X-Admin-Token header string true "Admin token"
+// @Param id path string true "Account ID"
+// @Param request body model.ChaosStatusRequest true "Status"
+// @Success 200 {object} model.APIResponse{data=model.Account}
+// @Router /chaos/accounts/{id}/status [post]
+func (h *Handler) ForceChaosStatus(c *gin.Context) {
+	var req model.ChaosStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	account, err := h.manager.ForceAccountStatus(c.Param("id"), req.Status)
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Account status forced successfully",
+		Data:    account,
+	})
+}
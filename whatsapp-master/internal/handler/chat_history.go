@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/validation"
+)
+
+// chatHistoryColumns 导出CSV的列
+var chatHistoryColumns = []string{"id", "timestamp", "direction", "contact", "type", "body", "status", "worker_message_id"}
+
+// GetChatHistory 获取与联系人的会话历史
+// @Summary Get Chat History
+// @Description Get one page of the conversation with a contact, oldest message first. With refresh (default true) the worker scrapes the page from WhatsApp Web first and messages missing from the master database are cached there; if the worker is unavailable the cached history is returned with a warning. Pass next_before from the response as before to page back to older messages. format=csv returns the page as a CSV attachment.
+// @Tags Message
+// @Produce json
+// @Produce text/csv
+// @Param id path string true "Account ID"
+// @Param contact path string true "Contact phone number or chat ID"
+// @Param limit query int false "Page size (default 50, max 500)"
+// @Param before query string false "next_before from the previous page"
+// @Param refresh query bool false "Scrape the page from the worker first (default true)"
+// @Param format query string false "json (default) or csv"
+// @Success 200 {object} model.APIResponse{data=model.ChatHistory}
+// @Failure 400 {object} model.APIResponse
+// @Failure 404 {object} model.APIResponse
+// @Router /accounts/{id}/chats/{contact}/messages [get]
+func (h *Handler) GetChatHistory(c *gin.Context) {
+	accountID := c.Param("id")
+	contact, ok := h.chatContact(c)
+	if !ok {
+		return
+	}
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+
+	limit := 0
+	if raw := c.Query("limit"); raw != "" {
+		parsed, err := strconv.Atoi(raw)
+		if err != nil || parsed < 0 {
+			respond(c, http.StatusBadRequest, model.APIResponse{
+				Success: false,
+				Message: "Invalid list query",
+				Error:   fmt.Sprintf("invalid limit: %s", raw),
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	if !h.accountExists(c, accountID) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 60*time.Second)
+	defer cancel()
+
+	history, warning, err := h.manager.GetChatHistory(ctx, accountID, contact, c.Query("before"), limit, c.Query("refresh") != "false")
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to get chat history",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if format == "csv" {
+		respondChatExport(c, accountID, contact, format, history.Messages)
+		return
+	}
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Chat history retrieved successfully",
+		Data:    history,
+		Warning: warning,
+	})
+}
+
+// ExportChatHistory 导出与联系人的会话历史
+// @Summary Export Chat History
+// @Description Download the whole conversation with a contact cached in the master database as a JSON or CSV attachment, oldest message first, for archival or CRM import. With store=true the file is written to the object storage (STORAGE_DESTINATION) under exports/ and a signed download URL is returned instead. Only cached messages are exported; page through GET /accounts/{id}/chats/{contact}/messages first to backfill older history from the worker.
+// @Tags Message
+// @Produce json
+// @Produce text/csv
+// @Param id path string true "Account ID"
+// @Param contact path string true "Contact phone number or chat ID"
+// @Param format query string false "json (default) or csv"
+// @Param since query string false "Only messages at or after this time (RFC3339)"
+// @Param until query string false "Only messages at or before this time (RFC3339)"
+// @Param store query bool false "Write the export to the object storage and return its key and a signed download URL instead of the attachment"
+// @Success 200 {array} model.Message
+// @Success 201 {object} model.APIResponse{data=model.StoredObject}
+// @Failure 400 {object} model.APIResponse
+// @Failure 404 {object} model.APIResponse
+// @Router /accounts/{id}/chats/{contact}/export [get]
+func (h *Handler) ExportChatHistory(c *gin.Context) {
+	accountID := c.Param("id")
+	contact, ok := h.chatContact(c)
+	if !ok {
+		return
+	}
+	format, ok := exportFormat(c)
+	if !ok {
+		return
+	}
+	since, until, err := parseTimeRange(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid time range",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if !h.accountExists(c, accountID) {
+		return
+	}
+
+	messages, err := h.manager.ExportChatHistory(accountID, contact, since, until)
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get chat history",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if c.Query("store") == "true" {
+		h.storeChatExport(c, accountID, contact, format, messages)
+		return
+	}
+	respondChatExport(c, accountID, contact, format, messages)
+}
+
+// chatContact 规范化路径中的联系人，号码无效时返回400
+func (h *Handler) chatContact(c *gin.Context) (string, bool) {
+	contact, err := validation.NormalizeContact(c.Param("contact"), h.manager.DefaultCountryCode())
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid phone number",
+			Error:   err.Error(),
+		})
+		return "", false
+	}
+	return contact, true
+}
+
+// exportFormat 解析 format 参数，只支持json和csv
+func exportFormat(c *gin.Context) (string, bool) {
+	format := strings.ToLower(c.DefaultQuery("format", "json"))
+	if format != "json" && format != "csv" {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid export format",
+			Error:   fmt.Sprintf("unsupported format %s, use json or csv", format),
+		})
+		return "", false
+	}
+	return format, true
+}
+
+// respondChatExport 以附件形式返回消息列表
+func respondChatExport(c *gin.Context, accountID, contact, format string, messages []*model.Message) {
+	filename, contentType, data := renderChatExport(accountID, contact, format, messages)
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, contentType, data)
+}
+
+// storeChatExport 将导出文件写入对象存储，返回签名下载地址
+func (h *Handler) storeChatExport(c *gin.Context, accountID, contact, format string, messages []*model.Message) {
+	filename, _, data := renderChatExport(accountID, contact, format, messages)
+	tenantID, _ := middleware.TenantID(c)
+	object, err := h.manager.StoreExport(c.Request.Context(), tenantID, filename, data)
+	if err != nil {
+		respond(c, http.StatusServiceUnavailable, model.APIResponse{
+			Success: false,
+			Message: "Failed to store object",
+			Error:   err.Error(),
+		})
+		return
+	}
+	respond(c, http.StatusCreated, model.APIResponse{
+		Success: true,
+		Message: "Chat history exported",
+		Data:    object,
+	})
+}
+
+// renderChatExport 按格式生成导出文件，返回文件名、Content-Type和内容
+func renderChatExport(accountID, contact, format string, messages []*model.Message) (string, string, []byte) {
+	var buf bytes.Buffer
+	contentType := "application/json; charset=utf-8"
+	if format == "csv" {
+		contentType = "text/csv; charset=utf-8"
+		w := csv.NewWriter(&buf)
+		w.Write(chatHistoryColumns)
+		for _, msg := range messages {
+			w.Write([]string{
+				msg.ID,
+				msg.Timestamp.UTC().Format(time.RFC3339),
+				msg.Direction,
+				msg.Contact,
+				msg.Type,
+				msg.Body,
+				msg.Status,
+				msg.WorkerMessageID,
+			})
+		}
+		w.Flush()
+	} else {
+		encoder := json.NewEncoder(&buf)
+		encoder.SetIndent("", "  ")
+		encoder.Encode(messages)
+	}
+
+	filename := fmt.Sprintf("chat-%s-%s.%s", accountID, strings.NewReplacer("@", "_", ".", "_").Replace(contact), format)
+	return filename, contentType, buf.Bytes()
+}
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+)
+
+// CloneAccount 复制账号配置创建新账号
+// @Summary Clone Account
+// @Description Create a new account with the source account's proxy binding, tags, pool, owner, worker resources and runtime, send limit, warmup profile, typing simulation, keepalive and status poll settings, and start its worker. Notes, phone, statistics and session history are not copied. tags replace the copied tags. With copy_session=true the new session directory is initialised from the source's latest successful backup before the worker starts (404 when the source has no backup); run POST /system/backups/run with the source account first for a fresh copy. With async=true the request returns a create_account job; poll GET /jobs/{id} for the result.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param id path string true "Source account ID"
+// @Param request body model.CloneAccountRequest true "New account"
+// @Param async query bool false "Clone the account in a background job"
+// @Success 200 {object} model.APIResponse{data=model.Account}
+// @Success 202 {object} model.APIResponse{data=model.Job}
+// @Failure 400 {object} model.APIResponse
+// @Failure 404 {object} model.APIResponse
+// @Failure 500 {object} model.APIResponse{data=model.WorkerStartupDiagnostics}
+// @Failure 503 {object} model.APIResponse
+// @Router /accounts/{id}/clone [post]
+func (h *Handler) CloneAccount(c *gin.Context) {
+	var req model.CloneAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if _, scoped := middleware.TenantID(c); scoped {
+		// 主机调度只对管理员开放
+		req.HostID = ""
+	}
+	sourceID := c.Param("id")
+	if _, err := h.manager.GetAccount(sourceID); err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if c.Query("async") == "true" {
+		job, err := h.manager.CloneAccountAsync(c.Request.Context(), sourceID, &req)
+		if err != nil {
+			respond(c, createErrorStatus(err, http.StatusBadRequest), model.APIResponse{
+				Success: false,
+				Message: "Failed to clone account",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		respond(c, http.StatusAccepted, model.APIResponse{
+			Success: true,
+			Message: "Account clone started",
+			Data:    job,
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 5*time.Minute)
+	defer cancel()
+
+	account, err := h.manager.CloneAccount(ctx, sourceID, &req)
+	if err != nil {
+		respond(c, createErrorStatus(err, http.StatusInternalServerError), model.APIResponse{
+			Success: false,
+			Message: "Failed to clone account",
+			Data:    startupDiagnostics(err),
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Account cloned successfully",
+		Data:    account,
+	})
+}
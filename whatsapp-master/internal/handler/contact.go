@@ -0,0 +1,246 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+	"whatsapp-aggregator/internal/validation"
+)
+
+// SearchContacts 搜索已同步的联系人
+// @Summary Search Contacts
+// @Description Search contacts synced from the workers into the master database. q matches name, push name, number or WhatsApp ID.
+// @Tags Contact
+// @Produce json
+// @Param q query string false "Search text"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending (default name)"
+// @Param filter[account_id] query string false "Account ID"
+// @Param filter[is_group] query bool false "Only groups or only individual contacts"
+// @Success 200 {object} model.APIResponse{data=[]model.Contact}
+// @Router /contacts [get]
+func (h *Handler) SearchContacts(c *gin.Context) {
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	var accountIDs []string
+	if tenantID, scoped := middleware.TenantID(c); scoped {
+		accountIDs = h.manager.TenantAccountIDs(tenantID)
+	}
+
+	contacts, total, err := h.manager.ListContacts(c.Query("q"), accountIDs, q)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list contacts",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, contacts, buildListMeta(q, total, len(contacts)), "Contacts retrieved successfully")
+}
+
+// SyncContacts 立即同步账号联系人
+// @Summary Sync Contacts
+// @Description Fetch the account's contacts from the worker now and store them in the master database
+// @Tags Contact
+// @Produce json
+// @Param id path string true "Account ID"
+// @Success 200 {object} model.APIResponse{data=model.ContactSyncResult}
+// @Router /accounts/{id}/contacts/sync [post]
+func (h *Handler) SyncContacts(c *gin.Context) {
+	accountID := c.Param("id")
+	if _, err := h.manager.GetAccount(accountID); err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), time.Minute)
+	defer cancel()
+
+	result, err := h.manager.SyncContacts(ctx, accountID)
+	if err != nil {
+		respond(c, workerErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to sync contacts",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Contacts synced successfully",
+		Data:    result,
+	})
+}
+
+// ImportContacts 从CSV或XLSX文件导入联系人
+// @Summary Import Contacts
+// @Description Upload a CSV or XLSX file (or reference one stored with POST /storage/objects by storage_key) and add its contacts to the account through the worker. The first row is a header when it has a phone column (phone, number, mobile...); otherwise the columns are phone, first name, last name. Numbers are normalized to E.164 and invalid or duplicate rows are not sent. The worker is called in batches of CONTACT_IMPORT_BATCH_SIZE with CONTACT_IMPORT_INTERVAL_MS between batches. Returns the result of every row.
+// @Tags Contact
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param file formData file false "CSV or XLSX file, required without storage_key"
+// @Param storage_key formData string false "Key of a CSV or XLSX file in the object storage"
+// @Param country_code formData string false "Country calling code for numbers without + or 00, e.g. 86"
+// @Success 200 {object} model.APIResponse{data=model.ContactImportResult}
+// @Failure 400 {object} model.APIResponse
+// @Failure 409 {object} model.APIResponse
+// @Router /accounts/{id}/contacts/import [post]
+func (h *Handler) ImportContacts(c *gin.Context) {
+	accountID := c.Param("id")
+	account, err := h.manager.GetAccount(accountID)
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if account.Status != "logged_in" {
+		respond(c, http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: "Account is not logged in",
+			Error:   fmt.Sprintf("account %s is %s", accountID, account.Status),
+		})
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.manager.ContactImportMaxSize())
+	filename, data, ok := h.readImportFile(c)
+	if !ok {
+		return
+	}
+
+	// 客户端断开时停止调用Worker，未处理的行不再添加
+	result, err := h.manager.ImportContacts(c.Request.Context(), accountID, filename, data, c.PostForm("country_code"))
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to import contacts",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Contacts imported",
+		Data:    result,
+	})
+}
+
+// readImportFile 读取上传的导入文件，或 storage_key 指定的对象存储中的文件
+func (h *Handler) readImportFile(c *gin.Context) (string, []byte, bool) {
+	if key := c.PostForm("storage_key"); key != "" {
+		tenantID, _ := middleware.TenantID(c)
+		data, err := h.manager.ReadObject(c.Request.Context(), tenantID, key, h.manager.ContactImportMaxSize())
+		if err != nil {
+			respond(c, http.StatusBadRequest, model.APIResponse{
+				Success: false,
+				Message: "Failed to import contacts",
+				Error:   err.Error(),
+			})
+			return "", nil, false
+		}
+		return service.ObjectFilename(key), data, true
+	}
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid multipart form",
+			Error:   err.Error(),
+		})
+		return "", nil, false
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid multipart form",
+			Error:   err.Error(),
+		})
+		return "", nil, false
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid multipart form",
+			Error:   err.Error(),
+		})
+		return "", nil, false
+	}
+	return fileHeader.Filename, data, true
+}
+
+// normalizePhones 将请求中的号码规范化为 E.164 的纯数字形式，号码格式错误时返回400，data中列出每个出错的字段
+func (h *Handler) normalizePhones(c *gin.Context, req model.PhoneNormalizer) bool {
+	err := req.NormalizePhones(h.manager.DefaultCountryCode())
+	if err == nil {
+		return true
+	}
+	var fieldErrs validation.Errors
+	errors.As(err, &fieldErrs)
+	respond(c, http.StatusBadRequest, model.APIResponse{
+		Success: false,
+		Message: "Invalid phone number",
+		Data:    fieldErrs,
+		Error:   err.Error(),
+	})
+	return false
+}
+
+// checkRecipient 校验收件人是否为账号的已知联系人，strict模式下不是时返回422，warn模式下返回警告
+func (h *Handler) checkRecipient(c *gin.Context, message, accountID, contact string) (string, bool) {
+	warning, err := h.manager.CheckRecipient(accountID, contact)
+	if err != nil {
+		respond(c, http.StatusUnprocessableEntity, model.APIResponse{
+			Success: false,
+			Message: message,
+			Error:   err.Error(),
+		})
+		return "", false
+	}
+	return warning, true
+}
+
+// joinWarnings 合并多个非空警告
+func joinWarnings(warnings ...string) string {
+	parts := make([]string, 0, len(warnings))
+	for _, w := range warnings {
+		if w != "" {
+			parts = append(parts, w)
+		}
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,297 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// CreateContentPolicy 创建内容策略
+// @Summary Create Content Policy
+// @Description Add an outbound content policy checked before every text message and media caption is sent: keyword (blocked keywords, case-insensitive), url_allowlist (links must belong to domains or their subdomains), max_links (at most max_links links) or pii (built-in email, credit_card, phone, iban or custom regular expressions in patterns). Action reject refuses the send with 422, review holds the message in the moderation queue and returns 202.
+// @Tags Content Policy
+// @Accept json
+// @Produce json
+// @Param request body model.ContentPolicyRequest true "Content Policy"
+// @Success 200 {object} model.APIResponse{data=model.ContentPolicy}
+// @Failure 400 {object} model.APIResponse
+// @Router /content-policies [post]
+func (h *Handler) CreateContentPolicy(c *gin.Context) {
+	var req model.ContentPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	policy, err := h.manager.CreateContentPolicy(&req)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to save content policy",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Content policy saved successfully",
+		Data:    policy,
+	})
+}
+
+// ListContentPolicies 列出内容策略
+// @Summary List Content Policies
+// @Description List outbound content policies in creation order
+// @Tags Content Policy
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending"
+// @Success 200 {object} model.APIResponse{data=[]model.ContentPolicy}
+// @Router /content-policies [get]
+func (h *Handler) ListContentPolicies(c *gin.Context) {
+	policies, err := h.manager.ListContentPolicies()
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to list content policies",
+			Error:   err.Error(),
+		})
+		return
+	}
+	respondList(c, policies, "Content policies retrieved successfully")
+}
+
+// UpdateContentPolicy 更新内容策略
+// @Summary Update Content Policy
+// @Description Replace a content policy. Messages already in the moderation queue are not re-evaluated.
+// @Tags Content Policy
+// @Accept json
+// @Produce json
+// @Param id path string true "Policy ID"
+// @Param request body model.ContentPolicyRequest true "Content Policy"
+// @Success 200 {object} model.APIResponse{data=model.ContentPolicy}
+// @Failure 404 {object} model.APIResponse
+// @Router /content-policies/{id} [put]
+func (h *Handler) UpdateContentPolicy(c *gin.Context) {
+	var req model.ContentPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	policy, err := h.manager.UpdateContentPolicy(c.Param("id"), &req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrContentPolicyNotFound) {
+			status = http.StatusNotFound
+		}
+		respond(c, status, model.APIResponse{
+			Success: false,
+			Message: "Failed to save content policy",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Content policy saved successfully",
+		Data:    policy,
+	})
+}
+
+// DeleteContentPolicy 删除内容策略
+// @Summary Delete Content Policy
+// @Description Delete a content policy. Held messages stay in the moderation queue.
+// @Tags Content Policy
+// @Produce json
+// @Param id path string true "Policy ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 404 {object} model.APIResponse
+// @Router /content-policies/{id} [delete]
+func (h *Handler) DeleteContentPolicy(c *gin.Context) {
+	if err := h.manager.DeleteContentPolicy(c.Param("id")); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrContentPolicyNotFound) {
+			status = http.StatusNotFound
+		}
+		respond(c, status, model.APIResponse{
+			Success: false,
+			Message: "Failed to delete content policy",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Content policy deleted successfully",
+	})
+}
+
+// moderationErrorStatus 消息不存在时返回404，已审核过返回409
+func moderationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrModerationItemNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrModerationNotPending):
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// ListModeration 审核队列
+// @Summary List Moderation Queue
+// @Description Messages held by review content policies, newest first
+// @Tags Content Policy
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending (default -created_at)"
+// @Param filter[status] query string false "pending, sent, failed or rejected"
+// @Param filter[account_id] query string false "Account IDs (comma separated)"
+// @Param filter[campaign] query string false "Campaign"
+// @Success 200 {object} model.APIResponse{data=[]model.ModerationItem}
+// @Router /moderation [get]
+func (h *Handler) ListModeration(c *gin.Context) {
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	items, total, err := h.manager.ListModeration(q)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list moderation queue",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, items, buildListMeta(q, total, len(items)), "Moderation queue retrieved successfully")
+}
+
+// GetModerationItem 查询审核队列中的消息
+// @Summary Get Moderation Item
+// @Description Get a held message with the policies it violated and the review outcome
+// @Tags Content Policy
+// @Produce json
+// @Param id path string true "Moderation item ID"
+// @Success 200 {object} model.APIResponse{data=model.ModerationItem}
+// @Failure 404 {object} model.APIResponse
+// @Router /moderation/{id} [get]
+func (h *Handler) GetModerationItem(c *gin.Context) {
+	item, err := h.manager.GetModerationItem(c.Param("id"))
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Moderation item not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Moderation item retrieved successfully",
+		Data:    item,
+	})
+}
+
+// ApproveModeration 审核通过并发送
+// @Summary Approve Held Message
+// @Description Approve a held message and send it without re-checking content policies. The send still respects account disable and send quota; a failed send is recorded as status failed and is not retried.
+// @Tags Content Policy
+// @Accept json
+// @Produce json
+// @Param id path string true "Moderation item ID"
+// @Param request body model.ReviewModerationRequest false "Review note"
+// @Success 200 {object} model.APIResponse{data=model.ModerationItem}
+// @Failure 404 {object} model.APIResponse
+// @Failure 409 {object} model.APIResponse
+// @Failure 502 {object} model.APIResponse
+// @Router /moderation/{id}/approve [post]
+func (h *Handler) ApproveModeration(c *gin.Context) {
+	var req model.ReviewModerationRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	item, _, err := h.manager.ApproveModeration(c.Request.Context(), c.Param("id"), req.Note)
+	if item == nil {
+		respond(c, moderationErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to approve message",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		respond(c, http.StatusBadGateway, model.APIResponse{
+			Success: false,
+			Message: "Approved message failed to send",
+			Data:    item,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Message approved and sent",
+		Data:    item,
+	})
+}
+
+// RejectModeration 审核拒绝
+// @Summary Reject Held Message
+// @Description Reject a held message; it is never sent
+// @Tags Content Policy
+// @Accept json
+// @Produce json
+// @Param id path string true "Moderation item ID"
+// @Param request body model.ReviewModerationRequest false "Review note"
+// @Success 200 {object} model.APIResponse{data=model.ModerationItem}
+// @Failure 404 {object} model.APIResponse
+// @Failure 409 {object} model.APIResponse
+// @Router /moderation/{id}/reject [post]
+func (h *Handler) RejectModeration(c *gin.Context) {
+	var req model.ReviewModerationRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	item, err := h.manager.RejectModeration(c.Param("id"), req.Note)
+	if err != nil {
+		respond(c, moderationErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to reject message",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Message rejected",
+		Data:    item,
+	})
+}
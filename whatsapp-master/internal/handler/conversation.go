@@ -0,0 +1,145 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// ListConversations 列出会话归属
+// @Summary List Conversations
+// @Description List conversation ownership (bot or agent) for an account
+// @Tags Conversation
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending"
+// @Param filter[handled_by] query string false "bot or agent"
+// @Param filter[agent_id] query string false "Agent ID"
+// @Success 200 {object} model.APIResponse{data=[]model.Conversation}
+// @Router /accounts/{id}/conversations [get]
+func (h *Handler) ListConversations(c *gin.Context) {
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	conversations, total, err := h.manager.ListConversations(c.Param("id"), q)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list conversations",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, conversations, buildListMeta(q, total, len(conversations)), "Conversations retrieved successfully")
+}
+
+// GetConversation 获取会话归属
+// @Summary Get Conversation
+// @Description Get who is handling the conversation with a contact
+// @Tags Conversation
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param contact path string true "Contact"
+// @Success 200 {object} model.APIResponse{data=model.Conversation}
+// @Router /accounts/{id}/conversations/{contact} [get]
+func (h *Handler) GetConversation(c *gin.Context) {
+	conv, err := h.manager.GetConversation(c.Param("id"), c.Param("contact"))
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Conversation retrieved successfully",
+		Data:    conv,
+	})
+}
+
+// ClaimConversation 坐席接管会话
+// @Summary Claim Conversation
+// @Description Assign a conversation to a human agent
+// @Tags Conversation
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param contact path string true "Contact"
+// @Param request body model.ClaimConversationRequest true "Claim Request"
+// @Success 200 {object} model.APIResponse{data=model.Conversation}
+// @Router /accounts/{id}/conversations/{contact}/claim [post]
+func (h *Handler) ClaimConversation(c *gin.Context) {
+	var req model.ClaimConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	conv, err := h.manager.ClaimConversation(c.Param("id"), c.Param("contact"), &req)
+	if err != nil {
+		respond(c, http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: "Failed to claim conversation",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Conversation claimed successfully",
+		Data:    conv,
+	})
+}
+
+// ReleaseConversation 释放会话
+// @Summary Release Conversation
+// @Description Hand a conversation back to the bot
+// @Tags Conversation
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param contact path string true "Contact"
+// @Param request body model.ReleaseConversationRequest false "Release Request"
+// @Success 200 {object} model.APIResponse{data=model.Conversation}
+// @Router /accounts/{id}/conversations/{contact}/release [post]
+func (h *Handler) ReleaseConversation(c *gin.Context) {
+	var req model.ReleaseConversationRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	conv, err := h.manager.ReleaseConversation(c.Param("id"), c.Param("contact"), &req)
+	if err != nil {
+		respond(c, http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: "Failed to release conversation",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Conversation released successfully",
+		Data:    conv,
+	})
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"bytes"
+	"embed"
+	"html/template"
+	"io/fs"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/i18n"
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+)
+
+// dashboardFiles 控制台页面和静态资源，随二进制一起发布
+//
+//go:embed dashboard
+var dashboardFiles embed.FS
+
+// dashboardTemplate 控制台页面模板，界面文字通过 .T 翻译
+var dashboardTemplate = template.Must(template.ParseFS(dashboardFiles, "dashboard/index.html"))
+
+// dashboardAssets 控制台脚本和样式
+func dashboardAssets() http.FileSystem {
+	assets, err := fs.Sub(dashboardFiles, "dashboard/assets")
+	if err != nil {
+		panic(err)
+	}
+	return http.FS(assets)
+}
+
+// dashboardScriptMessages 控制台脚本中使用的界面文字，按页面语言翻译后写入页面
+var dashboardScriptMessages = []string{
+	"Connected",
+	"Reconnecting...",
+	"No accounts",
+	"Phone",
+	"Proxy",
+	"Last activity",
+	"Messages sent",
+	"Never",
+	"None",
+	"QR code",
+	"Logs",
+	"Select an account",
+	"Sending...",
+	"Message sent",
+	"Failed to send message",
+	"No QR code available",
+	"Log stream ended",
+	"Failed to read worker logs",
+	"Failed to load accounts",
+	"Disabled",
+}
+
+// Dashboard 管理面板
+func (h *Handler) Dashboard(c *gin.Context) {
+	locale := middleware.GetLocale(c)
+	page := dashboardPage{
+		Lang:     locale,
+		Auth:     h.manager.GetConfig().Tenant.Enabled,
+		Messages: make(map[string]string, len(dashboardScriptMessages)),
+	}
+	for _, code := range i18n.Locales() {
+		page.Locales = append(page.Locales, dashboardLocale{Code: code, Name: i18n.T(code, "English")})
+	}
+	for _, message := range dashboardScriptMessages {
+		page.Messages[message] = i18n.T(locale, message)
+	}
+
+	var buf bytes.Buffer
+	if err := dashboardTemplate.Execute(&buf, page); err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to render dashboard",
+			Error:   err.Error(),
+		})
+		return
+	}
+	c.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+}
+
+// dashboardLocale 控制台语言切换链接
+type dashboardLocale struct {
+	Code string
+	Name string
+}
+
+// dashboardPage 控制台模板数据
+type dashboardPage struct {
+	Lang     string
+	Locales  []dashboardLocale
+	Auth     bool              // 开启多租户鉴权时显示凭证输入框
+	Messages map[string]string // 脚本使用的译文
+}
+
+// T 按页面语言翻译界面文字
+func (p dashboardPage) T(message string) string {
+	return i18n.T(p.Lang, message)
+}
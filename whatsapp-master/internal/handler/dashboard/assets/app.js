@@ -0,0 +1,507 @@
+// 控制台：账号卡片、二维码、发送消息和日志查看，通过事件WebSocket实时刷新
+(function () {
+    'use strict';
+
+    var config = window.DASHBOARD || {};
+    var messages = config.messages || {};
+    var accounts = {};
+    var qrAccount = '';
+    var logAbort = null;
+    var reloadTimer = null;
+    var reconnecting = false;
+    var maxEvents = 50;
+    var maxLogLines = 2000;
+
+    function t(message) {
+        return messages[message] || message;
+    }
+
+    function $(id) {
+        return document.getElementById(id);
+    }
+
+    // 凭证只在开启多租户鉴权时使用，保存在本地浏览器
+    function credential() {
+        if (!config.auth) {
+            return null;
+        }
+        var value = localStorage.getItem('dashboard.credential') || '';
+        if (!value) {
+            return null;
+        }
+        return {type: localStorage.getItem('dashboard.credentialType') || 'api_key', value: value};
+    }
+
+    function authHeaders() {
+        var cred = credential();
+        if (!cred) {
+            return {};
+        }
+        return cred.type === 'admin_token' ? {'X-Admin-Token': cred.value} : {'X-API-Key': cred.value};
+    }
+
+    function api(path, options) {
+        options = options || {};
+        var headers = Object.assign({'Accept-Language': document.documentElement.lang}, authHeaders(), options.headers || {});
+        return fetch('/api/v1' + path, Object.assign({}, options, {headers: headers})).then(function (resp) {
+            return resp.json().catch(function () {
+                return {};
+            }).then(function (body) {
+                return {ok: resp.ok, status: resp.status, body: body};
+            });
+        });
+    }
+
+    function errorText(body, fallback) {
+        var text = body.message || fallback;
+        if (body.error) {
+            text += ': ' + body.error;
+        }
+        if (Array.isArray(body.data)) {
+            body.data.forEach(function (fieldErr) {
+                if (fieldErr && fieldErr.field) {
+                    text += '\n' + fieldErr.field + ': ' + fieldErr.message;
+                }
+            });
+        }
+        return text;
+    }
+
+    function element(tag, className, text) {
+        var el = document.createElement(tag);
+        if (className) {
+            el.className = className;
+        }
+        if (text !== undefined) {
+            el.textContent = text;
+        }
+        return el;
+    }
+
+    function formatTime(value) {
+        if (!value) {
+            return t('Never');
+        }
+        return new Date(value).toLocaleString();
+    }
+
+    function formatProxy(proxy) {
+        if (!proxy || !proxy.ip) {
+            return t('None');
+        }
+        return (proxy.protocol || 'socks5') + '://' + proxy.ip + ':' + proxy.port;
+    }
+
+    // 账号列表
+
+    function loadAccounts() {
+        var all = [];
+        function page(cursor) {
+            var path = '/accounts?limit=1000&sort=id' + (cursor ? '&cursor=' + encodeURIComponent(cursor) : '');
+            return api(path).then(function (res) {
+                if (!res.ok) {
+                    throw new Error(errorText(res.body, t('Failed to load accounts')));
+                }
+                all = all.concat(res.body.data || []);
+                var next = res.body.meta && res.body.meta.next_cursor;
+                return next ? page(next) : all;
+            });
+        }
+        return page('').then(function (list) {
+            accounts = {};
+            list.forEach(function (account) {
+                accounts[account.id] = account;
+            });
+            renderAccounts();
+            renderAccountSelects();
+        }).catch(function (err) {
+            var container = $('accounts');
+            container.textContent = '';
+            container.appendChild(element('p', 'result error', err.message));
+        });
+    }
+
+    // 事件较多时合并为一次刷新
+    function scheduleReload() {
+        if (reloadTimer) {
+            return;
+        }
+        reloadTimer = setTimeout(function () {
+            reloadTimer = null;
+            loadAccounts();
+        }, 1000);
+    }
+
+    function sortedAccounts() {
+        return Object.keys(accounts).sort().map(function (id) {
+            return accounts[id];
+        });
+    }
+
+    function renderAccounts() {
+        var container = $('accounts');
+        var search = $('account-search').value.trim().toLowerCase();
+        container.textContent = '';
+
+        var list = sortedAccounts().filter(function (account) {
+            if (!search) {
+                return true;
+            }
+            return [account.id, account.name, account.phone, account.status].some(function (value) {
+                return value && String(value).toLowerCase().indexOf(search) >= 0;
+            });
+        });
+        if (list.length === 0) {
+            container.appendChild(element('p', 'empty', t('No accounts')));
+            return;
+        }
+        list.forEach(function (account) {
+            container.appendChild(renderCard(account));
+        });
+    }
+
+    function renderCard(account) {
+        var card = element('div', 'card');
+        card.dataset.id = account.id;
+
+        var header = element('div', 'card-header');
+        header.appendChild(element('span', 'card-title', account.name || account.id));
+        var status = account.disabled ? 'disabled' : account.status;
+        header.appendChild(element('span', 'status status-' + status, account.disabled ? t('Disabled') : account.status));
+        card.appendChild(header);
+
+        var fields = element('dl');
+        [
+            ['ID', account.id],
+            [t('Phone'), account.phone || '-'],
+            [t('Proxy'), formatProxy(account.proxy)],
+            [t('Last activity'), formatTime(account.last_activity)],
+            [t('Messages sent'), String(account.messages_sent || 0)]
+        ].forEach(function (field) {
+            fields.appendChild(element('dt', '', field[0]));
+            var value = element('dd', '', field[1]);
+            value.title = field[1];
+            fields.appendChild(value);
+        });
+        card.appendChild(fields);
+
+        var actions = element('div', 'card-actions');
+        var qr = element('button', 'btn btn-small', t('QR code'));
+        qr.type = 'button';
+        qr.addEventListener('click', function () {
+            showQRCode(account.id);
+        });
+        actions.appendChild(qr);
+        var logs = element('button', 'btn btn-small btn-secondary', t('Logs'));
+        logs.type = 'button';
+        logs.addEventListener('click', function () {
+            $('log-account').value = account.id;
+            startLogs();
+            $('log').scrollIntoView({behavior: 'smooth'});
+        });
+        actions.appendChild(logs);
+        card.appendChild(actions);
+        return card;
+    }
+
+    function flashCard(accountID) {
+        var card = document.querySelector('.card[data-id="' + CSS.escape(accountID) + '"]');
+        if (!card) {
+            return;
+        }
+        card.classList.add('flash');
+        setTimeout(function () {
+            card.classList.remove('flash');
+        }, 1500);
+    }
+
+    function renderAccountSelects() {
+        document.querySelectorAll('.account-select').forEach(function (select) {
+            var selected = select.value;
+            select.textContent = '';
+            var placeholder = element('option', '', t('Select an account'));
+            placeholder.value = '';
+            select.appendChild(placeholder);
+            sortedAccounts().forEach(function (account) {
+                var option = element('option', '', account.id + (account.name ? ' (' + account.name + ')' : '') + ' - ' + account.status);
+                option.value = account.id;
+                select.appendChild(option);
+            });
+            select.value = accounts[selected] ? selected : '';
+        });
+    }
+
+    // 二维码
+
+    function showQRCode(accountID) {
+        qrAccount = accountID;
+        $('qr-title').textContent = accountID;
+        $('qr-dialog').hidden = false;
+        loadQRCode();
+    }
+
+    function loadQRCode() {
+        var image = $('qr-image');
+        var errorEl = $('qr-error');
+        fetch('/api/v1/accounts/' + encodeURIComponent(qrAccount) + '/qr-code.png?t=' + Date.now(), {headers: authHeaders()}).then(function (resp) {
+            if (!resp.ok) {
+                return resp.json().catch(function () {
+                    return {};
+                }).then(function (body) {
+                    throw new Error(errorText(body, t('No QR code available')));
+                });
+            }
+            return resp.blob();
+        }).then(function (blob) {
+            if (image.src) {
+                URL.revokeObjectURL(image.src);
+            }
+            image.src = URL.createObjectURL(blob);
+            image.hidden = false;
+            errorEl.textContent = '';
+        }).catch(function (err) {
+            image.hidden = true;
+            errorEl.textContent = err.message;
+        });
+    }
+
+    function closeQRCode() {
+        qrAccount = '';
+        $('qr-dialog').hidden = true;
+    }
+
+    // 发送消息
+
+    function sendMessage(event) {
+        event.preventDefault();
+        var result = $('send-result');
+        result.className = 'result';
+        result.textContent = t('Sending...');
+        api('/send-message', {
+            method: 'POST',
+            headers: {'Content-Type': 'application/json'},
+            body: JSON.stringify({
+                account_id: $('send-account').value,
+                contact: $('send-contact').value.trim(),
+                message: $('send-message').value
+            })
+        }).then(function (res) {
+            if (res.ok) {
+                result.className = 'result ok';
+                result.textContent = t('Message sent') + (res.body.data && res.body.data.message_id ? ' (' + res.body.data.message_id + ')' : '');
+                $('send-message').value = '';
+                return;
+            }
+            result.className = 'result error';
+            result.textContent = errorText(res.body, t('Failed to send message'));
+        }).catch(function (err) {
+            result.className = 'result error';
+            result.textContent = err.message;
+        });
+    }
+
+    // 日志查看：读取日志SSE流，使用fetch以便携带鉴权请求头
+
+    function appendLog(line) {
+        var log = $('log');
+        var follow = log.scrollTop + log.clientHeight >= log.scrollHeight - 20;
+        log.appendChild(document.createTextNode(line + '\n'));
+        while (log.childNodes.length > maxLogLines) {
+            log.removeChild(log.firstChild);
+        }
+        if (follow) {
+            log.scrollTop = log.scrollHeight;
+        }
+    }
+
+    function stopLogs() {
+        if (logAbort) {
+            logAbort.abort();
+            logAbort = null;
+        }
+    }
+
+    function startLogs() {
+        stopLogs();
+        var accountID = $('log-account').value;
+        if (!accountID) {
+            return;
+        }
+        $('log').textContent = '';
+        var controller = new AbortController();
+        logAbort = controller;
+
+        fetch('/api/v1/accounts/' + encodeURIComponent(accountID) + '/logs/stream?tail=200', {
+            headers: authHeaders(),
+            signal: controller.signal
+        }).then(function (resp) {
+            if (!resp.ok) {
+                return resp.json().catch(function () {
+                    return {};
+                }).then(function (body) {
+                    appendLog(errorText(body, t('Failed to read worker logs')));
+                });
+            }
+            var reader = resp.body.getReader();
+            var decoder = new TextDecoder();
+            var buffer = '';
+            function read() {
+                return reader.read().then(function (chunk) {
+                    if (chunk.done) {
+                        return;
+                    }
+                    buffer += decoder.decode(chunk.value, {stream: true});
+                    var frames = buffer.split('\n\n');
+                    buffer = frames.pop();
+                    frames.forEach(handleLogFrame);
+                    return read();
+                });
+            }
+            return read();
+        }).catch(function (err) {
+            if (err.name !== 'AbortError') {
+                appendLog(err.message);
+            }
+        });
+    }
+
+    function handleLogFrame(frame) {
+        var type = 'message';
+        var data = [];
+        frame.split('\n').forEach(function (line) {
+            if (line.indexOf('event:') === 0) {
+                type = line.slice(6).trim();
+            } else if (line.indexOf('data:') === 0) {
+                data.push(line.slice(5).replace(/^ /, ''));
+            }
+        });
+        if (type === 'log') {
+            appendLog(data.join('\n'));
+        } else if (type === 'end') {
+            var end = {};
+            try {
+                end = JSON.parse(data.join('\n'));
+            } catch (e) {
+                end = {};
+            }
+            appendLog('-- ' + t('Log stream ended') + (end.error ? ': ' + end.error : '') + ' --');
+        }
+    }
+
+    // 实时事件
+
+    function connectEvents() {
+        var params = new URLSearchParams();
+        var cred = credential();
+        if (cred) {
+            params.set(cred.type, cred.value);
+        }
+        var scheme = location.protocol === 'https:' ? 'wss://' : 'ws://';
+        var query = params.toString();
+        var socket = new WebSocket(scheme + location.host + '/api/v1/events/ws' + (query ? '?' + query : ''));
+        var connection = $('connection');
+
+        socket.onopen = function () {
+            connection.textContent = t('Connected');
+            connection.classList.add('online');
+            // 断线期间可能错过事件，重连后重新加载账号
+            if (reconnecting) {
+                loadAccounts();
+            }
+            reconnecting = false;
+        };
+        socket.onmessage = function (msg) {
+            var event;
+            try {
+                event = JSON.parse(msg.data);
+            } catch (e) {
+                return;
+            }
+            if (event.type === 'ping') {
+                return;
+            }
+            handleEvent(event);
+        };
+        socket.onclose = function () {
+            connection.textContent = t('Reconnecting...');
+            connection.classList.remove('online');
+            reconnecting = true;
+            setTimeout(connectEvents, 3000);
+        };
+    }
+
+    function handleEvent(event) {
+        addEventEntry(event);
+
+        var account = accounts[event.account_id];
+        if (event.type === 'account.status_changed' && account && event.data) {
+            account.status = event.data.status;
+            renderAccounts();
+            renderAccountSelects();
+        } else if (event.type === 'qr.updated') {
+            if (qrAccount && qrAccount === event.account_id) {
+                loadQRCode();
+            }
+        } else if (/^(account|message|worker|session)\./.test(event.type)) {
+            scheduleReload();
+        }
+        if (event.account_id) {
+            flashCard(event.account_id);
+        }
+    }
+
+    function addEventEntry(event) {
+        var list = $('events');
+        var item = element('li');
+        item.appendChild(element('time', '', new Date(event.timestamp).toLocaleTimeString()));
+        item.appendChild(element('span', 'type', event.type));
+        if (event.account_id) {
+            item.appendChild(element('span', '', event.account_id));
+        }
+        var detail = '';
+        if (event.data && event.data.status) {
+            detail = (event.data.previous ? event.data.previous + ' → ' : '') + event.data.status;
+        } else if (event.data && event.data.error) {
+            detail = event.data.error;
+        }
+        if (detail) {
+            item.appendChild(element('span', 'empty', ' ' + detail));
+        }
+        list.insertBefore(item, list.firstChild);
+        while (list.childNodes.length > maxEvents) {
+            list.removeChild(list.lastChild);
+        }
+    }
+
+    // 初始化
+
+    function init() {
+        var form = $('credentials-form');
+        if (form) {
+            $('credential-type').value = localStorage.getItem('dashboard.credentialType') || 'api_key';
+            $('credential').value = localStorage.getItem('dashboard.credential') || '';
+            form.addEventListener('submit', function (event) {
+                event.preventDefault();
+                localStorage.setItem('dashboard.credentialType', $('credential-type').value);
+                localStorage.setItem('dashboard.credential', $('credential').value.trim());
+                location.reload();
+            });
+        }
+
+        $('account-search').addEventListener('input', renderAccounts);
+        $('send-form').addEventListener('submit', sendMessage);
+        $('log-start').addEventListener('click', startLogs);
+        $('log-stop').addEventListener('click', stopLogs);
+        $('qr-close').addEventListener('click', closeQRCode);
+        $('qr-dialog').addEventListener('click', function (event) {
+            if (event.target === this) {
+                closeQRCode();
+            }
+        });
+
+        loadAccounts();
+        connectEvents();
+    }
+
+    init();
+})();
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// ListDeadLetters 死信队列
+// @Summary List Dead Letters
+// @Description Messages whose send failed because the worker was unreachable or the proxy failed, newest first. Retried entries keep their history until the janitor removes them DEAD_LETTER_RETENTION_DAYS after delivery.
+// @Tags Message
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending (default -created_at)"
+// @Param filter[account_id] query string false "Account IDs (comma separated)"
+// @Param filter[status] query string false "pending, retrying, exhausted or resolved"
+// @Param filter[campaign] query string false "Campaign"
+// @Success 200 {object} model.APIResponse{data=[]model.DeadLetter}
+// @Router /dead-letters [get]
+func (h *Handler) ListDeadLetters(c *gin.Context) {
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	filter := &model.DeadLetterFilter{}
+	if tenantID, scoped := middleware.TenantID(c); scoped {
+		filter.AccountIDs = h.manager.TenantAccountIDs(tenantID)
+	}
+
+	letters, total, err := h.manager.ListDeadLetters(filter, q)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list dead letters",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, letters, buildListMeta(q, total, len(letters)), "Dead letters retrieved successfully")
+}
+
+// RetryDeadLetter 立即重发死信
+// @Summary Retry Dead Letter
+// @Description Resend a dead-lettered message now and return the updated entry. A failed attempt is recorded in retries and error and the entry stays in the queue.
+// @Tags Message
+// @Produce json
+// @Param id path string true "Dead letter ID"
+// @Success 200 {object} model.APIResponse{data=model.DeadLetter}
+// @Failure 404 {object} model.APIResponse
+// @Failure 409 {object} model.APIResponse
+// @Router /dead-letters/{id}/retry [post]
+func (h *Handler) RetryDeadLetter(c *gin.Context) {
+	letter, err := h.manager.GetDeadLetter(c.Param("id"))
+	if err == nil && !h.ownsAccounts(c, []string{letter.AccountID}) {
+		err = fmt.Errorf("dead letter %s not found", letter.ID)
+	}
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Dead letter not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if !h.allowSend(c, "Failed to retry dead letter", letter.AccountID) {
+		return
+	}
+
+	letter, err = h.manager.RetryDeadLetter(letter.ID)
+	if err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, service.ErrDeadLetterNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, service.ErrDeadLetterResolved), errors.Is(err, service.ErrDeadLetterBusy):
+			status = http.StatusConflict
+		}
+		respond(c, status, model.APIResponse{
+			Success: false,
+			Message: "Failed to retry dead letter",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	message := "Dead letter delivered"
+	if letter.Status != model.DeadLetterResolved {
+		message = "Dead letter retry failed"
+	}
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: letter.Status == model.DeadLetterResolved,
+		Message: message,
+		Data:    letter,
+	})
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// UploadDiagnostics Worker上传诊断包
+// @Summary Upload Diagnostic Bundle
+// @Description Called by workers to push screenshots, crash dumps or page HTML for an account. Authenticated with the X-Worker-Token header that the master passes to each worker as WORKER_TOKEN.
+// @Tags Diagnostics
+// @Accept multipart/form-data
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param X-Worker-Token header string true "Worker token"
+// @Param file formData file true "Diagnostic file, repeat for multiple files"
+// @Param incident_id formData string false "Related incident or event ID"
+// @Param kind formData string false "screenshot, crash_dump, html or other"
+// @Param note formData string false "Free-form description"
+// @Success 200 {object} model.APIResponse{data=model.DiagnosticBundle}
+// @Router /worker/accounts/{id}/diagnostics [post]
+func (h *Handler) UploadDiagnostics(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.manager.MaxDiagnosticUpload())
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid multipart form",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	bundle, err := h.manager.SaveDiagnosticBundle(c.Param("id"), c.PostForm("incident_id"), c.PostForm("kind"), c.PostForm("note"), form.File["file"])
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to save diagnostic bundle",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Diagnostic bundle uploaded successfully",
+		Data:    bundle,
+	})
+}
+
+// ListDiagnostics 列出账号的诊断包
+// @Summary List Diagnostic Bundles
+// @Description List diagnostic bundles uploaded by the account's worker
+// @Tags Diagnostics
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending"
+// @Param filter[incident_id] query string false "Related incident ID"
+// @Param filter[kind] query string false "screenshot, crash_dump, html or other"
+// @Success 200 {object} model.APIResponse{data=[]model.DiagnosticBundle}
+// @Router /accounts/{id}/diagnostics [get]
+func (h *Handler) ListDiagnostics(c *gin.Context) {
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	bundles, total, err := h.manager.ListDiagnosticBundles(c.Param("id"), q)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list diagnostic bundles",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, bundles, buildListMeta(q, total, len(bundles)), "Diagnostic bundles retrieved successfully")
+}
+
+// GetDiagnostics 获取诊断包
+// @Summary Get Diagnostic Bundle
+// @Description Get a diagnostic bundle and its file list
+// @Tags Diagnostics
+// @Produce json
+// @Param id path string true "Bundle ID"
+// @Success 200 {object} model.APIResponse{data=model.DiagnosticBundle}
+// @Router /diagnostics/{id} [get]
+func (h *Handler) GetDiagnostics(c *gin.Context) {
+	bundle, err := h.manager.GetDiagnosticBundle(c.Param("id"))
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Diagnostic bundle not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Diagnostic bundle retrieved successfully",
+		Data:    bundle,
+	})
+}
+
+// DownloadDiagnosticFile 下载诊断包中的文件
+// @Summary Download Diagnostic File
+// @Description Download a single file of a diagnostic bundle
+// @Tags Diagnostics
+// @Produce octet-stream
+// @Param id path string true "Bundle ID"
+// @Param name path string true "File name"
+// @Success 200 {file} file
+// @Router /diagnostics/{id}/files/{name} [get]
+func (h *Handler) DownloadDiagnosticFile(c *gin.Context) {
+	path, file, err := h.manager.DiagnosticFilePath(c.Param("id"), c.Param("name"))
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Diagnostic file not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if file.MimeType != "" {
+		c.Header("Content-Type", file.MimeType)
+	}
+	c.FileAttachment(path, file.Name)
+}
+
+// DeleteDiagnostics 删除诊断包
+// @Summary Delete Diagnostic Bundle
+// @Description Delete a diagnostic bundle and its files before the retention period ends
+// @Tags Diagnostics
+// @Produce json
+// @Param id path string true "Bundle ID"
+// @Success 200 {object} model.APIResponse
+// @Router /diagnostics/{id} [delete]
+func (h *Handler) DeleteDiagnostics(c *gin.Context) {
+	if err := h.manager.DeleteDiagnosticBundle(c.Param("id")); err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Failed to delete diagnostic bundle",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Diagnostic bundle deleted successfully",
+	})
+}
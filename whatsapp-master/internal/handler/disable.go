@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// DisableAccount 停用账号
+// @Summary Disable Account
+// @Description Put an account into maintenance mode without stopping its worker. Sends through the account are rejected with 409 and it is left out of bulk sends and campaigns until re-enabled.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param request body model.DisableAccountRequest false "Disable Request"
+// @Success 200 {object} model.APIResponse{data=model.Account}
+// @Router /accounts/{id}/disable [put]
+func (h *Handler) DisableAccount(c *gin.Context) {
+	var req model.DisableAccountRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	if _, err := h.manager.GetAccount(c.Param("id")); err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	account, err := h.manager.DisableAccount(c.Param("id"), req.Reason)
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to disable account",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Account disabled successfully",
+		Data:    account,
+	})
+}
+
+// EnableAccount 重新启用账号
+// @Summary Enable Account
+// @Description Take an account out of maintenance mode so it can send and join bulk sends and campaigns again.
+// @Tags Account
+// @Produce json
+// @Param id path string true "Account ID"
+// @Success 200 {object} model.APIResponse{data=model.Account}
+// @Router /accounts/{id}/enable [put]
+func (h *Handler) EnableAccount(c *gin.Context) {
+	if _, err := h.manager.GetAccount(c.Param("id")); err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	account, err := h.manager.EnableAccount(c.Param("id"))
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to enable account",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Account enabled successfully",
+		Data:    account,
+	})
+}
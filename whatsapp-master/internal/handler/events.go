@@ -0,0 +1,149 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// eventHeartbeatInterval SSE心跳间隔，防止代理断开空闲连接
+const eventHeartbeatInterval = 30 * time.Second
+
+// StreamEvents 实时事件流
+// @Summary Stream Events
+// @Description Stream account status, login, QR code, message and conversation events in real time (Server-Sent Events)
+// @Tags System
+// @Produce text/event-stream
+// @Param account_id query string false "Only events for these accounts (comma separated)"
+// @Param types query string false "Only these event types (comma separated, e.g. account.status_changed,message.received)"
+// @Success 200 {object} model.Event
+// @Router /events [get]
+func (h *Handler) StreamEvents(c *gin.Context) {
+	accountIDs := splitQueryValues(c.Query("account_id"))
+	types := splitQueryValues(c.Query("types"))
+
+	events, unsubscribe := h.manager.Events().Subscribe(64)
+	defer unsubscribe()
+
+	heartbeat := time.NewTicker(eventHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-heartbeat.C:
+			io.WriteString(w, ": ping\n\n")
+			return true
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			if len(accountIDs) > 0 && !accountIDs[event.AccountID] {
+				return true
+			}
+			if len(types) > 0 && !types[event.Type] {
+				return true
+			}
+			c.SSEvent(event.Type, event)
+			return true
+		}
+	})
+}
+
+// StreamEventsWS 通过WebSocket推送实时事件
+// @Summary Stream Events (WebSocket)
+// @Description Same events and filters as /events over a WebSocket, one JSON model.Event per text message. A {"type":"ping"} message is sent every 30 seconds. Browsers cannot set headers on WebSocket requests, so with MULTI_TENANT_ENABLED the credential can be passed as the api_key or admin_token query parameter. Cross-origin connections are rejected.
+// @Tags System
+// @Param account_id query string false "Only events for these accounts (comma separated)"
+// @Param types query string false "Only these event types (comma separated)"
+// @Success 101 {object} model.Event
+// @Router /events/ws [get]
+func (h *Handler) StreamEventsWS(c *gin.Context) {
+	accountIDs := splitQueryValues(c.Query("account_id"))
+	types := splitQueryValues(c.Query("types"))
+
+	server := websocket.Server{
+		Handshake: sameOriginHandshake,
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			events, unsubscribe := h.manager.Events().Subscribe(64)
+			defer unsubscribe()
+
+			// 客户端不发送数据，读取只用于发现连接关闭
+			closed := make(chan struct{})
+			go func() {
+				defer close(closed)
+				var discard string
+				for websocket.Message.Receive(ws, &discard) == nil {
+				}
+			}()
+
+			heartbeat := time.NewTicker(eventHeartbeatInterval)
+			defer heartbeat.Stop()
+
+			for {
+				select {
+				case <-closed:
+					return
+				case <-heartbeat.C:
+					if websocket.JSON.Send(ws, &model.Event{Type: "ping", Timestamp: time.Now()}) != nil {
+						return
+					}
+				case event, ok := <-events:
+					if !ok {
+						return
+					}
+					if len(accountIDs) > 0 && !accountIDs[event.AccountID] {
+						continue
+					}
+					if len(types) > 0 && !types[event.Type] {
+						continue
+					}
+					if websocket.JSON.Send(ws, event) != nil {
+						return
+					}
+				}
+			}
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// sameOriginHandshake 只接受同源页面或不带Origin的客户端，防止其他网站借用浏览器连接读取事件
+func sameOriginHandshake(config *websocket.Config, req *http.Request) error {
+	raw := req.Header.Get("Origin")
+	if raw == "" {
+		return nil
+	}
+	origin, err := url.Parse(raw)
+	if err != nil || origin.Host != req.Host {
+		return fmt.Errorf("cross-origin websocket connection from %q rejected", raw)
+	}
+	config.Origin = origin
+	return nil
+}
+
+// splitQueryValues 解析逗号分隔的查询参数
+func splitQueryValues(raw string) map[string]bool {
+	values := make(map[string]bool)
+	for _, v := range strings.Split(raw, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values[v] = true
+		}
+	}
+	return values
+}
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+
+	"whatsapp-aggregator/internal/graphql"
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+)
+
+const (
+	// graphQLTimeout 单次GraphQL查询的超时
+	graphQLTimeout = 30 * time.Second
+	// graphQLWSProtocol GraphQL over WebSocket 子协议（graphql-ws 库使用的协议）
+	graphQLWSProtocol = "graphql-transport-ws"
+	// graphQLInitTimeout 建立连接后等待 connection_init 的时间
+	graphQLInitTimeout = 10 * time.Second
+)
+
+// GraphQL 执行GraphQL查询
+// @Summary GraphQL Query
+// @Description Run a GraphQL query against accounts, messages, stats and events, e.g. {"query":"{ accounts(status: [\"logged_in\"]) { total nodes { id name messages(limit: 5) { nodes { contact body } } } } }"}. The response is the standard GraphQL {data, errors} object rather than the API envelope; it is 400 when the query cannot be parsed or validated, or exceeds the depth (10) or complexity (20000) limit. Each field costs 1 and a field with a limit argument multiplies the cost of its sub-fields by the limit. Tenant API keys only see their own accounts, messages and events, and cannot query stats. Subscriptions use the graphql-transport-ws WebSocket protocol on GET /graphql; mutations are not supported. The schema is at GET /graphql/schema.
+// @Tags System
+// @Accept json
+// @Produce json
+// @Param request body model.GraphQLRequest true "GraphQL Request"
+// @Success 200 {object} graphql.Response
+// @Failure 400 {object} graphql.Response
+// @Router /graphql [post]
+func (h *Handler) GraphQL(c *gin.Context) {
+	var req model.GraphQLRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &graphql.Response{Errors: []*graphql.Error{{Message: "Invalid GraphQL request: " + err.Error()}}})
+		return
+	}
+	h.executeGraphQL(c, &req)
+}
+
+// GraphQLGet 通过GET执行GraphQL查询或建立订阅连接
+// @Summary GraphQL Query (GET) and Subscriptions
+// @Description Run a GraphQL query passed as query parameters (variables is a JSON object). With a WebSocket upgrade using the graphql-transport-ws subprotocol, the connection accepts queries and subscriptions such as subscription { events(types: ["account.status_changed"]) { type accountId data timestamp account { name status } } }. Browsers cannot set headers on WebSocket requests, so with MULTI_TENANT_ENABLED the credential can be passed as the api_key or admin_token query parameter. Cross-origin connections are rejected.
+// @Tags System
+// @Produce json
+// @Param query query string true "GraphQL query"
+// @Param variables query string false "Variables as a JSON object"
+// @Param operationName query string false "Operation to run when the query contains several"
+// @Success 200 {object} graphql.Response
+// @Success 101 {object} graphql.Response
+// @Failure 400 {object} graphql.Response
+// @Router /graphql [get]
+func (h *Handler) GraphQLGet(c *gin.Context) {
+	if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		h.graphQLWebSocket(c)
+		return
+	}
+
+	req := model.GraphQLRequest{
+		Query:         c.Query("query"),
+		OperationName: c.Query("operationName"),
+	}
+	if raw := c.Query("variables"); raw != "" {
+		if err := json.Unmarshal([]byte(raw), &req.Variables); err != nil {
+			c.JSON(http.StatusBadRequest, &graphql.Response{Errors: []*graphql.Error{{Message: "variables must be a JSON object"}}})
+			return
+		}
+	}
+	h.executeGraphQL(c, &req)
+}
+
+// GetGraphQLSchema 输出GraphQL Schema
+// @Summary Get GraphQL Schema
+// @Description The GraphQL schema in SDL, for code generators and IDE plugins that do not use introspection
+// @Tags System
+// @Produce plain
+// @Success 200 {string} string "SDL"
+// @Router /graphql/schema [get]
+func (h *Handler) GetGraphQLSchema(c *gin.Context) {
+	c.String(http.StatusOK, h.graphqlSchema.SDL())
+}
+
+// executeGraphQL 执行查询并输出标准GraphQL响应，查询无法解析或校验失败时返回400
+func (h *Handler) executeGraphQL(c *gin.Context, req *model.GraphQLRequest) {
+	ctx, cancel := context.WithTimeout(h.graphQLContext(c), graphQLTimeout)
+	defer cancel()
+
+	resp := h.graphqlSchema.Execute(ctx, graphql.Params{
+		Query:         req.Query,
+		Variables:     req.Variables,
+		OperationName: req.OperationName,
+	})
+	status := http.StatusOK
+	if resp.Data == nil {
+		status = http.StatusBadRequest
+	}
+	c.JSON(status, resp)
+}
+
+// graphQLContext 在请求上下文中记录调用方租户
+func (h *Handler) graphQLContext(c *gin.Context) context.Context {
+	tenantID, scoped := middleware.TenantID(c)
+	return withGraphQLViewer(c.Request.Context(), graphQLViewer{tenantID: tenantID, scoped: scoped})
+}
+
+// graphQLWSMessage graphql-transport-ws 协议消息
+type graphQLWSMessage struct {
+	ID      string          `json:"id,omitempty"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload,omitempty"`
+}
+
+// graphQLWSConn 一个WebSocket连接上的订阅，写入需串行
+type graphQLWSConn struct {
+	ws            *websocket.Conn
+	writeMutex    sync.Mutex
+	subscriptions map[string]context.CancelFunc
+	mutex         sync.Mutex
+}
+
+func (conn *graphQLWSConn) send(id, msgType string, payload interface{}) error {
+	msg := graphQLWSMessage{ID: id, Type: msgType}
+	if payload != nil {
+		raw, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		msg.Payload = raw
+	}
+	conn.writeMutex.Lock()
+	defer conn.writeMutex.Unlock()
+	return websocket.JSON.Send(conn.ws, &msg)
+}
+
+// graphQLWebSocket 按 graphql-transport-ws 协议处理查询和订阅：
+// connection_init/connection_ack 握手后，每个 subscribe 消息对应一个操作，结果以 next 推送，结束时发送 complete
+func (h *Handler) graphQLWebSocket(c *gin.Context) {
+	baseCtx := h.graphQLContext(c)
+
+	server := websocket.Server{
+		Handshake: graphQLHandshake,
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+
+			ctx, cancel := context.WithCancel(baseCtx)
+			defer cancel()
+
+			conn := &graphQLWSConn{ws: ws, subscriptions: make(map[string]context.CancelFunc)}
+			initialized := false
+			ws.SetReadDeadline(time.Now().Add(graphQLInitTimeout))
+
+			for {
+				var msg graphQLWSMessage
+				if err := websocket.JSON.Receive(ws, &msg); err != nil {
+					return
+				}
+
+				switch msg.Type {
+				case "connection_init":
+					if initialized {
+						return
+					}
+					initialized = true
+					ws.SetReadDeadline(time.Time{})
+					if conn.send("", "connection_ack", nil) != nil {
+						return
+					}
+				case "ping":
+					if conn.send("", "pong", nil) != nil {
+						return
+					}
+				case "pong":
+				case "subscribe":
+					if !initialized || msg.ID == "" {
+						return
+					}
+					var payload model.GraphQLRequest
+					if err := json.Unmarshal(msg.Payload, &payload); err != nil {
+						conn.send(msg.ID, "error", []*graphql.Error{{Message: "Invalid subscribe payload: " + err.Error()}})
+						continue
+					}
+					conn.mutex.Lock()
+					if _, duplicate := conn.subscriptions[msg.ID]; duplicate {
+						// 协议要求重复的操作ID关闭连接
+						conn.mutex.Unlock()
+						return
+					}
+					opCtx, opCancel := context.WithCancel(ctx)
+					conn.subscriptions[msg.ID] = opCancel
+					conn.mutex.Unlock()
+					go h.runGraphQLOperation(opCtx, conn, msg.ID, &payload)
+				case "complete":
+					conn.mutex.Lock()
+					if stop, exists := conn.subscriptions[msg.ID]; exists {
+						stop()
+						delete(conn.subscriptions, msg.ID)
+					}
+					conn.mutex.Unlock()
+				default:
+					return
+				}
+			}
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// runGraphQLOperation 执行一个WebSocket操作：查询只推送一次结果，订阅持续推送直到客户端发送complete或连接关闭
+func (h *Handler) runGraphQLOperation(ctx context.Context, conn *graphQLWSConn, id string, req *model.GraphQLRequest) {
+	finished := false
+	defer func() {
+		conn.mutex.Lock()
+		stop, exists := conn.subscriptions[id]
+		delete(conn.subscriptions, id)
+		conn.mutex.Unlock()
+		// 客户端已发送complete时不再回复complete
+		if exists {
+			stop()
+			if finished {
+				conn.send(id, "complete", nil)
+			}
+		}
+	}()
+
+	responses, err := h.graphqlSchema.Subscribe(ctx, graphql.Params{
+		Query:         req.Query,
+		Variables:     req.Variables,
+		OperationName: req.OperationName,
+	})
+	if err != nil {
+		var gqlErr *graphql.Error
+		if !errors.As(err, &gqlErr) {
+			gqlErr = &graphql.Error{Message: err.Error()}
+		}
+		conn.send(id, "error", []*graphql.Error{gqlErr})
+		return
+	}
+
+	for resp := range responses {
+		if err := conn.send(id, "next", resp); err != nil {
+			return
+		}
+	}
+	finished = ctx.Err() == nil
+}
+
+// graphQLHandshake 在同源校验之外协商 graphql-transport-ws 子协议
+func graphQLHandshake(config *websocket.Config, req *http.Request) error {
+	if err := sameOriginHandshake(config, req); err != nil {
+		return err
+	}
+	for _, protocol := range config.Protocol {
+		if protocol == graphQLWSProtocol {
+			config.Protocol = []string{graphQLWSProtocol}
+			return nil
+		}
+	}
+	return fmt.Errorf("websocket subprotocol %s is required", graphQLWSProtocol)
+}
@@ -0,0 +1,534 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	gql "github.com/graphql-go/graphql"
+
+	"whatsapp-aggregator/internal/graphql"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// graphQLViewer GraphQL请求的调用方，租户调用方只能查询本租户的账号、消息和事件
+type graphQLViewer struct {
+	tenantID string
+	scoped   bool
+}
+
+type graphQLViewerKey struct{}
+
+func withGraphQLViewer(ctx context.Context, viewer graphQLViewer) context.Context {
+	return context.WithValue(ctx, graphQLViewerKey{}, viewer)
+}
+
+func viewerFrom(ctx context.Context) graphQLViewer {
+	viewer, _ := ctx.Value(graphQLViewerKey{}).(graphQLViewer)
+	return viewer
+}
+
+// graphQLConnection 分页列表字段的结果
+type graphQLConnection struct {
+	Total int64       `json:"total"`
+	Nodes interface{} `json:"nodes"`
+}
+
+// errGraphQLAccountNotFound 账号不存在或不属于调用方租户时返回，不区分两种情况
+var errGraphQLAccountNotFound = errors.New("account not found")
+
+// graphQLAccount 读取调用方可见的账号
+func (h *Handler) graphQLAccount(ctx context.Context, accountID string) (*model.Account, error) {
+	viewer := viewerFrom(ctx)
+	if viewer.scoped && !h.manager.AccountInTenant(accountID, viewer.tenantID) {
+		return nil, errGraphQLAccountNotFound
+	}
+	account, err := h.manager.GetAccount(accountID)
+	if err != nil {
+		return nil, errGraphQLAccountNotFound
+	}
+	return account, nil
+}
+
+// graphQLListQuery 把分页、排序和过滤参数转换为列表查询，过滤参数名为驼峰形式，对应蛇形的列名
+func graphQLListQuery(args map[string]interface{}, filters ...string) *model.ListQuery {
+	q := &model.ListQuery{Filters: make(map[string]string)}
+	q.Limit, _ = args["limit"].(int)
+	if q.Limit < 0 {
+		q.Limit = 0
+	}
+	if q.Limit > maxListLimit {
+		q.Limit = maxListLimit
+	}
+	if offset, _ := args["offset"].(int); offset > 0 {
+		q.Offset = offset
+	}
+	if sort, _ := args["sort"].(string); sort != "" {
+		for _, field := range strings.Split(sort, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				q.Sort = append(q.Sort, field)
+			}
+		}
+	}
+	for _, name := range filters {
+		switch value := args[name].(type) {
+		case string:
+			q.Filters[graphQLColumn(name)] = value
+		case []interface{}:
+			values := make([]string, 0, len(value))
+			for _, v := range value {
+				values = append(values, fmt.Sprint(v))
+			}
+			if len(values) > 0 {
+				q.Filters[graphQLColumn(name)] = strings.Join(values, ",")
+			}
+		}
+	}
+	return q
+}
+
+// graphQLColumn 参数名转换为列名，如 hostId -> host_id
+func graphQLColumn(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		if 'A' <= r && r <= 'Z' {
+			b.WriteByte('_')
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// stringSet 列表参数转换为集合，未传时返回空集合表示不过滤
+func stringSet(value interface{}) map[string]bool {
+	set := make(map[string]bool)
+	items, _ := value.([]interface{})
+	for _, item := range items {
+		if s, ok := item.(string); ok && s != "" {
+			set[s] = true
+		}
+	}
+	return set
+}
+
+// graphQLEventFilter 事件查询和订阅共用的过滤条件
+type graphQLEventFilter struct {
+	viewer     graphQLViewer
+	types      map[string]bool
+	accountIDs map[string]bool
+	manager    *service.Manager
+}
+
+func (f *graphQLEventFilter) match(event *model.Event) bool {
+	if len(f.types) > 0 && !f.types[event.Type] {
+		return false
+	}
+	if len(f.accountIDs) > 0 && !f.accountIDs[event.AccountID] {
+		return false
+	}
+	// 租户只能看到本租户账号的事件，主机和系统事件不属于任何租户
+	if f.viewer.scoped && (event.AccountID == "" || !f.manager.AccountInTenant(event.AccountID, f.viewer.tenantID)) {
+		return false
+	}
+	return true
+}
+
+func (h *Handler) graphQLEventFilter(p gql.ResolveParams) *graphQLEventFilter {
+	return &graphQLEventFilter{
+		viewer:     viewerFrom(p.Context),
+		types:      stringSet(p.Args["types"]),
+		accountIDs: stringSet(p.Args["accountIds"]),
+		manager:    h.manager,
+	}
+}
+
+// newGraphQLSchema 面板查询使用的Schema：账号、消息、统计和事件，字段与REST接口的JSON字段一一对应（驼峰命名）
+func (h *Handler) newGraphQLSchema() *graphql.Schema {
+	pageArgs := func(defaultLimit int, extra gql.FieldConfigArgument) gql.FieldConfigArgument {
+		args := gql.FieldConfigArgument{
+			"sort":   &gql.ArgumentConfig{Type: gql.String, Description: "排序字段，逗号分隔，- 前缀表示降序，与REST列表接口的sort参数相同"},
+			"limit":  &gql.ArgumentConfig{Type: gql.Int, DefaultValue: defaultLimit, Description: fmt.Sprintf("每页条数，最大%d", maxListLimit)},
+			"offset": &gql.ArgumentConfig{Type: gql.Int, DefaultValue: 0},
+		}
+		for name, arg := range extra {
+			args[name] = arg
+		}
+		return args
+	}
+	connection := func(name string, node *gql.Object) *gql.Object {
+		return gql.NewObject(gql.ObjectConfig{
+			Name: name,
+			Fields: gql.Fields{
+				"total": &gql.Field{Type: gql.NewNonNull(gql.Int), Description: "过滤后的总数"},
+				"nodes": &gql.Field{Type: graphql.NonNullList(node)},
+			},
+		})
+	}
+
+	statusTransition := gql.NewObject(gql.ObjectConfig{
+		Name:        "StatusTransition",
+		Description: "账号状态变化记录",
+		Fields: gql.Fields{
+			"id":        &gql.Field{Type: gql.NewNonNull(gql.ID)},
+			"accountId": &gql.Field{Type: gql.NewNonNull(gql.ID)},
+			"previous":  &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"status":    &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"source":    &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"reason":    &gql.Field{Type: gql.String},
+			"requestId": &gql.Field{Type: gql.String},
+			"createdAt": &gql.Field{Type: gql.NewNonNull(graphql.Time)},
+		},
+	})
+
+	// 账号和消息互相引用，消息列表字段在消息类型定义后再加到账号上
+	account := gql.NewObject(gql.ObjectConfig{
+		Name:        "Account",
+		Description: "WhatsApp账号及其Worker",
+		Fields: gql.Fields{
+			"id":                 &gql.Field{Type: gql.NewNonNull(gql.ID)},
+			"name":               &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"phone":              &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"status":             &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"notes":              &gql.Field{Type: gql.String},
+			"tags":               &gql.Field{Type: gql.NewList(gql.NewNonNull(gql.String))},
+			"pool":               &gql.Field{Type: gql.String},
+			"tenantId":           &gql.Field{Type: gql.String},
+			"hostId":             &gql.Field{Type: gql.String},
+			"port":               &gql.Field{Type: gql.Int},
+			"proxyRegion":        &gql.Field{Type: gql.String},
+			"ownerOperator":      &gql.Field{Type: gql.String},
+			"ownerTeam":          &gql.Field{Type: gql.String},
+			"ownerEmail":         &gql.Field{Type: gql.String},
+			"messagesSent":       &gql.Field{Type: gql.NewNonNull(gql.Int)},
+			"messagesReceived":   &gql.Field{Type: gql.NewNonNull(gql.Int)},
+			"mediaSent":          &gql.Field{Type: gql.NewNonNull(gql.Int)},
+			"lastActivity":       &gql.Field{Type: graphql.Time},
+			"sessionStartedAt":   &gql.Field{Type: graphql.Time},
+			"sessionDrops":       &gql.Field{Type: gql.NewNonNull(gql.Int)},
+			"restartCount":       &gql.Field{Type: gql.NewNonNull(gql.Int)},
+			"workerVersion":      &gql.Field{Type: gql.String},
+			"workerIncompatible": &gql.Field{Type: gql.NewNonNull(gql.Boolean)},
+			"disabled":           &gql.Field{Type: gql.NewNonNull(gql.Boolean)},
+			"disabledReason":     &gql.Field{Type: gql.String},
+			"warmupProfile":      &gql.Field{Type: gql.String},
+			"createdAt":          &gql.Field{Type: gql.NewNonNull(graphql.Time)},
+			"updatedAt":          &gql.Field{Type: gql.NewNonNull(graphql.Time)},
+			"statusHistory": &gql.Field{
+				Type:        gql.NewNonNull(connection("StatusTransitionConnection", statusTransition)),
+				Description: "状态变化历史，默认最新的在前",
+				Args:        pageArgs(20, nil),
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					q := graphQLListQuery(p.Args)
+					if len(q.Sort) == 0 {
+						q.Sort = []string{"-created_at"}
+					}
+					history, total, err := h.manager.ListStatusHistory(p.Source.(*model.Account).ID, q)
+					if err != nil {
+						return nil, err
+					}
+					return &graphQLConnection{Total: total, Nodes: history}, nil
+				},
+			},
+			"sessionHealth": &gql.Field{
+				Type:        graphql.JSON,
+				Description: "与 GET /accounts/{id}/session 相同",
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return h.manager.GetSessionHealth(p.Source.(*model.Account).ID)
+				},
+			},
+			"sendQuota": &gql.Field{
+				Type:        graphql.JSON,
+				Description: "与 GET /accounts/{id}/quota 相同",
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return h.manager.GetSendQuota(p.Source.(*model.Account).ID)
+				},
+			},
+			"sla": &gql.Field{
+				Type:        graphql.JSON,
+				Description: "与 GET /accounts/{id}/sla 相同",
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return h.manager.GetAccountSLA(p.Source.(*model.Account).ID)
+				},
+			},
+		},
+	})
+	accountField := &gql.Field{
+		Type:        account,
+		Description: "所属账号，账号已删除时为null",
+		Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			var accountID string
+			switch source := p.Source.(type) {
+			case *model.Message:
+				accountID = source.AccountID
+			case *model.Event:
+				accountID = source.AccountID
+			}
+			if accountID == "" {
+				return nil, nil
+			}
+			account, err := h.graphQLAccount(p.Context, accountID)
+			if err != nil {
+				return nil, nil
+			}
+			return account, nil
+		},
+	}
+
+	message := gql.NewObject(gql.ObjectConfig{
+		Name:        "Message",
+		Description: "消息记录",
+		Fields: gql.Fields{
+			"id":          &gql.Field{Type: gql.NewNonNull(gql.ID)},
+			"accountId":   &gql.Field{Type: gql.NewNonNull(gql.ID)},
+			"direction":   &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"contact":     &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"type":        &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"body":        &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"preview":     &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"mimeType":    &gql.Field{Type: gql.String},
+			"fileName":    &gql.Field{Type: gql.String},
+			"mediaSize":   &gql.Field{Type: gql.Int},
+			"status":      &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"campaign":    &gql.Field{Type: gql.String},
+			"error":       &gql.Field{Type: gql.String},
+			"attempts":    &gql.Field{Type: gql.Int},
+			"timestamp":   &gql.Field{Type: gql.NewNonNull(graphql.Time)},
+			"deliveredAt": &gql.Field{Type: graphql.Time},
+			"seenAt":      &gql.Field{Type: graphql.Time},
+			"readAt":      &gql.Field{Type: graphql.Time},
+			"createdAt":   &gql.Field{Type: gql.NewNonNull(graphql.Time)},
+			"account":     accountField,
+		},
+	})
+	messageFilters := func() gql.FieldConfigArgument {
+		return gql.FieldConfigArgument{
+			"direction": &gql.ArgumentConfig{Type: gql.String, Description: "inbound 或 outbound"},
+			"contact":   &gql.ArgumentConfig{Type: gql.String},
+			"status":    &gql.ArgumentConfig{Type: gql.String, Description: "sent、delivered、read、failed、retrying、received，逗号分隔"},
+			"campaign":  &gql.ArgumentConfig{Type: gql.String},
+		}
+	}
+	listMessages := func(accountID string, args map[string]interface{}) (interface{}, error) {
+		q := graphQLListQuery(args, "direction", "contact", "status", "campaign")
+		messages, total, err := h.manager.ListMessages(accountID, q)
+		if err != nil {
+			return nil, err
+		}
+		return &graphQLConnection{Total: total, Nodes: messages}, nil
+	}
+	messageConnection := connection("MessageConnection", message)
+
+	account.AddFieldConfig("messages", &gql.Field{
+		Type:        gql.NewNonNull(messageConnection),
+		Description: "数据库中的消息历史，不会先从Worker同步",
+		Args:        pageArgs(20, messageFilters()),
+		Resolve: func(p gql.ResolveParams) (interface{}, error) {
+			return listMessages(p.Source.(*model.Account).ID, p.Args)
+		},
+	})
+
+	statsPoint := gql.NewObject(gql.ObjectConfig{
+		Name:        "StatsPoint",
+		Description: "统计时间序列中的一个周期",
+		Fields: gql.Fields{
+			"period":        &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"from":          &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"to":            &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"sent":          &gql.Field{Type: gql.NewNonNull(gql.Int)},
+			"received":      &gql.Field{Type: gql.NewNonNull(gql.Int)},
+			"failed":        &gql.Field{Type: gql.NewNonNull(gql.Int)},
+			"uptimeSeconds": &gql.Field{Type: gql.NewNonNull(gql.Int)},
+			"accounts":      &gql.Field{Type: gql.NewNonNull(gql.Int)},
+		},
+	})
+	stats := gql.NewObject(gql.ObjectConfig{
+		Name:        "Stats",
+		Description: "集群统计，与 GET /stats 相同",
+		Fields: gql.Fields{
+			"totalWorkers":   &gql.Field{Type: gql.NewNonNull(gql.Int)},
+			"onlineWorkers":  &gql.Field{Type: gql.NewNonNull(gql.Int)},
+			"todayMessages":  &gql.Field{Type: gql.NewNonNull(gql.Int)},
+			"activeContacts": &gql.Field{Type: gql.NewNonNull(gql.Int)},
+			"breakdowns":     &gql.Field{Type: graphql.JSON, Description: "按 tag、pool、tenant、proxy_region、host 分组"},
+			"availability":   &gql.Field{Type: graphql.JSON, Description: "24h、7d、30d 可用性"},
+			"series":         &gql.Field{Type: gql.NewList(gql.NewNonNull(statsPoint)), Description: "指定 from、to、granularity 或 accountId 时返回"},
+		},
+	})
+
+	event := gql.NewObject(gql.ObjectConfig{
+		Name:        "Event",
+		Description: "与 /events 推送的事件相同",
+		Fields: gql.Fields{
+			"type":      &gql.Field{Type: gql.NewNonNull(gql.String)},
+			"accountId": &gql.Field{Type: gql.ID},
+			"data":      &gql.Field{Type: graphql.JSON},
+			"timestamp": &gql.Field{Type: gql.NewNonNull(graphql.Time)},
+			"account":   accountField,
+		},
+	})
+	eventArgs := func() gql.FieldConfigArgument {
+		return gql.FieldConfigArgument{
+			"types":      &gql.ArgumentConfig{Type: gql.NewList(gql.NewNonNull(gql.String)), Description: "只返回这些类型的事件，如 account.status_changed"},
+			"accountIds": &gql.ArgumentConfig{Type: gql.NewList(gql.NewNonNull(gql.ID))},
+		}
+	}
+
+	accountsArgs := pageArgs(50, gql.FieldConfigArgument{
+		"status": &gql.ArgumentConfig{Type: gql.NewList(gql.NewNonNull(gql.String))},
+		"pool":   &gql.ArgumentConfig{Type: gql.String},
+		"tags":   &gql.ArgumentConfig{Type: gql.NewList(gql.NewNonNull(gql.String)), Description: "包含任一标签的账号"},
+		"hostId": &gql.ArgumentConfig{Type: gql.String},
+		"owner":  &gql.ArgumentConfig{Type: gql.String, Description: "负责人或负责团队"},
+	})
+	messagesArgs := pageArgs(50, messageFilters())
+	messagesArgs["accountId"] = &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)}
+	recentEventsArgs := eventArgs()
+	recentEventsArgs["limit"] = &gql.ArgumentConfig{Type: gql.Int, DefaultValue: 50}
+
+	query := gql.NewObject(gql.ObjectConfig{
+		Name: "Query",
+		Fields: gql.Fields{
+			"accounts": &gql.Field{
+				Type:        gql.NewNonNull(connection("AccountConnection", account)),
+				Description: "账号列表，过滤和排序与 GET /accounts 相同，默认按创建时间升序",
+				Args:        accountsArgs,
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					q := graphQLListQuery(p.Args, "status", "pool", "tags", "hostId")
+					viewer := viewerFrom(p.Context)
+					scope := service.AccountListScope{}
+					scope.Owner, _ = p.Args["owner"].(string)
+					if viewer.scoped {
+						scope.TenantID = viewer.tenantID
+					}
+					accounts, total, err := h.manager.QueryAccounts(q, scope)
+					if err != nil {
+						return nil, err
+					}
+					return &graphQLConnection{Total: total, Nodes: accounts}, nil
+				},
+			},
+			"account": &gql.Field{
+				Type: account,
+				Args: gql.FieldConfigArgument{"id": &gql.ArgumentConfig{Type: gql.NewNonNull(gql.ID)}},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					return h.graphQLAccount(p.Context, p.Args["id"].(string))
+				},
+			},
+			"messages": &gql.Field{
+				Type:        gql.NewNonNull(messageConnection),
+				Description: "账号的消息历史，默认最新的在前",
+				Args:        messagesArgs,
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					accountID := p.Args["accountId"].(string)
+					if _, err := h.graphQLAccount(p.Context, accountID); err != nil {
+						return nil, err
+					}
+					return listMessages(accountID, p.Args)
+				},
+			},
+			"stats": &gql.Field{
+				Type:        stats,
+				Description: "集群统计，仅管理员可查询",
+				Args: gql.FieldConfigArgument{
+					"from":        &gql.ArgumentConfig{Type: gql.String, Description: "开始日期 YYYY-MM-DD"},
+					"to":          &gql.ArgumentConfig{Type: gql.String, Description: "结束日期 YYYY-MM-DD"},
+					"granularity": &gql.ArgumentConfig{Type: gql.String, Description: "day、week 或 month"},
+					"accountId":   &gql.ArgumentConfig{Type: gql.ID},
+				},
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					if viewerFrom(p.Context).scoped {
+						return nil, errors.New("stats requires admin token")
+					}
+					var q model.StatsQuery
+					q.From, _ = p.Args["from"].(string)
+					q.To, _ = p.Args["to"].(string)
+					q.Granularity, _ = p.Args["granularity"].(string)
+					q.AccountID, _ = p.Args["accountId"].(string)
+					switch q.Granularity {
+					case "", service.StatsGranularityDay, service.StatsGranularityWeek, service.StatsGranularityMonth:
+					default:
+						return nil, fmt.Errorf("invalid granularity %q: must be day, week or month", q.Granularity)
+					}
+
+					result := h.manager.GetStats()
+					if q != (model.StatsQuery{}) {
+						series, err := h.manager.StatsSeries(&q)
+						if err != nil {
+							return nil, err
+						}
+						result.Series = series
+					}
+					return result, nil
+				},
+			},
+			"events": &gql.Field{
+				Type:        graphql.NonNullList(event),
+				Description: "最近发布的事件（最多500条），最新的在前",
+				Args:        recentEventsArgs,
+				Resolve: func(p gql.ResolveParams) (interface{}, error) {
+					filter := h.graphQLEventFilter(p)
+					limit, _ := p.Args["limit"].(int)
+					events := make([]*model.Event, 0)
+					for _, e := range h.manager.Events().Recent() {
+						if limit > 0 && len(events) >= limit {
+							break
+						}
+						if filter.match(e) {
+							events = append(events, e)
+						}
+					}
+					return events, nil
+				},
+			},
+		},
+	})
+
+	subscription := gql.NewObject(gql.ObjectConfig{
+		Name: "Subscription",
+		Fields: gql.Fields{
+			"events": &gql.Field{
+				Type:        gql.NewNonNull(event),
+				Description: "实时事件，与 /events 相同，订阅者处理不过来时丢弃事件",
+				Args:        eventArgs(),
+				Subscribe: func(p gql.ResolveParams) (interface{}, error) {
+					filter := h.graphQLEventFilter(p)
+					events, unsubscribe := h.manager.Events().Subscribe(64)
+					out := make(chan interface{})
+					go func() {
+						defer close(out)
+						defer unsubscribe()
+						for {
+							select {
+							case <-p.Context.Done():
+								return
+							case e, ok := <-events:
+								if !ok {
+									return
+								}
+								if !filter.match(e) {
+									continue
+								}
+								select {
+								case out <- e:
+								case <-p.Context.Done():
+									return
+								}
+							}
+						}
+					}()
+					return out, nil
+				},
+			},
+		},
+	})
+
+	schema, err := graphql.NewSchema(gql.SchemaConfig{Query: query, Subscription: subscription})
+	if err != nil {
+		// Schema是静态定义的，出错说明定义本身有误
+		panic(fmt.Sprintf("graphql schema: %v", err))
+	}
+	return schema
+}
@@ -0,0 +1,272 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// ListGroups 获取账号群组
+// @Summary List Groups
+// @Description List the account's groups from the master cache. The cache is refreshed from the worker when it is empty, older than GROUP_CACHE_SECONDS or refresh=true; if the refresh fails the cached groups are returned with a warning.
+// @Tags Group
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param refresh query bool false "Refresh from the worker first"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending"
+// @Success 200 {object} model.APIResponse{data=[]model.Group}
+// @Router /accounts/{id}/groups [get]
+func (h *Handler) ListGroups(c *gin.Context) {
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if !h.accountExists(c, c.Param("id")) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	groups, warning, err := h.manager.ListGroups(ctx, c.Param("id"), c.Query("refresh") == "true")
+	if err != nil {
+		respond(c, workerErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to fetch data from worker",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	page, meta, err := applyListQuery(groups, q)
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to build list response",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Groups retrieved successfully",
+		Data:    page,
+		Meta:    meta,
+		Warning: warning,
+	})
+}
+
+// GetGroup 获取群组详情
+// @Summary Get Group
+// @Description Get a group with its participants and admins, refreshed from the worker when the cache is stale or refresh=true
+// @Tags Group
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param gid path string true "Group ID"
+// @Param refresh query bool false "Refresh from the worker first"
+// @Success 200 {object} model.APIResponse{data=model.Group}
+// @Router /accounts/{id}/groups/{gid} [get]
+func (h *Handler) GetGroup(c *gin.Context) {
+	if !h.accountExists(c, c.Param("id")) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	group, err := h.manager.GetGroup(ctx, c.Param("id"), c.Param("gid"), c.Query("refresh") == "true")
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Group not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Group retrieved successfully",
+		Data:    group,
+	})
+}
+
+// RenameGroup 修改群组名称
+// @Summary Rename Group
+// @Description Change the group subject. The account must be a group admin.
+// @Tags Group
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param gid path string true "Group ID"
+// @Param request body model.RenameGroupRequest true "New name"
+// @Success 200 {object} model.APIResponse{data=model.Group}
+// @Router /accounts/{id}/groups/{gid} [put]
+func (h *Handler) RenameGroup(c *gin.Context) {
+	var req model.RenameGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if !h.accountExists(c, c.Param("id")) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 30*time.Second)
+	defer cancel()
+
+	group, err := h.manager.RenameGroup(ctx, c.Param("id"), c.Param("gid"), req.Name)
+	if err != nil {
+		respond(c, workerErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to update group",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Group updated successfully",
+		Data:    group,
+	})
+}
+
+// RemoveGroupParticipants 移除群组成员
+// @Summary Remove Group Participants
+// @Description Remove participants from a group. The account must be a group admin.
+// @Tags Group
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param gid path string true "Group ID"
+// @Param request body model.GroupParticipantsRequest true "Participants"
+// @Success 200 {object} model.APIResponse{data=model.Group}
+// @Router /accounts/{id}/groups/{gid}/participants/remove [post]
+func (h *Handler) RemoveGroupParticipants(c *gin.Context) {
+	h.updateGroupParticipants(c, service.GroupActionRemove)
+}
+
+// PromoteGroupAdmins 设为群组管理员
+// @Summary Promote Group Admins
+// @Description Make participants group admins. The account must be a group admin.
+// @Tags Group
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param gid path string true "Group ID"
+// @Param request body model.GroupParticipantsRequest true "Participants"
+// @Success 200 {object} model.APIResponse{data=model.Group}
+// @Router /accounts/{id}/groups/{gid}/admins/promote [post]
+func (h *Handler) PromoteGroupAdmins(c *gin.Context) {
+	h.updateGroupParticipants(c, service.GroupActionPromote)
+}
+
+// DemoteGroupAdmins 取消群组管理员
+// @Summary Demote Group Admins
+// @Description Remove admin rights from participants. The account must be a group admin.
+// @Tags Group
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param gid path string true "Group ID"
+// @Param request body model.GroupParticipantsRequest true "Participants"
+// @Success 200 {object} model.APIResponse{data=model.Group}
+// @Router /accounts/{id}/groups/{gid}/admins/demote [post]
+func (h *Handler) DemoteGroupAdmins(c *gin.Context) {
+	h.updateGroupParticipants(c, service.GroupActionDemote)
+}
+
+// updateGroupParticipants 执行群组成员操作并返回刷新后的群组
+func (h *Handler) updateGroupParticipants(c *gin.Context, action string) {
+	var req model.GroupParticipantsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if !h.accountExists(c, c.Param("id")) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 30*time.Second)
+	defer cancel()
+
+	group, err := h.manager.UpdateGroupParticipants(ctx, c.Param("id"), c.Param("gid"), action, req.Participants)
+	if err != nil {
+		respond(c, workerErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to update group",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Group updated successfully",
+		Data:    group,
+	})
+}
+
+// GetGroupInviteLink 获取群组邀请链接
+// @Summary Get Group Invite Link
+// @Description Get the group's invite link from the worker. The account must be a group admin.
+// @Tags Group
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param gid path string true "Group ID"
+// @Success 200 {object} model.APIResponse
+// @Router /accounts/{id}/groups/{gid}/invite-link [get]
+func (h *Handler) GetGroupInviteLink(c *gin.Context) {
+	if !h.accountExists(c, c.Param("id")) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	link, err := h.manager.GroupInviteLink(ctx, c.Param("id"), c.Param("gid"))
+	if err != nil {
+		respond(c, workerErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to get group invite link",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Group invite link retrieved successfully",
+		Data:    gin.H{"group_id": c.Param("gid"), "invite_link": link},
+	})
+}
+
+// accountExists 账号不存在时返回404
+func (h *Handler) accountExists(c *gin.Context, accountID string) bool {
+	if _, err := h.manager.GetAccount(accountID); err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return false
+	}
+	return true
+}
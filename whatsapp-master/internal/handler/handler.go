@@ -1,12 +1,8 @@
 package handler
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
-	"fmt"
-	"io"
-	"log"
+	"errors"
 	"net/http"
 	"time"
 
@@ -15,6 +11,8 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 
 	_ "whatsapp-aggregator/docs"
+	"whatsapp-aggregator/internal/graphql"
+	"whatsapp-aggregator/internal/logging"
 	"whatsapp-aggregator/internal/middleware"
 	"whatsapp-aggregator/internal/model"
 	"whatsapp-aggregator/internal/service"
@@ -22,56 +20,135 @@ import (
 
 // Handler HTTP处理器
 type Handler struct {
-	manager *service.Manager
+	manager       *service.Manager
+	graphqlSchema *graphql.Schema
 }
 
 // NewHandler 创建处理器
 func NewHandler(manager *service.Manager) *Handler {
-	return &Handler{
+	registerFieldNames()
+	h := &Handler{
 		manager: manager,
 	}
+	h.graphqlSchema = h.newGraphQLSchema()
+	return h
 }
 
 // CreateAccount 创建账号
 // @Summary Create Account
-// @Description Create a new WhatsApp account worker
+// @Description Create a new WhatsApp account worker. With async=true the account is registered with status creating and the request returns a create_account job immediately; poll GET /jobs/{id} for the spawn stage (pulling_image, restoring_backup, starting, unsealing_session, waiting_ready), the created account or the error. With restore_backup=true the session directory is replaced by the account's latest successful backup before the worker starts, so a logged-in session can be brought back on a new host; returns 404 when the account has no backup. Returns 503 when the port pool has no free port; extend worker.portRanges through PUT /config. When the worker does not become ready within WORKER_READY_TIMEOUT_SECONDS (or its container exits first), data carries the container state, exit code, recent logs and docker inspect output; the same diagnostics are kept in the account's status history.
 // @Tags Account
 // @Accept json
 // @Produce json
 // @Param request body model.LoginRequest true "Login Request"
+// @Param async query bool false "Create the account in a background job"
 // @Success 200 {object} model.APIResponse
+// @Success 202 {object} model.APIResponse{data=model.Job}
+// @Failure 404 {object} model.APIResponse
+// @Failure 500 {object} model.APIResponse{data=model.WorkerStartupDiagnostics}
+// @Failure 503 {object} model.APIResponse
 // @Router /accounts [post]
 func (h *Handler) CreateAccount(c *gin.Context) {
 	var req model.LoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, model.APIResponse{
-			Success: false,
-			Message: "Invalid request format",
-			Error:   err.Error(),
+		respondBindError(c, err)
+		return
+	}
+
+	if tenantID, scoped := middleware.TenantID(c); scoped {
+		req.TenantID = tenantID
+		// 主机调度和挂载宿主机目录只对管理员开放
+		req.HostID = ""
+		if req.Runtime != nil {
+			req.Runtime.Volumes = nil
+		}
+	}
+
+	if c.Query("async") == "true" {
+		job, err := h.manager.CreateAccountAsync(c.Request.Context(), &req)
+		if err != nil {
+			respond(c, createErrorStatus(err, http.StatusBadRequest), model.APIResponse{
+				Success: false,
+				Message: "Failed to create account",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		respond(c, http.StatusAccepted, model.APIResponse{
+			Success: true,
+			Message: "Account creation started",
+			Data:    job,
 		})
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 5*time.Minute)
 	defer cancel()
 
 	account, err := h.manager.CreateAccount(ctx, &req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.APIResponse{
+		respond(c, createErrorStatus(err, http.StatusInternalServerError), model.APIResponse{
 			Success: false,
 			Message: "Failed to create account",
+			Data:    startupDiagnostics(err),
 			Error:   err.Error(),
 		})
 		return
 	}
 
-	c.JSON(http.StatusOK, model.APIResponse{
+	respond(c, http.StatusOK, model.APIResponse{
 		Success: true,
 		Message: "Account created successfully",
 		Data:    account,
 	})
 }
 
+// UpdateAccount 修改账号信息
+// @Summary Update Account
+// @Description Change the account's name, notes, tags, owner, status poll interval or hardware profile. Fields that are not sent stay unchanged; tags replace the whole list and owner replaces all owner fields. status_poll_seconds sets how often the worker status is polled while the account is stable (0 or at least 5; 0 uses statusPoll.intervalSeconds). hardware_profile_id binds a profile from /hardware-profiles ("" unbinds it); it takes effect the next time the worker is started or logs in.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param request body model.UpdateAccountRequest true "Account fields"
+// @Success 200 {object} model.APIResponse{data=model.Account}
+// @Failure 400 {object} model.APIResponse
+// @Failure 404 {object} model.APIResponse
+// @Router /accounts/{id} [patch]
+func (h *Handler) UpdateAccount(c *gin.Context) {
+	var req model.UpdateAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	accountID := c.Param("id")
+	if !h.accountExists(c, accountID) {
+		return
+	}
+
+	account, err := h.manager.UpdateAccount(accountID, &req)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrHardwareProfileNotFound) {
+			status = http.StatusNotFound
+		}
+		respond(c, status, model.APIResponse{
+			Success: false,
+			Message: "Failed to update account",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Account updated successfully",
+		Data:    account,
+	})
+}
+
 // GetAccount 获取账号信息
 // @Summary Get Account
 // @Description Get account details by ID
@@ -83,7 +160,7 @@ func (h *Handler) CreateAccount(c *gin.Context) {
 func (h *Handler) GetAccount(c *gin.Context) {
 	accountID := c.Param("id")
 	if accountID == "" {
-		c.JSON(http.StatusBadRequest, model.APIResponse{
+		respond(c, http.StatusBadRequest, model.APIResponse{
 			Success: false,
 			Message: "Account ID is required",
 		})
@@ -92,7 +169,7 @@ func (h *Handler) GetAccount(c *gin.Context) {
 
 	account, err := h.manager.GetAccount(accountID)
 	if err != nil {
-		c.JSON(http.StatusNotFound, model.APIResponse{
+		respond(c, http.StatusNotFound, model.APIResponse{
 			Success: false,
 			Message: "Account not found",
 			Error:   err.Error(),
@@ -100,7 +177,7 @@ func (h *Handler) GetAccount(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, model.APIResponse{
+	respond(c, http.StatusOK, model.APIResponse{
 		Success: true,
 		Message: "Account retrieved successfully",
 		Data:    account,
@@ -109,44 +186,78 @@ func (h *Handler) GetAccount(c *gin.Context) {
 
 // ListAccounts 列出所有账号
 // @Summary List Accounts
-// @Description Get all registered accounts
+// @Description List accounts, filtered, sorted and paged in the database. Without sort the accounts are ordered by created_at. filter[tags] matches accounts with any of the tags.
 // @Tags Account
 // @Produce json
-// @Success 200 {object} model.APIResponse
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param offset query int false "Number of accounts to skip, instead of cursor"
+// @Param sort query string false "Sort fields (created_at, last_activity, status, name, phone, messages_sent, ...), prefix with - for descending (e.g. -last_activity)"
+// @Param filter[status] query string false "Filter by field, comma separated values"
+// @Param filter[pool] query string false "Filter by pool"
+// @Param filter[tags] query string false "Accounts with any of these tags"
+// @Param filter[host_id] query string false "Filter by host"
+// @Param owner query string false "Only accounts assigned to this operator or team"
+// @Param filter[owner_operator] query string false "Filter by owner operator"
+// @Param filter[owner_team] query string false "Filter by owner team"
+// @Param filter[owner_email] query string false "Filter by owner email"
+// @Success 200 {object} model.APIResponse{data=[]model.Account}
+// @Failure 400 {object} model.APIResponse
 // @Router /accounts [get]
 func (h *Handler) ListAccounts(c *gin.Context) {
-	accounts := h.manager.ListAccounts()
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
 
-	c.JSON(http.StatusOK, model.APIResponse{
-		Success: true,
-		Message: "Accounts retrieved successfully",
-		Data:    accounts,
-	})
+	scope := service.AccountListScope{Owner: c.Query("owner")}
+	scope.TenantID, _ = middleware.TenantID(c)
+	accounts, total, err := h.manager.QueryAccounts(q, scope)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list accounts",
+			Error:   err.Error(),
+		})
+		return
+	}
+	respondPage(c, accounts, buildListMeta(q, total, len(accounts)), "Accounts retrieved successfully")
 }
 
 // DeleteAccount 删除账号
 // @Summary Delete Account
-// @Description Delete an account by ID
+// @Description Delete an account by ID. With purge_session=true the account's session data is overwritten and removed right away; otherwise it is purged by the janitor SESSION_RETENTION_DAYS after deletion.
 // @Tags Account
 // @Produce json
 // @Param id path string true "Account ID"
-// @Success 200 {object} model.APIResponse
+// @Param purge_session query bool false "Securely remove the session data now"
+// @Success 200 {object} model.APIResponse{data=model.DeleteAccountResult}
+// @Failure 404 {object} model.APIResponse
 // @Router /accounts/{id} [delete]
 func (h *Handler) DeleteAccount(c *gin.Context) {
 	accountID := c.Param("id")
 	if accountID == "" {
-		c.JSON(http.StatusBadRequest, model.APIResponse{
+		respond(c, http.StatusBadRequest, model.APIResponse{
 			Success: false,
 			Message: "Account ID is required",
 		})
 		return
 	}
+	if !h.accountExists(c, accountID) {
+		return
+	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 2*time.Minute)
 	defer cancel()
 
-	if err := h.manager.DeleteAccount(ctx, accountID); err != nil {
-		c.JSON(http.StatusInternalServerError, model.APIResponse{
+	result, err := h.manager.DeleteAccount(ctx, accountID, c.Query("purge_session") == "true")
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
 			Success: false,
 			Message: "Failed to delete account",
 			Error:   err.Error(),
@@ -154,80 +265,77 @@ func (h *Handler) DeleteAccount(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, model.APIResponse{
+	resp := model.APIResponse{
 		Success: true,
 		Message: "Account deleted successfully",
-	})
+		Data:    result,
+	}
+	if result.PurgeError != "" {
+		resp.Warning = "account deleted but its session data could not be purged; the janitor will retry"
+	}
+	respond(c, http.StatusOK, resp)
 }
 
 // SendMessage 发送消息
 // @Summary Send Message
-// @Description Send a WhatsApp message
+// @Description Send a WhatsApp message. {{contact}} and {{key}} placeholders in message are replaced from variables as in bulk sends. Responses carry X-RateLimit-* and X-Quota-* headers, include a warning when the remaining quota is low, and return 429 once it is exhausted. data.message_id identifies the message for /messages/{id}/status. With dry_run=true the request runs the same account, recipient, rate limit and quota checks and returns the rendered message without contacting the worker, using up quota or recording the message; the response status matches what a real send would return up to the worker call.
 // @Tags Message
 // @Accept json
 // @Produce json
 // @Param request body model.MessageRequest true "Message Request"
+// @Param dry_run query bool false "Validate and render only, do not send"
+// @Param Idempotency-Key header string false "Retries with the same key return the first response instead of sending again"
 // @Success 200 {object} model.APIResponse
+// @Success 200 {object} model.APIResponse{data=model.SendPreview} "dry_run=true"
 // @Router /send-message [post]
 func (h *Handler) SendMessage(c *gin.Context) {
 	var req model.MessageRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, model.APIResponse{
-			Success: false,
-			Message: "Invalid request format",
-			Error:   err.Error(),
-		})
+		respondBindError(c, err)
+		return
+	}
+	if !h.normalizePhones(c, &req) {
 		return
 	}
 
-	// 获取账号信息
-	account, err := h.manager.GetAccount(req.AccountID)
-	if err != nil {
-		c.JSON(http.StatusNotFound, model.APIResponse{
+	if !h.authorizeAccount(c, req.AccountID) {
+		return
+	}
+	if _, err := h.manager.GetAccount(req.AccountID); err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
 			Success: false,
 			Message: "Account not found",
 			Error:   err.Error(),
 		})
 		return
 	}
-
-	// 构造发送给Worker的请求
-	workerReq := map[string]string{
-		"contact": req.Contact,
-		"message": req.Message,
+	recipientWarning, ok := h.checkRecipient(c, "Failed to send message", req.AccountID, req.Contact)
+	if !ok {
+		return
 	}
-	jsonBody, _ := json.Marshal(workerReq)
-
-	// 发送请求到Worker
-	targetURL := fmt.Sprintf("%s/api/send-message", account.ServiceURL)
-	resp, err := http.Post(targetURL, "application/json", bytes.NewBuffer(jsonBody))
-	if err != nil {
-		c.JSON(http.StatusBadGateway, model.APIResponse{
-			Success: false,
-			Message: "Failed to connect to worker",
-			Error:   err.Error(),
-		})
+	if c.Query("dry_run") == "true" {
+		h.previewMessage(c, &req, recipientWarning)
+		return
+	}
+	if !h.allowSend(c, "Failed to send message", req.AccountID) {
 		return
 	}
-	defer resp.Body.Close()
 
-	// 复制Worker的响应
-	c.Status(resp.StatusCode)
-	for k, v := range resp.Header {
-		c.Writer.Header()[k] = v
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 2*time.Minute)
+	defer cancel()
+
+	result, err := h.manager.SendMessage(ctx, &req)
+	if err != nil {
+		h.respondSendError(c, req.AccountID, "Failed to send message", err)
+		return
 	}
-	io.Copy(c.Writer, resp.Body)
 
-	// 更新统计信息（异步）
-	go func() {
-		if resp.StatusCode == http.StatusOK {
-			// 这里应该有更好的方式更新统计，但暂时这样
-			account.MessagesSent++
-			now := time.Now()
-			account.LastActivity = &now
-			h.manager.UpdateAccountStatusSafe(account.ID, account.Status)
-		}
-	}()
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Message sent successfully",
+		Data:    result["data"],
+		Warning: joinWarnings(recipientWarning, h.setQuotaHeaders(c, req.AccountID)),
+	})
 }
 
 // GetContacts 获取联系人
@@ -236,24 +344,84 @@ func (h *Handler) SendMessage(c *gin.Context) {
 // @Tags Contact
 // @Produce json
 // @Param id path string true "Account ID"
-// @Success 200 {object} model.APIResponse
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending"
+// @Success 200 {object} model.APIResponse{data=[]model.WorkerContact}
 // @Router /accounts/{id}/contacts [get]
 func (h *Handler) GetContacts(c *gin.Context) {
-	accountID := c.Param("id")
-	h.proxyToWorker(c, accountID, "/api/contacts")
+	if !h.accountExists(c, c.Param("id")) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	contacts, err := h.manager.FetchWorkerContacts(ctx, c.Param("id"))
+	if err != nil {
+		respond(c, workerErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to fetch data from worker",
+			Error:   err.Error(),
+		})
+		return
+	}
+	respondList(c, contacts, "Contacts retrieved successfully")
 }
 
 // GetMessages 获取消息
 // @Summary Get Messages
-// @Description Get recent messages for a specific account
+// @Description Get message history for a specific account from the master database (recent inbound messages are synced from the worker first)
 // @Tags Message
 // @Produce json
 // @Param id path string true "Account ID"
-// @Success 200 {object} model.APIResponse
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending (default -timestamp)"
+// @Param filter[direction] query string false "inbound or outbound"
+// @Param filter[contact] query string false "Contact"
+// @Param filter[status] query string false "sent, failed, received"
+// @Success 200 {object} model.APIResponse{data=[]model.Message}
 // @Router /accounts/{id}/messages [get]
 func (h *Handler) GetMessages(c *gin.Context) {
 	accountID := c.Param("id")
-	h.proxyToWorker(c, accountID, "/api/messages")
+	if _, err := h.manager.GetAccount(accountID); err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// 尽力同步Worker上的最新消息，Worker不可用时仍返回已存储的历史
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+	if _, err := h.manager.SyncMessages(ctx, accountID); err != nil {
+		logging.FromContext(ctx).Warn("Failed to sync messages", "account_id", accountID, "error", err)
+	}
+
+	messages, total, err := h.manager.ListMessages(accountID, q)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list messages",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, messages, buildListMeta(q, total, len(messages)), "Messages retrieved successfully")
 }
 
 // GetAccountStatus 获取账号状态
@@ -333,141 +501,170 @@ func (h *Handler) CheckLoginStatus(c *gin.Context) {
 }
 
 // @Summary Phone Login
-// @Description Login with phone number
+// @Description Login with phone number. Concurrent requests for the same number are deduplicated: later requests wait for the one in progress and return its result with joined=true instead of starting another worker. If the worker fails to start, data carries its startup diagnostics. With signin_type 40 the response includes login_flow, which tracks the pairing code until the account is logged in (GET /accounts/{id}/login/flow).
 // @Tags Auth
 // @Accept json
 // @Produce json
 // @Param request body model.PhoneLoginRequest true "Phone Login Request"
 // @Success 200 {object} model.APIResponse
+// @Failure 500 {object} model.APIResponse{data=model.WorkerStartupDiagnostics}
 // @Router /phone-login [post]
 func (h *Handler) PhoneLogin(c *gin.Context) {
-	// Read body for logging
-	bodyBytes, _ := io.ReadAll(c.Request.Body)
-	// Restore body
-	c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
-
-	fmt.Printf("\n====== [PhoneLogin] Request Body ======\n%s\n======================================\n", string(bodyBytes))
+	logger := logging.FromContext(c.Request.Context())
 
 	var req model.PhoneLoginRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		fmt.Printf("[PhoneLogin] BindJSON Error: %v\n", err)
-		c.JSON(http.StatusBadRequest, model.APIResponse{
-			Success: false,
-			Message: "Invalid request format",
-			Error:   err.Error(),
-		})
+		respondBindError(c, err)
+		return
+	}
+	if !h.normalizePhones(c, &req) {
 		return
 	}
 
-	fmt.Printf("[PhoneLogin] Parsed Request: %+v\n", req)
+	logger.Debug("Phone login requested", "phone", req.LoginPhone, "cache_login", req.CacheLogin, "has_proxy", req.ProxyConfig.IP != "")
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 5*time.Minute)
 	defer cancel()
 
 	// 使用手机号作为账号ID
 	accountID := req.LoginPhone
+	tenantID, _ := middleware.TenantID(c)
 
 	// 检查是否已存在该手机号的Worker
+	if _, err := h.manager.GetAccount(accountID); err == nil && !h.authorizeAccount(c, accountID) {
+		return
+	}
+
+	// 同一手机号的并发登录请求只执行一次，后到的请求等待并返回相同的结果，避免创建两个Worker
+	value, joined, err := h.manager.RunAccountOperation(c.Request.Context(), accountID, "phone-login", func() (interface{}, error) {
+		return h.phoneLogin(ctx, &req, tenantID)
+	})
+	if joined && err == nil && !h.authorizeAccount(c, accountID) {
+		return
+	}
+	if err != nil {
+		var loginErr *phoneLoginError
+		if !errors.As(err, &loginErr) {
+			loginErr = &phoneLoginError{status: http.StatusServiceUnavailable, message: "Login already in progress", err: err}
+		}
+		logger.Error("Phone login failed", "account_id", accountID, "joined", joined, "error", loginErr.err)
+		respond(c, loginErr.status, model.APIResponse{
+			Success: false,
+			Message: loginErr.message,
+			Data:    startupDiagnostics(loginErr.err),
+			Error:   loginErr.err.Error(),
+		})
+		return
+	}
+
+	result := value.(*phoneLoginResult)
+	logger.Info("Phone login initiated", "account_id", result.account.ID, "joined", joined)
+	data := map[string]interface{}{
+		"account":      result.account,
+		"login_result": result.loginResult,
+		"joined":       joined,
+	}
+	if flow, err := h.manager.GetLoginFlow(result.account.ID); err == nil {
+		data["login_flow"] = flow
+	}
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Login initiated successfully",
+		Data:    data,
+	})
+}
+
+// phoneLoginResult 手机号登录结果，并发的重复请求共享同一个结果
+type phoneLoginResult struct {
+	account     *model.Account
+	loginResult map[string]interface{}
+}
+
+// phoneLoginError 手机号登录失败时返回的状态码和消息
+type phoneLoginError struct {
+	status  int
+	message string
+	err     error
+}
+
+func (e *phoneLoginError) Error() string {
+	return e.message + ": " + e.err.Error()
+}
+
+func (e *phoneLoginError) Unwrap() error {
+	return e.err
+}
+
+// phoneLogin 为手机号准备Worker（已有账号则启动，否则重用空闲Worker或新建）并发起登录
+func (h *Handler) phoneLogin(ctx context.Context, req *model.PhoneLoginRequest, tenantID string) (*phoneLoginResult, error) {
+	accountID := req.LoginPhone
 	account, err := h.manager.GetAccount(accountID)
 	if err != nil {
 		// 账号不存在，检查是否有可用的Worker可以重用
-		availableAccount := h.manager.FindAvailableWorker()
+		availableAccount := h.manager.FindAvailableWorker(tenantID)
 		if availableAccount != nil {
 			// 重用现有Worker，更新其信息
 			account, err = h.manager.ReuseWorkerForPhone(ctx, availableAccount.ID, req.LoginPhone)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, model.APIResponse{
-					Success: false,
-					Message: "Failed to reuse existing worker",
-					Error:   err.Error(),
-				})
-				return
+				return nil, &phoneLoginError{status: http.StatusInternalServerError, message: "Failed to reuse existing worker", err: err}
 			}
 		} else {
 			// 没有可用Worker，创建新的
-			// Convert HardwareInfo to map[string]interface{}
-			// Since we changed HardwareInfo to struct, we can convert it directly
 			hwInfoMap := map[string]interface{}{
 				"os":      req.HardwareInfo.OS,
 				"browser": req.HardwareInfo.Browser,
 			}
 
-			// ProxyConfig is already struct, we can use it directly or convert pointer
-			// CreateAccount expects *ProxyConfig
-			proxyCfg := &req.ProxyConfig
-
 			loginReq := &model.LoginRequest{
-				AccountID:    accountID,
-				LoginMethod:  "phone",
-				Phone:        req.LoginPhone,
-				HardwareInfo: hwInfoMap,
-				CacheLogin:   req.CacheLogin,
-				ProxyConfig:  proxyCfg,
+				AccountID:         accountID,
+				LoginMethod:       "phone",
+				Phone:             req.LoginPhone,
+				HardwareInfo:      hwInfoMap,
+				HardwareProfileID: req.HardwareProfileID,
+				CacheLogin:        req.CacheLogin,
+				ProxyConfig:       &req.ProxyConfig,
+				TenantID:          tenantID,
 			}
 
 			account, err = h.manager.CreateAccount(ctx, loginReq)
 			if err != nil {
-				c.JSON(http.StatusInternalServerError, model.APIResponse{
-					Success: false,
-					Message: "Failed to create worker for phone number",
-					Error:   err.Error(),
-				})
-				return
+				return nil, &phoneLoginError{status: createErrorStatus(err, http.StatusInternalServerError), message: "Failed to create worker for phone number", err: err}
 			}
 		}
-	} else {
+	} else if account.Status != "running" && account.Status != "logged_in" {
 		// 账号已存在，启动Worker
-		if account.Status != "running" && account.Status != "logged_in" {
-			err = h.manager.StartAccount(ctx, accountID, &req)
-			if err != nil {
-				log.Printf("[PhoneLogin] StartAccount Error: %v", err)
-				c.JSON(http.StatusInternalServerError, model.APIResponse{
-					Success: false,
-					Message: "Failed to start existing worker",
-					Error:   err.Error(),
-				})
-				return
-			}
+		if err := h.manager.StartAccount(ctx, accountID, req); err != nil {
+			return nil, &phoneLoginError{status: http.StatusInternalServerError, message: "Failed to start existing worker", err: err}
 		}
 	}
 
 	// Call worker login interface
-	loginResult, err := h.manager.LoginToWorker(ctx, account, &req)
+	loginResult, err := h.manager.LoginToWorker(ctx, account, req)
 	if err != nil {
-		log.Printf("[PhoneLogin] LoginToWorker Error: %v", err)
-		c.JSON(http.StatusInternalServerError, model.APIResponse{
-			Success: false,
-			Message: "Failed to login to WhatsApp",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	resp := model.APIResponse{
-		Success: true,
-		Message: "Login initiated successfully",
-		Data: map[string]interface{}{
-			"account":      account,
-			"login_result": loginResult,
-		},
+		return nil, &phoneLoginError{status: http.StatusInternalServerError, message: "Failed to login to WhatsApp", err: err}
 	}
-	// Log response
-	respBytes, _ := json.Marshal(resp)
-	log.Printf("[PhoneLogin] Response: %s", string(respBytes))
-
-	c.JSON(http.StatusOK, resp)
+	return &phoneLoginResult{account: account, loginResult: loginResult}, nil
 }
 
 // @Summary Get Health Status
-// @Description Check system health status
+// @Description Check database, Docker daemon, session disk space, port pool utilization and goroutine count. The overall status is the worst check result; unhealthy returns 503.
 // @Tags System
 // @Produce json
-// @Success 200 {object} model.APIResponse
+// @Success 200 {object} model.APIResponse{data=model.HealthStatus}
+// @Failure 503 {object} model.APIResponse{data=model.HealthStatus}
 // @Router /health [get]
 func (h *Handler) GetHealth(c *gin.Context) {
-	health := h.manager.GetHealthStatus()
+	health := h.manager.GetHealthStatus(c.Request.Context())
+	if health.Status == service.HealthUnhealthy {
+		respond(c, http.StatusServiceUnavailable, model.APIResponse{
+			Success: false,
+			Message: "System is unhealthy",
+			Data:    health,
+		})
+		return
+	}
 
-	c.JSON(http.StatusOK, model.APIResponse{
+	respond(c, http.StatusOK, model.APIResponse{
 		Success: true,
 		Message: "Health status retrieved successfully",
 		Data:    health,
@@ -475,29 +672,36 @@ func (h *Handler) GetHealth(c *gin.Context) {
 }
 
 // @Summary Get System Stats
-// @Description Get system statistics
+// @Description Get system statistics with breakdowns by tag, pool, tenant, proxy region and host. availability (fleet-wide and per breakdown) is the 24h, 7d and 30d availability from the accounts' status history, as in GET /accounts/{id}/sla. todayMessages counts messages sent since local midnight. With from, to, granularity or account_id the response also contains a time series of the per-account daily counters (sent, received, failed, uptime_seconds); from defaults to 29 days before to, to defaults to today.
 // @Tags System
 // @Produce json
-// @Success 200 {object} model.APIResponse
+// @Param from query string false "First day (YYYY-MM-DD)"
+// @Param to query string false "Last day (YYYY-MM-DD)"
+// @Param granularity query string false "day, week or month"
+// @Param account_id query string false "Only count this account"
+// @Success 200 {object} model.APIResponse{data=model.FleetStats}
 // @Router /stats [get]
 func (h *Handler) GetStats(c *gin.Context) {
-	workers := h.manager.ListAccounts()
-	total := len(workers)
-	online := 0
-	messagesSent := 0
-	for _, w := range workers {
-		if w.Status == "logged_in" || w.Status == "running" {
-			online++
-		}
-		messagesSent += w.MessagesSent
+	var query model.StatsQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
+		return
 	}
-	stats := map[string]interface{}{
-		"totalWorkers":   total,
-		"onlineWorkers":  online,
-		"todayMessages":  messagesSent,
-		"activeContacts": 0,
+
+	stats := h.manager.GetStats()
+	if query != (model.StatsQuery{}) {
+		series, err := h.manager.StatsSeries(&query)
+		if err != nil {
+			respond(c, http.StatusBadRequest, model.APIResponse{
+				Success: false,
+				Message: "Invalid stats query",
+				Error:   err.Error(),
+			})
+			return
+		}
+		stats.Series = series
 	}
-	c.JSON(http.StatusOK, model.APIResponse{
+	respond(c, http.StatusOK, model.APIResponse{
 		Success: true,
 		Message: "Stats retrieved successfully",
 		Data:    stats,
@@ -512,7 +716,7 @@ func (h *Handler) GetStats(c *gin.Context) {
 // @Router /config [get]
 func (h *Handler) GetConfig(c *gin.Context) {
 	cfg := h.manager.GetConfig()
-	c.JSON(http.StatusOK, model.APIResponse{
+	respond(c, http.StatusOK, model.APIResponse{
 		Success: true,
 		Message: "Config retrieved successfully",
 		Data:    cfg,
@@ -520,222 +724,84 @@ func (h *Handler) GetConfig(c *gin.Context) {
 }
 
 // @Summary Update Config
-// @Description Update system configuration
+// @Description Update and persist configuration, e.g. {"rateLimit":{"globalPerMinute":600},"log":{"level":"debug"}}. Hot-reloadable keys (worker.image, worker.portRanges, rateLimit.*, quota.*, retry.*, bulk.*, proxy.*, supervisor.*, statusPoll.*, typing.*, log.level) take effect immediately; server.* and the other worker.* keys are saved and applied on the next restart. Saved values override environment variables at startup.
 // @Tags System
 // @Accept json
 // @Produce json
 // @Param request body map[string]interface{} true "Configuration"
-// @Success 200 {object} model.APIResponse
+// @Success 200 {object} model.APIResponse{data=model.ConfigUpdateResult}
 // @Router /config [put]
 func (h *Handler) UpdateConfig(c *gin.Context) {
 	var input map[string]interface{}
 	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, model.APIResponse{
-			Success: false,
-			Message: "Invalid request format",
-			Error:   err.Error(),
-		})
+		respondBindError(c, err)
 		return
 	}
-	if err := h.manager.UpdateConfig(input); err != nil {
-		c.JSON(http.StatusInternalServerError, model.APIResponse{
+	result, err := h.manager.UpdateConfig(input)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
 			Success: false,
 			Message: "Failed to update config",
 			Error:   err.Error(),
 		})
 		return
 	}
-	c.JSON(http.StatusOK, model.APIResponse{
+	respond(c, http.StatusOK, model.APIResponse{
 		Success: true,
 		Message: "Config updated successfully",
+		Data:    result,
 	})
 }
 
-// Dashboard 管理面板
-func (h *Handler) Dashboard(c *gin.Context) {
-	// 简单的HTML响应，暂时不使用模板
-	html := `<!DOCTYPE html>
-<html>
-<head>
-    <title>WhatsApp Multi-Service Dashboard</title>
-    <style>
-        body { font-family: Arial, sans-serif; margin: 40px; background-color: #f0f2f5; }
-        .header { background: #25D366; color: white; padding: 20px; border-radius: 8px; margin-bottom: 20px; box-shadow: 0 2px 4px rgba(0,0,0,0.1); }
-        .section { background: white; margin: 20px 0; padding: 25px; border-radius: 8px; box-shadow: 0 2px 4px rgba(0,0,0,0.05); }
-        .btn { background: #25D366; color: white; padding: 10px 20px; border: none; border-radius: 4px; cursor: pointer; font-weight: bold; text-decoration: none; display: inline-block; margin-right: 10px; }
-        .btn:hover { background: #128C7E; }
-        .api-card { border: 1px solid #e1e4e8; border-radius: 6px; margin-bottom: 15px; overflow: hidden; }
-        .api-header { background: #f6f8fa; padding: 10px 15px; font-weight: bold; border-bottom: 1px solid #e1e4e8; display: flex; justify-content: space-between; align-items: center; cursor: pointer; }
-        .method { display: inline-block; padding: 3px 8px; border-radius: 4px; color: white; font-size: 12px; margin-right: 10px; min-width: 50px; text-align: center; }
-        .get { background-color: #61affe; }
-        .post { background-color: #49cc90; }
-        .put { background-color: #fca130; }
-        .delete { background-color: #f93e3e; }
-        .api-body { padding: 15px; display: none; background: #fff; }
-        .code-block { background: #282c34; color: #abb2bf; padding: 15px; border-radius: 4px; font-family: monospace; white-space: pre-wrap; margin: 10px 0; font-size: 13px; position: relative; }
-        .copy-btn { position: absolute; top: 5px; right: 5px; background: rgba(255,255,255,0.2); color: white; border: none; padding: 2px 8px; border-radius: 3px; cursor: pointer; font-size: 11px; }
-        h2 { color: #128C7E; border-bottom: 2px solid #25D366; padding-bottom: 10px; margin-top: 0; }
-    </style>
-    <script>
-        function toggleApi(id) {
-            var el = document.getElementById(id);
-            if (el.style.display === 'block') {
-                el.style.display = 'none';
-            } else {
-                el.style.display = 'block';
-            }
-        }
-        function copyToClipboard(text) {
-            navigator.clipboard.writeText(text).then(function() {
-                alert('Copied to clipboard!');
-            }, function(err) {
-                console.error('Could not copy text: ', err);
-            });
-        }
-    </script>
-</head>
-<body>
-    <div class="header">
-        <h1>📱 WhatsApp Multi-Service Dashboard</h1>
-        <p>统一管理多个WhatsApp账号实例</p>
-    </div>
-    
-    <div class="section">
-        <h2>🚀 常用链接</h2>
-        <div style="margin-top: 20px;">
-            <a href="/api/v1/health" target="_blank" class="btn">系统健康状态</a>
-            <a href="/api/v1/accounts" target="_blank" class="btn">查看所有账号</a>
-            <a href="/swagger/index.html" target="_blank" class="btn">Swagger API 文档</a>
-        </div>
-    </div>
-    
-    <div class="section">
-        <h2>📚 API 调用示例</h2>
-        <p>点击下方接口查看详细调用示例（使用 curl 格式）：</p>
-
-        <!-- 1. Phone Login -->
-        <div class="api-card">
-            <div class="api-header" onclick="toggleApi('api-login')">
-                <div><span class="method post">POST</span> /api/v1/phone-login</div>
-                <span>手机号登录</span>
-            </div>
-            <div id="api-login" class="api-body">
-                <p>启动一个新的 WhatsApp 实例并使用手机号登录。</p>
-                <div class="code-block">
-                    <button class="copy-btn" onclick="copyToClipboard(this.parentElement.innerText)">Copy</button>
-curl -X POST http://localhost:8080/api/v1/phone-login \
-  -H "Content-Type: application/json" \
-  -d '{
-    "login_phone": "8613800138000",
-    "signin_type": 40,
-    "hardware_info": {
-        "os": "MacOS",
-        "browser": "Chrome"
-    },
-    "socks5": {
-        "ip": "127.0.0.1",
-        "port": 7890,
-        "username": "",
-        "password": ""
-    }
-}'
-                </div>
-            </div>
-        </div>
-
-        <!-- 2. Get Accounts -->
-        <div class="api-card">
-            <div class="api-header" onclick="toggleApi('api-list')">
-                <div><span class="method get">GET</span> /api/v1/accounts</div>
-                <span>获取账号列表</span>
-            </div>
-            <div id="api-list" class="api-body">
-                <p>列出当前系统中所有管理的账号及其状态。</p>
-                <div class="code-block">
-                    <button class="copy-btn" onclick="copyToClipboard(this.parentElement.innerText)">Copy</button>
-curl http://localhost:8080/api/v1/accounts
-                </div>
-            </div>
-        </div>
-
-        <!-- 3. Send Message -->
-        <div class="api-card">
-            <div class="api-header" onclick="toggleApi('api-send')">
-                <div><span class="method post">POST</span> /api/v1/send-message</div>
-                <span>发送消息</span>
-            </div>
-            <div id="api-send" class="api-body">
-                <p>使用指定账号发送文本消息。</p>
-                <div class="code-block">
-                    <button class="copy-btn" onclick="copyToClipboard(this.parentElement.innerText)">Copy</button>
-curl -X POST http://localhost:8080/api/v1/send-message \
-  -H "Content-Type: application/json" \
-  -d '{
-    "account_id": "8613800138000",
-    "contact": "8613900139000",
-    "message": "Hello from WhatsApp Multi-Service!"
-}'
-                </div>
-            </div>
-        </div>
-
-        <!-- 4. Switch Proxy -->
-        <div class="api-card">
-            <div class="api-header" onclick="toggleApi('api-proxy')">
-                <div><span class="method post">POST</span> /api/v1/accounts/{id}/proxy/switch</div>
-                <span>切换代理</span>
-            </div>
-            <div id="api-proxy" class="api-body">
-                <p>为指定账号切换代理配置。</p>
-                <div class="code-block">
-                    <button class="copy-btn" onclick="copyToClipboard(this.parentElement.innerText)">Copy</button>
-curl -X POST http://localhost:8080/api/v1/accounts/8613800138000/proxy/switch \
-  -H "Content-Type: application/json" \
-  -d '{
-    "ip": "192.168.1.100",
-    "port": 1080,
-    "username": "user",
-    "password": "pass",
-    "protocol": "socks5"
-}'
-                </div>
-            </div>
-        </div>
-
-        <!-- 5. Stop Account -->
-        <div class="api-card">
-            <div class="api-header" onclick="toggleApi('api-stop')">
-                <div><span class="method post">POST</span> /api/v1/accounts/{id}/stop</div>
-                <span>停止账号服务</span>
-            </div>
-            <div id="api-stop" class="api-body">
-                <p>停止指定账号的 Worker 进程或容器。</p>
-                <div class="code-block">
-                    <button class="copy-btn" onclick="copyToClipboard(this.parentElement.innerText)">Copy</button>
-curl -X POST http://localhost:8080/api/v1/accounts/8613800138000/stop
-                </div>
-            </div>
-        </div>
-        
-         <!-- 6. Get QR Code -->
-        <div class="api-card">
-            <div class="api-header" onclick="toggleApi('api-qr')">
-                <div><span class="method get">GET</span> /api/v1/accounts/{id}/qr-code</div>
-                <span>获取登录二维码</span>
-            </div>
-            <div id="api-qr" class="api-body">
-                <p>获取指定账号的登录二维码（如果是扫码登录模式）。</p>
-                <div class="code-block">
-                    <button class="copy-btn" onclick="copyToClipboard(this.parentElement.innerText)">Copy</button>
-curl http://localhost:8080/api/v1/accounts/8613800138000/qr-code
-                </div>
-            </div>
-        </div>
-
-    </div>
-</body>
-</html>`
-	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(html))
+// @Summary List Config Overrides
+// @Description List configuration values saved through PUT /config. They override environment variables at startup.
+// @Tags System
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending, e.g. -updated_at"
+// @Param filter[hot_reload] query string false "true or false"
+// @Success 200 {object} model.APIResponse{data=[]model.ConfigOverride}
+// @Router /config/overrides [get]
+func (h *Handler) ListConfigOverrides(c *gin.Context) {
+	overrides, err := h.manager.ListConfigOverrides()
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to list config overrides",
+			Error:   err.Error(),
+		})
+		return
+	}
+	respondList(c, overrides, "Config overrides retrieved successfully")
+}
+
+// @Summary Delete Config Override
+// @Description Remove a saved configuration value and fall back to the environment variable. Hot-reloadable keys revert immediately; the others on the next restart.
+// @Tags System
+// @Produce json
+// @Param key path string true "Config key, e.g. rateLimit.globalPerMinute"
+// @Success 200 {object} model.APIResponse{data=model.ConfigUpdateResult}
+// @Router /config/overrides/{key} [delete]
+func (h *Handler) DeleteConfigOverride(c *gin.Context) {
+	result, err := h.manager.DeleteConfigOverride(c.Param("key"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrConfigOverrideNotFound) {
+			status = http.StatusNotFound
+		}
+		respond(c, status, model.APIResponse{
+			Success: false,
+			Message: "Failed to delete config override",
+			Error:   err.Error(),
+		})
+		return
+	}
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Config override removed successfully",
+		Data:    result,
+	})
 }
 
 // @Summary Get Proxy Status
@@ -743,7 +809,7 @@ curl http://localhost:8080/api/v1/accounts/8613800138000/qr-code
 // @Tags Proxy
 // @Produce json
 // @Param id path string true "Account ID"
-// @Success 200 {object} model.APIResponse
+// @Success 200 {object} model.ProxyStatus
 // @Router /accounts/{id}/proxy/status [get]
 func (h *Handler) GetProxyStatus(c *gin.Context) {
 	accountID := c.Param("id")
@@ -751,16 +817,23 @@ func (h *Handler) GetProxyStatus(c *gin.Context) {
 }
 
 // @Summary Switch Proxy
-// @Description Switch proxy for an account
+// @Description Switch proxy for an account. The worker restarts its browser through the new proxy, and the proxy is stored as the account's binding for later restarts.
 // @Tags Proxy
+// @Accept json
 // @Produce json
 // @Param id path string true "Account ID"
-// @Param request body model.ProxyConfig true "Proxy Config"
+// @Param request body model.SwitchProxyRequest true "Proxy Config"
 // @Success 200 {object} model.APIResponse
 // @Router /accounts/{id}/proxy/switch [post]
 func (h *Handler) SwitchProxy(c *gin.Context) {
-	accountID := c.Param("id")
-	h.proxyToWorker(c, accountID, "/api/proxy/switch")
+	var req model.SwitchProxyRequest
+	if !h.bindWorkerRequest(c, &req) {
+		return
+	}
+	if err := h.manager.BindSwitchedProxy(c.Param("id"), &req); err != nil {
+		logging.FromContext(c.Request.Context()).Warn("Failed to store switched proxy", "account_id", c.Param("id"), "error", err)
+	}
+	h.proxyToWorker(c, c.Param("id"), "/api/proxy/switch")
 }
 
 // @Summary Get External IP
@@ -768,7 +841,7 @@ func (h *Handler) SwitchProxy(c *gin.Context) {
 // @Tags Proxy
 // @Produce json
 // @Param id path string true "Account ID"
-// @Success 200 {object} model.APIResponse
+// @Success 200 {object} model.ExternalIPResult
 // @Router /accounts/{id}/proxy/external-ip [get]
 func (h *Handler) GetExternalIP(c *gin.Context) {
 	accountID := c.Param("id")
@@ -780,7 +853,7 @@ func (h *Handler) GetExternalIP(c *gin.Context) {
 // @Tags Proxy
 // @Produce json
 // @Param id path string true "Account ID"
-// @Success 200 {object} model.APIResponse
+// @Success 200 {object} model.ProxyDetectResult
 // @Router /accounts/{id}/proxy/detect [get]
 func (h *Handler) DetectProxy(c *gin.Context) {
 	accountID := c.Param("id")
@@ -836,31 +909,81 @@ func (h *Handler) Logout(c *gin.Context) {
 }
 
 // @Summary Create Group
-// @Description Create a new group
+// @Description Create a new group. The new group is added to the master's group cache.
 // @Tags Group
 // @Accept json
 // @Produce json
 // @Param id path string true "Account ID"
-// @Param request body map[string]interface{} true "Group Info"
-// @Success 200 {object} model.APIResponse
+// @Param request body model.CreateGroupRequest true "Group Info"
+// @Success 200 {object} model.APIResponse{data=model.CreateGroupResult}
 // @Router /accounts/{id}/groups [post]
 func (h *Handler) CreateGroup(c *gin.Context) {
-	accountID := c.Param("id")
-	h.proxyToWorker(c, accountID, "/api/groups/create")
+	var req model.CreateGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if !h.accountExists(c, c.Param("id")) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), time.Minute)
+	defer cancel()
+
+	result, err := h.manager.CreateGroup(ctx, c.Param("id"), &req)
+	if err != nil {
+		respond(c, workerErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to update group",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Group created successfully",
+		Data:    result,
+	})
 }
 
 // @Summary Add Group Participants
-// @Description Add participants to a group
+// @Description Add participants to a group. The group's cached participants are refreshed.
 // @Tags Group
 // @Accept json
 // @Produce json
 // @Param id path string true "Account ID"
-// @Param request body map[string]interface{} true "Participants Info"
+// @Param request body model.AddGroupParticipantsRequest true "Participants Info"
 // @Success 200 {object} model.APIResponse
 // @Router /accounts/{id}/groups/participants [post]
 func (h *Handler) AddGroupParticipants(c *gin.Context) {
-	accountID := c.Param("id")
-	h.proxyToWorker(c, accountID, "/api/groups/participants/add")
+	var req model.AddGroupParticipantsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if !h.accountExists(c, c.Param("id")) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), time.Minute)
+	defer cancel()
+
+	result, err := h.manager.AddGroupParticipants(ctx, c.Param("id"), &req)
+	if err != nil {
+		respond(c, workerErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to update group",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Group updated successfully",
+		Data:    result,
+	})
 }
 
 // @Summary Close Account
@@ -883,11 +1006,14 @@ func (h *Handler) CloseAccount(c *gin.Context) {
 // @Produce json
 // @Param id path string true "Account ID"
 // @Param request body model.AddContactRequest true "Contact Info"
-// @Success 200 {object} model.APIResponse
+// @Success 200 {object} model.APIResponse{data=model.WorkerContact}
 // @Router /accounts/{id}/contacts [post]
 func (h *Handler) AddContact(c *gin.Context) {
-	accountID := c.Param("id")
-	h.proxyToWorker(c, accountID, "/api/contacts/add")
+	var req model.AddContactRequest
+	if !h.bindWorkerRequest(c, &req) {
+		return
+	}
+	h.proxyToWorker(c, c.Param("id"), "/api/contacts/add")
 }
 
 // StopAccount 停止账号服务
@@ -901,18 +1027,18 @@ func (h *Handler) AddContact(c *gin.Context) {
 func (h *Handler) StopAccount(c *gin.Context) {
 	accountID := c.Param("id")
 	if accountID == "" {
-		c.JSON(http.StatusBadRequest, model.APIResponse{
+		respond(c, http.StatusBadRequest, model.APIResponse{
 			Success: false,
 			Message: "Account ID is required",
 		})
 		return
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 1*time.Minute)
 	defer cancel()
 
 	if err := h.manager.StopAccount(ctx, accountID); err != nil {
-		c.JSON(http.StatusInternalServerError, model.APIResponse{
+		respond(c, http.StatusInternalServerError, model.APIResponse{
 			Success: false,
 			Message: "Failed to stop account",
 			Error:   err.Error(),
@@ -920,7 +1046,7 @@ func (h *Handler) StopAccount(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, model.APIResponse{
+	respond(c, http.StatusOK, model.APIResponse{
 		Success: true,
 		Message: "Account stopped successfully",
 	})
@@ -928,230 +1054,364 @@ func (h *Handler) StopAccount(c *gin.Context) {
 
 // RestartAccount 重启指定账号的Worker
 // @Summary Restart Account Worker
-// @Description Restart the worker container/process for an account (e.g., after image update)
+// @Description Restart the worker container/process for an account (e.g., after image update). The restart runs as a background job; poll /jobs/{id} for the outcome.
 // @Tags Account
 // @Produce json
 // @Param id path string true "Account ID"
-// @Success 200 {object} model.APIResponse
+// @Success 202 {object} model.APIResponse{data=model.Job}
 // @Router /accounts/{id}/restart [post]
 func (h *Handler) RestartAccount(c *gin.Context) {
 	accountID := c.Param("id")
 	if accountID == "" {
-		c.JSON(http.StatusBadRequest, model.APIResponse{
+		respond(c, http.StatusBadRequest, model.APIResponse{
 			Success: false,
 			Message: "Account ID is required",
 		})
 		return
 	}
 
-	// 异步执行以避免阻塞请求
-	go func(id string) {
-		ctx := context.Background()
-		if err := h.manager.RestartAccount(ctx, id); err != nil {
-			log.Printf("Failed to restart account %s: %v", id, err)
-		}
-	}(accountID)
+	job, err := h.manager.RestartAccount(accountID)
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Failed to restart account",
+			Error:   err.Error(),
+		})
+		return
+	}
 
-	c.JSON(http.StatusOK, model.APIResponse{
+	respond(c, http.StatusAccepted, model.APIResponse{
 		Success: true,
 		Message: "Account restart triggered",
+		Data:    job,
 	})
 }
 
 // RestartWorkers 重启所有Workers
 // @Summary Restart All Workers
-// @Description Restart all active workers (e.g. after image update)
+// @Description Restart all active workers (e.g. after image update). The restart runs as a background job; poll /jobs/{id} for progress.
 // @Tags System
 // @Produce json
-// @Success 200 {object} model.APIResponse
+// @Success 202 {object} model.APIResponse{data=model.Job}
 // @Router /system/restart-workers [post]
 func (h *Handler) RestartWorkers(c *gin.Context) {
-	// 异步执行，避免阻塞HTTP请求
-	go func() {
-		ctx := context.Background()
-		if err := h.manager.RestartWorkers(ctx); err != nil {
-			log.Printf("Error restarting workers: %v", err)
-		}
-	}()
+	job, err := h.manager.RestartWorkers()
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to restart workers",
+			Error:   err.Error(),
+		})
+		return
+	}
 
-	c.JSON(http.StatusOK, model.APIResponse{
+	respond(c, http.StatusAccepted, model.APIResponse{
 		Success: true,
 		Message: "Workers restart triggered in background",
+		Data:    job,
 	})
 }
 
 // SetupRoutes 设置路由
 func (h *Handler) SetupRoutes() *gin.Engine {
 	gin.SetMode(gin.ReleaseMode)
-	r := gin.Default()
+	r := gin.New()
+	r.Use(gin.Recovery())
 
-	// 添加日志中间件
+	// 请求ID和结构化请求日志
+	r.Use(middleware.RequestID())
+	r.Use(middleware.Tracing())
 	r.Use(middleware.RequestLogger())
+	r.Use(middleware.Locale())
+	// 多副本时Follower把写请求转发给Leader
+	r.Use(h.forwardToLeader())
 
 	// 静态文件服务
 	r.Static("/static", "web/static")
 
 	// API路由
 	api := r.Group("/api/v1")
+	if h.manager.AuditEnabled() {
+		// 在鉴权之前记录，鉴权失败的写操作也会留下审计记录
+		api.Use(h.auditLog())
+	}
+	if cfg := h.manager.GetConfig().Tenant; cfg.Enabled {
+		api.Use(middleware.Authenticate(cfg.AdminToken, h.manager.AuthenticateAPIKey), h.tenantScope())
+	}
 	{
 		// 账号管理
 		api.POST("/accounts", h.CreateAccount)
+		api.POST("/accounts/bulk", h.CreateAccountsBulk)
+		api.POST("/accounts/:id/clone", h.CloneAccount)
 		api.GET("/accounts", h.ListAccounts)
 		api.GET("/accounts/:id", h.GetAccount)
+		api.PATCH("/accounts/:id", h.UpdateAccount)
 		api.DELETE("/accounts/:id", h.DeleteAccount)
+		api.PUT("/accounts/:id/owner", h.SetAccountOwner)
+		api.POST("/accounts/assign", h.AssignAccounts)
+		api.GET("/owners", h.ListOwners)
+		api.PUT("/accounts/:id/send-limit", h.SetAccountSendLimit)
+		api.PUT("/accounts/:id/disable", h.DisableAccount)
+		api.PUT("/accounts/:id/enable", h.EnableAccount)
+		api.POST("/accounts/:id/ban/release", h.ReleaseBan)
+		api.GET("/bans", h.ListBans)
+		api.GET("/backups", h.ListBackups)
+		api.PUT("/accounts/:id/typing-simulation", h.SetAccountTypingSimulation)
 
 		// 登录管理
 		api.POST("/phone-login", h.PhoneLogin)
+		api.POST("/qr-login", h.QRLogin)
 
 		// WhatsApp操作
-		api.POST("/send-message", h.SendMessage)
+		api.POST("/send-message", h.idempotent(), h.SendMessage)
+		api.POST("/messages/retry", h.RetryFailedMessages)
+		api.GET("/dead-letters", h.ListDeadLetters)
+		api.POST("/dead-letters/:id/retry", h.RetryDeadLetter)
+		api.GET("/inbox", h.ListInbox)
+		api.POST("/inbox/read", h.MarkInboxRead)
+		api.GET("/messages/:id/preview", h.GetMessagePreview)
+		api.GET("/messages/:id/status", h.GetMessageStatus)
+		api.POST("/send-bulk", h.idempotent(), h.SendBulk)
+		api.POST("/send-media", h.SendMedia)
+		api.GET("/send-bulk/:id", h.GetBulkBatch)
+		api.POST("/broadcast", h.Broadcast)
+		api.GET("/broadcast/:id", h.GetBroadcastReport)
 		api.GET("/accounts/:id/contacts", h.GetContacts)
 		api.POST("/accounts/:id/contacts", h.AddContact)
+		api.POST("/accounts/:id/contacts/sync", h.SyncContacts)
+		api.POST("/accounts/:id/contacts/import", h.ImportContacts)
+		api.GET("/contacts", h.SearchContacts)
 		api.GET("/accounts/:id/messages", h.GetMessages)
 		api.GET("/accounts/:id/status", h.GetAccountStatus)
 		api.GET("/accounts/:id/qr-code", h.GetQRCode)
+		api.GET("/accounts/:id/qr-code.png", h.GetQRCodePNG)
 		api.GET("/accounts/:id/logs", h.GetLogs)
+		api.GET("/accounts/:id/logs/stream", h.StreamLogs)
 		api.GET("/accounts/:id/debug", h.GetDebug)
 		api.GET("/accounts/:id/debug/html", h.GetDebugHTML)
 		api.GET("/accounts/:id/login/status", h.CheckLoginStatus)
 		api.POST("/accounts/:id/login/refresh", h.RefreshLogin)
+		api.GET("/accounts/:id/login/flow", h.GetLoginFlow)
+		api.POST("/accounts/:id/login/verify", h.SubmitLoginVerification)
+		api.GET("/accounts/:id/pairing-code", h.GetPairingCode)
+		api.GET("/accounts/:id/session", h.GetSessionHealth)
+		api.PUT("/accounts/:id/keepalive", h.SetAccountKeepAlive)
+		api.GET("/accounts/:id/history", h.GetStatusHistory)
+		api.GET("/accounts/:id/worker-calls", h.ListWorkerCalls)
+		api.GET("/accounts/:id/sla", h.GetAccountSLA)
+		api.GET("/accounts/:id/quota", h.GetSendQuota)
+		api.GET("/accounts/:id/warmup", h.GetWarmupStatus)
+		api.PUT("/accounts/:id/warmup", h.SetAccountWarmup)
+		api.GET("/accounts/:id/diagnostics", h.ListDiagnostics)
+		api.GET("/sessions", h.ListSessionHealth)
 		api.POST("/accounts/:id/logout", h.Logout)
 		api.POST("/accounts/:id/close", h.CloseAccount)
 		api.POST("/accounts/:id/stop", h.StopAccount)
 		api.POST("/accounts/:id/restart", h.RestartAccount)
 
+		// 会话历史
+		api.GET("/accounts/:id/chats/:contact/messages", h.GetChatHistory)
+		api.GET("/accounts/:id/chats/:contact/export", h.ExportChatHistory)
+
+		// 输入状态和在线状态
+		api.POST("/accounts/:id/typing", h.SendTyping)
+		api.GET("/accounts/:id/presence/:contact", h.GetPresence)
+
+		// 自动回复
+		api.GET("/accounts/:id/auto-replies", h.ListAutoReplyRules)
+		api.POST("/accounts/:id/auto-replies", h.CreateAutoReplyRule)
+		api.GET("/accounts/:id/auto-replies/log", h.ListAutoReplyLogs)
+		api.PUT("/accounts/:id/auto-replies/:rule_id", h.UpdateAutoReplyRule)
+		api.DELETE("/accounts/:id/auto-replies/:rule_id", h.DeleteAutoReplyRule)
+
+		// 会话归属
+		api.GET("/accounts/:id/conversations", h.ListConversations)
+		api.GET("/accounts/:id/conversations/:contact", h.GetConversation)
+		api.POST("/accounts/:id/conversations/:contact/claim", h.ClaimConversation)
+		api.POST("/accounts/:id/conversations/:contact/release", h.ReleaseConversation)
+
+		// 营销活动
+		api.POST("/campaigns", h.CreateCampaign)
+		api.GET("/campaigns", h.ListCampaigns)
+		api.GET("/campaigns/:id", h.GetCampaign)
+		api.GET("/campaigns/:id/recipients", h.ListCampaignRecipients)
+		api.POST("/campaigns/:id/start", h.StartCampaign)
+		api.POST("/campaigns/:id/pause", h.PauseCampaign)
+
+		// 发送审批
+		api.GET("/approvals", h.ListApprovals)
+		api.GET("/approvals/:id", h.GetApproval)
+		api.POST("/approvals/:id/approve", h.ApproveSend)
+		api.POST("/approvals/:id/reject", h.RejectSend)
+
+		// 租户管理
+		api.POST("/tenants", h.CreateTenant)
+		api.GET("/tenants", h.ListTenants)
+		api.GET("/tenants/:id", h.GetTenant)
+		api.PUT("/tenants/:id", h.UpdateTenant)
+		api.POST("/tenants/:id/keys", h.CreateTenantAPIKey)
+		api.GET("/tenants/:id/keys", h.ListTenantAPIKeys)
+		api.DELETE("/tenants/:id/keys/:key_id", h.RevokeTenantAPIKey)
+
+		// 诊断包
+		api.GET("/diagnostics/:id", h.GetDiagnostics)
+		api.GET("/diagnostics/:id/files/:name", h.DownloadDiagnosticFile)
+		api.DELETE("/diagnostics/:id", h.DeleteDiagnostics)
+
+		// 后台任务
+		api.GET("/jobs", h.ListJobs)
+		api.GET("/jobs/:id", h.GetJob)
+		api.POST("/jobs/:id/cancel", h.CancelJob)
+
 		// 群组管理
+		api.GET("/accounts/:id/groups", h.ListGroups)
 		api.POST("/accounts/:id/groups", h.CreateGroup)
 		api.POST("/accounts/:id/groups/participants", h.AddGroupParticipants)
+		api.GET("/accounts/:id/groups/:gid", h.GetGroup)
+		api.PUT("/accounts/:id/groups/:gid", h.RenameGroup)
+		api.POST("/accounts/:id/groups/:gid/participants/remove", h.RemoveGroupParticipants)
+		api.POST("/accounts/:id/groups/:gid/admins/promote", h.PromoteGroupAdmins)
+		api.POST("/accounts/:id/groups/:gid/admins/demote", h.DemoteGroupAdmins)
+		api.GET("/accounts/:id/groups/:gid/invite-link", h.GetGroupInviteLink)
 
 		// 代理管理
+		api.PUT("/accounts/:id/proxy", h.SetAccountProxy)
 		api.GET("/accounts/:id/proxy/status", h.GetProxyStatus)
 		api.POST("/accounts/:id/proxy/switch", h.SwitchProxy)
 		api.GET("/accounts/:id/proxy/external-ip", h.GetExternalIP)
 		api.GET("/accounts/:id/proxy/detect", h.DetectProxy)
 
+		// 硬件指纹配置
+		api.POST("/hardware-profiles", h.CreateHardwareProfile)
+		api.GET("/hardware-profiles", h.ListHardwareProfiles)
+		api.GET("/hardware-profiles/:id", h.GetHardwareProfile)
+		api.PUT("/hardware-profiles/:id", h.UpdateHardwareProfile)
+		api.DELETE("/hardware-profiles/:id", h.DeleteHardwareProfile)
+
 		// 调试工具
 		api.GET("/accounts/:id/debug/elements", h.GetDebugElements)
 		api.POST("/accounts/:id/debug/check-messages", h.CheckMessages)
 
+		// 事件Webhook
+		api.POST("/webhooks", h.CreateWebhook)
+		api.GET("/webhooks", h.ListWebhooks)
+		api.PATCH("/webhooks/:id", h.UpdateWebhook)
+		api.DELETE("/webhooks/:id", h.DeleteWebhook)
+
+		// 告警通道与路由规则
+		api.POST("/alerts/channels", h.CreateAlertChannel)
+		api.GET("/alerts/channels", h.ListAlertChannels)
+		api.PUT("/alerts/channels/:id", h.UpdateAlertChannel)
+		api.DELETE("/alerts/channels/:id", h.DeleteAlertChannel)
+		api.POST("/alerts/channels/:id/test", h.TestAlertChannel)
+		api.POST("/alerts/rules", h.CreateAlertRule)
+		api.GET("/alerts/rules", h.ListAlertRules)
+		api.PUT("/alerts/rules/:id", h.UpdateAlertRule)
+		api.DELETE("/alerts/rules/:id", h.DeleteAlertRule)
+
+		// 抑制名单
+		api.GET("/suppressions", h.ListSuppressions)
+		api.POST("/suppressions", h.AddSuppressions)
+		api.GET("/suppressions/:contact", h.GetSuppression)
+		api.DELETE("/suppressions/:contact", h.RemoveSuppression)
+
+		// 内容策略与审核队列
+		api.POST("/content-policies", h.CreateContentPolicy)
+		api.GET("/content-policies", h.ListContentPolicies)
+		api.PUT("/content-policies/:id", h.UpdateContentPolicy)
+		api.DELETE("/content-policies/:id", h.DeleteContentPolicy)
+		api.GET("/moderation", h.ListModeration)
+		api.GET("/moderation/:id", h.GetModerationItem)
+		api.POST("/moderation/:id/approve", h.ApproveModeration)
+		api.POST("/moderation/:id/reject", h.RejectModeration)
+
+		// 链接跟踪
+		api.GET("/tracking/stats", h.GetClickStats)
+
 		// 系统状态
 		api.GET("/health", h.GetHealth)
 		api.GET("/stats", h.GetStats)
+		api.GET("/events", h.StreamEvents)
+		api.GET("/events/ws", h.StreamEventsWS)
+		api.POST("/graphql", h.GraphQL)
+		api.GET("/graphql", h.GraphQLGet)
+		api.GET("/graphql/schema", h.GetGraphQLSchema)
 		api.GET("/config", h.GetConfig)
 		api.PUT("/config", h.UpdateConfig)
+		api.GET("/config/overrides", h.ListConfigOverrides)
+		api.DELETE("/config/overrides/:key", h.DeleteConfigOverride)
 
 		// 系统管理
 		api.POST("/system/restart-workers", h.RestartWorkers)
-	}
-
-	// Swagger文档 (移回根路径以便更好兼容gin-swagger默认行为)
-	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
-
-	// Web界面
-	r.GET("/", h.Dashboard)
-	r.GET("/dashboard", h.Dashboard)
-
-	return r
-}
-
-// proxyToWorker 转发请求到Worker
-func (h *Handler) proxyToWorker(c *gin.Context, accountID string, workerPath string) {
-	account, err := h.manager.GetAccount(accountID)
-	if err != nil {
-		c.JSON(http.StatusNotFound, model.APIResponse{
-			Success: false,
-			Message: "Account not found",
-			Error:   err.Error(),
-		})
-		return
-	}
-
-	targetURL := fmt.Sprintf("%s%s", account.ServiceURL, workerPath)
-
-	// 如果是GET请求，附带Query参数
-	if c.Request.Method == http.MethodGet {
-		if c.Request.URL.RawQuery != "" {
-			targetURL += "?" + c.Request.URL.RawQuery
+		api.POST("/system/upgrade-workers", h.UpgradeWorkers)
+		api.POST("/system/pull-image", h.PullImage)
+		api.GET("/system/janitor", h.GetJanitorStatus)
+		api.POST("/system/janitor/run", h.RunJanitor)
+		api.GET("/system/backups", h.GetBackupStatus)
+		api.POST("/system/backups/run", h.RunBackup)
+		api.GET("/system/ports", h.GetPortStatus)
+		api.GET("/system/migrations", h.GetMigrations)
+		api.POST("/system/migrations/up", h.ApplyMigrations)
+		api.POST("/system/migrations/rollback", h.RollbackMigrations)
+		api.GET("/system/leader", h.GetLeaderStatus)
+		api.GET("/audit", h.ListAuditLog)
+
+		// 对象存储
+		api.POST("/storage/objects", h.UploadObject)
+		api.GET("/storage/objects/url", h.GetObjectURL)
+		api.DELETE("/storage/objects", h.DeleteObject)
+
+		// Worker主机
+		api.POST("/hosts", h.RegisterHost)
+		api.GET("/hosts", h.ListHosts)
+		api.GET("/hosts/:id", h.GetHost)
+		api.PUT("/hosts/:id", h.UpdateHost)
+		api.DELETE("/hosts/:id", h.DeleteHost)
+		api.POST("/hosts/:id/evacuate", h.EvacuateHost)
+
+		// 故障注入（仅在CHAOS_ENABLED时注册）
+		if h.manager.ChaosEnabled() {
+			chaos := api.Group("/chaos", middleware.RequireAdminToken(h.manager.GetConfig().Chaos.AdminToken))
+			chaos.GET("/faults", h.ListChaosFaults)
+			chaos.POST("/accounts/:id/delay", h.InjectChaosDelay)
+			chaos.DELETE("/accounts/:id/delay", h.ClearChaosFault)
+			chaos.POST("/accounts/:id/kill", h.KillChaosWorker)
+			chaos.POST("/accounts/:id/status", h.ForceChaosStatus)
 		}
 	}
 
-	req, err := http.NewRequest(c.Request.Method, targetURL, c.Request.Body)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, model.APIResponse{
-			Success: false,
-			Message: "Failed to create proxy request",
-			Error:   err.Error(),
-		})
-		return
+	// Worker回调接口，使用每个账号的Worker凭证鉴权
+	worker := r.Group("/api/v1/worker/accounts/:id", middleware.RequireWorkerToken(h.manager.VerifyWorkerToken))
+	{
+		worker.POST("/diagnostics", h.UploadDiagnostics)
+		worker.POST("/receipts", h.ReportReceipts)
 	}
 
-	// Copy headers
-	for k, v := range c.Request.Header {
-		// 跳过一些不应该转发的头
-		if k == "Host" || k == "Content-Length" || k == "If-None-Match" || k == "If-Modified-Since" {
-			continue
-		}
-		req.Header[k] = v
+	// Worker事件推送，代替大部分状态轮询和收件箱拉取
+	internal := r.Group("/internal/v1/workers/:id", middleware.RequireWorkerToken(h.manager.VerifyWorkerToken))
+	{
+		internal.POST("/events", h.ReceiveWorkerEvents)
 	}
 
-	// 强制禁用缓存
-	req.Header.Set("Cache-Control", "no-cache")
-	req.Header.Set("Pragma", "no-cache")
+	// Swagger文档 (移回根路径以便更好兼容gin-swagger默认行为)
+	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	client := &http.Client{Timeout: 30 * time.Second}
-	resp, err := client.Do(req)
-	if err != nil {
-		c.JSON(http.StatusBadGateway, model.APIResponse{
-			Success: false,
-			Message: "Failed to connect to worker",
-			Error:   err.Error(),
-		})
-		return
-	}
-	defer resp.Body.Close()
+	// 链接点击跳转
+	r.GET("/r/:code", h.TrackRedirect)
 
-	// 复制Worker的响应
-	c.Status(resp.StatusCode)
-	for k, v := range resp.Header {
-		c.Writer.Header()[k] = v
-	}
+	// 签名媒体链接
+	r.GET("/media/:id", h.ServeSignedMedia)
 
-	// 读取响应体以进行状态更新
-	bodyBytes, err := io.ReadAll(resp.Body)
-	if err != nil {
-		// 如果读取失败，至少尽力转发（虽然可能已经部分写入了）
-		// 但由于我们还没写入ResponseWriter，所以这里可以返回错误
-		c.JSON(http.StatusInternalServerError, model.APIResponse{
-			Success: false,
-			Message: "Failed to read worker response",
-			Error:   err.Error(),
-		})
-		return
-	}
+	// 本地对象存储的签名下载链接
+	r.GET("/storage/*key", h.ServeSignedObject)
 
-	// 写入响应到客户端
-	c.Writer.Write(bodyBytes)
-
-	// 如果请求是获取状态，尝试更新本地状态
-	if workerPath == "/api/status" || workerPath == "/api/login/status" {
-		var result map[string]interface{}
-		if err := json.Unmarshal(bodyBytes, &result); err == nil {
-			// 尝试获取 status 字段
-			var statusStr string
-
-			// 检查直接的 status 字段
-			if s, ok := result["status"].(string); ok {
-				statusStr = s
-			} else if data, ok := result["data"].(map[string]interface{}); ok {
-				// 检查 data.status
-				if s, ok := data["status"].(string); ok {
-					statusStr = s
-				}
-			}
+	// Prometheus指标
+	r.GET("/metrics", h.Metrics)
 
-			if statusStr != "" && statusStr != account.Status {
-				h.manager.UpdateAccountStatusSafe(accountID, statusStr)
-			}
-		}
-	}
+	// Web界面
+	r.GET("/", h.Dashboard)
+	r.GET("/dashboard", h.Dashboard)
+	r.StaticFS("/dashboard/assets", dashboardAssets())
+
+	return r
 }
@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// hardwareProfileErrorStatus 将硬件指纹配置错误映射为HTTP状态码
+func hardwareProfileErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrHardwareProfileNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrHardwareProfileInUse), errors.Is(err, service.ErrHardwareProfileExists):
+		return http.StatusConflict
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// CreateHardwareProfile 创建硬件指纹配置
+// @Summary Create Hardware Profile
+// @Description Save a named browser fingerprint (OS, browser, user agent, screen resolution, locale, timezone). Accounts bound to it via hardware_profile_id get the same fingerprint every time their worker is started or logs in, so WhatsApp sees a stable device across restarts. Locale is a language tag such as en-US; timezone is an IANA name such as Asia/Shanghai.
+// @Tags HardwareProfile
+// @Accept json
+// @Produce json
+// @Param request body model.HardwareProfileRequest true "Hardware Profile"
+// @Success 201 {object} model.APIResponse{data=model.HardwareProfile}
+// @Failure 400 {object} model.APIResponse
+// @Failure 409 {object} model.APIResponse
+// @Router /hardware-profiles [post]
+func (h *Handler) CreateHardwareProfile(c *gin.Context) {
+	var req model.HardwareProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	profile, err := h.manager.CreateHardwareProfile(&req)
+	if err != nil {
+		respond(c, hardwareProfileErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to save hardware profile",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusCreated, model.APIResponse{
+		Success: true,
+		Message: "Hardware profile saved successfully",
+		Data:    profile,
+	})
+}
+
+// ListHardwareProfiles 列出硬件指纹配置
+// @Summary List Hardware Profiles
+// @Description List hardware fingerprint profiles ordered by name, with the number of accounts bound to each
+// @Tags HardwareProfile
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending"
+// @Success 200 {object} model.APIResponse{data=[]model.HardwareProfile}
+// @Router /hardware-profiles [get]
+func (h *Handler) ListHardwareProfiles(c *gin.Context) {
+	profiles, err := h.manager.ListHardwareProfiles()
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to list hardware profiles",
+			Error:   err.Error(),
+		})
+		return
+	}
+	respondList(c, profiles, "Hardware profiles retrieved successfully")
+}
+
+// GetHardwareProfile 获取硬件指纹配置
+// @Summary Get Hardware Profile
+// @Description Get a hardware fingerprint profile and the number of accounts bound to it
+// @Tags HardwareProfile
+// @Produce json
+// @Param id path string true "Profile ID"
+// @Success 200 {object} model.APIResponse{data=model.HardwareProfile}
+// @Failure 404 {object} model.APIResponse
+// @Router /hardware-profiles/{id} [get]
+func (h *Handler) GetHardwareProfile(c *gin.Context) {
+	profile, err := h.manager.GetHardwareProfile(c.Param("id"))
+	if err != nil {
+		respond(c, hardwareProfileErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Hardware profile not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Hardware profile retrieved successfully",
+		Data:    profile,
+	})
+}
+
+// UpdateHardwareProfile 更新硬件指纹配置
+// @Summary Update Hardware Profile
+// @Description Replace all fields of a hardware fingerprint profile. Bound accounts pick up the new fingerprint the next time their worker is started or logs in; running workers are not restarted.
+// @Tags HardwareProfile
+// @Accept json
+// @Produce json
+// @Param id path string true "Profile ID"
+// @Param request body model.HardwareProfileRequest true "Hardware Profile"
+// @Success 200 {object} model.APIResponse{data=model.HardwareProfile}
+// @Failure 400 {object} model.APIResponse
+// @Failure 404 {object} model.APIResponse
+// @Failure 409 {object} model.APIResponse
+// @Router /hardware-profiles/{id} [put]
+func (h *Handler) UpdateHardwareProfile(c *gin.Context) {
+	var req model.HardwareProfileRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	profile, err := h.manager.UpdateHardwareProfile(c.Param("id"), &req)
+	if err != nil {
+		respond(c, hardwareProfileErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to save hardware profile",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Hardware profile saved successfully",
+		Data:    profile,
+	})
+}
+
+// DeleteHardwareProfile 删除硬件指纹配置
+// @Summary Delete Hardware Profile
+// @Description Delete a hardware fingerprint profile. Profiles still bound to accounts cannot be deleted (409); unbind them first with PATCH /accounts/{id} and hardware_profile_id "".
+// @Tags HardwareProfile
+// @Produce json
+// @Param id path string true "Profile ID"
+// @Success 200 {object} model.APIResponse
+// @Failure 404 {object} model.APIResponse
+// @Failure 409 {object} model.APIResponse
+// @Router /hardware-profiles/{id} [delete]
+func (h *Handler) DeleteHardwareProfile(c *gin.Context) {
+	if err := h.manager.DeleteHardwareProfile(c.Param("id")); err != nil {
+		respond(c, hardwareProfileErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to delete hardware profile",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Hardware profile deleted successfully",
+	})
+}
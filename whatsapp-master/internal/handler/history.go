@@ -0,0 +1,83 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// GetStatusHistory 获取账号状态历史
+// @Summary Get Account Status History
+// @Description List the account's status transitions (previous and new status, trigger source, reason and request ID), newest first
+// @Tags Account
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending (default -created_at)"
+// @Param filter[status] query string false "New status, comma separated"
+// @Param filter[source] query string false "Trigger source: api, worker, supervisor, reconcile, shutdown, chaos"
+// @Success 200 {object} model.APIResponse{data=[]model.StatusTransition}
+// @Router /accounts/{id}/history [get]
+func (h *Handler) GetStatusHistory(c *gin.Context) {
+	accountID := c.Param("id")
+	if !h.accountExists(c, accountID) {
+		return
+	}
+
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	history, total, err := h.manager.ListStatusHistory(accountID, q)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to get status history",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, history, buildListMeta(q, total, len(history)), "Status history retrieved successfully")
+}
+
+// GetAccountSLA 获取账号可用性
+// @Summary Get Account SLA
+// @Description Availability of the account over the last 24h, 7d and 30d derived from its status history, with its outages of the last 30 days (newest first). Time spent logged_in counts as uptime; creating, stopping and stopped are planned and not counted; every other status is downtime. Consecutive down statuses form one outage, which ends when the account is logged in again or stopped. availability is null when a window has no counted time.
+// @Tags Account
+// @Produce json
+// @Param id path string true "Account ID"
+// @Success 200 {object} model.APIResponse{data=model.AccountSLA}
+// @Failure 404 {object} model.APIResponse
+// @Router /accounts/{id}/sla [get]
+func (h *Handler) GetAccountSLA(c *gin.Context) {
+	accountID := c.Param("id")
+	if !h.accountExists(c, accountID) {
+		return
+	}
+
+	sla, err := h.manager.GetAccountSLA(accountID)
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get account SLA",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Account SLA retrieved successfully",
+		Data:    sla,
+	})
+}
@@ -0,0 +1,199 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// RegisterHost 注册远程主机
+// @Summary Register Host
+// @Description Register a remote host running fleet-agent. The returned token must be set as AGENT_TOKEN on the agent; it is only shown once. The master checks the agent immediately and schedules new workers on the host once it is online. region is the host's egress region; accounts whose proxy_region matches are placed on it according to SCHEDULER_REGION_AFFINITY. Requires the admin token.
+// @Tags Host
+// @Accept json
+// @Produce json
+// @Param request body model.CreateHostRequest true "Register Host Request"
+// @Success 200 {object} model.APIResponse{data=model.RegisteredHost}
+// @Router /hosts [post]
+func (h *Handler) RegisterHost(c *gin.Context) {
+	var req model.CreateHostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	host, err := h.manager.RegisterHost(&req)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to register host",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Host registered successfully",
+		Data:    host,
+	})
+}
+
+// ListHosts 列出主机
+// @Summary List Hosts
+// @Description List the master host and registered remote hosts with their status, resources and the number of workers placed on them. Requires the admin token.
+// @Tags Host
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending, e.g. -workers"
+// @Param filter[status] query string false "Host status"
+// @Param filter[region] query string false "Host region"
+// @Success 200 {object} model.APIResponse{data=[]model.Host}
+// @Router /hosts [get]
+func (h *Handler) ListHosts(c *gin.Context) {
+	respondList(c, h.manager.ListHosts(), "Hosts retrieved successfully")
+}
+
+// GetHost 获取主机
+// @Summary Get Host
+// @Description Get a host with its status, resources and the number of workers placed on it. Requires the admin token.
+// @Tags Host
+// @Produce json
+// @Param id path string true "Host ID"
+// @Success 200 {object} model.APIResponse{data=model.Host}
+// @Router /hosts/{id} [get]
+func (h *Handler) GetHost(c *gin.Context) {
+	host, err := h.manager.GetHost(c.Param("id"))
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Host not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Host retrieved successfully",
+		Data:    host,
+	})
+}
+
+// UpdateHost 修改主机
+// @Summary Update Host
+// @Description Update a remote host's address, agent URL, region or capacity, or cordon it to stop scheduling new workers on it. The master host is configured with SCHEDULER_LOCAL_* settings. Requires the admin token.
+// @Tags Host
+// @Accept json
+// @Produce json
+// @Param id path string true "Host ID"
+// @Param request body model.UpdateHostRequest true "Update Host Request"
+// @Success 200 {object} model.APIResponse{data=model.Host}
+// @Router /hosts/{id} [put]
+func (h *Handler) UpdateHost(c *gin.Context) {
+	if !h.requireHost(c) {
+		return
+	}
+
+	var req model.UpdateHostRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	host, err := h.manager.UpdateHost(c.Param("id"), &req)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to update host",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Host updated successfully",
+		Data:    host,
+	})
+}
+
+// DeleteHost 注销主机
+// @Summary Remove Host
+// @Description Remove a registered remote host. Hosts that still have workers placed on them must be evacuated first. Requires the admin token.
+// @Tags Host
+// @Produce json
+// @Param id path string true "Host ID"
+// @Success 200 {object} model.APIResponse
+// @Router /hosts/{id} [delete]
+func (h *Handler) DeleteHost(c *gin.Context) {
+	if !h.requireHost(c) {
+		return
+	}
+
+	if err := h.manager.DeleteHost(c.Param("id")); err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrHostHasWorkers) {
+			status = http.StatusConflict
+		}
+		respond(c, status, model.APIResponse{
+			Success: false,
+			Message: "Failed to remove host",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Host removed successfully",
+	})
+}
+
+// EvacuateHost 迁移主机上的账号
+// @Summary Evacuate Host
+// @Description Cordon the host and move its workers to other hosts in a background job; stopped accounts are rescheduled on their next start. Poll /jobs/{id} for progress. Requires the admin token.
+// @Tags Host
+// @Produce json
+// @Param id path string true "Host ID"
+// @Success 202 {object} model.APIResponse{data=model.Job}
+// @Router /hosts/{id}/evacuate [post]
+func (h *Handler) EvacuateHost(c *gin.Context) {
+	if !h.requireHost(c) {
+		return
+	}
+
+	job, err := h.manager.EvacuateHost(c.Param("id"), "evacuated via API")
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to evacuate host",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusAccepted, model.APIResponse{
+		Success: true,
+		Message: "Host evacuation started",
+		Data:    job,
+	})
+}
+
+// requireHost 主机不存在时返回404
+func (h *Handler) requireHost(c *gin.Context) bool {
+	if _, err := h.manager.GetHost(c.Param("id")); err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Host not found",
+			Error:   err.Error(),
+		})
+		return false
+	}
+	return true
+}
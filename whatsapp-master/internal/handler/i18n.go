@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/i18n"
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+)
+
+// respond 输出JSON响应，按请求语言翻译消息并附带稳定的消息代码
+func respond(c *gin.Context, status int, resp model.APIResponse) {
+	if resp.Code == "" {
+		resp.Code = i18n.Code(resp.Message)
+	}
+	c.Set(auditResponseKey, resp)
+	resp.Message = i18n.T(middleware.GetLocale(c), resp.Message)
+	c.JSON(status, resp)
+}
+
+// abort 终止请求并输出本地化的JSON响应
+func abort(c *gin.Context, status int, resp model.APIResponse) {
+	c.Abort()
+	respond(c, status, resp)
+}
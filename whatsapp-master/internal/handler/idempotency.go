@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+const (
+	// idempotencyHeader 客户端提供的幂等键请求头
+	idempotencyHeader = "Idempotency-Key"
+	// idempotencyReplayedHeader 响应来自首次请求的缓存时设置
+	idempotencyReplayedHeader = "Idempotent-Replayed"
+	// maxIdempotencyKey 幂等键最大长度
+	maxIdempotencyKey = 128
+)
+
+// idempotent 支持 Idempotency-Key：同一调用方在同一接口上用相同的键重试时不再执行，直接返回首次请求的响应。
+// 服务端错误和限流（429）的响应不保存，客户端可以用同一个键重试
+func (h *Handler) idempotent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idempotencyKey := c.GetHeader(idempotencyHeader)
+		// 模拟发送不保存响应，否则之后用同一个键的真实发送会得到模拟结果
+		if idempotencyKey == "" || !h.manager.IdempotencyEnabled() || c.Query("dry_run") == "true" {
+			c.Next()
+			return
+		}
+		if len(idempotencyKey) > maxIdempotencyKey {
+			abort(c, http.StatusBadRequest, model.APIResponse{
+				Success: false,
+				Message: "Invalid idempotency key",
+				Error:   "Idempotency-Key must be at most 128 characters",
+			})
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			abort(c, http.StatusBadRequest, model.APIResponse{
+				Success: false,
+				Message: "Invalid request format",
+				Error:   err.Error(),
+			})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		sum := sha256.Sum256(body)
+
+		key := idempotencyScope(c) + "|" + c.FullPath() + "|" + idempotencyKey
+		replay, err := h.manager.BeginIdempotent(key, hex.EncodeToString(sum[:]))
+		switch {
+		case errors.Is(err, service.ErrIdempotencyInProgress):
+			c.Header("Retry-After", "1")
+			abort(c, http.StatusConflict, model.APIResponse{
+				Success: false,
+				Message: "Request with this idempotency key is in progress",
+				Error:   err.Error(),
+			})
+			return
+		case errors.Is(err, service.ErrIdempotencyMismatch):
+			abort(c, http.StatusUnprocessableEntity, model.APIResponse{
+				Success: false,
+				Message: "Idempotency key reused with a different request",
+				Error:   err.Error(),
+			})
+			return
+		case err != nil:
+			abort(c, http.StatusInternalServerError, model.APIResponse{
+				Success: false,
+				Message: "Failed to check idempotency key",
+				Error:   err.Error(),
+			})
+			return
+		}
+
+		if replay != nil {
+			var resp model.APIResponse
+			if err := json.Unmarshal([]byte(replay.Response), &resp); err == nil {
+				c.Header(idempotencyReplayedHeader, "true")
+				abort(c, replay.Status, resp)
+				return
+			}
+		}
+
+		finished := false
+		defer func() {
+			if !finished {
+				h.manager.ReleaseIdempotent(key)
+			}
+		}()
+		c.Next()
+
+		status := c.Writer.Status()
+		value, ok := c.Get(auditResponseKey)
+		if !ok || status >= http.StatusInternalServerError || status == http.StatusTooManyRequests {
+			return
+		}
+		data, err := json.Marshal(value.(model.APIResponse))
+		if err != nil {
+			return
+		}
+		if err := h.manager.FinishIdempotent(key, status, string(data)); err != nil {
+			slog.Warn("Failed to save idempotent response", "route", c.FullPath(), "error", err)
+			return
+		}
+		finished = true
+	}
+}
+
+// idempotencyScope 幂等键的作用范围：不同调用方使用相同的键互不影响
+func idempotencyScope(c *gin.Context) string {
+	if apiKeyID := middleware.APIKeyID(c); apiKeyID != "" {
+		return "api_key:" + apiKeyID
+	}
+	if tenantID, scoped := middleware.TenantID(c); scoped {
+		return "tenant:" + tenantID
+	}
+	return auditActor(c)
+}
@@ -0,0 +1,108 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+)
+
+// ListInbox 跨账号收件箱
+// @Summary List Inbox
+// @Description Inbound messages of all accounts, collected from the workers in the background (INBOX_COLLECT_INTERVAL_SECONDS), newest first
+// @Tags Message
+// @Produce json
+// @Param unread query bool false "Only messages not yet marked read"
+// @Param contact query string false "Contact contains this text"
+// @Param since query string false "Only messages at or after this time (RFC3339)"
+// @Param until query string false "Only messages before this time (RFC3339)"
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending (default -timestamp)"
+// @Param filter[account_id] query string false "Account IDs (comma separated)"
+// @Param filter[type] query string false "Message type"
+// @Success 200 {object} model.APIResponse{data=[]model.Message}
+// @Router /inbox [get]
+func (h *Handler) ListInbox(c *gin.Context) {
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	since, until, err := parseTimeRange(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid time range",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	filter := &model.InboxFilter{
+		Contact: c.Query("contact"),
+		Unread:  c.Query("unread") == "true",
+		Since:   since,
+		Until:   until,
+	}
+	if tenantID, scoped := middleware.TenantID(c); scoped {
+		filter.AccountIDs = h.manager.TenantAccountIDs(tenantID)
+	}
+
+	messages, total, err := h.manager.ListInbox(filter, q)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list inbox",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, messages, buildListMeta(q, total, len(messages)), "Inbox retrieved successfully")
+}
+
+// MarkInboxRead 标记收件箱消息已读
+// @Summary Mark Inbox Read
+// @Description Mark inbound messages as read by message IDs, account, contact and/or receive time, so they no longer match unread=true
+// @Tags Message
+// @Accept json
+// @Produce json
+// @Param request body model.MarkInboxReadRequest true "Messages to mark"
+// @Success 200 {object} model.APIResponse{data=model.MarkInboxReadResult}
+// @Router /inbox/read [post]
+func (h *Handler) MarkInboxRead(c *gin.Context) {
+	var req model.MarkInboxReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	var accountIDs []string
+	if tenantID, scoped := middleware.TenantID(c); scoped {
+		accountIDs = h.manager.TenantAccountIDs(tenantID)
+	}
+
+	result, err := h.manager.MarkInboxRead(&req, accountIDs)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to mark messages read",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Messages marked read",
+		Data:    result,
+	})
+}
@@ -0,0 +1,394 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"whatsapp-aggregator/internal/config"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// testPortRange 每个测试服务可分配的Worker端口数
+const testPortRange = 20
+
+// testServer 使用 WORKER_MODE=mock 的完整Master：临时目录中的SQLite数据库和会话目录，
+// Worker是进程内的模拟Worker，不需要Docker和WhatsApp
+type testServer struct {
+	t       *testing.T
+	url     string
+	manager *service.Manager
+}
+
+func newTestServer(t *testing.T) *testServer {
+	t.Helper()
+	dir := t.TempDir()
+	t.Chdir(dir)
+	t.Setenv("PWD", dir)
+	t.Setenv("WORKER_MODE", service.WorkerModeMock)
+	t.Setenv("WORKER_MOCK_LOGIN_DELAY_MS", "100")
+	t.Setenv("WORKER_BASE_PORT", strconv.Itoa(freePortRange(t)))
+	t.Setenv("WORKER_PORT_RANGE", strconv.Itoa(testPortRange))
+	t.Setenv("WORKER_STOP_ON_SHUTDOWN", "true")
+	t.Setenv("SESSION_DIR", filepath.Join(dir, "sessions"))
+	t.Setenv("DB_TYPE", "sqlite")
+	t.Setenv("DB_NAME", filepath.Join(dir, "test.db"))
+
+	manager, err := service.NewManager(config.Load())
+	if err != nil {
+		t.Fatalf("create manager: %v", err)
+	}
+	manager.StartLeaderElection()
+	server := httptest.NewServer(NewHandler(manager).SetupRoutes())
+	t.Cleanup(func() {
+		server.Close()
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := manager.Shutdown(ctx); err != nil {
+			t.Errorf("shutdown: %v", err)
+		}
+	})
+
+	ts := &testServer{t: t, url: server.URL + "/api/v1", manager: manager}
+	ts.waitFor("leadership", manager.IsLeader)
+	return ts
+}
+
+// freePortRange 找一段当前空闲的端口作为Worker端口池
+func freePortRange(t *testing.T) int {
+	t.Helper()
+	for attempt := 0; attempt < 20; attempt++ {
+		l, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("listen: %v", err)
+		}
+		base := l.Addr().(*net.TCPAddr).Port
+		l.Close()
+		if base+testPortRange > 65535 {
+			continue
+		}
+		free := true
+		for port := base; port < base+testPortRange && free; port++ {
+			l, err := net.Listen("tcp", "127.0.0.1:"+strconv.Itoa(port))
+			if err != nil {
+				free = false
+				continue
+			}
+			l.Close()
+		}
+		if free {
+			return base
+		}
+	}
+	t.Fatal("no free port range")
+	return 0
+}
+
+// apiResult 解析后的API响应，Data 保留原始JSON
+type apiResult struct {
+	Status  int
+	Success bool            `json:"success"`
+	Code    string          `json:"code"`
+	Data    json.RawMessage `json:"data"`
+	Error   string          `json:"error"`
+}
+
+// call 发送API请求，body不为nil时编码为JSON
+func (s *testServer) call(method, path string, body interface{}) apiResult {
+	s.t.Helper()
+	var reader *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			s.t.Fatalf("marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequest(method, s.url+path, reader)
+	if err != nil {
+		s.t.Fatalf("create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		s.t.Fatalf("%s %s: %v", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	result := apiResult{Status: resp.StatusCode}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		s.t.Fatalf("%s %s: decode response: %v", method, path, err)
+	}
+	return result
+}
+
+// mustCall 发送请求并要求返回指定状态码，data 解码到 out（可为nil）
+func (s *testServer) mustCall(method, path string, body interface{}, status int, out interface{}) apiResult {
+	s.t.Helper()
+	result := s.call(method, path, body)
+	if result.Status != status {
+		s.t.Fatalf("%s %s = %d %s (%s), want %d", method, path, result.Status, result.Code, result.Error, status)
+	}
+	if out != nil {
+		if err := json.Unmarshal(result.Data, out); err != nil {
+			s.t.Fatalf("%s %s: decode data: %v", method, path, err)
+		}
+	}
+	return result
+}
+
+// account 读取账号
+func (s *testServer) account(id string) *model.Account {
+	s.t.Helper()
+	var account model.Account
+	s.mustCall(http.MethodGet, "/accounts/"+id, nil, http.StatusOK, &account)
+	return &account
+}
+
+// waitFor 轮询直到条件成立，超时则测试失败
+func (s *testServer) waitFor(what string, cond func() bool) {
+	s.t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			s.t.Fatalf("timed out waiting for %s", what)
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+}
+
+// waitForStatus 等待账号进入指定状态
+func (s *testServer) waitForStatus(id, status string) *model.Account {
+	s.t.Helper()
+	var account *model.Account
+	s.waitFor("account "+id+" to become "+status, func() bool {
+		account = s.account(id)
+		return account.Status == status
+	})
+	return account
+}
+
+// login 扫码登录并等待模拟Worker完成登录
+func (s *testServer) login(id string) {
+	s.t.Helper()
+	s.mustCall(http.MethodPost, "/qr-login", map[string]string{"account_id": id}, http.StatusOK, nil)
+	s.waitForStatus(id, "logged_in")
+}
+
+// workerStarted 账号的Worker已就绪：创建完成时为running，模拟Worker先推送了状态时为idle
+func workerStarted(account *model.Account) bool {
+	return account.Status == "running" || account.Status == "idle"
+}
+
+// workerReachable 模拟Worker的状态接口是否可访问
+func workerReachable(serviceURL string) bool {
+	client := &http.Client{Timeout: time.Second}
+	resp, err := client.Get(serviceURL + "/api/health")
+	if err != nil {
+		return false
+	}
+	resp.Body.Close()
+	return true
+}
+
+func TestAccountLifecycle(t *testing.T) {
+	s := newTestServer(t)
+
+	var created model.Account
+	s.mustCall(http.MethodPost, "/accounts", map[string]interface{}{"account_id": "acc1", "tags": []string{"sales"}}, http.StatusOK, &created)
+	if created.ID != "acc1" || !workerStarted(&created) || len(created.Tags) != 1 || created.Tags[0] != "sales" {
+		t.Fatalf("created account = %s %s %v", created.ID, created.Status, created.Tags)
+	}
+	if created.WorkerVersion != "mock" || created.HostID != model.LocalHostID || created.ServiceURL == "" {
+		t.Errorf("worker = %s on %s at %s", created.WorkerVersion, created.HostID, created.ServiceURL)
+	}
+	if !workerReachable(created.ServiceURL) {
+		t.Fatalf("mock worker at %s is not reachable", created.ServiceURL)
+	}
+
+	var list []model.Account
+	s.mustCall(http.MethodGet, "/accounts", nil, http.StatusOK, &list)
+	if len(list) != 1 || list[0].ID != "acc1" {
+		t.Errorf("accounts = %+v", list)
+	}
+
+	s.login("acc1")
+
+	var sent map[string]interface{}
+	s.mustCall(http.MethodPost, "/send-message", map[string]string{
+		"account_id": "acc1",
+		"contact":    "8613800000001",
+		"message":    "hello from the test",
+	}, http.StatusOK, &sent)
+	messageID, _ := sent["message_id"].(string)
+	if messageID == "" {
+		t.Fatalf("send response has no message_id: %+v", sent)
+	}
+
+	var messages []model.Message
+	s.mustCall(http.MethodGet, "/accounts/acc1/messages", nil, http.StatusOK, &messages)
+	if len(messages) != 1 {
+		t.Fatalf("messages = %d, want 1", len(messages))
+	}
+	msg := messages[0]
+	if msg.ID != messageID || msg.Direction != "outbound" || msg.Contact != "8613800000001" || msg.Body != "hello from the test" || msg.Status != "sent" {
+		t.Errorf("message = %s %s %s %q %s", msg.ID, msg.Direction, msg.Contact, msg.Body, msg.Status)
+	}
+	if msg.WorkerMessageID == "" {
+		t.Error("message has no worker message id")
+	}
+	if account := s.account("acc1"); account.MessagesSent != 1 {
+		t.Errorf("messages_sent = %d, want 1", account.MessagesSent)
+	}
+
+	s.mustCall(http.MethodDelete, "/accounts/acc1", nil, http.StatusOK, nil)
+	if result := s.call(http.MethodGet, "/accounts/acc1", nil); result.Status != http.StatusNotFound || result.Code != "account_not_found" {
+		t.Errorf("get deleted account = %d %s, want 404 account_not_found", result.Status, result.Code)
+	}
+	if workerReachable(created.ServiceURL) {
+		t.Error("mock worker still running after the account was deleted")
+	}
+	s.mustCall(http.MethodGet, "/accounts", nil, http.StatusOK, &list)
+	if len(list) != 0 {
+		t.Errorf("accounts after delete = %d, want 0", len(list))
+	}
+}
+
+func TestCreateAccountAsync(t *testing.T) {
+	s := newTestServer(t)
+
+	// Job.Result 是数据库中的原始JSON，这里只取需要的字段
+	var job struct {
+		ID     string `json:"id"`
+		Type   string `json:"type"`
+		Status string `json:"status"`
+		Error  string `json:"error"`
+	}
+	s.mustCall(http.MethodPost, "/accounts?async=true", map[string]string{"account_id": "acc1"}, http.StatusAccepted, &job)
+	if job.Type != "create_account" || job.ID == "" {
+		t.Fatalf("job = %+v", job)
+	}
+
+	// 账号在任务启动前就已登记，重复创建立即被拒绝
+	if result := s.call(http.MethodPost, "/accounts", map[string]string{"account_id": "acc1"}); result.Success {
+		t.Errorf("duplicate create = %d %s, want failure", result.Status, result.Code)
+	}
+
+	s.waitFor("create job to finish", func() bool {
+		s.mustCall(http.MethodGet, "/jobs/"+job.ID, nil, http.StatusOK, &job)
+		return job.Status != "running"
+	})
+	if job.Status != "succeeded" {
+		t.Fatalf("job status = %s (%s), want succeeded", job.Status, job.Error)
+	}
+	account := s.account("acc1")
+	if !workerStarted(account) || !workerReachable(account.ServiceURL) {
+		t.Errorf("account = %s at %s", account.Status, account.ServiceURL)
+	}
+}
+
+func TestCreateAccountErrors(t *testing.T) {
+	s := newTestServer(t)
+	s.mustCall(http.MethodPost, "/accounts", map[string]string{"account_id": "acc1"}, http.StatusOK, nil)
+
+	tests := []struct {
+		name   string
+		body   interface{}
+		status int
+		code   string
+	}{
+		{"missing account id", map[string]string{"name": "x"}, http.StatusBadRequest, "invalid_request_format"},
+		{"invalid json", "not an object", http.StatusBadRequest, "invalid_request_format"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := s.call(http.MethodPost, "/accounts", tt.body)
+			if result.Status != tt.status || result.Code != tt.code {
+				t.Errorf("create = %d %s, want %d %s", result.Status, result.Code, tt.status, tt.code)
+			}
+		})
+	}
+
+	t.Run("duplicate account", func(t *testing.T) {
+		result := s.call(http.MethodPost, "/accounts", map[string]string{"account_id": "acc1"})
+		if result.Success || result.Status < 400 {
+			t.Errorf("duplicate create = %d %s, want failure", result.Status, result.Code)
+		}
+		var list []model.Account
+		s.mustCall(http.MethodGet, "/accounts", nil, http.StatusOK, &list)
+		if len(list) != 1 {
+			t.Errorf("accounts = %d, want 1", len(list))
+		}
+	})
+}
+
+func TestSendMessageErrors(t *testing.T) {
+	s := newTestServer(t)
+	s.mustCall(http.MethodPost, "/accounts", map[string]string{"account_id": "acc1"}, http.StatusOK, nil)
+
+	t.Run("unknown account", func(t *testing.T) {
+		result := s.call(http.MethodPost, "/send-message", map[string]string{"account_id": "missing", "contact": "8613800000001", "message": "hi"})
+		if result.Status != http.StatusNotFound {
+			t.Errorf("send = %d %s, want 404", result.Status, result.Code)
+		}
+	})
+	t.Run("not logged in", func(t *testing.T) {
+		result := s.call(http.MethodPost, "/send-message", map[string]string{"account_id": "acc1", "contact": "8613800000001", "message": "hi"})
+		if result.Success {
+			t.Errorf("send before login = %d %s, want failure", result.Status, result.Code)
+		}
+	})
+	t.Run("missing message", func(t *testing.T) {
+		result := s.call(http.MethodPost, "/send-message", map[string]string{"account_id": "acc1", "contact": "8613800000001"})
+		if result.Status != http.StatusBadRequest {
+			t.Errorf("send = %d %s, want 400", result.Status, result.Code)
+		}
+	})
+
+	var messages []model.Message
+	s.mustCall(http.MethodGet, "/accounts/acc1/messages", nil, http.StatusOK, &messages)
+	for _, msg := range messages {
+		if msg.Status == "sent" {
+			t.Errorf("message %s recorded as sent", msg.ID)
+		}
+	}
+}
+
+func TestDeleteAccount(t *testing.T) {
+	s := newTestServer(t)
+
+	if result := s.call(http.MethodDelete, "/accounts/missing", nil); result.Status != http.StatusNotFound {
+		t.Errorf("delete unknown account = %d %s, want 404", result.Status, result.Code)
+	}
+
+	// 已登录的账号删除后Worker停止、端口释放，可以用同一ID重新创建
+	var created model.Account
+	s.mustCall(http.MethodPost, "/accounts", map[string]string{"account_id": "acc1"}, http.StatusOK, &created)
+	s.login("acc1")
+	s.mustCall(http.MethodDelete, "/accounts/acc1", nil, http.StatusOK, nil)
+	if workerReachable(created.ServiceURL) {
+		t.Error("mock worker still running after the account was deleted")
+	}
+
+	var recreated model.Account
+	s.mustCall(http.MethodPost, "/accounts", map[string]string{"account_id": "acc1"}, http.StatusOK, &recreated)
+	if !workerReachable(recreated.ServiceURL) {
+		t.Fatalf("recreated account's worker at %s is not reachable", recreated.ServiceURL)
+	}
+	s.login("acc1")
+	var messages []model.Message
+	s.mustCall(http.MethodGet, "/accounts/acc1/messages", nil, http.StatusOK, &messages)
+	if len(messages) != 0 {
+		t.Errorf("recreated account has %d messages", len(messages))
+	}
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// GetJanitorStatus 获取清理任务状态
+// @Summary Get Janitor Status
+// @Description Get janitor settings, the last cleanup report and total disk space reclaimed since startup
+// @Tags System
+// @Produce json
+// @Success 200 {object} model.APIResponse{data=model.JanitorStatus}
+// @Router /system/janitor [get]
+func (h *Handler) GetJanitorStatus(c *gin.Context) {
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Janitor status retrieved successfully",
+		Data:    h.manager.GetJanitorStatus(),
+	})
+}
+
+// RunJanitor 立即执行一次清理
+// @Summary Run Janitor
+// @Description Remove exited fleet containers, session directories of accounts deleted longer than JANITOR_RETENTION_DAYS ago and expired diagnostic bundles. Use dry_run=true to only report what would be removed.
+// @Tags System
+// @Produce json
+// @Param dry_run query bool false "Only report, do not delete"
+// @Success 200 {object} model.APIResponse{data=model.JanitorReport}
+// @Router /system/janitor/run [post]
+func (h *Handler) RunJanitor(c *gin.Context) {
+	report, err := h.manager.RunJanitor(c.Query("dry_run") == "true")
+	if err != nil {
+		respond(c, http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: "Failed to run janitor",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Janitor run completed",
+		Data:    report,
+	})
+}
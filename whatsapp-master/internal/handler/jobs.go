@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// ListJobs 列出后台任务
+// @Summary List Jobs
+// @Description List background jobs (worker restarts, bulk sends, message retries, campaigns, janitor runs, account creation, worker upgrades) with their progress
+// @Tags Job
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending"
+// @Param filter[type] query string false "restart_workers, restart_account, bulk_send, message_retry, campaign, janitor, create_account or upgrade_workers"
+// @Param filter[status] query string false "running, succeeded, failed, cancelled or interrupted"
+// @Success 200 {object} model.APIResponse{data=[]model.Job}
+// @Router /jobs [get]
+func (h *Handler) ListJobs(c *gin.Context) {
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	jobs, total, err := h.manager.ListJobs(q)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list jobs",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, jobs, buildListMeta(q, total, len(jobs)), "Jobs retrieved successfully")
+}
+
+// GetJob 获取任务状态
+// @Summary Get Job
+// @Description Get a background job with its progress, result and error
+// @Tags Job
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 200 {object} model.APIResponse{data=model.Job}
+// @Router /jobs/{id} [get]
+func (h *Handler) GetJob(c *gin.Context) {
+	job, err := h.manager.GetJob(c.Param("id"))
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Job not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Job retrieved successfully",
+		Data:    job,
+	})
+}
+
+// CancelJob 取消任务
+// @Summary Cancel Job
+// @Description Request cancellation of a running job. Work items already in progress are completed; the job then finishes with status cancelled.
+// @Tags Job
+// @Produce json
+// @Param id path string true "Job ID"
+// @Success 202 {object} model.APIResponse{data=model.Job}
+// @Router /jobs/{id}/cancel [post]
+func (h *Handler) CancelJob(c *gin.Context) {
+	job, err := h.manager.CancelJob(c.Param("id"))
+	if err != nil {
+		respond(c, http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: "Failed to cancel job",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusAccepted, model.APIResponse{
+		Success: true,
+		Message: "Job cancellation requested",
+		Data:    job,
+	})
+}
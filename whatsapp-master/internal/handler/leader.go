@@ -0,0 +1,128 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/logging"
+	"whatsapp-aggregator/internal/model"
+)
+
+// forwardedByHeader Follower转发给Leader的请求带有该头，值为转发的副本ID，用于防止在Leader切换期间循环转发
+const forwardedByHeader = "X-Fleet-Forwarded-By"
+
+// replicaWriteRoutes Follower本地处理的写请求：只转发到Worker或写入消息记录，不修改账号和后台任务状态
+var replicaWriteRoutes = map[string]bool{
+	"POST /api/v1/send-message":                                 true,
+	"POST /api/v1/send-media":                                   true,
+	"POST /api/v1/inbox/read":                                   true,
+	"POST /api/v1/accounts/:id/typing":                          true,
+	"POST /api/v1/accounts/:id/contacts":                        true,
+	"POST /api/v1/accounts/:id/debug/check-messages":            true,
+	"POST /api/v1/accounts/:id/groups":                          true,
+	"POST /api/v1/accounts/:id/groups/participants":             true,
+	"PUT /api/v1/accounts/:id/groups/:gid":                      true,
+	"POST /api/v1/accounts/:id/groups/:gid/participants/remove": true,
+	"POST /api/v1/accounts/:id/groups/:gid/admins/promote":      true,
+	"POST /api/v1/accounts/:id/groups/:gid/admins/demote":       true,
+	"POST /api/v1/accounts/:id/conversations/:contact/claim":    true,
+	"POST /api/v1/accounts/:id/conversations/:contact/release":  true,
+}
+
+// leaderReadRoutes 依赖Leader内存状态的读请求：批量发送进度、清理任务状态、主机心跳、事件流和GraphQL（事件查询和订阅）
+var leaderReadRoutes = map[string]bool{
+	"GET /api/v1/send-bulk/:id":  true,
+	"GET /api/v1/system/janitor": true,
+	"GET /api/v1/hosts":          true,
+	"GET /api/v1/hosts/:id":      true,
+	"GET /api/v1/events":         true,
+	"GET /api/v1/events/ws":      true,
+	"GET /api/v1/graphql":        true,
+	"GET /api/v1/chaos/faults":   true,
+}
+
+// leaderRoute 请求是否需要由Leader处理：除 replicaWriteRoutes 外的写请求和 leaderReadRoutes 中的读请求
+func leaderRoute(method, route string) bool {
+	if route == "" {
+		return false
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return leaderReadRoutes[method+" "+route]
+	}
+	return !replicaWriteRoutes[method+" "+route]
+}
+
+// forwardToLeader Follower将需要Leader处理的请求反向代理到Leader，
+// 在审计和鉴权之前执行，由Leader记录审计并校验凭证
+func (h *Handler) forwardToLeader() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if h.manager.IsLeader() || !leaderRoute(c.Request.Method, c.FullPath()) {
+			c.Next()
+			return
+		}
+
+		leaderURL, err := h.manager.LeaderURL()
+		if err == nil && c.GetHeader(forwardedByHeader) != "" {
+			// 已被其他副本转发过，说明Leader刚刚切换，由调用方重试
+			leaderURL = ""
+		}
+		var target *url.URL
+		if leaderURL != "" {
+			target, err = url.Parse(leaderURL)
+		}
+		if target == nil || err != nil {
+			c.Header("Retry-After", "5")
+			errMsg := "leader changed while forwarding"
+			if err != nil {
+				errMsg = err.Error()
+			}
+			abort(c, http.StatusServiceUnavailable, model.APIResponse{
+				Success: false,
+				Message: "No leader available",
+				Error:   errMsg,
+			})
+			return
+		}
+
+		c.Abort()
+		proxy := &httputil.ReverseProxy{
+			FlushInterval: -1, // 事件流需要立即刷新
+			Rewrite: func(pr *httputil.ProxyRequest) {
+				pr.SetURL(target)
+				pr.Out.Host = pr.In.Host
+				pr.SetXForwarded()
+				pr.Out.Header.Set(forwardedByHeader, h.manager.ReplicaID())
+				logging.InjectRequestID(pr.Out)
+			},
+			ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+				logging.FromContext(r.Context()).Warn("Forward to leader failed", "leader_url", leaderURL, "path", r.URL.Path, "error", err)
+				c.Header("Retry-After", "5")
+				respond(c, http.StatusServiceUnavailable, model.APIResponse{
+					Success: false,
+					Message: "No leader available",
+					Error:   err.Error(),
+				})
+			},
+		}
+		proxy.ServeHTTP(c.Writer, c.Request)
+	}
+}
+
+// GetLeaderStatus 查询本副本的选举状态
+// @Summary Get Leader Status
+// @Description Show whether this master replica is the leader, the current leader and its lease. With LEADER_ELECTION=db only the leader spawns, polls and supervises workers; followers serve reads and worker proxy traffic and forward other writes to the leader.
+// @Tags System
+// @Produce json
+// @Success 200 {object} model.APIResponse{data=model.LeaderStatus}
+// @Router /system/leader [get]
+func (h *Handler) GetLeaderStatus(c *gin.Context) {
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Leader status retrieved successfully",
+		Data:    h.manager.LeaderStatus(),
+	})
+}
@@ -0,0 +1,142 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// loginFlowErrorStatus 将登录流程错误映射为HTTP状态码
+func loginFlowErrorStatus(err error) int {
+	var workerErr *service.WorkerError
+	switch {
+	case errors.Is(err, service.ErrLoginFlowNotFound), errors.Is(err, service.ErrPairingCodeUnavailable):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrLoginFlowFinished):
+		return http.StatusConflict
+	case errors.As(err, &workerErr):
+		return workerErrorStatus(err)
+	default:
+		return http.StatusBadRequest
+	}
+}
+
+// GetPairingCode 获取配对码
+// @Summary Get Pairing Code
+// @Description Relay the pairing code of a phone login from the worker, to be entered on the phone under Linked devices > Link with phone number. While a login flow is running the response includes when the code expires and which attempt it is; after it expires the master requests a new code automatically (see GET /accounts/{id}/login/flow). 404 when the worker has no code, e.g. before it is generated or after login.
+// @Tags Auth
+// @Produce json
+// @Param id path string true "Account ID"
+// @Success 200 {object} model.APIResponse{data=model.PairingCode}
+// @Failure 404 {object} model.APIResponse
+// @Failure 502 {object} model.APIResponse
+// @Router /accounts/{id}/pairing-code [get]
+func (h *Handler) GetPairingCode(c *gin.Context) {
+	accountID := c.Param("id")
+	if !h.accountExists(c, accountID) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	code, err := h.manager.GetPairingCode(ctx, accountID)
+	if err != nil {
+		respond(c, loginFlowErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Pairing code not available",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Pairing code retrieved successfully",
+		Data:    code,
+	})
+}
+
+// GetLoginFlow 获取手机号登录流程
+// @Summary Get Phone Login Flow
+// @Description Get the state machine of the account's latest phone login: requesting_code, waiting_for_code, verifying, then logged_in, failed or cancelled. Each pairing code is valid for PHONE_LOGIN_CODE_TIMEOUT_SECONDS; an expired code or a worker that failed to initialize triggers a new attempt, up to PHONE_LOGIN_MAX_ATTEMPTS, and the whole flow fails after PHONE_LOGIN_TIMEOUT_SECONDS. Finished flows are kept until the next phone login of the account.
+// @Tags Auth
+// @Produce json
+// @Param id path string true "Account ID"
+// @Success 200 {object} model.APIResponse{data=model.LoginFlow}
+// @Failure 404 {object} model.APIResponse
+// @Router /accounts/{id}/login/flow [get]
+func (h *Handler) GetLoginFlow(c *gin.Context) {
+	accountID := c.Param("id")
+	if !h.accountExists(c, accountID) {
+		return
+	}
+
+	flow, err := h.manager.GetLoginFlow(accountID)
+	if err != nil {
+		respond(c, loginFlowErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Login flow not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Login flow retrieved successfully",
+		Data:    flow,
+	})
+}
+
+// SubmitLoginVerification 提交登录流程操作
+// @Summary Submit Login Verification
+// @Description Report progress of a phone login. entered: the pairing code was typed on the phone; the flow moves to verifying and the code gets a fresh validity period so it is not replaced while WhatsApp confirms the link. resend: request a new pairing code now (counts as an attempt). cancel: stop tracking the login; the worker keeps running. 409 when the flow already finished.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param request body model.LoginVerifyRequest true "Verification action"
+// @Success 200 {object} model.APIResponse{data=model.LoginFlow}
+// @Failure 400 {object} model.APIResponse
+// @Failure 404 {object} model.APIResponse
+// @Failure 409 {object} model.APIResponse
+// @Router /accounts/{id}/login/verify [post]
+func (h *Handler) SubmitLoginVerification(c *gin.Context) {
+	var req model.LoginVerifyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	accountID := c.Param("id")
+	if !h.accountExists(c, accountID) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 90*time.Second)
+	defer cancel()
+
+	flow, err := h.manager.SubmitLoginVerification(ctx, accountID, &req)
+	if err != nil {
+		respond(c, loginFlowErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to submit login verification",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Login verification submitted successfully",
+		Data:    flow,
+	})
+}
@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+	"whatsapp-aggregator/internal/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StreamLogs 实时Worker日志流
+// @Summary Stream Worker Logs
+// @Description Stream the worker container's docker logs (stdout and stderr, with timestamps) as Server-Sent Events. Each line is a "log" event; an "end" event is sent when the logs end (the container exited, or follow=false), with the error if reading failed.
+// @Tags System
+// @Produce text/event-stream
+// @Param id path string true "Account ID"
+// @Param tail query int false "Number of recent lines to send first (default 100, max 10000)"
+// @Param follow query bool false "Keep streaming new lines (default true)"
+// @Param since query string false "Only lines after this RFC3339 time or relative duration (e.g. 10m)"
+// @Success 200 {string} string "log lines"
+// @Failure 400 {object} model.APIResponse
+// @Failure 404 {object} model.APIResponse
+// @Failure 502 {object} model.APIResponse
+// @Router /accounts/{id}/logs/stream [get]
+func (h *Handler) StreamLogs(c *gin.Context) {
+	accountID := c.Param("id")
+	if !h.accountExists(c, accountID) {
+		return
+	}
+
+	var query model.LogStreamQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	output, err := h.manager.OpenWorkerLogs(c.Request.Context(), accountID, &query)
+	if err != nil {
+		if errors.Is(err, service.ErrInvalidLogSince) {
+			respond(c, http.StatusBadRequest, model.APIResponse{
+				Success: false,
+				Message: "Invalid request format",
+				Data: validation.Errors{{
+					Field:      "since",
+					Value:      query.Since,
+					Message:    "must be an RFC3339 time or a duration like 10m",
+					Constraint: "format",
+					Type:       "string",
+				}},
+				Error: err.Error(),
+			})
+			return
+		}
+		respond(c, http.StatusBadGateway, model.APIResponse{
+			Success: false,
+			Message: "Failed to read worker logs",
+			Error:   err.Error(),
+		})
+		return
+	}
+	defer output.Close()
+
+	// 读取在单独的goroutine中进行，主循环同时发送心跳
+	lines := make(chan string, 256)
+	var readErr error
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(output)
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			select {
+			case lines <- scanner.Text():
+			case <-c.Request.Context().Done():
+				return
+			}
+		}
+		readErr = scanner.Err()
+	}()
+
+	heartbeat := time.NewTicker(eventHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case <-heartbeat.C:
+			io.WriteString(w, ": ping\n\n")
+			return true
+		case line, ok := <-lines:
+			if !ok {
+				end := gin.H{}
+				if readErr != nil && c.Request.Context().Err() == nil {
+					end["error"] = readErr.Error()
+				}
+				c.SSEvent("end", end)
+				return false
+			}
+			c.SSEvent("log", line)
+			return true
+		}
+	})
+}
@@ -0,0 +1,152 @@
+package handler
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// SendMedia 发送媒体消息
+// @Summary Send Media Message
+// @Description Send an image, document or audio message. Accepts multipart/form-data with a "file" field, or JSON with base64 "data", an http(s) "url" or the "storage_key" of a file uploaded with POST /storage/objects. URLs resolving to loopback, private or link-local addresses (including after redirects) are rejected unless MEDIA_ALLOW_PRIVATE_URLS=true. Rate limit and quota headers behave as for /send-message.
+// @Tags Message
+// @Accept json,mpfd
+// @Produce json
+// @Param request body model.MediaMessageRequest false "Media Message Request (JSON)"
+// @Param file formData file false "Media file (multipart)"
+// @Success 200 {object} model.APIResponse
+// @Router /send-media [post]
+func (h *Handler) SendMedia(c *gin.Context) {
+	var req model.MediaMessageRequest
+	if err := c.ShouldBind(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if !h.normalizePhones(c, &req) {
+		return
+	}
+
+	if !h.authorizeAccount(c, req.AccountID) {
+		return
+	}
+	recipientWarning, ok := h.checkRecipient(c, "Failed to send media", req.AccountID, req.Contact)
+	if !ok {
+		return
+	}
+	if !h.allowSend(c, "Failed to send media", req.AccountID) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 2*time.Minute)
+	defer cancel()
+
+	data, err := h.readMediaPayload(ctx, c, &req)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid media payload",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	result, err := h.manager.SendMedia(ctx, &req, data)
+	if err != nil {
+		h.respondSendError(c, req.AccountID, "Failed to send media", err)
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Media sent successfully",
+		Data:    result["data"],
+		Warning: joinWarnings(recipientWarning, h.setQuotaHeaders(c, req.AccountID)),
+	})
+}
+
+// readMediaPayload 从对象存储、上传文件、base64 或 URL 中读取媒体内容
+func (h *Handler) readMediaPayload(ctx context.Context, c *gin.Context, req *model.MediaMessageRequest) ([]byte, error) {
+	maxSize := h.manager.MaxMediaSize()
+
+	if req.StorageKey != "" {
+		tenantID, _ := middleware.TenantID(c)
+		data, err := h.manager.ReadObject(ctx, tenantID, req.StorageKey, maxSize)
+		if err != nil {
+			return nil, err
+		}
+		if req.Filename == "" {
+			req.Filename = service.ObjectFilename(req.StorageKey)
+		}
+		if req.MimeType == "" {
+			req.MimeType = mime.TypeByExtension(path.Ext(req.StorageKey))
+		}
+		return data, nil
+	}
+
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			return nil, fmt.Errorf("missing file field: %v", err)
+		}
+		if fileHeader.Size > maxSize {
+			return nil, fmt.Errorf("file exceeds max size of %d bytes", maxSize)
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+
+		if req.Filename == "" {
+			req.Filename = fileHeader.Filename
+		}
+		if req.MimeType == "" {
+			req.MimeType = fileHeader.Header.Get("Content-Type")
+		}
+		return io.ReadAll(file)
+	}
+
+	if req.Data != "" {
+		// 兼容 data URL 格式：data:image/png;base64,xxxx
+		encoded := req.Data
+		if idx := strings.Index(encoded, ";base64,"); strings.HasPrefix(encoded, "data:") && idx > 0 {
+			if req.MimeType == "" {
+				req.MimeType = strings.TrimPrefix(encoded[:idx], "data:")
+			}
+			encoded = encoded[idx+len(";base64,"):]
+		}
+		data, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 data: %v", err)
+		}
+		return data, nil
+	}
+
+	if req.URL != "" {
+		data, mimeType, filename, err := h.manager.DownloadMedia(ctx, req.URL)
+		if err != nil {
+			return nil, err
+		}
+		if req.MimeType == "" {
+			req.MimeType = mimeType
+		}
+		if req.Filename == "" {
+			req.Filename = filename
+		}
+		return data, nil
+	}
+
+	return nil, fmt.Errorf("one of file, data, url or storage_key is required")
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// promLabelEscaper 转义Prometheus标签值
+var promLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// Metrics 以Prometheus文本格式导出集群和活动指标
+func (h *Handler) Metrics(c *gin.Context) {
+	var b strings.Builder
+
+	stats := h.manager.GetStats()
+	writeMetricHeader(&b, "whatsapp_workers_total", "gauge", "Number of registered workers.")
+	fmt.Fprintf(&b, "whatsapp_workers_total %d\n", stats.TotalWorkers)
+	writeMetricHeader(&b, "whatsapp_workers_online", "gauge", "Number of online workers.")
+	fmt.Fprintf(&b, "whatsapp_workers_online %d\n", stats.OnlineWorkers)
+
+	leader := 0
+	if h.manager.IsLeader() {
+		leader = 1
+	}
+	writeMetricHeader(&b, "whatsapp_master_leader", "gauge", "Whether this master replica is the leader.")
+	fmt.Fprintf(&b, "whatsapp_master_leader{replica_id=\"%s\"} %d\n", promLabelEscaper.Replace(h.manager.ReplicaID()), leader)
+
+	statusCounts := make(map[string]int)
+	for _, account := range h.manager.ListAccounts() {
+		statusCounts[account.Status]++
+	}
+	statuses := make([]string, 0, len(statusCounts))
+	for status := range statusCounts {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+	writeMetricHeader(&b, "whatsapp_accounts", "gauge", "Number of accounts by status.")
+	for _, status := range statuses {
+		fmt.Fprintf(&b, "whatsapp_accounts{status=\"%s\"} %d\n", promLabelEscaper.Replace(status), statusCounts[status])
+	}
+
+	campaigns, err := h.manager.CampaignMetrics()
+	if err != nil {
+		c.String(http.StatusInternalServerError, "failed to collect campaign metrics: %v", err)
+		return
+	}
+
+	type campaignMetric struct {
+		name, kind, help string
+		value            func(i int) int64
+	}
+	for _, metric := range []campaignMetric{
+		{"whatsapp_campaign_queued", "gauge", "Recipients waiting to be sent in running bulk batches and campaigns.", func(i int) int64 { return int64(campaigns[i].Queued) }},
+		{"whatsapp_campaign_in_flight", "gauge", "Recipients currently being sent.", func(i int) int64 { return int64(campaigns[i].InFlight) }},
+		{"whatsapp_campaign_sent_total", "counter", "Messages sent successfully.", func(i int) int64 { return campaigns[i].Sent }},
+		{"whatsapp_campaign_failed_total", "counter", "Messages that failed to send.", func(i int) int64 { return campaigns[i].Failed }},
+		{"whatsapp_campaign_opt_outs_total", "counter", "Contacts that opted out after the campaign.", func(i int) int64 { return campaigns[i].OptOuts }},
+	} {
+		writeMetricHeader(&b, metric.name, metric.kind, metric.help)
+		for i, campaign := range campaigns {
+			fmt.Fprintf(&b, "%s{campaign=\"%s\"} %d\n", metric.name, promLabelEscaper.Replace(campaign.Campaign), metric.value(i))
+		}
+	}
+
+	concurrency := h.manager.WorkerConcurrencyMetrics()
+	type concurrencyMetric struct {
+		name, kind, help string
+		value            func(i int) int64
+	}
+	for _, metric := range []concurrencyMetric{
+		{"whatsapp_worker_requests_in_flight", "gauge", "Requests currently proxied to the account's worker.", func(i int) int64 { return int64(concurrency[i].InFlight) }},
+		{"whatsapp_worker_requests_queued", "gauge", "Proxied requests waiting for a concurrency slot.", func(i int) int64 { return int64(concurrency[i].Queued) }},
+		{"whatsapp_worker_requests_rejected_total", "counter", "Proxied requests rejected with 429 because the queue was full or the wait timed out.", func(i int) int64 { return concurrency[i].Rejected }},
+	} {
+		writeMetricHeader(&b, metric.name, metric.kind, metric.help)
+		for i, account := range concurrency {
+			fmt.Fprintf(&b, "%s{account_id=\"%s\"} %d\n", metric.name, promLabelEscaper.Replace(account.AccountID), metric.value(i))
+		}
+	}
+
+	conns := h.manager.WorkerConnectionMetrics()
+	writeMetricHeader(&b, "whatsapp_worker_connections_total", "counter", "Requests to workers by whether they reused a pooled connection.")
+	fmt.Fprintf(&b, "whatsapp_worker_connections_total{reused=\"true\"} %d\n", conns.Reused)
+	fmt.Fprintf(&b, "whatsapp_worker_connections_total{reused=\"false\"} %d\n", conns.Opened)
+
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
+
+// writeMetricHeader 写入指标的HELP和TYPE行
+func writeMetricHeader(b *strings.Builder, name, kind, help string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
@@ -0,0 +1,110 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// migrationErrorStatus 数据库版本高于当前程序返回409，其余为500
+func migrationErrorStatus(err error) int {
+	if errors.Is(err, service.ErrSchemaTooNew) {
+		return http.StatusConflict
+	}
+	return http.StatusInternalServerError
+}
+
+// GetMigrations 查询数据库迁移状态
+// @Summary Get Database Migrations
+// @Description Show the schema version of the database, the latest version this build supports and every versioned migration with whether it has been applied and can be rolled back
+// @Tags System
+// @Produce json
+// @Success 200 {object} model.APIResponse{data=model.SchemaStatus}
+// @Failure 500 {object} model.APIResponse
+// @Router /system/migrations [get]
+func (h *Handler) GetMigrations(c *gin.Context) {
+	status, err := h.manager.SchemaStatus()
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get migration status",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Migration status retrieved successfully",
+		Data:    status,
+	})
+}
+
+// ApplyMigrations 执行待执行的迁移
+// @Summary Apply Database Migrations
+// @Description Apply all pending migrations in version order, each in its own transaction. Needed when DB_AUTO_MIGRATE is false. Refused with 409 when the database was migrated by a newer build.
+// @Tags System
+// @Produce json
+// @Success 200 {object} model.APIResponse{data=model.MigrationResult}
+// @Failure 409 {object} model.APIResponse
+// @Failure 500 {object} model.APIResponse
+// @Router /system/migrations/up [post]
+func (h *Handler) ApplyMigrations(c *gin.Context) {
+	result, err := h.manager.ApplyMigrations()
+	if err != nil {
+		respond(c, migrationErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to apply migrations",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Migrations applied successfully",
+		Data:    result,
+	})
+}
+
+// RollbackMigrations 回滚最近执行的迁移
+// @Summary Roll Back Database Migrations
+// @Description Roll back the most recent applied migrations (default 1), newest first. Stops at a migration that cannot be rolled back. The running server may use the dropped tables, so deploy the matching older build afterwards.
+// @Tags System
+// @Accept json
+// @Produce json
+// @Param request body model.MigrationRollbackRequest false "Rollback Request"
+// @Success 200 {object} model.APIResponse{data=model.MigrationResult}
+// @Failure 400 {object} model.APIResponse
+// @Failure 409 {object} model.APIResponse
+// @Failure 500 {object} model.APIResponse
+// @Router /system/migrations/rollback [post]
+func (h *Handler) RollbackMigrations(c *gin.Context) {
+	var req model.MigrationRollbackRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	result, err := h.manager.RollbackMigrations(req.Steps)
+	if err != nil {
+		respond(c, migrationErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to roll back migrations",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Migrations rolled back successfully",
+		Data:    result,
+	})
+}
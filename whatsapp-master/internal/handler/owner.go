@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+)
+
+// SetAccountOwner 设置账号负责人
+// @Summary Set Account Owner
+// @Description Attach owner metadata (operator, team, email, escalation webhook) to an account, replacing the previous owner. Accounts can be listed by owner with GET /accounts?owner=; incidents for the account are routed to the owner's channel, falling back to ALERT_WEBHOOK_URL.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param request body model.AccountOwner true "Account Owner"
+// @Success 200 {object} model.APIResponse{data=model.Account}
+// @Router /accounts/{id}/owner [put]
+func (h *Handler) SetAccountOwner(c *gin.Context) {
+	var req model.AccountOwner
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if _, err := h.manager.GetAccount(c.Param("id")); err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	account, err := h.manager.SetAccountOwner(c.Param("id"), &req)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to set account owner",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Account owner updated successfully",
+		Data:    account,
+	})
+}
+
+// AssignAccounts 批量分配账号
+// @Summary Assign Accounts
+// @Description Assign accounts to an operator and/or team. Only the provided fields change; an empty string unassigns. Fails without changes if any account does not exist.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param request body model.AssignAccountsRequest true "Assign Request"
+// @Success 200 {object} model.APIResponse{data=[]model.Account}
+// @Failure 404 {object} model.APIResponse
+// @Router /accounts/assign [post]
+func (h *Handler) AssignAccounts(c *gin.Context) {
+	var req model.AssignAccountsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	for _, accountID := range req.AccountIDs {
+		if !h.authorizeAccount(c, accountID) {
+			return
+		}
+		if _, err := h.manager.GetAccount(accountID); err != nil {
+			respond(c, http.StatusNotFound, model.APIResponse{
+				Success: false,
+				Message: "Account not found",
+				Error:   err.Error(),
+			})
+			return
+		}
+	}
+
+	accounts, err := h.manager.AssignAccounts(&req)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to assign accounts",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Accounts assigned successfully",
+		Data:    accounts,
+	})
+}
+
+// ListOwners 列出负责人
+// @Summary List Owners
+// @Description List the operators and teams that accounts are assigned to, with the number of accounts each. Tenant API keys only see their own accounts.
+// @Tags Account
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending, e.g. -accounts"
+// @Param filter[kind] query string false "operator or team"
+// @Success 200 {object} model.APIResponse{data=[]model.OwnerSummary}
+// @Router /owners [get]
+func (h *Handler) ListOwners(c *gin.Context) {
+	tenantID, _ := middleware.TenantID(c)
+	respondList(c, h.manager.ListOwners(tenantID), "Owners retrieved successfully")
+}
@@ -0,0 +1,47 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// createErrorStatus 创建Worker失败时的状态码：端口池耗尽返回503，租户超限返回403，其余错误使用fallback
+func createErrorStatus(err error, fallback int) int {
+	if errors.Is(err, service.ErrPortPoolExhausted) {
+		return http.StatusServiceUnavailable
+	}
+	if errors.Is(err, service.ErrNoBackup) || errors.Is(err, service.ErrHardwareProfileNotFound) {
+		return http.StatusNotFound
+	}
+	return tenantErrorStatus(err, fallback)
+}
+
+// startupDiagnostics Worker未能就绪时返回采集的诊断信息，其他错误返回nil
+func startupDiagnostics(err error) interface{} {
+	var startupErr *service.WorkerStartupError
+	if errors.As(err, &startupErr) {
+		return startupErr.Diagnostics
+	}
+	return nil
+}
+
+// GetPortStatus 获取端口池分配状态
+// @Summary Get Port Pool Status
+// @Description List worker ports allocated to accounts and ports skipped because another process holds them. Use probe=true to probe every unallocated port now.
+// @Tags System
+// @Produce json
+// @Param probe query bool false "Probe all unallocated ports before reporting"
+// @Success 200 {object} model.APIResponse{data=model.PortPoolStatus}
+// @Router /system/ports [get]
+func (h *Handler) GetPortStatus(c *gin.Context) {
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Port status retrieved successfully",
+		Data:    h.manager.GetPortStatus(c.Query("probe") == "true"),
+	})
+}
@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/validation"
+)
+
+// SendTyping 显示输入状态
+// @Summary Send Typing State
+// @Description Show "typing…" or "recording audio…" to a contact, or clear it with state=paused. With duration_ms the worker clears the state when it runs out; otherwise WhatsApp clears it when a message is sent or after about 25 seconds.
+// @Tags Message
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param request body model.TypingRequest true "Typing Request"
+// @Success 200 {object} model.APIResponse{data=model.ChatState}
+// @Failure 400 {object} model.APIResponse
+// @Failure 404 {object} model.APIResponse
+// @Router /accounts/{id}/typing [post]
+func (h *Handler) SendTyping(c *gin.Context) {
+	var req model.TypingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	contact, err := validation.NormalizeContact(req.Contact, h.manager.DefaultCountryCode())
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid phone number",
+			Error:   err.Error(),
+		})
+		return
+	}
+	req.Contact = contact
+
+	if !h.accountExists(c, c.Param("id")) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	state, err := h.manager.SendTyping(ctx, c.Param("id"), &req)
+	if err != nil {
+		respond(c, workerErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to send typing state",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Typing state sent successfully",
+		Data:    state,
+	})
+}
+
+// GetPresence 查询联系人在线状态
+// @Summary Get Contact Presence
+// @Description Subscribe to a contact's presence through the worker and return whether they are online, their current chat state and when they were last seen. online is null and last_seen is empty when the contact hides them or WhatsApp has not reported them yet; ask again after a few seconds.
+// @Tags Contact
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param contact path string true "Contact phone number or chat ID"
+// @Success 200 {object} model.APIResponse{data=model.ContactPresence}
+// @Failure 400 {object} model.APIResponse
+// @Failure 404 {object} model.APIResponse
+// @Router /accounts/{id}/presence/{contact} [get]
+func (h *Handler) GetPresence(c *gin.Context) {
+	contact, ok := h.chatContact(c)
+	if !ok {
+		return
+	}
+	if !h.accountExists(c, c.Param("id")) {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	presence, err := h.manager.GetPresence(ctx, c.Param("id"), contact)
+	if err != nil {
+		respond(c, workerErrorStatus(err), model.APIResponse{
+			Success: false,
+			Message: "Failed to get contact presence",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Contact presence retrieved successfully",
+		Data:    presence,
+	})
+}
+
+// SetAccountTypingSimulation 开启或关闭模拟输入
+// @Summary Set Account Typing Simulation
+// @Description Turn human-like typing on or off for an account. When it is on, every text message from /send-message, bulk sends and campaigns first shows "typing…" to the contact and waits for a delay based on the message length (TYPING_CHARS_PER_SECOND, between TYPING_MIN_DELAY_MS and TYPING_MAX_DELAY_MS with TYPING_JITTER_PERCENT random variation). Retries and media messages are sent without the delay.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param request body model.SetTypingSimulationRequest true "Typing simulation setting"
+// @Success 200 {object} model.APIResponse{data=model.Account}
+// @Router /accounts/{id}/typing-simulation [put]
+func (h *Handler) SetAccountTypingSimulation(c *gin.Context) {
+	var req model.SetTypingSimulationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if !h.accountExists(c, c.Param("id")) {
+		return
+	}
+
+	account, err := h.manager.SetAccountTypingSimulation(c.Param("id"), *req.Enabled)
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to update account typing simulation",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Account typing simulation updated successfully",
+		Data:    account,
+	})
+}
@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// GetMessagePreview 获取消息的HTML预览
+// @Summary Get Message Preview
+// @Description Get a sanitized, render-ready HTML preview of a stored message. WhatsApp formatting is converted to HTML and media comes with signed, expiring URLs.
+// @Tags Message
+// @Produce json
+// @Param id path string true "Message ID"
+// @Success 200 {object} model.APIResponse{data=model.MessagePreview}
+// @Router /messages/{id}/preview [get]
+func (h *Handler) GetMessagePreview(c *gin.Context) {
+	preview, err := h.manager.GetMessagePreview(c.Param("id"))
+	if err == nil && !h.ownsAccounts(c, []string{preview.AccountID}) {
+		err = fmt.Errorf("message %s not found", preview.ID)
+	}
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Message not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Message preview generated successfully",
+		Data:    preview,
+	})
+}
+
+// ServeSignedMedia 通过签名链接返回媒体或缩略图
+func (h *Handler) ServeSignedMedia(c *gin.Context) {
+	variant := c.DefaultQuery("variant", service.MediaVariantOriginal)
+	data, contentType, err := h.manager.OpenSignedMedia(c.Param("id"), variant, c.Query("expires"), c.Query("sig"))
+	if err != nil {
+		c.String(http.StatusForbidden, err.Error())
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Data(http.StatusOK, contentType, data)
+}
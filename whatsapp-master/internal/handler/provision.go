@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+)
+
+// CreateAccountsBulk 批量创建账号
+// @Summary Bulk Create Accounts
+// @Description Create one account per phone number (the account ID is the normalized number) with shared settings. proxies is a pool assigned round-robin in phone order. Numbers that already have an account or appear twice are skipped. Workers are started in a create_accounts job with at most WORKER_PROVISION_CONCURRENCY (or the lower concurrency) at a time; the request is rejected with 503 when the port pool has fewer free ports than accounts to create. The response lists every phone as pending or skipped; poll GET /jobs/{id} for the per-account results.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Param request body model.BulkCreateAccountsRequest true "Bulk Create Request"
+// @Success 202 {object} model.APIResponse{data=model.BulkCreateAccountsResult}
+// @Failure 400 {object} model.APIResponse
+// @Failure 503 {object} model.APIResponse
+// @Router /accounts/bulk [post]
+func (h *Handler) CreateAccountsBulk(c *gin.Context) {
+	var req model.BulkCreateAccountsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if !h.normalizePhones(c, &req) {
+		return
+	}
+
+	if tenantID, scoped := middleware.TenantID(c); scoped {
+		req.TenantID = tenantID
+		// 主机调度和挂载宿主机目录只对管理员开放
+		req.HostID = ""
+		if req.Runtime != nil {
+			req.Runtime.Volumes = nil
+		}
+	}
+
+	result, err := h.manager.CreateAccountsBulk(c.Request.Context(), &req)
+	if err != nil {
+		respond(c, createErrorStatus(err, http.StatusBadRequest), model.APIResponse{
+			Success: false,
+			Message: "Failed to create accounts",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusAccepted, model.APIResponse{
+		Success: true,
+		Message: "Account creation started",
+		Data:    result,
+	})
+}
@@ -0,0 +1,364 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/logging"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+	"whatsapp-aggregator/internal/tracing"
+)
+
+// maxStatusBody 状态接口用于同步账号状态时最多读取的响应大小
+const maxStatusBody = 1 << 20
+
+// workerRoundTripper 通过Manager的共享连接池转发到单个Worker：幂等请求在连接失败或Worker暂时不可用时按退避重试，
+// 最终结果记入账号的熔断器
+type workerRoundTripper struct {
+	manager   *service.Manager
+	accountID string
+	attempts  int
+	backoff   time.Duration
+}
+
+func (t *workerRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	idempotent := req.Method == http.MethodGet || req.Method == http.MethodHead
+	for attempt := 0; ; attempt++ {
+		resp, err := t.roundTripOnce(req, attempt)
+		failure := workerFailure(req, resp, err)
+		if failure == nil || !idempotent || attempt >= t.attempts || req.Context().Err() != nil {
+			// 调用方主动断开不代表Worker故障
+			if !errors.Is(req.Context().Err(), context.Canceled) {
+				t.manager.RecordWorkerResult(t.accountID, failure)
+			}
+			return resp, err
+		}
+
+		if resp != nil {
+			io.Copy(io.Discard, io.LimitReader(resp.Body, maxStatusBody))
+			resp.Body.Close()
+		}
+		wait := t.backoff << attempt
+		logging.FromContext(req.Context()).Debug("Retrying worker request", "account_id", t.accountID, "path", req.URL.Path, "attempt", attempt+1, "wait", wait, "error", failure)
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// roundTripOnce 转发一次并记录客户端Span，同时向Worker传播 traceparent；
+// Span在收到响应头时结束，不包含流式响应体的传输时间
+func (t *workerRoundTripper) roundTripOnce(req *http.Request, attempt int) (*http.Response, error) {
+	ctx, span := tracing.Start(req.Context(), "worker "+req.Method+" "+req.URL.Path, tracing.KindClient,
+		tracing.String("account.id", t.accountID),
+		tracing.String("http.request.method", req.Method),
+		tracing.String("server.address", req.URL.Host),
+		tracing.String("url.path", req.URL.Path),
+	)
+	if span == nil {
+		return t.manager.WorkerTransport().RoundTrip(req)
+	}
+	defer span.End()
+	if attempt > 0 {
+		span.SetAttributes(tracing.Int("http.request.resend_count", attempt))
+	}
+
+	out := req.Clone(ctx)
+	tracing.Inject(ctx, out.Header)
+	resp, err := t.manager.WorkerTransport().RoundTrip(out)
+	if err != nil {
+		span.RecordError(err)
+		return resp, err
+	}
+	span.SetAttributes(tracing.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.SetError(http.StatusText(resp.StatusCode))
+	}
+	return resp, nil
+}
+
+// workerFailure 请求是否因Worker故障失败：连接错误、超时或网关类状态码
+func workerFailure(req *http.Request, resp *http.Response, err error) error {
+	if err != nil {
+		return err
+	}
+	switch resp.StatusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return fmt.Errorf("worker returned status %d for %s", resp.StatusCode, req.URL.Path)
+	}
+	return nil
+}
+
+// proxyToWorker 以流式方式转发请求到Worker
+func (h *Handler) proxyToWorker(c *gin.Context, accountID string, workerPath string) {
+	account, err := h.manager.GetAccount(accountID)
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.manager.AllowWorkerRequest(accountID); err != nil {
+		var openErr *service.CircuitOpenError
+		if errors.As(err, &openErr) {
+			c.Header("Retry-After", strconv.Itoa(int(math.Ceil(openErr.RetryAfter.Seconds()))))
+		}
+		respond(c, http.StatusServiceUnavailable, model.APIResponse{
+			Success: false,
+			Message: "Worker temporarily unavailable",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	if err := h.manager.ApplyWorkerFault(c.Request.Context(), accountID); err != nil {
+		h.manager.RecordWorkerResult(accountID, err)
+		respond(c, http.StatusBadGateway, model.APIResponse{
+			Success: false,
+			Message: "Failed to connect to worker",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	// 流式接口长期占用连接，不计入并发上限
+	if !h.streamingRoute(workerPath) {
+		release, err := h.manager.AcquireWorkerSlot(c.Request.Context(), accountID)
+		if err != nil {
+			var busyErr *service.WorkerBusyError
+			if !errors.As(err, &busyErr) {
+				// 调用方在排队时断开
+				return
+			}
+			c.Header("Retry-After", "1")
+			respond(c, http.StatusTooManyRequests, model.APIResponse{
+				Success: false,
+				Message: "Too many concurrent requests to worker",
+				Error:   err.Error(),
+			})
+			return
+		}
+		defer release()
+	}
+
+	target, err := url.Parse(account.ServiceURL)
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to create proxy request",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	call := newWorkerCall(c.Request, accountID, workerPath)
+	started := time.Now()
+
+	cfg := h.manager.GetConfig().Proxy
+	proxy := &httputil.ReverseProxy{
+		Transport: &workerRoundTripper{
+			manager:   h.manager,
+			accountID: accountID,
+			attempts:  cfg.RetryAttempts,
+			backoff:   time.Duration(cfg.RetryBackoffMs) * time.Millisecond,
+		},
+		FlushInterval: -1, // 立即刷新，保证二维码、日志等流式响应实时到达
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.Out.URL.Path = target.Path + workerPath
+			pr.Out.URL.RawPath = ""
+			pr.Out.URL.RawQuery = ""
+			// 只有GET请求附带Query参数
+			if pr.In.Method == http.MethodGet {
+				pr.Out.URL.RawQuery = pr.In.URL.RawQuery
+			}
+			pr.SetXForwarded()
+			logging.InjectRequestID(pr.Out)
+
+			// 强制禁用缓存
+			pr.Out.Header.Del("If-None-Match")
+			pr.Out.Header.Del("If-Modified-Since")
+			pr.Out.Header.Set("Cache-Control", "no-cache")
+			pr.Out.Header.Set("Pragma", "no-cache")
+		},
+		ModifyResponse: func(resp *http.Response) error {
+			call.Status = resp.StatusCode
+			if resp.StatusCode >= http.StatusBadRequest {
+				return workerResponseError(resp)
+			}
+			// 如果请求是获取状态，尝试更新本地状态
+			if workerPath != "/api/status" && workerPath != "/api/login/status" {
+				return nil
+			}
+			body, err := io.ReadAll(io.LimitReader(resp.Body, maxStatusBody))
+			resp.Body.Close()
+			if err != nil {
+				return err
+			}
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			h.syncStatusFromResponse(accountID, account.Status, body)
+			return nil
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			call.Error = err.Error()
+			var workerErr *service.WorkerError
+			if errors.As(err, &workerErr) && workerErr.StatusCode != 0 {
+				respond(c, workerErrorStatus(err), model.APIResponse{
+					Success: false,
+					Message: "Worker request failed",
+					Error:   err.Error(),
+				})
+				return
+			}
+			logging.FromContext(r.Context()).Warn("Proxy to worker failed", "account_id", accountID, "path", workerPath, "error", err)
+			respond(c, http.StatusBadGateway, model.APIResponse{
+				Success: false,
+				Message: "Failed to connect to worker",
+				Error:   err.Error(),
+			})
+		},
+	}
+
+	req := c.Request
+	if timeout := h.proxyTimeout(workerPath); timeout > 0 {
+		ctx, cancel := context.WithTimeout(req.Context(), timeout)
+		defer cancel()
+		req = req.WithContext(ctx)
+	}
+
+	proxy.ServeHTTP(c.Writer, req)
+	call.LatencyMs = time.Since(started).Milliseconds()
+	h.manager.RecordWorkerCall(c.Request.Context(), call)
+}
+
+// newWorkerCall 为代理请求创建调用记录，请求体前 WorkerCallHashLimit 字节参与哈希，读出的部分接回请求体继续转发
+func newWorkerCall(req *http.Request, accountID, workerPath string) *model.WorkerCall {
+	call := &model.WorkerCall{
+		AccountID:   accountID,
+		Source:      service.WorkerCallSourceProxy,
+		Method:      req.Method,
+		Path:        workerPath,
+		PayloadSize: max(req.ContentLength, 0),
+		CreatedAt:   time.Now(),
+	}
+	if req.Body == nil || req.Body == http.NoBody {
+		return call
+	}
+	prefix, _ := io.ReadAll(io.LimitReader(req.Body, service.WorkerCallHashLimit))
+	call.PayloadHash = service.WorkerCallHash(prefix)
+	if req.ContentLength < 0 {
+		call.PayloadSize = int64(len(prefix))
+	}
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(prefix), req.Body), req.Body}
+	return call
+}
+
+// workerResponseError 将Worker的失败响应转换为WorkerError，由代理的ErrorHandler输出统一格式的错误
+func workerResponseError(resp *http.Response) error {
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxStatusBody))
+	resp.Body.Close()
+
+	var payload struct {
+		Error   string `json:"error"`
+		Message string `json:"message"`
+	}
+	json.Unmarshal(body, &payload)
+	message := payload.Error
+	if message == "" {
+		message = payload.Message
+	}
+	return &service.WorkerError{StatusCode: resp.StatusCode, Message: message}
+}
+
+// workerErrorStatus Worker调用失败时返回给调用方的状态码：Worker拒绝的请求原样返回4xx，其余视为网关错误
+func workerErrorStatus(err error) int {
+	var workerErr *service.WorkerError
+	if errors.As(err, &workerErr) && workerErr.StatusCode >= 400 && workerErr.StatusCode < 500 {
+		return workerErr.StatusCode
+	}
+	return http.StatusBadGateway
+}
+
+// bindWorkerRequest 校验请求体并替换为按模型重新编码的JSON，Worker只会收到模型中定义的字段，号码已规范化
+func (h *Handler) bindWorkerRequest(c *gin.Context, req interface{}) bool {
+	if err := c.ShouldBindJSON(req); err != nil {
+		respondBindError(c, err)
+		return false
+	}
+	if normalizer, ok := req.(model.PhoneNormalizer); ok && !h.normalizePhones(c, normalizer) {
+		return false
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to create proxy request",
+			Error:   err.Error(),
+		})
+		return false
+	}
+	c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	c.Request.ContentLength = int64(len(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	return true
+}
+
+// proxyTimeout 返回Worker路径对应的超时时间，0表示不限制
+func (h *Handler) proxyTimeout(workerPath string) time.Duration {
+	cfg := h.manager.GetConfig().Proxy
+	seconds := cfg.Timeout
+	if routeSeconds, ok := cfg.RouteTimeouts[workerPath]; ok {
+		seconds = routeSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// streamingRoute Worker路径是否为流式接口（PROXY_ROUTE_TIMEOUTS 中超时为0）
+func (h *Handler) streamingRoute(workerPath string) bool {
+	seconds, ok := h.manager.GetConfig().Proxy.RouteTimeouts[workerPath]
+	return ok && seconds == 0
+}
+
+// syncStatusFromResponse 根据Worker状态接口的响应更新账号状态
+func (h *Handler) syncStatusFromResponse(accountID, currentStatus string, body []byte) {
+	var result map[string]interface{}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return
+	}
+
+	// 检查直接的 status 字段，或 data.status
+	var statusStr string
+	if s, ok := result["status"].(string); ok {
+		statusStr = s
+	} else if data, ok := result["data"].(map[string]interface{}); ok {
+		if s, ok := data["status"].(string); ok {
+			statusStr = s
+		}
+	}
+
+	if statusStr != "" && statusStr != currentStatus {
+		h.manager.UpdateAccountStatusSafe(accountID, statusStr, service.StatusCause{Source: service.StatusSourceWorker, Reason: "reported by worker status response"})
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+)
+
+// 二维码图片边长范围（像素）
+const (
+	defaultQRCodeSize = 256
+	minQRCodeSize     = 128
+	maxQRCodeSize     = 1024
+)
+
+// QRLogin 扫码登录
+// @Summary QR Login
+// @Description Assign a worker (the given account, an idle worker or a new account) and start a QR code login. The master polls the worker until the account is logged in; fetch the QR image from qr_code_url.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param request body model.QRLoginRequest false "QR Login Request"
+// @Success 200 {object} model.APIResponse{data=model.QRLoginResult}
+// @Router /qr-login [post]
+func (h *Handler) QRLogin(c *gin.Context) {
+	var req model.QRLoginRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	if tenantID, scoped := middleware.TenantID(c); scoped {
+		req.TenantID = tenantID
+		if req.AccountID != "" {
+			if _, err := h.manager.GetAccount(req.AccountID); err == nil && !h.authorizeAccount(c, req.AccountID) {
+				return
+			}
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(c.Request.Context()), 5*time.Minute)
+	defer cancel()
+
+	result, err := h.manager.StartQRLogin(ctx, &req)
+	if err != nil {
+		respond(c, createErrorStatus(err, http.StatusInternalServerError), model.APIResponse{
+			Success: false,
+			Message: "Failed to start QR login",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "QR login initiated successfully",
+		Data:    result,
+	})
+}
+
+// GetQRCodePNG 获取二维码图片
+// @Summary Get QR Code Image
+// @Description Fetch the current login QR code from the worker and render it as a PNG.
+// @Tags Auth
+// @Produce png
+// @Param id path string true "Account ID"
+// @Param size query int false "Image size in pixels (128-1024, default 256)"
+// @Success 200 {file} binary
+// @Router /accounts/{id}/qr-code.png [get]
+func (h *Handler) GetQRCodePNG(c *gin.Context) {
+	accountID := c.Param("id")
+	if _, err := h.manager.GetAccount(accountID); err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	size := defaultQRCodeSize
+	if raw := c.Query("size"); raw != "" {
+		if n, err := strconv.Atoi(raw); err == nil {
+			size = min(max(n, minQRCodeSize), maxQRCodeSize)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	png, err := h.manager.QRCodePNG(ctx, accountID, size)
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "QR code not available",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "image/png", png)
+}
@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+const (
+	// maxListLimit 单页最大条数
+	maxListLimit = 1000
+)
+
+// parseListQuery 解析列表查询参数，offset 可代替 cursor
+// limit=20&cursor=xxx&sort=-created_at,status&filter[status]=logged_in,running
+func parseListQuery(c *gin.Context) (*model.ListQuery, error) {
+	q := &model.ListQuery{
+		Filters: c.QueryMap("filter"),
+	}
+
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 0 {
+			return nil, fmt.Errorf("invalid limit: %s", raw)
+		}
+		if limit > maxListLimit {
+			limit = maxListLimit
+		}
+		q.Limit = limit
+	}
+
+	if raw := c.Query("cursor"); raw != "" {
+		offset, err := decodeCursor(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor: %s", raw)
+		}
+		q.Offset = offset
+	}
+	if raw := c.Query("offset"); raw != "" && c.Query("cursor") == "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return nil, fmt.Errorf("invalid offset: %s", raw)
+		}
+		q.Offset = offset
+	}
+
+	if raw := c.Query("sort"); raw != "" {
+		for _, field := range strings.Split(raw, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				q.Sort = append(q.Sort, field)
+			}
+		}
+	}
+
+	return q, nil
+}
+
+// parseTimeRange 解析 since/until 时间范围参数（RFC3339），未传的一端为nil
+func parseTimeRange(c *gin.Context) (since, until *time.Time, err error) {
+	for param, target := range map[string]**time.Time{"since": &since, "until": &until} {
+		raw := c.Query(param)
+		if raw == "" {
+			continue
+		}
+		t, parseErr := time.Parse(time.RFC3339, raw)
+		if parseErr != nil {
+			return nil, nil, fmt.Errorf("invalid %s: %v", param, parseErr)
+		}
+		*target = &t
+	}
+	return since, until, nil
+}
+
+// encodeCursor 将偏移量编码为不透明游标
+func encodeCursor(offset int) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.Itoa(offset)))
+}
+
+// decodeCursor 解码游标
+func decodeCursor(cursor string) (int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := strconv.Atoi(string(b))
+	if err != nil || offset < 0 {
+		return 0, fmt.Errorf("invalid cursor offset")
+	}
+	return offset, nil
+}
+
+// applyListQuery 对任意切片按JSON字段名进行过滤、排序和分页
+func applyListQuery(items interface{}, q *model.ListQuery) ([]map[string]interface{}, *model.ListMeta, error) {
+	raw, err := json.Marshal(items)
+	if err != nil {
+		return nil, nil, err
+	}
+	var rows []map[string]interface{}
+	if err := json.Unmarshal(raw, &rows); err != nil {
+		return nil, nil, err
+	}
+
+	// 过滤：同一字段多个值以逗号分隔，表示或关系
+	filtered := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		if matchFilters(row, q.Filters) {
+			filtered = append(filtered, row)
+		}
+	}
+
+	// 排序：字段前缀 "-" 表示降序
+	if len(q.Sort) > 0 {
+		sort.SliceStable(filtered, func(i, j int) bool {
+			for _, field := range q.Sort {
+				desc := strings.HasPrefix(field, "-")
+				key := strings.TrimPrefix(field, "-")
+				cmp := compareValues(filtered[i][key], filtered[j][key])
+				if cmp == 0 {
+					continue
+				}
+				if desc {
+					return cmp > 0
+				}
+				return cmp < 0
+			}
+			return false
+		})
+	}
+
+	start := q.Offset
+	if start > len(filtered) {
+		start = len(filtered)
+	}
+	end := len(filtered)
+	if q.Limit > 0 && start+q.Limit < end {
+		end = start + q.Limit
+	}
+
+	return filtered[start:end], buildListMeta(q, int64(len(filtered)), end-start), nil
+}
+
+// buildListMeta 根据总数和当前页条数生成分页元数据
+func buildListMeta(q *model.ListQuery, total int64, pageSize int) *model.ListMeta {
+	meta := &model.ListMeta{
+		Total: int(total),
+		Limit: q.Limit,
+	}
+	if next := q.Offset + pageSize; q.Limit > 0 && int64(next) < total {
+		meta.NextCursor = encodeCursor(next)
+	}
+	return meta
+}
+
+// matchFilters 判断一行数据是否满足所有过滤条件
+func matchFilters(row map[string]interface{}, filters map[string]string) bool {
+	for key, expected := range filters {
+		// 数组字段（如 tags）只要任一元素匹配即可
+		values := []interface{}{row[key]}
+		if list, ok := row[key].([]interface{}); ok {
+			values = list
+		}
+
+		matched := false
+		for _, candidate := range strings.Split(expected, ",") {
+			candidate = strings.TrimSpace(candidate)
+			for _, value := range values {
+				actual := ""
+				if value != nil {
+					actual = fmt.Sprint(value)
+				}
+				if strings.EqualFold(actual, candidate) {
+					matched = true
+					break
+				}
+			}
+			if matched {
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// compareValues 比较两个JSON值，数字按数值比较，其他按字符串比较
+func compareValues(a, b interface{}) int {
+	if a == nil && b == nil {
+		return 0
+	}
+	if a == nil {
+		return -1
+	}
+	if b == nil {
+		return 1
+	}
+	if fa, ok := a.(float64); ok {
+		if fb, ok := b.(float64); ok {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			}
+			return 0
+		}
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// respondPage 返回已分页的数据
+func respondPage(c *gin.Context, items interface{}, meta *model.ListMeta, message string) {
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: message,
+		Data:    items,
+		Meta:    meta,
+	})
+}
+
+// respondList 应用通用列表参数并返回统一响应
+func respondList(c *gin.Context, items interface{}, message string) {
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	page, meta, err := applyListQuery(items, q)
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to build list response",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, page, meta, message)
+}
@@ -0,0 +1,224 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// GetSendQuota 获取账号发送限流与配额
+// @Summary Get Send Quota
+// @Description Get the per-minute rate limit and daily quota usage of an account. A zero limit means unlimited.
+// @Tags Message
+// @Produce json
+// @Param id path string true "Account ID"
+// @Success 200 {object} model.APIResponse{data=model.SendQuota}
+// @Router /accounts/{id}/quota [get]
+func (h *Handler) GetSendQuota(c *gin.Context) {
+	quota, err := h.manager.GetSendQuota(c.Param("id"))
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	h.setQuotaHeaders(c, c.Param("id"))
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Send quota retrieved successfully",
+		Data:    quota,
+		Warning: quota.Warning,
+	})
+}
+
+// setQuotaHeaders 写入账号的限流与配额响应头，返回剩余额度偏低时的警告
+func (h *Handler) setQuotaHeaders(c *gin.Context, accountID string) string {
+	quota, err := h.manager.GetSendQuota(accountID)
+	if err != nil {
+		return ""
+	}
+
+	if quota.RateLimit > 0 {
+		c.Header("X-RateLimit-Limit", strconv.Itoa(quota.RateLimit))
+		c.Header("X-RateLimit-Remaining", strconv.Itoa(quota.RateRemaining))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(quota.RateReset.Unix(), 10))
+	}
+	if quota.DailyQuota > 0 {
+		c.Header("X-Quota-Limit", strconv.Itoa(quota.DailyQuota))
+		c.Header("X-Quota-Remaining", strconv.Itoa(quota.QuotaRemaining))
+		c.Header("X-Quota-Reset", strconv.FormatInt(quota.QuotaReset.Unix(), 10))
+	}
+	if quota.WarmupLimit > 0 {
+		c.Header("X-Warmup-Limit", strconv.Itoa(quota.WarmupLimit))
+		c.Header("X-Warmup-Remaining", strconv.Itoa(quota.WarmupRemaining))
+	}
+	return quota.Warning
+}
+
+// allowSend 检查API Key的负责人限制以及发送接口的全局和账号令牌桶，超限时返回429和Retry-After
+func (h *Handler) allowSend(c *gin.Context, message string, accountIDs ...string) bool {
+	if !h.authorizeSend(c, message, accountIDs...) {
+		return false
+	}
+	err := h.manager.AllowSend(accountIDs...)
+	if err == nil {
+		return true
+	}
+
+	accountID := ""
+	if len(accountIDs) == 1 {
+		accountID = accountIDs[0]
+	}
+	h.respondSendError(c, accountID, message, err)
+	return false
+}
+
+// previewMessage 模拟发送：检查API Key负责人限制和令牌桶但不扣减，返回渲染后将要发送的内容
+func (h *Handler) previewMessage(c *gin.Context, req *model.MessageRequest, recipientWarning string) {
+	if !h.authorizeSend(c, "Failed to send message", req.AccountID) {
+		return
+	}
+	if err := h.manager.CheckSendLimit(req.AccountID); err != nil {
+		h.respondSendError(c, req.AccountID, "Failed to send message", err)
+		return
+	}
+	preview, err := h.manager.PreviewMessage(req)
+	if err != nil {
+		h.respondSendError(c, req.AccountID, "Failed to send message", err)
+		return
+	}
+
+	warnings := []string{recipientWarning}
+	if preview.AccountStatus != "logged_in" {
+		warnings = append(warnings, fmt.Sprintf("account status is %s, the worker would reject the message until it is logged in", preview.AccountStatus))
+	}
+	if len(preview.UnresolvedVariables) > 0 {
+		warnings = append(warnings, "placeholders without variables would be sent as is: "+strings.Join(preview.UnresolvedVariables, ", "))
+	}
+	if len(preview.ContentViolations) > 0 {
+		details := make([]string, len(preview.ContentViolations))
+		for i, v := range preview.ContentViolations {
+			details[i] = fmt.Sprintf("%s (%s, %s)", v.Detail, v.Type, v.Action)
+		}
+		warnings = append(warnings, "content policies would reject or hold the message: "+strings.Join(details, ", "))
+	}
+	warnings = append(warnings, h.setQuotaHeaders(c, req.AccountID))
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Message validated (dry run)",
+		Data:    preview,
+		Warning: joinWarnings(warnings...),
+	})
+}
+
+// SetAccountSendLimit 设置账号发送接口限流
+// @Summary Set Account Send Limit
+// @Description Override the token bucket limit for send requests of an account (requests per minute and burst). 0 falls back to SEND_LIMIT_ACCOUNT_PER_MINUTE / SEND_LIMIT_ACCOUNT_BURST. The setting is persisted.
+// @Tags Message
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param request body model.AccountSendLimit true "Account Send Limit"
+// @Success 200 {object} model.APIResponse{data=model.Account}
+// @Router /accounts/{id}/send-limit [put]
+func (h *Handler) SetAccountSendLimit(c *gin.Context) {
+	var req model.AccountSendLimit
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	account, err := h.manager.SetAccountSendLimit(c.Param("id"), &req)
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Failed to set account send limit",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Account send limit updated successfully",
+		Data:    account,
+	})
+}
+
+// respondSendError 发送失败时的响应，超过限流或配额时返回429，账号停用时返回409，
+// 收件人在抑制名单中或违反内容策略时拒绝返回422，放入审核队列返回202
+func (h *Handler) respondSendError(c *gin.Context, accountID, message string, err error) {
+	warning := h.setQuotaHeaders(c, accountID)
+
+	var quotaErr *service.QuotaExceededError
+	if errors.As(err, &quotaErr) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(quotaErr.RetryAfter.Seconds()))))
+		respond(c, http.StatusTooManyRequests, model.APIResponse{
+			Success: false,
+			Message: message,
+			Error:   err.Error(),
+			Warning: warning,
+		})
+		return
+	}
+	var disabledErr *service.AccountDisabledError
+	if errors.As(err, &disabledErr) {
+		respond(c, http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: message,
+			Error:   err.Error(),
+			Warning: warning,
+		})
+		return
+	}
+	var suppressedErr *service.SuppressedContactError
+	if errors.As(err, &suppressedErr) {
+		respond(c, http.StatusUnprocessableEntity, model.APIResponse{
+			Success: false,
+			Message: "Contact is on the suppression list",
+			Error:   err.Error(),
+			Warning: warning,
+		})
+		return
+	}
+	var policyErr *service.ContentPolicyError
+	if errors.As(err, &policyErr) {
+		if policyErr.Held != nil {
+			respond(c, http.StatusAccepted, model.APIResponse{
+				Success: false,
+				Message: "Message held for review",
+				Data:    policyErr.Held,
+				Error:   err.Error(),
+				Warning: warning,
+			})
+			return
+		}
+		respond(c, http.StatusUnprocessableEntity, model.APIResponse{
+			Success: false,
+			Message: "Message rejected by content policy",
+			Data:    policyErr.Violations,
+			Error:   err.Error(),
+			Warning: warning,
+		})
+		return
+	}
+
+	respond(c, http.StatusBadGateway, model.APIResponse{
+		Success: false,
+		Message: message,
+		Error:   err.Error(),
+		Warning: warning,
+	})
+}
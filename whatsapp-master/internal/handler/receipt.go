@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// GetMessageStatus 获取消息投递状态
+// @Summary Get Message Status
+// @Description Delivery state of a message by the ID the master returned as message_id when sending: sent, delivered, read or failed, with the time each state was reached. Receipts come from worker callbacks and periodic polling (RECEIPT_POLL_INTERVAL_SECONDS).
+// @Tags Message
+// @Produce json
+// @Param id path string true "Message ID"
+// @Success 200 {object} model.APIResponse{data=model.MessageStatus}
+// @Router /messages/{id}/status [get]
+func (h *Handler) GetMessageStatus(c *gin.Context) {
+	status, err := h.manager.GetMessageStatus(c.Param("id"))
+	if err == nil && !h.ownsAccounts(c, []string{status.AccountID}) {
+		err = fmt.Errorf("message %s not found", status.ID)
+	}
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Message not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Message status retrieved successfully",
+		Data:    status,
+	})
+}
+
+// ReportReceipts Worker上报消息回执
+// @Summary Report Message Receipts
+// @Description Called by workers when WhatsApp acknowledges an outgoing message. Authenticated with the X-Worker-Token header. Status only moves forward (sent, delivered, read) and each change emits a message.delivered or message.read event.
+// @Tags Message
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param X-Worker-Token header string true "Worker token"
+// @Param request body model.ReportReceiptsRequest true "Receipts"
+// @Success 200 {object} model.APIResponse{data=model.ReportReceiptsResult}
+// @Router /worker/accounts/{id}/receipts [post]
+func (h *Handler) ReportReceipts(c *gin.Context) {
+	var req model.ReportReceiptsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	updated, err := h.manager.ApplyReceipts(c.Param("id"), req.Receipts)
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to apply message receipts",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Message receipts applied",
+		Data:    model.ReportReceiptsResult{Updated: updated},
+	})
+}
@@ -0,0 +1,59 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+)
+
+// RetryFailedMessages 批量重试失败消息
+// @Summary Retry Failed Messages
+// @Description Re-queue failed outbound text messages, optionally scoped by account and time window. Retries are throttled and run in the background.
+// @Tags Message
+// @Accept json
+// @Produce json
+// @Param request body model.RetryMessagesRequest false "Retry Request"
+// @Success 202 {object} model.APIResponse{data=model.RetryMessagesResult}
+// @Router /messages/retry [post]
+func (h *Handler) RetryFailedMessages(c *gin.Context) {
+	var req model.RetryMessagesRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	if _, scoped := middleware.TenantID(c); scoped {
+		if req.AccountID == "" {
+			respond(c, http.StatusBadRequest, model.APIResponse{
+				Success: false,
+				Message: "Failed to retry messages",
+				Error:   "account_id is required when using a tenant API key",
+			})
+			return
+		}
+		if !h.authorizeAccount(c, req.AccountID) || !h.authorizeSend(c, "Failed to retry messages", req.AccountID) {
+			return
+		}
+	}
+
+	result, err := h.manager.RetryFailedMessages(&req)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to retry messages",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusAccepted, model.APIResponse{
+		Success: true,
+		Message: "Failed messages queued for retry",
+		Data:    result,
+	})
+}
@@ -0,0 +1,99 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+)
+
+// GetSessionHealth 获取账号会话健康度
+// @Summary Get Session Health
+// @Description Get session age, drop history and whether a relogin is recommended before the session expires
+// @Tags Auth
+// @Produce json
+// @Param id path string true "Account ID"
+// @Success 200 {object} model.APIResponse{data=model.SessionHealth}
+// @Router /accounts/{id}/session [get]
+func (h *Handler) GetSessionHealth(c *gin.Context) {
+	health, err := h.manager.GetSessionHealth(c.Param("id"))
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Session health retrieved successfully",
+		Data:    health,
+	})
+}
+
+// ListSessionHealth 列出所有账号的会话健康度
+// @Summary List Session Health
+// @Description List session health for all accounts. Use filter[relogin_recommended]=true to find sessions about to expire.
+// @Tags Auth
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending"
+// @Param filter[relogin_recommended] query string false "true or false"
+// @Success 200 {object} model.APIResponse{data=[]model.SessionHealth}
+// @Router /sessions [get]
+func (h *Handler) ListSessionHealth(c *gin.Context) {
+	sessions := h.manager.ListSessionHealth()
+	if _, scoped := middleware.TenantID(c); scoped {
+		owned := make([]*model.SessionHealth, 0, len(sessions))
+		for _, session := range sessions {
+			if h.ownsAccounts(c, []string{session.AccountID}) {
+				owned = append(owned, session)
+			}
+		}
+		sessions = owned
+	}
+	respondList(c, sessions, "Session health retrieved successfully")
+}
+
+// SetAccountKeepAlive 开启或关闭账号的会话保活
+// @Summary Set Account Keep-Alive
+// @Description Opt an account in or out of the session keep-alive loop, which checks the worker's login status every SESSION_KEEPALIVE_INTERVAL_SECONDS and refreshes the session when it is disconnected or close to expiry. Opted-out accounts are also skipped by the quiet-hours refresher.
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param request body model.SetKeepAliveRequest true "Keep-alive setting"
+// @Success 200 {object} model.APIResponse{data=model.Account}
+// @Router /accounts/{id}/keepalive [put]
+func (h *Handler) SetAccountKeepAlive(c *gin.Context) {
+	var req model.SetKeepAliveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if !h.accountExists(c, c.Param("id")) {
+		return
+	}
+
+	account, err := h.manager.SetAccountKeepAlive(c.Param("id"), *req.Enabled)
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to update account keep-alive",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Account keep-alive updated successfully",
+		Data:    account,
+	})
+}
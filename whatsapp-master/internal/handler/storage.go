@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+	"whatsapp-aggregator/internal/storage"
+)
+
+// UploadObject 上传文件到对象存储
+// @Summary Upload Object
+// @Description Store a file in the object storage configured by STORAGE_DESTINATION (local directory, S3 or MinIO) and return its key and a signed download URL valid for STORAGE_URL_TTL_MINUTES. Reference the key as storage_key in POST /send-media or POST /accounts/{id}/contacts/import. Tenant uploads are stored under tenants/{tenant}/ and only that tenant can use them.
+// @Tags Storage
+// @Accept multipart/form-data
+// @Produce json
+// @Param file formData file true "File to store, at most STORAGE_MAX_UPLOAD_MB"
+// @Success 201 {object} model.APIResponse{data=model.StoredObject}
+// @Failure 400 {object} model.APIResponse
+// @Failure 503 {object} model.APIResponse
+// @Router /storage/objects [post]
+func (h *Handler) UploadObject(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.manager.MaxObjectUploadSize()+(1<<20))
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid multipart form",
+			Error:   err.Error(),
+		})
+		return
+	}
+	if fileHeader.Size > h.manager.MaxObjectUploadSize() {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid multipart form",
+			Error:   fmt.Sprintf("file exceeds max size of %d bytes", h.manager.MaxObjectUploadSize()),
+		})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid multipart form",
+			Error:   err.Error(),
+		})
+		return
+	}
+	data, err := io.ReadAll(file)
+	file.Close()
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid multipart form",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	tenantID, _ := middleware.TenantID(c)
+	object, err := h.manager.UploadObject(c.Request.Context(), tenantID, fileHeader.Filename, data)
+	if err != nil {
+		respond(c, http.StatusServiceUnavailable, model.APIResponse{
+			Success: false,
+			Message: "Failed to store object",
+			Error:   err.Error(),
+		})
+		return
+	}
+	respond(c, http.StatusCreated, model.APIResponse{
+		Success: true,
+		Message: "Object stored successfully",
+		Data:    object,
+	})
+}
+
+// GetObjectURL 为对象生成新的签名下载地址
+// @Summary Get Object Download URL
+// @Description Generate a fresh signed download URL for an object, e.g. after the URL returned by the upload or export expired. S3 destinations return a presigned S3 URL (at most 7 days); local directories return a link to GET /storage/{key} on this server built from STORAGE_BASE_URL. The object's existence is not checked.
+// @Tags Storage
+// @Produce json
+// @Param key query string true "Object key"
+// @Success 200 {object} model.APIResponse{data=model.StoredObject}
+// @Failure 400 {object} model.APIResponse
+// @Failure 404 {object} model.APIResponse
+// @Router /storage/objects/url [get]
+func (h *Handler) GetObjectURL(c *gin.Context) {
+	key, ok := h.objectKey(c)
+	if !ok {
+		return
+	}
+	object, err := h.manager.SignObjectURL(key)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to sign object URL",
+			Error:   err.Error(),
+		})
+		return
+	}
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Object URL generated successfully",
+		Data:    object,
+	})
+}
+
+// DeleteObject 删除对象存储中的文件
+// @Summary Delete Object
+// @Description Delete an object from the object storage. Deleting a missing object succeeds. Signed URLs issued for it stop working.
+// @Tags Storage
+// @Produce json
+// @Param key query string true "Object key"
+// @Success 200 {object} model.APIResponse
+// @Failure 400 {object} model.APIResponse
+// @Failure 404 {object} model.APIResponse
+// @Router /storage/objects [delete]
+func (h *Handler) DeleteObject(c *gin.Context) {
+	key, ok := h.objectKey(c)
+	if !ok {
+		return
+	}
+	if err := h.manager.DeleteObject(c.Request.Context(), key); err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to delete object",
+			Error:   err.Error(),
+		})
+		return
+	}
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Object deleted successfully",
+	})
+}
+
+// objectKey 读取 key 参数，租户只能访问自己前缀下的对象，其余按不存在处理
+func (h *Handler) objectKey(c *gin.Context) (string, bool) {
+	key := strings.TrimSpace(c.Query("key"))
+	if err := storage.ValidKey(key); err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid object key",
+			Error:   err.Error(),
+		})
+		return "", false
+	}
+	tenantID, _ := middleware.TenantID(c)
+	if !service.ObjectInTenant(key, tenantID) {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Object not found",
+			Error:   fmt.Sprintf("storage key %s not found", key),
+		})
+		return "", false
+	}
+	return key, true
+}
+
+// ServeSignedObject 通过签名链接下载本地目录中的对象
+func (h *Handler) ServeSignedObject(c *gin.Context) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	data, contentType, err := h.manager.OpenSignedObject(c.Request.Context(), key, c.Query("expires"), c.Query("sig"))
+	if err != nil {
+		c.String(http.StatusForbidden, err.Error())
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": service.ObjectFilename(key)}))
+	c.Data(http.StatusOK, contentType, data)
+}
@@ -0,0 +1,155 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// ListSuppressions 抑制名单
+// @Summary List Suppressions
+// @Description Global suppression list: contacts that replied an OPT_OUT_KEYWORDS keyword (source keyword) or were added by hand (source manual), newest first. No account sends to a listed contact.
+// @Tags Suppression
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending (default -created_at)"
+// @Param filter[contact] query string false "Contact"
+// @Param filter[source] query string false "keyword or manual"
+// @Param filter[account_id] query string false "Account that received the opt-out"
+// @Param filter[campaign] query string false "Campaign the opt-out is attributed to"
+// @Success 200 {object} model.APIResponse{data=[]model.OptOut}
+// @Router /suppressions [get]
+func (h *Handler) ListSuppressions(c *gin.Context) {
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	entries, total, err := h.manager.ListSuppressions(q)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list suppressions",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, entries, buildListMeta(q, total, len(entries)), "Suppressions retrieved successfully")
+}
+
+// AddSuppressions 手动加入抑制名单
+// @Summary Add Suppressions
+// @Description Add contacts to the suppression list by hand. Contacts already on the list are skipped; the response lists the new entries.
+// @Tags Suppression
+// @Accept json
+// @Produce json
+// @Param request body model.AddSuppressionRequest true "Contacts"
+// @Success 200 {object} model.APIResponse{data=[]model.OptOut}
+// @Failure 400 {object} model.APIResponse
+// @Router /suppressions [post]
+func (h *Handler) AddSuppressions(c *gin.Context) {
+	var req model.AddSuppressionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+	if !h.normalizePhones(c, &req) {
+		return
+	}
+
+	added, err := h.manager.AddSuppressions(req.Contacts, req.Reason)
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to add suppressions",
+			Data:    added,
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Suppressions added successfully",
+		Data:    added,
+	})
+}
+
+// GetSuppression 查询号码是否在抑制名单中
+// @Summary Get Suppression
+// @Description Whether a contact is suppressed, with all of its opt-out entries
+// @Tags Suppression
+// @Produce json
+// @Param contact path string true "Contact number"
+// @Success 200 {object} model.APIResponse{data=model.SuppressionStatus}
+// @Failure 400 {object} model.APIResponse
+// @Router /suppressions/{contact} [get]
+func (h *Handler) GetSuppression(c *gin.Context) {
+	contact, ok := h.chatContact(c)
+	if !ok {
+		return
+	}
+
+	status, err := h.manager.GetSuppression(contact)
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get suppression",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Suppression retrieved successfully",
+		Data:    status,
+	})
+}
+
+// RemoveSuppression 从抑制名单中删除号码
+// @Summary Remove Suppression
+// @Description Remove every opt-out entry of a contact, keyword and manual alike, so accounts can message it again. A later opt-out keyword adds it back.
+// @Tags Suppression
+// @Produce json
+// @Param contact path string true "Contact number"
+// @Success 200 {object} model.APIResponse
+// @Failure 404 {object} model.APIResponse
+// @Router /suppressions/{contact} [delete]
+func (h *Handler) RemoveSuppression(c *gin.Context) {
+	contact, ok := h.chatContact(c)
+	if !ok {
+		return
+	}
+
+	removed, err := h.manager.RemoveSuppression(contact)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrSuppressionNotFound) {
+			status = http.StatusNotFound
+		}
+		respond(c, status, model.APIResponse{
+			Success: false,
+			Message: "Failed to remove suppression",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Suppression removed successfully",
+		Data:    map[string]interface{}{"contact": contact, "removed": removed},
+	})
+}
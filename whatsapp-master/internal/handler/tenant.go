@@ -0,0 +1,365 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// tenantRoutes 租户API Key可访问的接口前缀，其余接口仅管理员可用
+var tenantRoutes = []string{
+	"/api/v1/accounts",
+	"/api/v1/owners",
+	"/api/v1/phone-login",
+	"/api/v1/qr-login",
+	"/api/v1/send-message",
+	"/api/v1/send-media",
+	"/api/v1/send-bulk",
+	"/api/v1/messages",
+	"/api/v1/inbox",
+	"/api/v1/webhooks",
+	"/api/v1/contacts",
+	"/api/v1/sessions",
+	"/api/v1/storage",
+	"/api/v1/health",
+	"/api/v1/graphql",
+	"/api/v1/jobs/:id",
+	"/api/v1/tenants/:id",
+}
+
+// tenantScope 将租户调用方限制在租户接口和本租户的账号内
+func (h *Handler) tenantScope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID, scoped := middleware.TenantID(c)
+		if !scoped {
+			c.Next()
+			return
+		}
+
+		path := c.FullPath()
+		allowed := false
+		for _, prefix := range tenantRoutes {
+			if path == prefix || strings.HasPrefix(path, prefix+"/") {
+				allowed = true
+				break
+			}
+		}
+		if !allowed || strings.HasPrefix(path, "/api/v1/tenants/:id/") {
+			abort(c, http.StatusForbidden, model.APIResponse{
+				Success: false,
+				Message: "Endpoint requires admin token",
+			})
+			return
+		}
+
+		switch {
+		case strings.HasPrefix(path, "/api/v1/accounts/:id"):
+			if !h.manager.AccountInTenant(c.Param("id"), tenantID) {
+				abort(c, http.StatusNotFound, model.APIResponse{
+					Success: false,
+					Message: "Account not found",
+				})
+				return
+			}
+		case strings.HasPrefix(path, "/api/v1/jobs/:id"):
+			// 租户只能查询自己创建的任务
+			if path != "/api/v1/jobs/:id" || c.Request.Method != http.MethodGet || !h.manager.JobInTenant(c.Param("id"), tenantID) {
+				abort(c, http.StatusNotFound, model.APIResponse{
+					Success: false,
+					Message: "Job not found",
+				})
+				return
+			}
+		case path == "/api/v1/tenants/:id":
+			if c.Param("id") != tenantID || c.Request.Method != http.MethodGet {
+				abort(c, http.StatusForbidden, model.APIResponse{
+					Success: false,
+					Message: "Endpoint requires admin token",
+				})
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+// authorizeAccount 校验请求体中的账号属于调用方租户，不属于时按账号不存在处理
+func (h *Handler) authorizeAccount(c *gin.Context, accountID string) bool {
+	tenantID, scoped := middleware.TenantID(c)
+	if !scoped || h.manager.AccountInTenant(accountID, tenantID) {
+		return true
+	}
+	respond(c, http.StatusNotFound, model.APIResponse{
+		Success: false,
+		Message: "Account not found",
+		Error:   "account " + accountID + " not found",
+	})
+	return false
+}
+
+// ownsAccounts 调用方是否可以访问所有给定账号，管理员始终可以
+func (h *Handler) ownsAccounts(c *gin.Context, accountIDs []string) bool {
+	tenantID, scoped := middleware.TenantID(c)
+	if !scoped {
+		return true
+	}
+	for _, accountID := range accountIDs {
+		if !h.manager.AccountInTenant(accountID, tenantID) {
+			return false
+		}
+	}
+	return true
+}
+
+// sendOwner 开启 API_KEY_OWNER_ENFORCEMENT 时返回调用方API Key绑定的负责人，不受限制时返回空字符串
+func (h *Handler) sendOwner(c *gin.Context) string {
+	if !h.manager.GetConfig().Tenant.EnforceKeyOwner {
+		return ""
+	}
+	return middleware.APIKeyOwner(c)
+}
+
+// authorizeSend 绑定了负责人的API Key只能通过分配给该负责人的账号发送
+func (h *Handler) authorizeSend(c *gin.Context, message string, accountIDs ...string) bool {
+	owner := h.sendOwner(c)
+	if owner == "" {
+		return true
+	}
+	for _, accountID := range accountIDs {
+		if !h.manager.AccountOwnedBy(accountID, owner) {
+			respond(c, http.StatusForbidden, model.APIResponse{
+				Success: false,
+				Message: message,
+				Error:   fmt.Sprintf("account %s is not assigned to %s", accountID, owner),
+			})
+			return false
+		}
+	}
+	return true
+}
+
+// tenantErrorStatus 租户超限返回403，其余错误使用fallback
+func tenantErrorStatus(err error, fallback int) int {
+	var limitErr *service.TenantLimitError
+	if errors.As(err, &limitErr) {
+		return http.StatusForbidden
+	}
+	return fallback
+}
+
+// CreateTenant 创建租户
+// @Summary Create Tenant
+// @Description Create a tenant with optional limits on worker count and messages per day (0 = unlimited). Requires the admin token.
+// @Tags Tenant
+// @Accept json
+// @Produce json
+// @Param request body model.CreateTenantRequest true "Create Tenant Request"
+// @Success 200 {object} model.APIResponse{data=model.Tenant}
+// @Router /tenants [post]
+func (h *Handler) CreateTenant(c *gin.Context) {
+	var req model.CreateTenantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	tenant, err := h.manager.CreateTenant(&req)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to create tenant",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Tenant created successfully",
+		Data:    tenant,
+	})
+}
+
+// ListTenants 列出租户
+// @Summary List Tenants
+// @Description List tenants with their limits and current usage. Requires the admin token.
+// @Tags Tenant
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending"
+// @Success 200 {object} model.APIResponse{data=[]model.Tenant}
+// @Router /tenants [get]
+func (h *Handler) ListTenants(c *gin.Context) {
+	q, err := parseListQuery(c)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Invalid list query",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	tenants, total, err := h.manager.ListTenants(q)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to list tenants",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondPage(c, tenants, buildListMeta(q, total, len(tenants)), "Tenants retrieved successfully")
+}
+
+// GetTenant 获取租户
+// @Summary Get Tenant
+// @Description Get a tenant with its limits and current usage. Tenant API keys may only read their own tenant.
+// @Tags Tenant
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} model.APIResponse{data=model.Tenant}
+// @Router /tenants/{id} [get]
+func (h *Handler) GetTenant(c *gin.Context) {
+	tenant, err := h.manager.GetTenant(c.Param("id"))
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Tenant not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Tenant retrieved successfully",
+		Data:    tenant,
+	})
+}
+
+// UpdateTenant 修改租户
+// @Summary Update Tenant
+// @Description Update a tenant's name or limits. Requires the admin token.
+// @Tags Tenant
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param request body model.UpdateTenantRequest true "Update Tenant Request"
+// @Success 200 {object} model.APIResponse{data=model.Tenant}
+// @Router /tenants/{id} [put]
+func (h *Handler) UpdateTenant(c *gin.Context) {
+	var req model.UpdateTenantRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	tenant, err := h.manager.UpdateTenant(c.Param("id"), &req)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to update tenant",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Tenant updated successfully",
+		Data:    tenant,
+	})
+}
+
+// CreateTenantAPIKey 创建租户API Key
+// @Summary Create Tenant API Key
+// @Description Generate an API key scoped to the tenant, optionally bound to an operator or team (owner) whose accounts it may send through when API_KEY_OWNER_ENFORCEMENT is on. The key is only returned once; send it as X-API-Key. Requires the admin token.
+// @Tags Tenant
+// @Accept json
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param request body model.CreateAPIKeyRequest false "Create API Key Request"
+// @Success 200 {object} model.APIResponse{data=model.CreatedAPIKey}
+// @Router /tenants/{id}/keys [post]
+func (h *Handler) CreateTenantAPIKey(c *gin.Context) {
+	var req model.CreateAPIKeyRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	key, err := h.manager.CreateTenantAPIKey(c.Param("id"), &req)
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Failed to create API key",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "API key created successfully",
+		Data:    key,
+	})
+}
+
+// ListTenantAPIKeys 列出租户API Key
+// @Summary List Tenant API Keys
+// @Description List a tenant's API keys without the secret part. Requires the admin token.
+// @Tags Tenant
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Success 200 {object} model.APIResponse{data=[]model.TenantAPIKey}
+// @Router /tenants/{id}/keys [get]
+func (h *Handler) ListTenantAPIKeys(c *gin.Context) {
+	keys, err := h.manager.ListTenantAPIKeys(c.Param("id"))
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Failed to list API keys",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respondList(c, keys, "API keys retrieved successfully")
+}
+
+// RevokeTenantAPIKey 吊销租户API Key
+// @Summary Revoke Tenant API Key
+// @Description Revoke an API key; requests using it are rejected immediately. Requires the admin token.
+// @Tags Tenant
+// @Produce json
+// @Param id path string true "Tenant ID"
+// @Param key_id path string true "API Key ID"
+// @Success 200 {object} model.APIResponse
+// @Router /tenants/{id}/keys/{key_id} [delete]
+func (h *Handler) RevokeTenantAPIKey(c *gin.Context) {
+	if err := h.manager.RevokeTenantAPIKey(c.Param("id"), c.Param("key_id")); err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Failed to revoke API key",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "API key revoked successfully",
+	})
+}
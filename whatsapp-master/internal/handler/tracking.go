@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// TrackRedirect 记录链接点击并跳转到原始地址
+func (h *Handler) TrackRedirect(c *gin.Context) {
+	url, err := h.manager.RecordClick(c.Param("code"), c.ClientIP(), c.Request.UserAgent())
+	if err != nil {
+		c.String(http.StatusNotFound, "link not found")
+		return
+	}
+	c.Redirect(http.StatusFound, url)
+}
+
+// GetClickStats 获取链接点击统计
+// @Summary Get Link Click Stats
+// @Description Get click-through statistics of tracked links, grouped by campaign and account
+// @Tags Tracking
+// @Produce json
+// @Param campaign query string false "Campaign"
+// @Param account_id query string false "Account ID"
+// @Success 200 {object} model.APIResponse{data=model.ClickStatsReport}
+// @Router /tracking/stats [get]
+func (h *Handler) GetClickStats(c *gin.Context) {
+	report, err := h.manager.GetClickStats(c.Query("campaign"), c.Query("account_id"))
+	if err != nil {
+		respond(c, http.StatusInternalServerError, model.APIResponse{
+			Success: false,
+			Message: "Failed to get click stats",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Click stats retrieved successfully",
+		Data:    report,
+	})
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// UpgradeWorkers 滚动升级Worker镜像
+// @Summary Upgrade Workers
+// @Description Pull a new worker image, then recreate running workers in batches. After each batch the workers' status endpoints are checked; if any worker fails, the upgrade stops and all upgraded workers are rolled back to the previous image. Runs as an upgrade_workers job; poll /jobs/{id} for the stage and result.
+// @Tags System
+// @Accept json
+// @Produce json
+// @Param request body model.UpgradeWorkersRequest true "Upgrade Request"
+// @Success 202 {object} model.APIResponse{data=model.Job}
+// @Router /system/upgrade-workers [post]
+func (h *Handler) UpgradeWorkers(c *gin.Context) {
+	var req model.UpgradeWorkersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	job, err := h.manager.UpgradeWorkers(&req)
+	if err != nil {
+		respond(c, http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: "Failed to start worker upgrade",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusAccepted, model.APIResponse{
+		Success: true,
+		Message: "Worker upgrade started",
+		Data:    job,
+	})
+}
+
+// PullImage 在主机上预拉取Worker镜像
+// @Summary Pre-pull Worker Image
+// @Description Pull a worker image onto hosts ahead of time so spawning and upgrades don't wait on the download. Defaults to the current worker image and all reachable hosts. With prune, older versions of the image repository and dangling worker images are removed after a successful pull (images in use and the most recent JANITOR_KEEP_IMAGES versions are kept). Runs as a pull_image job; poll /jobs/{id} for per-layer progress in detail.
+// @Tags System
+// @Accept json
+// @Produce json
+// @Param request body model.PullImageRequest false "Pull Request"
+// @Success 202 {object} model.APIResponse{data=model.Job}
+// @Router /system/pull-image [post]
+func (h *Handler) PullImage(c *gin.Context) {
+	var req model.PullImageRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			respondBindError(c, err)
+			return
+		}
+	}
+
+	job, err := h.manager.PullImage(&req)
+	if err != nil {
+		respond(c, http.StatusConflict, model.APIResponse{
+			Success: false,
+			Message: "Failed to start image pull",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusAccepted, model.APIResponse{
+		Success: true,
+		Message: "Image pull started",
+		Data:    job,
+	})
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// GetWarmupStatus 获取账号预热状态
+// @Summary Get Account Warmup
+// @Description Current warmup step of an account: its profile, age in days, today's allowance and usage, and when the next step starts. A daily_limit of 0 means unlimited.
+// @Tags Message
+// @Produce json
+// @Param id path string true "Account ID"
+// @Success 200 {object} model.APIResponse{data=model.WarmupStatus}
+// @Router /accounts/{id}/warmup [get]
+func (h *Handler) GetWarmupStatus(c *gin.Context) {
+	status, err := h.manager.GetWarmupStatus(c.Param("id"))
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Warmup status retrieved successfully",
+		Data:    status,
+	})
+}
+
+// SetAccountWarmup 修改账号预热方案
+// @Summary Set Account Warmup
+// @Description Assign a warmup profile to an account ("" for WARMUP_DEFAULT_PROFILE, "off" to exempt it) and/or restart its warmup from today. The setting is persisted.
+// @Tags Message
+// @Accept json
+// @Produce json
+// @Param id path string true "Account ID"
+// @Param request body model.SetWarmupRequest true "Warmup Settings"
+// @Success 200 {object} model.APIResponse{data=model.WarmupStatus}
+// @Router /accounts/{id}/warmup [put]
+func (h *Handler) SetAccountWarmup(c *gin.Context) {
+	var req model.SetWarmupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	if _, err := h.manager.GetAccount(c.Param("id")); err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	status, err := h.manager.SetAccountWarmup(c.Param("id"), &req)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to update account warmup",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Account warmup updated successfully",
+		Data:    status,
+	})
+}
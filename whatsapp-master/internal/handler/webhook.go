@@ -0,0 +1,131 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/middleware"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/service"
+)
+
+// CreateWebhook 注册事件Webhook
+// @Summary Create Webhook
+// @Description Register a URL that receives events (e.g. message.delivered, message.read) as JSON POSTs signed with HMAC-SHA256 in the X-Fleet-Signature header. The secret is only returned on creation. Webhooks created with a tenant API key only receive events of that tenant's accounts. filter limits delivery to account_ids, message directions (inbound, outbound), contact patterns (* wildcard) and body keywords; include_media adds signed media URLs to message events; retry overrides max_attempts, backoff_ms and max_backoff_ms.
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param request body model.CreateWebhookRequest true "Webhook"
+// @Success 200 {object} model.APIResponse{data=model.CreatedWebhook}
+// @Router /webhooks [post]
+func (h *Handler) CreateWebhook(c *gin.Context) {
+	var req model.CreateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	tenantID, _ := middleware.TenantID(c)
+	webhook, err := h.manager.CreateWebhook(&req, tenantID)
+	if err != nil {
+		respond(c, http.StatusBadRequest, model.APIResponse{
+			Success: false,
+			Message: "Failed to create webhook",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Webhook created successfully",
+		Data:    webhook,
+	})
+}
+
+// UpdateWebhook 修改Webhook
+// @Summary Update Webhook
+// @Description Change a webhook's events, filter, include_media or retry policy. Fields that are not sent stay unchanged; filter and retry replace the whole object. Tenants can only update their own webhooks.
+// @Tags Webhook
+// @Accept json
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Param request body model.UpdateWebhookRequest true "Webhook changes"
+// @Success 200 {object} model.APIResponse{data=model.Webhook}
+// @Failure 400 {object} model.APIResponse
+// @Failure 404 {object} model.APIResponse
+// @Router /webhooks/{id} [patch]
+func (h *Handler) UpdateWebhook(c *gin.Context) {
+	var req model.UpdateWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	tenantID, scoped := middleware.TenantID(c)
+	webhook, err := h.manager.UpdateWebhook(c.Param("id"), &req, tenantID, scoped)
+	if err != nil {
+		status := http.StatusBadRequest
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			status = http.StatusNotFound
+		}
+		respond(c, status, model.APIResponse{
+			Success: false,
+			Message: "Failed to update webhook",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Webhook updated successfully",
+		Data:    webhook,
+	})
+}
+
+// ListWebhooks 列出Webhook
+// @Summary List Webhooks
+// @Description List registered webhooks; tenants only see their own
+// @Tags Webhook
+// @Produce json
+// @Param limit query int false "Page size"
+// @Param cursor query string false "Cursor from previous page"
+// @Param sort query string false "Sort fields, prefix with - for descending"
+// @Success 200 {object} model.APIResponse{data=[]model.Webhook}
+// @Router /webhooks [get]
+func (h *Handler) ListWebhooks(c *gin.Context) {
+	tenantID, scoped := middleware.TenantID(c)
+	respondList(c, h.manager.ListWebhooks(tenantID, scoped), "Webhooks retrieved successfully")
+}
+
+// DeleteWebhook 删除Webhook
+// @Summary Delete Webhook
+// @Description Stop delivering events to a webhook
+// @Tags Webhook
+// @Produce json
+// @Param id path string true "Webhook ID"
+// @Success 200 {object} model.APIResponse
+// @Router /webhooks/{id} [delete]
+func (h *Handler) DeleteWebhook(c *gin.Context) {
+	tenantID, scoped := middleware.TenantID(c)
+	if err := h.manager.DeleteWebhook(c.Param("id"), tenantID, scoped); err != nil {
+		status := http.StatusInternalServerError
+		if errors.Is(err, service.ErrWebhookNotFound) {
+			status = http.StatusNotFound
+		}
+		respond(c, status, model.APIResponse{
+			Success: false,
+			Message: "Failed to delete webhook",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Webhook deleted successfully",
+	})
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// ReceiveWorkerEvents Worker推送状态、二维码、入站消息、注销和等待会话解密事件（POST /internal/v1/workers/:id/events），
+// 使用 X-Worker-Token 鉴权；不在 /api/v1 下，不出现在Swagger文档中
+func (h *Handler) ReceiveWorkerEvents(c *gin.Context) {
+	var req model.WorkerEventsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		respondBindError(c, err)
+		return
+	}
+
+	result, err := h.manager.HandleWorkerEvents(c.Param("id"), req.Events)
+	if err != nil {
+		respond(c, http.StatusNotFound, model.APIResponse{
+			Success: false,
+			Message: "Account not found",
+			Error:   err.Error(),
+		})
+		return
+	}
+
+	respond(c, http.StatusOK, model.APIResponse{
+		Success: true,
+		Message: "Worker events accepted",
+		Data:    result,
+	})
+}
@@ -0,0 +1,62 @@
+// Package hoststat 读取主机的CPU、内存和负载，供调度器和 fleet-agent 使用
+package hoststat
+
+import (
+	"bufio"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// Collect 采集当前主机资源，无法读取 /proc 的系统上内存和负载为0
+func Collect() model.AgentStatus {
+	status := model.AgentStatus{CPUs: runtime.NumCPU()}
+	status.MemoryTotal, status.MemoryAvailable = memory()
+	status.Load1 = load1()
+	return status
+}
+
+// memory 从 /proc/meminfo 读取总内存和可用内存（字节）
+func memory() (total, available int64) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, 0
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		kb, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			total = kb * 1024
+		case "MemAvailable:":
+			available = kb * 1024
+		}
+	}
+	return total, available
+}
+
+// load1 从 /proc/loadavg 读取1分钟平均负载
+func load1() float64 {
+	raw, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return 0
+	}
+	fields := strings.Fields(string(raw))
+	if len(fields) == 0 {
+		return 0
+	}
+	load, _ := strconv.ParseFloat(fields[0], 64)
+	return load
+}
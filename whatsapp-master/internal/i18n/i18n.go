@@ -0,0 +1,117 @@
+// Package i18n API消息和控制台界面的多语言支持
+//
+// 消息目录以英文原文为键，未翻译的消息按原文返回；
+// 响应中的code由英文原文生成，不随语言变化，供程序判断。
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// DefaultLocale 默认语言，目录键即为该语言的原文
+const DefaultLocale = "en"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalogs 语言 -> 英文原文 -> 译文
+var catalogs = loadCatalogs()
+
+// Locales 支持的语言
+func Locales() []string {
+	locales := []string{DefaultLocale}
+	for _, locale := range []string{"zh", "es"} {
+		if _, ok := catalogs[locale]; ok {
+			locales = append(locales, locale)
+		}
+	}
+	return locales
+}
+
+// loadCatalogs 加载内嵌的消息目录
+func loadCatalogs() map[string]map[string]string {
+	entries, err := localeFiles.ReadDir("locales")
+	if err != nil {
+		panic(fmt.Sprintf("i18n: failed to read locales: %v", err))
+	}
+
+	catalogs := make(map[string]map[string]string)
+	for _, entry := range entries {
+		data, err := localeFiles.ReadFile("locales/" + entry.Name())
+		if err != nil {
+			panic(fmt.Sprintf("i18n: failed to read %s: %v", entry.Name(), err))
+		}
+		var catalog map[string]string
+		if err := json.Unmarshal(data, &catalog); err != nil {
+			panic(fmt.Sprintf("i18n: invalid catalog %s: %v", entry.Name(), err))
+		}
+		catalogs[strings.TrimSuffix(entry.Name(), ".json")] = catalog
+	}
+	return catalogs
+}
+
+// T 翻译消息，没有对应译文时返回原文
+func T(locale, message string) string {
+	if translated, ok := catalogs[locale][message]; ok && translated != "" {
+		return translated
+	}
+	return message
+}
+
+// Code 由英文原文生成稳定的消息代码，如 "Account not found" -> "account_not_found"
+func Code(message string) string {
+	var b strings.Builder
+	underscore := false
+	for _, r := range message {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if underscore && b.Len() > 0 {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			underscore = false
+		} else {
+			underscore = true
+		}
+	}
+	return b.String()
+}
+
+// Negotiate 根据 Accept-Language 选择支持的语言，按q值优先，忽略地区子标签
+func Negotiate(acceptLanguage string) string {
+	best, bestQ := DefaultLocale, 0.0
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+
+		locale := Match(tag)
+		if locale != "" && q > bestQ {
+			best, bestQ = locale, q
+		}
+	}
+	return best
+}
+
+// Match 将语言标签匹配到支持的语言，不支持时返回空
+func Match(tag string) string {
+	primary, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+	primary, _, _ = strings.Cut(primary, "_")
+	if primary == DefaultLocale {
+		return DefaultLocale
+	}
+	if _, ok := catalogs[primary]; ok {
+		return primary
+	}
+	return ""
+}
@@ -0,0 +1,154 @@
+package logging
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+
+	"whatsapp-aggregator/internal/tracing"
+)
+
+// RequestIDHeader 请求ID使用的HTTP头，Master转发给Worker时保持不变
+const RequestIDHeader = "X-Request-ID"
+
+// level 全局日志级别，运行时可通过配置接口修改
+var level = new(slog.LevelVar)
+
+type requestIDKey struct{}
+
+// Setup 初始化全局结构化日志，format 为 text 或 json
+func Setup(levelName, format string) error {
+	if err := SetLevel(levelName); err != nil {
+		return err
+	}
+
+	opts := &slog.HandlerOptions{Level: level}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "", "text":
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	case "json":
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	default:
+		return fmt.Errorf("invalid log format %q: must be text or json", format)
+	}
+	slog.SetDefault(slog.New(handler))
+	return nil
+}
+
+// SetLevel 修改全局日志级别（debug, info, warn, error）
+func SetLevel(name string) error {
+	l, err := parseLevel(name)
+	if err != nil {
+		return err
+	}
+	level.Set(l)
+	return nil
+}
+
+// ValidateLevel 检查日志级别名称是否有效，不修改当前级别
+func ValidateLevel(name string) error {
+	_, err := parseLevel(name)
+	return err
+}
+
+func parseLevel(name string) (slog.Level, error) {
+	var l slog.Level
+	if err := l.UnmarshalText([]byte(name)); err != nil {
+		return l, fmt.Errorf("invalid log level %q: must be debug, info, warn or error", name)
+	}
+	return l, nil
+}
+
+// Level 当前全局日志级别
+func Level() string {
+	return strings.ToLower(level.Level().String())
+}
+
+// WithRequestID 将请求ID写入上下文
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestID 获取上下文中的请求ID，不存在时返回空字符串
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// FromContext 返回带有请求ID和链路ID字段的Logger
+func FromContext(ctx context.Context) *slog.Logger {
+	logger := slog.Default()
+	if id := RequestID(ctx); id != "" {
+		logger = logger.With("request_id", id)
+	}
+	if id := tracing.TraceID(ctx); id != "" {
+		logger = logger.With("trace_id", id)
+	}
+	return logger
+}
+
+// InjectRequestID 将请求上下文中的请求ID写入发往Worker的请求头
+func InjectRequestID(req *http.Request) {
+	if id := RequestID(req.Context()); id != "" {
+		req.Header.Set(RequestIDHeader, id)
+	}
+}
+
+// sensitiveKeys 需要脱敏的字段名片段
+var sensitiveKeys = []string{"password", "passwd", "pwd", "token", "secret", "api_key", "apikey", "authorization", "credential"}
+
+// Redact 递归替换JSON对象中敏感字段的值
+func Redact(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		redacted := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			if sensitiveKey(key) {
+				redacted[key] = "[REDACTED]"
+				continue
+			}
+			redacted[key] = Redact(item)
+		}
+		return redacted
+	case []interface{}:
+		redacted := make([]interface{}, len(v))
+		for i, item := range v {
+			redacted[i] = Redact(item)
+		}
+		return redacted
+	default:
+		return value
+	}
+}
+
+// RedactJSON 脱敏JSON文本后返回，无法解析时原样返回
+func RedactJSON(body []byte) []byte {
+	var value interface{}
+	if err := json.Unmarshal(body, &value); err != nil {
+		return body
+	}
+	redacted, err := json.Marshal(Redact(value))
+	if err != nil {
+		return body
+	}
+	return redacted
+}
+
+// sensitiveKey 字段名是否包含敏感词
+func sensitiveKey(key string) bool {
+	key = strings.ToLower(key)
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(key, sensitive) {
+			return true
+		}
+	}
+	return false
+}
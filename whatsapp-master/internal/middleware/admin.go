@@ -0,0 +1,29 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminTokenHeader 管理接口鉴权请求头
+const AdminTokenHeader = "X-Admin-Token"
+
+// RequireAdminToken 校验管理接口的 X-Admin-Token，未配置token时拒绝所有请求
+func RequireAdminToken(token string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token == "" {
+			abortWithMessage(c, http.StatusForbidden, "Admin token is not configured")
+			return
+		}
+
+		provided := c.GetHeader(AdminTokenHeader)
+		if subtle.ConstantTimeCompare([]byte(provided), []byte(token)) != 1 {
+			abortWithMessage(c, http.StatusUnauthorized, "Invalid admin token")
+			return
+		}
+		c.Set(adminContextKey, true)
+		c.Next()
+	}
+}
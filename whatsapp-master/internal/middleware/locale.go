@@ -0,0 +1,41 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/i18n"
+)
+
+// localeContextKey 请求上下文中保存响应语言的键
+const localeContextKey = "locale"
+
+// Locale 根据 ?lang= 或 Accept-Language 选择响应语言
+func Locale() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		locale := i18n.Match(c.Query("lang"))
+		if locale == "" {
+			locale = i18n.Negotiate(c.GetHeader("Accept-Language"))
+		}
+		c.Set(localeContextKey, locale)
+		c.Header("Content-Language", locale)
+		c.Header("Vary", "Accept-Language")
+		c.Next()
+	}
+}
+
+// GetLocale 返回请求选择的语言
+func GetLocale(c *gin.Context) string {
+	if locale := c.GetString(localeContextKey); locale != "" {
+		return locale
+	}
+	return i18n.DefaultLocale
+}
+
+// abortWithMessage 以本地化的消息终止请求
+func abortWithMessage(c *gin.Context, status int, message string) {
+	c.AbortWithStatusJSON(status, gin.H{
+		"success": false,
+		"code":    i18n.Code(message),
+		"message": i18n.T(GetLocale(c), message),
+	})
+}
@@ -3,55 +3,67 @@ package middleware
 import (
 	"bytes"
 	"io"
-	"log"
+	"log/slog"
 	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"whatsapp-aggregator/internal/logging"
 )
 
-// RequestLogger 记录请求和响应日志的中间件
+// maxLoggedBody 调试日志中请求和响应体最多记录的字节数，避免事件流等长连接无限占用内存
+const maxLoggedBody = 1000
+
+// RequestLogger 记录请求日志的中间件，debug级别下同时记录请求和响应体
 func RequestLogger() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		// Start time
 		startTime := time.Now()
+		logger := logging.FromContext(c.Request.Context())
+		debug := logger.Enabled(c.Request.Context(), slog.LevelDebug)
 
-		// Read body
 		var bodyBytes []byte
-		if c.Request.Body != nil {
-			bodyBytes, _ = io.ReadAll(c.Request.Body)
-			c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+		var blw *bodyLogWriter
+		if debug {
+			if c.Request.Body != nil {
+				bodyBytes, _ = io.ReadAll(c.Request.Body)
+				c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
+			}
+			blw = &bodyLogWriter{body: bytes.NewBufferString(""), ResponseWriter: c.Writer}
+			c.Writer = blw
 		}
 
-		// Custom ResponseWriter to capture response
-		blw := &bodyLogWriter{body: bytes.NewBufferString(""), ResponseWriter: c.Writer}
-		c.Writer = blw
-
-		// Process request
 		c.Next()
 
-		// Log details
-		duration := time.Since(startTime)
-		
-		// Truncate body if too long for log
-		reqBody := string(bodyBytes)
-		if len(reqBody) > 1000 {
-			reqBody = reqBody[:1000] + "...(truncated)"
+		status := c.Writer.Status()
+		level := slog.LevelInfo
+		switch {
+		case status >= 500:
+			level = slog.LevelError
+		case status >= 400:
+			level = slog.LevelWarn
+		}
+
+		attrs := []any{
+			"status", status,
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"duration", time.Since(startTime),
+			"client_ip", c.ClientIP(),
 		}
-		
-		respBody := blw.body.String()
-		if len(respBody) > 1000 {
-			respBody = respBody[:1000] + "...(truncated)"
+		if debug {
+			// 密码、令牌等敏感字段脱敏后记录
+			attrs = append(attrs, "request_body", truncateBody(logging.RedactJSON(bodyBytes)), "response_body", truncateBody(logging.RedactJSON(blw.body.Bytes())))
 		}
+		logger.Log(c.Request.Context(), level, "API request", attrs...)
+	}
+}
 
-		log.Printf("\n[API] %d | %13v | %s | %s\n> Req: %s\n< Resp: %s\n",
-			c.Writer.Status(),
-			duration,
-			c.Request.Method,
-			c.Request.RequestURI,
-			reqBody,
-			respBody,
-		)
+// truncateBody 截断过长的日志内容
+func truncateBody(body []byte) string {
+	if len(body) > maxLoggedBody {
+		return string(body[:maxLoggedBody]) + "...(truncated)"
 	}
+	return string(body)
 }
 
 type bodyLogWriter struct {
@@ -60,6 +72,12 @@ type bodyLogWriter struct {
 }
 
 func (w bodyLogWriter) Write(b []byte) (int, error) {
-	w.body.Write(b)
+	if remaining := maxLoggedBody + 1 - w.body.Len(); remaining > 0 {
+		if len(b) > remaining {
+			w.body.Write(b[:remaining])
+		} else {
+			w.body.Write(b)
+		}
+	}
 	return w.ResponseWriter.Write(b)
 }
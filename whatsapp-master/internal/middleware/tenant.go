@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyHeader 租户API Key请求头
+const APIKeyHeader = "X-API-Key"
+
+// 请求上下文中保存调用方身份的键
+const (
+	tenantContextKey   = "tenant_id"
+	apiKeyContextKey   = "api_key_id"
+	keyOwnerContextKey = "api_key_owner"
+	adminContextKey    = "admin"
+)
+
+// Authenticate 多租户模式下的鉴权：X-Admin-Token 可访问所有资源，X-API-Key 限定为所属租户
+func Authenticate(adminToken string, lookup func(key string) (tenantID, keyID, owner string, err error)) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if provided := credential(c, AdminTokenHeader, "admin_token"); provided != "" {
+			if adminToken == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(adminToken)) != 1 {
+				abortWithMessage(c, http.StatusUnauthorized, "Invalid admin token")
+				return
+			}
+			c.Set(adminContextKey, true)
+			c.Next()
+			return
+		}
+
+		key := credential(c, APIKeyHeader, "api_key")
+		if key == "" {
+			abortWithMessage(c, http.StatusUnauthorized, "Missing X-API-Key header")
+			return
+		}
+
+		tenantID, keyID, owner, err := lookup(key)
+		if err != nil {
+			abortWithMessage(c, http.StatusUnauthorized, "Invalid API key")
+			return
+		}
+		c.Set(tenantContextKey, tenantID)
+		c.Set(apiKeyContextKey, keyID)
+		c.Set(keyOwnerContextKey, owner)
+		c.Next()
+	}
+}
+
+// credential 读取鉴权请求头。浏览器无法为WebSocket握手设置请求头，此时也接受同名的查询参数
+func credential(c *gin.Context, header, queryParam string) string {
+	if value := c.GetHeader(header); value != "" {
+		return value
+	}
+	if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		return c.Query(queryParam)
+	}
+	return ""
+}
+
+// TenantID 返回调用方租户，管理员或未开启多租户时返回false
+func TenantID(c *gin.Context) (string, bool) {
+	tenantID := c.GetString(tenantContextKey)
+	return tenantID, tenantID != ""
+}
+
+// APIKeyID 返回调用方使用的租户API Key ID，未使用API Key时返回空字符串
+func APIKeyID(c *gin.Context) string {
+	return c.GetString(apiKeyContextKey)
+}
+
+// APIKeyOwner 返回调用方API Key绑定的运营人员或团队，未绑定时返回空字符串
+func APIKeyOwner(c *gin.Context) string {
+	return c.GetString(keyOwnerContextKey)
+}
+
+// IsAdmin 调用方是否通过了管理员token校验
+func IsAdmin(c *gin.Context) bool {
+	return c.GetBool(adminContextKey)
+}
@@ -0,0 +1,21 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WorkerTokenHeader Worker回调Master时携带凭证的请求头
+const WorkerTokenHeader = "X-Worker-Token"
+
+// RequireWorkerToken 校验路径中账号对应Worker的 X-Worker-Token
+func RequireWorkerToken(verify func(accountID, token string) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !verify(c.Param("id"), c.GetHeader(WorkerTokenHeader)) {
+			abortWithMessage(c, http.StatusUnauthorized, "Invalid worker token")
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,95 @@
+package model
+
+import "time"
+
+// 告警通道类型
+const (
+	AlertChannelSlack    = "slack"
+	AlertChannelTelegram = "telegram"
+	AlertChannelEmail    = "email"
+	AlertChannelWebhook  = "webhook"
+)
+
+// AlertChannel 告警通知通道，凭证只写不读
+type AlertChannel struct {
+	ID      string `json:"id" gorm:"primaryKey"`
+	Name    string `json:"name"`
+	Type    string `json:"type"` // slack, telegram, email, webhook
+	Enabled bool   `json:"enabled"`
+
+	URL string `json:"url,omitempty"` // slack: Incoming Webhook地址；webhook: 接收Incident JSON的地址
+
+	BotToken Secret `json:"-" gorm:"size:512"` // telegram
+	ChatID   string `json:"chat_id,omitempty"`
+
+	SMTPHost     string     `json:"smtp_host,omitempty"` // email
+	SMTPPort     int        `json:"smtp_port,omitempty"`
+	SMTPUsername string     `json:"smtp_username,omitempty"`
+	SMTPPassword Secret     `json:"-" gorm:"size:512"`
+	From         string     `json:"from,omitempty"`
+	To           StringList `json:"to,omitempty" gorm:"type:text"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (AlertChannel) TableName() string {
+	return "alert_channels"
+}
+
+// AlertChannelRequest 创建或更新告警通道请求，更新时凭证留空表示保持不变
+type AlertChannelRequest struct {
+	Name    string `json:"name"`
+	Type    string `json:"type" binding:"required,oneof=slack telegram email webhook"`
+	Enabled *bool  `json:"enabled,omitempty"` // 默认true
+
+	URL string `json:"url,omitempty" binding:"omitempty,url"`
+
+	BotToken string `json:"bot_token,omitempty"`
+	ChatID   string `json:"chat_id,omitempty"`
+
+	SMTPHost     string   `json:"smtp_host,omitempty"`
+	SMTPPort     int      `json:"smtp_port,omitempty" binding:"omitempty,min=1,max=65535"` // 默认587
+	SMTPUsername string   `json:"smtp_username,omitempty"`
+	SMTPPassword string   `json:"smtp_password,omitempty"`
+	From         string   `json:"from,omitempty" binding:"omitempty,email"`
+	To           []string `json:"to,omitempty" binding:"omitempty,dive,email"`
+}
+
+// AlertRule 告警路由规则，匹配的事件发送到规则指定的通道
+type AlertRule struct {
+	ID         string     `json:"id" gorm:"primaryKey"`
+	Name       string     `json:"name"`
+	Events     StringList `json:"events" gorm:"type:text"`      // 为空时匹配 ALERT_EVENTS 中的事件
+	AccountIDs StringList `json:"account_ids" gorm:"type:text"` // 为空时匹配所有账号
+	Pool       string     `json:"pool,omitempty"`               // 只匹配该账号池
+	OwnerTeam  string     `json:"owner_team,omitempty"`         // 只匹配该团队负责的账号
+	Channels   StringList `json:"channels" gorm:"type:text"`    // 告警通道ID
+	Enabled    bool       `json:"enabled"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (AlertRule) TableName() string {
+	return "alert_rules"
+}
+
+// AlertRuleRequest 创建或更新告警路由规则请求
+type AlertRuleRequest struct {
+	Name       string   `json:"name"`
+	Events     []string `json:"events,omitempty"`
+	AccountIDs []string `json:"account_ids,omitempty"`
+	Pool       string   `json:"pool,omitempty"`
+	OwnerTeam  string   `json:"owner_team,omitempty"`
+	Channels   []string `json:"channels" binding:"required,min=1,dive,required"`
+	Enabled    *bool    `json:"enabled,omitempty"` // 默认true
+}
+
+// AlertTestResult 告警通道测试结果
+type AlertTestResult struct {
+	ChannelID string `json:"channel_id"`
+	Delivered bool   `json:"delivered"`
+	Error     string `json:"error,omitempty"`
+}
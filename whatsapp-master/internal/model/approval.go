@@ -0,0 +1,48 @@
+package model
+
+import "time"
+
+// 审批类型
+const (
+	ApprovalKindCampaign = "campaign" // 开始发送活动
+	ApprovalKindBulk     = "bulk"     // 批量发送或广播
+)
+
+// 审批状态
+const (
+	ApprovalPending  = "pending"  // 等待审批
+	ApprovalApproved = "approved" // 已批准并开始发送
+	ApprovalRejected = "rejected" // 已拒绝，不会发送
+	ApprovalFailed   = "failed"   // 已批准但无法开始发送
+)
+
+// Approval 收件人数超过审批阈值的活动或批量发送，批准后才开始发送
+type Approval struct {
+	ID         string     `json:"id" gorm:"primaryKey"`
+	Kind       string     `json:"kind" gorm:"index"` // campaign, bulk
+	CampaignID string     `json:"campaign_id,omitempty" gorm:"index"`
+	Campaign   string     `json:"campaign,omitempty"` // 活动名称或批量发送的campaign标签
+	AccountIDs StringList `json:"account_ids" gorm:"type:text"`
+	Recipients int        `json:"recipients"`
+	Message    string     `json:"message" gorm:"type:text"` // 消息模板
+	Sample     RawJSON    `json:"sample" gorm:"type:text" swaggertype:"array,object"`
+	Request    RawJSON    `json:"-" gorm:"type:text"` // 批量发送的原始请求，批准后按此发送
+	Status     string     `json:"status" gorm:"index"`
+	Note       string     `json:"note,omitempty" gorm:"type:text"` // 审批备注
+	BatchID    string     `json:"batch_id,omitempty"`              // 批准后创建的批量发送批次
+	Error      string     `json:"error,omitempty" gorm:"type:text"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// ApprovalSample 审批时展示的一条示例消息
+type ApprovalSample struct {
+	Contact string `json:"contact"`
+	Message string `json:"message"` // 按收件人变量渲染后的正文
+}
+
+// ReviewApprovalRequest 批准或拒绝请求
+type ReviewApprovalRequest struct {
+	Note string `json:"note,omitempty" binding:"max=1000"`
+}
@@ -0,0 +1,28 @@
+package model
+
+import "time"
+
+// AuditEntry 一次写操作API调用的审计记录
+type AuditEntry struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	RequestID  string    `json:"request_id" gorm:"index"`
+	Actor      string    `json:"actor" gorm:"index"` // admin、api_key 或 anonymous（未开启鉴权）
+	APIKeyID   string    `json:"api_key_id,omitempty" gorm:"column:api_key_id;index"`
+	TenantID   string    `json:"tenant_id,omitempty" gorm:"index"`
+	Method     string    `json:"method"`
+	Route      string    `json:"route" gorm:"index"` // 路由模板，如 /api/v1/accounts/:id/stop
+	Path       string    `json:"path"`
+	AccountID  string    `json:"account_id,omitempty" gorm:"index"`
+	Request    string    `json:"request,omitempty" gorm:"type:text"` // 脱敏并截断后的请求体
+	Status     int       `json:"status"`
+	Success    bool      `json:"success"`
+	Result     string    `json:"result,omitempty"` // 响应消息，失败时附带错误
+	ClientIP   string    `json:"client_ip"`
+	DurationMs int64     `json:"duration_ms"`
+	CreatedAt  time.Time `json:"created_at" gorm:"index"`
+}
+
+// TableName 指定表名
+func (AuditEntry) TableName() string {
+	return "audit_log"
+}
@@ -0,0 +1,76 @@
+package model
+
+import "time"
+
+// 自动回复规则的匹配方式
+const (
+	AutoReplyMatchKeyword = "keyword" // 消息包含任一关键词（不区分大小写）
+	AutoReplyMatchRegex   = "regex"   // 消息匹配正则表达式
+)
+
+// AutoReplyRule 账号的自动回复规则：收件箱收集到的入站消息按优先级匹配第一条启用的规则，回复经发送队列发出
+type AutoReplyRule struct {
+	ID                   string     `json:"id" gorm:"primaryKey"`
+	AccountID            string     `json:"account_id" gorm:"index"`
+	Name                 string     `json:"name"`
+	MatchType            string     `json:"match_type"`                // keyword, regex
+	Keywords             StringList `json:"keywords" gorm:"type:text"` // match_type为keyword时使用
+	Pattern              string     `json:"pattern,omitempty"`         // match_type为regex时使用
+	Reply                string     `json:"reply" gorm:"type:text"`    // 回复模板，支持 {{contact}}、{{message}} 和正则命名分组 {{name}}
+	Priority             int        `json:"priority"`                  // 数值小的先匹配
+	QuietHourStart       int        `json:"quiet_hour_start"`          // 静默时段开始（本地时间，小时），与结束相同表示不静默
+	QuietHourEnd         int        `json:"quiet_hour_end"`            // 静默时段结束（不含）
+	MaxRepliesPerContact int        `json:"max_replies_per_contact"`   // 每个联系人在 AUTO_REPLY_WINDOW_HOURS 内最多回复次数，0表示不限制
+	IncludeGroups        bool       `json:"include_groups"`            // 是否回复群组消息
+	Enabled              bool       `json:"enabled"`
+	CreatedAt            time.Time  `json:"created_at"`
+	UpdatedAt            time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (AutoReplyRule) TableName() string {
+	return "auto_reply_rules"
+}
+
+// AutoReplyRuleRequest 创建或更新自动回复规则请求
+type AutoReplyRuleRequest struct {
+	Name                 string   `json:"name" binding:"max=100"`
+	MatchType            string   `json:"match_type" binding:"required,oneof=keyword regex"`
+	Keywords             []string `json:"keywords,omitempty" binding:"max=100,dive,required,max=200"`
+	Pattern              string   `json:"pattern,omitempty" binding:"max=1000"`
+	Reply                string   `json:"reply" binding:"required,max=4096"`
+	Priority             int      `json:"priority"`
+	QuietHourStart       int      `json:"quiet_hour_start" binding:"min=0,max=23"`
+	QuietHourEnd         int      `json:"quiet_hour_end" binding:"min=0,max=23"`
+	MaxRepliesPerContact int      `json:"max_replies_per_contact" binding:"min=0"`
+	IncludeGroups        bool     `json:"include_groups"`
+	Enabled              *bool    `json:"enabled,omitempty"` // 默认true
+}
+
+// 自动回复记录状态
+const (
+	AutoReplyQueued  = "queued"
+	AutoReplySent    = "sent"
+	AutoReplyFailed  = "failed"
+	AutoReplyDropped = "dropped" // 发送队列已满
+)
+
+// AutoReplyLog 一次自动回复，用于限制每个联系人的回复次数和排查
+type AutoReplyLog struct {
+	ID               string     `json:"id" gorm:"primaryKey"`
+	RuleID           string     `json:"rule_id" gorm:"index"`
+	AccountID        string     `json:"account_id" gorm:"index:idx_auto_reply_contact"`
+	Contact          string     `json:"contact" gorm:"index:idx_auto_reply_contact"`
+	InboundMessageID string     `json:"inbound_message_id"`
+	Reply            string     `json:"reply" gorm:"type:text"`
+	Status           string     `json:"status"`               // queued, sent, failed, dropped
+	MessageID        string     `json:"message_id,omitempty"` // 回复消息的ID
+	Error            string     `json:"error,omitempty" gorm:"type:text"`
+	CreatedAt        time.Time  `json:"created_at" gorm:"index:idx_auto_reply_contact"`
+	SentAt           *time.Time `json:"sent_at,omitempty"`
+}
+
+// TableName 指定表名
+func (AutoReplyLog) TableName() string {
+	return "auto_reply_logs"
+}
@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// 会话备份状态
+const (
+	BackupStatusSucceeded = "succeeded"
+	BackupStatusFailed    = "failed"
+)
+
+// 会话备份触发方式
+const (
+	BackupTriggerScheduled = "scheduled" // BACKUP_SCHEDULE 定期备份
+	BackupTriggerManual    = "manual"    // 通过接口立即备份
+)
+
+// SessionBackup 账号会话目录的一次备份
+type SessionBackup struct {
+	ID          string    `json:"id" gorm:"primaryKey"`
+	AccountID   string    `json:"account_id" gorm:"index"`
+	HostID      string    `json:"host_id,omitempty"`                      // 备份时Worker所在主机
+	Destination string    `json:"destination"`                            // 不含凭证的备份目的地
+	Key         string    `json:"key,omitempty" gorm:"column:object_key"` // 目的地中的备份文件，如 账号ID/20240101T030000Z.tar.gz
+	SizeBytes   int64     `json:"size_bytes"`                             // 压缩后的大小
+	SHA256      string    `json:"sha256,omitempty"`                       // 恢复时校验
+	Trigger     string    `json:"trigger" gorm:"column:backup_trigger"`   // scheduled, manual
+	Status      string    `json:"status" gorm:"index"`                    // succeeded, failed
+	Error       string    `json:"error,omitempty" gorm:"type:text"`
+	CreatedAt   time.Time `json:"created_at" gorm:"index"`
+}
+
+// BackupFilter 备份列表筛选条件
+type BackupFilter struct {
+	AccountIDs []string // 不为nil时只返回这些账号的备份
+}
+
+// RunBackupRequest 立即备份请求
+type RunBackupRequest struct {
+	AccountIDs []string `json:"account_ids,omitempty"` // 为空时备份所有已登录的账号
+}
+
+// BackupRunResult 一次备份的结果
+type BackupRunResult struct {
+	Backups  []*SessionBackup `json:"backups"`
+	Skipped  []string         `json:"skipped"` // 未登录或已停用而跳过的账号
+	Pruned   int              `json:"pruned"`  // 按保留策略删除的旧备份数
+	Failed   int              `json:"failed"`
+	Duration string           `json:"duration"`
+}
+
+// BackupStatus 定期备份的配置和最近一次运行
+type BackupStatus struct {
+	Schedule       string           `json:"schedule,omitempty"`
+	Destination    string           `json:"destination"`
+	RetentionCount int              `json:"retention_count"`
+	RetentionDays  int              `json:"retention_days"`
+	NextRun        *time.Time       `json:"next_run,omitempty"`
+	LastRun        *BackupRunResult `json:"last_run,omitempty"`
+	LastRunAt      *time.Time       `json:"last_run_at,omitempty"`
+}
@@ -0,0 +1,53 @@
+package model
+
+import "time"
+
+// 封号记录状态
+const (
+	BanStatusActive   = "active"   // 账号隔离中
+	BanStatusReleased = "released" // 已人工解除隔离
+)
+
+// 封号信号来源
+const (
+	BanSourceWorkerError = "worker_error" // Worker接口返回的错误信息
+	BanSourceLogout      = "logout"       // Worker上报的注销原因
+	BanSourceLogoutLoop  = "logout_loop"  // 短时间内反复掉线
+)
+
+// BanRecord 检测到的封号信号和隔离记录，证据保留用于申诉
+type BanRecord struct {
+	ID             string     `json:"id" gorm:"primaryKey"`
+	AccountID      string     `json:"account_id" gorm:"index"`
+	Phone          string     `json:"phone,omitempty"`
+	Source         string     `json:"source"` // worker_error, logout, logout_loop
+	Signal         string     `json:"signal"` // 匹配到的错误特征或掉线次数
+	Evidence       RawJSON    `json:"evidence,omitempty" gorm:"type:text"`
+	PreviousStatus string     `json:"previous_status"` // 隔离前的账号状态
+	WasDisabled    bool       `json:"-"`               // 隔离前已停用，解除隔离时保持停用
+	Status         string     `json:"status" gorm:"index"`
+	ReleaseNote    string     `json:"release_note,omitempty" gorm:"type:text"`
+	ReleasedAt     *time.Time `json:"released_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// BanEvidence 封号证据：触发的请求、Worker返回的错误和最近的状态变化
+type BanEvidence struct {
+	WorkerPath   string                 `json:"worker_path,omitempty"`
+	StatusCode   int                    `json:"status_code,omitempty"`
+	Error        string                 `json:"error,omitempty"`
+	Logouts      []time.Time            `json:"logouts,omitempty"` // 窗口内的掉线时间
+	Transitions  []*StatusTransition    `json:"transitions,omitempty"`
+	WorkerStatus map[string]interface{} `json:"worker_status,omitempty"` // 检测时Worker的 /api/status 响应
+}
+
+// BanFilter 封号记录列表筛选条件
+type BanFilter struct {
+	AccountIDs []string // 不为nil时只返回这些账号的记录
+}
+
+// ReleaseBanRequest 解除隔离请求
+type ReleaseBanRequest struct {
+	Note string `json:"note,omitempty"` // 申诉结果等备注
+}
@@ -0,0 +1,87 @@
+package model
+
+import "time"
+
+// BulkRecipient 批量发送的收件人
+type BulkRecipient struct {
+	Contact   string            `json:"contact" binding:"required"`
+	Variables map[string]string `json:"variables,omitempty"` // 模板变量，替换消息中的 {{key}}
+}
+
+// BulkSendRequest 批量发送请求模型
+type BulkSendRequest struct {
+	AccountIDs  []string        `json:"account_ids,omitempty"` // 为空时使用所有已登录账号
+	Recipients  []BulkRecipient `json:"recipients" binding:"required,min=1,dive"`
+	Message     string          `json:"message" binding:"required"` // 消息模板
+	Concurrency int             `json:"concurrency,omitempty"`      // 全局并发发送数
+	IntervalMs  int             `json:"interval_ms,omitempty"`      // 同一账号两次发送的最小间隔
+	Campaign    string          `json:"campaign,omitempty"`
+	TrackLinks  *bool           `json:"track_links,omitempty"`
+}
+
+// BulkResult 单个收件人的发送结果
+type BulkResult struct {
+	Contact   string     `json:"contact"`
+	AccountID string     `json:"account_id"`
+	Status    string     `json:"status"` // pending, sending, sent, failed
+	Error     string     `json:"error,omitempty"`
+	SentAt    *time.Time `json:"sent_at,omitempty"`
+}
+
+// BulkBatch 批量发送批次
+type BulkBatch struct {
+	ID         string        `json:"id"`
+	Status     string        `json:"status"` // running, completed, cancelled, interrupted
+	JobID      string        `json:"job_id,omitempty"`
+	AccountIDs []string      `json:"account_ids"`
+	Campaign   string        `json:"campaign,omitempty"`
+	Total      int           `json:"total"`
+	Duplicates int           `json:"duplicates,omitempty"` // 广播时去掉的重复收件人数
+	Sent       int           `json:"sent"`
+	Failed     int           `json:"failed"`
+	Results    []*BulkResult `json:"results"`
+	CreatedAt  time.Time     `json:"created_at"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+}
+
+// BroadcastFilter 筛选参与广播的发送账号，条件之间为与关系，为空时使用所有已登录账号
+type BroadcastFilter struct {
+	AccountIDs []string `json:"account_ids,omitempty"`
+	Pool       string   `json:"pool,omitempty"`
+	Tags       []string `json:"tags,omitempty"` // 账号需包含全部标签
+}
+
+// BroadcastRequest 全局广播请求：去重后的联系人按轮询分配给各发送账号
+type BroadcastRequest struct {
+	Contacts    []string         `json:"contacts" binding:"required,min=1,dive,required"`
+	Message     string           `json:"message" binding:"required"` // 支持 {{contact}} 变量
+	Senders     *BroadcastFilter `json:"senders,omitempty"`
+	Concurrency int              `json:"concurrency,omitempty"`
+	IntervalMs  int              `json:"interval_ms,omitempty"`
+	Campaign    string           `json:"campaign,omitempty"`
+	TrackLinks  *bool            `json:"track_links,omitempty"`
+}
+
+// BroadcastAccountReport 单个发送账号的广播结果
+type BroadcastAccountReport struct {
+	AccountID string `json:"account_id"`
+	Assigned  int    `json:"assigned"`
+	Sent      int    `json:"sent"`
+	Failed    int    `json:"failed"`
+}
+
+// BroadcastReport 广播汇总报告，逐个收件人的结果见 GET /send-bulk/{id}
+type BroadcastReport struct {
+	ID         string                    `json:"id"` // 批量发送批次ID
+	JobID      string                    `json:"job_id,omitempty"`
+	Status     string                    `json:"status"`
+	Total      int                       `json:"total"` // 去重后的收件人数
+	Duplicates int                       `json:"duplicates"`
+	Sent       int                       `json:"sent"`
+	Failed     int                       `json:"failed"`
+	Pending    int                       `json:"pending"`
+	Accounts   []*BroadcastAccountReport `json:"accounts"`
+	Errors     map[string]int            `json:"errors,omitempty"` // 失败原因 -> 收件人数
+	CreatedAt  time.Time                 `json:"created_at"`
+	FinishedAt *time.Time                `json:"finished_at,omitempty"`
+}
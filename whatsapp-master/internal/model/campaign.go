@@ -0,0 +1,82 @@
+package model
+
+import "time"
+
+// OptOut 联系人退订记录，所有退订记录组成全局的抑制名单，名单中的号码不会再收到任何账号发送的消息
+type OptOut struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	AccountID string    `json:"account_id,omitempty" gorm:"index"` // 收到退订回复的账号，手动添加时为空
+	Contact   string    `json:"contact" gorm:"index"`
+	Campaign  string    `json:"campaign,omitempty" gorm:"index"` // 退订归属的最近一次活动
+	MessageID string    `json:"message_id,omitempty"`            // 触发退订的入站消息
+	Keyword   string    `json:"keyword,omitempty"`
+	Source    string    `json:"source" gorm:"index"`               // keyword, manual
+	Reason    string    `json:"reason,omitempty" gorm:"type:text"` // 手动添加的原因
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名
+func (OptOut) TableName() string {
+	return "opt_outs"
+}
+
+// CampaignMetrics 单个活动的实时指标
+type CampaignMetrics struct {
+	Campaign string `json:"campaign"`
+	Queued   int    `json:"queued"`    // 运行中批次和活动里等待发送的收件人
+	InFlight int    `json:"in_flight"` // 正在发送的收件人
+	Sent     int64  `json:"sent"`
+	Failed   int64  `json:"failed"`
+	OptOuts  int64  `json:"opt_outs"`
+}
+
+// Campaign 营销活动：一组发送账号、目标联系人列表和消息模板
+type Campaign struct {
+	ID         string            `json:"id" gorm:"primaryKey"`
+	Name       string            `json:"name" gorm:"uniqueIndex"` // 同时作为消息的campaign标签，用于指标和退订归属
+	Status     string            `json:"status" gorm:"index"`     // draft, pending_approval, running, paused, completed
+	AccountIDs StringList        `json:"account_ids" gorm:"type:text"`
+	Message    string            `json:"message" gorm:"type:text"` // 消息模板，支持 {{contact}} 和收件人变量
+	IntervalMs int               `json:"interval_ms"`              // 同一账号两次发送的最小间隔
+	TrackLinks *bool             `json:"track_links,omitempty"`
+	LastError  string            `json:"last_error,omitempty" gorm:"type:text"` // 最近一次无法继续发送的原因
+	JobID      string            `json:"job_id,omitempty"`                      // 最近一次发送的任务ID
+	Progress   *CampaignProgress `json:"progress,omitempty" gorm:"-"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+	StartedAt  *time.Time        `json:"started_at,omitempty"`
+	FinishedAt *time.Time        `json:"finished_at,omitempty"`
+}
+
+// CampaignRecipient 活动收件人及其发送结果
+type CampaignRecipient struct {
+	ID              uint       `json:"id" gorm:"primaryKey"`
+	CampaignID      string     `json:"campaign_id" gorm:"index"`
+	Contact         string     `json:"contact"`
+	Variables       StringMap  `json:"variables,omitempty" gorm:"type:text"`
+	Status          string     `json:"status" gorm:"index"` // pending, sending, sent, failed, skipped
+	AccountID       string     `json:"account_id,omitempty"`
+	WorkerMessageID string     `json:"worker_message_id,omitempty"`
+	Error           string     `json:"error,omitempty" gorm:"type:text"`
+	SentAt          *time.Time `json:"sent_at,omitempty"`
+}
+
+// CampaignProgress 活动收件人按状态统计
+type CampaignProgress struct {
+	Total   int64 `json:"total"`
+	Pending int64 `json:"pending"`
+	Sending int64 `json:"sending"`
+	Sent    int64 `json:"sent"`
+	Failed  int64 `json:"failed"`
+	Skipped int64 `json:"skipped"` // 已退订的联系人
+}
+
+// CreateCampaignRequest 创建活动请求
+type CreateCampaignRequest struct {
+	Name       string          `json:"name" binding:"required"`
+	AccountIDs []string        `json:"account_ids" binding:"required,min=1"`
+	Recipients []BulkRecipient `json:"recipients" binding:"required,min=1,dive"`
+	Message    string          `json:"message" binding:"required"`
+	IntervalMs int             `json:"interval_ms,omitempty"`
+	TrackLinks *bool           `json:"track_links,omitempty"`
+}
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// ChaosFault 注入到账号Worker调用上的故障
+type ChaosFault struct {
+	AccountID string    `json:"account_id"`
+	DelayMs   int       `json:"delay_ms"`   // 每次Worker调用前的额外延迟
+	ErrorRate float64   `json:"error_rate"` // Worker调用直接失败的概率（0-1）
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// ChaosDelayRequest 注入延迟/失败请求
+type ChaosDelayRequest struct {
+	DelayMs         int     `json:"delay_ms" binding:"min=0"`
+	ErrorRate       float64 `json:"error_rate" binding:"min=0,max=1"`
+	DurationSeconds int     `json:"duration_seconds" binding:"required,min=1"`
+}
+
+// ChaosStatusRequest 强制切换账号状态请求
+type ChaosStatusRequest struct {
+	Status string `json:"status" binding:"required"`
+}
@@ -0,0 +1,30 @@
+package model
+
+// ChatHistory 与某个联系人的一页会话历史，消息按时间从旧到新排列
+type ChatHistory struct {
+	AccountID  string     `json:"account_id"`
+	Contact    string     `json:"contact"`
+	Messages   []*Message `json:"messages"`
+	Cached     int        `json:"cached"`                // 本次从Worker抓取并新写入数据库的消息数
+	HasMore    bool       `json:"has_more"`              // 是否还有更早的消息
+	NextBefore string     `json:"next_before,omitempty"` // 下一页（更早的消息）的 before 参数
+}
+
+// WorkerChatHistory Worker返回的一页会话历史
+type WorkerChatHistory struct {
+	Messages []WorkerChatMessage `json:"messages"`
+	HasMore  bool                `json:"has_more"`
+}
+
+// WorkerChatMessage Worker返回的单条历史消息，timestamp为毫秒时间戳
+type WorkerChatMessage struct {
+	ID        string `json:"id"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+	FromMe    bool   `json:"from_me"`
+	Body      string `json:"body"`
+	Timestamp int64  `json:"timestamp"`
+	Type      string `json:"type"`
+	Author    string `json:"author,omitempty"`
+	Ack       int    `json:"ack"`
+}
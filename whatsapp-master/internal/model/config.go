@@ -0,0 +1,18 @@
+package model
+
+import "time"
+
+// ConfigOverride 通过配置接口修改并持久化的配置项，启动时覆盖环境变量中的默认值
+type ConfigOverride struct {
+	Key       string      `json:"key" gorm:"column:config_key;primaryKey"` // 如 rateLimit.globalPerMinute
+	Value     string      `json:"-" gorm:"type:text"`                      // JSON编码的值
+	Decoded   interface{} `json:"value" gorm:"-"`
+	HotReload bool        `json:"hot_reload" gorm:"-"` // 为false时需要重启Master才能生效
+	UpdatedAt time.Time   `json:"updated_at"`
+}
+
+// ConfigUpdateResult 配置修改结果
+type ConfigUpdateResult struct {
+	Applied        []string `json:"applied"`         // 已立即生效的配置项
+	PendingRestart []string `json:"pending_restart"` // 已保存但需要重启Master才能生效的配置项
+}
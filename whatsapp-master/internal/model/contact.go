@@ -0,0 +1,62 @@
+package model
+
+import "time"
+
+// Contact 从Worker同步的账号联系人
+type Contact struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	AccountID   string    `json:"account_id" gorm:"uniqueIndex:idx_contact_account_wid"`
+	WID         string    `json:"wid" gorm:"column:wid;uniqueIndex:idx_contact_account_wid"` // WhatsApp ID，如 8613800000000@c.us
+	Number      string    `json:"number" gorm:"index"`
+	Name        string    `json:"name"`
+	PushName    string    `json:"push_name,omitempty"`
+	IsGroup     bool      `json:"is_group"`
+	IsMyContact bool      `json:"is_my_contact"`
+	SyncedAt    time.Time `json:"synced_at"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Contact) TableName() string {
+	return "contacts"
+}
+
+// ContactSyncResult 一次联系人同步的结果
+type ContactSyncResult struct {
+	AccountID string `json:"account_id"`
+	Synced    int    `json:"synced"`  // Worker返回并写入的联系人数
+	Removed   int64  `json:"removed"` // Worker上已不存在而删除的联系人数
+}
+
+// 联系人导入每行的结果
+const (
+	ContactImportAdded     = "added"
+	ContactImportInvalid   = "invalid"   // 号码格式错误，未发送给Worker
+	ContactImportDuplicate = "duplicate" // 与文件中前面的行号码相同
+	ContactImportFailed    = "failed"    // Worker添加失败
+	ContactImportSkipped   = "skipped"   // 账号离线或请求取消，未处理
+)
+
+// ContactImportRow 联系人导入中一行的结果
+type ContactImportRow struct {
+	Row       int    `json:"row"`              // 文件中的行号，从1开始，包含表头
+	Phone     string `json:"phone"`            // 文件中的原始号码
+	Number    string `json:"number,omitempty"` // 规范化后的 E.164 号码
+	FirstName string `json:"first_name,omitempty"`
+	LastName  string `json:"last_name,omitempty"`
+	Status    string `json:"status"` // added, invalid, duplicate, failed, skipped
+	Error     string `json:"error,omitempty"`
+}
+
+// ContactImportResult 联系人导入的结果
+type ContactImportResult struct {
+	AccountID  string              `json:"account_id"`
+	Total      int                 `json:"total"`
+	Added      int                 `json:"added"`
+	Invalid    int                 `json:"invalid"`
+	Duplicates int                 `json:"duplicates"`
+	Failed     int                 `json:"failed"`
+	Skipped    int                 `json:"skipped"`
+	Rows       []*ContactImportRow `json:"rows"`
+}
@@ -0,0 +1,89 @@
+package model
+
+import "time"
+
+// 内容策略类型
+const (
+	ContentPolicyKeyword      = "keyword"       // 消息包含任一屏蔽词（不区分大小写）
+	ContentPolicyURLAllowlist = "url_allowlist" // 消息中的链接必须属于允许的域名（含子域名）
+	ContentPolicyMaxLinks     = "max_links"     // 消息中的链接数超过上限
+	ContentPolicyPII          = "pii"           // 消息包含个人信息：内置的 email、credit_card、phone、iban 或自定义正则
+)
+
+// 违反内容策略时的处理方式
+const (
+	ContentActionReject = "reject" // 拒绝发送
+	ContentActionReview = "review" // 放入审核队列，人工通过后发送
+)
+
+// ContentPolicy 出站消息的内容策略，发送文本消息前按启用的策略检查渲染后的正文
+type ContentPolicy struct {
+	ID        string     `json:"id" gorm:"primaryKey"`
+	Name      string     `json:"name"`
+	Type      string     `json:"type"`                                // keyword, url_allowlist, max_links, pii
+	Keywords  StringList `json:"keywords,omitempty" gorm:"type:text"` // type为keyword时的屏蔽词
+	Domains   StringList `json:"domains,omitempty" gorm:"type:text"`  // type为url_allowlist时允许的域名
+	MaxLinks  int        `json:"max_links,omitempty"`                 // type为max_links时每条消息最多的链接数
+	Patterns  StringList `json:"patterns,omitempty" gorm:"type:text"` // type为pii时的内置名称或正则表达式
+	Action    string     `json:"action"`                              // reject, review
+	Enabled   bool       `json:"enabled"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// ContentPolicyRequest 创建或更新内容策略请求
+type ContentPolicyRequest struct {
+	Name     string   `json:"name" binding:"max=100"`
+	Type     string   `json:"type" binding:"required,oneof=keyword url_allowlist max_links pii"`
+	Keywords []string `json:"keywords,omitempty" binding:"max=500,dive,required,max=200"`
+	Domains  []string `json:"domains,omitempty" binding:"max=500,dive,required,max=253"`
+	MaxLinks int      `json:"max_links,omitempty" binding:"min=0"`
+	Patterns []string `json:"patterns,omitempty" binding:"max=50,dive,required,max=1000"`
+	Action   string   `json:"action" binding:"required,oneof=reject review"`
+	Enabled  *bool    `json:"enabled,omitempty"` // 默认true
+}
+
+// ContentViolation 消息违反的一条内容策略
+type ContentViolation struct {
+	PolicyID   string `json:"policy_id"`
+	PolicyName string `json:"policy_name,omitempty"`
+	Type       string `json:"type"`
+	Action     string `json:"action"`
+	Detail     string `json:"detail"` // 命中的屏蔽词、链接或个人信息类型
+}
+
+// 审核队列中消息的状态
+const (
+	ModerationPending  = "pending"  // 等待审核
+	ModerationSent     = "sent"     // 审核通过并已发送
+	ModerationFailed   = "failed"   // 审核通过但发送失败
+	ModerationRejected = "rejected" // 审核拒绝
+)
+
+// ModerationItem 因违反内容策略被暂扣、等待人工审核的消息
+type ModerationItem struct {
+	ID         string     `json:"id" gorm:"primaryKey"`
+	AccountID  string     `json:"account_id" gorm:"index"`
+	Contact    string     `json:"contact"`
+	Message    string     `json:"message" gorm:"type:text"` // 渲染模板后的正文
+	Campaign   string     `json:"campaign,omitempty"`
+	TrackLinks *bool      `json:"track_links,omitempty"`
+	Violations RawJSON    `json:"violations" gorm:"type:text" swaggertype:"array,object"`
+	Status     string     `json:"status" gorm:"index"`
+	Note       string     `json:"note,omitempty" gorm:"type:text"` // 审核备注
+	MessageID  string     `json:"message_id,omitempty"`            // 审核通过后发送的消息ID
+	Error      string     `json:"error,omitempty" gorm:"type:text"`
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (ModerationItem) TableName() string {
+	return "moderation_queue"
+}
+
+// ReviewModerationRequest 审核通过或拒绝请求
+type ReviewModerationRequest struct {
+	Note string `json:"note,omitempty" binding:"max=1000"`
+}
@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// Conversation 会话归属模型（机器人处理或人工坐席接管）
+type Conversation struct {
+	ID         uint       `json:"id" gorm:"primaryKey"`
+	AccountID  string     `json:"account_id" gorm:"uniqueIndex:idx_conversation_account_contact"`
+	Contact    string     `json:"contact" gorm:"uniqueIndex:idx_conversation_account_contact"`
+	HandledBy  string     `json:"handled_by" gorm:"index"` // bot, agent
+	AgentID    string     `json:"agent_id,omitempty" gorm:"index"`
+	Note       string     `json:"note,omitempty" gorm:"type:text"`
+	AssignedAt *time.Time `json:"assigned_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Conversation) TableName() string {
+	return "conversations"
+}
+
+// ClaimConversationRequest 坐席接管会话请求
+type ClaimConversationRequest struct {
+	AgentID string `json:"agent_id" binding:"required"`
+	Note    string `json:"note,omitempty"`
+	Force   bool   `json:"force,omitempty"` // 强制从其他坐席转移
+}
+
+// ReleaseConversationRequest 释放会话请求
+type ReleaseConversationRequest struct {
+	AgentID string `json:"agent_id,omitempty"` // 指定时校验当前归属
+	Note    string `json:"note,omitempty"`
+}
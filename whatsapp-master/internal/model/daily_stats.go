@@ -0,0 +1,34 @@
+package model
+
+import "time"
+
+// AccountDailyStats 账号每日统计，按本地日期累计
+type AccountDailyStats struct {
+	AccountID     string    `json:"account_id" gorm:"primaryKey"`
+	Day           string    `json:"day" gorm:"primaryKey;size:10"` // 2006-01-02
+	Sent          int       `json:"sent"`
+	Received      int       `json:"received"`
+	Failed        int       `json:"failed"`
+	UptimeSeconds int64     `json:"uptime_seconds"` // 当天处于 logged_in 的累计时长
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// StatsQuery 统计时间序列查询参数
+type StatsQuery struct {
+	From        string `form:"from"`                                                 // 开始日期（含），2006-01-02
+	To          string `form:"to"`                                                   // 结束日期（含），默认今天
+	Granularity string `form:"granularity" binding:"omitempty,oneof=day week month"` // 默认 day
+	AccountID   string `form:"account_id"`                                           // 只统计指定账号
+}
+
+// StatsPoint 时间序列中的一个统计周期
+type StatsPoint struct {
+	Period        string `json:"period"` // day: 2006-01-02，week: 周一日期，month: 2006-01
+	From          string `json:"from"`
+	To            string `json:"to"`
+	Sent          int    `json:"sent"`
+	Received      int    `json:"received"`
+	Failed        int    `json:"failed"`
+	UptimeSeconds int64  `json:"uptime_seconds"`
+	Accounts      int    `json:"accounts"` // 该周期内有统计数据的账号数
+}
@@ -0,0 +1,36 @@
+package model
+
+import "time"
+
+// 死信状态
+const (
+	DeadLetterPending   = "pending"   // 等待重试
+	DeadLetterRetrying  = "retrying"  // 正在重试
+	DeadLetterExhausted = "exhausted" // 自动重试次数用完，只能手动重试
+	DeadLetterResolved  = "resolved"  // 重试成功
+)
+
+// DeadLetter 因Worker不可达或代理错误发送失败的出站消息，可手动或自动重试
+type DeadLetter struct {
+	ID          string     `json:"id" gorm:"primaryKey"`
+	MessageID   string     `json:"message_id" gorm:"uniqueIndex"` // 对应的消息记录，重试成功后状态同步更新
+	AccountID   string     `json:"account_id" gorm:"index"`
+	Contact     string     `json:"contact"`
+	Type        string     `json:"type"` // chat, image, document, audio
+	Body        string     `json:"body" gorm:"type:text"`
+	Voice       bool       `json:"voice,omitempty"` // 音频作为语音消息发送
+	Campaign    string     `json:"campaign,omitempty"`
+	Error       string     `json:"error" gorm:"type:text"` // 最近一次失败的原因
+	Retries     int        `json:"retries"`                // 进入死信队列后的重试次数
+	Status      string     `json:"status" gorm:"index"`
+	NextRetryAt *time.Time `json:"next_retry_at,omitempty" gorm:"index"` // 下次自动重试时间，未开启自动重试时为空
+	LastRetryAt *time.Time `json:"last_retry_at,omitempty"`
+	ResolvedAt  *time.Time `json:"resolved_at,omitempty" gorm:"index"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// DeadLetterFilter 死信列表筛选条件
+type DeadLetterFilter struct {
+	AccountIDs []string // 不为nil时只返回这些账号的死信
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// DiagnosticBundle Worker上传的诊断包（截图、崩溃转储、页面HTML等）
+type DiagnosticBundle struct {
+	ID         string            `json:"id" gorm:"primaryKey"`
+	AccountID  string            `json:"account_id" gorm:"index"`
+	IncidentID string            `json:"incident_id,omitempty" gorm:"index"` // 关联的故障或事件ID
+	Kind       string            `json:"kind"`                               // screenshot, crash_dump, html, other
+	Note       string            `json:"note,omitempty" gorm:"type:text"`
+	Size       int64             `json:"size"` // 所有文件的总字节数
+	Files      []*DiagnosticFile `json:"files,omitempty" gorm:"-"`
+	CreatedAt  time.Time         `json:"created_at"`
+	ExpiresAt  time.Time         `json:"expires_at" gorm:"index"`
+}
+
+// DiagnosticFile 诊断包中的单个文件
+type DiagnosticFile struct {
+	ID       uint   `json:"-" gorm:"primaryKey"`
+	BundleID string `json:"-" gorm:"index"`
+	Name     string `json:"name"`
+	MimeType string `json:"mime_type,omitempty"`
+	Size     int64  `json:"size"`
+}
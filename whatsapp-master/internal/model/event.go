@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// Event 系统事件模型
+type Event struct {
+	Type      string      `json:"type"`
+	AccountID string      `json:"account_id,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+	Timestamp time.Time   `json:"timestamp"`
+}
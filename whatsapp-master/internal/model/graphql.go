@@ -0,0 +1,8 @@
+package model
+
+// GraphQLRequest GraphQL请求
+type GraphQLRequest struct {
+	Query         string                 `json:"query" binding:"required"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	OperationName string                 `json:"operationName,omitempty"`
+}
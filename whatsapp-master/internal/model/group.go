@@ -0,0 +1,82 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Group 从Worker同步并缓存的群组
+type Group struct {
+	ID               uint            `json:"id" gorm:"primaryKey"`
+	AccountID        string          `json:"account_id" gorm:"uniqueIndex:idx_group_account_gid"`
+	GID              string          `json:"gid" gorm:"column:gid;uniqueIndex:idx_group_account_gid"` // 群组ID，如 120363000000000000@g.us
+	Name             string          `json:"name"`
+	Description      string          `json:"description,omitempty" gorm:"type:text"`
+	Owner            string          `json:"owner,omitempty"`
+	Participants     GroupMemberList `json:"participants" gorm:"type:text"`
+	ParticipantCount int             `json:"participant_count"`
+	InviteLink       string          `json:"invite_link,omitempty"`
+	SyncedAt         time.Time       `json:"synced_at"`
+	CreatedAt        time.Time       `json:"created_at"`
+	UpdatedAt        time.Time       `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Group) TableName() string {
+	return "groups"
+}
+
+// GroupMember 群组成员
+type GroupMember struct {
+	ID           string `json:"id"`
+	IsAdmin      bool   `json:"is_admin"`
+	IsSuperAdmin bool   `json:"is_super_admin"`
+}
+
+// GroupMemberList 以JSON格式存储在数据库中的群组成员列表
+type GroupMemberList []GroupMember
+
+// Value 实现 driver.Valuer
+func (l GroupMemberList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]GroupMember(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan 实现 sql.Scanner
+func (l *GroupMemberList) Scan(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*l = GroupMemberList{}
+		return nil
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported GroupMemberList value type: %T", value)
+	}
+	if len(raw) == 0 {
+		*l = GroupMemberList{}
+		return nil
+	}
+	return json.Unmarshal(raw, (*[]GroupMember)(l))
+}
+
+// GroupParticipantsRequest 群组成员操作请求（移除、设为管理员、取消管理员）
+type GroupParticipantsRequest struct {
+	Participants []string `json:"participants" binding:"required,min=1"` // 成员ID，如 8613800000000@c.us
+}
+
+// RenameGroupRequest 群组改名请求
+type RenameGroupRequest struct {
+	Name string `json:"name" binding:"required"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// HardwareProfile 命名的硬件指纹配置。账号绑定后，每次启动Worker和登录都注入同一指纹，重启和重建容器后指纹不变
+type HardwareProfile struct {
+	ID           string `json:"id" gorm:"primaryKey"`
+	Name         string `json:"name" gorm:"uniqueIndex"`
+	HardwareInfo `gorm:"embedded"`
+	Accounts     int64     `json:"accounts" gorm:"-"` // 绑定该配置的账号数
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// HardwareProfileRequest 创建或替换硬件指纹配置
+type HardwareProfileRequest struct {
+	Name         string `json:"name" binding:"required,max=100"`
+	OS           string `json:"os" binding:"max=64"`
+	Browser      string `json:"browser" binding:"max=64"`
+	UserAgent    string `json:"user_agent" binding:"max=512"`
+	ScreenWidth  int    `json:"screen_width" binding:"omitempty,min=320,max=7680"`
+	ScreenHeight int    `json:"screen_height" binding:"omitempty,min=240,max=4320"`
+	Locale       string `json:"locale" binding:"max=35"`
+	Timezone     string `json:"timezone" binding:"max=64"`
+}
@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// StatusTransition 账号的一次状态变化记录
+type StatusTransition struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	AccountID string    `json:"account_id" gorm:"index"`
+	Previous  string    `json:"previous"`
+	Status    string    `json:"status"`
+	Source    string    `json:"source"`                                                  // 触发来源：api、worker、supervisor、reconcile、shutdown、chaos、breaker、ban_detector
+	Reason    string    `json:"reason,omitempty"`                                        // 触发原因，如启动失败的错误信息
+	RequestID string    `json:"request_id,omitempty"`                                    // 由API请求触发时的请求ID
+	Details   RawJSON   `json:"details,omitempty" gorm:"type:text" swaggertype:"object"` // 附加信息，如Worker启动失败时的诊断
+	CreatedAt time.Time `json:"created_at" gorm:"index"`
+}
+
+// TableName 指定表名
+func (StatusTransition) TableName() string {
+	return "status_history"
+}
+
+// WorkerStartupDiagnostics Worker容器未能在超时前就绪时采集的诊断信息
+type WorkerStartupDiagnostics struct {
+	Container      string   `json:"container"`
+	HostID         string   `json:"host_id,omitempty"`
+	TimeoutSeconds int      `json:"timeout_seconds"`
+	State          string   `json:"state,omitempty"`     // 容器状态：running、exited、restarting等
+	ExitCode       *int     `json:"exit_code,omitempty"` // 容器已退出时的退出码
+	OOMKilled      bool     `json:"oom_killed,omitempty"`
+	StateError     string   `json:"state_error,omitempty"`                  // docker记录的启动错误
+	Logs           string   `json:"logs,omitempty"`                         // 容器最近的日志
+	Inspect        RawJSON  `json:"inspect,omitempty" swaggertype:"object"` // docker inspect 输出
+	Errors         []string `json:"errors,omitempty"`                       // 采集诊断时的错误
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// HookPayload 生命周期钩子收到的账号信息：命令钩子从标准输入读取，Webhook钩子作为请求体
+type HookPayload struct {
+	Hook        string     `json:"hook"` // pre_spawn, post_ready, pre_delete, post_login
+	AccountID   string     `json:"account_id"`
+	Name        string     `json:"name,omitempty"`
+	Phone       string     `json:"phone,omitempty"`
+	Status      string     `json:"status"`
+	HostID      string     `json:"host_id,omitempty"`
+	Port        int        `json:"port,omitempty"`
+	ServiceURL  string     `json:"service_url,omitempty"`
+	ContainerID string     `json:"container_id,omitempty"`
+	Pool        string     `json:"pool,omitempty"`
+	Tags        StringList `json:"tags,omitempty"`
+	TenantID    string     `json:"tenant_id,omitempty"`
+	ProxyIP     string     `json:"proxy_ip,omitempty"`
+	ProxyPort   int        `json:"proxy_port,omitempty"`
+	Timestamp   time.Time  `json:"timestamp"`
+}
@@ -0,0 +1,94 @@
+package model
+
+import "time"
+
+// LocalHostID Master所在主机的ID，不需要注册，由 SCHEDULER_LOCAL_* 配置
+const LocalHostID = "local"
+
+// 主机状态
+const (
+	HostStatusUnknown = "unknown" // 注册后尚未完成心跳
+	HostStatusOnline  = "online"
+	HostStatusOffline = "offline"
+)
+
+// Host 运行Worker容器的主机，远程主机通过 fleet-agent 执行docker命令
+type Host struct {
+	ID              string     `json:"id" gorm:"primaryKey"`
+	Name            string     `json:"name"`
+	Address         string     `json:"address"`           // Master访问该主机上Worker映射端口使用的地址
+	AgentURL        string     `json:"agent_url"`         // fleet-agent 地址，本机为空
+	Region          string     `json:"region,omitempty"`  // 主机的出口地区，调度时与账号的 proxy_region 匹配
+	Token           Secret     `json:"-" gorm:"size:512"` // 调用 fleet-agent 的凭证
+	MaxWorkers      int        `json:"max_workers"`       // 最多放置的账号数，0表示不限制
+	Cordoned        bool       `json:"cordoned"`          // 停止调度新账号到该主机
+	Status          string     `json:"status" gorm:"-"`
+	CPUs            int        `json:"cpus" gorm:"-"`
+	MemoryTotal     int64      `json:"memory_total" gorm:"-"`     // 字节
+	MemoryAvailable int64      `json:"memory_available" gorm:"-"` // 字节
+	Load1           float64    `json:"load1" gorm:"-"`            // 1分钟平均负载
+	Containers      int        `json:"containers" gorm:"-"`       // 主机上的Worker容器数
+	Workers         int        `json:"workers" gorm:"-"`          // 放置在该主机上的账号数
+	LastSeenAt      *time.Time `json:"last_seen_at,omitempty" gorm:"-"`
+	LastError       string     `json:"last_error,omitempty" gorm:"-"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// CreateHostRequest 注册远程主机请求
+type CreateHostRequest struct {
+	ID         string `json:"id" binding:"required"`
+	Name       string `json:"name"`
+	Address    string `json:"address" binding:"required"`
+	AgentURL   string `json:"agent_url" binding:"required"`
+	Region     string `json:"region,omitempty"`
+	MaxWorkers int    `json:"max_workers"`
+}
+
+// UpdateHostRequest 修改主机，未提供的字段保持不变
+type UpdateHostRequest struct {
+	Name       *string `json:"name,omitempty"`
+	Address    *string `json:"address,omitempty"`
+	AgentURL   *string `json:"agent_url,omitempty"`
+	Region     *string `json:"region,omitempty"` // 只影响之后的调度，已放置的账号不会迁移
+	MaxWorkers *int    `json:"max_workers,omitempty"`
+	Cordoned   *bool   `json:"cordoned,omitempty"`
+}
+
+// RegisteredHost 新注册的主机，fleet-agent 凭证只在注册时返回一次
+type RegisteredHost struct {
+	Host
+	Token string `json:"token"`
+}
+
+// EvacuateHostResult 主机迁移任务的结果
+type EvacuateHostResult struct {
+	HostID   string   `json:"host_id"`
+	Moved    []string `json:"moved"`    // 已在其他主机上重建Worker的账号
+	Unplaced []string `json:"unplaced"` // 已停止的账号，下次启动时重新调度
+	Failed   []string `json:"failed"`
+}
+
+// AgentStatus fleet-agent 上报的主机资源
+type AgentStatus struct {
+	CPUs            int     `json:"cpus"`
+	MemoryTotal     int64   `json:"memory_total"`
+	MemoryAvailable int64   `json:"memory_available"`
+	Load1           float64 `json:"load1"`
+	Containers      int     `json:"containers"`
+}
+
+// AgentDockerRequest 请求 fleet-agent 执行一条docker命令
+type AgentDockerRequest struct {
+	Args           []string `json:"args"`
+	TimeoutSeconds int      `json:"timeout_seconds,omitempty"`
+	Stdin          []byte   `json:"stdin,omitempty"` // 命令的标准输入，JSON中为base64
+}
+
+// AgentDockerResult docker命令的执行结果
+type AgentDockerResult struct {
+	Stdout   string `json:"stdout"`
+	Stderr   string `json:"stderr"`
+	ExitCode int    `json:"exit_code"`
+	Error    string `json:"error,omitempty"` // 命令无法执行时的错误
+}
@@ -0,0 +1,13 @@
+package model
+
+import "time"
+
+// IdempotencyKey 发送接口的幂等键，保存首次请求的响应，客户端用相同的键重试时直接返回
+type IdempotencyKey struct {
+	Key         string `gorm:"column:idempotency_key;primaryKey;size:255"` // 调用方、路由和 Idempotency-Key 组合
+	RequestHash string `gorm:"size:64"`                                    // 请求体SHA-256，同一个键不能用于不同的请求
+	Status      int    // 响应状态码，0表示首次请求仍在处理
+	Response    string `gorm:"type:text"` // 响应JSON（未本地化）
+	CreatedAt   time.Time
+	ExpiresAt   time.Time `gorm:"index"`
+}
@@ -0,0 +1,43 @@
+package model
+
+import "time"
+
+// JanitorReport 一次清理任务的结果
+type JanitorReport struct {
+	StartedAt         time.Time  `json:"started_at"`
+	FinishedAt        *time.Time `json:"finished_at,omitempty"`
+	DryRun            bool       `json:"dry_run"`
+	ContainersRemoved []string   `json:"containers_removed"`
+	SessionsRemoved   []string   `json:"sessions_removed"`
+	BundlesRemoved    []string   `json:"diagnostic_bundles_removed"`
+	ImagesRemoved     []string   `json:"images_removed"` // 主机ID/镜像，悬空镜像以镜像ID表示
+	MediaRemoved      int64      `json:"media_files_removed"`
+	AuditRemoved      int64      `json:"audit_entries_removed"`
+	WorkerCalls       int64      `json:"worker_calls_removed"`
+	IdempotencyKeys   int64      `json:"idempotency_keys_removed"`
+	DeadLetters       int64      `json:"dead_letters_removed"`
+	ReclaimedBytes    int64      `json:"reclaimed_bytes"`
+	Errors            []string   `json:"errors,omitempty"`
+}
+
+// JanitorStatus 清理任务配置与累计回收空间
+type JanitorStatus struct {
+	Enabled              bool             `json:"enabled"`
+	IntervalMinutes      int              `json:"interval_minutes"`
+	RetentionDays        int              `json:"retention_days"`
+	SessionRetentionDays int              `json:"session_retention_days"`
+	Runs                 int              `json:"runs"`
+	TotalReclaimedBytes  int64            `json:"total_reclaimed_bytes"`
+	LastRun              *JanitorReport   `json:"last_run,omitempty"`
+	LastReconcile        *ReconcileReport `json:"last_reconcile,omitempty"`
+}
+
+// ReconcileReport 启动时Worker容器与账号表对账的结果
+type ReconcileReport struct {
+	StartedAt      time.Time  `json:"started_at"`
+	FinishedAt     *time.Time `json:"finished_at,omitempty"`
+	Adopted        []string   `json:"adopted"`         // 重新接管容器的账号
+	OrphansRemoved []string   `json:"orphans_removed"` // 删除的无主容器
+	PortsReleased  []string   `json:"ports_released"`  // 容器已不存在、释放端口的账号
+	Errors         []string   `json:"errors,omitempty"`
+}
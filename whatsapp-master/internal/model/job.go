@@ -0,0 +1,21 @@
+package model
+
+import "time"
+
+// Job 后台异步任务记录
+type Job struct {
+	ID          string     `json:"id" gorm:"primaryKey"`
+	Type        string     `json:"type" gorm:"index"`   // restart_workers, restart_account, bulk_send, message_retry, campaign, janitor, create_account, upgrade_workers, pull_image, backup
+	Status      string     `json:"status" gorm:"index"` // running, succeeded, failed, cancelled, interrupted
+	Stage       string     `json:"stage,omitempty"`     // 当前执行阶段，如创建账号时的 pulling_image, starting, waiting_ready
+	Detail      string     `json:"detail,omitempty"`    // 当前阶段的进度说明，如拉取镜像时已完成的镜像层
+	TenantID    string     `json:"tenant_id,omitempty" gorm:"index"`
+	Description string     `json:"description"`
+	Progress    int        `json:"progress"` // 已完成的工作项
+	Total       int        `json:"total"`    // 工作项总数，0表示未知
+	Result      RawJSON    `json:"result,omitempty" gorm:"type:text"`
+	Error       string     `json:"error,omitempty" gorm:"type:text"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	FinishedAt  *time.Time `json:"finished_at,omitempty"`
+}
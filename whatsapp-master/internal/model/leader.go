@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// LeaderLease 多副本的Leader租约，每个选举名称一行，持有者需在过期前续约
+type LeaderLease struct {
+	Name       string    `json:"name" gorm:"primaryKey"`
+	HolderID   string    `json:"holder_id"`
+	HolderURL  string    `json:"holder_url"`
+	AcquiredAt time.Time `json:"acquired_at"`
+	ExpiresAt  time.Time `json:"expires_at" gorm:"index"`
+}
+
+// TableName 指定表名
+func (LeaderLease) TableName() string {
+	return "leader_leases"
+}
+
+// LeaderStatus 本副本的选举状态
+type LeaderStatus struct {
+	Election       string     `json:"election"` // off 或 db
+	ReplicaID      string     `json:"replica_id"`
+	IsLeader       bool       `json:"is_leader"`
+	LeaderID       string     `json:"leader_id,omitempty"`
+	LeaderURL      string     `json:"leader_url,omitempty"`
+	LeaderSince    *time.Time `json:"leader_since,omitempty"`
+	LeaseExpiresAt *time.Time `json:"lease_expires_at,omitempty"`
+	LastSyncAt     *time.Time `json:"last_sync_at,omitempty"` // 作为Follower最近一次从数据库同步账号的时间
+}
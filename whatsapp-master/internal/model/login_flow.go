@@ -0,0 +1,51 @@
+package model
+
+import "time"
+
+// 手机号配对码登录流程的状态
+const (
+	LoginFlowRequestingCode = "requesting_code"  // 等待Worker生成配对码
+	LoginFlowWaitingForCode = "waiting_for_code" // 配对码已生成，等待在手机上输入
+	LoginFlowVerifying      = "verifying"        // 已在手机上输入配对码，等待WhatsApp确认
+	LoginFlowLoggedIn       = "logged_in"
+	LoginFlowFailed         = "failed"
+	LoginFlowCancelled      = "cancelled"
+)
+
+// LoginFlow 手机号配对码登录流程，Master跟踪配对码的有效期并在过期或Worker初始化失败时重试
+type LoginFlow struct {
+	AccountID     string     `json:"account_id"`
+	Phone         string     `json:"phone"`
+	State         string     `json:"state"` // requesting_code, waiting_for_code, verifying, logged_in, failed, cancelled
+	PairingCode   string     `json:"pairing_code,omitempty"`
+	CodeIssuedAt  *time.Time `json:"code_issued_at,omitempty"`
+	CodeExpiresAt *time.Time `json:"code_expires_at,omitempty"` // 超过该时间仍未登录则请求新的配对码
+	Attempt       int        `json:"attempt"`                   // 第几次请求配对码，从1开始
+	MaxAttempts   int        `json:"max_attempts"`
+	Deadline      time.Time  `json:"deadline"` // 整个流程的截止时间
+	Error         string     `json:"error,omitempty"`
+	StartedAt     time.Time  `json:"started_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	FinishedAt    *time.Time `json:"finished_at,omitempty"`
+}
+
+// Finished 流程是否已结束
+func (f *LoginFlow) Finished() bool {
+	return f.State == LoginFlowLoggedIn || f.State == LoginFlowFailed || f.State == LoginFlowCancelled
+}
+
+// PairingCode 账号当前的配对码
+type PairingCode struct {
+	AccountID   string     `json:"account_id"`
+	Phone       string     `json:"phone,omitempty"`
+	Code        string     `json:"code"`
+	State       string     `json:"state"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // 没有进行中的登录流程时为空
+	Attempt     int        `json:"attempt,omitempty"`
+	MaxAttempts int        `json:"max_attempts,omitempty"`
+}
+
+// LoginVerifyRequest 登录流程中提交的操作：entered 已在手机上输入配对码，resend 请求新的配对码，cancel 放弃登录
+type LoginVerifyRequest struct {
+	Action string `json:"action" binding:"required,oneof=entered resend cancel"`
+}
@@ -0,0 +1,8 @@
+package model
+
+// LogStreamQuery Worker日志流查询参数
+type LogStreamQuery struct {
+	Tail   *int   `form:"tail" binding:"omitempty,min=0,max=10000"` // 先输出最近多少行，默认100
+	Follow *bool  `form:"follow"`                                   // 是否持续跟随新日志，默认true
+	Since  string `form:"since"`                                    // 只输出此后的日志，RFC3339时间或相对时长（如 10m）
+}
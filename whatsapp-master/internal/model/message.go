@@ -0,0 +1,117 @@
+package model
+
+import "time"
+
+// Message 消息记录模型
+type Message struct {
+	ID              string     `json:"id" gorm:"primaryKey"`
+	AccountID       string     `json:"account_id" gorm:"index"`
+	Direction       string     `json:"direction" gorm:"index"` // inbound, outbound
+	Contact         string     `json:"contact" gorm:"index"`
+	Type            string     `json:"type"` // chat, image, document, audio ...
+	Body            string     `json:"body" gorm:"type:text"`
+	Preview         string     `json:"preview"`
+	MimeType        string     `json:"mime_type,omitempty"`
+	FileName        string     `json:"file_name,omitempty"`
+	MediaSize       int64      `json:"media_size,omitempty"`
+	MediaPath       string     `json:"-"`                   // 本地存储的媒体文件路径
+	Status          string     `json:"status" gorm:"index"` // sent, delivered, read, failed, retrying, received
+	Campaign        string     `json:"campaign,omitempty" gorm:"index"`
+	Error           string     `json:"error,omitempty" gorm:"type:text"`
+	Attempts        int        `json:"attempts,omitempty"` // 出站消息的发送尝试次数
+	WorkerMessageID string     `json:"worker_message_id,omitempty" gorm:"index"`
+	Timestamp       time.Time  `json:"timestamp" gorm:"index"`
+	ReadAt          *time.Time `json:"read_at,omitempty" gorm:"index"` // 入站消息在收件箱中标记已读的时间
+	DeliveredAt     *time.Time `json:"delivered_at,omitempty"`         // 出站消息送达对方设备的时间
+	SeenAt          *time.Time `json:"seen_at,omitempty"`              // 出站消息被对方阅读的时间
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Message) TableName() string {
+	return "messages"
+}
+
+// MessageStatus 出站消息的投递状态
+type MessageStatus struct {
+	ID              string     `json:"id"`
+	AccountID       string     `json:"account_id"`
+	Contact         string     `json:"contact"`
+	Status          string     `json:"status"` // sent, delivered, read, failed, retrying
+	WorkerMessageID string     `json:"worker_message_id,omitempty"`
+	Error           string     `json:"error,omitempty"`
+	SentAt          time.Time  `json:"sent_at"`
+	DeliveredAt     *time.Time `json:"delivered_at,omitempty"`
+	SeenAt          *time.Time `json:"seen_at,omitempty"`
+}
+
+// MessageReceipt Worker上报的消息回执，ack取值与whatsapp-web.js一致：1已发送到服务器，2已送达，3已读，4已播放
+type MessageReceipt struct {
+	ID  string `json:"id" binding:"required"` // Worker消息ID
+	Ack int    `json:"ack"`
+}
+
+// ReportReceiptsRequest Worker上报回执请求
+type ReportReceiptsRequest struct {
+	Receipts []MessageReceipt `json:"receipts" binding:"required,dive"`
+}
+
+// ReportReceiptsResult 回执处理结果
+type ReportReceiptsResult struct {
+	Updated int `json:"updated"` // 状态发生变化的消息数
+}
+
+// InboxFilter 收件箱查询条件
+type InboxFilter struct {
+	AccountIDs []string   // 限定账号范围，nil表示所有账号
+	Contact    string     // 联系人包含该文本
+	Unread     bool       // 只返回未读消息
+	Since      *time.Time // 消息时间起点
+	Until      *time.Time // 消息时间终点（不含）
+}
+
+// MarkInboxReadRequest 标记收件箱消息已读，至少需要一个条件
+type MarkInboxReadRequest struct {
+	MessageIDs []string   `json:"message_ids,omitempty"`
+	AccountID  string     `json:"account_id,omitempty"`
+	Contact    string     `json:"contact,omitempty"`
+	Until      *time.Time `json:"until,omitempty"` // 只标记该时间之前收到的消息
+}
+
+// MarkInboxReadResult 标记已读结果
+type MarkInboxReadResult struct {
+	Marked int64 `json:"marked"`
+}
+
+// RetryMessagesRequest 批量重试失败消息请求
+type RetryMessagesRequest struct {
+	AccountID string     `json:"account_id,omitempty"` // 为空时重试所有账号
+	Since     *time.Time `json:"since,omitempty"`      // 失败消息的时间窗口起点
+	Until     *time.Time `json:"until,omitempty"`      // 失败消息的时间窗口终点
+	Limit     int        `json:"limit,omitempty"`      // 本次最多重试条数
+}
+
+// RetryMessagesResult 批量重试结果
+type RetryMessagesResult struct {
+	JobID      string   `json:"job_id,omitempty"`
+	Queued     int      `json:"queued"`
+	MessageIDs []string `json:"message_ids"`
+}
+
+// MessagePreview 可直接渲染的消息预览
+type MessagePreview struct {
+	ID           string     `json:"id"`
+	AccountID    string     `json:"account_id"`
+	Direction    string     `json:"direction"`
+	Contact      string     `json:"contact"`
+	Type         string     `json:"type"`
+	HTML         string     `json:"html"` // 已转义的HTML片段
+	Text         string     `json:"text"` // 纯文本预览
+	MimeType     string     `json:"mime_type,omitempty"`
+	FileName     string     `json:"file_name,omitempty"`
+	MediaURL     string     `json:"media_url,omitempty"`     // 签名的媒体原文件链接
+	ThumbnailURL string     `json:"thumbnail_url,omitempty"` // 签名的缩略图链接（仅图片）
+	URLExpiresAt *time.Time `json:"url_expires_at,omitempty"`
+	Timestamp    time.Time  `json:"timestamp"`
+}
@@ -0,0 +1,55 @@
+package model
+
+import "time"
+
+// SchemaMigration gormigrate 记录的已执行迁移，ID为“版本号_名称”
+type SchemaMigration struct {
+	ID        string     `gorm:"primaryKey;size:255"`
+	AppliedAt *time.Time // 迁移事务提交后写入
+}
+
+// TableName 指定表名
+func (SchemaMigration) TableName() string {
+	return "schema_versions"
+}
+
+// LegacySchemaMigration 改用 gormigrate 之前记录已执行迁移的表，首次启动时导入 schema_versions 后不再写入
+type LegacySchemaMigration struct {
+	Version   int `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+// TableName 指定表名
+func (LegacySchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationStatus 一个迁移的执行状态
+type MigrationStatus struct {
+	Version    int        `json:"version"`
+	Name       string     `json:"name"`
+	Applied    bool       `json:"applied"`
+	AppliedAt  *time.Time `json:"applied_at,omitempty"`
+	Reversible bool       `json:"reversible"` // 是否可以回滚
+}
+
+// SchemaStatus 数据库结构版本与迁移列表
+type SchemaStatus struct {
+	CurrentVersion int               `json:"current_version"` // 数据库已执行的最高版本
+	LatestVersion  int               `json:"latest_version"`  // 当前程序包含的最高版本
+	Pending        int               `json:"pending"`
+	Migrations     []MigrationStatus `json:"migrations"`
+}
+
+// MigrationRollbackRequest 回滚迁移请求
+type MigrationRollbackRequest struct {
+	Steps int `json:"steps" binding:"omitempty,min=1"` // 回滚的迁移数，默认1
+}
+
+// MigrationResult 执行或回滚迁移的结果
+type MigrationResult struct {
+	Applied    []MigrationStatus `json:"applied,omitempty"`
+	RolledBack []MigrationStatus `json:"rolled_back,omitempty"`
+	Schema     *SchemaStatus     `json:"schema"`
+}
@@ -1,6 +1,8 @@
 package model
 
 import (
+	"maps"
+	"slices"
 	"time"
 
 	"gorm.io/gorm"
@@ -8,30 +10,155 @@ import (
 
 // Account WhatsApp账号模型
 type Account struct {
-	ID               string         `json:"id" gorm:"primaryKey"`
-	Name             string         `json:"name"`
-	Phone            string         `json:"phone"`
-	Status           string         `json:"status"` // creating, starting, running, stopping, stopped, error, logged_in, logged_out
-	ServiceURL       string         `json:"service_url"`
-	ContainerID      string         `json:"container_id,omitempty"`
-	PodName          string         `json:"pod_name,omitempty"`
-	Port             int            `json:"port"`
-	MessagesSent     int            `json:"messages_sent"`
-	MessagesReceived int            `json:"messages_received"`
-	LastActivity     *time.Time     `json:"last_activity,omitempty"`
-	CreatedAt        time.Time      `json:"created_at"`
-	UpdatedAt        time.Time      `json:"updated_at"`
-	DeletedAt        gorm.DeletedAt `json:"-" gorm:"index"`
+	ID                 string          `json:"id" gorm:"primaryKey"`
+	Name               string          `json:"name"`
+	Notes              string          `json:"notes,omitempty" gorm:"type:text"` // 运维备注
+	Phone              string          `json:"phone"`
+	Status             string          `json:"status"` // creating, starting, running, stopping, stopped, error, logged_in, logged_out, restarting, crash_looping, unreachable, banned
+	ServiceURL         string          `json:"service_url"`
+	ContainerID        string          `json:"container_id,omitempty"`
+	PodName            string          `json:"pod_name,omitempty"`
+	HostID             string          `json:"host_id" gorm:"index"` // Worker容器所在主机
+	Port               int             `json:"port"`
+	Tags               StringList      `json:"tags" gorm:"type:text"`
+	Pool               string          `json:"pool,omitempty" gorm:"index"`
+	TenantID           string          `json:"tenant_id,omitempty" gorm:"index"`
+	ProxyRegion        string          `json:"proxy_region,omitempty"`
+	Proxy              AccountProxy    `json:"proxy" gorm:"embedded"`                 // 绑定的代理，登录和Worker重建时使用
+	OwnerOperator      string          `json:"owner_operator,omitempty" gorm:"index"` // 负责的运营人员
+	OwnerTeam          string          `json:"owner_team,omitempty" gorm:"index"`     // 负责团队
+	OwnerEmail         string          `json:"owner_email,omitempty"`                 // 负责人邮箱
+	OwnerChannel       string          `json:"owner_channel,omitempty"`               // 告警通知Webhook
+	MessagesSent       int             `json:"messages_sent"`
+	MessagesReceived   int             `json:"messages_received"`
+	MediaSent          int             `json:"media_sent"`
+	MediaBytesSent     int64           `json:"media_bytes_sent"`
+	LastActivity       *time.Time      `json:"last_activity,omitempty"`
+	SessionStartedAt   *time.Time      `json:"session_started_at,omitempty"` // 当前登录会话开始时间
+	SessionDrops       int             `json:"session_drops"`                // 会话意外掉线次数
+	AvgSessionHours    float64         `json:"avg_session_hours"`            // 历史会话平均时长
+	SessionRefreshAt   *time.Time      `json:"session_refresh_at,omitempty"` // 最近一次主动刷新会话时间
+	KeepAliveOff       bool            `json:"keepalive_disabled"`           // 不参与会话保活和主动刷新
+	RestartCount       int             `json:"restart_count"`                // 自动恢复累计重启次数
+	LastRestartAt      *time.Time      `json:"last_restart_at,omitempty"`    // 最近一次自动重启时间
+	WorkerToken        Secret          `json:"-" gorm:"size:512"`            // Worker回调Master时使用的凭证
+	WorkerVersion      string          `json:"worker_version,omitempty"`     // Worker上报的版本
+	WorkerAPIVersion   int             `json:"worker_api_version,omitempty"` // Worker上报的API版本，旧版Worker不上报时为0
+	WorkerIncompatible bool            `json:"worker_incompatible"`          // Worker的API版本不在Master支持的范围内
+	SendLimit          int             `json:"send_limit,omitempty"`         // 账号每分钟允许的发送请求数，0表示使用全局默认
+	SendLimitBurst     int             `json:"send_limit_burst,omitempty"`   // 账号突发容量，0表示使用全局默认
+	Resources          WorkerResources `json:"resources" gorm:"embedded"`    // 账号单独设置的Worker资源限制，未设置的项使用全局默认
+	Runtime            WorkerRuntime   `json:"runtime" gorm:"embedded"`      // 账号额外的Worker环境变量、挂载卷和DNS，与全局默认合并
+	Disabled           bool            `json:"disabled" gorm:"index"`        // 维护模式：拒绝发送并排除在批量发送和活动之外，Worker保持运行
+	DisabledReason     string          `json:"disabled_reason,omitempty"`
+	DisabledAt         *time.Time      `json:"disabled_at,omitempty"`
+	WarmupProfile      string          `json:"warmup_profile,omitempty"`      // 预热方案，为空时使用默认方案，off表示不预热
+	WarmupStartedAt    *time.Time      `json:"warmup_started_at,omitempty"`   // 预热起点，为空时使用创建时间
+	TypingSimulation   bool            `json:"typing_simulation"`             // 发送文本前先显示正在输入并按消息长度等待
+	StatusPollSeconds  int             `json:"status_poll_seconds,omitempty"` // 账号稳定时轮询Worker状态的间隔（秒），0表示使用全局配置
+	HardwareProfileID  string          `json:"hardware_profile_id,omitempty"` // 绑定的硬件指纹配置，启动Worker和登录时注入
+	SessionPurgedAt    *time.Time      `json:"-"`                             // 账号删除后会话数据被清除的时间
+	CreatedAt          time.Time       `json:"created_at"`
+	UpdatedAt          time.Time       `json:"updated_at"`
+	DeletedAt          gorm.DeletedAt  `json:"-" gorm:"index"`
+}
+
+// Clone 返回账号的副本。Manager 内存中的账号在持有 mutex 时才会修改，返回给调用方前需复制，
+// 避免序列化响应时与状态更新并发读写
+func (a *Account) Clone() *Account {
+	clone := *a
+	clone.Tags = slices.Clone(a.Tags)
+	clone.Runtime.Env = maps.Clone(a.Runtime.Env)
+	clone.Runtime.Volumes = slices.Clone(a.Runtime.Volumes)
+	clone.Runtime.DNS = slices.Clone(a.Runtime.DNS)
+	return &clone
 }
 
 // LoginRequest 登录请求模型
 type LoginRequest struct {
-	AccountID    string                 `json:"account_id" binding:"required"`
-	LoginMethod  string                 `json:"login_method"` // qr, phone
-	Phone        string                 `json:"phone,omitempty"`
-	HardwareInfo map[string]interface{} `json:"hardware_info,omitempty"`
-	CacheLogin   bool                   `json:"cache_login"`
-	ProxyConfig  *ProxyConfig           `json:"proxy_config,omitempty"`
+	AccountID     string                 `json:"account_id" binding:"required"`
+	LoginMethod   string                 `json:"login_method"` // qr, phone
+	Phone         string                 `json:"phone,omitempty"`
+	HardwareInfo  map[string]interface{} `json:"hardware_info,omitempty"`
+	CacheLogin    bool                   `json:"cache_login"`
+	ProxyConfig   *ProxyConfig           `json:"proxy_config,omitempty"`
+	Tags          []string               `json:"tags,omitempty"`
+	Pool          string                 `json:"pool,omitempty"`
+	Owner         *AccountOwner          `json:"owner,omitempty"`
+	Resources     *WorkerResources       `json:"resources,omitempty"`      // 覆盖该账号Worker容器的资源限制
+	Runtime       *WorkerRuntime         `json:"runtime,omitempty"`        // 该账号Worker容器额外的环境变量、挂载卷和DNS
+	HostID        string                 `json:"host_id,omitempty"`        // 指定Worker运行的主机，为空时由调度器选择负载最低的主机
+	TenantID      string                 `json:"tenant_id,omitempty"`      // 使用租户API Key时由调用方租户决定
+	RestoreBackup bool                   `json:"restore_backup,omitempty"` // 启动Worker前用该账号最近一次成功的备份恢复会话目录
+
+	HardwareProfileID string `json:"hardware_profile_id,omitempty"` // 绑定的硬件指纹配置，代替 hardware_info
+}
+
+// CloneAccountRequest 复制账号的代理、标签、池、负责人、资源限制和运行配置创建新账号
+type CloneAccountRequest struct {
+	AccountID   string   `json:"account_id" binding:"required,max=100"`                 // 新账号ID
+	Name        string   `json:"name,omitempty" binding:"max=100"`                      // 新账号名称，默认为账号ID
+	Tags        []string `json:"tags,omitempty" binding:"omitempty,max=50,dive,max=64"` // 替换复制的标签
+	HostID      string   `json:"host_id,omitempty"`                                     // 指定Worker运行的主机，为空时由调度器选择
+	CopySession bool     `json:"copy_session,omitempty"`                                // 用源账号最近一次成功的备份初始化新账号的会话目录
+}
+
+// DeleteAccountResult 删除账号的结果
+type DeleteAccountResult struct {
+	AccountID      string `json:"account_id"`
+	SessionPurged  bool   `json:"session_purged"`            // 会话数据是否已清除
+	ReclaimedBytes int64  `json:"reclaimed_bytes,omitempty"` // 清除会话数据回收的空间，远程主机上无法统计
+	PurgeError     string `json:"purge_error,omitempty"`     // 清除失败时由清理任务按保留策略重试
+}
+
+// DisableAccountRequest 停用账号请求
+type DisableAccountRequest struct {
+	Reason string `json:"reason,omitempty"` // 停用原因，如 maintenance
+}
+
+// UpdateAccountRequest 修改账号名称、备注、标签、负责人和状态轮询间隔，未提供的字段保持不变
+type UpdateAccountRequest struct {
+	Name              *string       `json:"name" binding:"omitempty,min=1,max=100"`
+	Notes             *string       `json:"notes" binding:"omitempty,max=2000"`                // 空字符串清除备注
+	Tags              *[]string     `json:"tags" binding:"omitempty,max=50,dive,max=64"`       // 替换全部标签，空数组清除标签
+	Owner             *AccountOwner `json:"owner"`                                             // 替换负责人信息
+	StatusPollSeconds *int          `json:"status_poll_seconds" binding:"omitempty,max=86400"` // 账号稳定时的状态轮询间隔（秒），0恢复全局配置
+	HardwareProfileID *string       `json:"hardware_profile_id"`                               // 绑定硬件指纹配置，空字符串解除绑定；下次启动Worker或登录时生效
+}
+
+// AccountProxy 账号绑定的代理，密码只写不读
+type AccountProxy struct {
+	IP       string `json:"ip,omitempty" gorm:"column:proxy_ip"` // IP或主机名
+	Port     int    `json:"port,omitempty" gorm:"column:proxy_port"`
+	Username string `json:"username,omitempty" gorm:"column:proxy_username"`
+	Password Secret `json:"-" gorm:"column:proxy_password;size:512"`
+	Protocol string `json:"protocol,omitempty" gorm:"column:proxy_protocol"` // socks5、socks4、http、https，为空表示socks5
+}
+
+// SetAccountProxyRequest 设置账号代理绑定请求，ip为空表示解除绑定
+type SetAccountProxyRequest struct {
+	IP       string `json:"ip"`
+	Port     int    `json:"port" binding:"omitempty,min=1,max=65535"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Protocol string `json:"protocol,omitempty" binding:"omitempty,oneof=socks5 socks4 http https"`
+	Region   string `json:"region,omitempty"` // 同时更新 proxy_region
+	Apply    bool   `json:"apply,omitempty"`  // 立即切换运行中Worker的代理，会重启WhatsApp客户端
+}
+
+// WorkerResources Worker容器的资源限制
+type WorkerResources struct {
+	Memory        string `json:"memory,omitempty" gorm:"column:worker_memory"`                 // 内存上限，如 512m、1g
+	CPUs          string `json:"cpus,omitempty" gorm:"column:worker_cpus"`                     // CPU核数上限，如 1.5
+	PidsLimit     int    `json:"pids_limit,omitempty" gorm:"column:worker_pids_limit"`         // 进程数上限
+	RestartPolicy string `json:"restart_policy,omitempty" gorm:"column:worker_restart_policy"` // no、always、unless-stopped、on-failure[:N]
+}
+
+// WorkerRuntime Worker容器额外的运行参数，与 WORKER_ENV、WORKER_VOLUMES、WORKER_DNS 合并
+type WorkerRuntime struct {
+	Env     StringMap  `json:"env,omitempty" gorm:"column:worker_env;type:text"`         // 环境变量，如 TZ、LANG，同名时覆盖全局设置
+	Volumes StringList `json:"volumes,omitempty" gorm:"column:worker_volumes;type:text"` // docker -v 格式：宿主机路径或卷名:容器路径[:ro]，同一容器路径时覆盖全局设置
+	DNS     StringList `json:"dns,omitempty" gorm:"column:worker_dns;type:text"`         // DNS服务器，设置后替换全局设置
 }
 
 // PhoneLoginRequest 手机号登录请求模型
@@ -41,12 +168,40 @@ type PhoneLoginRequest struct {
 	HardwareInfo HardwareInfo `json:"hardware_info,omitempty"`
 	CacheLogin   bool         `json:"is_cache_login"`
 	ProxyConfig  ProxyConfig  `json:"socks5,omitempty"`
+
+	HardwareProfileID string `json:"hardware_profile_id,omitempty"` // 新建账号时绑定的硬件指纹配置
+}
+
+// QRLoginRequest 扫码登录请求模型
+type QRLoginRequest struct {
+	AccountID    string        `json:"account_id,omitempty"` // 为空时复用空闲Worker或自动生成账号ID
+	HardwareInfo HardwareInfo  `json:"hardware_info,omitempty"`
+	CacheLogin   bool          `json:"is_cache_login"`
+	ProxyConfig  ProxyConfig   `json:"socks5,omitempty"`
+	Tags         []string      `json:"tags,omitempty"`
+	Pool         string        `json:"pool,omitempty"`
+	Owner        *AccountOwner `json:"owner,omitempty"`
+	TenantID     string        `json:"tenant_id,omitempty"` // 使用租户API Key时由调用方租户决定
+
+	HardwareProfileID string `json:"hardware_profile_id,omitempty"` // 新建账号时绑定的硬件指纹配置
+}
+
+// QRLoginResult 扫码登录发起结果
+type QRLoginResult struct {
+	Account     *Account               `json:"account"`
+	LoginResult map[string]interface{} `json:"login_result,omitempty"`
+	QRCodeURL   string                 `json:"qr_code_url"` // 渲染好的二维码图片地址
 }
 
-// HardwareInfo 硬件信息模型
+// HardwareInfo 硬件指纹：Worker浏览器上报的系统、浏览器、UA、屏幕分辨率、语言和时区
 type HardwareInfo struct {
-	OS      string `json:"os"`
-	Browser string `json:"browser"`
+	OS           string `json:"os"`
+	Browser      string `json:"browser"`
+	UserAgent    string `json:"user_agent,omitempty"`
+	ScreenWidth  int    `json:"screen_width,omitempty"`
+	ScreenHeight int    `json:"screen_height,omitempty"`
+	Locale       string `json:"locale,omitempty"`   // BCP 47 语言标签，如 zh-CN
+	Timezone     string `json:"timezone,omitempty"` // IANA时区，如 Asia/Shanghai
 }
 
 // ProxyConfig 代理配置模型
@@ -55,6 +210,7 @@ type ProxyConfig struct {
 	Port         int    `json:"port"`
 	Username     string `json:"username,omitempty"`
 	Password     string `json:"password,omitempty"`
+	Protocol     string `json:"protocol,omitempty"` // socks5、socks4、http、https，默认socks5
 	Region       string `json:"region,omitempty"`
 	ResourceCode string `json:"resource_code,omitempty"`
 	ResourceName string `json:"resource_name,omitempty"`
@@ -62,9 +218,41 @@ type ProxyConfig struct {
 
 // MessageRequest 消息请求模型
 type MessageRequest struct {
-	AccountID string `json:"account_id" binding:"required"`
-	Contact   string `json:"contact" binding:"required"`
-	Message   string `json:"message" binding:"required"`
+	AccountID  string            `json:"account_id" binding:"required"`
+	Contact    string            `json:"contact" binding:"required"`
+	Message    string            `json:"message" binding:"required"`
+	Variables  map[string]string `json:"variables,omitempty"`   // 模板变量，替换消息中的 {{key}}，{{contact}} 始终可用
+	Campaign   string            `json:"campaign,omitempty"`    // 营销活动标识，用于统计
+	TrackLinks *bool             `json:"track_links,omitempty"` // 是否改写链接以跟踪点击，为空时使用全局配置
+}
+
+// SendPreview 模拟发送（dry_run）的结果：通过了哪些校验以及将要发送的内容
+type SendPreview struct {
+	DryRun              bool               `json:"dry_run"`
+	AccountID           string             `json:"account_id"`
+	AccountStatus       string             `json:"account_status"`
+	Contact             string             `json:"contact"` // 规范化后的收件人
+	Message             string             `json:"message"` // 渲染模板后的正文，链接尚未改写
+	Campaign            string             `json:"campaign,omitempty"`
+	TrackedLinks        []string           `json:"tracked_links,omitempty"`        // 发送时会改写为跟踪地址的链接
+	UnresolvedVariables []string           `json:"unresolved_variables,omitempty"` // 没有对应变量、会原样发送的占位符
+	ContentViolations   []ContentViolation `json:"content_violations,omitempty"`   // 违反的内容策略，真实发送时会被拒绝或放入审核队列
+	Quota               *SendQuota         `json:"quota,omitempty"`                // 不含本次发送的额度
+}
+
+// MediaMessageRequest 媒体消息请求模型（multipart 上传或 JSON 中的 base64/URL）
+type MediaMessageRequest struct {
+	AccountID string `json:"account_id" form:"account_id" binding:"required"`
+	Contact   string `json:"contact" form:"contact" binding:"required"`
+	MediaType string `json:"media_type" form:"media_type" binding:"required,oneof=image document audio"`
+	Caption   string `json:"caption,omitempty" form:"caption"`
+	Filename  string `json:"filename,omitempty" form:"filename"`
+	MimeType  string `json:"mime_type,omitempty" form:"mime_type"`
+	Data      string `json:"data,omitempty" form:"-"` // base64 编码的文件内容
+	URL       string `json:"url,omitempty" form:"url"`
+	Voice     bool   `json:"voice,omitempty" form:"voice"` // 音频作为语音消息发送
+
+	StorageKey string `json:"storage_key,omitempty" form:"storage_key"` // 对象存储中已上传文件的键，见 POST /storage/objects
 }
 
 // AddContactRequest 添加联系人请求模型
@@ -77,20 +265,48 @@ type AddContactRequest struct {
 // APIResponse 统一API响应模型
 type APIResponse struct {
 	Success bool        `json:"success"`
-	Message string      `json:"message"`
+	Code    string      `json:"code,omitempty"` // 稳定的消息代码，不随语言变化
+	Message string      `json:"message"`        // 按请求语言本地化的消息
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+	Warning string      `json:"warning,omitempty"`
+	Meta    *ListMeta   `json:"meta,omitempty"`
+}
+
+// ListQuery 列表接口通用查询参数
+type ListQuery struct {
+	Limit   int               // 每页条数，0 表示不限制
+	Offset  int               // 由游标解码得到的偏移量
+	Sort    []string          // 排序字段，"-" 前缀表示降序
+	Filters map[string]string // 字段过滤，逗号分隔表示或关系
+}
+
+// ListMeta 列表分页元数据
+type ListMeta struct {
+	Total      int    `json:"total"`
+	Limit      int    `json:"limit,omitempty"`
+	NextCursor string `json:"next_cursor,omitempty"`
 }
 
 // HealthStatus 健康状态模型
 type HealthStatus struct {
-	Status        string     `json:"status"`
-	Uptime        string     `json:"uptime"`
-	Accounts      []*Account `json:"accounts"`
-	TotalCount    int        `json:"total_count"`
-	RunningCount  int        `json:"running_count"`
-	LoggedInCount int        `json:"logged_in_count"`
-	SystemInfo    SystemInfo `json:"system_info"`
+	Status        string         `json:"status"` // healthy、degraded 或 unhealthy，取各项检查中最差的结果
+	Uptime        string         `json:"uptime"`
+	Accounts      []*Account     `json:"accounts"`
+	TotalCount    int            `json:"total_count"`
+	RunningCount  int            `json:"running_count"`
+	LoggedInCount int            `json:"logged_in_count"`
+	Checks        []*HealthCheck `json:"checks"`
+	SystemInfo    SystemInfo     `json:"system_info"`
+}
+
+// HealthCheck 单项健康检查的结果
+type HealthCheck struct {
+	Name       string                 `json:"name"`   // database、docker、disk、port_pool、hosts、worker_versions、goroutines
+	Status     string                 `json:"status"` // healthy、degraded 或 unhealthy
+	Message    string                 `json:"message,omitempty"`
+	Details    map[string]interface{} `json:"details,omitempty"`
+	DurationMs int64                  `json:"duration_ms"`
 }
 
 // SystemInfo 系统信息模型
@@ -98,6 +314,29 @@ type SystemInfo struct {
 	WorkerMode  string `json:"worker_mode"`
 	Environment string `json:"environment"`
 	Version     string `json:"version"`
+	GoVersion   string `json:"go_version"`
+	Goroutines  int    `json:"goroutines"`
+}
+
+// StatsBucket 某一维度下的统计数据
+type StatsBucket struct {
+	TotalWorkers     int                   `json:"totalWorkers"`
+	OnlineWorkers    int                   `json:"onlineWorkers"`
+	LoggedInWorkers  int                   `json:"loggedInWorkers"`
+	MessagesSent     int                   `json:"messagesSent"`
+	MessagesReceived int                   `json:"messagesReceived"`
+	Availability     map[string]*SLAWindow `json:"availability,omitempty"` // 24h, 7d, 30d，由分组内账号的在线和故障时间合计
+}
+
+// FleetStats 集群统计模型（全局 + 按维度分组）
+type FleetStats struct {
+	TotalWorkers   int                                `json:"totalWorkers"`
+	OnlineWorkers  int                                `json:"onlineWorkers"`
+	TodayMessages  int                                `json:"todayMessages"`
+	ActiveContacts int                                `json:"activeContacts"`
+	Breakdowns     map[string]map[string]*StatsBucket `json:"breakdowns"`             // tag, pool, tenant, proxy_region, host
+	Availability   map[string]*SLAWindow              `json:"availability,omitempty"` // 24h, 7d, 30d，全部账号的可用性
+	Series         []*StatsPoint                      `json:"series,omitempty"`       // 指定 from/to/granularity 时返回的时间序列
 }
 
 // AccountStats 账号统计模型
@@ -141,3 +380,17 @@ type ServiceInstance struct {
 func (Account) TableName() string {
 	return "accounts"
 }
+
+// WorkerConcurrencyMetrics 账号转发到Worker的并发请求统计
+type WorkerConcurrencyMetrics struct {
+	AccountID string `json:"account_id"`
+	InFlight  int    `json:"in_flight"` // 正在转发的请求
+	Queued    int    `json:"queued"`    // 等待空位的请求
+	Rejected  int64  `json:"rejected"`  // 队列已满或等待超时而返回429的请求
+}
+
+// WorkerConnectionMetrics Master到Worker连接池的连接复用统计
+type WorkerConnectionMetrics struct {
+	Reused int64 `json:"reused"` // 复用空闲连接的请求
+	Opened int64 `json:"opened"` // 新建连接的请求
+}
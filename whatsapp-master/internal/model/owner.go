@@ -0,0 +1,37 @@
+package model
+
+import "time"
+
+// AccountOwner 账号负责人信息，用于账号分配、按负责人过滤和故障告警路由
+type AccountOwner struct {
+	Operator string `json:"operator"` // 负责的运营人员
+	Team     string `json:"team"`
+	Email    string `json:"email" binding:"omitempty,email"`
+	Channel  string `json:"channel"` // 告警通知Webhook地址，为空时使用全局 ALERT_WEBHOOK_URL
+}
+
+// AssignAccountsRequest 批量将账号分配给运营人员或团队，未提供的字段保持不变，空字符串取消分配
+type AssignAccountsRequest struct {
+	AccountIDs []string `json:"account_ids" binding:"required,min=1,max=1000"`
+	Operator   *string  `json:"operator,omitempty"`
+	Team       *string  `json:"team,omitempty"`
+}
+
+// OwnerSummary 负责人及其名下的账号数
+type OwnerSummary struct {
+	Owner    string `json:"owner"`
+	Kind     string `json:"kind"` // operator, team
+	Accounts int    `json:"accounts"`
+}
+
+// Incident 发送到告警通道的故障通知
+type Incident struct {
+	Text       string      `json:"text"` // 便于Slack等IM的Incoming Webhook直接展示
+	Event      string      `json:"event"`
+	AccountID  string      `json:"account_id"`
+	Status     string      `json:"status"`
+	OwnerTeam  string      `json:"owner_team,omitempty"`
+	OwnerEmail string      `json:"owner_email,omitempty"`
+	Data       interface{} `json:"data,omitempty"`
+	Timestamp  time.Time   `json:"timestamp"`
+}
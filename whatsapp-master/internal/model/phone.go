@@ -0,0 +1,111 @@
+package model
+
+import (
+	"fmt"
+
+	"whatsapp-aggregator/internal/validation"
+)
+
+// PhoneNormalizer 包含号码字段的请求，绑定后将号码规范化为 E.164 的纯数字形式，
+// 格式错误时返回 validation.Errors，列出每个出错的字段
+type PhoneNormalizer interface {
+	NormalizePhones(countryCode string) error
+}
+
+// NormalizePhones 规范化登录号码，账号ID使用规范化后的号码
+func (r *PhoneLoginRequest) NormalizePhones(countryCode string) error {
+	var errs validation.Errors
+	if number, err := validation.WhatsAppNumber(r.LoginPhone, countryCode); err != nil {
+		errs.Add("login_phone", r.LoginPhone, err)
+	} else {
+		r.LoginPhone = number
+	}
+	return errs.Err()
+}
+
+// NormalizePhones 规范化收件人号码
+func (r *MessageRequest) NormalizePhones(countryCode string) error {
+	var errs validation.Errors
+	normalizeContact(&errs, "contact", &r.Contact, countryCode)
+	return errs.Err()
+}
+
+// NormalizePhones 规范化收件人号码
+func (r *MediaMessageRequest) NormalizePhones(countryCode string) error {
+	var errs validation.Errors
+	normalizeContact(&errs, "contact", &r.Contact, countryCode)
+	return errs.Err()
+}
+
+// NormalizePhones 规范化所有收件人号码
+func (r *BulkSendRequest) NormalizePhones(countryCode string) error {
+	var errs validation.Errors
+	normalizeRecipients(&errs, r.Recipients, countryCode)
+	return errs.Err()
+}
+
+// NormalizePhones 规范化所有收件人号码
+func (r *CreateCampaignRequest) NormalizePhones(countryCode string) error {
+	var errs validation.Errors
+	normalizeRecipients(&errs, r.Recipients, countryCode)
+	return errs.Err()
+}
+
+// NormalizePhones 规范化所有联系人号码
+func (r *BroadcastRequest) NormalizePhones(countryCode string) error {
+	var errs validation.Errors
+	for i := range r.Contacts {
+		normalizeContact(&errs, fmt.Sprintf("contacts[%d]", i), &r.Contacts[i], countryCode)
+	}
+	return errs.Err()
+}
+
+// NormalizePhones 规范化联系人号码，添加联系人只接受号码
+func (r *AddContactRequest) NormalizePhones(countryCode string) error {
+	var errs validation.Errors
+	if number, err := validation.WhatsAppNumber(r.Phone, countryCode); err != nil {
+		errs.Add("phone", r.Phone, err)
+	} else {
+		r.Phone = number
+	}
+	return errs.Err()
+}
+
+// NormalizePhones 规范化要创建账号的号码，账号ID使用规范化后的号码
+func (r *BulkCreateAccountsRequest) NormalizePhones(countryCode string) error {
+	var errs validation.Errors
+	for i, phone := range r.Phones {
+		if number, err := validation.WhatsAppNumber(phone, countryCode); err != nil {
+			errs.Add(fmt.Sprintf("phones[%d]", i), phone, err)
+		} else {
+			r.Phones[i] = number
+		}
+	}
+	return errs.Err()
+}
+
+// NormalizePhones 规范化要加入抑制名单的号码
+func (r *AddSuppressionRequest) NormalizePhones(countryCode string) error {
+	var errs validation.Errors
+	for i := range r.Contacts {
+		normalizeContact(&errs, fmt.Sprintf("contacts[%d]", i), &r.Contacts[i], countryCode)
+	}
+	return errs.Err()
+}
+
+// normalizeRecipients 规范化批量发送和活动的收件人
+func normalizeRecipients(errs *validation.Errors, recipients []BulkRecipient, countryCode string) {
+	for i := range recipients {
+		normalizeContact(errs, fmt.Sprintf("recipients[%d].contact", i), &recipients[i].Contact, countryCode)
+	}
+}
+
+// normalizeContact 规范化单个收件人，失败时保留原值并记录错误
+func normalizeContact(errs *validation.Errors, field string, contact *string, countryCode string) {
+	normalized, err := validation.NormalizeContact(*contact, countryCode)
+	if err != nil {
+		errs.Add(field, *contact, err)
+		return
+	}
+	*contact = normalized
+}
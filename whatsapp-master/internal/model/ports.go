@@ -0,0 +1,35 @@
+package model
+
+import "time"
+
+// 端口状态
+const (
+	PortStateAllocated = "allocated" // 已分配给账号
+	PortStateOccupied  = "occupied"  // 被其他进程占用，分配时跳过
+)
+
+// PortAllocation 单个端口的分配状态
+type PortAllocation struct {
+	Port      int        `json:"port"`
+	State     string     `json:"state"`
+	AccountID string     `json:"account_id,omitempty"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"` // 最近一次探测到被占用的时间
+}
+
+// PortRange 一段连续的Worker端口，包含首尾
+type PortRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// PortPoolStatus Worker端口池状态
+type PortPoolStatus struct {
+	StartPort int              `json:"start_port"` // 第一段的起始端口
+	EndPort   int              `json:"end_port"`   // 最后一段的结束端口
+	Ranges    []PortRange      `json:"ranges"`
+	Total     int              `json:"total"`
+	Allocated int              `json:"allocated"`
+	Occupied  int              `json:"occupied"`
+	Available int              `json:"available"`
+	Ports     []PortAllocation `json:"ports"`
+}
@@ -0,0 +1,40 @@
+package model
+
+import "time"
+
+// TypingRequest 在与联系人的会话中显示输入状态
+type TypingRequest struct {
+	Contact    string `json:"contact" binding:"required"`
+	State      string `json:"state" binding:"omitempty,oneof=typing recording paused"` // 默认typing，paused清除状态
+	DurationMs int    `json:"duration_ms" binding:"omitempty,min=0,max=60000"`         // 大于0时到时自动清除，否则保持到发送消息或WhatsApp超时
+}
+
+// ChatState 已发送的输入状态
+type ChatState struct {
+	AccountID  string `json:"account_id"`
+	Contact    string `json:"contact"`
+	State      string `json:"state"`
+	DurationMs int    `json:"duration_ms"`
+}
+
+// ContactPresence 联系人的在线状态
+type ContactPresence struct {
+	AccountID string     `json:"account_id"`
+	Contact   string     `json:"contact"`
+	Online    *bool      `json:"online"`              // 对方隐藏在线状态或尚未收到时为null
+	State     string     `json:"state,omitempty"`     // available, unavailable, typing, recording
+	LastSeen  *time.Time `json:"last_seen,omitempty"` // 对方公开时的最后在线时间
+	CheckedAt time.Time  `json:"checked_at"`
+}
+
+// WorkerPresence Worker /api/presence 返回的在线状态
+type WorkerPresence struct {
+	Online   *bool  `json:"online"`
+	State    string `json:"state"`
+	LastSeen int64  `json:"last_seen"` // 毫秒时间戳
+}
+
+// SetTypingSimulationRequest 开启或关闭账号的模拟输入
+type SetTypingSimulationRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
@@ -0,0 +1,36 @@
+package model
+
+// BulkCreateAccountsRequest 批量创建账号请求：每个号码创建一个账号（账号ID为规范化后的号码），其余设置所有账号共用
+type BulkCreateAccountsRequest struct {
+	Phones       []string               `json:"phones" binding:"required,min=1,max=500,dive,required"`
+	Proxies      []ProxyConfig          `json:"proxies,omitempty"` // 代理池，按号码顺序轮流分配给账号
+	HardwareInfo map[string]interface{} `json:"hardware_info,omitempty"`
+	Tags         []string               `json:"tags,omitempty"`
+	Pool         string                 `json:"pool,omitempty"`
+	Owner        *AccountOwner          `json:"owner,omitempty"`
+	Resources    *WorkerResources       `json:"resources,omitempty"`
+	Runtime      *WorkerRuntime         `json:"runtime,omitempty"`
+	HostID       string                 `json:"host_id,omitempty"`
+	Concurrency  int                    `json:"concurrency,omitempty"` // 同时启动的Worker数，不超过 WORKER_PROVISION_CONCURRENCY
+	TenantID     string                 `json:"tenant_id,omitempty"`   // 使用租户API Key时由调用方租户决定
+
+	HardwareProfileID string `json:"hardware_profile_id,omitempty"` // 所有账号绑定同一硬件指纹配置
+}
+
+// BulkAccountResult 批量创建中单个账号的结果
+type BulkAccountResult struct {
+	Phone     string   `json:"phone"`
+	AccountID string   `json:"account_id"`
+	Status    string   `json:"status"` // pending, created, failed, skipped
+	Error     string   `json:"error,omitempty"`
+	Account   *Account `json:"account,omitempty"`
+}
+
+// BulkCreateAccountsResult 批量创建账号的结果，返回时各账号为pending，完成后的结果保存在任务中
+type BulkCreateAccountsResult struct {
+	Job      *Job                 `json:"job,omitempty"`
+	Created  int                  `json:"created"`
+	Failed   int                  `json:"failed"`
+	Skipped  int                  `json:"skipped"`
+	Accounts []*BulkAccountResult `json:"accounts"`
+}
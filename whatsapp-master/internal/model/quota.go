@@ -0,0 +1,54 @@
+package model
+
+import "time"
+
+// SendQuota 账号发送限流与每日配额的当前状态，Limit为0表示不限制
+type SendQuota struct {
+	AccountID       string     `json:"account_id"`
+	RateLimit       int        `json:"rate_limit"`
+	RateRemaining   int        `json:"rate_remaining"`
+	RateReset       *time.Time `json:"rate_reset,omitempty"`
+	DailyQuota      int        `json:"daily_quota"`
+	QuotaRemaining  int        `json:"quota_remaining"`
+	QuotaReset      *time.Time `json:"quota_reset,omitempty"`
+	WarmupLimit     int        `json:"warmup_limit"` // 预热阶段的当日上限
+	WarmupRemaining int        `json:"warmup_remaining"`
+	Warning         string     `json:"warning,omitempty"`
+}
+
+// AccountSendLimit 账号级发送接口限流设置，0表示使用全局默认
+type AccountSendLimit struct {
+	PerMinute int `json:"per_minute" binding:"min=0"`
+	Burst     int `json:"burst" binding:"min=0"`
+}
+
+// WarmupOff 账号不参与预热
+const WarmupOff = "off"
+
+// WarmupStatus 账号预热进度与当日额度
+type WarmupStatus struct {
+	AccountID  string       `json:"account_id"`
+	Enabled    bool         `json:"enabled"` // 预热是否对该账号生效
+	Profile    string       `json:"profile"`
+	StartedAt  time.Time    `json:"started_at"`
+	AgeDays    int          `json:"age_days"`    // 从0开始的预热天数
+	DailyLimit int          `json:"daily_limit"` // 当日允许发送条数，0表示不限制
+	SentToday  int          `json:"sent_today"`
+	Remaining  int          `json:"remaining"`
+	Completed  bool         `json:"completed"` // 已进入不限制的阶段
+	NextStepAt *time.Time   `json:"next_step_at,omitempty"`
+	NextLimit  *int         `json:"next_limit,omitempty"` // 下一阶段的每日上限，0表示不限制
+	Steps      []WarmupStep `json:"steps,omitempty"`
+}
+
+// WarmupStep 预热阶段：从第 FromDay 天起每天最多发送 PerDay 条，0表示不限制
+type WarmupStep struct {
+	FromDay int `json:"from_day"`
+	PerDay  int `json:"per_day"`
+}
+
+// SetWarmupRequest 修改账号预热方案
+type SetWarmupRequest struct {
+	Profile *string `json:"profile,omitempty"` // 方案名，空字符串表示默认方案，off表示不预热
+	Restart bool    `json:"restart,omitempty"` // 从今天起重新开始预热
+}
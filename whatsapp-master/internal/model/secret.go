@@ -0,0 +1,196 @@
+package model
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+// secretPrefix 加密后数据库中保存的格式：enc:v1:<密钥ID>:<base64(nonce+密文)>
+const secretPrefix = "enc:v1:"
+
+// redactedSecret 凭证在JSON和日志中的显示值
+const redactedSecret = "[REDACTED]"
+
+// ErrSecretKey 无法解密数据库中的凭证：未配置加密密钥或密钥不匹配
+var ErrSecretKey = errors.New("secret encryption key unavailable")
+
+// Secret 数据库中以AES-GCM加密保存的凭证（代理密码、Worker和主机令牌、Webhook密钥、告警通道凭证），
+// 读写数据库时自动加解密，序列化为JSON和写入日志时脱敏；使用明文时需显式转换为string
+type Secret string
+
+// secretKeyring 加密密钥，current 用于加密，其余密钥只用于解密轮换前写入的数据
+type secretKeyring struct {
+	currentID string
+	keys      map[string]cipher.AEAD
+}
+
+var (
+	secretKeysMutex sync.RWMutex
+	secretKeys      *secretKeyring
+)
+
+// SetSecretKeys 设置凭证加密密钥，previous 为轮换前的旧密钥；current为空时新写入的凭证保持明文
+func SetSecretKeys(current []byte, previous ...[]byte) error {
+	keyring := &secretKeyring{keys: make(map[string]cipher.AEAD)}
+	for i, key := range append([][]byte{current}, previous...) {
+		if len(key) == 0 {
+			continue
+		}
+		aead, err := newSecretAEAD(key)
+		if err != nil {
+			return err
+		}
+		id := secretKeyID(key)
+		keyring.keys[id] = aead
+		if i == 0 {
+			keyring.currentID = id
+		}
+	}
+
+	secretKeysMutex.Lock()
+	secretKeys = keyring
+	secretKeysMutex.Unlock()
+	return nil
+}
+
+// SecretKeyID 当前加密密钥的ID，未配置密钥时返回空字符串
+func SecretKeyID() string {
+	secretKeysMutex.RLock()
+	defer secretKeysMutex.RUnlock()
+	if secretKeys == nil {
+		return ""
+	}
+	return secretKeys.currentID
+}
+
+// SecretPrefix 使用当前密钥加密的凭证在数据库中的前缀，用于找出需要重新加密的记录
+func SecretPrefix() string {
+	return secretPrefix + SecretKeyID() + ":"
+}
+
+// ParseSecretKey 解析32字节的AES-256密钥，支持base64和十六进制编码
+func ParseSecretKey(value string) ([]byte, error) {
+	value = strings.TrimSpace(value)
+	if key, err := hex.DecodeString(value); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	if key, err := base64.StdEncoding.DecodeString(value); err == nil && len(key) == 32 {
+		return key, nil
+	}
+	return nil, fmt.Errorf("secret encryption key must be 32 bytes encoded as base64 or hex")
+}
+
+func newSecretAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("invalid secret encryption key: %v", err)
+	}
+	return cipher.NewGCM(block)
+}
+
+// secretKeyID 密钥指纹，写入密文以便轮换后找到对应的密钥
+func secretKeyID(key []byte) string {
+	sum := sha256.Sum256(key)
+	return hex.EncodeToString(sum[:4])
+}
+
+// Value 实现 driver.Valuer，配置了密钥时加密后写入
+func (s Secret) Value() (driver.Value, error) {
+	if s == "" {
+		return "", nil
+	}
+	secretKeysMutex.RLock()
+	keyring := secretKeys
+	secretKeysMutex.RUnlock()
+	if keyring == nil || keyring.currentID == "" {
+		return string(s), nil
+	}
+
+	aead := keyring.keys[keyring.currentID]
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("encrypt secret: %v", err)
+	}
+	sealed := aead.Seal(nonce, nonce, []byte(s), nil)
+	return secretPrefix + keyring.currentID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Scan 实现 sql.Scanner，未加密的旧数据原样读取
+func (s *Secret) Scan(value interface{}) error {
+	var raw string
+	switch v := value.(type) {
+	case nil:
+		*s = ""
+		return nil
+	case string:
+		raw = v
+	case []byte:
+		raw = string(v)
+	default:
+		return fmt.Errorf("unsupported Secret value type: %T", value)
+	}
+
+	if !strings.HasPrefix(raw, secretPrefix) {
+		*s = Secret(raw)
+		return nil
+	}
+	plain, err := decryptSecret(strings.TrimPrefix(raw, secretPrefix))
+	if err != nil {
+		return err
+	}
+	*s = Secret(plain)
+	return nil
+}
+
+func decryptSecret(payload string) (string, error) {
+	id, encoded, ok := strings.Cut(payload, ":")
+	if !ok {
+		return "", fmt.Errorf("decrypt secret: malformed value")
+	}
+
+	secretKeysMutex.RLock()
+	var aead cipher.AEAD
+	if secretKeys != nil {
+		aead = secretKeys.keys[id]
+	}
+	secretKeysMutex.RUnlock()
+	if aead == nil {
+		return "", fmt.Errorf("decrypt secret with key %s: %w", id, ErrSecretKey)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("decrypt secret: malformed value")
+	}
+	plain, err := aead.Open(nil, sealed[:aead.NonceSize()], sealed[aead.NonceSize():], nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypt secret with key %s: %w", id, ErrSecretKey)
+	}
+	return string(plain), nil
+}
+
+// MarshalJSON 实现 json.Marshaler，凭证不会出现在API响应中
+func (s Secret) MarshalJSON() ([]byte, error) {
+	if s == "" {
+		return []byte(`""`), nil
+	}
+	return []byte(`"` + redactedSecret + `"`), nil
+}
+
+// LogValue 实现 slog.LogValuer，凭证不会写入日志
+func (s Secret) LogValue() slog.Value {
+	if s == "" {
+		return slog.StringValue("")
+	}
+	return slog.StringValue(redactedSecret)
+}
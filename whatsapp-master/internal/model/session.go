@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// SessionHealth 账号登录会话健康度及过期预测
+type SessionHealth struct {
+	AccountID          string     `json:"account_id"`
+	Status             string     `json:"status"`
+	SessionStartedAt   *time.Time `json:"session_started_at,omitempty"`
+	SessionAgeHours    float64    `json:"session_age_hours"`
+	SessionDrops       int        `json:"session_drops"`
+	AvgSessionHours    float64    `json:"avg_session_hours"`
+	ExpectedExpiryAt   *time.Time `json:"expected_expiry_at,omitempty"`
+	ReloginRecommended bool       `json:"relogin_recommended"`
+	Reason             string     `json:"reason,omitempty"`
+	SessionRefreshAt   *time.Time `json:"session_refresh_at,omitempty"`
+}
+
+// SetKeepAliveRequest 开启或关闭账号的会话保活
+type SetKeepAliveRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"` // false表示不参与会话保活和主动刷新
+}
@@ -0,0 +1,29 @@
+package model
+
+import "time"
+
+// AccountSLA 账号的可用性统计，由状态历史计算
+type AccountSLA struct {
+	AccountID string                `json:"account_id"`
+	Status    string                `json:"status"`
+	Windows   map[string]*SLAWindow `json:"windows"` // 24h, 7d, 30d
+	Outages   []*SLAOutage          `json:"outages"` // 最近30天的故障时段，最新的在前
+}
+
+// SLAWindow 一个统计窗口内的可用性，停止等计划内状态和没有状态记录的时间不计入
+type SLAWindow struct {
+	Availability    *float64 `json:"availability"` // 在线时间占比（百分比），窗口内没有可统计的时间时为null
+	UptimeSeconds   int64    `json:"uptime_seconds"`
+	DowntimeSeconds int64    `json:"downtime_seconds"`
+	Outages         int      `json:"outages"` // 与窗口有重叠的故障次数
+}
+
+// SLAOutage 一次故障时段：从离开 logged_in 到恢复或被主动停止
+type SLAOutage struct {
+	Start           time.Time  `json:"start"`
+	End             *time.Time `json:"end,omitempty"` // 故障仍在持续时为空
+	Status          string     `json:"status"`        // 故障开始时的状态
+	Source          string     `json:"source,omitempty"`
+	Reason          string     `json:"reason,omitempty"`
+	DurationSeconds int64      `json:"duration_seconds"`
+}
@@ -0,0 +1,11 @@
+package model
+
+import "time"
+
+// StoredObject 写入对象存储的文件及其限时下载地址
+type StoredObject struct {
+	Key       string     `json:"key"`                  // 对象键，发送媒体和导入联系人时通过 storage_key 引用
+	Size      int64      `json:"size,omitempty"`       // 字节数，只在写入时返回
+	URL       string     `json:"url,omitempty"`        // 签名下载地址，无需API凭证即可访问
+	ExpiresAt *time.Time `json:"expires_at,omitempty"` // 下载地址过期时间
+}
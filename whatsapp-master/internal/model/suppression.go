@@ -0,0 +1,20 @@
+package model
+
+// 退订记录来源
+const (
+	OptOutSourceKeyword = "keyword" // 联系人回复了退订关键字
+	OptOutSourceManual  = "manual"  // 通过API手动添加
+)
+
+// AddSuppressionRequest 手动将号码加入抑制名单
+type AddSuppressionRequest struct {
+	Contacts []string `json:"contacts" binding:"required,min=1,max=1000,dive,required"`
+	Reason   string   `json:"reason,omitempty" binding:"max=500"`
+}
+
+// SuppressionStatus 号码是否在抑制名单中及其全部退订记录
+type SuppressionStatus struct {
+	Contact    string    `json:"contact"`
+	Suppressed bool      `json:"suppressed"`
+	Entries    []*OptOut `json:"entries"`
+}
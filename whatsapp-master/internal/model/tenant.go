@@ -0,0 +1,60 @@
+package model
+
+import "time"
+
+// Tenant 租户，账号、消息和配额按租户隔离
+type Tenant struct {
+	ID                string       `json:"id" gorm:"primaryKey"`
+	Name              string       `json:"name"`
+	MaxWorkers        int          `json:"max_workers"`          // 最多可创建的账号数，0表示不限制
+	MaxMessagesPerDay int          `json:"max_messages_per_day"` // 所有账号每日发送总数上限，0表示不限制
+	Usage             *TenantUsage `json:"usage,omitempty" gorm:"-"`
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+}
+
+// TenantUsage 租户当前用量
+type TenantUsage struct {
+	Workers       int `json:"workers"`
+	MessagesToday int `json:"messages_today"`
+}
+
+// TenantAPIKey 租户API Key，只保存哈希
+type TenantAPIKey struct {
+	ID         string     `json:"id" gorm:"primaryKey"`
+	TenantID   string     `json:"tenant_id" gorm:"index"`
+	Name       string     `json:"name"`
+	Owner      string     `json:"owner,omitempty"` // 绑定的运营人员或团队，开启 API_KEY_OWNER_ENFORCEMENT 后只能通过其名下账号发送
+	Prefix     string     `json:"prefix"`          // Key的前几位，用于识别
+	KeyHash    string     `json:"-" gorm:"uniqueIndex"`
+	LastUsedAt *time.Time `json:"last_used_at,omitempty"` // 最近使用时间，每分钟最多更新一次
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `json:"created_at"`
+}
+
+// CreateTenantRequest 创建租户请求
+type CreateTenantRequest struct {
+	ID                string `json:"id" binding:"required"`
+	Name              string `json:"name"`
+	MaxWorkers        int    `json:"max_workers"`
+	MaxMessagesPerDay int    `json:"max_messages_per_day"`
+}
+
+// UpdateTenantRequest 修改租户名称或配额，未提供的字段保持不变
+type UpdateTenantRequest struct {
+	Name              *string `json:"name,omitempty"`
+	MaxWorkers        *int    `json:"max_workers,omitempty"`
+	MaxMessagesPerDay *int    `json:"max_messages_per_day,omitempty"`
+}
+
+// CreateAPIKeyRequest 创建API Key请求
+type CreateAPIKeyRequest struct {
+	Name  string `json:"name"`
+	Owner string `json:"owner,omitempty"` // 运营人员或团队名
+}
+
+// CreatedAPIKey 新建的API Key，明文Key只在创建时返回一次
+type CreatedAPIKey struct {
+	TenantAPIKey
+	Key string `json:"key"`
+}
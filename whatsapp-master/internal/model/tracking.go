@@ -0,0 +1,52 @@
+package model
+
+import "time"
+
+// TrackedLink 出站消息中被改写的跟踪链接
+type TrackedLink struct {
+	ID             string     `json:"id" gorm:"primaryKey"` // 短码
+	MessageID      string     `json:"message_id" gorm:"index"`
+	AccountID      string     `json:"account_id" gorm:"index"`
+	Campaign       string     `json:"campaign,omitempty" gorm:"index"`
+	Contact        string     `json:"contact"`
+	URL            string     `json:"url" gorm:"type:text"`
+	Clicks         int        `json:"clicks"`
+	FirstClickedAt *time.Time `json:"first_clicked_at,omitempty"`
+	LastClickedAt  *time.Time `json:"last_clicked_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+}
+
+// TableName 指定表名
+func (TrackedLink) TableName() string {
+	return "tracked_links"
+}
+
+// LinkClick 单次点击记录
+type LinkClick struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	LinkID    string    `json:"link_id" gorm:"index"`
+	IP        string    `json:"ip"`
+	UserAgent string    `json:"user_agent" gorm:"type:text"`
+	ClickedAt time.Time `json:"clicked_at"`
+}
+
+// TableName 指定表名
+func (LinkClick) TableName() string {
+	return "link_clicks"
+}
+
+// ClickStats 点击统计
+type ClickStats struct {
+	Messages         int     `json:"messages"`         // 含跟踪链接的消息数
+	Links            int     `json:"links"`            // 跟踪链接数
+	Clicks           int     `json:"clicks"`           // 总点击次数
+	ClickedMessages  int     `json:"clicked_messages"` // 至少有一次点击的消息数
+	ClickThroughRate float64 `json:"click_through_rate"`
+}
+
+// ClickStatsReport 按活动和账号分组的点击统计
+type ClickStatsReport struct {
+	Total      *ClickStats            `json:"total"`
+	ByCampaign map[string]*ClickStats `json:"by_campaign"`
+	ByAccount  map[string]*ClickStats `json:"by_account"`
+}
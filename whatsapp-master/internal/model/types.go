@@ -0,0 +1,100 @@
+package model
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+)
+
+// StringList 以JSON格式存储在数据库中的字符串列表
+type StringList []string
+
+// Value 实现 driver.Valuer
+func (l StringList) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal([]string(l))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan 实现 sql.Scanner
+func (l *StringList) Scan(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*l = StringList{}
+		return nil
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported StringList value type: %T", value)
+	}
+	if len(raw) == 0 {
+		*l = StringList{}
+		return nil
+	}
+	return json.Unmarshal(raw, (*[]string)(l))
+}
+
+// Contains 判断列表是否包含指定值
+func (l StringList) Contains(value string) bool {
+	for _, v := range l {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// StringMap 以JSON格式存储在数据库中的字符串映射
+type StringMap map[string]string
+
+// Value 实现 driver.Valuer
+func (m StringMap) Value() (driver.Value, error) {
+	if m == nil {
+		return "{}", nil
+	}
+	b, err := json.Marshal(map[string]string(m))
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// Scan 实现 sql.Scanner
+func (m *StringMap) Scan(value interface{}) error {
+	var raw []byte
+	switch v := value.(type) {
+	case nil:
+		*m = StringMap{}
+		return nil
+	case string:
+		raw = []byte(v)
+	case []byte:
+		raw = v
+	default:
+		return fmt.Errorf("unsupported StringMap value type: %T", value)
+	}
+	if len(raw) == 0 {
+		*m = StringMap{}
+		return nil
+	}
+	return json.Unmarshal(raw, (*map[string]string)(m))
+}
+
+// RawJSON 以文本存储、原样输出的JSON
+type RawJSON string
+
+// MarshalJSON 实现 json.Marshaler
+func (r RawJSON) MarshalJSON() ([]byte, error) {
+	if r == "" {
+		return []byte("null"), nil
+	}
+	return []byte(r), nil
+}
@@ -0,0 +1,34 @@
+package model
+
+// UpgradeWorkersRequest Worker镜像滚动升级请求
+type UpgradeWorkersRequest struct {
+	Image         string `json:"image" binding:"required"` // 新镜像，如 whatsapp-node-service:1.4.0
+	BatchSize     int    `json:"batch_size,omitempty"`     // 每批升级的Worker数量，默认 WORKER_UPGRADE_BATCH_SIZE
+	SettleSeconds int    `json:"settle_seconds,omitempty"` // 每批启动后等待多久再做健康检查，默认 WORKER_UPGRADE_SETTLE_SECONDS
+}
+
+// UpgradeWorkersResult 滚动升级结果
+type UpgradeWorkersResult struct {
+	PreviousImage  string   `json:"previous_image"`
+	Image          string   `json:"image"`
+	Batches        int      `json:"batches"`  // 已开始的批次数
+	Upgraded       []string `json:"upgraded"` // 升级成功的账号，回滚后为空
+	Failed         []string `json:"failed,omitempty"`
+	RolledBack     bool     `json:"rolled_back"`
+	RollbackFailed []string `json:"rollback_failed,omitempty"`
+}
+
+// PullImageRequest 预拉取Worker镜像请求
+type PullImageRequest struct {
+	Image   string   `json:"image,omitempty"`    // 为空时使用当前Worker镜像
+	HostIDs []string `json:"host_ids,omitempty"` // 为空时拉取到所有未离线的主机
+	Prune   bool     `json:"prune,omitempty"`    // 拉取成功后清理该主机上Worker镜像的旧版本和悬空镜像
+}
+
+// PullImageResult 预拉取任务结果
+type PullImageResult struct {
+	Image         string            `json:"image"`
+	Pulled        []string          `json:"pulled"`                   // 拉取成功的主机
+	Failed        map[string]string `json:"failed,omitempty"`         // 主机ID -> 错误
+	ImagesRemoved []string          `json:"images_removed,omitempty"` // prune 时删除的镜像，格式 主机ID/镜像
+}
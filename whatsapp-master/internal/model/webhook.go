@@ -0,0 +1,70 @@
+package model
+
+import "time"
+
+// Webhook 注册的事件Webhook，事件以JSON POST到URL
+type Webhook struct {
+	ID           string             `json:"id" gorm:"primaryKey"`
+	URL          string             `json:"url"`
+	Events       StringList         `json:"events" gorm:"type:text"` // 订阅的事件类型，为空表示所有事件
+	Filter       WebhookFilter      `json:"filter" gorm:"embedded"`  // 投递前的过滤条件
+	IncludeMedia bool               `json:"include_media"`           // 消息事件附带签名的媒体链接
+	Retry        WebhookRetryPolicy `json:"retry" gorm:"embedded"`   // 投递失败时的重试策略
+	Secret       Secret             `json:"-" gorm:"size:512"`       // 用于 X-Fleet-Signature 的HMAC-SHA256签名密钥
+	TenantID     string             `json:"tenant_id,omitempty" gorm:"index"`
+	CreatedAt    time.Time          `json:"created_at"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+}
+
+// TableName 指定表名
+func (Webhook) TableName() string {
+	return "webhooks"
+}
+
+// WebhookFilter Webhook的过滤条件，所有设置了的条件都满足才投递；
+// 方向、联系人和关键词只作用于带有这些字段的消息事件，其他事件不受限制
+type WebhookFilter struct {
+	AccountIDs StringList `json:"account_ids,omitempty" gorm:"column:filter_account_ids;type:text"` // 只投递这些账号的事件
+	Directions StringList `json:"directions,omitempty" gorm:"column:filter_directions;type:text"`   // inbound、outbound
+	Contacts   StringList `json:"contacts,omitempty" gorm:"column:filter_contacts;type:text"`       // 联系人匹配任一模式，* 匹配任意字符，如 86138*
+	Keywords   StringList `json:"keywords,omitempty" gorm:"column:filter_keywords;type:text"`       // 消息内容包含任一关键词（不区分大小写）
+}
+
+// WebhookRetryPolicy Webhook投递失败（连接失败或5xx）时的重试策略，0表示使用全局默认
+type WebhookRetryPolicy struct {
+	MaxAttempts  int `json:"max_attempts,omitempty" gorm:"column:retry_max_attempts"`     // 最多尝试次数，默认 WEBHOOK_MAX_ATTEMPTS
+	BackoffMs    int `json:"backoff_ms,omitempty" gorm:"column:retry_backoff_ms"`         // 首次重试前的等待，之后每次翻倍，默认1000
+	MaxBackoffMs int `json:"max_backoff_ms,omitempty" gorm:"column:retry_max_backoff_ms"` // 单次等待的上限，默认不限制
+}
+
+// CreateWebhookRequest 注册Webhook请求
+type CreateWebhookRequest struct {
+	URL          string              `json:"url" binding:"required,url"`
+	Events       []string            `json:"events,omitempty"` // 如 message.delivered、message.read，为空表示所有事件
+	Secret       string              `json:"secret,omitempty"` // 为空时自动生成
+	Filter       *WebhookFilter      `json:"filter,omitempty"`
+	IncludeMedia bool                `json:"include_media"`
+	Retry        *WebhookRetryPolicy `json:"retry,omitempty"`
+}
+
+// UpdateWebhookRequest 修改Webhook订阅的事件、过滤条件、媒体选项和重试策略，未提供的字段保持不变
+type UpdateWebhookRequest struct {
+	Events       *[]string           `json:"events"`
+	Filter       *WebhookFilter      `json:"filter"` // 替换全部过滤条件
+	IncludeMedia *bool               `json:"include_media"`
+	Retry        *WebhookRetryPolicy `json:"retry"` // 替换重试策略
+}
+
+// CreatedWebhook 新建的Webhook，签名密钥只在创建时返回一次
+type CreatedWebhook struct {
+	Webhook
+	Secret string `json:"secret"`
+}
+
+// WebhookMessage include_media 的Webhook收到的消息事件数据，附带签名的媒体链接
+type WebhookMessage struct {
+	*Message
+	MediaURL       string     `json:"media_url,omitempty"`
+	ThumbnailURL   string     `json:"thumbnail_url,omitempty"`
+	MediaExpiresAt *time.Time `json:"media_expires_at,omitempty"`
+}
@@ -0,0 +1,114 @@
+package model
+
+// 以下模型对应Worker接口的请求和响应，字段名与Worker保持一致
+
+// WorkerContact Worker返回的联系人（/api/contacts、/api/contacts/add）
+type WorkerContact struct {
+	ID          string `json:"id"` // WhatsApp ID，如 8613800000000@c.us
+	Name        string `json:"name"`
+	Number      string `json:"number"`
+	PushName    string `json:"pushname,omitempty"`
+	IsGroup     bool   `json:"isGroup"`
+	IsMyContact bool   `json:"isMyContact"`
+}
+
+// CreateGroupRequest 创建群组请求（/api/groups/create）
+type CreateGroupRequest struct {
+	Name         string   `json:"name" binding:"required"`
+	Participants []string `json:"participants" binding:"required,min=1,dive,required"` // 成员ID，如 8613800000000@c.us
+}
+
+// CreateGroupResult Worker创建群组的结果
+type CreateGroupResult struct {
+	GID          interface{}            `json:"gid" swaggertype:"object"` // 新群组ID，Worker返回 {_serialized: ...}
+	Title        string                 `json:"title,omitempty"`
+	Participants map[string]interface{} `json:"participants,omitempty"` // 每个成员的添加结果
+}
+
+// AddGroupParticipantsRequest 添加群组成员请求（/api/groups/participants/add）
+type AddGroupParticipantsRequest struct {
+	GroupID      string   `json:"groupId" binding:"required"`
+	Participants []string `json:"participants" binding:"required,min=1,dive,required"`
+}
+
+// SwitchProxyRequest 切换代理请求（/api/proxy/switch），ip和host二选一
+type SwitchProxyRequest struct {
+	IP       string `json:"ip,omitempty" binding:"required_without=Host"`
+	Host     string `json:"host,omitempty"`
+	Port     int    `json:"port" binding:"required,min=1,max=65535"`
+	Username string `json:"username,omitempty"`
+	Password string `json:"password,omitempty"`
+	Protocol string `json:"protocol,omitempty" binding:"omitempty,oneof=socks5 socks4 http https"` // 默认socks5
+}
+
+// WorkerProxyConfig Worker当前使用的代理，密码已隐藏
+type WorkerProxyConfig struct {
+	IP     string `json:"ip"`
+	Port   string `json:"port"`
+	User   string `json:"user,omitempty"`
+	Pwd    string `json:"pwd,omitempty"`
+	Scheme string `json:"scheme,omitempty"`
+}
+
+// ProxyStatus Worker的代理状态（/api/proxy/status）
+type ProxyStatus struct {
+	Success        bool               `json:"success"`
+	Enabled        bool               `json:"enabled"`
+	Config         *WorkerProxyConfig `json:"config"`
+	LocalForwarder string             `json:"local_forwarder,omitempty"`
+}
+
+// ExternalIPResult 通过代理访问外网的出口IP（/api/proxy/external-ip）
+type ExternalIPResult struct {
+	Success bool        `json:"success"`
+	IP      interface{} `json:"ip" swaggertype:"string"` // 查询失败时为错误描述
+}
+
+// ProxyDetectResult 代理连通性检测结果（/api/proxy/detect）
+type ProxyDetectResult struct {
+	Success  bool        `json:"success"`
+	Detected bool        `json:"detected"`
+	IP       interface{} `json:"ip" swaggertype:"string"`
+}
+
+// WorkerMessage Worker收到的入站消息（/api/messages 和 message 回调事件）
+type WorkerMessage struct {
+	ID        string `json:"id"`
+	From      string `json:"from" binding:"required"`
+	To        string `json:"to,omitempty"`
+	Body      string `json:"body"`
+	Type      string `json:"type"`
+	Timestamp int64  `json:"timestamp"` // 毫秒
+}
+
+// Worker回调事件类型
+const (
+	WorkerEventStatus  = "status"  // 登录状态变化，status 为Worker的状态
+	WorkerEventQRCode  = "qr"      // 新的登录二维码，qr_code 为空表示二维码已失效
+	WorkerEventMessage = "message" // 收到消息
+	WorkerEventLogout  = "logout"  // 会话被注销或断开，reason 为原因
+
+	WorkerEventSessionWaiting = "session_waiting" // 开启会话加密时Worker的会话尚未解密，如容器被Docker重启后tmpfs已清空
+)
+
+// WorkerEvent Worker推送给Master的事件
+type WorkerEvent struct {
+	Type      string         `json:"type" binding:"required,oneof=status qr message logout session_waiting"`
+	Status    string         `json:"status,omitempty" binding:"required_if=Type status"`
+	QRCode    string         `json:"qr_code,omitempty"`
+	Message   *WorkerMessage `json:"message,omitempty" binding:"required_if=Type message"`
+	Reason    string         `json:"reason,omitempty"`
+	Timestamp int64          `json:"timestamp,omitempty"` // 事件发生时间（毫秒），晚于它的状态事件已处理时忽略
+}
+
+// WorkerEventsRequest Worker推送事件请求，按发生顺序排列
+type WorkerEventsRequest struct {
+	Events []WorkerEvent `json:"events" binding:"required,min=1,max=500,dive"`
+}
+
+// WorkerEventsResult 事件处理结果
+type WorkerEventsResult struct {
+	Accepted      int `json:"accepted"`
+	Ignored       int `json:"ignored"`        // 过期的状态事件
+	MessagesAdded int `json:"messages_added"` // 新保存的消息，重复的消息不计入
+}
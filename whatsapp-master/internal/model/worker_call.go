@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// WorkerCall 一次对Worker的API调用，用于追溯账号被封前调用过哪些接口。只保存请求体的大小和哈希，不保存内容
+type WorkerCall struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	AccountID   string    `json:"account_id" gorm:"index"`
+	RequestID   string    `json:"request_id,omitempty" gorm:"index"`
+	Source      string    `json:"source" gorm:"index"` // proxy：代理转发的API请求；master：Master发起的调用，如发送消息和登录
+	Method      string    `json:"method"`
+	Path        string    `json:"path" gorm:"index"` // Worker接口路径，如 /api/send-message
+	Status      int       `json:"status"`            // Worker响应状态码，未连上Worker时为0
+	LatencyMs   int64     `json:"latency_ms"`
+	PayloadSize int64     `json:"payload_size"`           // 请求体字节数
+	PayloadHash string    `json:"payload_hash,omitempty"` // 请求体前64KiB的SHA-256，相同请求的哈希相同
+	Error       string    `json:"error,omitempty" gorm:"type:text"`
+	CreatedAt   time.Time `json:"created_at" gorm:"index"`
+}
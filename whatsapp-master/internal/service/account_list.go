@@ -0,0 +1,96 @@
+package service
+
+import (
+	"encoding/json"
+	"strings"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// accountColumns 账号列表允许过滤和排序的字段
+var accountColumns = map[string]string{
+	"id":                  "id",
+	"name":                "name",
+	"phone":               "phone",
+	"status":              "status",
+	"pool":                "pool",
+	"tenant_id":           "tenant_id",
+	"host_id":             "host_id",
+	"port":                "port",
+	"proxy_region":        "proxy_region",
+	"owner_operator":      "owner_operator",
+	"owner_team":          "owner_team",
+	"owner_email":         "owner_email",
+	"worker_version":      "worker_version",
+	"worker_incompatible": "worker_incompatible",
+	"disabled":            "disabled",
+	"messages_sent":       "messages_sent",
+	"messages_received":   "messages_received",
+	"restart_count":       "restart_count",
+	"last_activity":       "last_activity",
+	"created_at":          "created_at",
+	"updated_at":          "updated_at",
+}
+
+// AccountListScope 账号列表的调用方范围
+type AccountListScope struct {
+	TenantID string // 租户API Key调用时只返回该租户的账号
+	Owner    string // 只返回负责人或负责团队为该值的账号
+}
+
+// QueryAccounts 在数据库中过滤、排序和分页账号，返回内存中的账号对象（包含最新的运行状态）和过滤后的总数。
+// filter[tags] 匹配包含任一标签的账号；未指定排序时按创建时间升序，并以ID作为相同值的次序
+func (m *Manager) QueryAccounts(q *model.ListQuery, scope AccountListScope) ([]*model.Account, int64, error) {
+	db := m.db.Model(&model.Account{})
+	if scope.TenantID != "" {
+		db = db.Where("tenant_id = ?", scope.TenantID)
+	}
+	if scope.Owner != "" {
+		db = db.Where("owner_operator = ? OR owner_team = ?", scope.Owner, scope.Owner)
+	}
+
+	query := *q
+	query.Filters = make(map[string]string, len(q.Filters))
+	for field, value := range q.Filters {
+		if field != "tags" {
+			query.Filters[field] = value
+			continue
+		}
+		conditions := make([]string, 0)
+		args := make([]interface{}, 0)
+		for _, tag := range strings.Split(value, ",") {
+			if tag = strings.TrimSpace(tag); tag != "" {
+				// tags 以JSON数组存储，按带引号的元素匹配
+				quoted, _ := json.Marshal(tag)
+				conditions = append(conditions, "tags LIKE ?")
+				args = append(args, "%"+string(quoted)+"%")
+			}
+		}
+		if len(conditions) > 0 {
+			db = db.Where(strings.Join(conditions, " OR "), args...)
+		}
+	}
+	query.Sort = append([]string{}, q.Sort...)
+	if len(query.Sort) == 0 {
+		query.Sort = []string{"created_at"}
+	}
+	query.Sort = append(query.Sort, "id")
+
+	rows := make([]*model.Account, 0)
+	total, err := findWithListQuery(db, &query, accountColumns, "", &rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	accounts := make([]*model.Account, 0, len(rows))
+	for _, row := range rows {
+		if account, exists := m.accounts[row.ID]; exists {
+			accounts = append(accounts, account)
+			continue
+		}
+		accounts = append(accounts, row)
+	}
+	return accounts, total, nil
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"whatsapp-aggregator/internal/logging"
+	"whatsapp-aggregator/internal/model"
+)
+
+// SetAccountProxy 更新账号绑定的代理，之后的登录和Worker重建使用该代理；apply为true时同时切换运行中Worker的代理。
+// 绑定已保存但切换失败时返回账号和错误
+func (m *Manager) SetAccountProxy(ctx context.Context, accountID string, req *model.SetAccountProxyRequest) (*model.Account, error) {
+	proxy := model.AccountProxy{
+		IP:       strings.TrimSpace(req.IP),
+		Port:     req.Port,
+		Username: req.Username,
+		Password: model.Secret(req.Password),
+		Protocol: req.Protocol,
+	}
+	if proxy.IP == "" {
+		if req.Apply {
+			return nil, fmt.Errorf("apply requires a proxy ip")
+		}
+		proxy = model.AccountProxy{}
+	} else if proxy.Port == 0 {
+		return nil, fmt.Errorf("proxy port is required")
+	}
+
+	account, err := m.bindAccountProxy(accountID, proxy, req.Region)
+	if err != nil {
+		return nil, err
+	}
+	logging.FromContext(ctx).Info("Account proxy updated", "account_id", accountID, "proxy", proxy.IP, "port", proxy.Port)
+
+	if req.Apply {
+		if _, err := m.postToWorker(ctx, account, "/api/proxy/switch", model.SwitchProxyRequest{
+			IP:       proxy.IP,
+			Port:     proxy.Port,
+			Username: proxy.Username,
+			Password: string(proxy.Password),
+			Protocol: proxy.Protocol,
+		}); err != nil {
+			return account, fmt.Errorf("failed to apply proxy to worker: %w", err)
+		}
+	}
+	return account, nil
+}
+
+// BindSwitchedProxy 记录通过Worker切换的代理，保证Worker重建后继续使用
+func (m *Manager) BindSwitchedProxy(accountID string, req *model.SwitchProxyRequest) error {
+	_, err := m.bindAccountProxy(accountID, model.AccountProxy{
+		IP:       valueOrDefault(req.IP, req.Host),
+		Port:     req.Port,
+		Username: req.Username,
+		Password: model.Secret(req.Password),
+		Protocol: req.Protocol,
+	}, "")
+	return err
+}
+
+// bindAccountProxy 保存账号的代理绑定，region不为空时同时更新代理地区
+func (m *Manager) bindAccountProxy(accountID string, proxy model.AccountProxy, region string) (*model.Account, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return nil, fmt.Errorf("account %s not found", accountID)
+	}
+
+	updates := map[string]interface{}{
+		"proxy_ip":       proxy.IP,
+		"proxy_port":     proxy.Port,
+		"proxy_username": proxy.Username,
+		"proxy_password": proxy.Password,
+		"proxy_protocol": proxy.Protocol,
+	}
+	if region != "" {
+		updates["proxy_region"] = region
+	}
+	if err := m.db.Model(account).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("failed to update account proxy: %v", err)
+	}
+	account.Proxy = proxy
+	if region != "" {
+		account.ProxyRegion = region
+	}
+	return account.Clone(), nil
+}
+
+// accountProxyFromConfig 将登录请求中的代理配置转换为代理绑定，未指定代理时返回false
+func accountProxyFromConfig(cfg *model.ProxyConfig) (model.AccountProxy, bool) {
+	if cfg == nil || strings.TrimSpace(cfg.IP) == "" || cfg.Port <= 0 {
+		return model.AccountProxy{}, false
+	}
+	return model.AccountProxy{
+		IP:       strings.TrimSpace(cfg.IP),
+		Port:     cfg.Port,
+		Username: cfg.Username,
+		Password: model.Secret(cfg.Password),
+		Protocol: cfg.Protocol,
+	}, true
+}
+
+// loginProxy 登录使用的代理：请求指定代理时保存为账号绑定，否则使用已绑定的代理
+func (m *Manager) loginProxy(ctx context.Context, account *model.Account, cfg *model.ProxyConfig) model.AccountProxy {
+	if proxy, ok := accountProxyFromConfig(cfg); ok {
+		if _, err := m.bindAccountProxy(account.ID, proxy, cfg.Region); err != nil {
+			logging.FromContext(ctx).Warn("Failed to store login proxy", "account_id", account.ID, "error", err)
+		}
+		return proxy
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return account.Proxy
+}
+
+// workerSocks5 将代理绑定转换为Worker登录接口的 socks5 字段，未绑定代理时返回nil
+func workerSocks5(proxy model.AccountProxy) map[string]interface{} {
+	if proxy.IP == "" {
+		return nil
+	}
+	return map[string]interface{}{
+		"ip":       proxy.IP,
+		"port":     proxy.Port,
+		"username": proxy.Username,
+		"password": string(proxy.Password),
+		"scheme":   valueOrDefault(proxy.Protocol, "socks5"),
+	}
+}
+
+// workerProxyEnv Worker容器的代理环境变量，Worker启动时自动恢复会话使用
+func workerProxyEnv(proxy model.AccountProxy) []string {
+	if proxy.IP == "" {
+		return nil
+	}
+	return []string{
+		"-e", "PROXY_IP=" + proxy.IP,
+		"-e", "PROXY_PORT=" + strconv.Itoa(proxy.Port),
+		"-e", "PROXY_USERNAME=" + proxy.Username,
+		"-e", "PROXY_PASSWORD=" + string(proxy.Password),
+		"-e", "PROXY_PROTOCOL=" + valueOrDefault(proxy.Protocol, "socks5"),
+	}
+}
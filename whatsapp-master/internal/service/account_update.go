@@ -0,0 +1,107 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// UpdateAccount 修改账号名称、备注、标签、负责人、状态轮询间隔和硬件指纹配置，只写入请求中提供的字段
+func (m *Manager) UpdateAccount(accountID string, req *model.UpdateAccountRequest) (*model.Account, error) {
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("name must not be empty")
+		}
+		updates["name"] = name
+	}
+	if req.Notes != nil {
+		updates["notes"] = strings.TrimSpace(*req.Notes)
+	}
+	if req.Tags != nil {
+		updates["tags"] = normalizeTags(*req.Tags)
+	}
+	if req.Owner != nil {
+		if err := validateAccountOwner(req.Owner); err != nil {
+			return nil, err
+		}
+		owner := &model.Account{}
+		applyAccountOwner(owner, req.Owner)
+		updates["owner_operator"] = owner.OwnerOperator
+		updates["owner_team"] = owner.OwnerTeam
+		updates["owner_email"] = owner.OwnerEmail
+		updates["owner_channel"] = owner.OwnerChannel
+	}
+	if req.StatusPollSeconds != nil {
+		if seconds := *req.StatusPollSeconds; seconds != 0 && seconds < minStatusPollSeconds {
+			return nil, fmt.Errorf("status_poll_seconds must be 0 or at least %d", minStatusPollSeconds)
+		}
+		updates["status_poll_seconds"] = *req.StatusPollSeconds
+	}
+	if req.HardwareProfileID != nil {
+		profileID := strings.TrimSpace(*req.HardwareProfileID)
+		if err := m.checkHardwareProfile(profileID); err != nil {
+			return nil, err
+		}
+		updates["hardware_profile_id"] = profileID
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return nil, fmt.Errorf("account %s not found", accountID)
+	}
+	if len(updates) == 0 {
+		return account.Clone(), nil
+	}
+
+	if err := m.db.Model(account).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("failed to update account: %v", err)
+	}
+	if name, ok := updates["name"].(string); ok {
+		account.Name = name
+	}
+	if notes, ok := updates["notes"].(string); ok {
+		account.Notes = notes
+	}
+	if tags, ok := updates["tags"].(model.StringList); ok {
+		account.Tags = tags
+	}
+	if req.Owner != nil {
+		applyAccountOwner(account, req.Owner)
+	}
+	if seconds, ok := updates["status_poll_seconds"].(int); ok {
+		account.StatusPollSeconds = seconds
+	}
+	if profileID, ok := updates["hardware_profile_id"].(string); ok {
+		account.HardwareProfileID = profileID
+	}
+
+	fields := make([]string, 0, 6)
+	for _, field := range []string{"name", "notes", "tags", "owner", "status_poll_seconds", "hardware_profile_id"} {
+		if _, ok := updates[field]; ok || (field == "owner" && req.Owner != nil) {
+			fields = append(fields, field)
+		}
+	}
+	slog.Info("Account updated", "account_id", accountID, "fields", fields)
+	m.emit(EventAccountUpdated, accountID, map[string]interface{}{"fields": fields})
+	return account.Clone(), nil
+}
+
+// normalizeTags 去掉空白和重复的标签，保持原有顺序
+func normalizeTags(tags []string) model.StringList {
+	normalized := make(model.StringList, 0, len(tags))
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); tag != "" && !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized
+}
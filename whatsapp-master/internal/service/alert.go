@@ -0,0 +1,453 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// alertTimeout 单次告警通知的超时时间
+const alertTimeout = 10 * time.Second
+
+var (
+	// ErrAlertChannelNotFound 告警通道不存在
+	ErrAlertChannelNotFound = errors.New("alert channel not found")
+	// ErrAlertRuleNotFound 告警路由规则不存在
+	ErrAlertRuleNotFound = errors.New("alert rule not found")
+)
+
+// loadAlertRouting 从数据库加载告警通道和路由规则，替换内存中的列表
+func (m *Manager) loadAlertRouting() error {
+	var channels []*model.AlertChannel
+	if err := m.db.Find(&channels).Error; err != nil {
+		return err
+	}
+	var rules []*model.AlertRule
+	if err := m.db.Find(&rules).Error; err != nil {
+		return err
+	}
+
+	loadedChannels := make(map[string]*model.AlertChannel, len(channels))
+	for _, channel := range channels {
+		loadedChannels[channel.ID] = channel
+	}
+	loadedRules := make(map[string]*model.AlertRule, len(rules))
+	for _, rule := range rules {
+		loadedRules[rule.ID] = rule
+	}
+	m.alertMutex.Lock()
+	m.alertChannels = loadedChannels
+	m.alertRules = loadedRules
+	m.alertMutex.Unlock()
+	return nil
+}
+
+// CreateAlertChannel 创建告警通道
+func (m *Manager) CreateAlertChannel(req *model.AlertChannelRequest) (*model.AlertChannel, error) {
+	channel := &model.AlertChannel{ID: generateID("ach")}
+	applyAlertChannel(channel, req)
+	if err := validateAlertChannel(channel); err != nil {
+		return nil, err
+	}
+	if err := m.db.Create(channel).Error; err != nil {
+		return nil, fmt.Errorf("failed to create alert channel: %v", err)
+	}
+
+	m.alertMutex.Lock()
+	m.alertChannels[channel.ID] = channel
+	m.alertMutex.Unlock()
+
+	slog.Info("Alert channel created", "channel_id", channel.ID, "type", channel.Type)
+	return channel, nil
+}
+
+// UpdateAlertChannel 更新告警通道，凭证留空时保持不变
+func (m *Manager) UpdateAlertChannel(id string, req *model.AlertChannelRequest) (*model.AlertChannel, error) {
+	m.alertMutex.Lock()
+	defer m.alertMutex.Unlock()
+
+	existing, exists := m.alertChannels[id]
+	if !exists {
+		return nil, ErrAlertChannelNotFound
+	}
+	channel := *existing
+	applyAlertChannel(&channel, req)
+	if err := validateAlertChannel(&channel); err != nil {
+		return nil, err
+	}
+	if err := m.db.Save(&channel).Error; err != nil {
+		return nil, fmt.Errorf("failed to update alert channel: %v", err)
+	}
+	m.alertChannels[id] = &channel
+	return &channel, nil
+}
+
+// applyAlertChannel 将请求写入告警通道，凭证为空时不覆盖
+func applyAlertChannel(channel *model.AlertChannel, req *model.AlertChannelRequest) {
+	channel.Name = strings.TrimSpace(req.Name)
+	channel.Type = req.Type
+	channel.Enabled = req.Enabled == nil || *req.Enabled
+	channel.URL = strings.TrimSpace(req.URL)
+	channel.ChatID = strings.TrimSpace(req.ChatID)
+	channel.SMTPHost = strings.TrimSpace(req.SMTPHost)
+	channel.SMTPPort = req.SMTPPort
+	channel.SMTPUsername = req.SMTPUsername
+	channel.From = req.From
+	channel.To = model.StringList(req.To)
+	if req.BotToken != "" {
+		channel.BotToken = model.Secret(req.BotToken)
+	}
+	if req.SMTPPassword != "" {
+		channel.SMTPPassword = model.Secret(req.SMTPPassword)
+	}
+	if channel.Type == model.AlertChannelEmail && channel.SMTPPort == 0 {
+		channel.SMTPPort = 587
+	}
+}
+
+// validateAlertChannel 校验告警通道类型所需的字段
+func validateAlertChannel(channel *model.AlertChannel) error {
+	switch channel.Type {
+	case model.AlertChannelSlack, model.AlertChannelWebhook:
+		if !strings.HasPrefix(channel.URL, "http://") && !strings.HasPrefix(channel.URL, "https://") {
+			return fmt.Errorf("%s channel requires an http(s) url", channel.Type)
+		}
+	case model.AlertChannelTelegram:
+		if channel.BotToken == "" || channel.ChatID == "" {
+			return fmt.Errorf("telegram channel requires bot_token and chat_id")
+		}
+	case model.AlertChannelEmail:
+		if channel.SMTPHost == "" || channel.From == "" || len(channel.To) == 0 {
+			return fmt.Errorf("email channel requires smtp_host, from and to")
+		}
+	default:
+		return fmt.Errorf("unsupported alert channel type %q", channel.Type)
+	}
+	return nil
+}
+
+// ListAlertChannels 列出告警通道
+func (m *Manager) ListAlertChannels() []*model.AlertChannel {
+	m.alertMutex.RLock()
+	defer m.alertMutex.RUnlock()
+
+	channels := make([]*model.AlertChannel, 0, len(m.alertChannels))
+	for _, channel := range m.alertChannels {
+		channels = append(channels, channel)
+	}
+	slices.SortFunc(channels, func(a, b *model.AlertChannel) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return channels
+}
+
+// DeleteAlertChannel 删除告警通道，并从引用它的路由规则中移除
+func (m *Manager) DeleteAlertChannel(id string) error {
+	m.alertMutex.Lock()
+	defer m.alertMutex.Unlock()
+
+	channel, exists := m.alertChannels[id]
+	if !exists {
+		return ErrAlertChannelNotFound
+	}
+	if err := m.db.Delete(channel).Error; err != nil {
+		return fmt.Errorf("failed to delete alert channel: %v", err)
+	}
+	delete(m.alertChannels, id)
+
+	for _, rule := range m.alertRules {
+		if !slices.Contains(rule.Channels, id) {
+			continue
+		}
+		rule.Channels = slices.DeleteFunc(slices.Clone(rule.Channels), func(c string) bool { return c == id })
+		if err := m.db.Model(rule).Update("channels", rule.Channels).Error; err != nil {
+			slog.Warn("Failed to detach alert channel from rule", "rule_id", rule.ID, "channel_id", id, "error", err)
+		}
+	}
+	return nil
+}
+
+// CreateAlertRule 创建告警路由规则
+func (m *Manager) CreateAlertRule(req *model.AlertRuleRequest) (*model.AlertRule, error) {
+	rule := &model.AlertRule{ID: generateID("arl")}
+	applyAlertRule(rule, req)
+
+	m.alertMutex.Lock()
+	defer m.alertMutex.Unlock()
+	if err := m.validateAlertRuleLocked(rule); err != nil {
+		return nil, err
+	}
+	if err := m.db.Create(rule).Error; err != nil {
+		return nil, fmt.Errorf("failed to create alert rule: %v", err)
+	}
+	m.alertRules[rule.ID] = rule
+	return rule, nil
+}
+
+// UpdateAlertRule 更新告警路由规则
+func (m *Manager) UpdateAlertRule(id string, req *model.AlertRuleRequest) (*model.AlertRule, error) {
+	m.alertMutex.Lock()
+	defer m.alertMutex.Unlock()
+
+	existing, exists := m.alertRules[id]
+	if !exists {
+		return nil, ErrAlertRuleNotFound
+	}
+	rule := *existing
+	applyAlertRule(&rule, req)
+	if err := m.validateAlertRuleLocked(&rule); err != nil {
+		return nil, err
+	}
+	if err := m.db.Save(&rule).Error; err != nil {
+		return nil, fmt.Errorf("failed to update alert rule: %v", err)
+	}
+	m.alertRules[id] = &rule
+	return &rule, nil
+}
+
+// applyAlertRule 将请求写入路由规则
+func applyAlertRule(rule *model.AlertRule, req *model.AlertRuleRequest) {
+	rule.Name = strings.TrimSpace(req.Name)
+	rule.Events = model.StringList(req.Events)
+	rule.AccountIDs = model.StringList(req.AccountIDs)
+	rule.Pool = strings.TrimSpace(req.Pool)
+	rule.OwnerTeam = strings.TrimSpace(req.OwnerTeam)
+	rule.Channels = model.StringList(req.Channels)
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+}
+
+// validateAlertRuleLocked 校验规则引用的通道存在，调用方需持有alertMutex
+func (m *Manager) validateAlertRuleLocked(rule *model.AlertRule) error {
+	for _, id := range rule.Channels {
+		if _, exists := m.alertChannels[id]; !exists {
+			return fmt.Errorf("alert channel %s not found", id)
+		}
+	}
+	return nil
+}
+
+// ListAlertRules 列出告警路由规则
+func (m *Manager) ListAlertRules() []*model.AlertRule {
+	m.alertMutex.RLock()
+	defer m.alertMutex.RUnlock()
+
+	rules := make([]*model.AlertRule, 0, len(m.alertRules))
+	for _, rule := range m.alertRules {
+		rules = append(rules, rule)
+	}
+	slices.SortFunc(rules, func(a, b *model.AlertRule) int { return a.CreatedAt.Compare(b.CreatedAt) })
+	return rules
+}
+
+// DeleteAlertRule 删除告警路由规则
+func (m *Manager) DeleteAlertRule(id string) error {
+	m.alertMutex.Lock()
+	defer m.alertMutex.Unlock()
+
+	rule, exists := m.alertRules[id]
+	if !exists {
+		return ErrAlertRuleNotFound
+	}
+	if err := m.db.Delete(rule).Error; err != nil {
+		return fmt.Errorf("failed to delete alert rule: %v", err)
+	}
+	delete(m.alertRules, id)
+	return nil
+}
+
+// matchAlertChannels 返回路由规则匹配事件的已启用通道；规则未指定事件时只匹配 ALERT_EVENTS 中的事件
+func (m *Manager) matchAlertChannels(eventType string, alerting bool, account *model.Account) []*model.AlertChannel {
+	m.alertMutex.RLock()
+	defer m.alertMutex.RUnlock()
+
+	seen := make(map[string]bool)
+	var channels []*model.AlertChannel
+	for _, rule := range m.alertRules {
+		if !rule.Enabled || !alertRuleMatches(rule, eventType, alerting, account) {
+			continue
+		}
+		for _, id := range rule.Channels {
+			channel, exists := m.alertChannels[id]
+			if !exists || !channel.Enabled || seen[id] {
+				continue
+			}
+			seen[id] = true
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// alertRuleMatches 事件是否满足规则的事件和账号条件，account为nil表示事件不属于单个账号
+func alertRuleMatches(rule *model.AlertRule, eventType string, alerting bool, account *model.Account) bool {
+	if len(rule.Events) == 0 {
+		if !alerting {
+			return false
+		}
+	} else if !slices.Contains(rule.Events, eventType) {
+		return false
+	}
+
+	if len(rule.AccountIDs) == 0 && rule.Pool == "" && rule.OwnerTeam == "" {
+		return true
+	}
+	if account == nil {
+		return false
+	}
+	return (len(rule.AccountIDs) == 0 || slices.Contains(rule.AccountIDs, account.ID)) &&
+		(rule.Pool == "" || rule.Pool == account.Pool) &&
+		(rule.OwnerTeam == "" || rule.OwnerTeam == account.OwnerTeam)
+}
+
+// TestAlertChannel 向告警通道发送一条测试通知
+func (m *Manager) TestAlertChannel(id string) (*model.AlertTestResult, error) {
+	m.alertMutex.RLock()
+	channel, exists := m.alertChannels[id]
+	m.alertMutex.RUnlock()
+	if !exists {
+		return nil, ErrAlertChannelNotFound
+	}
+
+	incident := &model.Incident{
+		Text:      fmt.Sprintf("[alert.test] test notification for channel %s", valueOrDefault(channel.Name, channel.ID)),
+		Event:     "alert.test",
+		Timestamp: time.Now(),
+	}
+	result := &model.AlertTestResult{ChannelID: id, Delivered: true}
+	if err := m.sendAlert(channel, incident); err != nil {
+		result.Delivered = false
+		result.Error = err.Error()
+	}
+	return result, nil
+}
+
+// notifyChannel 发送故障通知到告警通道，失败只记录日志
+func (m *Manager) notifyChannel(channel *model.AlertChannel, incident *model.Incident) {
+	if err := m.sendAlert(channel, incident); err != nil {
+		slog.Warn("Failed to deliver alert", "channel_id", channel.ID, "type", channel.Type, "event", incident.Event, "account_id", incident.AccountID, "error", err)
+	}
+}
+
+// sendAlert 按通道类型发送故障通知
+func (m *Manager) sendAlert(channel *model.AlertChannel, incident *model.Incident) error {
+	switch channel.Type {
+	case model.AlertChannelSlack:
+		return postAlert(channel.URL, map[string]string{"text": incident.Text})
+	case model.AlertChannelTelegram:
+		target := fmt.Sprintf("%s/bot%s/sendMessage", strings.TrimRight(m.GetConfig().Alert.TelegramAPIURL, "/"), string(channel.BotToken))
+		return postAlert(target, map[string]string{"chat_id": channel.ChatID, "text": incident.Text})
+	case model.AlertChannelEmail:
+		return sendAlertEmail(channel, incident)
+	case model.AlertChannelWebhook:
+		return postAlert(channel.URL, incident)
+	}
+	return fmt.Errorf("unsupported alert channel type %q", channel.Type)
+}
+
+// postAlert 以JSON POST告警通知
+func postAlert(target string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), alertTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		// 错误中的URL可能包含Telegram Bot Token
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			return urlErr.Err
+		}
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("alert channel returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// sendAlertEmail 通过SMTP发送告警邮件，465端口使用隐式TLS，其他端口在服务器支持时使用STARTTLS
+func sendAlertEmail(channel *model.AlertChannel, incident *model.Incident) error {
+	addr := net.JoinHostPort(channel.SMTPHost, strconv.Itoa(channel.SMTPPort))
+	dialer := &net.Dialer{Timeout: alertTimeout}
+	tlsConfig := &tls.Config{ServerName: channel.SMTPHost}
+
+	var conn net.Conn
+	var err error
+	if channel.SMTPPort == 465 {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %v", err)
+	}
+	conn.SetDeadline(time.Now().Add(alertTimeout))
+
+	client, err := smtp.NewClient(conn, channel.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start smtp session: %v", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && channel.SMTPPort != 465 {
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return fmt.Errorf("failed to start tls: %v", err)
+		}
+	}
+	if channel.SMTPUsername != "" {
+		if err := client.Auth(smtp.PlainAuth("", channel.SMTPUsername, string(channel.SMTPPassword), channel.SMTPHost)); err != nil {
+			return fmt.Errorf("smtp authentication failed: %v", err)
+		}
+	}
+	if err := client.Mail(channel.From); err != nil {
+		return fmt.Errorf("smtp MAIL FROM failed: %v", err)
+	}
+	for _, to := range channel.To {
+		if err := client.Rcpt(to); err != nil {
+			return fmt.Errorf("smtp RCPT TO %s failed: %v", to, err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("smtp DATA failed: %v", err)
+	}
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", channel.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(channel.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", strings.TrimSpace("[whatsapp-fleet] "+incident.Event+" "+incident.AccountID))
+	fmt.Fprintf(&msg, "Date: %s\r\n", incident.Timestamp.Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(incident.Text + "\r\n")
+	if _, err := w.Write([]byte(msg.String())); err != nil {
+		return fmt.Errorf("failed to write email: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("smtp server rejected email: %v", err)
+	}
+	return client.Quit()
+}
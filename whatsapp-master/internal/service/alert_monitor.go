@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// alertCheckConcurrency 同时检查代理的Worker数量
+const alertCheckConcurrency = 8
+
+// StartAlertMonitor 定期检查Worker代理连通性和会话目录磁盘空间，状态变化时发布 proxy.down / proxy.up、disk.low / disk.recovered 事件
+func (m *Manager) StartAlertMonitor() {
+	if m.GetConfig().Alert.CheckInterval <= 0 {
+		return
+	}
+
+	interval := time.Duration(m.GetConfig().Alert.CheckInterval) * time.Second
+	slog.Info("Alert monitor enabled", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer ticker.Stop()
+
+		proxyDown := make(map[string]bool)
+		diskLow := false
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				if !m.IsLeader() {
+					continue
+				}
+				diskLow = m.checkDiskAlert(diskLow)
+				m.checkProxyAlerts(proxyDown)
+			}
+		}
+	}()
+}
+
+// checkDiskAlert 会话目录所在磁盘剩余空间低于 HEALTH_DISK_MIN_FREE_PERCENT 时告警，返回当前是否空间不足
+func (m *Manager) checkDiskAlert(wasLow bool) bool {
+	path := existingParent(m.GetConfig().Worker.SessionDir)
+	total, free, err := diskUsage(path)
+	if err != nil || total == 0 {
+		return wasLow
+	}
+
+	freePercent := float64(free) * 100 / float64(total)
+	low := freePercent < float64(m.GetConfig().Health.DiskMinFreePercent)
+	if low == wasLow {
+		return low
+	}
+
+	data := map[string]interface{}{
+		"path":         path,
+		"free_bytes":   free,
+		"total_bytes":  total,
+		"free_percent": freePercent,
+	}
+	if low {
+		slog.Warn("Disk space low", "path", path, "free_percent", freePercent)
+		m.emit(EventDiskLow, "", data)
+	} else {
+		slog.Info("Disk space recovered", "path", path, "free_percent", freePercent)
+		m.emit(EventDiskRecovered, "", data)
+	}
+	return low
+}
+
+// checkProxyAlerts 通过Worker检查已登录账号的代理出口，代理不可用或恢复时发布事件；Worker不可达时不改变状态
+func (m *Manager) checkProxyAlerts(proxyDown map[string]bool) {
+	m.mutex.RLock()
+	accountIDs := make([]string, 0)
+	for _, account := range m.accounts {
+		if account.Status == "logged_in" {
+			accountIDs = append(accountIDs, account.ID)
+		}
+	}
+	for accountID := range proxyDown {
+		if _, exists := m.accounts[accountID]; !exists {
+			delete(proxyDown, accountID)
+		}
+	}
+	m.mutex.RUnlock()
+
+	results := make(map[string]bool)
+	var resultsMutex sync.Mutex
+	sem := make(chan struct{}, alertCheckConcurrency)
+	var wg sync.WaitGroup
+	for _, accountID := range accountIDs {
+		if m.shuttingDown() {
+			break
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(accountID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			healthy, checked := m.checkAccountProxy(accountID)
+			if !checked {
+				return
+			}
+			resultsMutex.Lock()
+			results[accountID] = healthy
+			resultsMutex.Unlock()
+		}(accountID)
+	}
+	wg.Wait()
+
+	for accountID, healthy := range results {
+		switch {
+		case !healthy && !proxyDown[accountID]:
+			proxyDown[accountID] = true
+			slog.Warn("Account proxy is down", "account_id", accountID)
+			m.emit(EventProxyDown, accountID, nil)
+		case healthy && proxyDown[accountID]:
+			delete(proxyDown, accountID)
+			slog.Info("Account proxy recovered", "account_id", accountID)
+			m.emit(EventProxyUp, accountID, nil)
+		}
+	}
+}
+
+// checkAccountProxy 检查账号代理能否访问外网，未使用代理的账号视为正常；checked为false表示无法判断
+func (m *Manager) checkAccountProxy(accountID string) (healthy, checked bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	status, err := m.FetchFromWorker(ctx, accountID, "/api/proxy/status")
+	if err != nil {
+		return false, false
+	}
+	if enabled, _ := status["enabled"].(bool); !enabled {
+		return true, true
+	}
+
+	result, err := m.FetchFromWorker(ctx, accountID, "/api/proxy/detect")
+	if err != nil {
+		return false, false
+	}
+	detected, _ := result["detected"].(bool)
+	return detected, true
+}
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// ErrApprovalNotFound 审批不存在
+var ErrApprovalNotFound = errors.New("approval not found")
+
+// ErrApprovalNotPending 审批已处理过
+var ErrApprovalNotPending = errors.New("approval is not pending")
+
+// approvalColumns 审批列表允许过滤和排序的字段
+var approvalColumns = map[string]string{
+	"id":          "id",
+	"kind":        "kind",
+	"campaign_id": "campaign_id",
+	"campaign":    "campaign",
+	"status":      "status",
+	"recipients":  "recipients",
+	"reviewed_at": "reviewed_at",
+	"created_at":  "created_at",
+}
+
+// ApprovalRequiredError 收件人数超过审批阈值，发送已转为待审批
+type ApprovalRequiredError struct {
+	Approval *model.Approval
+}
+
+func (e *ApprovalRequiredError) Error() string {
+	return fmt.Sprintf("%d recipients exceed the approval threshold, waiting for approval %s", e.Approval.Recipients, e.Approval.ID)
+}
+
+// needsApproval 收件人数是否超过审批阈值，阈值为0时不需要审批
+func (m *Manager) needsApproval(recipients int) bool {
+	threshold := m.GetConfig().Bulk.ApprovalThreshold
+	return threshold > 0 && recipients > threshold
+}
+
+// sampleIndexes 从n个收件人中均匀取出最多size个下标，示例覆盖整个收件人列表
+func sampleIndexes(n, size int) []int {
+	if size <= 0 || n <= 0 {
+		return nil
+	}
+	if size > n {
+		size = n
+	}
+	indexes := make([]int, size)
+	for i := range indexes {
+		indexes[i] = i * n / size
+	}
+	return indexes
+}
+
+// requestBulkApproval 保存批量发送请求并等待审批
+func (m *Manager) requestBulkApproval(req *model.BulkSendRequest) error {
+	samples := make([]model.ApprovalSample, 0)
+	for _, i := range sampleIndexes(len(req.Recipients), m.GetConfig().Bulk.ApprovalSampleSize) {
+		recipient := req.Recipients[i]
+		samples = append(samples, model.ApprovalSample{Contact: recipient.Contact, Message: renderTemplate(req.Message, recipient)})
+	}
+	raw, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+
+	approval := &model.Approval{
+		ID:         generateID("apr"),
+		Kind:       model.ApprovalKindBulk,
+		Campaign:   req.Campaign,
+		AccountIDs: model.StringList(req.AccountIDs),
+		Recipients: len(req.Recipients),
+		Message:    req.Message,
+		Request:    model.RawJSON(raw),
+	}
+	return m.createApproval(approval, samples)
+}
+
+// requestCampaignApproval 活动转为待审批
+func (m *Manager) requestCampaignApproval(campaign *model.Campaign, recipients int) error {
+	samples := make([]model.ApprovalSample, 0)
+	for _, i := range sampleIndexes(recipients, m.GetConfig().Bulk.ApprovalSampleSize) {
+		var recipient model.CampaignRecipient
+		if err := m.db.Where("campaign_id = ?", campaign.ID).Order("id").Offset(i).First(&recipient).Error; err != nil {
+			continue
+		}
+		samples = append(samples, model.ApprovalSample{
+			Contact: recipient.Contact,
+			Message: renderTemplate(campaign.Message, model.BulkRecipient{Contact: recipient.Contact, Variables: recipient.Variables}),
+		})
+	}
+
+	approval := &model.Approval{
+		ID:         generateID("apr"),
+		Kind:       model.ApprovalKindCampaign,
+		CampaignID: campaign.ID,
+		Campaign:   campaign.Name,
+		AccountIDs: campaign.AccountIDs,
+		Recipients: recipients,
+		Message:    campaign.Message,
+	}
+	if err := m.createApproval(approval, samples); err != nil {
+		var required *ApprovalRequiredError
+		if errors.As(err, &required) {
+			m.db.Model(campaign).Updates(map[string]interface{}{"status": CampaignPendingApproval, "last_error": ""})
+		}
+		return err
+	}
+	return nil
+}
+
+// createApproval 保存待审批记录，成功时返回 *ApprovalRequiredError
+func (m *Manager) createApproval(approval *model.Approval, samples []model.ApprovalSample) error {
+	raw, _ := json.Marshal(samples)
+	approval.Sample = model.RawJSON(raw)
+	approval.Status = model.ApprovalPending
+	if err := m.db.Create(approval).Error; err != nil {
+		return fmt.Errorf("failed to save approval: %v", err)
+	}
+
+	slog.Info("Send waiting for approval", "approval_id", approval.ID, "kind", approval.Kind, "recipients", approval.Recipients)
+	m.emit(EventApprovalRequested, "", map[string]interface{}{
+		"approval_id": approval.ID,
+		"kind":        approval.Kind,
+		"campaign":    approval.Campaign,
+		"recipients":  approval.Recipients,
+	})
+	return &ApprovalRequiredError{Approval: approval}
+}
+
+// campaignApproved 活动是否已经批准过，批准后暂停再继续不需要重新审批
+func (m *Manager) campaignApproved(campaignID string) bool {
+	var count int64
+	m.db.Model(&model.Approval{}).
+		Where("campaign_id = ? AND status IN ?", campaignID, []string{model.ApprovalApproved, model.ApprovalFailed}).
+		Count(&count)
+	return count > 0
+}
+
+// ListApprovals 分页查询审批，默认按提交时间倒序
+func (m *Manager) ListApprovals(q *model.ListQuery) ([]*model.Approval, int64, error) {
+	approvals := make([]*model.Approval, 0)
+	total, err := findWithListQuery(m.db.Model(&model.Approval{}), q, approvalColumns, "-created_at", &approvals)
+	if err != nil {
+		return nil, 0, err
+	}
+	return approvals, total, nil
+}
+
+// GetApproval 查询审批
+func (m *Manager) GetApproval(id string) (*model.Approval, error) {
+	var approval model.Approval
+	if err := m.db.Where("id = ?", id).First(&approval).Error; err != nil {
+		return nil, ErrApprovalNotFound
+	}
+	return &approval, nil
+}
+
+// ApproveSend 批准并开始发送：活动开始发送，批量发送按原请求创建批次。无法开始发送时审批记为failed
+func (m *Manager) ApproveSend(id, note string) (*model.Approval, error) {
+	approval, err := m.reviewApproval(id, model.ApprovalApproved, note)
+	if err != nil {
+		return nil, err
+	}
+
+	var dispatchErr error
+	switch approval.Kind {
+	case model.ApprovalKindCampaign:
+		dispatchErr = m.startApprovedCampaign(approval.CampaignID)
+	case model.ApprovalKindBulk:
+		var req model.BulkSendRequest
+		if dispatchErr = json.Unmarshal([]byte(approval.Request), &req); dispatchErr == nil {
+			var batch *model.BulkBatch
+			if batch, dispatchErr = m.startBulk(&req); dispatchErr == nil {
+				approval.BatchID = batch.ID
+			}
+		}
+	default:
+		dispatchErr = fmt.Errorf("unknown approval kind %q", approval.Kind)
+	}
+
+	if dispatchErr != nil {
+		approval.Status = model.ApprovalFailed
+		approval.Error = dispatchErr.Error()
+	}
+	if err := m.db.Model(approval).Updates(map[string]interface{}{
+		"status":   approval.Status,
+		"error":    approval.Error,
+		"batch_id": approval.BatchID,
+	}).Error; err != nil {
+		slog.Warn("Failed to save approval result", "approval_id", approval.ID, "error", err)
+	}
+	slog.Info("Send approved", "approval_id", approval.ID, "kind", approval.Kind, "status", approval.Status)
+	return approval, dispatchErr
+}
+
+// startApprovedCampaign 开始发送已批准的活动，失败时活动回到草稿状态
+func (m *Manager) startApprovedCampaign(campaignID string) error {
+	campaign, err := m.GetCampaign(campaignID)
+	if err != nil {
+		return err
+	}
+	if campaign.Status != CampaignPendingApproval {
+		return fmt.Errorf("campaign %s is %s", campaignID, campaign.Status)
+	}
+	if err := m.launchCampaign(campaign); err != nil {
+		m.db.Model(campaign).Updates(map[string]interface{}{"status": CampaignDraft, "last_error": err.Error()})
+		return err
+	}
+	return nil
+}
+
+// RejectSend 拒绝发送，待审批的活动回到草稿状态
+func (m *Manager) RejectSend(id, note string) (*model.Approval, error) {
+	approval, err := m.reviewApproval(id, model.ApprovalRejected, note)
+	if err != nil {
+		return nil, err
+	}
+	if approval.Kind == model.ApprovalKindCampaign {
+		reason := "approval rejected"
+		if note != "" {
+			reason += ": " + note
+		}
+		m.db.Model(&model.Campaign{}).
+			Where("id = ? AND status = ?", approval.CampaignID, CampaignPendingApproval).
+			Updates(map[string]interface{}{"status": CampaignDraft, "last_error": reason})
+	}
+	slog.Info("Send rejected", "approval_id", approval.ID, "kind", approval.Kind)
+	return approval, nil
+}
+
+// reviewApproval 处理待审批记录，同一审批并发处理时只有一个成功
+func (m *Manager) reviewApproval(id, status, note string) (*model.Approval, error) {
+	approval, err := m.GetApproval(id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	res := m.db.Model(&model.Approval{}).
+		Where("id = ? AND status = ?", id, model.ApprovalPending).
+		Updates(map[string]interface{}{"status": status, "note": note, "reviewed_at": now})
+	if res.Error != nil {
+		return nil, fmt.Errorf("failed to review approval: %v", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, ErrApprovalNotPending
+	}
+	approval.Status = status
+	approval.Note = note
+	approval.ReviewedAt = &now
+	return approval, nil
+}
@@ -0,0 +1,76 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// auditColumns 审计日志允许过滤和排序的字段
+var auditColumns = map[string]string{
+	"request_id": "request_id",
+	"actor":      "actor",
+	"api_key_id": "api_key_id",
+	"tenant_id":  "tenant_id",
+	"method":     "method",
+	"route":      "route",
+	"account_id": "account_id",
+	"status":     "status",
+	"success":    "success",
+	"created_at": "created_at",
+}
+
+// AuditEnabled 是否记录写操作审计日志
+func (m *Manager) AuditEnabled() bool {
+	return m.GetConfig().Audit.Enabled
+}
+
+// RecordAudit 写入一条审计记录，写入失败只记录日志，不影响请求
+func (m *Manager) RecordAudit(entry *model.AuditEntry) {
+	if err := m.db.Create(entry).Error; err != nil {
+		slog.Warn("Failed to record audit entry", "route", entry.Route, "request_id", entry.RequestID, "error", err)
+	}
+}
+
+// ListAuditEntries 分页查询审计日志，since/until 限定时间范围，默认按时间倒序
+func (m *Manager) ListAuditEntries(q *model.ListQuery, since, until *time.Time) ([]*model.AuditEntry, int64, error) {
+	db := m.db.Model(&model.AuditEntry{})
+	if since != nil {
+		db = db.Where("created_at >= ?", *since)
+	}
+	if until != nil {
+		db = db.Where("created_at < ?", *until)
+	}
+
+	entries := make([]*model.AuditEntry, 0)
+	total, err := findWithListQuery(db, q, auditColumns, "-created_at", &entries)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// cleanExpiredAudit 删除超过保留期的审计记录
+func (m *Manager) cleanExpiredAudit(report *model.JanitorReport) {
+	days := m.GetConfig().Audit.RetentionDays
+	if days <= 0 {
+		return
+	}
+
+	db := m.db.Where("created_at < ?", time.Now().AddDate(0, 0, -days))
+	if report.DryRun {
+		if err := db.Model(&model.AuditEntry{}).Count(&report.AuditRemoved).Error; err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to count expired audit entries: %v", err))
+		}
+		return
+	}
+
+	result := db.Delete(&model.AuditEntry{})
+	if result.Error != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to delete expired audit entries: %v", result.Error))
+		return
+	}
+	report.AuditRemoved = result.RowsAffected
+}
@@ -0,0 +1,294 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// ErrAutoReplyRuleNotFound 自动回复规则不存在或不属于该账号
+var ErrAutoReplyRuleNotFound = errors.New("auto-reply rule not found")
+
+// autoReplyLogColumns 自动回复记录允许过滤和排序的字段
+var autoReplyLogColumns = map[string]string{
+	"rule_id":    "rule_id",
+	"contact":    "contact",
+	"status":     "status",
+	"created_at": "created_at",
+}
+
+// CreateAutoReplyRule 为账号创建自动回复规则
+func (m *Manager) CreateAutoReplyRule(accountID string, req *model.AutoReplyRuleRequest) (*model.AutoReplyRule, error) {
+	if _, err := m.GetAccount(accountID); err != nil {
+		return nil, err
+	}
+	rule := &model.AutoReplyRule{ID: generateID("arr"), AccountID: accountID}
+	applyAutoReplyRule(rule, req)
+	if err := validateAutoReplyRule(rule); err != nil {
+		return nil, err
+	}
+	if err := m.db.Create(rule).Error; err != nil {
+		return nil, fmt.Errorf("failed to create auto-reply rule: %v", err)
+	}
+	slog.Info("Auto-reply rule created", "account_id", accountID, "rule_id", rule.ID, "match_type", rule.MatchType)
+	return rule, nil
+}
+
+// UpdateAutoReplyRule 替换账号的自动回复规则
+func (m *Manager) UpdateAutoReplyRule(accountID, ruleID string, req *model.AutoReplyRuleRequest) (*model.AutoReplyRule, error) {
+	var rule model.AutoReplyRule
+	if err := m.db.Where("id = ? AND account_id = ?", ruleID, accountID).First(&rule).Error; err != nil {
+		return nil, ErrAutoReplyRuleNotFound
+	}
+	applyAutoReplyRule(&rule, req)
+	if err := validateAutoReplyRule(&rule); err != nil {
+		return nil, err
+	}
+	if err := m.db.Save(&rule).Error; err != nil {
+		return nil, fmt.Errorf("failed to update auto-reply rule: %v", err)
+	}
+	return &rule, nil
+}
+
+// ListAutoReplyRules 按匹配顺序列出账号的自动回复规则
+func (m *Manager) ListAutoReplyRules(accountID string) ([]*model.AutoReplyRule, error) {
+	rules := make([]*model.AutoReplyRule, 0)
+	if err := m.db.Where("account_id = ?", accountID).Order("priority ASC, created_at ASC").Find(&rules).Error; err != nil {
+		return nil, fmt.Errorf("failed to list auto-reply rules: %v", err)
+	}
+	return rules, nil
+}
+
+// DeleteAutoReplyRule 删除账号的自动回复规则，已有的回复记录保留
+func (m *Manager) DeleteAutoReplyRule(accountID, ruleID string) error {
+	res := m.db.Where("id = ? AND account_id = ?", ruleID, accountID).Delete(&model.AutoReplyRule{})
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete auto-reply rule: %v", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrAutoReplyRuleNotFound
+	}
+	return nil
+}
+
+// ListAutoReplyLogs 分页查询账号的自动回复记录
+func (m *Manager) ListAutoReplyLogs(accountID string, q *model.ListQuery) ([]*model.AutoReplyLog, int64, error) {
+	logs := make([]*model.AutoReplyLog, 0)
+	db := m.db.Model(&model.AutoReplyLog{}).Where("account_id = ?", accountID)
+	total, err := findWithListQuery(db, q, autoReplyLogColumns, "-created_at", &logs)
+	if err != nil {
+		return nil, 0, err
+	}
+	return logs, total, nil
+}
+
+// applyAutoReplyRule 将请求写入规则
+func applyAutoReplyRule(rule *model.AutoReplyRule, req *model.AutoReplyRuleRequest) {
+	rule.Name = strings.TrimSpace(req.Name)
+	rule.MatchType = req.MatchType
+	rule.Keywords = model.StringList{}
+	for _, keyword := range req.Keywords {
+		if keyword = strings.TrimSpace(keyword); keyword != "" {
+			rule.Keywords = append(rule.Keywords, keyword)
+		}
+	}
+	rule.Pattern = req.Pattern
+	rule.Reply = req.Reply
+	rule.Priority = req.Priority
+	rule.QuietHourStart = req.QuietHourStart
+	rule.QuietHourEnd = req.QuietHourEnd
+	rule.MaxRepliesPerContact = req.MaxRepliesPerContact
+	rule.IncludeGroups = req.IncludeGroups
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+}
+
+// validateAutoReplyRule 校验匹配方式所需的字段
+func validateAutoReplyRule(rule *model.AutoReplyRule) error {
+	switch rule.MatchType {
+	case model.AutoReplyMatchKeyword:
+		if len(rule.Keywords) == 0 {
+			return fmt.Errorf("keyword rule requires at least one keyword")
+		}
+	case model.AutoReplyMatchRegex:
+		if rule.Pattern == "" {
+			return fmt.Errorf("regex rule requires a pattern")
+		}
+		if _, err := regexp.Compile(rule.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern: %v", err)
+		}
+	default:
+		return fmt.Errorf("unsupported match type %q", rule.MatchType)
+	}
+	if strings.TrimSpace(rule.Reply) == "" {
+		return fmt.Errorf("reply must not be empty")
+	}
+	return nil
+}
+
+// matchAutoReplyRule 判断消息是否匹配规则，匹配时返回可用于回复模板的变量
+func matchAutoReplyRule(rule *model.AutoReplyRule, msg *model.Message) (map[string]string, bool) {
+	vars := map[string]string{"message": msg.Body}
+	switch rule.MatchType {
+	case model.AutoReplyMatchKeyword:
+		body := strings.ToLower(msg.Body)
+		for _, keyword := range rule.Keywords {
+			if strings.Contains(body, strings.ToLower(keyword)) {
+				return vars, true
+			}
+		}
+	case model.AutoReplyMatchRegex:
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return nil, false
+		}
+		match := re.FindStringSubmatch(msg.Body)
+		if match == nil {
+			return nil, false
+		}
+		for i, name := range re.SubexpNames() {
+			if name != "" && name != "contact" && name != "message" {
+				vars[name] = match[i]
+			}
+		}
+		return vars, true
+	}
+	return nil, false
+}
+
+// evaluateAutoReply 对收件箱新收集到的入站消息按优先级匹配账号的规则，处于静默时段或对该联系人已达回复上限的规则跳过，
+// 第一条可用的规则生成回复放入发送队列；已退订、由坐席接管的会话和过旧的消息不回复
+func (m *Manager) evaluateAutoReply(msg *model.Message) {
+	cfg := m.GetConfig().AutoReply
+	if !cfg.Enabled || msg.Direction != "inbound" || strings.TrimSpace(msg.Body) == "" {
+		return
+	}
+	if cfg.MaxAgeMinutes > 0 && time.Since(msg.Timestamp) > time.Duration(cfg.MaxAgeMinutes)*time.Minute {
+		return
+	}
+
+	var rules []*model.AutoReplyRule
+	if err := m.db.Where("account_id = ? AND enabled = ?", msg.AccountID, true).Order("priority ASC, created_at ASC").Find(&rules).Error; err != nil {
+		slog.Warn("Failed to load auto-reply rules", "account_id", msg.AccountID, "error", err)
+		return
+	}
+	if len(rules) == 0 || m.isOptedOut(msg.Contact) || m.handledByAgent(msg.AccountID, msg.Contact) {
+		return
+	}
+
+	now := time.Now()
+	group := strings.HasSuffix(msg.Contact, "@g.us")
+	for _, rule := range rules {
+		if group && !rule.IncludeGroups {
+			continue
+		}
+		vars, ok := matchAutoReplyRule(rule, msg)
+		if !ok {
+			continue
+		}
+		if inQuietHours(now.Hour(), rule.QuietHourStart, rule.QuietHourEnd) {
+			continue
+		}
+		if rule.MaxRepliesPerContact > 0 && m.autoRepliesSince(rule.ID, msg.Contact, now.Add(-time.Duration(cfg.WindowHours)*time.Hour)) >= int64(rule.MaxRepliesPerContact) {
+			continue
+		}
+		m.enqueueAutoReply(rule, msg, renderTemplate(rule.Reply, model.BulkRecipient{Contact: msg.Contact, Variables: vars}))
+		return
+	}
+}
+
+// handledByAgent 会话是否已由坐席接管
+func (m *Manager) handledByAgent(accountID, contact string) bool {
+	number := strings.TrimSuffix(contact, "@c.us")
+	var count int64
+	m.db.Model(&model.Conversation{}).
+		Where("account_id = ? AND contact IN ? AND handled_by = ?", accountID, []string{number, number + "@c.us"}, HandledByAgent).
+		Count(&count)
+	return count > 0
+}
+
+// autoRepliesSince 规则在时间点之后对联系人的回复次数，队列满被丢弃的不计入
+func (m *Manager) autoRepliesSince(ruleID, contact string, since time.Time) int64 {
+	var count int64
+	m.db.Model(&model.AutoReplyLog{}).
+		Where("rule_id = ? AND contact = ? AND created_at >= ? AND status <> ?", ruleID, contact, since, model.AutoReplyDropped).
+		Count(&count)
+	return count
+}
+
+// enqueueAutoReply 记录回复并放入发送队列，队列满时记录为dropped
+func (m *Manager) enqueueAutoReply(rule *model.AutoReplyRule, msg *model.Message, reply string) {
+	entry := &model.AutoReplyLog{
+		ID:               generateID("rpl"),
+		RuleID:           rule.ID,
+		AccountID:        msg.AccountID,
+		Contact:          msg.Contact,
+		InboundMessageID: msg.ID,
+		Reply:            reply,
+		Status:           model.AutoReplyQueued,
+	}
+	if err := m.db.Create(entry).Error; err != nil {
+		slog.Error("Failed to record auto-reply", "account_id", msg.AccountID, "rule_id", rule.ID, "error", err)
+		return
+	}
+
+	select {
+	case m.autoReplies <- entry:
+	default:
+		m.db.Model(entry).Update("status", model.AutoReplyDropped)
+		slog.Warn("Auto-reply queue full, reply dropped", "account_id", msg.AccountID, "rule_id", rule.ID, "contact", msg.Contact)
+	}
+}
+
+// StartAutoReplier 启动自动回复发送协程，回复经过与 /send-message 相同的限流、配额和模拟输入
+func (m *Manager) StartAutoReplier() {
+	cfg := m.GetConfig().AutoReply
+	if !cfg.Enabled {
+		return
+	}
+	for range max(cfg.Concurrency, 1) {
+		m.background.Add(1)
+		go func() {
+			defer m.background.Done()
+			for {
+				select {
+				case <-m.stopCh:
+					return
+				case entry := <-m.autoReplies:
+					m.sendAutoReply(entry)
+				}
+			}
+		}()
+	}
+}
+
+// sendAutoReply 发送一条队列中的回复并更新记录
+func (m *Manager) sendAutoReply(entry *model.AutoReplyLog) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	result, err := m.SendMessage(ctx, &model.MessageRequest{
+		AccountID: entry.AccountID,
+		Contact:   entry.Contact,
+		Message:   entry.Reply,
+	})
+	cancel()
+
+	updates := map[string]interface{}{"status": model.AutoReplySent}
+	if err != nil {
+		updates["status"] = model.AutoReplyFailed
+		updates["error"] = err.Error()
+		slog.Warn("Auto-reply failed", "account_id", entry.AccountID, "rule_id", entry.RuleID, "contact", entry.Contact, "error", err)
+	} else {
+		updates["sent_at"] = time.Now()
+		if data, ok := result["data"].(map[string]interface{}); ok {
+			updates["message_id"] = data["message_id"]
+		}
+	}
+	if err := m.db.Model(entry).Updates(updates).Error; err != nil {
+		slog.Error("Failed to update auto-reply", "reply_id", entry.ID, "error", err)
+	}
+}
@@ -0,0 +1,468 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+
+	"whatsapp-aggregator/internal/backup"
+	"whatsapp-aggregator/internal/config"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/storage"
+)
+
+// backupTimeout 单个账号打包或恢复会话目录的超时
+const backupTimeout = 5 * time.Minute
+
+// ErrNoBackup 账号没有可恢复的备份
+var ErrNoBackup = errors.New("no successful backup found for account")
+
+// backupColumns 备份列表允许过滤和排序的字段
+var backupColumns = map[string]string{
+	"id":         "id",
+	"account_id": "account_id",
+	"host_id":    "host_id",
+	"trigger":    "backup_trigger",
+	"status":     "status",
+	"size_bytes": "size_bytes",
+	"created_at": "created_at",
+}
+
+// restoreBackupKey 上下文中保存创建账号时要恢复的备份，Worker选定主机后、启动前恢复
+type restoreBackupKey struct{}
+
+// openBackupStore 校验 BACKUP_SCHEDULE 并打开备份目的地。设置了定期备份时配置错误导致启动失败，
+// 否则只记录警告，立即备份和恢复时返回该错误。BACKUP_DESTINATION=storage 时使用对象存储
+func openBackupStore(cfg config.BackupConfig, objectStore storage.Store) (*cronSchedule, backup.Store, error) {
+	var schedule *cronSchedule
+	if cfg.Schedule != "" {
+		s, err := parseCron(cfg.Schedule)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid BACKUP_SCHEDULE: %v", err)
+		}
+		schedule = s
+	}
+
+	var store backup.Store
+	var err error
+	if cfg.Destination == BackupDestinationStorage {
+		if objectStore == nil {
+			err = fmt.Errorf("object storage is not configured, check STORAGE_DESTINATION")
+		} else {
+			store = storage.WithPrefix(objectStore, objectPrefixBackups)
+		}
+	} else {
+		store, err = backup.Open(backup.Config{
+			Destination:        cfg.Destination,
+			S3Endpoint:         cfg.S3Endpoint,
+			S3Region:           cfg.S3Region,
+			S3AccessKeyID:      cfg.S3AccessKeyID,
+			S3SecretAccessKey:  cfg.S3SecretAccessKey,
+			SFTPKeyFile:        cfg.SFTPKeyFile,
+			SFTPKnownHostsFile: cfg.SFTPKnownHostsFile,
+		})
+	}
+	if err != nil {
+		if schedule != nil {
+			return nil, nil, fmt.Errorf("invalid BACKUP_DESTINATION: %v", err)
+		}
+		slog.Warn("Session backups are not available", "destination", cfg.Destination, "error", err)
+		return nil, nil, nil
+	}
+	return schedule, store, nil
+}
+
+// getBackupStore 返回备份目的地，未正确配置时返回错误
+func (m *Manager) getBackupStore() (backup.Store, error) {
+	if m.backupStore == nil {
+		return nil, fmt.Errorf("backup destination is not configured, check BACKUP_DESTINATION")
+	}
+	return m.backupStore, nil
+}
+
+// StartBackupScheduler 按 BACKUP_SCHEDULE 定期备份所有已登录账号的会话目录
+func (m *Manager) StartBackupScheduler() {
+	if m.backupSchedule == nil {
+		return
+	}
+	slog.Info("Session backups enabled", "schedule", m.GetConfig().Backup.Schedule, "destination", m.backupStore.String(),
+		"retention_count", m.GetConfig().Backup.RetentionCount, "retention_days", m.GetConfig().Backup.RetentionDays)
+
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		for {
+			next := m.backupSchedule.Next(time.Now())
+			if next.IsZero() {
+				slog.Warn("BACKUP_SCHEDULE never matches, scheduled backups stopped", "schedule", m.GetConfig().Backup.Schedule)
+				return
+			}
+			m.backupMutex.Lock()
+			m.backupNext = &next
+			m.backupMutex.Unlock()
+
+			timer := time.NewTimer(time.Until(next))
+			select {
+			case <-m.stopCh:
+				timer.Stop()
+				return
+			case <-timer.C:
+				if !m.IsLeader() {
+					continue
+				}
+				if _, err := m.RunBackup(nil, model.BackupTriggerScheduled); err != nil {
+					slog.Info("Scheduled backup skipped", "reason", err)
+				}
+			}
+		}
+	}()
+}
+
+// RunBackup 以后台任务备份账号的会话目录，accountIDs为空时备份所有已登录且未停用的账号，完成后按保留策略清理旧备份
+func (m *Manager) RunBackup(accountIDs []string, trigger string) (*model.Job, error) {
+	store, err := m.getBackupStore()
+	if err != nil {
+		return nil, err
+	}
+	if !m.backupRun.TryLock() {
+		return nil, fmt.Errorf("a backup is already running")
+	}
+
+	accounts, skipped, err := m.backupTargets(accountIDs)
+	if err != nil {
+		m.backupRun.Unlock()
+		return nil, err
+	}
+
+	job, err := m.startJob(JobBackup, fmt.Sprintf("%s backup of %d sessions to %s", trigger, len(accounts), store.String()), len(accounts), func(r *jobRun) error {
+		defer m.backupRun.Unlock()
+		result := m.backupAccounts(r, store, accounts, trigger)
+		result.Skipped = skipped
+		r.SetResult(result)
+
+		now := time.Now()
+		m.backupMutex.Lock()
+		m.backupLast = result
+		m.backupLastAt = &now
+		m.backupMutex.Unlock()
+
+		if result.Failed > 0 {
+			return fmt.Errorf("%d of %d backups failed", result.Failed, len(accounts))
+		}
+		return nil
+	})
+	if err != nil {
+		m.backupRun.Unlock()
+		return nil, err
+	}
+	return job, nil
+}
+
+// backupTargets 返回要备份的账号快照和跳过的账号，指定的账号不存在时返回错误
+func (m *Manager) backupTargets(accountIDs []string) ([]model.Account, []string, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	candidates := make([]*model.Account, 0, len(m.accounts))
+	if len(accountIDs) == 0 {
+		for _, account := range m.accounts {
+			candidates = append(candidates, account)
+		}
+	} else {
+		for _, id := range accountIDs {
+			account, exists := m.accounts[id]
+			if !exists {
+				return nil, nil, fmt.Errorf("account %s not found", id)
+			}
+			candidates = append(candidates, account)
+		}
+	}
+
+	accounts := make([]model.Account, 0, len(candidates))
+	skipped := make([]string, 0)
+	for _, account := range candidates {
+		// 未登录的会话目录中没有可用的凭证，备份会覆盖掉之前有效的备份
+		if account.Status != "logged_in" || account.Disabled {
+			skipped = append(skipped, account.ID)
+			continue
+		}
+		accounts = append(accounts, *account)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].ID < accounts[j].ID })
+	sort.Strings(skipped)
+	return accounts, skipped, nil
+}
+
+// backupAccounts 按 BACKUP_CONCURRENCY 并发备份，取消时不再开始新的账号
+func (m *Manager) backupAccounts(r *jobRun, store backup.Store, accounts []model.Account, trigger string) *model.BackupRunResult {
+	start := time.Now()
+	result := &model.BackupRunResult{Backups: make([]*model.SessionBackup, 0, len(accounts))}
+	concurrency := max(m.GetConfig().Backup.Concurrency, 1)
+
+	var wg sync.WaitGroup
+	var resultMutex sync.Mutex
+	sem := make(chan struct{}, concurrency)
+	for i := range accounts {
+		if r.Cancelled() {
+			break
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(account *model.Account) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			record := m.backupAccount(r.Context(), store, account, trigger)
+			pruned := 0
+			if record.Status == model.BackupStatusSucceeded {
+				pruned = m.pruneBackups(r.Context(), store, account.ID)
+			}
+
+			resultMutex.Lock()
+			result.Backups = append(result.Backups, record)
+			result.Pruned += pruned
+			if record.Status == model.BackupStatusFailed {
+				result.Failed++
+			}
+			resultMutex.Unlock()
+			r.Advance(1)
+		}(&accounts[i])
+	}
+	wg.Wait()
+
+	sort.Slice(result.Backups, func(i, j int) bool { return result.Backups[i].AccountID < result.Backups[j].AccountID })
+	result.Duration = time.Since(start).Round(time.Millisecond).String()
+	slog.Info("Session backup finished", "trigger", trigger, "backups", len(result.Backups), "failed", result.Failed, "pruned", result.Pruned, "duration", result.Duration)
+	return result
+}
+
+// backupAccount 在Worker所在主机上通过一次性容器打包会话目录并上传到目的地，结果记录为备份记录
+func (m *Manager) backupAccount(ctx context.Context, store backup.Store, account *model.Account, trigger string) *model.SessionBackup {
+	now := time.Now().UTC()
+	record := &model.SessionBackup{
+		ID:          generateID("bak"),
+		AccountID:   account.ID,
+		HostID:      valueOrDefault(account.HostID, model.LocalHostID),
+		Destination: store.String(),
+		Trigger:     trigger,
+	}
+
+	data, err := m.sessionArchive(record.HostID, account.ID)
+	if err == nil {
+		record.Key = fmt.Sprintf("%s/%s.tar.gz", account.ID, now.Format("20060102T150405Z"))
+		record.SizeBytes = int64(len(data))
+		sum := sha256.Sum256(data)
+		record.SHA256 = hex.EncodeToString(sum[:])
+		err = store.Put(ctx, record.Key, data)
+	}
+	if err != nil {
+		record.Status = model.BackupStatusFailed
+		record.Error = err.Error()
+		record.Key = ""
+		slog.Error("Session backup failed", "account_id", account.ID, "host_id", record.HostID, "error", err)
+		m.emit(EventBackupFailed, account.ID, map[string]string{"backup_id": record.ID, "error": record.Error})
+	} else {
+		record.Status = model.BackupStatusSucceeded
+		slog.Info("Session backed up", "account_id", account.ID, "key", record.Key, "size_bytes", record.SizeBytes)
+	}
+
+	if err := m.db.Create(record).Error; err != nil {
+		slog.Error("Failed to save backup record", "account_id", account.ID, "error", err)
+	}
+	return record
+}
+
+// archiveSession 打包账号的会话目录为tar.gz。目录可能在远程主机上，打包在一次性容器中执行，
+// 经base64编码从标准输出返回
+func (m *Manager) archiveSession(hostID, accountID string) ([]byte, error) {
+	if err := validSessionName(accountID); err != nil {
+		return nil, err
+	}
+	// 账号ID作为位置参数传入，避免拼接进shell命令
+	output, err := m.runDocker(hostID, backupTimeout, "run", "--rm",
+		"-v", m.GetConfig().Worker.SessionDir+":/sessions:ro",
+		"--entrypoint", "sh",
+		m.GetConfig().Worker.Image,
+		"-c", `[ -d "/sessions/$1" ] || { echo "session directory not found" >&2; exit 3; }; tar -C /sessions -czf /tmp/session.tar.gz "$1" && base64 /tmp/session.tar.gz`, "sh", accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to archive session: %v", err)
+	}
+	// 解码时忽略base64输出中的换行
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(output))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode session archive: %v", err)
+	}
+	return data, nil
+}
+
+// restoreSession 用备份替换accountID在主机上的会话目录，在一次性容器中解压，只解出备份账号的目录；
+// accountID与备份账号不同时（克隆账号）解压后改名；开启会话加密时保存为加密归档
+func (m *Manager) restoreSession(ctx context.Context, hostID string, record *model.SessionBackup, accountID string) error {
+	if err := validSessionName(record.AccountID); err != nil {
+		return err
+	}
+	if err := validSessionName(accountID); err != nil {
+		return err
+	}
+	store, err := m.getBackupStore()
+	if err != nil {
+		return err
+	}
+	if record.Destination != store.String() {
+		return fmt.Errorf("backup %s is stored in %s, but the current destination is %s", record.ID, record.Destination, store.String())
+	}
+
+	data, err := store.Get(ctx, record.Key)
+	if err != nil {
+		return err
+	}
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != record.SHA256 {
+		return fmt.Errorf("backup %s failed checksum verification", record.ID)
+	}
+
+	if m.sessionSealEnabled() {
+		// 开启会话加密时备份加密后替换归档，明文不落盘；Worker启动时解压会去掉顶层目录，不需要改名
+		lock := m.sessionSealLock(accountID)
+		lock.Lock()
+		defer lock.Unlock()
+		if err := m.storeSealedSession(hostID, accountID, data); err != nil {
+			return fmt.Errorf("failed to restore session: %v", err)
+		}
+		if err := m.shredPlainSession(hostID, accountID); err != nil {
+			return fmt.Errorf("failed to remove plaintext session: %v", err)
+		}
+		slog.Info("Session restored from backup", "account_id", accountID, "backup_account_id", record.AccountID, "backup_id", record.ID, "host_id", hostID, "sealed", true)
+		return nil
+	}
+
+	if _, err := m.runDockerInput(hostID, backupTimeout, data, "run", "--rm", "-i",
+		"-v", m.GetConfig().Worker.SessionDir+":/sessions",
+		"--entrypoint", "sh",
+		m.GetConfig().Worker.Image,
+		"-c", `rm -rf "/sessions/$2" && mkdir -p /tmp/restore && tar -C /tmp/restore -xzf - "$1" && mv "/tmp/restore/$1" "/sessions/$2"`,
+		"sh", record.AccountID, accountID); err != nil {
+		return fmt.Errorf("failed to restore session: %v", err)
+	}
+	slog.Info("Session restored from backup", "account_id", accountID, "backup_account_id", record.AccountID, "backup_id", record.ID, "host_id", hostID)
+	return nil
+}
+
+// validSessionName 账号ID用作会话目录名，不能包含路径
+func validSessionName(accountID string) error {
+	if accountID == "" || strings.ContainsAny(accountID, `/\`) || strings.HasPrefix(accountID, ".") {
+		return fmt.Errorf("invalid account id %q", accountID)
+	}
+	return nil
+}
+
+// latestBackup 账号最近一次成功的备份
+func (m *Manager) latestBackup(accountID string) (*model.SessionBackup, error) {
+	var record model.SessionBackup
+	if err := m.db.Where("account_id = ? AND status = ?", accountID, model.BackupStatusSucceeded).
+		Order("created_at DESC").First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w %s", ErrNoBackup, accountID)
+		}
+		return nil, fmt.Errorf("failed to query backups: %v", err)
+	}
+	return &record, nil
+}
+
+// withRestoreBackup 创建账号时在上下文中指定要恢复的备份
+func withRestoreBackup(ctx context.Context, record *model.SessionBackup) context.Context {
+	return context.WithValue(ctx, restoreBackupKey{}, record)
+}
+
+// restoreBackupFrom 返回上下文中要恢复的备份，没有时返回nil
+func restoreBackupFrom(ctx context.Context) *model.SessionBackup {
+	record, _ := ctx.Value(restoreBackupKey{}).(*model.SessionBackup)
+	return record
+}
+
+// pruneBackups 按 BACKUP_RETENTION_COUNT 和 BACKUP_RETENTION_DAYS 删除账号在当前目的地的旧备份，
+// 最新的成功备份始终保留；失败记录超过保留天数后删除。返回删除的备份数
+func (m *Manager) pruneBackups(ctx context.Context, store backup.Store, accountID string) int {
+	cfg := m.GetConfig().Backup
+	var records []*model.SessionBackup
+	if err := m.db.Where("account_id = ? AND destination = ?", accountID, store.String()).
+		Order("created_at DESC").Find(&records).Error; err != nil {
+		slog.Warn("Failed to list backups for retention", "account_id", accountID, "error", err)
+		return 0
+	}
+
+	var cutoff time.Time
+	if cfg.RetentionDays > 0 {
+		cutoff = time.Now().AddDate(0, 0, -cfg.RetentionDays)
+	}
+	pruned, kept := 0, 0
+	for _, record := range records {
+		expired := !cutoff.IsZero() && record.CreatedAt.Before(cutoff)
+		if record.Status != model.BackupStatusSucceeded {
+			if expired {
+				m.db.Delete(record)
+			}
+			continue
+		}
+		kept++
+		if kept == 1 || (!expired && (cfg.RetentionCount <= 0 || kept <= cfg.RetentionCount)) {
+			continue
+		}
+		if err := store.Delete(ctx, record.Key); err != nil {
+			slog.Warn("Failed to delete expired backup", "account_id", accountID, "backup_id", record.ID, "error", err)
+			continue
+		}
+		if err := m.db.Delete(record).Error; err != nil {
+			slog.Warn("Failed to delete expired backup record", "backup_id", record.ID, "error", err)
+			continue
+		}
+		pruned++
+	}
+	return pruned
+}
+
+// ListBackups 分页查询备份记录，默认按备份时间倒序
+func (m *Manager) ListBackups(filter *model.BackupFilter, q *model.ListQuery) ([]*model.SessionBackup, int64, error) {
+	db := m.db.Model(&model.SessionBackup{})
+	if filter.AccountIDs != nil {
+		db = db.Where("account_id IN ?", filter.AccountIDs)
+	}
+
+	records := make([]*model.SessionBackup, 0)
+	total, err := findWithListQuery(db, q, backupColumns, "-created_at", &records)
+	if err != nil {
+		return nil, 0, err
+	}
+	return records, total, nil
+}
+
+// GetBackupStatus 定期备份的配置、下次执行时间和最近一次结果
+func (m *Manager) GetBackupStatus() *model.BackupStatus {
+	cfg := m.GetConfig().Backup
+	status := &model.BackupStatus{
+		Schedule:       cfg.Schedule,
+		Destination:    cfg.Destination,
+		RetentionCount: cfg.RetentionCount,
+		RetentionDays:  cfg.RetentionDays,
+	}
+	if m.backupStore != nil {
+		status.Destination = m.backupStore.String()
+	}
+
+	m.backupMutex.Lock()
+	defer m.backupMutex.Unlock()
+	status.NextRun = m.backupNext
+	status.LastRun = m.backupLast
+	status.LastRunAt = m.backupLastAt
+	return status
+}
@@ -0,0 +1,240 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// statusBanned 检测到封号信号后隔离中的账号状态
+const statusBanned = "banned"
+
+// banEvidenceTransitions 证据中保存的最近状态变化条数
+const banEvidenceTransitions = 20
+
+// ErrAccountNotBanned 账号没有处于隔离中的封号记录
+var ErrAccountNotBanned = errors.New("account is not quarantined")
+
+// banColumns 封号记录列表允许过滤和排序的字段
+var banColumns = map[string]string{
+	"id":          "id",
+	"account_id":  "account_id",
+	"source":      "source",
+	"status":      "status",
+	"released_at": "released_at",
+	"created_at":  "created_at",
+}
+
+// matchBanPattern 返回文本中出现的第一个 BAN_ERROR_PATTERNS 特征，没有时返回空
+func (m *Manager) matchBanPattern(text string) string {
+	if text == "" {
+		return ""
+	}
+	lower := strings.ToLower(text)
+	for _, pattern := range m.GetConfig().Ban.ErrorPatterns {
+		if strings.Contains(lower, strings.ToLower(pattern)) {
+			return pattern
+		}
+	}
+	return ""
+}
+
+// detectWorkerErrorBan Worker返回的错误信息包含封号特征时隔离账号
+func (m *Manager) detectWorkerErrorBan(accountID, workerPath string, workerErr *WorkerError) {
+	if !m.GetConfig().Ban.Enabled || workerErr.StatusCode == 0 {
+		return
+	}
+	pattern := m.matchBanPattern(workerErr.Message)
+	if pattern == "" {
+		return
+	}
+	go m.quarantineAccount(accountID, model.BanSourceWorkerError, pattern, &model.BanEvidence{
+		WorkerPath: workerPath,
+		StatusCode: workerErr.StatusCode,
+		Error:      workerErr.Message,
+	})
+}
+
+// detectLogoutBan Worker上报的注销原因包含封号特征时隔离账号
+func (m *Manager) detectLogoutBan(accountID, reason string) {
+	if !m.GetConfig().Ban.Enabled {
+		return
+	}
+	pattern := m.matchBanPattern(reason)
+	if pattern == "" {
+		return
+	}
+	go m.quarantineAccount(accountID, model.BanSourceLogout, pattern, &model.BanEvidence{Error: reason})
+}
+
+// detectLogoutLoop 已登录账号掉线后统计窗口内的掉线次数，达到 BAN_LOGOUT_LOOP_COUNT 时隔离账号。
+// 在持有mutex的状态变化中调用，查询和隔离在后台执行
+func (m *Manager) detectLogoutLoop(accountID string) {
+	cfg := m.GetConfig().Ban
+	if !cfg.Enabled || cfg.LogoutLoopCount <= 0 || cfg.LogoutLoopWindowMin <= 0 {
+		return
+	}
+
+	go func() {
+		lost := make([]string, 0, len(sessionLostStatuses))
+		for status := range sessionLostStatuses {
+			lost = append(lost, status)
+		}
+		var transitions []*model.StatusTransition
+		if err := m.db.Where("account_id = ? AND previous = ? AND status IN ? AND created_at >= ?",
+			accountID, "logged_in", lost, time.Now().Add(-time.Duration(cfg.LogoutLoopWindowMin)*time.Minute)).
+			Order("created_at").Find(&transitions).Error; err != nil {
+			slog.Warn("Failed to count recent logouts", "account_id", accountID, "error", err)
+			return
+		}
+		if len(transitions) < cfg.LogoutLoopCount {
+			return
+		}
+		logouts := make([]time.Time, 0, len(transitions))
+		for _, transition := range transitions {
+			logouts = append(logouts, transition.CreatedAt)
+		}
+		signal := fmt.Sprintf("%d logouts in %d minutes", len(logouts), cfg.LogoutLoopWindowMin)
+		m.quarantineAccount(accountID, model.BanSourceLogoutLoop, signal, &model.BanEvidence{Logouts: logouts})
+	}()
+}
+
+// quarantineAccount 隔离疑似被封的账号：状态改为banned并停用，保存证据并发布 account.banned 事件。
+// 只由Leader执行，已隔离的账号不重复记录
+func (m *Manager) quarantineAccount(accountID, source, signal string, evidence *model.BanEvidence) {
+	if !m.IsLeader() {
+		slog.Warn("Ban signal detected on a follower, leaving quarantine to the leader", "account_id", accountID, "source", source, "signal", signal)
+		return
+	}
+	if current, err := m.GetAccount(accountID); err != nil || current.Status == statusBanned {
+		return
+	}
+
+	// 在加锁前收集证据
+	if err := m.db.Where("account_id = ?", accountID).Order("created_at DESC").Limit(banEvidenceTransitions).
+		Find(&evidence.Transitions).Error; err != nil {
+		slog.Warn("Failed to load status history for ban evidence", "account_id", accountID, "error", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if status, err := m.FetchFromWorker(ctx, accountID, "/api/status"); err == nil {
+		evidence.WorkerStatus = status
+	}
+	cancel()
+	raw, _ := json.Marshal(evidence)
+
+	m.mutex.Lock()
+	account, exists := m.accounts[accountID]
+	if !exists || account.Status == statusBanned {
+		m.mutex.Unlock()
+		return
+	}
+	record := &model.BanRecord{
+		ID:             generateID("ban"),
+		AccountID:      accountID,
+		Phone:          account.Phone,
+		Source:         source,
+		Signal:         signal,
+		Evidence:       model.RawJSON(raw),
+		PreviousStatus: account.Status,
+		WasDisabled:    account.Disabled,
+		Status:         model.BanStatusActive,
+	}
+	if err := m.db.Create(record).Error; err != nil {
+		m.mutex.Unlock()
+		slog.Error("Failed to record ban", "account_id", accountID, "error", err)
+		return
+	}
+	if !account.Disabled {
+		if err := m.disableAccountLocked(account, "banned: "+signal); err != nil {
+			slog.Error("Failed to disable banned account", "account_id", accountID, "error", err)
+		}
+	}
+	m.UpdateAccountStatus(accountID, statusBanned, StatusCause{Source: StatusSourceBanDetector, Reason: fmt.Sprintf("%s: %s", source, signal)})
+	m.mutex.Unlock()
+
+	slog.Error("Account quarantined as banned", "account_id", accountID, "ban_id", record.ID, "source", source, "signal", signal)
+	m.emit(EventAccountBanned, accountID, map[string]string{
+		"ban_id": record.ID,
+		"source": source,
+		"signal": signal,
+	})
+}
+
+// ReleaseBan 解除账号的隔离：封号记录标记为已解除，恢复发送并重新查询Worker状态
+func (m *Manager) ReleaseBan(ctx context.Context, accountID, note string) (*model.BanRecord, error) {
+	if _, err := m.GetAccount(accountID); err != nil {
+		return nil, err
+	}
+
+	var record model.BanRecord
+	if err := m.db.Where("account_id = ? AND status = ?", accountID, model.BanStatusActive).
+		Order("created_at DESC").First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccountNotBanned
+		}
+		return nil, fmt.Errorf("failed to query ban record: %v", err)
+	}
+
+	now := time.Now()
+	note = strings.TrimSpace(note)
+	if err := m.db.Model(&model.BanRecord{}).Where("account_id = ? AND status = ?", accountID, model.BanStatusActive).
+		Updates(map[string]interface{}{
+			"status":       model.BanStatusReleased,
+			"release_note": note,
+			"released_at":  &now,
+		}).Error; err != nil {
+		return nil, fmt.Errorf("failed to release ban: %v", err)
+	}
+	record.Status = model.BanStatusReleased
+	record.ReleaseNote = note
+	record.ReleasedAt = &now
+
+	m.mutex.Lock()
+	account, exists := m.accounts[accountID]
+	var refresh *model.Account
+	if exists {
+		if !record.WasDisabled {
+			if err := m.enableAccountLocked(account); err != nil {
+				m.mutex.Unlock()
+				return nil, err
+			}
+		}
+		if account.Status == statusBanned {
+			// 状态由随后的Worker状态查询更新
+			m.UpdateAccountStatus(accountID, "running", apiCause(ctx, "ban released"))
+			copied := *account
+			refresh = &copied
+		}
+	}
+	m.mutex.Unlock()
+	if refresh != nil {
+		go m.checkWorkerStatus(refresh)
+	}
+
+	slog.Info("Account ban released", "account_id", accountID, "ban_id", record.ID, "note", note)
+	m.emit(EventAccountBanReleased, accountID, map[string]string{"ban_id": record.ID, "note": note})
+	return &record, nil
+}
+
+// ListBans 分页查询封号记录，默认按检测时间倒序
+func (m *Manager) ListBans(filter *model.BanFilter, q *model.ListQuery) ([]*model.BanRecord, int64, error) {
+	db := m.db.Model(&model.BanRecord{})
+	if filter.AccountIDs != nil {
+		db = db.Where("account_id IN ?", filter.AccountIDs)
+	}
+
+	records := make([]*model.BanRecord, 0)
+	total, err := findWithListQuery(db, q, banColumns, "-created_at", &records)
+	if err != nil {
+		return nil, 0, err
+	}
+	return records, total, nil
+}
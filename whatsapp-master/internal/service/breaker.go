@@ -0,0 +1,155 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// 熔断器状态
+const (
+	BreakerClosed   = "closed"
+	BreakerOpen     = "open"
+	BreakerHalfOpen = "half_open"
+)
+
+// statusUnreachable 熔断期间的账号状态
+const statusUnreachable = "unreachable"
+
+// circuitBreaker 单个Worker的代理熔断器
+type circuitBreaker struct {
+	state          string
+	failures       int       // 连续失败次数
+	openedAt       time.Time // 最近一次熔断时间
+	probeStartedAt time.Time // 半开状态下放行的探测请求开始时间
+	previousStatus string    // 熔断前的账号状态，恢复时还原
+}
+
+// CircuitOpenError Worker已熔断，请求被直接拒绝
+type CircuitOpenError struct {
+	AccountID  string
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("worker of account %s is unreachable, circuit breaker is open", e.AccountID)
+}
+
+// AllowWorkerRequest 检查账号的熔断器是否放行请求：熔断期间直接拒绝，冷却结束后每个冷却周期放行一个探测请求
+func (m *Manager) AllowWorkerRequest(accountID string) error {
+	cfg := m.GetConfig().Proxy
+	if cfg.BreakerThreshold <= 0 {
+		return nil
+	}
+	cooldown := time.Duration(cfg.BreakerCooldown) * time.Second
+
+	m.breakerMutex.Lock()
+	defer m.breakerMutex.Unlock()
+
+	breaker, exists := m.breakers[accountID]
+	if !exists || breaker.state == BreakerClosed {
+		return nil
+	}
+
+	now := time.Now()
+	if breaker.state == BreakerOpen {
+		if wait := breaker.openedAt.Add(cooldown).Sub(now); wait > 0 {
+			return &CircuitOpenError{AccountID: accountID, RetryAfter: wait}
+		}
+		breaker.state = BreakerHalfOpen
+		breaker.probeStartedAt = time.Time{}
+	}
+	// 探测请求没有结果（如调用方断开）时，超过一个冷却周期后放行下一个探测
+	if !breaker.probeStartedAt.IsZero() && now.Sub(breaker.probeStartedAt) < cooldown {
+		return &CircuitOpenError{AccountID: accountID, RetryAfter: breaker.probeStartedAt.Add(cooldown).Sub(now)}
+	}
+	breaker.probeStartedAt = now
+	return nil
+}
+
+// RecordWorkerResult 记录一次Worker请求的结果：连续失败达到阈值或探测失败时熔断并将账号标记为unreachable，
+// 成功时关闭熔断器并恢复账号熔断前的状态
+func (m *Manager) RecordWorkerResult(accountID string, err error) {
+	cfg := m.GetConfig().Proxy
+	if cfg.BreakerThreshold <= 0 {
+		return
+	}
+
+	m.breakerMutex.Lock()
+	breaker, exists := m.breakers[accountID]
+	if err == nil {
+		if !exists {
+			m.breakerMutex.Unlock()
+			return
+		}
+		delete(m.breakers, accountID)
+		m.breakerMutex.Unlock()
+
+		if breaker.state != BreakerClosed {
+			m.closeBreaker(accountID, breaker.previousStatus)
+		}
+		return
+	}
+
+	if !exists {
+		breaker = &circuitBreaker{state: BreakerClosed}
+		m.breakers[accountID] = breaker
+	}
+	breaker.failures++
+	opening := breaker.state == BreakerHalfOpen || (breaker.state == BreakerClosed && breaker.failures >= cfg.BreakerThreshold)
+	if !opening {
+		m.breakerMutex.Unlock()
+		return
+	}
+	tripped := breaker.state == BreakerClosed
+	breaker.state = BreakerOpen
+	breaker.openedAt = time.Now()
+	breaker.probeStartedAt = time.Time{}
+	failures := breaker.failures
+	m.breakerMutex.Unlock()
+
+	if !tripped {
+		slog.Warn("Worker probe failed, circuit breaker stays open", "account_id", accountID, "error", err)
+		return
+	}
+
+	m.mutex.Lock()
+	previous := ""
+	if account, exists := m.accounts[accountID]; exists {
+		previous = account.Status
+		m.UpdateAccountStatus(accountID, statusUnreachable, StatusCause{Source: StatusSourceBreaker, Reason: fmt.Sprintf("%d consecutive failures: %v", failures, err)})
+	}
+	m.mutex.Unlock()
+
+	m.breakerMutex.Lock()
+	if breaker, exists := m.breakers[accountID]; exists {
+		breaker.previousStatus = previous
+	}
+	m.breakerMutex.Unlock()
+
+	slog.Error("Worker unreachable, circuit breaker opened", "account_id", accountID, "failures", failures, "cooldown_seconds", cfg.BreakerCooldown, "error", err)
+	m.emit(EventWorkerUnreachable, accountID, map[string]interface{}{
+		"failures":         failures,
+		"cooldown_seconds": cfg.BreakerCooldown,
+		"error":            err.Error(),
+	})
+}
+
+// closeBreaker 熔断恢复，账号仍为unreachable时还原熔断前的状态
+func (m *Manager) closeBreaker(accountID, previousStatus string) {
+	m.mutex.Lock()
+	if account, exists := m.accounts[accountID]; exists && account.Status == statusUnreachable && previousStatus != "" {
+		m.UpdateAccountStatus(accountID, previousStatus, StatusCause{Source: StatusSourceBreaker, Reason: "worker reachable again"})
+	}
+	m.mutex.Unlock()
+
+	slog.Info("Worker reachable again, circuit breaker closed", "account_id", accountID)
+	m.emit(EventWorkerReachable, accountID, nil)
+}
+
+// resetBreaker Worker重建后清除熔断状态，账号状态由调用方设置
+func (m *Manager) resetBreaker(accountID string) {
+	m.breakerMutex.Lock()
+	defer m.breakerMutex.Unlock()
+	delete(m.breakers, accountID)
+}
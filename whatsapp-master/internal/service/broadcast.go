@@ -0,0 +1,172 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// Broadcast 将消息发送给去重后的联系人列表，发送账号为所有（或按条件筛选出的）已登录账号，
+// 联系人按轮询分配给各账号，复用批量发送的节流、限流和任务机制。tenantID不为空时只使用该租户的账号
+func (m *Manager) Broadcast(req *model.BroadcastRequest, tenantID string) (*model.BroadcastReport, error) {
+	filter := req.Senders
+	if filter == nil {
+		filter = &model.BroadcastFilter{}
+	}
+	senders, err := m.broadcastSenders(filter, tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipients := make([]model.BulkRecipient, 0, len(req.Contacts))
+	seen := make(map[string]bool)
+	for _, contact := range req.Contacts {
+		contact = strings.TrimSpace(contact)
+		key := broadcastContactKey(contact)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		recipients = append(recipients, model.BulkRecipient{Contact: contact})
+	}
+	if len(recipients) == 0 {
+		return nil, fmt.Errorf("no valid contacts")
+	}
+	duplicates := len(req.Contacts) - len(recipients)
+
+	batch, err := m.SendBulk(&model.BulkSendRequest{
+		AccountIDs:  senders,
+		Recipients:  recipients,
+		Message:     req.Message,
+		Concurrency: req.Concurrency,
+		IntervalMs:  req.IntervalMs,
+		Campaign:    req.Campaign,
+		TrackLinks:  req.TrackLinks,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	m.bulkMutex.Lock()
+	if stored, exists := m.bulkBatches[batch.ID]; exists {
+		stored.Duplicates = duplicates
+	}
+	m.bulkMutex.Unlock()
+
+	slog.Info("Broadcast started", "batch_id", batch.ID, "recipients", len(recipients), "duplicates", duplicates, "senders", len(senders))
+	return m.BroadcastReport(batch.ID)
+}
+
+// broadcastSenders 按筛选条件确定广播的发送账号：已登录、未停用，按账号ID排序保证分配稳定
+func (m *Manager) broadcastSenders(filter *model.BroadcastFilter, tenantID string) ([]string, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	var wanted map[string]bool
+	if len(filter.AccountIDs) > 0 {
+		wanted = make(map[string]bool, len(filter.AccountIDs))
+		for _, id := range filter.AccountIDs {
+			account, exists := m.accounts[id]
+			if !exists || (tenantID != "" && account.TenantID != tenantID) {
+				return nil, fmt.Errorf("account %s not found", id)
+			}
+			wanted[id] = true
+		}
+	}
+
+	senders := make([]string, 0)
+	for _, account := range m.accounts {
+		if account.Status != "logged_in" || account.Disabled {
+			continue
+		}
+		if tenantID != "" && account.TenantID != tenantID {
+			continue
+		}
+		if wanted != nil && !wanted[account.ID] {
+			continue
+		}
+		if filter.Pool != "" && account.Pool != filter.Pool {
+			continue
+		}
+		if !hasAllTags(account.Tags, filter.Tags) {
+			continue
+		}
+		senders = append(senders, account.ID)
+	}
+	if len(senders) == 0 {
+		return nil, fmt.Errorf("no logged-in enabled accounts match the sender filter")
+	}
+	sort.Strings(senders)
+	return senders, nil
+}
+
+// hasAllTags 账号标签是否包含全部指定标签
+func hasAllTags(tags model.StringList, required []string) bool {
+	for _, tag := range required {
+		if !tags.Contains(tag) {
+			return false
+		}
+	}
+	return true
+}
+
+// broadcastContactKey 联系人去重键：去掉 @c.us 后缀、加号和号码中的空格、横线、括号
+func broadcastContactKey(contact string) string {
+	contact = strings.TrimSuffix(contact, "@c.us")
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '+', ' ', '-', '(', ')', '.':
+			return -1
+		}
+		return r
+	}, contact)
+}
+
+// BroadcastReport 汇总批量发送批次的结果：按账号统计分配、成功和失败数，并按失败原因归类
+func (m *Manager) BroadcastReport(batchID string) (*model.BroadcastReport, error) {
+	batch, err := m.GetBulkBatch(batchID)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &model.BroadcastReport{
+		ID:         batch.ID,
+		JobID:      batch.JobID,
+		Status:     batch.Status,
+		Total:      batch.Total,
+		Duplicates: batch.Duplicates,
+		Sent:       batch.Sent,
+		Failed:     batch.Failed,
+		Pending:    batch.Total - batch.Sent - batch.Failed,
+		Accounts:   make([]*model.BroadcastAccountReport, 0, len(batch.AccountIDs)),
+		CreatedAt:  batch.CreatedAt,
+		FinishedAt: batch.FinishedAt,
+	}
+
+	accounts := make(map[string]*model.BroadcastAccountReport, len(batch.AccountIDs))
+	for _, accountID := range batch.AccountIDs {
+		accounts[accountID] = &model.BroadcastAccountReport{AccountID: accountID}
+		report.Accounts = append(report.Accounts, accounts[accountID])
+	}
+	for _, result := range batch.Results {
+		account, exists := accounts[result.AccountID]
+		if !exists {
+			continue
+		}
+		account.Assigned++
+		switch result.Status {
+		case "sent":
+			account.Sent++
+		case "failed":
+			account.Failed++
+			if report.Errors == nil {
+				report.Errors = make(map[string]int)
+			}
+			report.Errors[result.Error]++
+		}
+	}
+	return report, nil
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// SendBulk 创建批量发送批次，并在后台按账号分发发送。收件人数超过审批阈值时返回 *ApprovalRequiredError，批准后才发送
+func (m *Manager) SendBulk(req *model.BulkSendRequest) (*model.BulkBatch, error) {
+	if m.needsApproval(len(req.Recipients)) {
+		return nil, m.requestBulkApproval(req)
+	}
+	return m.startBulk(req)
+}
+
+// startBulk 立即开始批量发送
+func (m *Manager) startBulk(req *model.BulkSendRequest) (*model.BulkBatch, error) {
+	senders, err := m.resolveBulkSenders(req.AccountIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	concurrency := req.Concurrency
+	if concurrency <= 0 {
+		concurrency = m.GetConfig().Bulk.Concurrency
+	}
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	interval := req.IntervalMs
+	if interval <= 0 {
+		interval = m.GetConfig().Bulk.IntervalMs
+	}
+
+	batch := &model.BulkBatch{
+		ID:         generateID("bulk"),
+		Status:     "running",
+		AccountIDs: senders,
+		Campaign:   req.Campaign,
+		Total:      len(req.Recipients),
+		Results:    make([]*model.BulkResult, 0, len(req.Recipients)),
+		CreatedAt:  time.Now(),
+	}
+
+	// 轮询分配收件人到各个账号
+	queues := make(map[string][]int)
+	for i, recipient := range req.Recipients {
+		accountID := senders[i%len(senders)]
+		batch.Results = append(batch.Results, &model.BulkResult{
+			Contact:   recipient.Contact,
+			AccountID: accountID,
+			Status:    "pending",
+		})
+		queues[accountID] = append(queues[accountID], i)
+	}
+
+	// 任务启动前登记批次，任务goroutine可能立即开始更新结果
+	m.bulkMutex.Lock()
+	m.evictBulkBatchesLocked(batch.CreatedAt)
+	m.bulkBatches[batch.ID] = batch
+	m.bulkMutex.Unlock()
+
+	job, err := m.startJob(JobBulkSend, "bulk batch "+batch.ID, batch.Total, func(r *jobRun) error {
+		m.runBulkBatch(r, batch, req, queues, concurrency, time.Duration(interval)*time.Millisecond)
+		return nil
+	})
+	if err != nil {
+		m.bulkMutex.Lock()
+		delete(m.bulkBatches, batch.ID)
+		m.bulkMutex.Unlock()
+		return nil, err
+	}
+
+	m.bulkMutex.Lock()
+	batch.JobID = job.ID
+	m.bulkMutex.Unlock()
+
+	slog.Info("Bulk batch started", "batch_id", batch.ID, "recipients", batch.Total, "accounts", len(senders))
+	return m.GetBulkBatch(batch.ID)
+}
+
+// GetBulkBatch 获取批量发送批次的快照
+func (m *Manager) GetBulkBatch(batchID string) (*model.BulkBatch, error) {
+	m.bulkMutex.RLock()
+	defer m.bulkMutex.RUnlock()
+
+	batch, exists := m.bulkBatches[batchID]
+	if !exists || m.bulkBatchExpired(batch, time.Now()) {
+		return nil, fmt.Errorf("bulk batch %s not found", batchID)
+	}
+
+	snapshot := *batch
+	snapshot.Results = make([]*model.BulkResult, len(batch.Results))
+	for i, result := range batch.Results {
+		r := *result
+		snapshot.Results[i] = &r
+	}
+	return &snapshot, nil
+}
+
+// evictBulkBatchesLocked 移除已过保留期的批次。批次只保存在内存中，在创建新批次时清理，
+// 因此内存占用以保留期内创建的批次数为上限。调用方需持有 m.bulkMutex 写锁
+func (m *Manager) evictBulkBatchesLocked(now time.Time) {
+	for id, batch := range m.bulkBatches {
+		if m.bulkBatchExpired(batch, now) {
+			delete(m.bulkBatches, id)
+		}
+	}
+}
+
+// bulkBatchExpired 批次是否已结束超过 BULK_BATCH_RETENTION_HOURS，过期批次视为不存在
+func (m *Manager) bulkBatchExpired(batch *model.BulkBatch, now time.Time) bool {
+	retention := time.Duration(m.GetConfig().Bulk.BatchRetentionHours) * time.Hour
+	if retention <= 0 {
+		retention = 24 * time.Hour
+	}
+	return batch.FinishedAt != nil && now.Sub(*batch.FinishedAt) > retention
+}
+
+// resolveBulkSenders 确定参与批量发送的账号
+func (m *Manager) resolveBulkSenders(accountIDs []string) ([]string, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	senders := make([]string, 0)
+	if len(accountIDs) == 0 {
+		for _, account := range m.accounts {
+			if account.Status == "logged_in" && !account.Disabled {
+				senders = append(senders, account.ID)
+			}
+		}
+	} else {
+		for _, id := range accountIDs {
+			account, exists := m.accounts[id]
+			if !exists {
+				return nil, fmt.Errorf("account %s not found", id)
+			}
+			if account.Status != "logged_in" {
+				slog.Info("Bulk send skipping account", "account_id", id, "status", account.Status)
+				continue
+			}
+			if account.Disabled {
+				slog.Info("Bulk send skipping disabled account", "account_id", id)
+				continue
+			}
+			senders = append(senders, account.ID)
+		}
+	}
+
+	if len(senders) == 0 {
+		return nil, fmt.Errorf("no logged-in enabled accounts available for bulk send")
+	}
+	return senders, nil
+}
+
+// runBulkBatch 执行批量发送，每个账号串行发送并节流，账号之间受全局并发限制
+func (m *Manager) runBulkBatch(r *jobRun, batch *model.BulkBatch, req *model.BulkSendRequest, queues map[string][]int, concurrency int, interval time.Duration) {
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for accountID, indexes := range queues {
+		wg.Add(1)
+		go func(accountID string, indexes []int) {
+			defer wg.Done()
+			for n, idx := range indexes {
+				if n > 0 && interval > 0 {
+					select {
+					case <-r.Context().Done():
+						return
+					case <-m.stopCh:
+						return
+					case <-time.After(interval):
+					}
+				}
+				// 达到账号每分钟限流时等待，而不是让收件人直接失败
+				if r.Cancelled() || !m.waitSendRate(accountID) {
+					return
+				}
+
+				recipient := req.Recipients[idx]
+				message := renderTemplate(req.Message, recipient)
+
+				sem <- struct{}{}
+				m.bulkMutex.Lock()
+				batch.Results[idx].Status = "sending"
+				m.bulkMutex.Unlock()
+
+				ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+				_, err := m.SendMessage(ctx, &model.MessageRequest{
+					AccountID:  accountID,
+					Contact:    recipient.Contact,
+					Message:    message,
+					Campaign:   req.Campaign,
+					TrackLinks: req.TrackLinks,
+				})
+				cancel()
+				<-sem
+
+				m.bulkMutex.Lock()
+				result := batch.Results[idx]
+				if err != nil {
+					result.Status = "failed"
+					result.Error = err.Error()
+					batch.Failed++
+				} else {
+					now := time.Now()
+					result.Status = "sent"
+					result.SentAt = &now
+					batch.Sent++
+				}
+				m.bulkMutex.Unlock()
+				r.Advance(1)
+			}
+		}(accountID, indexes)
+	}
+
+	wg.Wait()
+
+	m.bulkMutex.Lock()
+	now := time.Now()
+	batch.Status = "completed"
+	if batch.Sent+batch.Failed < batch.Total {
+		// 取消或关闭期间中断，剩余收件人保持pending
+		batch.Status = "interrupted"
+		if r.Cancelled() {
+			batch.Status = "cancelled"
+		}
+	}
+	batch.FinishedAt = &now
+	status, sent, failed := batch.Status, batch.Sent, batch.Failed
+	m.bulkMutex.Unlock()
+
+	r.SetResult(map[string]interface{}{"batch_id": batch.ID, "sent": sent, "failed": failed})
+
+	slog.Info("Bulk batch finished", "batch_id", batch.ID, "status", status, "sent", sent, "failed", failed)
+}
+
+// templatePlaceholder 渲染后仍未替换的模板占位符
+var templatePlaceholder = regexp.MustCompile(`\{\{[^{}]+\}\}`)
+
+// renderTemplate 使用收件人变量渲染消息模板
+func renderTemplate(tpl string, recipient model.BulkRecipient) string {
+	pairs := []string{"{{contact}}", recipient.Contact}
+	for k, v := range recipient.Variables {
+		pairs = append(pairs, "{{"+k+"}}", v)
+	}
+	return strings.NewReplacer(pairs...).Replace(tpl)
+}
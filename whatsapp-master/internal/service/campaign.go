@@ -0,0 +1,406 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// 活动状态
+const (
+	CampaignDraft           = "draft"
+	CampaignPendingApproval = "pending_approval" // 收件人超过审批阈值，批准后开始发送
+	CampaignRunning         = "running"
+	CampaignPaused          = "paused"
+	CampaignCompleted       = "completed"
+)
+
+// campaignColumns 活动列表允许过滤和排序的字段
+var campaignColumns = map[string]string{
+	"id":         "id",
+	"name":       "name",
+	"status":     "status",
+	"created_at": "created_at",
+	"started_at": "started_at",
+}
+
+// campaignRecipientColumns 活动收件人列表允许过滤和排序的字段
+var campaignRecipientColumns = map[string]string{
+	"id":         "id",
+	"contact":    "contact",
+	"status":     "status",
+	"account_id": "account_id",
+	"sent_at":    "sent_at",
+}
+
+// CreateCampaign 创建草稿状态的活动
+func (m *Manager) CreateCampaign(req *model.CreateCampaignRequest) (*model.Campaign, error) {
+	for _, id := range req.AccountIDs {
+		if _, err := m.GetAccount(id); err != nil {
+			return nil, err
+		}
+	}
+
+	var count int64
+	m.db.Model(&model.Campaign{}).Where("name = ?", req.Name).Count(&count)
+	if count > 0 {
+		return nil, fmt.Errorf("campaign %s already exists", req.Name)
+	}
+
+	campaign := &model.Campaign{
+		ID:         generateID("cmp"),
+		Name:       req.Name,
+		Status:     CampaignDraft,
+		AccountIDs: model.StringList(req.AccountIDs),
+		Message:    req.Message,
+		IntervalMs: req.IntervalMs,
+		TrackLinks: req.TrackLinks,
+	}
+	recipients := make([]*model.CampaignRecipient, 0, len(req.Recipients))
+	for _, r := range req.Recipients {
+		recipients = append(recipients, &model.CampaignRecipient{
+			CampaignID: campaign.ID,
+			Contact:    r.Contact,
+			Variables:  model.StringMap(r.Variables),
+			Status:     "pending",
+		})
+	}
+
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(campaign).Error; err != nil {
+			return err
+		}
+		return tx.CreateInBatches(recipients, 100).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save campaign: %v", err)
+	}
+
+	slog.Info("Campaign created", "campaign_id", campaign.ID, "name", campaign.Name, "recipients", len(recipients), "accounts", len(req.AccountIDs))
+	return m.GetCampaign(campaign.ID)
+}
+
+// GetCampaign 获取活动及其发送进度
+func (m *Manager) GetCampaign(campaignID string) (*model.Campaign, error) {
+	var campaign model.Campaign
+	if err := m.db.Where("id = ?", campaignID).First(&campaign).Error; err != nil {
+		return nil, fmt.Errorf("campaign %s not found", campaignID)
+	}
+	campaign.Progress = m.campaignProgress(campaign.ID)
+	return &campaign, nil
+}
+
+// ListCampaigns 分页查询活动
+func (m *Manager) ListCampaigns(q *model.ListQuery) ([]*model.Campaign, int64, error) {
+	campaigns := make([]*model.Campaign, 0)
+	total, err := findWithListQuery(m.db.Model(&model.Campaign{}), q, campaignColumns, "-created_at", &campaigns)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, campaign := range campaigns {
+		campaign.Progress = m.campaignProgress(campaign.ID)
+	}
+	return campaigns, total, nil
+}
+
+// ListCampaignRecipients 分页查询活动收件人及发送结果
+func (m *Manager) ListCampaignRecipients(campaignID string, q *model.ListQuery) ([]*model.CampaignRecipient, int64, error) {
+	if _, err := m.GetCampaign(campaignID); err != nil {
+		return nil, 0, err
+	}
+
+	recipients := make([]*model.CampaignRecipient, 0)
+	db := m.db.Model(&model.CampaignRecipient{}).Where("campaign_id = ?", campaignID)
+	total, err := findWithListQuery(db, q, campaignRecipientColumns, "id", &recipients)
+	if err != nil {
+		return nil, 0, err
+	}
+	return recipients, total, nil
+}
+
+// campaignProgress 按状态统计活动收件人
+func (m *Manager) campaignProgress(campaignID string) *model.CampaignProgress {
+	var rows []struct {
+		Status string
+		Count  int64
+	}
+	m.db.Model(&model.CampaignRecipient{}).
+		Select("status, COUNT(*) AS count").
+		Where("campaign_id = ?", campaignID).
+		Group("status").
+		Scan(&rows)
+
+	progress := &model.CampaignProgress{}
+	for _, row := range rows {
+		progress.Total += row.Count
+		switch row.Status {
+		case "pending":
+			progress.Pending = row.Count
+		case "sending":
+			progress.Sending = row.Count
+		case "sent":
+			progress.Sent = row.Count
+		case "failed":
+			progress.Failed = row.Count
+		case "skipped":
+			progress.Skipped = row.Count
+		}
+	}
+	return progress
+}
+
+// StartCampaign 开始或继续发送活动
+func (m *Manager) StartCampaign(campaignID string) (*model.Campaign, error) {
+	campaign, err := m.GetCampaign(campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != CampaignDraft && campaign.Status != CampaignPaused {
+		return nil, fmt.Errorf("campaign %s is %s", campaignID, campaign.Status)
+	}
+	// 首次开始发送时收件人超过审批阈值，转为待审批
+	if campaign.Status == CampaignDraft && m.needsApproval(int(campaign.Progress.Total)) && !m.campaignApproved(campaign.ID) {
+		return nil, m.requestCampaignApproval(campaign, int(campaign.Progress.Total))
+	}
+
+	if err := m.launchCampaign(campaign); err != nil {
+		return nil, err
+	}
+	return m.GetCampaign(campaignID)
+}
+
+// PauseCampaign 暂停发送中的活动，正在发送的消息会先完成
+func (m *Manager) PauseCampaign(campaignID string) (*model.Campaign, error) {
+	campaign, err := m.GetCampaign(campaignID)
+	if err != nil {
+		return nil, err
+	}
+	if campaign.Status != CampaignRunning {
+		return nil, fmt.Errorf("campaign %s is %s", campaignID, campaign.Status)
+	}
+
+	// 先更新状态，发送任务结束时不会再重复发出暂停事件
+	m.db.Model(&model.Campaign{}).Where("id = ?", campaignID).Update("status", CampaignPaused)
+
+	m.campaignMutex.Lock()
+	if jobID, running := m.campaignRuns[campaignID]; running {
+		m.CancelJob(jobID)
+	}
+	m.campaignMutex.Unlock()
+
+	m.emit(EventCampaignPaused, "", map[string]string{"campaign_id": campaignID, "name": campaign.Name})
+	slog.Info("Campaign paused", "campaign_id", campaignID)
+	return m.GetCampaign(campaignID)
+}
+
+// ResumeCampaigns 重启或成为Leader后继续执行之前处于发送中的活动
+func (m *Manager) ResumeCampaigns() {
+	var campaigns []*model.Campaign
+	m.db.Where("status = ?", CampaignRunning).Find(&campaigns)
+	for _, campaign := range campaigns {
+		slog.Info("Resuming campaign", "campaign_id", campaign.ID, "name", campaign.Name)
+		if err := m.launchCampaign(campaign); err != nil {
+			slog.Error("Failed to resume campaign", "campaign_id", campaign.ID, "error", err)
+		}
+	}
+}
+
+// launchCampaign 标记活动为发送中并在后台分发
+func (m *Manager) launchCampaign(campaign *model.Campaign) error {
+	m.campaignMutex.Lock()
+	defer m.campaignMutex.Unlock()
+
+	if _, running := m.campaignRuns[campaign.ID]; running {
+		return fmt.Errorf("campaign %s is still stopping, try again shortly", campaign.ID)
+	}
+
+	// 上次异常中断时处于发送中的收件人，重新排队
+	m.db.Model(&model.CampaignRecipient{}).
+		Where("campaign_id = ? AND status = ?", campaign.ID, "sending").
+		Update("status", "pending")
+
+	now := time.Now()
+	updates := map[string]interface{}{"status": CampaignRunning, "last_error": ""}
+	if campaign.StartedAt == nil {
+		updates["started_at"] = &now
+		campaign.StartedAt = &now
+	}
+	if err := m.db.Model(campaign).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to update campaign: %v", err)
+	}
+	campaign.Status = CampaignRunning
+
+	job, err := m.startJob(JobCampaign, "campaign "+campaign.Name, 0, func(r *jobRun) error {
+		defer func() {
+			m.campaignMutex.Lock()
+			delete(m.campaignRuns, campaign.ID)
+			m.campaignMutex.Unlock()
+		}()
+		return m.runCampaign(r, campaign)
+	})
+	if err != nil {
+		return err
+	}
+	m.campaignRuns[campaign.ID] = job.ID
+	campaign.JobID = job.ID
+	m.db.Model(campaign).Update("job_id", job.ID)
+
+	m.emit(EventCampaignStarted, "", map[string]string{"campaign_id": campaign.ID, "name": campaign.Name})
+	return nil
+}
+
+// runCampaign 将待发送的收件人分发给活动中已登录的账号，空闲的账号领取下一个收件人
+func (m *Manager) runCampaign(r *jobRun, campaign *model.Campaign) error {
+	ctx := r.Context()
+	senders, err := m.resolveBulkSenders(campaign.AccountIDs)
+	if err != nil {
+		slog.Warn("Campaign paused", "campaign_id", campaign.ID, "reason", err)
+		m.db.Model(campaign).Updates(map[string]interface{}{"status": CampaignPaused, "last_error": err.Error()})
+		m.emit(EventCampaignPaused, "", map[string]string{"campaign_id": campaign.ID, "name": campaign.Name, "error": err.Error()})
+		return err
+	}
+
+	var pending []*model.CampaignRecipient
+	m.db.Where("campaign_id = ? AND status = ?", campaign.ID, "pending").Order("id").Find(&pending)
+	slog.Info("Campaign running", "campaign_id", campaign.ID, "pending", len(pending), "accounts", len(senders))
+	r.SetTotal(len(pending))
+
+	interval := time.Duration(campaign.IntervalMs) * time.Millisecond
+	if interval <= 0 {
+		interval = time.Duration(m.GetConfig().Bulk.IntervalMs) * time.Millisecond
+	}
+
+	work := make(chan *model.CampaignRecipient)
+	go func() {
+		defer close(work)
+		for _, recipient := range pending {
+			select {
+			case <-ctx.Done():
+				return
+			case <-m.stopCh:
+				return
+			case work <- recipient:
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	var disabled atomic.Int32
+	for _, accountID := range senders {
+		wg.Add(1)
+		go func(accountID string) {
+			defer wg.Done()
+			for n := 0; ; n++ {
+				if n > 0 && interval > 0 {
+					select {
+					case <-ctx.Done():
+						return
+					case <-m.stopCh:
+						return
+					case <-time.After(interval):
+					}
+				}
+				if !m.waitSendRate(accountID) {
+					return
+				}
+				// 发送中被停用的账号不再领取收件人，剩余收件人由其他账号发送
+				if m.checkAccountEnabled(accountID) != nil {
+					slog.Info("Campaign account disabled, leaving distribution", "campaign_id", campaign.ID, "account_id", accountID)
+					disabled.Add(1)
+					return
+				}
+
+				recipient, ok := <-work
+				if !ok {
+					return
+				}
+				m.sendCampaignMessage(campaign, recipient, accountID)
+				r.Advance(1)
+			}
+		}(accountID)
+	}
+	wg.Wait()
+
+	if m.shuttingDown() || !m.IsLeader() {
+		// 关闭或失去Leader身份时保持running，由重启后的实例或新Leader继续
+		return nil
+	}
+	if ctx.Err() != nil {
+		// 通过任务接口取消时，活动同样转为暂停
+		res := m.db.Model(&model.Campaign{}).
+			Where("id = ? AND status = ?", campaign.ID, CampaignRunning).
+			Update("status", CampaignPaused)
+		if res.RowsAffected > 0 {
+			slog.Info("Campaign paused by job cancellation", "campaign_id", campaign.ID)
+			m.emit(EventCampaignPaused, "", map[string]string{"campaign_id": campaign.ID, "name": campaign.Name})
+		}
+		return nil
+	}
+
+	var remaining int64
+	m.db.Model(&model.CampaignRecipient{}).
+		Where("campaign_id = ? AND status IN ?", campaign.ID, []string{"pending", "sending"}).
+		Count(&remaining)
+	if remaining > 0 {
+		if int(disabled.Load()) == len(senders) {
+			reason := "all campaign accounts are disabled"
+			m.db.Model(campaign).Updates(map[string]interface{}{"status": CampaignPaused, "last_error": reason})
+			slog.Warn("Campaign paused", "campaign_id", campaign.ID, "reason", reason)
+			m.emit(EventCampaignPaused, "", map[string]string{"campaign_id": campaign.ID, "name": campaign.Name, "error": reason})
+		}
+		return nil
+	}
+
+	now := time.Now()
+	m.db.Model(campaign).Updates(map[string]interface{}{"status": CampaignCompleted, "finished_at": &now})
+	progress := m.campaignProgress(campaign.ID)
+	slog.Info("Campaign completed", "campaign_id", campaign.ID, "sent", progress.Sent, "failed", progress.Failed, "skipped", progress.Skipped)
+	m.emit(EventCampaignCompleted, "", map[string]interface{}{"campaign_id": campaign.ID, "name": campaign.Name, "progress": progress})
+	r.SetResult(progress)
+	return nil
+}
+
+// sendCampaignMessage 通过指定账号向单个收件人发送活动消息，已退订的联系人跳过
+func (m *Manager) sendCampaignMessage(campaign *model.Campaign, recipient *model.CampaignRecipient, accountID string) {
+	recipient.AccountID = accountID
+	if m.isOptedOut(recipient.Contact) {
+		recipient.Status = "skipped"
+		recipient.Error = "contact opted out"
+		m.db.Save(recipient)
+		return
+	}
+
+	recipient.Status = "sending"
+	m.db.Save(recipient)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	result, err := m.SendMessage(ctx, &model.MessageRequest{
+		AccountID:  accountID,
+		Contact:    recipient.Contact,
+		Message:    renderTemplate(campaign.Message, model.BulkRecipient{Contact: recipient.Contact, Variables: recipient.Variables}),
+		Campaign:   campaign.Name,
+		TrackLinks: campaign.TrackLinks,
+	})
+	cancel()
+
+	if err != nil {
+		recipient.Status = "failed"
+		recipient.Error = err.Error()
+	} else {
+		now := time.Now()
+		recipient.Status = "sent"
+		recipient.Error = ""
+		recipient.WorkerMessageID = workerMessageID(result)
+		recipient.SentAt = &now
+	}
+	if err := m.db.Save(recipient).Error; err != nil {
+		slog.Error("Failed to update campaign recipient", "campaign_id", campaign.ID, "contact", recipient.Contact, "error", err)
+	}
+}
@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sort"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// ChaosEnabled 是否开启故障注入
+func (m *Manager) ChaosEnabled() bool {
+	return m.GetConfig().Chaos.Enabled
+}
+
+// InjectWorkerFault 为账号的Worker调用注入延迟或随机失败
+func (m *Manager) InjectWorkerFault(accountID string, req *model.ChaosDelayRequest) (*model.ChaosFault, error) {
+	if _, err := m.GetAccount(accountID); err != nil {
+		return nil, err
+	}
+
+	fault := &model.ChaosFault{
+		AccountID: accountID,
+		DelayMs:   req.DelayMs,
+		ErrorRate: req.ErrorRate,
+		ExpiresAt: time.Now().Add(time.Duration(req.DurationSeconds) * time.Second),
+	}
+
+	m.chaosMutex.Lock()
+	m.chaosFaults[accountID] = fault
+	m.chaosMutex.Unlock()
+
+	slog.Info("Chaos fault injected", "account_id", accountID, "delay_ms", fault.DelayMs, "error_rate", fault.ErrorRate, "expires_at", fault.ExpiresAt)
+	return fault, nil
+}
+
+// ClearWorkerFault 清除账号上注入的故障
+func (m *Manager) ClearWorkerFault(accountID string) {
+	m.chaosMutex.Lock()
+	delete(m.chaosFaults, accountID)
+	m.chaosMutex.Unlock()
+
+	slog.Info("Chaos fault cleared", "account_id", accountID)
+}
+
+// ListWorkerFaults 列出仍然生效的故障
+func (m *Manager) ListWorkerFaults() []*model.ChaosFault {
+	m.chaosMutex.Lock()
+	defer m.chaosMutex.Unlock()
+
+	now := time.Now()
+	faults := make([]*model.ChaosFault, 0, len(m.chaosFaults))
+	for id, fault := range m.chaosFaults {
+		if now.After(fault.ExpiresAt) {
+			delete(m.chaosFaults, id)
+			continue
+		}
+		faults = append(faults, fault)
+	}
+	sort.Slice(faults, func(i, j int) bool {
+		return faults[i].AccountID < faults[j].AccountID
+	})
+	return faults
+}
+
+// ApplyWorkerFault 在调用Worker前执行注入的故障，未开启或无故障时立即返回
+func (m *Manager) ApplyWorkerFault(ctx context.Context, accountID string) error {
+	if !m.GetConfig().Chaos.Enabled {
+		return nil
+	}
+
+	m.chaosMutex.Lock()
+	fault, exists := m.chaosFaults[accountID]
+	if exists && time.Now().After(fault.ExpiresAt) {
+		delete(m.chaosFaults, accountID)
+		exists = false
+	}
+	m.chaosMutex.Unlock()
+	if !exists {
+		return nil
+	}
+
+	if fault.DelayMs > 0 {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(fault.DelayMs) * time.Millisecond):
+		}
+	}
+	if fault.ErrorRate > 0 && rand.Float64() < fault.ErrorRate {
+		return &WorkerError{Message: "chaos: injected worker failure"}
+	}
+	return nil
+}
+
+// KillWorker 强制杀掉账号的Worker容器，不更新账号状态，用于验证故障检测
+func (m *Manager) KillWorker(accountID string) error {
+	account, err := m.GetAccount(accountID)
+	if err != nil {
+		return err
+	}
+	m.mutex.RLock()
+	hostID := account.HostID
+	m.mutex.RUnlock()
+
+	containerName := fmt.Sprintf("whatsapp-worker-%s", accountID)
+	if err := m.hostRuntime(hostID).Kill(containerName); err != nil {
+		return fmt.Errorf("failed to kill container %s: %v", containerName, err)
+	}
+
+	slog.Info("Chaos killed worker container", "container", containerName)
+	return nil
+}
+
+// ForceAccountStatus 强制切换账号状态，下一次状态轮询可能会恢复真实状态
+func (m *Manager) ForceAccountStatus(accountID, status string) (*model.Account, error) {
+	if _, err := m.GetAccount(accountID); err != nil {
+		return nil, err
+	}
+
+	m.UpdateAccountStatusSafe(accountID, status, StatusCause{Source: StatusSourceChaos, Reason: "forced by chaos endpoint"})
+	slog.Info("Chaos forced account status", "account_id", accountID, "status", status)
+	return m.GetAccount(accountID)
+}
@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// 会话历史每页的默认和最大消息数
+const (
+	DefaultChatHistoryLimit = 50
+	MaxChatHistoryLimit     = 500
+)
+
+// GetChatHistory 获取与联系人的一页会话历史（before 为上一页返回的 next_before，为空时从最新消息开始）。
+// refresh 时先让Worker抓取这一页的历史写入数据库，Worker不可用时返回已缓存的历史并附带警告
+func (m *Manager) GetChatHistory(ctx context.Context, accountID, contact, before string, limit int, refresh bool) (*model.ChatHistory, string, error) {
+	if _, err := m.GetAccount(accountID); err != nil {
+		return nil, "", err
+	}
+	if limit <= 0 {
+		limit = DefaultChatHistoryLimit
+	}
+	limit = min(limit, MaxChatHistoryLimit)
+
+	var cursor *model.Message
+	if before != "" {
+		cursor = &model.Message{}
+		if err := m.db.Where("id = ? AND account_id = ?", before, accountID).First(cursor).Error; err != nil {
+			return nil, "", fmt.Errorf("invalid before cursor: message %s not found", before)
+		}
+	}
+
+	history := &model.ChatHistory{AccountID: accountID, Contact: contact}
+	warning := ""
+	workerHasMore := false
+	if refresh {
+		cached, hasMore, err := m.scrapeChatHistory(ctx, accountID, contact, cursor, limit)
+		if err != nil {
+			slog.Warn("Failed to fetch chat history from worker", "account_id", accountID, "contact", contact, "error", err)
+			warning = fmt.Sprintf("worker unavailable, showing cached history: %v", err)
+		}
+		history.Cached, workerHasMore = cached, hasMore
+	}
+
+	messages := make([]*model.Message, 0, limit+1)
+	db := m.chatMessages(accountID, contact)
+	if cursor != nil {
+		db = db.Where("(timestamp < ? OR (timestamp = ? AND id < ?))", cursor.Timestamp, cursor.Timestamp, cursor.ID)
+	}
+	if err := db.Order("timestamp DESC, id DESC").Limit(limit + 1).Find(&messages).Error; err != nil {
+		return nil, "", fmt.Errorf("failed to query chat history: %v", err)
+	}
+	if len(messages) > limit {
+		messages = messages[:limit]
+		history.HasMore = true
+	}
+	history.HasMore = history.HasMore || workerHasMore
+
+	// 查询按时间倒序取最近的一页，返回时改为从旧到新
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	history.Messages = messages
+	if history.HasMore && len(messages) > 0 {
+		history.NextBefore = messages[0].ID
+	}
+	return history, warning, nil
+}
+
+// ExportChatHistory 导出已缓存的与联系人的全部会话历史，按时间从旧到新排列，可按时间范围过滤
+func (m *Manager) ExportChatHistory(accountID, contact string, since, until *time.Time) ([]*model.Message, error) {
+	if _, err := m.GetAccount(accountID); err != nil {
+		return nil, err
+	}
+
+	messages := make([]*model.Message, 0)
+	db := m.chatMessages(accountID, contact)
+	if since != nil {
+		db = db.Where("timestamp >= ?", *since)
+	}
+	if until != nil {
+		db = db.Where("timestamp <= ?", *until)
+	}
+	if err := db.Order("timestamp ASC, id ASC").Find(&messages).Error; err != nil {
+		return nil, fmt.Errorf("failed to query chat history: %v", err)
+	}
+	return messages, nil
+}
+
+// chatMessages 账号与联系人之间的消息（入站消息的联系人带 @c.us 后缀，出站消息不带）
+func (m *Manager) chatMessages(accountID, contact string) *gorm.DB {
+	number := strings.TrimSuffix(contact, "@c.us")
+	return m.db.Model(&model.Message{}).
+		Where("account_id = ? AND contact IN ?", accountID, []string{number, number + "@c.us"})
+}
+
+// scrapeChatHistory 让Worker抓取早于cursor的一页会话历史，把数据库中还没有的消息写入缓存，
+// 返回新写入的消息数和Worker上是否还有更早的消息
+func (m *Manager) scrapeChatHistory(ctx context.Context, accountID, contact string, cursor *model.Message, limit int) (int, bool, error) {
+	// 先同步最近的入站消息，让它们经过正常的事件和统计流程，而不是作为历史消息写入
+	if _, err := m.SyncMessages(ctx, accountID); err != nil {
+		return 0, false, err
+	}
+
+	query := url.Values{}
+	query.Set("contact", contact)
+	if cursor != nil {
+		// WhatsApp的时间戳精确到秒，需要包含与cursor同一时刻的消息，多取的已缓存消息按ID去重
+		var sameTime int64
+		m.chatMessages(accountID, contact).Where("timestamp = ?", cursor.Timestamp).Count(&sameTime)
+		limit = min(limit+int(sameTime), MaxChatHistoryLimit)
+		query.Set("before", strconv.FormatInt(cursor.Timestamp.UnixMilli()+1, 10))
+	}
+	query.Set("limit", strconv.Itoa(limit))
+	result, err := m.FetchFromWorker(ctx, accountID, "/api/chats/messages?"+query.Encode())
+	if err != nil {
+		return 0, false, err
+	}
+	var page model.WorkerChatHistory
+	if err := decodeWorkerData(result, &page); err != nil {
+		return 0, false, err
+	}
+
+	lock := m.messageSyncLock(accountID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	cached := 0
+	for _, item := range page.Messages {
+		if item.ID == "" {
+			continue
+		}
+		var count int64
+		m.db.Model(&model.Message{}).
+			Where("account_id = ? AND worker_message_id = ?", accountID, item.ID).
+			Count(&count)
+		if count > 0 {
+			continue
+		}
+
+		// 历史消息只写入缓存，不触发事件、Webhook和每日统计
+		msg := &model.Message{
+			ID:              generateID("msg"),
+			AccountID:       accountID,
+			Direction:       "inbound",
+			Contact:         item.From,
+			Type:            item.Type,
+			Body:            item.Body,
+			Preview:         messagePreview(item.Type, item.Body),
+			Status:          "received",
+			WorkerMessageID: item.ID,
+			Timestamp:       time.UnixMilli(item.Timestamp),
+		}
+		if item.FromMe {
+			msg.Direction = "outbound"
+			msg.Contact = strings.TrimSuffix(item.To, "@c.us")
+			msg.Status = ackStatus(item.Ack)
+			if msg.Status == "" {
+				msg.Status = "sent"
+			}
+		}
+		if err := m.db.Create(msg).Error; err != nil {
+			slog.Error("Failed to cache chat history message", "account_id", accountID, "error", err)
+			continue
+		}
+		cached++
+	}
+	if cached > 0 {
+		slog.Info("Chat history cached", "account_id", accountID, "contact", contact, "messages", cached)
+	}
+	return cached, page.HasMore, nil
+}
@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"whatsapp-aggregator/internal/logging"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/tracing"
+)
+
+// cloneSource 校验克隆请求并返回源账号的快照和要恢复的备份（不复制会话时为nil）
+func (m *Manager) cloneSource(sourceID string, req *model.CloneAccountRequest) (model.Account, *model.SessionBackup, error) {
+	m.mutex.RLock()
+	account, exists := m.accounts[sourceID]
+	var source model.Account
+	if exists {
+		source = *account
+	}
+	m.mutex.RUnlock()
+	if !exists {
+		return source, nil, fmt.Errorf("account %s not found", sourceID)
+	}
+	if req.AccountID == sourceID {
+		return source, nil, fmt.Errorf("clone must have a different account id than %s", sourceID)
+	}
+
+	var record *model.SessionBackup
+	if req.CopySession {
+		var err error
+		if record, err = m.latestBackup(sourceID); err != nil {
+			return source, nil, err
+		}
+	}
+	return source, record, nil
+}
+
+// cloneLoginRequest 按源账号的配置构造创建请求，请求中的标签和主机覆盖源账号
+func cloneLoginRequest(source *model.Account, req *model.CloneAccountRequest) *model.LoginRequest {
+	resources := source.Resources
+	runtime := source.Runtime
+	login := &model.LoginRequest{
+		AccountID: req.AccountID,
+		Tags:      source.Tags,
+		Pool:      source.Pool,
+		Owner: &model.AccountOwner{
+			Operator: source.OwnerOperator,
+			Team:     source.OwnerTeam,
+			Email:    source.OwnerEmail,
+			Channel:  source.OwnerChannel,
+		},
+		Resources:         &resources,
+		Runtime:           &runtime,
+		HostID:            req.HostID,
+		TenantID:          source.TenantID,
+		HardwareProfileID: source.HardwareProfileID,
+	}
+	if req.Tags != nil {
+		login.Tags = normalizeTags(req.Tags)
+	}
+	if source.Proxy.IP != "" {
+		login.ProxyConfig = &model.ProxyConfig{
+			IP:       source.Proxy.IP,
+			Port:     source.Proxy.Port,
+			Username: source.Proxy.Username,
+			Password: string(source.Proxy.Password),
+			Protocol: source.Proxy.Protocol,
+			Region:   source.ProxyRegion,
+		}
+	}
+	return login
+}
+
+// CloneAccount 复制源账号的配置创建新账号并启动Worker；copy_session 时在Worker启动前用源账号最近一次成功的备份初始化会话目录。
+// 名称、发送限速、预热方案、模拟输入、保活和状态轮询设置在Worker启动后写入新账号
+func (m *Manager) CloneAccount(ctx context.Context, sourceID string, req *model.CloneAccountRequest) (_ *model.Account, err error) {
+	ctx, span := tracing.Start(ctx, "Manager.CloneAccount", tracing.KindInternal,
+		tracing.String("account.id", req.AccountID),
+		tracing.String("account.source_id", sourceID),
+	)
+	defer func() { span.Finish(err) }()
+
+	source, record, err := m.cloneSource(sourceID, req)
+	if err != nil {
+		return nil, err
+	}
+	if record != nil {
+		ctx = withRestoreBackup(ctx, record)
+	}
+
+	account, err := m.CreateAccount(ctx, cloneLoginRequest(&source, req))
+	if err != nil {
+		return nil, err
+	}
+
+	name := req.Name
+	if name == "" {
+		name = account.ID
+	}
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	// CreateAccount 返回的是副本，设置需写到内存中的账号上
+	if live, exists := m.accounts[account.ID]; exists {
+		account = live
+	}
+	if err := m.db.Model(account).Updates(map[string]interface{}{
+		"name":                name,
+		"send_limit":          source.SendLimit,
+		"send_limit_burst":    source.SendLimitBurst,
+		"warmup_profile":      source.WarmupProfile,
+		"typing_simulation":   source.TypingSimulation,
+		"keep_alive_off":      source.KeepAliveOff,
+		"status_poll_seconds": source.StatusPollSeconds,
+	}).Error; err != nil {
+		return account.Clone(), fmt.Errorf("account created but failed to copy settings: %v", err)
+	}
+	account.Name = name
+	account.SendLimit = source.SendLimit
+	account.SendLimitBurst = source.SendLimitBurst
+	account.WarmupProfile = source.WarmupProfile
+	account.TypingSimulation = source.TypingSimulation
+	account.KeepAliveOff = source.KeepAliveOff
+	account.StatusPollSeconds = source.StatusPollSeconds
+
+	logging.FromContext(ctx).Info("Account cloned", "account_id", account.ID, "source_id", sourceID, "session_copied", record != nil)
+	return account.Clone(), nil
+}
+
+// CloneAccountAsync 校验请求后在后台任务中克隆账号，任务记录Worker启动阶段，结果为创建的账号
+func (m *Manager) CloneAccountAsync(ctx context.Context, sourceID string, req *model.CloneAccountRequest) (*model.Job, error) {
+	source, _, err := m.cloneSource(sourceID, req)
+	if err != nil {
+		return nil, err
+	}
+	if req.HostID != "" {
+		if err := m.checkHostPin(req.HostID); err != nil {
+			return nil, err
+		}
+	}
+	m.mutex.RLock()
+	_, exists := m.accounts[req.AccountID]
+	var limitErr error
+	if !exists && source.TenantID != "" {
+		limitErr = m.checkTenantWorkerLimitLocked(source.TenantID)
+	}
+	m.mutex.RUnlock()
+	if exists {
+		return nil, fmt.Errorf("account %s already exists", req.AccountID)
+	}
+	if limitErr != nil {
+		return nil, limitErr
+	}
+	if err := m.checkPortCapacity(1); err != nil {
+		return nil, err
+	}
+
+	requestID := logging.RequestID(ctx)
+	return m.startTenantJob(source.TenantID, JobCreateAccount, fmt.Sprintf("clone account %s to %s", sourceID, req.AccountID), 1, func(r *jobRun) error {
+		account, err := m.CloneAccount(logging.WithRequestID(tracing.ContinueFrom(r.Context(), ctx), requestID), sourceID, req)
+		if err != nil {
+			return err
+		}
+		r.SetResult(account)
+		r.Advance(1)
+		return nil
+	})
+}
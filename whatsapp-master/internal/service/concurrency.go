@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// workerSemaphore 单个账号的Worker代理并发控制，空位释放时关闭wake通知排队的请求
+type workerSemaphore struct {
+	inFlight int
+	queued   int
+	rejected int64
+	wake     chan struct{}
+}
+
+// WorkerBusyError 账号转发中的请求已达上限，且队列已满或等待超时
+type WorkerBusyError struct {
+	AccountID string
+	Limit     int
+	Queued    bool // 是否排队等待过
+}
+
+func (e *WorkerBusyError) Error() string {
+	if e.Queued {
+		return fmt.Sprintf("worker of account %s is busy: timed out waiting for one of %d concurrent request slots", e.AccountID, e.Limit)
+	}
+	return fmt.Sprintf("worker of account %s is busy: %d concurrent requests in flight and the queue is full", e.AccountID, e.Limit)
+}
+
+// AcquireWorkerSlot 占用账号的一个Worker代理并发名额，已达上限时排队等待，
+// 队列已满或等待超过 PROXY_QUEUE_TIMEOUT_SECONDS 时返回 WorkerBusyError。成功时返回释放名额的函数
+func (m *Manager) AcquireWorkerSlot(ctx context.Context, accountID string) (func(), error) {
+	cfg := m.GetConfig().Proxy
+	if cfg.MaxConcurrent <= 0 {
+		return func() {}, nil
+	}
+
+	m.workerSlotMutex.Lock()
+	sem, exists := m.workerSlots[accountID]
+	if !exists {
+		sem = &workerSemaphore{wake: make(chan struct{})}
+		m.workerSlots[accountID] = sem
+	}
+	if sem.inFlight < cfg.MaxConcurrent {
+		sem.inFlight++
+		m.workerSlotMutex.Unlock()
+		return m.workerSlotRelease(sem), nil
+	}
+	if sem.queued >= cfg.QueueSize {
+		sem.rejected++
+		m.workerSlotMutex.Unlock()
+		return nil, &WorkerBusyError{AccountID: accountID, Limit: cfg.MaxConcurrent}
+	}
+	sem.queued++
+
+	timeout := time.NewTimer(time.Duration(cfg.QueueTimeoutSeconds) * time.Second)
+	defer timeout.Stop()
+	for {
+		wake := sem.wake
+		m.workerSlotMutex.Unlock()
+
+		select {
+		case <-wake:
+			m.workerSlotMutex.Lock()
+			// 上限可能在排队期间被修改，按最新配置判断
+			if sem.inFlight < m.GetConfig().Proxy.MaxConcurrent || m.GetConfig().Proxy.MaxConcurrent <= 0 {
+				sem.queued--
+				sem.inFlight++
+				m.workerSlotMutex.Unlock()
+				return m.workerSlotRelease(sem), nil
+			}
+		case <-ctx.Done():
+			m.workerSlotMutex.Lock()
+			sem.queued--
+			m.workerSlotMutex.Unlock()
+			return nil, ctx.Err()
+		case <-timeout.C:
+			m.workerSlotMutex.Lock()
+			sem.queued--
+			sem.rejected++
+			m.workerSlotMutex.Unlock()
+			return nil, &WorkerBusyError{AccountID: accountID, Limit: cfg.MaxConcurrent, Queued: true}
+		}
+	}
+}
+
+// workerSlotRelease 返回只生效一次的释放函数，释放后唤醒排队的请求
+func (m *Manager) workerSlotRelease(sem *workerSemaphore) func() {
+	released := false
+	return func() {
+		m.workerSlotMutex.Lock()
+		defer m.workerSlotMutex.Unlock()
+		if released {
+			return
+		}
+		released = true
+		sem.inFlight--
+		close(sem.wake)
+		sem.wake = make(chan struct{})
+	}
+}
+
+// WorkerConcurrencyMetrics 各账号转发中、排队和被拒绝的Worker代理请求数，按账号ID排序
+func (m *Manager) WorkerConcurrencyMetrics() []*model.WorkerConcurrencyMetrics {
+	m.workerSlotMutex.Lock()
+	defer m.workerSlotMutex.Unlock()
+
+	metrics := make([]*model.WorkerConcurrencyMetrics, 0, len(m.workerSlots))
+	for accountID, sem := range m.workerSlots {
+		metrics = append(metrics, &model.WorkerConcurrencyMetrics{
+			AccountID: accountID,
+			InFlight:  sem.inFlight,
+			Queued:    sem.queued,
+			Rejected:  sem.rejected,
+		})
+	}
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].AccountID < metrics[j].AccountID })
+	return metrics
+}
@@ -0,0 +1,428 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"whatsapp-aggregator/internal/config"
+	"whatsapp-aggregator/internal/logging"
+	"whatsapp-aggregator/internal/model"
+)
+
+// ErrConfigOverrideNotFound 要删除的配置项没有被覆盖过
+var ErrConfigOverrideNotFound = errors.New("config override not found")
+
+// configSetting 可通过配置接口修改并持久化的配置项
+type configSetting struct {
+	hot   bool                                 // 修改后立即生效，否则保存后在下次启动时生效
+	min   float64                              // 数值下限
+	max   float64                              // 数值上限，0表示不限制
+	field func(cfg *config.Config) interface{} // 返回配置字段的指针
+	apply func(cfg *config.Config)             // 字段修改后的附加动作
+	sync  func(m *Manager)                     // 热更新生效后同步Manager的运行时状态，调用方持有mutex
+}
+
+// configSettings 配置接口可修改的配置项，键为 分组.字段
+var configSettings = map[string]configSetting{
+	"server.host":      {field: func(c *config.Config) interface{} { return &c.Server.Host }},
+	"server.port":      {min: 1, max: 65535, field: func(c *config.Config) interface{} { return &c.Server.Port }},
+	"worker.mode":      {field: func(c *config.Config) interface{} { return &c.Worker.Mode }},
+	"worker.network":   {field: func(c *config.Config) interface{} { return &c.Worker.Network }},
+	"worker.basePort":  {min: 1, max: 65535, field: func(c *config.Config) interface{} { return &c.Worker.BasePort }},
+	"worker.portRange": {min: 1, field: func(c *config.Config) interface{} { return &c.Worker.PortRange }},
+	"worker.namespace": {field: func(c *config.Config) interface{} { return &c.Worker.Namespace }},
+
+	"worker.image": {hot: true, field: func(c *config.Config) interface{} { return &c.Worker.Image }},
+	"worker.portRanges": {
+		hot:   true,
+		field: func(c *config.Config) interface{} { return &c.Worker.PortRanges },
+		sync:  (*Manager).syncPortRangesLocked,
+	},
+
+	"rateLimit.globalPerMinute":  {hot: true, field: func(c *config.Config) interface{} { return &c.RateLimit.GlobalPerMinute }},
+	"rateLimit.globalBurst":      {hot: true, field: func(c *config.Config) interface{} { return &c.RateLimit.GlobalBurst }},
+	"rateLimit.accountPerMinute": {hot: true, field: func(c *config.Config) interface{} { return &c.RateLimit.AccountPerMinute }},
+	"rateLimit.accountBurst":     {hot: true, field: func(c *config.Config) interface{} { return &c.RateLimit.AccountBurst }},
+
+	"quota.ratePerMinute": {hot: true, field: func(c *config.Config) interface{} { return &c.Quota.RatePerMinute }},
+	"quota.dailyQuota":    {hot: true, field: func(c *config.Config) interface{} { return &c.Quota.DailyQuota }},
+	"quota.warnRatio":     {hot: true, max: 1, field: func(c *config.Config) interface{} { return &c.Quota.WarnRatio }},
+
+	"retry.maxAttempts":  {hot: true, min: 1, field: func(c *config.Config) interface{} { return &c.Retry.MaxAttempts }},
+	"retry.backoffMs":    {hot: true, field: func(c *config.Config) interface{} { return &c.Retry.BackoffMs }},
+	"retry.maxBackoffMs": {hot: true, field: func(c *config.Config) interface{} { return &c.Retry.MaxBackoffMs }},
+
+	"bulk.concurrency":         {hot: true, min: 1, field: func(c *config.Config) interface{} { return &c.Bulk.Concurrency }},
+	"bulk.intervalMs":          {hot: true, field: func(c *config.Config) interface{} { return &c.Bulk.IntervalMs }},
+	"bulk.batchRetentionHours": {hot: true, min: 1, field: func(c *config.Config) interface{} { return &c.Bulk.BatchRetentionHours }},
+
+	"proxy.timeout":             {hot: true, field: func(c *config.Config) interface{} { return &c.Proxy.Timeout }},
+	"proxy.retryAttempts":       {hot: true, field: func(c *config.Config) interface{} { return &c.Proxy.RetryAttempts }},
+	"proxy.retryBackoffMs":      {hot: true, field: func(c *config.Config) interface{} { return &c.Proxy.RetryBackoffMs }},
+	"proxy.breakerThreshold":    {hot: true, field: func(c *config.Config) interface{} { return &c.Proxy.BreakerThreshold }},
+	"proxy.breakerCooldown":     {hot: true, field: func(c *config.Config) interface{} { return &c.Proxy.BreakerCooldown }},
+	"proxy.maxConcurrent":       {hot: true, field: func(c *config.Config) interface{} { return &c.Proxy.MaxConcurrent }},
+	"proxy.queueSize":           {hot: true, field: func(c *config.Config) interface{} { return &c.Proxy.QueueSize }},
+	"proxy.queueTimeoutSeconds": {hot: true, field: func(c *config.Config) interface{} { return &c.Proxy.QueueTimeoutSeconds }},
+
+	"supervisor.maxRestarts":    {hot: true, field: func(c *config.Config) interface{} { return &c.Supervisor.MaxRestarts }},
+	"supervisor.backoffSeconds": {hot: true, field: func(c *config.Config) interface{} { return &c.Supervisor.BackoffSeconds }},
+	"supervisor.maxBackoff":     {hot: true, field: func(c *config.Config) interface{} { return &c.Supervisor.MaxBackoff }},
+
+	"statusPoll.intervalSeconds":      {hot: true, min: 5, max: 86400, field: func(c *config.Config) interface{} { return &c.StatusPoll.IntervalSeconds }},
+	"statusPoll.loginIntervalSeconds": {hot: true, min: 1, max: 3600, field: func(c *config.Config) interface{} { return &c.StatusPoll.LoginIntervalSeconds }},
+
+	"typing.charsPerSecond": {hot: true, min: 1, field: func(c *config.Config) interface{} { return &c.Typing.CharsPerSecond }},
+	"typing.minDelayMs":     {hot: true, field: func(c *config.Config) interface{} { return &c.Typing.MinDelayMs }},
+	"typing.maxDelayMs":     {hot: true, max: 60000, field: func(c *config.Config) interface{} { return &c.Typing.MaxDelayMs }},
+	"typing.jitterPercent":  {hot: true, max: 100, field: func(c *config.Config) interface{} { return &c.Typing.JitterPercent }},
+
+	"log.level": {
+		hot:   true,
+		field: func(c *config.Config) interface{} { return &c.Log.Level },
+		apply: func(c *config.Config) {
+			if err := logging.SetLevel(c.Log.Level); err == nil {
+				c.Log.Level = logging.Level()
+			}
+		},
+	},
+}
+
+// parseConfigInput 把配置接口的嵌套JSON展开为 分组.字段 形式并校验取值
+func parseConfigInput(input map[string]interface{}) (map[string]interface{}, error) {
+	values := make(map[string]interface{})
+	for section, raw := range input {
+		if section == "db" {
+			return nil, fmt.Errorf("db settings cannot be changed through the config API")
+		}
+		fields, ok := raw.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid config section %s: must be an object", section)
+		}
+		for name, rawValue := range fields {
+			key := section + "." + name
+			setting, exists := configSettings[key]
+			if !exists {
+				return nil, fmt.Errorf("unknown config key %s", key)
+			}
+			value, err := decodeConfigValue(key, setting, rawValue)
+			if err != nil {
+				return nil, err
+			}
+			values[key] = value
+		}
+	}
+	return values, nil
+}
+
+// decodeConfigValue 按配置字段类型转换并校验JSON值
+func decodeConfigValue(key string, setting configSetting, raw interface{}) (interface{}, error) {
+	var value interface{}
+	switch reflect.ValueOf(setting.field(&config.Config{})).Elem().Kind() {
+	case reflect.String:
+		s, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s: must be a string", key)
+		}
+		s = strings.TrimSpace(s)
+		if s == "" {
+			return nil, fmt.Errorf("invalid %s: must not be empty", key)
+		}
+		switch key {
+		case "log.level":
+			if err := logging.ValidateLevel(s); err != nil {
+				return nil, err
+			}
+		case "worker.portRanges":
+			ranges, err := parsePortRanges(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %v", key, err)
+			}
+			s = formatPortRanges(ranges)
+		}
+		value = s
+	case reflect.Int:
+		n, ok := raw.(float64)
+		if !ok || n != math.Trunc(n) {
+			return nil, fmt.Errorf("invalid %s: must be an integer", key)
+		}
+		if err := checkConfigRange(key, setting, n); err != nil {
+			return nil, err
+		}
+		value = int(n)
+	case reflect.Float64:
+		n, ok := raw.(float64)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s: must be a number", key)
+		}
+		if err := checkConfigRange(key, setting, n); err != nil {
+			return nil, err
+		}
+		value = n
+	case reflect.Bool:
+		b, ok := raw.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid %s: must be a boolean", key)
+		}
+		value = b
+	}
+	return value, nil
+}
+
+func checkConfigRange(key string, setting configSetting, n float64) error {
+	if n < setting.min {
+		return fmt.Errorf("invalid %s: must be at least %v", key, setting.min)
+	}
+	if setting.max > 0 && n > setting.max {
+		return fmt.Errorf("invalid %s: must be at most %v", key, setting.max)
+	}
+	return nil
+}
+
+// setConfigValue 修改配置字段，value 需已由 decodeConfigValue 转换
+func setConfigValue(cfg *config.Config, setting configSetting, value interface{}) {
+	reflect.ValueOf(setting.field(cfg)).Elem().Set(reflect.ValueOf(value))
+	if setting.apply != nil {
+		setting.apply(cfg)
+	}
+}
+
+// setConfigValueLocked 在当前配置的副本上修改后整体替换，再同步运行时状态。
+// 已发布的配置快照不会被修改，并发读取无需加锁；调用方需持有mutex，保证修改不会相互覆盖
+func (m *Manager) setConfigValueLocked(setting configSetting, value interface{}) {
+	next := m.GetConfig().Clone()
+	setConfigValue(next, setting, value)
+	m.config.Store(next)
+	if setting.sync != nil {
+		setting.sync(m)
+	}
+}
+
+// decodeConfigOverride 解析数据库中保存的配置项
+func decodeConfigOverride(override *model.ConfigOverride) (configSetting, interface{}, error) {
+	setting, exists := configSettings[override.Key]
+	if !exists {
+		return setting, nil, fmt.Errorf("unknown config key %s", override.Key)
+	}
+	var raw interface{}
+	if err := json.Unmarshal([]byte(override.Value), &raw); err != nil {
+		return setting, nil, fmt.Errorf("invalid value for %s: %v", override.Key, err)
+	}
+	value, err := decodeConfigValue(override.Key, setting, raw)
+	return setting, value, err
+}
+
+// applyConfigOverrides 启动时把数据库中保存的配置项覆盖到环境变量配置上，返回已应用的配置项
+func applyConfigOverrides(db *gorm.DB, cfg *config.Config) (map[string]string, error) {
+	var overrides []*model.ConfigOverride
+	if err := db.Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("failed to load config overrides: %v", err)
+	}
+
+	applied := make(map[string]string, len(overrides))
+	for _, override := range overrides {
+		setting, value, err := decodeConfigOverride(override)
+		if err != nil {
+			slog.Warn("Ignoring invalid config override", "key", override.Key, "error", err)
+			continue
+		}
+		setConfigValue(cfg, setting, value)
+		applied[override.Key] = override.Value
+	}
+	if len(applied) > 0 {
+		slog.Info("Applied config overrides", "count", len(applied))
+	}
+	return applied, nil
+}
+
+// UpdateConfig 校验并保存配置项，可热更新的立即生效，其余在重启后生效
+func (m *Manager) UpdateConfig(input map[string]interface{}) (*model.ConfigUpdateResult, error) {
+	values, err := parseConfigInput(input)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if err := m.saveConfigOverridesLocked(values); err != nil {
+		return nil, err
+	}
+
+	result := &model.ConfigUpdateResult{Applied: []string{}, PendingRestart: []string{}}
+	for _, key := range sortedKeys(values) {
+		setting := configSettings[key]
+		if !setting.hot {
+			result.PendingRestart = append(result.PendingRestart, key)
+			continue
+		}
+		m.setConfigValueLocked(setting, values[key])
+		result.Applied = append(result.Applied, key)
+	}
+	slog.Info("Config updated", "applied", result.Applied, "pending_restart", result.PendingRestart)
+	return result, nil
+}
+
+// saveConfigOverridesLocked 保存配置项，调用方需持有mutex
+func (m *Manager) saveConfigOverridesLocked(values map[string]interface{}) error {
+	if len(values) == 0 {
+		return nil
+	}
+	encoded := make(map[string]string, len(values))
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		for key, value := range values {
+			data, err := json.Marshal(value)
+			if err != nil {
+				return err
+			}
+			override := &model.ConfigOverride{Key: key, Value: string(data), UpdatedAt: time.Now()}
+			if err := tx.Save(override).Error; err != nil {
+				return err
+			}
+			encoded[key] = override.Value
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save config: %v", err)
+	}
+	for key, value := range encoded {
+		m.configOverrides[key] = value
+	}
+	return nil
+}
+
+// ListConfigOverrides 返回所有已保存的配置项
+func (m *Manager) ListConfigOverrides() ([]*model.ConfigOverride, error) {
+	var overrides []*model.ConfigOverride
+	if err := m.db.Order("config_key").Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("failed to list config overrides: %v", err)
+	}
+	for _, override := range overrides {
+		setting, value, err := decodeConfigOverride(override)
+		if err != nil {
+			// 保留原始值，便于排查后删除
+			override.Decoded = override.Value
+			continue
+		}
+		override.Decoded = value
+		override.HotReload = setting.hot
+	}
+	return overrides, nil
+}
+
+// DeleteConfigOverride 删除已保存的配置项，恢复为环境变量中的值
+func (m *Manager) DeleteConfigOverride(key string) (*model.ConfigUpdateResult, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	res := m.db.Where("config_key = ?", key).Delete(&model.ConfigOverride{})
+	if res.Error != nil {
+		return nil, fmt.Errorf("failed to delete config override: %v", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, fmt.Errorf("%w: %s", ErrConfigOverrideNotFound, key)
+	}
+	delete(m.configOverrides, key)
+
+	result := &model.ConfigUpdateResult{Applied: []string{}, PendingRestart: []string{}}
+	setting, exists := configSettings[key]
+	switch {
+	case !exists:
+	case setting.hot:
+		m.revertConfigValueLocked(setting)
+		result.Applied = append(result.Applied, key)
+	default:
+		result.PendingRestart = append(result.PendingRestart, key)
+	}
+	slog.Info("Config override removed", "key", key)
+	return result, nil
+}
+
+// revertConfigValueLocked 把配置项恢复为启动时环境变量中的值，调用方需持有mutex
+func (m *Manager) revertConfigValueLocked(setting configSetting) {
+	base := reflect.ValueOf(setting.field(&m.baseConfig)).Elem().Interface()
+	m.setConfigValueLocked(setting, base)
+}
+
+// StartConfigReloader 定期从数据库重新加载配置项，使其他Master实例的修改在本实例生效
+func (m *Manager) StartConfigReloader() {
+	interval := time.Duration(m.GetConfig().Server.ConfigReloadSeconds) * time.Second
+	if interval <= 0 {
+		return
+	}
+
+	ticker := time.NewTicker(interval)
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				if err := m.reloadConfigOverrides(); err != nil {
+					slog.Warn("Config reload failed", "error", err)
+				}
+			}
+		}
+	}()
+}
+
+// reloadConfigOverrides 应用数据库中有变化的可热更新配置项，需要重启的配置项不在运行时修改
+func (m *Manager) reloadConfigOverrides() error {
+	var overrides []*model.ConfigOverride
+	if err := m.db.Find(&overrides).Error; err != nil {
+		return fmt.Errorf("failed to load config overrides: %v", err)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	current := make(map[string]bool, len(overrides))
+	for _, override := range overrides {
+		current[override.Key] = true
+		if m.configOverrides[override.Key] == override.Value {
+			continue
+		}
+		setting, value, err := decodeConfigOverride(override)
+		if err != nil {
+			slog.Warn("Ignoring invalid config override", "key", override.Key, "error", err)
+			continue
+		}
+		m.configOverrides[override.Key] = override.Value
+		if setting.hot {
+			m.setConfigValueLocked(setting, value)
+			slog.Info("Config override reloaded", "key", override.Key)
+		}
+	}
+	for key := range m.configOverrides {
+		if current[key] {
+			continue
+		}
+		delete(m.configOverrides, key)
+		if setting, exists := configSettings[key]; exists && setting.hot {
+			m.revertConfigValueLocked(setting)
+			slog.Info("Config override removed", "key", key)
+		}
+	}
+	return nil
+}
+
+func sortedKeys(values map[string]interface{}) []string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// 收件人校验模式
+const (
+	ContactValidationOff    = "off"
+	ContactValidationWarn   = "warn"
+	ContactValidationStrict = "strict"
+)
+
+// contactColumns 联系人列表允许过滤和排序的字段
+var contactColumns = map[string]string{
+	"account_id":    "account_id",
+	"number":        "number",
+	"name":          "name",
+	"is_group":      "is_group",
+	"is_my_contact": "is_my_contact",
+	"synced_at":     "synced_at",
+}
+
+// UnknownRecipientError strict模式下收件人不在账号已同步的联系人中
+type UnknownRecipientError struct {
+	AccountID string
+	Contact   string
+}
+
+func (e *UnknownRecipientError) Error() string {
+	return fmt.Sprintf("contact %s is not in the synced contacts of account %s", e.Contact, e.AccountID)
+}
+
+// StartContactSync 启动联系人定期同步
+func (m *Manager) StartContactSync() {
+	cfg := m.GetConfig().Contact
+	if !cfg.SyncEnabled {
+		return
+	}
+
+	interval := time.Duration(cfg.SyncInterval) * time.Minute
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	slog.Info("Contact sync enabled", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				if !m.IsLeader() {
+					continue
+				}
+				m.syncAllContacts()
+			}
+		}
+	}()
+}
+
+// syncAllContacts 同步所有已登录账号的联系人
+func (m *Manager) syncAllContacts() {
+	m.mutex.RLock()
+	accountIDs := make([]string, 0)
+	for _, account := range m.accounts {
+		if account.Status == "logged_in" {
+			accountIDs = append(accountIDs, account.ID)
+		}
+	}
+	m.mutex.RUnlock()
+
+	for _, accountID := range accountIDs {
+		if m.shuttingDown() {
+			return
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+		if _, err := m.SyncContacts(ctx, accountID); err != nil {
+			slog.Warn("Failed to sync contacts", "account_id", accountID, "error", err)
+		}
+		cancel()
+	}
+}
+
+// DefaultCountryCode 号码没有国家代码时使用的默认国家代码（PHONE_DEFAULT_COUNTRY_CODE）
+func (m *Manager) DefaultCountryCode() string {
+	return m.GetConfig().Contact.DefaultCountryCode
+}
+
+// SyncContacts 从Worker拉取账号联系人写入数据库，并删除Worker上已不存在的联系人
+func (m *Manager) SyncContacts(ctx context.Context, accountID string) (*model.ContactSyncResult, error) {
+	items, err := m.FetchWorkerContacts(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	contacts := make([]*model.Contact, 0)
+	for _, item := range items {
+		if item.ID == "" {
+			continue
+		}
+		contacts = append(contacts, &model.Contact{
+			AccountID:   accountID,
+			WID:         item.ID,
+			Number:      item.Number,
+			Name:        item.Name,
+			PushName:    item.PushName,
+			IsGroup:     item.IsGroup,
+			IsMyContact: item.IsMyContact,
+			SyncedAt:    now,
+		})
+	}
+
+	summary := &model.ContactSyncResult{AccountID: accountID, Synced: len(contacts)}
+	// 未登录的Worker返回空列表，此时保留已同步的联系人
+	if len(contacts) == 0 {
+		return summary, nil
+	}
+
+	err = m.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "account_id"}, {Name: "wid"}},
+		DoUpdates: clause.AssignmentColumns([]string{"number", "name", "push_name", "is_group", "is_my_contact", "synced_at", "updated_at"}),
+	}).CreateInBatches(contacts, 100).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to save contacts: %v", err)
+	}
+
+	res := m.db.Where("account_id = ? AND synced_at < ?", accountID, now).Delete(&model.Contact{})
+	if res.Error != nil {
+		return nil, fmt.Errorf("failed to remove stale contacts: %v", res.Error)
+	}
+	summary.Removed = res.RowsAffected
+
+	slog.Debug("Contacts synced", "account_id", accountID, "synced", summary.Synced, "removed", summary.Removed)
+	return summary, nil
+}
+
+// ListContacts 分页搜索已同步的联系人，q按名称、号码模糊匹配，accountIDs不为nil时只查询这些账号
+func (m *Manager) ListContacts(search string, accountIDs []string, q *model.ListQuery) ([]*model.Contact, int64, error) {
+	db := m.db.Model(&model.Contact{})
+	if accountIDs != nil {
+		db = db.Where("account_id IN ?", accountIDs)
+	}
+	if search = strings.TrimSpace(search); search != "" {
+		like := "%" + strings.ToLower(search) + "%"
+		db = db.Where("LOWER(name) LIKE ? OR LOWER(push_name) LIKE ? OR number LIKE ? OR wid LIKE ?", like, like, like, like)
+	}
+
+	contacts := make([]*model.Contact, 0)
+	total, err := findWithListQuery(db, q, contactColumns, "name", &contacts)
+	if err != nil {
+		return nil, 0, err
+	}
+	return contacts, total, nil
+}
+
+// CheckRecipient 按 CONTACT_VALIDATION 校验收件人是否在账号已同步的联系人中，
+// warn模式返回警告，strict模式返回 *UnknownRecipientError；账号还没有同步过联系人时不校验
+func (m *Manager) CheckRecipient(accountID, contact string) (string, error) {
+	m.mutex.RLock()
+	mode := m.GetConfig().Contact.Validation
+	m.mutex.RUnlock()
+	if mode != ContactValidationWarn && mode != ContactValidationStrict {
+		return "", nil
+	}
+
+	var synced int64
+	m.db.Model(&model.Contact{}).Where("account_id = ?", accountID).Count(&synced)
+	if synced == 0 {
+		return "", nil
+	}
+
+	number := strings.TrimPrefix(strings.TrimSuffix(contact, "@c.us"), "+")
+	var count int64
+	m.db.Model(&model.Contact{}).
+		Where("account_id = ? AND (number = ? OR wid = ?)", accountID, number, contact).
+		Count(&count)
+	if count > 0 {
+		return "", nil
+	}
+
+	err := &UnknownRecipientError{AccountID: accountID, Contact: contact}
+	if mode == ContactValidationStrict {
+		return "", err
+	}
+	return err.Error(), nil
+}
@@ -0,0 +1,444 @@
+package service
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/validation"
+)
+
+// maxXLSXPartSize XLSX中单个XML部件解压后的大小上限，防止压缩炸弹
+const maxXLSXPartSize = 64 << 20
+
+// 表头中可识别的列名，比较时忽略大小写、空格、下划线和横线
+var (
+	contactPhoneColumns     = []string{"phone", "phonenumber", "number", "mobile", "tel", "telephone", "whatsapp"}
+	contactFirstNameColumns = []string{"firstname", "first", "name", "givenname"}
+	contactLastNameColumns  = []string{"lastname", "last", "surname", "familyname"}
+)
+
+// contactFileRow 联系人文件中的一行，Line为文件中的行号
+type contactFileRow struct {
+	Line  int
+	Cells []string
+}
+
+// ContactImportMaxSize 联系人导入文件大小上限（字节）
+func (m *Manager) ContactImportMaxSize() int64 {
+	return int64(m.GetConfig().Contact.ImportMaxSizeMB) << 20
+}
+
+// ImportContacts 从CSV或XLSX文件导入联系人：号码规范化为 E.164，按 CONTACT_IMPORT_BATCH_SIZE 分批并发调用Worker添加，
+// 批次之间间隔 CONTACT_IMPORT_INTERVAL_MS，返回每行的结果。countryCode为空时使用默认国家代码
+func (m *Manager) ImportContacts(ctx context.Context, accountID, filename string, data []byte, countryCode string) (*model.ContactImportResult, error) {
+	countryCode = strings.TrimPrefix(strings.TrimSpace(countryCode), "+")
+	if countryCode == "" {
+		countryCode = m.DefaultCountryCode()
+	}
+	if countryCode != "" && !validation.IsDigits(countryCode) {
+		return nil, fmt.Errorf("invalid country code %q", countryCode)
+	}
+
+	account, err := m.GetAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Status != "logged_in" {
+		return nil, fmt.Errorf("account %s is not logged in (status %s)", accountID, account.Status)
+	}
+
+	fileRows, err := readContactFile(filename, data)
+	if err != nil {
+		return nil, err
+	}
+	rows := parseContactRows(fileRows, countryCode)
+	if len(rows) == 0 {
+		return nil, fmt.Errorf("no contacts found in file")
+	}
+	if limit := m.GetConfig().Contact.ImportMaxRows; limit > 0 && len(rows) > limit {
+		return nil, fmt.Errorf("file has %d contacts, at most %d can be imported at once", len(rows), limit)
+	}
+
+	pending := make([]*model.ContactImportRow, 0, len(rows))
+	seen := make(map[string]int)
+	for _, row := range rows {
+		if row.Status != "" {
+			continue
+		}
+		if first, exists := seen[row.Number]; exists {
+			row.Status = model.ContactImportDuplicate
+			row.Error = fmt.Sprintf("same number as row %d", first)
+			continue
+		}
+		seen[row.Number] = row.Row
+		pending = append(pending, row)
+	}
+
+	m.addImportedContacts(ctx, account, pending)
+
+	result := &model.ContactImportResult{AccountID: accountID, Total: len(rows), Rows: rows}
+	for _, row := range rows {
+		switch row.Status {
+		case model.ContactImportAdded:
+			result.Added++
+		case model.ContactImportInvalid:
+			result.Invalid++
+		case model.ContactImportDuplicate:
+			result.Duplicates++
+		case model.ContactImportFailed:
+			result.Failed++
+		case model.ContactImportSkipped:
+			result.Skipped++
+		}
+	}
+	slog.Info("Contacts imported", "account_id", accountID, "total", result.Total, "added", result.Added,
+		"invalid", result.Invalid, "duplicates", result.Duplicates, "failed", result.Failed, "skipped", result.Skipped)
+	return result, nil
+}
+
+// addImportedContacts 分批调用Worker添加联系人。Worker无法连接或请求取消时，剩余的行标记为skipped
+func (m *Manager) addImportedContacts(ctx context.Context, account *model.Account, rows []*model.ContactImportRow) {
+	batchSize := max(m.GetConfig().Contact.ImportBatchSize, 1)
+	interval := time.Duration(m.GetConfig().Contact.ImportIntervalMs) * time.Millisecond
+
+	skip := func(rows []*model.ContactImportRow, reason string) {
+		for _, row := range rows {
+			row.Status = model.ContactImportSkipped
+			row.Error = reason
+		}
+	}
+
+	for start := 0; start < len(rows); start += batchSize {
+		if start > 0 && interval > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(interval):
+			}
+		}
+		if ctx.Err() != nil {
+			skip(rows[start:], "import cancelled")
+			return
+		}
+
+		batch := rows[start:min(start+batchSize, len(rows))]
+		var wg sync.WaitGroup
+		var unreachable error
+		var mu sync.Mutex
+		for _, row := range batch {
+			wg.Add(1)
+			go func(row *model.ContactImportRow) {
+				defer wg.Done()
+				// Worker没有名字时只查询号码而不保存，使用号码作为名字
+				firstName := row.FirstName
+				if firstName == "" {
+					firstName = row.Number
+				}
+				_, err := m.postToWorker(ctx, account, "/api/contacts/add", model.AddContactRequest{
+					Phone:     strings.TrimPrefix(row.Number, "+"),
+					FirstName: firstName,
+					LastName:  row.LastName,
+				})
+				if err == nil {
+					row.Status = model.ContactImportAdded
+					return
+				}
+				row.Status = model.ContactImportFailed
+				row.Error = err.Error()
+				var workerErr *WorkerError
+				if errors.As(err, &workerErr) && workerErr.StatusCode == 0 {
+					mu.Lock()
+					unreachable = err
+					mu.Unlock()
+				}
+			}(row)
+		}
+		wg.Wait()
+
+		if unreachable != nil {
+			skip(rows[start+len(batch):], unreachable.Error())
+			return
+		}
+	}
+}
+
+// parseContactRows 识别表头并解析每行的号码和姓名，没有表头时依次为号码、名、姓
+func parseContactRows(fileRows []contactFileRow, countryCode string) []*model.ContactImportRow {
+	if len(fileRows) == 0 {
+		return nil
+	}
+	phoneCol, firstCol, lastCol := 0, 1, 2
+	if header := fileRows[0].Cells; contactColumn(header, contactPhoneColumns) >= 0 {
+		phoneCol = contactColumn(header, contactPhoneColumns)
+		firstCol = contactColumn(header, contactFirstNameColumns)
+		lastCol = contactColumn(header, contactLastNameColumns)
+		fileRows = fileRows[1:]
+	}
+
+	cell := func(cells []string, i int) string {
+		if i < 0 || i >= len(cells) {
+			return ""
+		}
+		return strings.TrimSpace(cells[i])
+	}
+
+	rows := make([]*model.ContactImportRow, 0, len(fileRows))
+	for _, fileRow := range fileRows {
+		row := &model.ContactImportRow{
+			Row:       fileRow.Line,
+			Phone:     cell(fileRow.Cells, phoneCol),
+			FirstName: cell(fileRow.Cells, firstCol),
+			LastName:  cell(fileRow.Cells, lastCol),
+		}
+		number, err := validation.NormalizePhone(row.Phone, countryCode)
+		if err != nil {
+			row.Status = model.ContactImportInvalid
+			row.Error = err.Error()
+		}
+		row.Number = number
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// contactColumn 在表头中查找列，未找到返回-1
+func contactColumn(header []string, names []string) int {
+	for i, cell := range header {
+		key := strings.Map(func(r rune) rune {
+			switch r {
+			case ' ', '_', '-':
+				return -1
+			}
+			return r
+		}, strings.ToLower(strings.TrimSpace(cell)))
+		for _, name := range names {
+			if key == name {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// readContactFile 按扩展名或内容读取CSV或XLSX文件，跳过空行
+func readContactFile(filename string, data []byte) ([]contactFileRow, error) {
+	ext := strings.ToLower(filepath.Ext(filename))
+	switch {
+	case ext == ".xlsx" || (ext != ".csv" && bytes.HasPrefix(data, []byte("PK\x03\x04"))):
+		return readXLSXRows(data)
+	case ext == ".csv" || ext == ".txt" || ext == "":
+		return readCSVRows(data)
+	}
+	return nil, fmt.Errorf("unsupported file type %s, use .csv or .xlsx", ext)
+}
+
+// readCSVRows 读取CSV，第一行只有分号没有逗号时使用分号分隔
+func readCSVRows(data []byte) ([]contactFileRow, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	firstLine, _, _ := bytes.Cut(data, []byte("\n"))
+	if bytes.Contains(firstLine, []byte(";")) && !bytes.Contains(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	rows := make([]contactFileRow, 0)
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %v", err)
+		}
+		line, _ := reader.FieldPos(0)
+		if !emptyCells(record) {
+			rows = append(rows, contactFileRow{Line: line, Cells: record})
+		}
+	}
+	return rows, nil
+}
+
+// XLSX文件中用到的XML结构
+type (
+	xlsxWorkbook struct {
+		Sheets []struct {
+			RID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	xlsxRelationships struct {
+		Items []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	xlsxText struct {
+		Text string `xml:"t"`
+		Runs []struct {
+			Text string `xml:"t"`
+		} `xml:"r"`
+	}
+	xlsxSharedStrings struct {
+		Items []xlsxText `xml:"si"`
+	}
+	xlsxWorksheet struct {
+		Rows []struct {
+			Num   int `xml:"r,attr"`
+			Cells []struct {
+				Ref    string   `xml:"r,attr"`
+				Type   string   `xml:"t,attr"`
+				Value  string   `xml:"v"`
+				Inline xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+)
+
+// String 富文本由多段组成时拼接各段
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.Text
+	}
+	var b strings.Builder
+	for _, run := range t.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+// readXLSXRows 读取XLSX第一个工作表的单元格文本
+func readXLSXRows(data []byte) ([]contactFileRow, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid XLSX: %v", err)
+	}
+	decode := func(name string, out interface{}) (bool, error) {
+		file, err := archive.Open(name)
+		if err != nil {
+			return false, nil
+		}
+		defer file.Close()
+		if err := xml.NewDecoder(io.LimitReader(file, maxXLSXPartSize)).Decode(out); err != nil {
+			return true, fmt.Errorf("invalid XLSX %s: %v", name, err)
+		}
+		return true, nil
+	}
+
+	sheetPath := "xl/worksheets/sheet1.xml"
+	var workbook xlsxWorkbook
+	var rels xlsxRelationships
+	if _, err := decode("xl/workbook.xml", &workbook); err != nil {
+		return nil, err
+	}
+	if _, err := decode("xl/_rels/workbook.xml.rels", &rels); err != nil {
+		return nil, err
+	}
+	if len(workbook.Sheets) > 0 {
+		for _, rel := range rels.Items {
+			if rel.ID != workbook.Sheets[0].RID {
+				continue
+			}
+			if strings.HasPrefix(rel.Target, "/") {
+				sheetPath = strings.TrimPrefix(rel.Target, "/")
+			} else {
+				sheetPath = path.Join("xl", rel.Target)
+			}
+		}
+	}
+
+	var shared xlsxSharedStrings
+	if _, err := decode("xl/sharedStrings.xml", &shared); err != nil {
+		return nil, err
+	}
+	var sheet xlsxWorksheet
+	found, err := decode(sheetPath, &sheet)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, fmt.Errorf("invalid XLSX: worksheet %s not found", sheetPath)
+	}
+
+	rows := make([]contactFileRow, 0, len(sheet.Rows))
+	for i, sheetRow := range sheet.Rows {
+		cells := make([]string, 0, len(sheetRow.Cells))
+		for _, c := range sheetRow.Cells {
+			col := xlsxColumn(c.Ref)
+			if col < 0 {
+				col = len(cells)
+			}
+			for len(cells) <= col {
+				cells = append(cells, "")
+			}
+			switch c.Type {
+			case "s":
+				if index, err := strconv.Atoi(c.Value); err == nil && index >= 0 && index < len(shared.Items) {
+					cells[col] = shared.Items[index].String()
+				}
+			case "inlineStr":
+				cells[col] = c.Inline.String()
+			case "str", "b", "e":
+				cells[col] = c.Value
+			default:
+				// 号码列是数字格式时以浮点数保存，如 8.6138000000010001E+12，取整后还原号码
+				cells[col] = c.Value
+				if f, err := strconv.ParseFloat(c.Value, 64); err == nil {
+					if rounded := math.Round(f); math.Abs(f-rounded) < 0.01 {
+						f = rounded
+					}
+					cells[col] = strconv.FormatFloat(f, 'f', -1, 64)
+				}
+			}
+		}
+		line := sheetRow.Num
+		if line == 0 {
+			line = i + 1
+		}
+		if !emptyCells(cells) {
+			rows = append(rows, contactFileRow{Line: line, Cells: cells})
+		}
+	}
+	return rows, nil
+}
+
+// xlsxColumn 单元格引用（如 B12）的列序号，从0开始，无法解析时返回-1
+func xlsxColumn(ref string) int {
+	col := 0
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		n++
+	}
+	if n == 0 {
+		return -1
+	}
+	return col - 1
+}
+
+// emptyCells 一行是否所有单元格都为空
+func emptyCells(cells []string) bool {
+	for _, cell := range cells {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,421 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// ErrContentPolicyNotFound 内容策略不存在
+var ErrContentPolicyNotFound = errors.New("content policy not found")
+
+// ErrModerationItemNotFound 审核队列中没有该消息
+var ErrModerationItemNotFound = errors.New("moderation item not found")
+
+// ErrModerationNotPending 消息已审核过
+var ErrModerationNotPending = errors.New("moderation item is not pending")
+
+// piiPatterns 内置的个人信息识别规则，pii策略中的其他值按正则表达式处理
+var piiPatterns = map[string]*regexp.Regexp{
+	"email":       regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`),
+	"credit_card": regexp.MustCompile(`\b\d(?:[ -]?\d){12,18}\b`),
+	"phone":       regexp.MustCompile(`\+?\d[\d ()-]{7,}\d`),
+	"iban":        regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]){11,30}\b`),
+}
+
+// moderationColumns 审核队列列表允许过滤和排序的字段
+var moderationColumns = map[string]string{
+	"id":          "id",
+	"account_id":  "account_id",
+	"contact":     "contact",
+	"campaign":    "campaign",
+	"status":      "status",
+	"reviewed_at": "reviewed_at",
+	"created_at":  "created_at",
+}
+
+// ContentPolicyError 消息违反内容策略：Held不为nil时消息已放入审核队列，否则被拒绝
+type ContentPolicyError struct {
+	Violations []model.ContentViolation
+	Held       *model.ModerationItem
+}
+
+func (e *ContentPolicyError) Error() string {
+	details := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		details[i] = fmt.Sprintf("%s (%s)", v.Detail, v.Type)
+	}
+	if e.Held != nil {
+		return fmt.Sprintf("message held for review as %s: %s", e.Held.ID, strings.Join(details, ", "))
+	}
+	return "message rejected by content policy: " + strings.Join(details, ", ")
+}
+
+// contentApprovedKey 上下文中标记审核已通过的发送，不再检查内容策略
+type contentApprovedKey struct{}
+
+func withContentApproved(ctx context.Context) context.Context {
+	return context.WithValue(ctx, contentApprovedKey{}, true)
+}
+
+func contentApproved(ctx context.Context) bool {
+	approved, _ := ctx.Value(contentApprovedKey{}).(bool)
+	return approved
+}
+
+// CreateContentPolicy 创建内容策略
+func (m *Manager) CreateContentPolicy(req *model.ContentPolicyRequest) (*model.ContentPolicy, error) {
+	policy := &model.ContentPolicy{ID: generateID("cp")}
+	applyContentPolicy(policy, req)
+	if err := validateContentPolicy(policy); err != nil {
+		return nil, err
+	}
+	if err := m.db.Create(policy).Error; err != nil {
+		return nil, fmt.Errorf("failed to create content policy: %v", err)
+	}
+	slog.Info("Content policy created", "policy_id", policy.ID, "type", policy.Type, "action", policy.Action)
+	return policy, nil
+}
+
+// UpdateContentPolicy 替换内容策略
+func (m *Manager) UpdateContentPolicy(policyID string, req *model.ContentPolicyRequest) (*model.ContentPolicy, error) {
+	var policy model.ContentPolicy
+	if err := m.db.Where("id = ?", policyID).First(&policy).Error; err != nil {
+		return nil, ErrContentPolicyNotFound
+	}
+	applyContentPolicy(&policy, req)
+	if err := validateContentPolicy(&policy); err != nil {
+		return nil, err
+	}
+	if err := m.db.Save(&policy).Error; err != nil {
+		return nil, fmt.Errorf("failed to update content policy: %v", err)
+	}
+	return &policy, nil
+}
+
+// ListContentPolicies 按创建时间列出内容策略
+func (m *Manager) ListContentPolicies() ([]*model.ContentPolicy, error) {
+	policies := make([]*model.ContentPolicy, 0)
+	if err := m.db.Order("created_at ASC").Find(&policies).Error; err != nil {
+		return nil, fmt.Errorf("failed to list content policies: %v", err)
+	}
+	return policies, nil
+}
+
+// DeleteContentPolicy 删除内容策略，审核队列中已暂扣的消息保留
+func (m *Manager) DeleteContentPolicy(policyID string) error {
+	res := m.db.Where("id = ?", policyID).Delete(&model.ContentPolicy{})
+	if res.Error != nil {
+		return fmt.Errorf("failed to delete content policy: %v", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrContentPolicyNotFound
+	}
+	return nil
+}
+
+// applyContentPolicy 将请求写入策略
+func applyContentPolicy(policy *model.ContentPolicy, req *model.ContentPolicyRequest) {
+	trimmed := func(values []string) model.StringList {
+		list := model.StringList{}
+		for _, value := range values {
+			if value = strings.TrimSpace(value); value != "" {
+				list = append(list, value)
+			}
+		}
+		return list
+	}
+	policy.Name = strings.TrimSpace(req.Name)
+	policy.Type = req.Type
+	policy.Keywords = trimmed(req.Keywords)
+	policy.Domains = model.StringList{}
+	for _, domain := range trimmed(req.Domains) {
+		policy.Domains = append(policy.Domains, strings.TrimPrefix(strings.ToLower(domain), "*."))
+	}
+	policy.MaxLinks = req.MaxLinks
+	policy.Patterns = trimmed(req.Patterns)
+	policy.Action = req.Action
+	policy.Enabled = req.Enabled == nil || *req.Enabled
+}
+
+// validateContentPolicy 校验策略类型所需的字段
+func validateContentPolicy(policy *model.ContentPolicy) error {
+	switch policy.Type {
+	case model.ContentPolicyKeyword:
+		if len(policy.Keywords) == 0 {
+			return fmt.Errorf("keyword policy requires at least one keyword")
+		}
+	case model.ContentPolicyURLAllowlist:
+		if len(policy.Domains) == 0 {
+			return fmt.Errorf("url_allowlist policy requires at least one domain")
+		}
+	case model.ContentPolicyMaxLinks:
+		// max_links为0表示不允许任何链接
+	case model.ContentPolicyPII:
+		if len(policy.Patterns) == 0 {
+			return fmt.Errorf("pii policy requires at least one pattern")
+		}
+		for _, pattern := range policy.Patterns {
+			if _, builtin := piiPatterns[pattern]; builtin {
+				continue
+			}
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid pattern %q: %v", pattern, err)
+			}
+		}
+	default:
+		return fmt.Errorf("unknown policy type %q", policy.Type)
+	}
+	return nil
+}
+
+// CheckContent 按启用的内容策略检查消息正文，返回所有违反的策略
+func (m *Manager) CheckContent(body string) ([]model.ContentViolation, error) {
+	var policies []*model.ContentPolicy
+	if err := m.db.Where("enabled = ?", true).Order("created_at ASC").Find(&policies).Error; err != nil {
+		return nil, fmt.Errorf("failed to load content policies: %v", err)
+	}
+
+	violations := make([]model.ContentViolation, 0)
+	for _, policy := range policies {
+		for _, detail := range contentViolations(policy, body) {
+			violations = append(violations, model.ContentViolation{
+				PolicyID:   policy.ID,
+				PolicyName: policy.Name,
+				Type:       policy.Type,
+				Action:     policy.Action,
+				Detail:     detail,
+			})
+		}
+	}
+	return violations, nil
+}
+
+// contentViolations 正文违反策略的具体内容，未违反时为空
+func contentViolations(policy *model.ContentPolicy, body string) []string {
+	var details []string
+	switch policy.Type {
+	case model.ContentPolicyKeyword:
+		lower := strings.ToLower(body)
+		for _, keyword := range policy.Keywords {
+			if strings.Contains(lower, strings.ToLower(keyword)) {
+				details = append(details, "blocked keyword "+keyword)
+			}
+		}
+	case model.ContentPolicyURLAllowlist:
+		for _, link := range linkPattern.FindAllString(body, -1) {
+			link = strings.TrimRight(link, ".,;:!?)")
+			if !domainAllowed(link, policy.Domains) {
+				details = append(details, "link not in allowlist "+link)
+			}
+		}
+	case model.ContentPolicyMaxLinks:
+		if count := len(linkPattern.FindAllString(body, -1)); count > policy.MaxLinks {
+			details = append(details, fmt.Sprintf("%d links, at most %d allowed", count, policy.MaxLinks))
+		}
+	case model.ContentPolicyPII:
+		for _, pattern := range policy.Patterns {
+			re, builtin := piiPatterns[pattern]
+			if !builtin {
+				var err error
+				if re, err = regexp.Compile(pattern); err != nil {
+					continue
+				}
+			}
+			for _, match := range re.FindAllString(body, -1) {
+				// 信用卡号还需通过Luhn校验，避免把订单号等长数字当作卡号
+				if pattern == "credit_card" && !luhnValid(match) {
+					continue
+				}
+				details = append(details, "personal data matching "+pattern)
+				break
+			}
+		}
+	}
+	return details
+}
+
+// domainAllowed 链接的主机是否为允许的域名或其子域名
+func domainAllowed(link string, domains []string) bool {
+	parsed, err := url.Parse(link)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+	for _, domain := range domains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// luhnValid 数字串是否通过Luhn校验，忽略空格和连字符
+func luhnValid(number string) bool {
+	sum, digits := 0, 0
+	for i := len(number) - 1; i >= 0; i-- {
+		c := number[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		d := int(c - '0')
+		if digits%2 == 1 {
+			d *= 2
+			if d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		digits++
+	}
+	return digits >= 13 && sum%10 == 0
+}
+
+// enforceContentPolicies 发送文本消息前检查内容策略：有reject策略被违反时拒绝，只违反review策略时放入审核队列，
+// 两种情况都返回 *ContentPolicyError。审核通过后的发送不再检查
+func (m *Manager) enforceContentPolicies(ctx context.Context, req *model.MessageRequest, body string) error {
+	if contentApproved(ctx) {
+		return nil
+	}
+	violations, err := m.CheckContent(body)
+	if err != nil {
+		return err
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	for _, v := range violations {
+		if v.Action == model.ContentActionReject {
+			slog.Info("Message rejected by content policy", "account_id", req.AccountID, "contact", req.Contact, "violations", len(violations))
+			return &ContentPolicyError{Violations: violations}
+		}
+	}
+
+	raw, _ := json.Marshal(violations)
+	item := &model.ModerationItem{
+		ID:         generateID("mod"),
+		AccountID:  req.AccountID,
+		Contact:    req.Contact,
+		Message:    body,
+		Campaign:   req.Campaign,
+		TrackLinks: req.TrackLinks,
+		Violations: model.RawJSON(raw),
+		Status:     model.ModerationPending,
+	}
+	if err := m.db.Create(item).Error; err != nil {
+		return fmt.Errorf("failed to queue message for review: %v", err)
+	}
+	slog.Info("Message held for review", "account_id", req.AccountID, "moderation_id", item.ID, "violations", len(violations))
+	m.emit(EventMessageHeld, req.AccountID, map[string]interface{}{"moderation_id": item.ID, "contact": item.Contact, "violations": violations})
+	return &ContentPolicyError{Violations: violations, Held: item}
+}
+
+// enforceCaptionPolicies 检查媒体消息的说明文字，审核队列只保存文本消息，所以任何违反都直接拒绝
+func (m *Manager) enforceCaptionPolicies(req *model.MediaMessageRequest) error {
+	if req.Caption == "" {
+		return nil
+	}
+	violations, err := m.CheckContent(req.Caption)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		slog.Info("Media rejected by content policy", "account_id", req.AccountID, "contact", req.Contact, "violations", len(violations))
+		return &ContentPolicyError{Violations: violations}
+	}
+	return nil
+}
+
+// ListModeration 分页查询审核队列，默认按暂扣时间倒序
+func (m *Manager) ListModeration(q *model.ListQuery) ([]*model.ModerationItem, int64, error) {
+	items := make([]*model.ModerationItem, 0)
+	total, err := findWithListQuery(m.db.Model(&model.ModerationItem{}), q, moderationColumns, "-created_at", &items)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, total, nil
+}
+
+// GetModerationItem 查询审核队列中的消息
+func (m *Manager) GetModerationItem(id string) (*model.ModerationItem, error) {
+	var item model.ModerationItem
+	if err := m.db.Where("id = ?", id).First(&item).Error; err != nil {
+		return nil, ErrModerationItemNotFound
+	}
+	return &item, nil
+}
+
+// ApproveModeration 审核通过并发送消息，发送仍受账号停用和发送额度限制；发送失败时记为failed，不会再次发送
+func (m *Manager) ApproveModeration(ctx context.Context, id, note string) (*model.ModerationItem, map[string]interface{}, error) {
+	item, err := m.reviewModeration(id, note)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	result, sendErr := m.SendMessage(withContentApproved(ctx), &model.MessageRequest{
+		AccountID:  item.AccountID,
+		Contact:    item.Contact,
+		Message:    item.Message,
+		Campaign:   item.Campaign,
+		TrackLinks: item.TrackLinks,
+	})
+	item.Status = model.ModerationSent
+	if sendErr != nil {
+		item.Status = model.ModerationFailed
+		item.Error = sendErr.Error()
+	} else if data, ok := result["data"].(map[string]interface{}); ok {
+		item.MessageID, _ = data["message_id"].(string)
+	}
+	if err := m.db.Model(item).Updates(map[string]interface{}{
+		"status":     item.Status,
+		"error":      item.Error,
+		"message_id": item.MessageID,
+	}).Error; err != nil {
+		slog.Warn("Failed to save moderation result", "moderation_id", item.ID, "error", err)
+	}
+	slog.Info("Held message approved", "moderation_id", item.ID, "account_id", item.AccountID, "status", item.Status)
+	return item, result, sendErr
+}
+
+// RejectModeration 审核拒绝，消息不会发送
+func (m *Manager) RejectModeration(id, note string) (*model.ModerationItem, error) {
+	item, err := m.reviewModeration(id, note)
+	if err != nil {
+		return nil, err
+	}
+	item.Status = model.ModerationRejected
+	if err := m.db.Model(item).Update("status", item.Status).Error; err != nil {
+		return nil, fmt.Errorf("failed to save moderation result: %v", err)
+	}
+	slog.Info("Held message rejected", "moderation_id", item.ID, "account_id", item.AccountID)
+	return item, nil
+}
+
+// reviewModeration 把待审核的消息标记为已审核，同一条消息并发审核时只有一个成功
+func (m *Manager) reviewModeration(id, note string) (*model.ModerationItem, error) {
+	item, err := m.GetModerationItem(id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	res := m.db.Model(&model.ModerationItem{}).
+		Where("id = ? AND status = ? AND reviewed_at IS NULL", id, model.ModerationPending).
+		Updates(map[string]interface{}{"reviewed_at": now, "note": note})
+	if res.Error != nil {
+		return nil, fmt.Errorf("failed to review message: %v", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return nil, ErrModerationNotPending
+	}
+	item.ReviewedAt = &now
+	item.Note = note
+	return item, nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// 会话处理方
+const (
+	HandledByBot   = "bot"
+	HandledByAgent = "agent"
+)
+
+// conversationColumns 会话列表允许过滤和排序的字段
+var conversationColumns = map[string]string{
+	"contact":     "contact",
+	"handled_by":  "handled_by",
+	"agent_id":    "agent_id",
+	"assigned_at": "assigned_at",
+	"updated_at":  "updated_at",
+}
+
+// GetConversation 获取会话归属，不存在时返回默认的机器人处理状态
+func (m *Manager) GetConversation(accountID, contact string) (*model.Conversation, error) {
+	if _, err := m.GetAccount(accountID); err != nil {
+		return nil, err
+	}
+
+	var conv model.Conversation
+	err := m.db.Where("account_id = ? AND contact = ?", accountID, contact).First(&conv).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &model.Conversation{
+			AccountID: accountID,
+			Contact:   contact,
+			HandledBy: HandledByBot,
+		}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &conv, nil
+}
+
+// ListConversations 查询账号下的会话归属
+func (m *Manager) ListConversations(accountID string, q *model.ListQuery) ([]*model.Conversation, int64, error) {
+	if _, err := m.GetAccount(accountID); err != nil {
+		return nil, 0, err
+	}
+
+	conversations := make([]*model.Conversation, 0)
+	db := m.db.Model(&model.Conversation{}).Where("account_id = ?", accountID)
+	total, err := findWithListQuery(db, q, conversationColumns, "-updated_at", &conversations)
+	if err != nil {
+		return nil, 0, err
+	}
+	return conversations, total, nil
+}
+
+// ClaimConversation 坐席接管会话
+func (m *Manager) ClaimConversation(accountID, contact string, req *model.ClaimConversationRequest) (*model.Conversation, error) {
+	conv, err := m.GetConversation(accountID, contact)
+	if err != nil {
+		return nil, err
+	}
+
+	if conv.HandledBy == HandledByAgent && conv.AgentID != req.AgentID && !req.Force {
+		return nil, fmt.Errorf("conversation is already assigned to agent %s", conv.AgentID)
+	}
+
+	previousAgent := conv.AgentID
+	now := time.Now()
+	conv.HandledBy = HandledByAgent
+	conv.AgentID = req.AgentID
+	conv.Note = req.Note
+	conv.AssignedAt = &now
+
+	if err := m.db.Save(conv).Error; err != nil {
+		return nil, fmt.Errorf("failed to save conversation: %v", err)
+	}
+
+	slog.Info("Conversation claimed", "account_id", accountID, "contact", contact, "agent_id", req.AgentID)
+	m.emit(EventConversationClaimed, accountID, map[string]interface{}{
+		"contact":        contact,
+		"agent_id":       req.AgentID,
+		"previous_agent": previousAgent,
+	})
+	return conv, nil
+}
+
+// ReleaseConversation 坐席释放会话，交还机器人处理
+func (m *Manager) ReleaseConversation(accountID, contact string, req *model.ReleaseConversationRequest) (*model.Conversation, error) {
+	conv, err := m.GetConversation(accountID, contact)
+	if err != nil {
+		return nil, err
+	}
+
+	if conv.HandledBy != HandledByAgent {
+		return conv, nil
+	}
+	if req.AgentID != "" && conv.AgentID != req.AgentID {
+		return nil, fmt.Errorf("conversation is assigned to agent %s", conv.AgentID)
+	}
+
+	previousAgent := conv.AgentID
+	conv.HandledBy = HandledByBot
+	conv.AgentID = ""
+	conv.Note = req.Note
+	conv.AssignedAt = nil
+
+	if err := m.db.Save(conv).Error; err != nil {
+		return nil, fmt.Errorf("failed to save conversation: %v", err)
+	}
+
+	slog.Info("Conversation released", "account_id", accountID, "contact", contact, "agent_id", previousAgent)
+	m.emit(EventConversationReleased, accountID, map[string]interface{}{
+		"contact":        contact,
+		"previous_agent": previousAgent,
+	})
+	return conv, nil
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// statsDayLayout 每日统计的日期格式
+const statsDayLayout = "2006-01-02"
+
+// statsMaxDays 统计时间序列单次查询的最大天数
+const statsMaxDays = 1830
+
+// 统计时间序列粒度
+const (
+	StatsGranularityDay   = "day"
+	StatsGranularityWeek  = "week"
+	StatsGranularityMonth = "month"
+)
+
+// statsDay 时间所在的统计日期（本地时区）
+func statsDay(t time.Time) string {
+	return t.In(time.Local).Format(statsDayLayout)
+}
+
+// addDailyStats 将增量累加到账号当天的统计
+func (m *Manager) addDailyStats(accountID string, at time.Time, delta model.AccountDailyStats) {
+	row := delta
+	row.AccountID = accountID
+	row.Day = statsDay(at)
+	row.UpdatedAt = time.Now()
+
+	err := m.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "account_id"}, {Name: "day"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"sent":           gorm.Expr("sent + ?", delta.Sent),
+			"received":       gorm.Expr("received + ?", delta.Received),
+			"failed":         gorm.Expr("failed + ?", delta.Failed),
+			"uptime_seconds": gorm.Expr("uptime_seconds + ?", delta.UptimeSeconds),
+			"updated_at":     row.UpdatedAt,
+		}),
+	}).Create(&row).Error
+	if err != nil {
+		slog.Warn("Failed to record daily stats", "account_id", accountID, "day", row.Day, "error", err)
+	}
+}
+
+// recordDailyMessage 按消息方向和发送结果计入账号当天的统计
+func (m *Manager) recordDailyMessage(msg *model.Message) {
+	var delta model.AccountDailyStats
+	switch {
+	case msg.Direction == "inbound":
+		delta.Received = 1
+	case msg.Status == "failed":
+		delta.Failed = 1
+	default:
+		delta.Sent = 1
+	}
+	m.addDailyStats(msg.AccountID, time.Now(), delta)
+}
+
+// todayMessagesSent 今天（本地日期）所有账号的发送条数
+func (m *Manager) todayMessagesSent() int {
+	var sent int64
+	m.db.Model(&model.AccountDailyStats{}).
+		Where("day = ?", statsDay(time.Now())).
+		Select("COALESCE(SUM(sent), 0)").
+		Scan(&sent)
+	return int(sent)
+}
+
+// StartStatsRecorder 启动每日统计任务：按采样间隔累计已登录账号的在线时长，每天清理超过保留期的统计
+func (m *Manager) StartStatsRecorder() {
+	cfg := m.GetConfig().Stats
+	sample := time.Duration(cfg.UptimeSampleSeconds) * time.Second
+	interval := sample
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	slog.Info("Stats recorder enabled", "uptime_sample", sample, "retention_days", cfg.RetentionDays)
+
+	ticker := time.NewTicker(interval)
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer ticker.Stop()
+
+		last := time.Now()
+		pruned := ""
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case now := <-ticker.C:
+				if !m.IsLeader() {
+					last = now
+					continue
+				}
+				if sample > 0 {
+					// 进程暂停等导致的长间隔最多按两个采样周期计
+					elapsed := now.Sub(last)
+					if elapsed > 2*sample {
+						elapsed = 2 * sample
+					}
+					m.sampleUptime(now, elapsed)
+				}
+				last = now
+
+				if day := statsDay(now); day != pruned {
+					m.pruneDailyStats(now)
+					pruned = day
+				}
+			}
+		}
+	}()
+}
+
+// sampleUptime 为当前已登录的账号累加在线时长
+func (m *Manager) sampleUptime(now time.Time, elapsed time.Duration) {
+	seconds := int64(elapsed.Round(time.Second).Seconds())
+	if seconds <= 0 {
+		return
+	}
+
+	m.mutex.RLock()
+	accountIDs := make([]string, 0)
+	for _, account := range m.accounts {
+		if account.Status == "logged_in" {
+			accountIDs = append(accountIDs, account.ID)
+		}
+	}
+	m.mutex.RUnlock()
+
+	for _, accountID := range accountIDs {
+		m.addDailyStats(accountID, now, model.AccountDailyStats{UptimeSeconds: seconds})
+	}
+}
+
+// pruneDailyStats 删除超过 STATS_RETENTION_DAYS 的每日统计
+func (m *Manager) pruneDailyStats(now time.Time) {
+	days := m.GetConfig().Stats.RetentionDays
+	if days <= 0 {
+		return
+	}
+	cutoff := statsDay(now.AddDate(0, 0, -days))
+	result := m.db.Where("day < ?", cutoff).Delete(&model.AccountDailyStats{})
+	if result.Error != nil {
+		slog.Warn("Failed to prune daily stats", "error", result.Error)
+		return
+	}
+	if result.RowsAffected > 0 {
+		slog.Info("Pruned daily stats", "before", cutoff, "rows", result.RowsAffected)
+	}
+}
+
+// StatsSeries 按天、周（周一开始）或月汇总每日统计，没有数据的周期也会返回
+func (m *Manager) StatsSeries(q *model.StatsQuery) ([]*model.StatsPoint, error) {
+	granularity := valueOrDefault(q.Granularity, StatsGranularityDay)
+
+	to := time.Now().In(time.Local)
+	if q.To != "" {
+		parsed, err := time.ParseInLocation(statsDayLayout, q.To, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid to date %q, expected YYYY-MM-DD", q.To)
+		}
+		to = parsed
+	}
+	to = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.Local)
+
+	from := to.AddDate(0, 0, -29)
+	if q.From != "" {
+		parsed, err := time.ParseInLocation(statsDayLayout, q.From, time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid from date %q, expected YYYY-MM-DD", q.From)
+		}
+		from = parsed
+	}
+	if from.After(to) {
+		return nil, fmt.Errorf("from must not be after to")
+	}
+	if to.Sub(from) > statsMaxDays*24*time.Hour {
+		return nil, fmt.Errorf("date range exceeds %d days", statsMaxDays)
+	}
+
+	rows := make([]*model.AccountDailyStats, 0)
+	db := m.db.Where("day >= ? AND day <= ?", from.Format(statsDayLayout), to.Format(statsDayLayout))
+	if q.AccountID != "" {
+		db = db.Where("account_id = ?", q.AccountID)
+	}
+	if err := db.Order("day").Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to query daily stats: %v", err)
+	}
+
+	points := make([]*model.StatsPoint, 0)
+	index := make(map[string]*model.StatsPoint)
+	for start := statsPeriodStart(from, granularity); !start.After(to); start = statsNextPeriod(start, granularity) {
+		point := &model.StatsPoint{
+			Period: statsPeriodKey(start, granularity),
+			From:   maxTime(start, from).Format(statsDayLayout),
+			To:     minTime(statsNextPeriod(start, granularity).AddDate(0, 0, -1), to).Format(statsDayLayout),
+		}
+		points = append(points, point)
+		index[point.Period] = point
+	}
+
+	accounts := make(map[string]map[string]bool)
+	for _, row := range rows {
+		day, err := time.ParseInLocation(statsDayLayout, row.Day, time.Local)
+		if err != nil {
+			continue
+		}
+		key := statsPeriodKey(statsPeriodStart(day, granularity), granularity)
+		point, exists := index[key]
+		if !exists {
+			continue
+		}
+		point.Sent += row.Sent
+		point.Received += row.Received
+		point.Failed += row.Failed
+		point.UptimeSeconds += row.UptimeSeconds
+		if accounts[key] == nil {
+			accounts[key] = make(map[string]bool)
+		}
+		accounts[key][row.AccountID] = true
+	}
+	for key, ids := range accounts {
+		index[key].Accounts = len(ids)
+	}
+	return points, nil
+}
+
+// statsPeriodStart 日期所在统计周期的第一天
+func statsPeriodStart(day time.Time, granularity string) time.Time {
+	switch granularity {
+	case StatsGranularityWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case StatsGranularityMonth:
+		return time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, day.Location())
+	}
+	return day
+}
+
+// statsNextPeriod 下一个统计周期的第一天
+func statsNextPeriod(start time.Time, granularity string) time.Time {
+	switch granularity {
+	case StatsGranularityWeek:
+		return start.AddDate(0, 0, 7)
+	case StatsGranularityMonth:
+		return start.AddDate(0, 1, 0)
+	}
+	return start.AddDate(0, 0, 1)
+}
+
+// statsPeriodKey 统计周期的标识
+func statsPeriodKey(start time.Time, granularity string) string {
+	if granularity == StatsGranularityMonth {
+		return start.Format("2006-01")
+	}
+	return start.Format(statsDayLayout)
+}
+
+// minTime 较早的时间
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+	return b
+}
+
+// maxTime 较晚的时间
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,274 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+const (
+	// deadLetterPollInterval 自动重试检查到期死信的间隔
+	deadLetterPollInterval = 30 * time.Second
+	// deadLetterRetryBatch 每次检查最多重试的死信数
+	deadLetterRetryBatch = 50
+)
+
+var (
+	// ErrDeadLetterNotFound 死信不存在
+	ErrDeadLetterNotFound = errors.New("dead letter not found")
+	// ErrDeadLetterResolved 死信已重试成功
+	ErrDeadLetterResolved = errors.New("dead letter was already delivered")
+	// ErrDeadLetterBusy 死信或对应的消息正在重试
+	ErrDeadLetterBusy = errors.New("message is already being retried")
+)
+
+// deadLetterColumns 死信列表允许过滤和排序的字段
+var deadLetterColumns = map[string]string{
+	"id":            "id",
+	"account_id":    "account_id",
+	"status":        "status",
+	"contact":       "contact",
+	"type":          "type",
+	"campaign":      "campaign",
+	"next_retry_at": "next_retry_at",
+	"created_at":    "created_at",
+}
+
+// deadLetter Worker不可达或代理错误导致发送失败时，将消息写入死信队列
+func (m *Manager) deadLetter(record *model.Message, voice bool, err error) {
+	if !m.GetConfig().DeadLetter.Enabled || !isRetryable(err) {
+		return
+	}
+
+	letter := &model.DeadLetter{
+		ID:          generateID("dl"),
+		MessageID:   record.ID,
+		AccountID:   record.AccountID,
+		Contact:     record.Contact,
+		Type:        record.Type,
+		Body:        record.Body,
+		Voice:       voice,
+		Campaign:    record.Campaign,
+		Error:       err.Error(),
+		Status:      model.DeadLetterPending,
+		NextRetryAt: m.nextDeadLetterRetry(0),
+	}
+	if err := m.db.Create(letter).Error; err != nil {
+		slog.Error("Failed to save dead letter", "message_id", record.ID, "account_id", record.AccountID, "error", err)
+		return
+	}
+	slog.Warn("Message moved to dead-letter queue", "dead_letter_id", letter.ID, "message_id", record.ID, "account_id", record.AccountID, "error", letter.Error)
+	m.emit(EventMessageDeadLettered, record.AccountID, letter)
+}
+
+// nextDeadLetterRetry 第retries次重试失败后的下次自动重试时间，未开启自动重试或次数用完时返回nil
+func (m *Manager) nextDeadLetterRetry(retries int) *time.Time {
+	cfg := m.GetConfig().DeadLetter
+	if !cfg.AutoRetry || retries >= cfg.MaxRetries {
+		return nil
+	}
+	backoff := time.Duration(cfg.BackoffSeconds) * time.Second
+	maxBackoff := time.Duration(cfg.MaxBackoffSeconds) * time.Second
+	for i := 0; i < retries && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if maxBackoff > 0 && backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	next := time.Now().Add(backoff)
+	return &next
+}
+
+// ListDeadLetters 分页查询死信
+func (m *Manager) ListDeadLetters(filter *model.DeadLetterFilter, q *model.ListQuery) ([]*model.DeadLetter, int64, error) {
+	db := m.db.Model(&model.DeadLetter{})
+	if filter.AccountIDs != nil {
+		db = db.Where("account_id IN ?", filter.AccountIDs)
+	}
+
+	letters := make([]*model.DeadLetter, 0)
+	total, err := findWithListQuery(db, q, deadLetterColumns, "-created_at", &letters)
+	if err != nil {
+		return nil, 0, err
+	}
+	return letters, total, nil
+}
+
+// GetDeadLetter 获取死信
+func (m *Manager) GetDeadLetter(id string) (*model.DeadLetter, error) {
+	var letter model.DeadLetter
+	if err := m.db.Where("id = ?", id).First(&letter).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDeadLetterNotFound
+		}
+		return nil, fmt.Errorf("failed to query dead letter: %v", err)
+	}
+	return &letter, nil
+}
+
+// RetryDeadLetter 立即重发死信中的消息，返回重试后的死信
+func (m *Manager) RetryDeadLetter(id string) (*model.DeadLetter, error) {
+	letter, err := m.GetDeadLetter(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.retryDeadLetter(letter); err != nil {
+		return nil, err
+	}
+	return letter, nil
+}
+
+// retryDeadLetter 重发死信对应的消息并更新死信状态。消息已由 /messages/retry 重发成功时直接标记为resolved
+func (m *Manager) retryDeadLetter(letter *model.DeadLetter) error {
+	if letter.Status == model.DeadLetterResolved {
+		return ErrDeadLetterResolved
+	}
+	previous := letter.Status
+	res := m.db.Model(&model.DeadLetter{}).
+		Where("id = ? AND status IN ?", letter.ID, []string{model.DeadLetterPending, model.DeadLetterExhausted}).
+		Update("status", model.DeadLetterRetrying)
+	if res.Error != nil {
+		return fmt.Errorf("failed to update dead letter: %v", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return ErrDeadLetterBusy
+	}
+
+	var msg model.Message
+	if err := m.db.Where("id = ?", letter.MessageID).First(&msg).Error; err != nil {
+		m.db.Model(letter).Update("status", previous)
+		return fmt.Errorf("failed to load message %s: %v", letter.MessageID, err)
+	}
+	// 与 /messages/retry 共用消息状态，避免同一条消息被同时重发
+	res = m.db.Model(&model.Message{}).Where("id = ? AND status = ?", msg.ID, "failed").Update("status", "retrying")
+	if res.Error != nil || res.RowsAffected == 0 {
+		if res.Error == nil && msg.Status != "failed" && msg.Status != "retrying" {
+			m.resolveDeadLetter(letter)
+			return nil
+		}
+		m.db.Model(letter).Update("status", previous)
+		return ErrDeadLetterBusy
+	}
+	msg.Status = "retrying"
+
+	m.resendMessage(&msg, letter.Voice)
+
+	now := time.Now()
+	letter.Retries++
+	letter.LastRetryAt = &now
+	if msg.Status != "failed" {
+		m.resolveDeadLetter(letter)
+		slog.Info("Dead letter delivered", "dead_letter_id", letter.ID, "message_id", msg.ID, "account_id", letter.AccountID, "retries", letter.Retries)
+		return nil
+	}
+
+	letter.Error = msg.Error
+	letter.NextRetryAt = m.nextDeadLetterRetry(letter.Retries)
+	letter.Status = model.DeadLetterPending
+	if m.GetConfig().DeadLetter.AutoRetry && letter.NextRetryAt == nil {
+		letter.Status = model.DeadLetterExhausted
+	}
+	if err := m.db.Save(letter).Error; err != nil {
+		slog.Error("Failed to update dead letter", "dead_letter_id", letter.ID, "error", err)
+	}
+	slog.Warn("Dead letter retry failed", "dead_letter_id", letter.ID, "message_id", msg.ID, "account_id", letter.AccountID,
+		"retries", letter.Retries, "status", letter.Status, "error", letter.Error)
+	return nil
+}
+
+// resolveDeadLetter 将死信标记为已送达
+func (m *Manager) resolveDeadLetter(letter *model.DeadLetter) {
+	now := time.Now()
+	letter.Status = model.DeadLetterResolved
+	letter.Error = ""
+	letter.NextRetryAt = nil
+	letter.ResolvedAt = &now
+	if err := m.db.Save(letter).Error; err != nil {
+		slog.Error("Failed to update dead letter", "dead_letter_id", letter.ID, "error", err)
+	}
+}
+
+// resolveDeadLetterForMessage 消息经其他途径重发成功后，将其死信标记为已送达
+func (m *Manager) resolveDeadLetterForMessage(messageID string) {
+	now := time.Now()
+	err := m.db.Model(&model.DeadLetter{}).
+		Where("message_id = ? AND status IN ?", messageID, []string{model.DeadLetterPending, model.DeadLetterExhausted}).
+		Updates(map[string]interface{}{"status": model.DeadLetterResolved, "error": "", "next_retry_at": nil, "resolved_at": now}).Error
+	if err != nil {
+		slog.Warn("Failed to resolve dead letter", "message_id", messageID, "error", err)
+	}
+}
+
+// StartDeadLetterRetrier 开启自动重试时，定期重发到期的死信
+func (m *Manager) StartDeadLetterRetrier() {
+	cfg := m.GetConfig().DeadLetter
+	if !cfg.Enabled || !cfg.AutoRetry {
+		return
+	}
+	slog.Info("Dead-letter auto retry enabled", "max_retries", cfg.MaxRetries, "backoff_seconds", cfg.BackoffSeconds, "max_backoff_seconds", cfg.MaxBackoffSeconds)
+
+	ticker := time.NewTicker(deadLetterPollInterval)
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				if !m.IsLeader() {
+					continue
+				}
+				m.retryDueDeadLetters()
+			}
+		}
+	}()
+}
+
+// retryDueDeadLetters 依次重发到期的死信
+func (m *Manager) retryDueDeadLetters() {
+	var due []*model.DeadLetter
+	err := m.db.Where("status = ? AND next_retry_at <= ?", model.DeadLetterPending, time.Now()).
+		Order("next_retry_at ASC").Limit(deadLetterRetryBatch).Find(&due).Error
+	if err != nil {
+		slog.Warn("Failed to list due dead letters", "error", err)
+		return
+	}
+
+	for _, letter := range due {
+		if m.shuttingDown() {
+			return
+		}
+		if err := m.retryDeadLetter(letter); err != nil && !errors.Is(err, ErrDeadLetterBusy) {
+			slog.Warn("Failed to retry dead letter", "dead_letter_id", letter.ID, "error", err)
+		}
+	}
+}
+
+// cleanResolvedDeadLetters 删除超过保留期的已送达死信
+func (m *Manager) cleanResolvedDeadLetters(report *model.JanitorReport) {
+	days := m.GetConfig().DeadLetter.RetentionDays
+	if days <= 0 {
+		return
+	}
+	db := m.db.Where("status = ? AND resolved_at < ?", model.DeadLetterResolved, time.Now().AddDate(0, 0, -days))
+	if report.DryRun {
+		if err := db.Model(&model.DeadLetter{}).Count(&report.DeadLetters).Error; err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to count resolved dead letters: %v", err))
+		}
+		return
+	}
+
+	result := db.Delete(&model.DeadLetter{})
+	if result.Error != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to delete resolved dead letters: %v", result.Error))
+		return
+	}
+	report.DeadLetters = result.RowsAffected
+}
@@ -0,0 +1,237 @@
+package service
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// diagnosticKinds 允许的诊断包类型
+var diagnosticKinds = map[string]bool{
+	"screenshot": true,
+	"crash_dump": true,
+	"html":       true,
+	"other":      true,
+}
+
+// diagnosticColumns 诊断包列表允许过滤和排序的字段
+var diagnosticColumns = map[string]string{
+	"id":          "id",
+	"incident_id": "incident_id",
+	"kind":        "kind",
+	"size":        "size",
+	"created_at":  "created_at",
+}
+
+// unsafeFilenameChars 文件名中需要替换的字符
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// workerMasterURL Worker回调Master使用的地址
+func (m *Manager) workerMasterURL() string {
+	if m.GetConfig().Worker.MasterURL != "" {
+		return m.GetConfig().Worker.MasterURL
+	}
+	return fmt.Sprintf("http://host.docker.internal:%d", m.GetConfig().Server.Port)
+}
+
+// VerifyWorkerToken 校验Worker回调凭证
+func (m *Manager) VerifyWorkerToken(accountID, token string) bool {
+	account, err := m.GetAccount(accountID)
+	if err != nil || account.WorkerToken == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), []byte(account.WorkerToken)) == 1
+}
+
+// MaxDiagnosticUpload 单次诊断包上传的字节数上限
+func (m *Manager) MaxDiagnosticUpload() int64 {
+	return int64(m.GetConfig().Diagnostics.MaxUploadMB) << 20
+}
+
+// SaveDiagnosticBundle 保存Worker上传的诊断包
+func (m *Manager) SaveDiagnosticBundle(accountID, incidentID, kind, note string, files []*multipart.FileHeader) (*model.DiagnosticBundle, error) {
+	if _, err := m.GetAccount(accountID); err != nil {
+		return nil, err
+	}
+	if kind == "" {
+		kind = "other"
+	}
+	if !diagnosticKinds[kind] {
+		return nil, fmt.Errorf("invalid kind %q: must be screenshot, crash_dump, html or other", kind)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("at least one file is required")
+	}
+
+	now := time.Now()
+	bundle := &model.DiagnosticBundle{
+		ID:         generateID("diag"),
+		AccountID:  accountID,
+		IncidentID: strings.TrimSpace(incidentID),
+		Kind:       kind,
+		Note:       note,
+		Files:      make([]*model.DiagnosticFile, 0, len(files)),
+		CreatedAt:  now,
+		ExpiresAt:  now.AddDate(0, 0, m.GetConfig().Diagnostics.RetentionDays),
+	}
+
+	dir := m.diagnosticBundleDir(accountID, bundle.ID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create diagnostics dir: %v", err)
+	}
+
+	used := make(map[string]bool)
+	for _, header := range files {
+		name := diagnosticFilename(header.Filename, used)
+		size, err := saveUploadedFile(header, filepath.Join(dir, name))
+		if err != nil {
+			os.RemoveAll(dir)
+			return nil, fmt.Errorf("failed to save %s: %v", header.Filename, err)
+		}
+		bundle.Files = append(bundle.Files, &model.DiagnosticFile{
+			BundleID: bundle.ID,
+			Name:     name,
+			MimeType: header.Header.Get("Content-Type"),
+			Size:     size,
+		})
+		bundle.Size += size
+	}
+
+	if err := m.db.Create(bundle).Error; err != nil {
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to save diagnostic bundle: %v", err)
+	}
+	if err := m.db.Create(bundle.Files).Error; err != nil {
+		m.db.Delete(bundle)
+		os.RemoveAll(dir)
+		return nil, fmt.Errorf("failed to save diagnostic files: %v", err)
+	}
+
+	slog.Info("Diagnostic bundle uploaded", "bundle_id", bundle.ID, "kind", bundle.Kind, "account_id", accountID, "files", len(bundle.Files), "bytes", bundle.Size)
+	m.emit(EventDiagnosticsUploaded, accountID, map[string]interface{}{
+		"bundle_id":   bundle.ID,
+		"incident_id": bundle.IncidentID,
+		"kind":        bundle.Kind,
+	})
+	return bundle, nil
+}
+
+// ListDiagnosticBundles 分页查询账号的诊断包
+func (m *Manager) ListDiagnosticBundles(accountID string, q *model.ListQuery) ([]*model.DiagnosticBundle, int64, error) {
+	bundles := make([]*model.DiagnosticBundle, 0)
+	db := m.db.Model(&model.DiagnosticBundle{}).Where("account_id = ?", accountID)
+	total, err := findWithListQuery(db, q, diagnosticColumns, "-created_at", &bundles)
+	if err != nil {
+		return nil, 0, err
+	}
+	return bundles, total, nil
+}
+
+// GetDiagnosticBundle 获取诊断包及其文件列表
+func (m *Manager) GetDiagnosticBundle(bundleID string) (*model.DiagnosticBundle, error) {
+	var bundle model.DiagnosticBundle
+	if err := m.db.Where("id = ?", bundleID).First(&bundle).Error; err != nil {
+		return nil, fmt.Errorf("diagnostic bundle %s not found", bundleID)
+	}
+	m.db.Where("bundle_id = ?", bundleID).Order("id").Find(&bundle.Files)
+	return &bundle, nil
+}
+
+// DiagnosticFilePath 获取诊断包中文件在磁盘上的路径
+func (m *Manager) DiagnosticFilePath(bundleID, name string) (string, *model.DiagnosticFile, error) {
+	bundle, err := m.GetDiagnosticBundle(bundleID)
+	if err != nil {
+		return "", nil, err
+	}
+	for _, file := range bundle.Files {
+		if file.Name == name {
+			return filepath.Join(m.diagnosticBundleDir(bundle.AccountID, bundle.ID), file.Name), file, nil
+		}
+	}
+	return "", nil, fmt.Errorf("file %s not found in bundle %s", name, bundleID)
+}
+
+// DeleteDiagnosticBundle 删除诊断包
+func (m *Manager) DeleteDiagnosticBundle(bundleID string) error {
+	bundle, err := m.GetDiagnosticBundle(bundleID)
+	if err != nil {
+		return err
+	}
+	return m.removeDiagnosticBundle(bundle)
+}
+
+// removeDiagnosticBundle 删除诊断包的文件和记录
+func (m *Manager) removeDiagnosticBundle(bundle *model.DiagnosticBundle) error {
+	if err := os.RemoveAll(m.diagnosticBundleDir(bundle.AccountID, bundle.ID)); err != nil {
+		return fmt.Errorf("failed to remove diagnostic bundle %s: %v", bundle.ID, err)
+	}
+	m.db.Where("bundle_id = ?", bundle.ID).Delete(&model.DiagnosticFile{})
+	m.db.Delete(bundle)
+	return nil
+}
+
+// cleanExpiredDiagnostics 删除超过保留期的诊断包
+func (m *Manager) cleanExpiredDiagnostics(report *model.JanitorReport) {
+	var bundles []*model.DiagnosticBundle
+	if err := m.db.Where("expires_at < ?", time.Now()).Find(&bundles).Error; err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to query diagnostic bundles: %v", err))
+		return
+	}
+
+	for _, bundle := range bundles {
+		if !report.DryRun {
+			if err := m.removeDiagnosticBundle(bundle); err != nil {
+				report.Errors = append(report.Errors, err.Error())
+				continue
+			}
+		}
+		report.BundlesRemoved = append(report.BundlesRemoved, bundle.ID)
+		report.ReclaimedBytes += bundle.Size
+	}
+}
+
+// diagnosticBundleDir 诊断包在磁盘上的目录
+func (m *Manager) diagnosticBundleDir(accountID, bundleID string) string {
+	return filepath.Join(m.GetConfig().Diagnostics.Dir, accountID, bundleID)
+}
+
+// diagnosticFilename 清理上传文件名，重名时追加序号
+func diagnosticFilename(original string, used map[string]bool) string {
+	name := unsafeFilenameChars.ReplaceAllString(filepath.Base(original), "_")
+	if name == "" || name == "." || name == ".." {
+		name = "file"
+	}
+
+	candidate := name
+	ext := filepath.Ext(name)
+	for i := 2; used[candidate]; i++ {
+		candidate = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(name, ext), i, ext)
+	}
+	used[candidate] = true
+	return candidate
+}
+
+// saveUploadedFile 将上传的文件写入磁盘，返回写入的字节数
+func saveUploadedFile(header *multipart.FileHeader, path string) (int64, error) {
+	src, err := header.Open()
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(path)
+	if err != nil {
+		return 0, err
+	}
+	defer dst.Close()
+	return io.Copy(dst, src)
+}
@@ -0,0 +1,108 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// AccountDisabledError 账号已停用，不接受发送
+type AccountDisabledError struct {
+	AccountID string
+	Reason    string
+}
+
+func (e *AccountDisabledError) Error() string {
+	if e.Reason != "" {
+		return fmt.Sprintf("account %s is disabled: %s", e.AccountID, e.Reason)
+	}
+	return fmt.Sprintf("account %s is disabled", e.AccountID)
+}
+
+// DisableAccount 停用账号：保留Worker和会话，但拒绝发送并从批量发送和活动中排除
+func (m *Manager) DisableAccount(accountID, reason string) (*model.Account, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return nil, fmt.Errorf("account %s not found", accountID)
+	}
+	if err := m.disableAccountLocked(account, strings.TrimSpace(reason)); err != nil {
+		return nil, err
+	}
+	return account.Clone(), nil
+}
+
+// disableAccountLocked 停用账号，调用方需持有mutex
+func (m *Manager) disableAccountLocked(account *model.Account, reason string) error {
+	now := time.Now()
+	wasDisabled := account.Disabled // Updates会同时写回account的字段
+	if err := m.db.Model(account).Updates(map[string]interface{}{
+		"disabled":        true,
+		"disabled_reason": reason,
+		"disabled_at":     &now,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to disable account: %v", err)
+	}
+	account.Disabled = true
+	account.DisabledReason = reason
+	account.DisabledAt = &now
+
+	if !wasDisabled {
+		slog.Info("Account disabled", "account_id", account.ID, "reason", reason)
+		m.emit(EventAccountDisabled, account.ID, map[string]string{"reason": reason})
+	}
+	return nil
+}
+
+// EnableAccount 重新启用账号
+func (m *Manager) EnableAccount(accountID string) (*model.Account, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return nil, fmt.Errorf("account %s not found", accountID)
+	}
+	if err := m.enableAccountLocked(account); err != nil {
+		return nil, err
+	}
+	return account.Clone(), nil
+}
+
+// enableAccountLocked 重新启用账号，调用方需持有mutex
+func (m *Manager) enableAccountLocked(account *model.Account) error {
+	if !account.Disabled {
+		return nil
+	}
+
+	if err := m.db.Model(account).Updates(map[string]interface{}{
+		"disabled":        false,
+		"disabled_reason": "",
+		"disabled_at":     nil,
+	}).Error; err != nil {
+		return fmt.Errorf("failed to enable account: %v", err)
+	}
+	account.Disabled = false
+	account.DisabledReason = ""
+	account.DisabledAt = nil
+
+	slog.Info("Account enabled", "account_id", account.ID)
+	m.emit(EventAccountEnabled, account.ID, nil)
+	return nil
+}
+
+// checkAccountEnabled 账号停用时返回 *AccountDisabledError
+func (m *Manager) checkAccountEnabled(accountID string) error {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	if account, exists := m.accounts[accountID]; exists && account.Disabled {
+		return &AccountDisabledError{AccountID: accountID, Reason: account.DisabledReason}
+	}
+	return nil
+}
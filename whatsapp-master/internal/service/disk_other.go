@@ -0,0 +1,10 @@
+//go:build !unix
+
+package service
+
+import "fmt"
+
+// diskUsage 当前平台不支持检查磁盘空间
+func diskUsage(path string) (total, free uint64, err error) {
+	return 0, 0, fmt.Errorf("disk usage is not supported on this platform")
+}
@@ -0,0 +1,14 @@
+//go:build unix
+
+package service
+
+import "syscall"
+
+// diskUsage 返回路径所在文件系统的总空间和非特权用户可用空间（字节）
+func diskUsage(path string) (total, free uint64, err error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, 0, err
+	}
+	return uint64(stat.Blocks) * uint64(stat.Bsize), uint64(stat.Bavail) * uint64(stat.Bsize), nil
+}
@@ -0,0 +1,207 @@
+package service
+
+import (
+	"sync"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// 事件类型
+const (
+	EventAccountStatusChanged = "account.status_changed"
+	EventAccountLoggedIn      = "account.logged_in"
+	EventAccountLoggedOut     = "account.logged_out"
+	EventAccountDisabled      = "account.disabled"
+	EventAccountEnabled       = "account.enabled"
+	EventAccountBanned        = "account.banned"
+	EventAccountBanReleased   = "account.ban_released"
+	EventAccountUpdated       = "account.updated"
+	EventQRCodeUpdated        = "qr.updated"
+	EventMessageSent          = "message.sent"
+	EventMessageFailed        = "message.failed"
+	EventMessageDelivered     = "message.delivered"
+	EventMessageRead          = "message.read"
+	EventMessageReceived      = "message.received"
+	EventMessageDeadLettered  = "message.dead_lettered"
+	EventMessageHeld          = "message.held"
+	EventContactOptedOut      = "contact.opted_out"
+	EventContactOptedIn       = "contact.opted_in"
+	EventConversationClaimed  = "conversation.claimed"
+	EventConversationReleased = "conversation.released"
+	EventWorkerRestarted      = "worker.restarted"
+	EventWorkerRestartFailed  = "worker.restart_failed"
+	EventWorkerCrashLooping   = "worker.crash_looping"
+	EventWorkerUnreachable    = "worker.unreachable"
+	EventWorkerReachable      = "worker.reachable"
+	EventWorkerIncompatible   = "worker.incompatible"
+	EventCampaignStarted      = "campaign.started"
+	EventCampaignPaused       = "campaign.paused"
+	EventCampaignCompleted    = "campaign.completed"
+	EventJobFinished          = "job.finished"
+	EventDiagnosticsUploaded  = "diagnostics.uploaded"
+	EventHostOffline          = "host.offline"
+	EventHostOnline           = "host.online"
+	EventProxyDown            = "proxy.down"
+	EventProxyUp              = "proxy.up"
+	EventDiskLow              = "disk.low"
+	EventDiskRecovered        = "disk.recovered"
+	EventSessionRefreshed     = "session.refreshed"
+	EventLeaderElected        = "leader.elected"
+	EventLeaderLost           = "leader.lost"
+	EventBackupFailed         = "backup.failed"
+	EventApprovalRequested    = "approval.requested"
+	EventLoginCodeIssued      = "login.code_issued"
+	EventLoginFailed          = "login.failed"
+)
+
+// eventHistorySize 事件总线保留的最近事件数，供GraphQL events查询
+const eventHistorySize = 500
+
+// EventBus 进程内事件总线
+type EventBus struct {
+	subscribers map[int]chan *model.Event
+	nextID      int
+	recent      []*model.Event // 环形缓冲区，next为下一个写入位置
+	next        int
+	mutex       sync.RWMutex
+}
+
+// NewEventBus 创建事件总线
+func NewEventBus() *EventBus {
+	return &EventBus{
+		subscribers: make(map[int]chan *model.Event),
+	}
+}
+
+// Subscribe 订阅事件，返回事件通道和取消订阅函数
+func (b *EventBus) Subscribe(buffer int) (<-chan *model.Event, func()) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	ch := make(chan *model.Event, buffer)
+	b.subscribers[id] = ch
+
+	return ch, func() {
+		b.mutex.Lock()
+		defer b.mutex.Unlock()
+		if sub, exists := b.subscribers[id]; exists {
+			delete(b.subscribers, id)
+			close(sub)
+		}
+	}
+}
+
+// Publish 发布事件，订阅者处理不过来时丢弃事件而不阻塞发布方
+func (b *EventBus) Publish(event *model.Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if len(b.recent) < eventHistorySize {
+		b.recent = append(b.recent, event)
+	} else {
+		b.recent[b.next] = event
+	}
+	b.next = (b.next + 1) % eventHistorySize
+
+	for _, ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Recent 返回最近发布的事件，最新的在前
+func (b *EventBus) Recent() []*model.Event {
+	b.mutex.RLock()
+	defer b.mutex.RUnlock()
+
+	events := make([]*model.Event, 0, len(b.recent))
+	for i := 1; i <= len(b.recent); i++ {
+		events = append(events, b.recent[(b.next-i+len(b.recent))%len(b.recent)])
+	}
+	return events
+}
+
+// Close 关闭所有订阅，用于服务关闭时结束长连接
+func (b *EventBus) Close() {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	for id, ch := range b.subscribers {
+		delete(b.subscribers, id)
+		close(ch)
+	}
+}
+
+// Events 返回事件总线
+func (m *Manager) Events() *EventBus {
+	return m.events
+}
+
+// emit 发布账号相关事件
+func (m *Manager) emit(eventType, accountID string, data interface{}) {
+	m.events.Publish(&model.Event{
+		Type:      eventType,
+		AccountID: accountID,
+		Data:      data,
+	})
+}
+
+// emitStatusChange 记录状态历史并发布账号状态变化事件，登录状态切换时额外发布登录事件并执行登录后钩子
+func (m *Manager) emitStatusChange(accountID, previous, status string, cause StatusCause) {
+	if previous == status {
+		return
+	}
+
+	m.recordStatusTransition(accountID, previous, status, cause)
+	m.emit(EventAccountStatusChanged, accountID, map[string]string{
+		"previous": previous,
+		"status":   status,
+		"source":   cause.Source,
+		"reason":   cause.Reason,
+	})
+
+	switch {
+	case status == "logged_in":
+		m.emit(EventAccountLoggedIn, accountID, nil)
+		m.runHookForAccount(HookPostLogin, accountID)
+		m.scheduleSessionCheckpoint(accountID)
+	case previous == "logged_in":
+		m.emit(EventAccountLoggedOut, accountID, map[string]string{"status": status})
+		if sessionLostStatuses[status] {
+			m.detectLogoutLoop(accountID)
+		}
+	}
+}
+
+// emitQRCode 二维码变化时发布事件
+func (m *Manager) emitQRCode(accountID, qrCode string) {
+	if qrCode == "" {
+		m.qrCodes.Delete(accountID)
+		return
+	}
+	if previous, loaded := m.qrCodes.Swap(accountID, qrCode); loaded && previous == qrCode {
+		return
+	}
+	m.emit(EventQRCodeUpdated, accountID, map[string]string{"qr_code": qrCode})
+}
+
+// emitMessage 根据消息方向和状态发布消息事件
+func (m *Manager) emitMessage(msg *model.Message) {
+	eventType := EventMessageSent
+	switch {
+	case msg.Direction == "inbound":
+		eventType = EventMessageReceived
+	case msg.Status == "failed":
+		eventType = EventMessageFailed
+	}
+	m.emit(eventType, msg.AccountID, msg)
+}
@@ -0,0 +1,283 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// 群组成员操作，与Worker的 /api/groups/participants/{action} 对应
+const (
+	GroupActionRemove  = "remove"
+	GroupActionPromote = "promote"
+	GroupActionDemote  = "demote"
+)
+
+// groupSyncColumns 从Worker刷新群组时更新的字段，邀请链接单独获取
+var groupSyncColumns = []string{"name", "description", "owner", "participants", "participant_count", "synced_at", "updated_at"}
+
+// ListGroups 返回账号的群组缓存，缓存为空、过期或refresh为true时先从Worker刷新；
+// 刷新失败但有缓存时返回缓存和警告
+func (m *Manager) ListGroups(ctx context.Context, accountID string, refresh bool) ([]*model.Group, string, error) {
+	groups, err := m.cachedGroups(accountID)
+	if err != nil {
+		return nil, "", err
+	}
+	if !refresh && len(groups) > 0 && !m.groupStale(groups[0].SyncedAt) {
+		return groups, "", nil
+	}
+
+	synced, err := m.SyncGroups(ctx, accountID)
+	if err != nil {
+		if len(groups) > 0 {
+			return groups, fmt.Sprintf("serving cached groups: %v", err), nil
+		}
+		return nil, "", err
+	}
+	return synced, "", nil
+}
+
+// SyncGroups 从Worker拉取账号的所有群组写入缓存，并删除Worker上已不存在的群组
+func (m *Manager) SyncGroups(ctx context.Context, accountID string) ([]*model.Group, error) {
+	result, err := m.FetchFromWorker(ctx, accountID, "/api/groups")
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	groups := make([]*model.Group, 0)
+	items, _ := result["data"].([]interface{})
+	for _, item := range items {
+		if raw, ok := item.(map[string]interface{}); ok {
+			if group := parseWorkerGroup(accountID, raw, now); group != nil {
+				groups = append(groups, group)
+			}
+		}
+	}
+
+	// 未登录的Worker返回空列表，此时保留已缓存的群组
+	if len(groups) > 0 {
+		if err := m.saveGroups(groups); err != nil {
+			return nil, err
+		}
+		if err := m.db.Where("account_id = ? AND synced_at < ?", accountID, now).Delete(&model.Group{}).Error; err != nil {
+			return nil, fmt.Errorf("failed to remove stale groups: %v", err)
+		}
+		slog.Debug("Groups synced", "account_id", accountID, "count", len(groups))
+	}
+	return m.cachedGroups(accountID)
+}
+
+// GetGroup 获取单个群组，缓存不存在、过期或refresh为true时从Worker刷新
+func (m *Manager) GetGroup(ctx context.Context, accountID, groupID string, refresh bool) (*model.Group, error) {
+	var group model.Group
+	err := m.db.Where("account_id = ? AND gid = ?", accountID, groupID).First(&group).Error
+	if err == nil && !refresh && !m.groupStale(group.SyncedAt) {
+		return &group, nil
+	}
+	return m.refreshGroup(ctx, accountID, groupID)
+}
+
+// UpdateGroupParticipants 对群组成员执行移除、设为管理员或取消管理员，成功后刷新群组缓存
+func (m *Manager) UpdateGroupParticipants(ctx context.Context, accountID, groupID, action string, participants []string) (*model.Group, error) {
+	switch action {
+	case GroupActionRemove, GroupActionPromote, GroupActionDemote:
+	default:
+		return nil, fmt.Errorf("unsupported group action: %s", action)
+	}
+
+	account, err := m.GetAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := m.postToWorker(ctx, account, "/api/groups/participants/"+action, map[string]interface{}{
+		"groupId":      groupID,
+		"participants": participants,
+	}); err != nil {
+		return nil, err
+	}
+
+	slog.Info("Group participants updated", "account_id", accountID, "group_id", groupID, "action", action, "count", len(participants))
+	return m.refreshGroup(ctx, accountID, groupID)
+}
+
+// RenameGroup 修改群组名称
+func (m *Manager) RenameGroup(ctx context.Context, accountID, groupID, name string) (*model.Group, error) {
+	account, err := m.GetAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := m.postToWorker(ctx, account, "/api/groups/subject", map[string]interface{}{
+		"groupId": groupID,
+		"name":    name,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	raw, _ := result["data"].(map[string]interface{})
+	group := parseWorkerGroup(accountID, raw, time.Now())
+	if group == nil {
+		return m.refreshGroup(ctx, accountID, groupID)
+	}
+	if err := m.saveGroups([]*model.Group{group}); err != nil {
+		return nil, err
+	}
+	return m.GetGroup(ctx, accountID, groupID, false)
+}
+
+// GroupInviteLink 从Worker获取群组邀请链接并保存到缓存
+func (m *Manager) GroupInviteLink(ctx context.Context, accountID, groupID string) (string, error) {
+	result, err := m.FetchFromWorker(ctx, accountID, "/api/groups/invite?groupId="+url.QueryEscape(groupID))
+	if err != nil {
+		return "", err
+	}
+
+	data, _ := result["data"].(map[string]interface{})
+	link, _ := data["link"].(string)
+	if link == "" {
+		if code, _ := data["code"].(string); code != "" {
+			link = "https://chat.whatsapp.com/" + code
+		}
+	}
+	if link == "" {
+		return "", fmt.Errorf("worker returned no invite link for group %s", groupID)
+	}
+
+	m.db.Model(&model.Group{}).Where("account_id = ? AND gid = ?", accountID, groupID).Update("invite_link", link)
+	return link, nil
+}
+
+// CreateGroup 通过Worker创建群组，成功后将新群组写入缓存
+func (m *Manager) CreateGroup(ctx context.Context, accountID string, req *model.CreateGroupRequest) (*model.CreateGroupResult, error) {
+	account, err := m.GetAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := m.postToWorker(ctx, account, "/api/groups/create", req)
+	if err != nil {
+		return nil, err
+	}
+
+	var created model.CreateGroupResult
+	if err := decodeWorkerData(result, &created); err != nil {
+		return nil, err
+	}
+	if groupID := serializedID(created.GID); groupID != "" {
+		if _, err := m.refreshGroup(ctx, accountID, groupID); err != nil {
+			slog.Warn("Failed to cache new group", "account_id", accountID, "group_id", groupID, "error", err)
+		}
+	}
+	return &created, nil
+}
+
+// AddGroupParticipants 通过Worker添加群组成员，成功后刷新群组缓存，返回Worker对每个成员的添加结果
+func (m *Manager) AddGroupParticipants(ctx context.Context, accountID string, req *model.AddGroupParticipantsRequest) (map[string]interface{}, error) {
+	account, err := m.GetAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := m.postToWorker(ctx, account, "/api/groups/participants/add", req)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := m.refreshGroup(ctx, accountID, req.GroupID); err != nil {
+		slog.Warn("Failed to refresh group", "account_id", accountID, "group_id", req.GroupID, "error", err)
+	}
+	data, _ := result["data"].(map[string]interface{})
+	return data, nil
+}
+
+// refreshGroup 从Worker获取单个群组并更新缓存
+func (m *Manager) refreshGroup(ctx context.Context, accountID, groupID string) (*model.Group, error) {
+	result, err := m.FetchFromWorker(ctx, accountID, "/api/groups/info?groupId="+url.QueryEscape(groupID))
+	if err != nil {
+		return nil, err
+	}
+
+	raw, _ := result["data"].(map[string]interface{})
+	group := parseWorkerGroup(accountID, raw, time.Now())
+	if group == nil {
+		return nil, fmt.Errorf("group %s not found", groupID)
+	}
+	if err := m.saveGroups([]*model.Group{group}); err != nil {
+		return nil, err
+	}
+
+	var saved model.Group
+	if err := m.db.Where("account_id = ? AND gid = ?", accountID, groupID).First(&saved).Error; err != nil {
+		return nil, fmt.Errorf("group %s not found", groupID)
+	}
+	return &saved, nil
+}
+
+// saveGroups 按账号和群组ID写入或更新缓存
+func (m *Manager) saveGroups(groups []*model.Group) error {
+	err := m.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "account_id"}, {Name: "gid"}},
+		DoUpdates: clause.AssignmentColumns(groupSyncColumns),
+	}).CreateInBatches(groups, 100).Error
+	if err != nil {
+		return fmt.Errorf("failed to save groups: %v", err)
+	}
+	return nil
+}
+
+// cachedGroups 按名称返回账号的群组缓存
+func (m *Manager) cachedGroups(accountID string) ([]*model.Group, error) {
+	groups := make([]*model.Group, 0)
+	if err := m.db.Where("account_id = ?", accountID).Order("name").Find(&groups).Error; err != nil {
+		return nil, fmt.Errorf("failed to load groups: %v", err)
+	}
+	return groups, nil
+}
+
+// groupStale 群组缓存是否已超过 GROUP_CACHE_SECONDS
+func (m *Manager) groupStale(syncedAt time.Time) bool {
+	return time.Since(syncedAt) > time.Duration(m.GetConfig().Group.CacheSeconds)*time.Second
+}
+
+// parseWorkerGroup 解析Worker返回的群组，缺少ID时返回nil
+func parseWorkerGroup(accountID string, raw map[string]interface{}, now time.Time) *model.Group {
+	groupID := serializedID(raw["id"])
+	if groupID == "" {
+		return nil
+	}
+
+	group := &model.Group{AccountID: accountID, GID: groupID, SyncedAt: now, Participants: model.GroupMemberList{}}
+	group.Name, _ = raw["name"].(string)
+	group.Description, _ = raw["description"].(string)
+	group.Owner, _ = raw["owner"].(string)
+	participants, _ := raw["participants"].([]interface{})
+	for _, item := range participants {
+		p, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		member := model.GroupMember{ID: serializedID(p["id"])}
+		member.IsAdmin, _ = p["isAdmin"].(bool)
+		member.IsSuperAdmin, _ = p["isSuperAdmin"].(bool)
+		group.Participants = append(group.Participants, member)
+	}
+	group.ParticipantCount = len(group.Participants)
+	return group
+}
+
+// serializedID 兼容Worker返回的字符串ID和 {_serialized: "..."} 形式的ID
+func serializedID(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		id, _ := v["_serialized"].(string)
+		return id
+	}
+	return ""
+}
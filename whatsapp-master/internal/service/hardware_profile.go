@@ -0,0 +1,228 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	_ "time/tzdata" // Master镜像可能没有系统时区数据，校验时区时使用内置数据
+
+	"whatsapp-aggregator/internal/model"
+)
+
+var (
+	// ErrHardwareProfileNotFound 硬件指纹配置不存在
+	ErrHardwareProfileNotFound = errors.New("hardware profile not found")
+	// ErrHardwareProfileInUse 仍有账号绑定，不能删除
+	ErrHardwareProfileInUse = errors.New("hardware profile is in use")
+	// ErrHardwareProfileExists 同名配置已存在
+	ErrHardwareProfileExists = errors.New("hardware profile name already exists")
+)
+
+// localeTag BCP 47 语言标签，如 en、zh-CN、zh-Hant-TW
+var localeTag = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+
+// hardwareWorkerEnv Master按账号的硬件指纹配置设置的Worker环境变量
+var hardwareWorkerEnv = []string{"HW_OS", "HW_BROWSER", "HW_USER_AGENT", "HW_SCREEN", "HW_LOCALE", "HW_TIMEZONE"}
+
+// validateHardwareProfile 校验并规范化硬件指纹配置
+func validateHardwareProfile(req *model.HardwareProfileRequest) error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return fmt.Errorf("name must not be empty")
+	}
+	if strings.ContainsAny(req.UserAgent, "\r\n") {
+		return fmt.Errorf("user_agent must be a single line")
+	}
+	if (req.ScreenWidth == 0) != (req.ScreenHeight == 0) {
+		return fmt.Errorf("screen_width and screen_height must be set together")
+	}
+	if req.Locale != "" && !localeTag.MatchString(req.Locale) {
+		return fmt.Errorf("invalid locale %q, expected a language tag such as en-US", req.Locale)
+	}
+	if req.Timezone != "" {
+		if _, err := time.LoadLocation(req.Timezone); err != nil || req.Timezone == "Local" {
+			return fmt.Errorf("invalid timezone %q, expected an IANA name such as Asia/Shanghai", req.Timezone)
+		}
+	}
+	return nil
+}
+
+// applyHardwareProfile 用请求替换配置的全部字段
+func applyHardwareProfile(profile *model.HardwareProfile, req *model.HardwareProfileRequest) {
+	profile.Name = req.Name
+	profile.HardwareInfo = model.HardwareInfo{
+		OS:           strings.TrimSpace(req.OS),
+		Browser:      strings.TrimSpace(req.Browser),
+		UserAgent:    strings.TrimSpace(req.UserAgent),
+		ScreenWidth:  req.ScreenWidth,
+		ScreenHeight: req.ScreenHeight,
+		Locale:       req.Locale,
+		Timezone:     req.Timezone,
+	}
+}
+
+// CreateHardwareProfile 创建硬件指纹配置
+func (m *Manager) CreateHardwareProfile(req *model.HardwareProfileRequest) (*model.HardwareProfile, error) {
+	if err := validateHardwareProfile(req); err != nil {
+		return nil, err
+	}
+	if err := m.checkHardwareProfileName(req.Name, ""); err != nil {
+		return nil, err
+	}
+	profile := &model.HardwareProfile{ID: generateID("hwp")}
+	applyHardwareProfile(profile, req)
+	if err := m.db.Create(profile).Error; err != nil {
+		return nil, fmt.Errorf("failed to create hardware profile: %v", err)
+	}
+	slog.Info("Hardware profile created", "profile_id", profile.ID, "name", profile.Name)
+	return profile, nil
+}
+
+// ListHardwareProfiles 按名称列出硬件指纹配置及绑定的账号数
+func (m *Manager) ListHardwareProfiles() ([]*model.HardwareProfile, error) {
+	profiles := make([]*model.HardwareProfile, 0)
+	if err := m.db.Order("name ASC").Find(&profiles).Error; err != nil {
+		return nil, fmt.Errorf("failed to list hardware profiles: %v", err)
+	}
+	counts, err := m.hardwareProfileAccounts()
+	if err != nil {
+		return nil, err
+	}
+	for _, profile := range profiles {
+		profile.Accounts = counts[profile.ID]
+	}
+	return profiles, nil
+}
+
+// GetHardwareProfile 获取硬件指纹配置
+func (m *Manager) GetHardwareProfile(id string) (*model.HardwareProfile, error) {
+	var profile model.HardwareProfile
+	if err := m.db.Where("id = ?", id).First(&profile).Error; err != nil {
+		return nil, ErrHardwareProfileNotFound
+	}
+	counts, err := m.hardwareProfileAccounts()
+	if err != nil {
+		return nil, err
+	}
+	profile.Accounts = counts[profile.ID]
+	return &profile, nil
+}
+
+// UpdateHardwareProfile 替换硬件指纹配置，绑定的账号在下次启动Worker或登录时使用新指纹
+func (m *Manager) UpdateHardwareProfile(id string, req *model.HardwareProfileRequest) (*model.HardwareProfile, error) {
+	if err := validateHardwareProfile(req); err != nil {
+		return nil, err
+	}
+	profile, err := m.GetHardwareProfile(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkHardwareProfileName(req.Name, id); err != nil {
+		return nil, err
+	}
+	applyHardwareProfile(profile, req)
+	if err := m.db.Save(profile).Error; err != nil {
+		return nil, fmt.Errorf("failed to update hardware profile: %v", err)
+	}
+	return profile, nil
+}
+
+// DeleteHardwareProfile 删除硬件指纹配置，仍有账号绑定时返回 ErrHardwareProfileInUse
+func (m *Manager) DeleteHardwareProfile(id string) error {
+	profile, err := m.GetHardwareProfile(id)
+	if err != nil {
+		return err
+	}
+	if profile.Accounts > 0 {
+		return fmt.Errorf("%w by %d accounts", ErrHardwareProfileInUse, profile.Accounts)
+	}
+	if err := m.db.Delete(profile).Error; err != nil {
+		return fmt.Errorf("failed to delete hardware profile: %v", err)
+	}
+	slog.Info("Hardware profile deleted", "profile_id", id)
+	return nil
+}
+
+// checkHardwareProfileName 名称不能与其他配置重复
+func (m *Manager) checkHardwareProfileName(name, exceptID string) error {
+	var count int64
+	m.db.Model(&model.HardwareProfile{}).Where("name = ? AND id <> ?", name, exceptID).Count(&count)
+	if count > 0 {
+		return fmt.Errorf("%w: %s", ErrHardwareProfileExists, name)
+	}
+	return nil
+}
+
+// hardwareProfileAccounts 各配置绑定的账号数
+func (m *Manager) hardwareProfileAccounts() (map[string]int64, error) {
+	var rows []struct {
+		HardwareProfileID string
+		Count             int64
+	}
+	if err := m.db.Model(&model.Account{}).Select("hardware_profile_id, COUNT(*) AS count").
+		Where("hardware_profile_id <> ''").Group("hardware_profile_id").Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to count hardware profile accounts: %v", err)
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		counts[row.HardwareProfileID] = row.Count
+	}
+	return counts, nil
+}
+
+// checkHardwareProfile 创建或更新账号前确认要绑定的配置存在，id为空时不绑定
+func (m *Manager) checkHardwareProfile(id string) error {
+	if id == "" {
+		return nil
+	}
+	var count int64
+	m.db.Model(&model.HardwareProfile{}).Where("id = ?", id).Count(&count)
+	if count == 0 {
+		return fmt.Errorf("%w: %s", ErrHardwareProfileNotFound, id)
+	}
+	return nil
+}
+
+// accountHardware 账号绑定的硬件指纹，未绑定或配置已不存在时返回nil。启动Worker时调用方可能持有 m.mutex，这里不加锁
+func (m *Manager) accountHardware(account *model.Account) *model.HardwareInfo {
+	if account.HardwareProfileID == "" {
+		return nil
+	}
+	var profile model.HardwareProfile
+	if err := m.db.Where("id = ?", account.HardwareProfileID).First(&profile).Error; err != nil {
+		slog.Warn("Hardware profile of account not found, worker uses its default fingerprint", "account_id", account.ID, "profile_id", account.HardwareProfileID)
+		return nil
+	}
+	return &profile.HardwareInfo
+}
+
+// loginHardware 登录请求中的硬件信息：账号绑定了配置时使用配置，保证每次登录指纹一致
+func (m *Manager) loginHardware(account *model.Account, requested model.HardwareInfo) model.HardwareInfo {
+	if hw := m.accountHardware(account); hw != nil {
+		return *hw
+	}
+	return requested
+}
+
+// workerHardwareEnv 硬件指纹对应的 docker run 环境变量，未设置的项不传
+func workerHardwareEnv(hw *model.HardwareInfo) []string {
+	if hw == nil {
+		return nil
+	}
+	var screen string
+	if hw.ScreenWidth > 0 && hw.ScreenHeight > 0 {
+		screen = strconv.Itoa(hw.ScreenWidth) + "x" + strconv.Itoa(hw.ScreenHeight)
+	}
+	values := []string{hw.OS, hw.Browser, hw.UserAgent, screen, hw.Locale, hw.Timezone}
+	var args []string
+	for i, name := range hardwareWorkerEnv {
+		if values[i] != "" {
+			args = append(args, "-e", name+"="+values[i])
+		}
+	}
+	return args
+}
@@ -0,0 +1,307 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// 健康状态，按严重程度递增
+const (
+	HealthHealthy   = "healthy"
+	HealthDegraded  = "degraded"
+	HealthUnhealthy = "unhealthy"
+)
+
+// healthCheckTimeout 单项依赖检查（数据库、Docker）的超时时间
+const healthCheckTimeout = 3 * time.Second
+
+// Version 构建版本，发布时通过 -ldflags "-X whatsapp-aggregator/internal/service.Version=v1.2.3" 注入，
+// 未注入时使用构建信息中的模块版本或代码提交
+var Version = ""
+
+// buildVersion 计算一次版本号
+var buildVersion = sync.OnceValue(func() string {
+	if Version != "" {
+		return Version
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "dev"
+	}
+	if info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" && len(setting.Value) >= 12 {
+			return "dev-" + setting.Value[:12]
+		}
+	}
+	return "dev"
+})
+
+// BuildVersion 当前构建版本
+func BuildVersion() string {
+	return buildVersion()
+}
+
+// healthSeverity 健康状态的严重程度，用于取最差结果
+var healthSeverity = map[string]int{
+	HealthHealthy:   0,
+	HealthDegraded:  1,
+	HealthUnhealthy: 2,
+}
+
+// GetHealthStatus 检查数据库、Docker、磁盘、端口池和goroutine，整体状态取各项检查中最差的结果
+func (m *Manager) GetHealthStatus(ctx context.Context) *model.HealthStatus {
+	checks := []*model.HealthCheck{
+		runHealthCheck("database", func(check *model.HealthCheck) { m.checkDatabase(ctx, check) }),
+		runHealthCheck("docker", func(check *model.HealthCheck) { checkDocker(ctx, check) }),
+		runHealthCheck("disk", m.checkDisk),
+		runHealthCheck("port_pool", m.checkPortPool),
+		runHealthCheck("hosts", m.checkWorkerHosts),
+		runHealthCheck("worker_versions", m.checkWorkerVersions),
+		runHealthCheck("goroutines", m.checkGoroutines),
+	}
+	status := HealthHealthy
+	for _, check := range checks {
+		if healthSeverity[check.Status] > healthSeverity[status] {
+			status = check.Status
+		}
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	accounts := make([]*model.Account, 0, len(m.accounts))
+	runningCount := 0
+	loggedInCount := 0
+
+	for _, account := range m.accounts {
+		accounts = append(accounts, account)
+		if account.Status == "running" {
+			runningCount++
+		}
+		if account.Status == "logged_in" {
+			loggedInCount++
+		}
+	}
+
+	return &model.HealthStatus{
+		Status:        status,
+		Uptime:        time.Since(m.startTime).String(),
+		Accounts:      accounts,
+		TotalCount:    len(accounts),
+		RunningCount:  runningCount,
+		LoggedInCount: loggedInCount,
+		Checks:        checks,
+		SystemInfo: model.SystemInfo{
+			WorkerMode:  m.GetConfig().Worker.Mode,
+			Environment: m.GetConfig().Server.Environment,
+			Version:     buildVersion(),
+			GoVersion:   runtime.Version(),
+			Goroutines:  runtime.NumGoroutine(),
+		},
+	}
+}
+
+// runHealthCheck 执行一项检查并记录耗时，检查函数未设置状态时视为健康
+func runHealthCheck(name string, fn func(check *model.HealthCheck)) *model.HealthCheck {
+	started := time.Now()
+	check := &model.HealthCheck{Name: name, Status: HealthHealthy, Details: map[string]interface{}{}}
+	fn(check)
+	check.DurationMs = time.Since(started).Milliseconds()
+	return check
+}
+
+// checkDatabase 数据库连接是否可用，不可用时整体不健康
+func (m *Manager) checkDatabase(ctx context.Context, check *model.HealthCheck) {
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		check.Status = HealthUnhealthy
+		check.Message = fmt.Sprintf("database handle unavailable: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+	if err := sqlDB.PingContext(ctx); err != nil {
+		check.Status = HealthUnhealthy
+		check.Message = fmt.Sprintf("database ping failed: %v", err)
+		return
+	}
+
+	stats := sqlDB.Stats()
+	check.Details["type"] = m.GetConfig().DB.Type
+	check.Details["open_connections"] = stats.OpenConnections
+	check.Details["in_use"] = stats.InUse
+}
+
+// checkWorkerHosts Worker主机是否在线，以及是否还有可调度新账号的主机
+func (m *Manager) checkWorkerHosts(check *model.HealthCheck) {
+	m.hostMutex.RLock()
+	offline := make([]string, 0)
+	schedulable := 0
+	for _, host := range m.hosts {
+		if host.Status == model.HostStatusOffline {
+			offline = append(offline, host.ID)
+			continue
+		}
+		workers := m.hostWorkersLocked(host.ID)
+		if host.Status == model.HostStatusOnline && !host.Cordoned && (host.MaxWorkers == 0 || workers < host.MaxWorkers) {
+			schedulable++
+		}
+	}
+	total := len(m.hosts)
+	m.hostMutex.RUnlock()
+	sort.Strings(offline)
+
+	check.Details["total"] = total
+	check.Details["offline"] = offline
+	check.Details["schedulable"] = schedulable
+
+	switch {
+	case len(offline) > 0:
+		check.Status = HealthDegraded
+		check.Message = fmt.Sprintf("%d hosts offline: %s", len(offline), strings.Join(offline, ", "))
+	case schedulable == 0:
+		check.Status = HealthDegraded
+		check.Message = "no host available for new workers"
+	}
+}
+
+// checkDocker Docker守护进程是否可达，不可达时已有Worker不受影响，但无法创建或重启Worker
+func checkDocker(ctx context.Context, check *model.HealthCheck) {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	output, err := exec.CommandContext(ctx, "docker", "version", "--format", "{{.Server.Version}}").CombinedOutput()
+	if err != nil {
+		check.Status = HealthDegraded
+		check.Message = fmt.Sprintf("docker daemon unreachable: %v", err)
+		if out := strings.TrimSpace(string(output)); out != "" {
+			check.Details["output"] = out
+		}
+		return
+	}
+	check.Details["server_version"] = strings.TrimSpace(string(output))
+}
+
+// checkDisk 会话目录所在磁盘的剩余空间
+func (m *Manager) checkDisk(check *model.HealthCheck) {
+	path := existingParent(m.GetConfig().Worker.SessionDir)
+	total, free, err := diskUsage(path)
+	if err != nil {
+		check.Status = HealthDegraded
+		check.Message = fmt.Sprintf("failed to stat %s: %v", path, err)
+		return
+	}
+
+	freePercent := 0.0
+	if total > 0 {
+		freePercent = float64(free) * 100 / float64(total)
+	}
+	check.Details["path"] = path
+	check.Details["total_bytes"] = total
+	check.Details["free_bytes"] = free
+	check.Details["free_percent"] = freePercent
+
+	if minFree := m.GetConfig().Health.DiskMinFreePercent; freePercent < float64(minFree) {
+		check.Status = HealthDegraded
+		check.Message = fmt.Sprintf("only %.1f%% disk space free (minimum %d%%)", freePercent, minFree)
+	}
+}
+
+// existingParent 返回路径本身或最近的已存在上级目录，会话目录可能还未创建
+func existingParent(path string) string {
+	path = filepath.Clean(path)
+	for {
+		if _, err := os.Stat(path); err == nil || !errors.Is(err, os.ErrNotExist) {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
+
+// portPoolForecastWindow 预测端口池耗尽时间使用的账号创建速度统计窗口
+const portPoolForecastWindow = 7 * 24 * time.Hour
+
+// checkPortPool Worker端口池使用率和预计耗尽时间，端口耗尽时无法创建新账号
+func (m *Manager) checkPortPool(check *model.HealthCheck) {
+	p := m.portPool
+	p.mutex.Lock()
+	ranges := formatPortRanges(p.ranges)
+	total := p.totalLocked()
+	allocated, occupied, available := p.countsLocked()
+	p.mutex.Unlock()
+
+	// 按最近7天新建且仍存在的账号数估算端口消耗速度，已删除账号的端口已经释放
+	since := time.Now().Add(-portPoolForecastWindow)
+	created := 0
+	m.mutex.RLock()
+	for _, account := range m.accounts {
+		if account.CreatedAt.After(since) {
+			created++
+		}
+	}
+	m.mutex.RUnlock()
+
+	usedPercent := 0.0
+	if total > 0 {
+		usedPercent = float64(total-available) * 100 / float64(total)
+	}
+	check.Details["ranges"] = ranges
+	check.Details["total"] = total
+	check.Details["allocated"] = allocated
+	check.Details["occupied"] = occupied
+	check.Details["available"] = available
+	check.Details["used_percent"] = usedPercent
+	check.Details["created_last_7d"] = created
+
+	daysLeft := -1.0
+	if created > 0 {
+		perDay := float64(created) / portPoolForecastWindow.Hours() * 24
+		daysLeft = math.Round(float64(available)/perDay*10) / 10
+		check.Details["projected_days_left"] = daysLeft
+	}
+
+	warnDays := m.GetConfig().Health.PortPoolWarnDays
+	switch warn := m.GetConfig().Health.PortPoolWarnPercent; {
+	case available == 0:
+		check.Status = HealthDegraded
+		check.Message = "port pool exhausted, new workers cannot be started"
+	case warn > 0 && usedPercent >= float64(warn):
+		check.Status = HealthDegraded
+		check.Message = fmt.Sprintf("port pool %.1f%% used (warning at %d%%)", usedPercent, warn)
+	case warnDays > 0 && daysLeft >= 0 && daysLeft < float64(warnDays):
+		check.Status = HealthDegraded
+		check.Message = fmt.Sprintf("port pool projected to be exhausted in %.1f days at the current creation rate (warning at %d days)", daysLeft, warnDays)
+	}
+}
+
+// checkGoroutines goroutine数量，持续增长通常意味着泄漏
+func (m *Manager) checkGoroutines(check *model.HealthCheck) {
+	count := runtime.NumGoroutine()
+	check.Details["count"] = count
+
+	if limit := m.GetConfig().Health.MaxGoroutines; limit > 0 && count > limit {
+		check.Status = HealthDegraded
+		check.Message = fmt.Sprintf("%d goroutines running (limit %d)", count, limit)
+	}
+}
@@ -0,0 +1,53 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// dockerHealthArgs Worker容器Docker健康检查对应的 docker run 参数，WORKER_HEALTH_CMD为none时不设置
+func (m *Manager) dockerHealthArgs() []string {
+	cfg := m.GetConfig().Worker
+	if !m.dockerHealthEnabled() {
+		return nil
+	}
+	return []string{
+		"--health-cmd", cfg.HealthCmd,
+		"--health-interval", fmt.Sprintf("%ds", cfg.HealthInterval),
+		"--health-timeout", fmt.Sprintf("%ds", cfg.HealthTimeout),
+		"--health-retries", strconv.Itoa(cfg.HealthRetries),
+		"--health-start-period", fmt.Sprintf("%ds", cfg.HealthStartPeriod),
+	}
+}
+
+// dockerHealthEnabled 是否为Worker容器配置了Docker健康检查
+func (m *Manager) dockerHealthEnabled() bool {
+	cmd := strings.TrimSpace(m.GetConfig().Worker.HealthCmd)
+	return cmd != "" && cmd != "none"
+}
+
+// dockerHealthStatus 从 docker ps 的状态列中解析健康检查状态，如 "Up 5 minutes (unhealthy)"
+var dockerHealthStatus = regexp.MustCompile(`\((healthy|unhealthy|health: starting)\)$`)
+
+// dockerUnhealthyWorkers 查询各主机上Docker健康检查判定为unhealthy的Worker容器，返回 主机ID -> 容器名集合；docker不可用的主机跳过
+func (m *Manager) dockerUnhealthyWorkers(hostIDs map[string]bool) map[string]map[string]bool {
+	unhealthy := make(map[string]map[string]bool)
+	for hostID := range hostIDs {
+		containers, err := m.hostRuntime(hostID).List(workerContainerPrefix)
+		if err != nil {
+			slog.Debug("Failed to query docker health", "host_id", hostID, "error", err)
+			continue
+		}
+		names := make(map[string]bool)
+		for _, container := range containers {
+			if container.Managed && container.Running() && container.Health == "unhealthy" {
+				names[container.Name] = true
+			}
+		}
+		unhealthy[hostID] = names
+	}
+	return unhealthy
+}
@@ -0,0 +1,87 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"time"
+
+	"whatsapp-aggregator/internal/logging"
+	"whatsapp-aggregator/internal/model"
+)
+
+// 状态变化的触发来源
+const (
+	StatusSourceAPI         = "api"          // 管理接口操作
+	StatusSourceWorker      = "worker"       // Worker上报或轮询到的状态
+	StatusSourceSupervisor  = "supervisor"   // 自动恢复任务
+	StatusSourceReconcile   = "reconcile"    // 启动时容器对账
+	StatusSourceShutdown    = "shutdown"     // 服务关闭
+	StatusSourceChaos       = "chaos"        // 故障注入
+	StatusSourceBreaker     = "breaker"      // Worker代理熔断
+	StatusSourceBanDetector = "ban_detector" // 检测到封号信号后隔离
+)
+
+// statusHistoryColumns 状态历史允许过滤和排序的字段
+var statusHistoryColumns = map[string]string{
+	"previous":   "previous",
+	"status":     "status",
+	"source":     "source",
+	"created_at": "created_at",
+}
+
+// StatusCause 状态变化的触发来源和原因，记录在状态历史中
+type StatusCause struct {
+	Source    string
+	Reason    string
+	RequestID string
+	Details   interface{} // 附加信息，序列化后写入状态历史的 details
+}
+
+// withError 错误中带有Worker启动诊断时附加到状态历史
+func (c StatusCause) withError(err error) StatusCause {
+	var startupErr *WorkerStartupError
+	if errors.As(err, &startupErr) {
+		c.Details = startupErr.Diagnostics
+	}
+	return c
+}
+
+// apiCause 由API请求触发的状态变化，带上请求ID便于和请求日志对应
+func apiCause(ctx context.Context, reason string) StatusCause {
+	return StatusCause{Source: StatusSourceAPI, Reason: reason, RequestID: logging.RequestID(ctx)}
+}
+
+// recordStatusTransition 写入一条状态历史
+func (m *Manager) recordStatusTransition(accountID, previous, status string, cause StatusCause) {
+	transition := &model.StatusTransition{
+		AccountID: accountID,
+		Previous:  previous,
+		Status:    status,
+		Source:    cause.Source,
+		Reason:    cause.Reason,
+		RequestID: cause.RequestID,
+		CreatedAt: time.Now(),
+	}
+	if cause.Details != nil {
+		if raw, err := json.Marshal(cause.Details); err == nil {
+			transition.Details = model.RawJSON(raw)
+		}
+	}
+	if err := m.db.Create(transition).Error; err != nil {
+		slog.Warn("Failed to record status transition", "account_id", accountID, "status", status, "error", err)
+	}
+}
+
+// ListStatusHistory 分页查询账号的状态历史，默认按时间倒序
+func (m *Manager) ListStatusHistory(accountID string, q *model.ListQuery) ([]*model.StatusTransition, int64, error) {
+	db := m.db.Model(&model.StatusTransition{}).Where("account_id = ?", accountID)
+
+	history := make([]*model.StatusTransition, 0)
+	total, err := findWithListQuery(db, q, statusHistoryColumns, "-created_at", &history)
+	if err != nil {
+		return nil, 0, err
+	}
+	return history, total, nil
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/tracing"
+)
+
+// Worker生命周期钩子
+const (
+	HookPreSpawn  = "pre_spawn"
+	HookPostReady = "post_ready"
+	HookPreDelete = "pre_delete"
+	HookPostLogin = "post_login"
+)
+
+// hookOutputLimit 钩子失败时错误信息中保留的输出长度
+const hookOutputLimit = 500
+
+// hookTarget 钩子配置的命令或Webhook地址
+func (m *Manager) hookTarget(hook string) string {
+	cfg := m.GetConfig().Hooks
+	switch hook {
+	case HookPreSpawn:
+		return cfg.PreSpawn
+	case HookPostReady:
+		return cfg.PostReady
+	case HookPreDelete:
+		return cfg.PreDelete
+	case HookPostLogin:
+		return cfg.PostLogin
+	}
+	return ""
+}
+
+// hookPayload 生成钩子收到的账号信息，调用方需持有读锁或账号不会被并发修改
+func hookPayload(hook string, account *model.Account) *model.HookPayload {
+	return &model.HookPayload{
+		Hook:        hook,
+		AccountID:   account.ID,
+		Name:        account.Name,
+		Phone:       account.Phone,
+		Status:      account.Status,
+		HostID:      account.HostID,
+		Port:        account.Port,
+		ServiceURL:  account.ServiceURL,
+		ContainerID: account.ContainerID,
+		Pool:        account.Pool,
+		Tags:        account.Tags,
+		TenantID:    account.TenantID,
+		ProxyIP:     account.Proxy.IP,
+		ProxyPort:   account.Proxy.Port,
+		Timestamp:   time.Now(),
+	}
+}
+
+// runHook 同步执行钩子，未配置时直接返回；返回的错误由调用方决定是否中止操作
+func (m *Manager) runHook(ctx context.Context, hook string, account *model.Account) error {
+	target := m.hookTarget(hook)
+	if target == "" {
+		return nil
+	}
+	return m.executeHook(ctx, target, hookPayload(hook, account))
+}
+
+// runHookAsync 在后台执行钩子，失败只记录日志
+func (m *Manager) runHookAsync(hook string, account *model.Account) {
+	target := m.hookTarget(hook)
+	if target == "" {
+		return
+	}
+	payload := hookPayload(hook, account)
+	go func() {
+		if err := m.executeHook(context.Background(), target, payload); err != nil {
+			slog.Warn("Lifecycle hook failed", "hook", hook, "account_id", payload.AccountID, "error", err)
+		}
+	}()
+}
+
+// runHookForAccount 按账号ID在后台执行钩子，用于调用方可能持有写锁的场景
+func (m *Manager) runHookForAccount(hook, accountID string) {
+	if m.hookTarget(hook) == "" {
+		return
+	}
+	go func() {
+		m.mutex.RLock()
+		account, exists := m.accounts[accountID]
+		if !exists {
+			m.mutex.RUnlock()
+			return
+		}
+		payload := hookPayload(hook, account)
+		m.mutex.RUnlock()
+
+		if err := m.executeHook(context.Background(), m.hookTarget(hook), payload); err != nil {
+			slog.Warn("Lifecycle hook failed", "hook", hook, "account_id", accountID, "error", err)
+		}
+	}()
+}
+
+// executeHook 执行一次钩子：http(s) 地址以JSON POST调用，其余作为 sh -c 命令执行
+func (m *Manager) executeHook(ctx context.Context, target string, payload *model.HookPayload) (err error) {
+	ctx, span := tracing.Start(ctx, "hook "+payload.Hook, tracing.KindInternal, tracing.String("account.id", payload.AccountID))
+	defer func() { span.Finish(err) }()
+
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(max(m.GetConfig().Hooks.Timeout, 1))*time.Second)
+	defer cancel()
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode hook payload: %v", err)
+	}
+
+	start := time.Now()
+	if strings.HasPrefix(target, "http://") || strings.HasPrefix(target, "https://") {
+		err = m.postHook(ctx, target, payload.Hook, body)
+	} else {
+		err = runHookCommand(ctx, target, payload, body)
+	}
+	if err != nil {
+		return fmt.Errorf("%s hook failed: %v", payload.Hook, err)
+	}
+	slog.Info("Lifecycle hook finished", "hook", payload.Hook, "account_id", payload.AccountID, "duration", time.Since(start))
+	return nil
+}
+
+// postHook 调用Webhook钩子，配置了 HOOK_SECRET 时带 X-Fleet-Signature 签名，非2xx响应视为失败
+func (m *Manager) postHook(ctx context.Context, target, hook string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Fleet-Event", "hook."+hook)
+	if secret := m.GetConfig().Hooks.Secret; secret != "" {
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(body)
+		req.Header.Set(WebhookSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// runHookCommand 执行命令钩子：账号信息通过环境变量和标准输入（JSON）传入，非0退出码视为失败
+func runHookCommand(ctx context.Context, command string, payload *model.HookPayload, body []byte) error {
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Env = append(os.Environ(),
+		"HOOK="+payload.Hook,
+		"ACCOUNT_ID="+payload.AccountID,
+		"ACCOUNT_PHONE="+payload.Phone,
+		"ACCOUNT_STATUS="+payload.Status,
+		"WORKER_HOST_ID="+payload.HostID,
+		"WORKER_PORT="+strconv.Itoa(payload.Port),
+		"WORKER_SERVICE_URL="+payload.ServiceURL,
+		"WORKER_CONTAINER="+payload.ContainerID,
+		"PROXY_IP="+payload.ProxyIP,
+		"PROXY_PORT="+strconv.Itoa(payload.ProxyPort),
+	)
+	cmd.Stdin = bytes.NewReader(body)
+
+	output, err := cmd.CombinedOutput()
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	out := strings.TrimSpace(string(output))
+	if len(out) > hookOutputLimit {
+		out = "..." + out[len(out)-hookOutputLimit:]
+	}
+	if out == "" {
+		return err
+	}
+	return fmt.Errorf("%v: %s", err, out)
+}
@@ -0,0 +1,717 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/hoststat"
+	"whatsapp-aggregator/internal/model"
+)
+
+// docker命令超时，拉取镜像单独使用更长的超时
+const (
+	dockerTimeout     = 2 * time.Minute
+	dockerPullTimeout = 15 * time.Minute
+)
+
+// agentStatusTimeout 心跳请求 fleet-agent 的超时
+const agentStatusTimeout = 5 * time.Second
+
+// JobEvacuateHost 迁移主机上所有账号的任务类型
+const JobEvacuateHost = "evacuate_host"
+
+// ErrHostHasWorkers 删除仍有账号的主机
+var ErrHostHasWorkers = errors.New("host still has workers placed on it, evacuate it first")
+
+// 按账号代理地区调度的方式
+const (
+	RegionAffinityOff     = "off"
+	RegionAffinityPrefer  = "prefer"
+	RegionAffinityRequire = "require"
+)
+
+// validateRegionAffinity 校验地区调度配置
+func validateRegionAffinity(affinity string) error {
+	switch affinity {
+	case RegionAffinityOff, RegionAffinityPrefer, RegionAffinityRequire:
+		return nil
+	}
+	return fmt.Errorf("invalid SCHEDULER_REGION_AFFINITY %q: must be off, prefer or require", affinity)
+}
+
+// loadHosts 加载本机和已注册的远程主机，以及账号当前所在的主机
+func (m *Manager) loadHosts() error {
+	now := time.Now()
+	m.hosts[model.LocalHostID] = &model.Host{
+		ID:         model.LocalHostID,
+		Name:       model.LocalHostID,
+		Address:    "localhost",
+		Region:     strings.TrimSpace(m.GetConfig().Scheduler.LocalRegion),
+		MaxWorkers: m.GetConfig().Scheduler.LocalMaxWorkers,
+		Cordoned:   !m.GetConfig().Scheduler.LocalEnabled,
+		Status:     model.HostStatusOnline,
+		LastSeenAt: &now,
+		CreatedAt:  m.startTime,
+		UpdatedAt:  m.startTime,
+	}
+	m.refreshLocalHost()
+
+	var hosts []*model.Host
+	if err := m.db.Find(&hosts).Error; err != nil {
+		return err
+	}
+	for _, host := range hosts {
+		host.Status = model.HostStatusUnknown
+		m.hosts[host.ID] = host
+	}
+
+	for _, account := range m.accounts {
+		if account.HostID == "" {
+			// 多主机调度之前创建的账号都运行在本机
+			account.HostID = model.LocalHostID
+		}
+		m.placements[account.ID] = account.HostID
+	}
+	slog.Info("Loaded worker hosts", "count", len(m.hosts))
+	return nil
+}
+
+// refreshLocalHost 采集本机资源
+func (m *Manager) refreshLocalHost() {
+	stats := hoststat.Collect()
+	now := time.Now()
+
+	m.hostMutex.Lock()
+	defer m.hostMutex.Unlock()
+	host := m.hosts[model.LocalHostID]
+	host.CPUs = stats.CPUs
+	host.MemoryTotal = stats.MemoryTotal
+	host.MemoryAvailable = stats.MemoryAvailable
+	host.Load1 = stats.Load1
+	host.LastSeenAt = &now
+}
+
+// RegisterHost 注册运行 fleet-agent 的远程主机，返回调用 agent 使用的凭证
+func (m *Manager) RegisterHost(req *model.CreateHostRequest) (*model.RegisteredHost, error) {
+	id := strings.TrimSpace(req.ID)
+	if id == "" {
+		return nil, fmt.Errorf("host id is required")
+	}
+	if id == model.LocalHostID {
+		return nil, fmt.Errorf("host id %s is reserved for the master host", model.LocalHostID)
+	}
+	if req.MaxWorkers < 0 {
+		return nil, fmt.Errorf("max_workers must not be negative")
+	}
+	agentURL, err := normalizeAgentURL(req.AgentURL)
+	if err != nil {
+		return nil, err
+	}
+
+	host := &model.Host{
+		ID:         id,
+		Name:       valueOrDefault(strings.TrimSpace(req.Name), id),
+		Address:    strings.TrimSpace(req.Address),
+		AgentURL:   agentURL,
+		Region:     strings.TrimSpace(req.Region),
+		Token:      model.Secret(randomHex(24)),
+		MaxWorkers: req.MaxWorkers,
+		Status:     model.HostStatusUnknown,
+	}
+
+	m.hostMutex.Lock()
+	if _, exists := m.hosts[id]; exists {
+		m.hostMutex.Unlock()
+		return nil, fmt.Errorf("host %s already exists", id)
+	}
+	if err := m.db.Create(host).Error; err != nil {
+		m.hostMutex.Unlock()
+		return nil, fmt.Errorf("failed to create host: %v", err)
+	}
+	m.hosts[id] = host
+	m.hostMutex.Unlock()
+
+	slog.Info("Host registered", "host_id", id, "address", host.Address, "agent_url", host.AgentURL, "region", host.Region, "max_workers", host.MaxWorkers)
+	// 立即做一次心跳，注册后即可调度
+	m.checkHost(id)
+
+	registered, err := m.GetHost(id)
+	if err != nil {
+		return nil, err
+	}
+	return &model.RegisteredHost{Host: *registered, Token: string(host.Token)}, nil
+}
+
+// normalizeAgentURL 校验 fleet-agent 地址并去掉末尾的斜杠
+func normalizeAgentURL(raw string) (string, error) {
+	raw = strings.TrimRight(strings.TrimSpace(raw), "/")
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return "", fmt.Errorf("invalid agent_url %q: must be an http(s) URL", raw)
+	}
+	return raw, nil
+}
+
+// GetHost 获取主机及其当前资源和放置的账号数
+func (m *Manager) GetHost(hostID string) (*model.Host, error) {
+	m.hostMutex.RLock()
+	defer m.hostMutex.RUnlock()
+
+	host, exists := m.hosts[hostID]
+	if !exists {
+		return nil, fmt.Errorf("host %s not found", hostID)
+	}
+	return m.hostSnapshotLocked(host), nil
+}
+
+// ListHosts 列出所有主机，本机排在最前
+func (m *Manager) ListHosts() []*model.Host {
+	m.hostMutex.RLock()
+	defer m.hostMutex.RUnlock()
+
+	hosts := make([]*model.Host, 0, len(m.hosts))
+	for _, host := range m.hosts {
+		hosts = append(hosts, m.hostSnapshotLocked(host))
+	}
+	sort.Slice(hosts, func(i, j int) bool {
+		if (hosts[i].ID == model.LocalHostID) != (hosts[j].ID == model.LocalHostID) {
+			return hosts[i].ID == model.LocalHostID
+		}
+		return hosts[i].ID < hosts[j].ID
+	})
+	return hosts
+}
+
+// hostSnapshotLocked 复制主机状态，调用方需持有hostMutex
+func (m *Manager) hostSnapshotLocked(host *model.Host) *model.Host {
+	snapshot := *host
+	snapshot.Workers = m.hostWorkersLocked(host.ID)
+	return &snapshot
+}
+
+// hostWorkersLocked 放置在主机上的账号数，调用方需持有hostMutex
+func (m *Manager) hostWorkersLocked(hostID string) int {
+	count := 0
+	for _, placed := range m.placements {
+		if placed == hostID {
+			count++
+		}
+	}
+	return count
+}
+
+// UpdateHost 修改主机，本机的容量和调度开关由 SCHEDULER_LOCAL_* 配置
+func (m *Manager) UpdateHost(hostID string, req *model.UpdateHostRequest) (*model.Host, error) {
+	if hostID == model.LocalHostID {
+		return nil, fmt.Errorf("the master host is configured with SCHEDULER_LOCAL_* settings")
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		updates["name"] = valueOrDefault(strings.TrimSpace(*req.Name), hostID)
+	}
+	if req.Address != nil {
+		address := strings.TrimSpace(*req.Address)
+		if address == "" {
+			return nil, fmt.Errorf("address must not be empty")
+		}
+		updates["address"] = address
+	}
+	if req.AgentURL != nil {
+		agentURL, err := normalizeAgentURL(*req.AgentURL)
+		if err != nil {
+			return nil, err
+		}
+		updates["agent_url"] = agentURL
+	}
+	if req.Region != nil {
+		updates["region"] = strings.TrimSpace(*req.Region)
+	}
+	if req.MaxWorkers != nil {
+		if *req.MaxWorkers < 0 {
+			return nil, fmt.Errorf("max_workers must not be negative")
+		}
+		updates["max_workers"] = *req.MaxWorkers
+	}
+	if req.Cordoned != nil {
+		updates["cordoned"] = *req.Cordoned
+	}
+
+	m.hostMutex.Lock()
+	host, exists := m.hosts[hostID]
+	if !exists {
+		m.hostMutex.Unlock()
+		return nil, fmt.Errorf("host %s not found", hostID)
+	}
+	if len(updates) > 0 {
+		if err := m.db.Model(&model.Host{}).Where("id = ?", hostID).Updates(updates).Error; err != nil {
+			m.hostMutex.Unlock()
+			return nil, fmt.Errorf("failed to update host: %v", err)
+		}
+	}
+	if req.Name != nil {
+		host.Name = updates["name"].(string)
+	}
+	if req.Address != nil {
+		host.Address = updates["address"].(string)
+	}
+	if req.AgentURL != nil {
+		host.AgentURL = updates["agent_url"].(string)
+	}
+	if req.Region != nil {
+		host.Region = updates["region"].(string)
+	}
+	if req.MaxWorkers != nil {
+		host.MaxWorkers = *req.MaxWorkers
+	}
+	if req.Cordoned != nil {
+		host.Cordoned = *req.Cordoned
+	}
+	slog.Info("Host updated", "host_id", hostID, "cordoned", host.Cordoned, "region", host.Region, "max_workers", host.MaxWorkers)
+	m.hostMutex.Unlock()
+
+	return m.GetHost(hostID)
+}
+
+// DeleteHost 注销远程主机，主机上仍有账号时拒绝
+func (m *Manager) DeleteHost(hostID string) error {
+	if hostID == model.LocalHostID {
+		return fmt.Errorf("the master host cannot be removed")
+	}
+
+	m.hostMutex.Lock()
+	defer m.hostMutex.Unlock()
+
+	host, exists := m.hosts[hostID]
+	if !exists {
+		return fmt.Errorf("host %s not found", hostID)
+	}
+	if m.hostWorkersLocked(hostID) > 0 {
+		return ErrHostHasWorkers
+	}
+	if err := m.db.Delete(host).Error; err != nil {
+		return fmt.Errorf("failed to delete host: %v", err)
+	}
+	delete(m.hosts, hostID)
+	delete(m.hostFailures, hostID)
+
+	slog.Info("Host removed", "host_id", hostID)
+	return nil
+}
+
+// checkHostPin 校验创建账号时指定的主机存在且未离线
+func (m *Manager) checkHostPin(hostID string) error {
+	m.hostMutex.RLock()
+	defer m.hostMutex.RUnlock()
+
+	host, exists := m.hosts[hostID]
+	if !exists {
+		return fmt.Errorf("host %s not found", hostID)
+	}
+	if host.Status == model.HostStatusOffline {
+		return fmt.Errorf("host %s is offline", hostID)
+	}
+	return nil
+}
+
+// placeWorker 确定账号Worker运行的主机：已放置且主机未离线时保持不变，
+// 否则在在线、未停止调度且有空余容量的主机中选择负载最低的一台。
+// 账号设置了 proxy_region 时按 SCHEDULER_REGION_AFFINITY 优先或只选择出口地区相同的主机
+func (m *Manager) placeWorker(account *model.Account) (*model.Host, error) {
+	m.hostMutex.Lock()
+	defer m.hostMutex.Unlock()
+
+	if m.mockWorkers != nil {
+		// 模拟Worker运行在Master进程内，总是位于本机
+		account.HostID = model.LocalHostID
+		m.placements[account.ID] = model.LocalHostID
+		return m.hosts[model.LocalHostID], nil
+	}
+
+	if host, exists := m.hosts[account.HostID]; exists && host.Status != model.HostStatusOffline {
+		if m.placements[account.ID] == host.ID {
+			return host, nil
+		}
+		// 创建时指定的主机不受停止调度和地区限制，但不能超过容量
+		if host.MaxWorkers > 0 && m.hostWorkersLocked(host.ID) >= host.MaxWorkers {
+			return nil, fmt.Errorf("host %s reached its limit of %d workers", host.ID, host.MaxWorkers)
+		}
+		m.placements[account.ID] = host.ID
+		return host, nil
+	}
+
+	delete(m.placements, account.ID)
+	affinity := m.GetConfig().Scheduler.RegionAffinity
+	region := strings.TrimSpace(account.ProxyRegion)
+	if region == "" {
+		affinity = RegionAffinityOff
+	}
+
+	var best, bestInRegion *model.Host
+	var bestScore, bestInRegionScore float64
+	for _, host := range m.hosts {
+		if host.Status != model.HostStatusOnline || host.Cordoned {
+			continue
+		}
+		workers := m.hostWorkersLocked(host.ID)
+		if host.MaxWorkers > 0 && workers >= host.MaxWorkers {
+			continue
+		}
+		score := hostLoad(host, workers)
+		if best == nil || score < bestScore || (score == bestScore && host.ID < best.ID) {
+			best, bestScore = host, score
+		}
+		if affinity != RegionAffinityOff && strings.EqualFold(host.Region, region) &&
+			(bestInRegion == nil || score < bestInRegionScore || (score == bestInRegionScore && host.ID < bestInRegion.ID)) {
+			bestInRegion, bestInRegionScore = host, score
+		}
+	}
+
+	switch {
+	case bestInRegion != nil:
+		best = bestInRegion
+	case affinity == RegionAffinityRequire:
+		return nil, fmt.Errorf("no host available for new workers in region %s", region)
+	case best == nil:
+		return nil, fmt.Errorf("no host available for new workers")
+	case affinity == RegionAffinityPrefer:
+		slog.Warn("No host available in the account's proxy region, placing worker elsewhere",
+			"account_id", account.ID, "proxy_region", region, "host_id", best.ID, "host_region", best.Region)
+	}
+
+	if account.HostID != "" {
+		slog.Info("Moving worker to another host", "account_id", account.ID, "from", account.HostID, "to", best.ID)
+	}
+	account.HostID = best.ID
+	m.placements[account.ID] = best.ID
+	return best, nil
+}
+
+// hostLoad 主机负载，取账号容量、CPU负载和内存使用率中最高的一项，范围通常为0~1
+func hostLoad(host *model.Host, workers int) float64 {
+	load := 0.0
+	if host.MaxWorkers > 0 {
+		load = max(load, float64(workers)/float64(host.MaxWorkers))
+	}
+	if host.CPUs > 0 {
+		load = max(load, host.Load1/float64(host.CPUs))
+	}
+	if host.MemoryTotal > 0 {
+		load = max(load, 1-float64(host.MemoryAvailable)/float64(host.MemoryTotal))
+	}
+	return load
+}
+
+// releasePlacement 账号删除或迁移时释放其在主机上的位置
+func (m *Manager) releasePlacement(accountID string) {
+	m.hostMutex.Lock()
+	defer m.hostMutex.Unlock()
+	delete(m.placements, accountID)
+}
+
+// hostAddress Master访问主机上Worker映射端口使用的地址，本机返回空
+func (m *Manager) hostAddress(hostID string) string {
+	if hostID == "" || hostID == model.LocalHostID {
+		return ""
+	}
+	m.hostMutex.RLock()
+	defer m.hostMutex.RUnlock()
+	if host, exists := m.hosts[hostID]; exists {
+		return host.Address
+	}
+	return ""
+}
+
+// runDocker 在账号所在主机上执行docker命令并返回标准输出，本机直接执行，远程主机通过 fleet-agent 执行
+func (m *Manager) runDocker(hostID string, timeout time.Duration, args ...string) (string, error) {
+	return m.runDockerInput(hostID, timeout, nil, args...)
+}
+
+// runDockerInput 同 runDocker，stdin不为空时作为命令的标准输入，用于 docker run -i 向容器写入数据
+func (m *Manager) runDockerInput(hostID string, timeout time.Duration, stdin []byte, args ...string) (string, error) {
+	return m.dockerOn(hostID).run(timeout, stdin, args...)
+}
+
+// hostAgent 远程主机 fleet-agent 的地址和令牌
+func (m *Manager) hostAgent(hostID string) (string, string, error) {
+	m.hostMutex.RLock()
+	defer m.hostMutex.RUnlock()
+	host, exists := m.hosts[hostID]
+	if !exists {
+		return "", "", fmt.Errorf("host %s not found", hostID)
+	}
+	return host.AgentURL, string(host.Token), nil
+}
+
+// callAgent 调用 fleet-agent 接口
+func (m *Manager) callAgent(ctx context.Context, agentURL, token, method, path string, body, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %v", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, agentURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Agent-Token", token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("agent unreachable: %v", err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read agent response: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("agent returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(data)))
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return fmt.Errorf("failed to decode agent response: %v", err)
+	}
+	return nil
+}
+
+// StartHostMonitor 定期检查远程主机心跳，连续失败达到阈值后标记为离线并迁移其上的账号
+func (m *Manager) StartHostMonitor() {
+	cfg := m.GetConfig().Scheduler
+	if cfg.HeartbeatSeconds <= 0 {
+		return
+	}
+
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		ticker := time.NewTicker(time.Duration(cfg.HeartbeatSeconds) * time.Second)
+		defer ticker.Stop()
+
+		slog.Info("Host monitor started", "interval_seconds", cfg.HeartbeatSeconds, "failure_threshold", cfg.FailureThreshold)
+		m.checkHosts()
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				if !m.IsLeader() {
+					continue
+				}
+				m.checkHosts()
+			}
+		}
+	}()
+}
+
+// checkHosts 刷新本机资源并检查所有远程主机
+func (m *Manager) checkHosts() {
+	m.refreshLocalHost()
+
+	m.hostMutex.RLock()
+	ids := make([]string, 0, len(m.hosts))
+	for id := range m.hosts {
+		if id != model.LocalHostID {
+			ids = append(ids, id)
+		}
+	}
+	m.hostMutex.RUnlock()
+
+	for _, id := range ids {
+		if m.shuttingDown() {
+			return
+		}
+		m.checkHost(id)
+	}
+}
+
+// checkHost 请求 fleet-agent 的状态接口并更新主机状态
+func (m *Manager) checkHost(hostID string) {
+	m.hostMutex.RLock()
+	host, exists := m.hosts[hostID]
+	var agentURL, token string
+	if exists {
+		agentURL, token = host.AgentURL, string(host.Token)
+	}
+	m.hostMutex.RUnlock()
+	if !exists {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), agentStatusTimeout)
+	defer cancel()
+	var status model.AgentStatus
+	err := m.callAgent(ctx, agentURL, token, http.MethodGet, "/status", nil, &status)
+
+	m.hostMutex.Lock()
+	host, exists = m.hosts[hostID]
+	if !exists {
+		m.hostMutex.Unlock()
+		return
+	}
+	previous := host.Status
+	if err == nil {
+		now := time.Now()
+		host.CPUs = status.CPUs
+		host.MemoryTotal = status.MemoryTotal
+		host.MemoryAvailable = status.MemoryAvailable
+		host.Load1 = status.Load1
+		host.Containers = status.Containers
+		host.LastSeenAt = &now
+		host.LastError = ""
+		host.Status = model.HostStatusOnline
+		m.hostFailures[hostID] = 0
+	} else {
+		host.LastError = err.Error()
+		m.hostFailures[hostID]++
+		if m.hostFailures[hostID] >= max(m.GetConfig().Scheduler.FailureThreshold, 1) {
+			host.Status = model.HostStatusOffline
+		}
+	}
+	current := host.Status
+	failures := m.hostFailures[hostID]
+	m.hostMutex.Unlock()
+
+	if current == previous {
+		if err != nil && current != model.HostStatusOffline {
+			slog.Warn("Host heartbeat failed", "host_id", hostID, "failures", failures, "error", err)
+		}
+		return
+	}
+
+	switch current {
+	case model.HostStatusOnline:
+		slog.Info("Host online", "host_id", hostID, "previous_status", previous)
+		m.emit(EventHostOnline, "", map[string]interface{}{"host_id": hostID, "previous": previous})
+		if previous == model.HostStatusOffline {
+			// 离线期间账号可能已迁走，删除主机上残留的容器
+			m.removeStrayContainers(hostID)
+		}
+	case model.HostStatusOffline:
+		slog.Error("Host offline", "host_id", hostID, "failures", failures, "error", err)
+		m.emit(EventHostOffline, "", map[string]interface{}{"host_id": hostID, "error": err.Error()})
+		if m.GetConfig().Scheduler.RebalanceOnFailure {
+			if _, err := m.EvacuateHost(hostID, "host offline"); err != nil {
+				slog.Error("Failed to evacuate offline host", "host_id", hostID, "error", err)
+			}
+		}
+	}
+}
+
+// removeStrayContainers 删除主机上已不属于该主机的Worker容器
+func (m *Manager) removeStrayContainers(hostID string) {
+	containers, err := m.listWorkerContainers(hostID)
+	if err != nil {
+		slog.Warn("Failed to list containers on recovered host", "host_id", hostID, "error", err)
+		return
+	}
+
+	m.hostMutex.RLock()
+	stray := make([]string, 0)
+	for name := range containers {
+		if m.placements[strings.TrimPrefix(name, workerContainerPrefix)] != hostID {
+			stray = append(stray, name)
+		}
+	}
+	m.hostMutex.RUnlock()
+
+	for _, name := range stray {
+		if err := m.hostRuntime(hostID).Stop(name); err != nil {
+			slog.Warn("Failed to remove stray container", "host_id", hostID, "container", name, "error", err)
+			continue
+		}
+		slog.Info("Removed stray worker container", "host_id", hostID, "container", name)
+	}
+}
+
+// EvacuateHost 在后台任务中把主机上的账号迁移到其他主机，迁移前停止向该主机调度新账号。
+// 已停止的账号只解除放置，下次启动时重新调度
+func (m *Manager) EvacuateHost(hostID, reason string) (*model.Job, error) {
+	if hostID == model.LocalHostID && !m.GetConfig().Scheduler.LocalEnabled {
+		return nil, fmt.Errorf("the master host is not schedulable")
+	}
+
+	m.hostMutex.Lock()
+	host, exists := m.hosts[hostID]
+	if !exists {
+		m.hostMutex.Unlock()
+		return nil, fmt.Errorf("host %s not found", hostID)
+	}
+	accountIDs := make([]string, 0)
+	for accountID, placed := range m.placements {
+		if placed == hostID {
+			accountIDs = append(accountIDs, accountID)
+		}
+	}
+	if !host.Cordoned {
+		host.Cordoned = true
+		if hostID != model.LocalHostID {
+			m.db.Model(host).Update("cordoned", true)
+		}
+	}
+	m.hostMutex.Unlock()
+	sort.Strings(accountIDs)
+
+	slog.Info("Evacuating host", "host_id", hostID, "reason", reason, "accounts", len(accountIDs))
+	return m.startJob(JobEvacuateHost, fmt.Sprintf("evacuate %d workers from host %s", len(accountIDs), hostID), len(accountIDs), func(r *jobRun) error {
+		result := &model.EvacuateHostResult{HostID: hostID, Moved: []string{}, Unplaced: []string{}, Failed: []string{}}
+		defer r.SetResult(result)
+
+		for _, accountID := range accountIDs {
+			if r.Cancelled() || m.shuttingDown() {
+				break
+			}
+			m.evacuateAccount(hostID, accountID, reason, result)
+			r.Advance(1)
+		}
+		if len(result.Failed) > 0 {
+			return fmt.Errorf("failed to move %d workers: %s", len(result.Failed), strings.Join(result.Failed, ", "))
+		}
+		return nil
+	})
+}
+
+// evacuateAccount 把一个账号从主机上迁走，运行中的账号在新主机上重建Worker
+func (m *Manager) evacuateAccount(hostID, accountID, reason string, result *model.EvacuateHostResult) {
+	m.mutex.Lock()
+	account, exists := m.accounts[accountID]
+	if !exists || account.HostID != hostID {
+		m.mutex.Unlock()
+		return
+	}
+	account.HostID = ""
+	stopped := account.Status == "stopped"
+	m.db.Model(account).Update("host_id", "")
+	m.mutex.Unlock()
+	m.releasePlacement(accountID)
+
+	// 主机在线时（手动迁移）先保存会话再删除旧容器，离线主机上的容器在恢复时清理
+	m.sealBeforeRemove(hostID, accountID)
+	m.hostRuntime(hostID).Stop(workerContainerPrefix + accountID)
+
+	if stopped {
+		result.Unplaced = append(result.Unplaced, accountID)
+		return
+	}
+	if err := m.restartAccountWorker(account, fmt.Sprintf("evacuated from host %s: %s", hostID, reason)); err != nil {
+		slog.Error("Failed to move worker", "account_id", accountID, "host_id", hostID, "error", err)
+		result.Failed = append(result.Failed, accountID)
+		return
+	}
+	result.Moved = append(result.Moved, accountID)
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// idempotencyPendingTTL 首次请求处理中的占位记录有效期，Master在处理中崩溃时到期后允许重试
+const idempotencyPendingTTL = 10 * time.Minute
+
+var (
+	// ErrIdempotencyInProgress 相同幂等键的首次请求仍在处理
+	ErrIdempotencyInProgress = errors.New("a request with this idempotency key is still in progress")
+	// ErrIdempotencyMismatch 幂等键已用于不同的请求
+	ErrIdempotencyMismatch = errors.New("idempotency key was already used with a different request")
+)
+
+// IdempotencyEnabled 是否支持 Idempotency-Key
+func (m *Manager) IdempotencyEnabled() bool {
+	return m.GetConfig().Idempotency.TTLHours > 0
+}
+
+// BeginIdempotent 登记幂等键。键已有完成的响应时返回该记录供重放；
+// 返回nil表示调用方是首次请求，处理完成后需调用 FinishIdempotent 或 ReleaseIdempotent
+func (m *Manager) BeginIdempotent(key, requestHash string) (*model.IdempotencyKey, error) {
+	var replay *model.IdempotencyKey
+	err := m.db.Transaction(func(tx *gorm.DB) error {
+		var existing model.IdempotencyKey
+		err := tx.Where("idempotency_key = ?", key).First(&existing).Error
+		switch {
+		case err == nil && existing.ExpiresAt.Before(time.Now()):
+			if err := tx.Delete(&existing).Error; err != nil {
+				return err
+			}
+		case err == nil:
+			if existing.RequestHash != requestHash {
+				return ErrIdempotencyMismatch
+			}
+			if existing.Status == 0 {
+				return ErrIdempotencyInProgress
+			}
+			replay = &existing
+			return nil
+		case !errors.Is(err, gorm.ErrRecordNotFound):
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Create(&model.IdempotencyKey{
+			Key:         key,
+			RequestHash: requestHash,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(idempotencyPendingTTL),
+		}).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				// 并发的相同请求先写入了记录
+				return ErrIdempotencyInProgress
+			}
+			return err
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, ErrIdempotencyInProgress) && !errors.Is(err, ErrIdempotencyMismatch) {
+		return nil, fmt.Errorf("failed to check idempotency key: %v", err)
+	}
+	return replay, err
+}
+
+// FinishIdempotent 保存首次请求的响应，保留 IDEMPOTENCY_TTL_HOURS
+func (m *Manager) FinishIdempotent(key string, status int, response string) error {
+	ttl := time.Duration(m.GetConfig().Idempotency.TTLHours) * time.Hour
+	return m.db.Model(&model.IdempotencyKey{}).Where("idempotency_key = ?", key).Updates(map[string]interface{}{
+		"status":     status,
+		"response":   response,
+		"expires_at": time.Now().Add(ttl),
+	}).Error
+}
+
+// ReleaseIdempotent 删除幂等键，用于首次请求的结果不应被重放（如服务端错误或限流）时
+func (m *Manager) ReleaseIdempotent(key string) error {
+	return m.db.Where("idempotency_key = ?", key).Delete(&model.IdempotencyKey{}).Error
+}
+
+// cleanExpiredIdempotencyKeys 删除过期的幂等键
+func (m *Manager) cleanExpiredIdempotencyKeys(report *model.JanitorReport) {
+	db := m.db.Where("expires_at < ?", time.Now())
+	if report.DryRun {
+		if err := db.Model(&model.IdempotencyKey{}).Count(&report.IdempotencyKeys).Error; err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to count expired idempotency keys: %v", err))
+		}
+		return
+	}
+
+	result := db.Delete(&model.IdempotencyKey{})
+	if result.Error != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to delete expired idempotency keys: %v", result.Error))
+		return
+	}
+	report.IdempotencyKeys = result.RowsAffected
+}
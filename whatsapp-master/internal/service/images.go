@@ -0,0 +1,221 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/tracing"
+)
+
+// fleetImageLabel Worker镜像的标签，清理悬空镜像时只处理带该标签的镜像
+const fleetImageLabel = "whatsapp-fleet.image"
+
+// imagePullLayer docker pull 输出中的分层进度行，如 "3f4ca61aafcd: Pull complete"
+var imagePullLayer = regexp.MustCompile(`^([0-9a-f]{12}): (.+)$`)
+
+// pullImage 在主机上拉取Worker镜像，在任务中执行时按分层汇报下载进度
+func (m *Manager) pullImage(ctx context.Context, hostID, image string) (err error) {
+	ctx, span := tracing.Start(ctx, "docker pull", tracing.KindInternal, tracing.String("host.id", hostID), tracing.String("worker.image", image))
+	defer func() { span.Finish(err) }()
+
+	slog.Info("Pulling worker image", "image", image, "host_id", hostID)
+	ctx, cancel := context.WithTimeout(ctx, dockerPullTimeout)
+	defer cancel()
+
+	stream, err := m.dockerOn(hostID).stream(ctx, []string{"pull", image})
+	if err != nil {
+		return fmt.Errorf("failed to pull image %s: %v", image, err)
+	}
+	defer stream.Close()
+
+	onDetail := jobDetailReporter(ctx)
+	layers := make(map[string]bool)
+	done, reported := 0, -1
+	var lastLine string
+	scanner := bufio.NewScanner(stream)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		lastLine = line
+		match := imagePullLayer.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		status := match[2]
+		if _, seen := layers[match[1]]; !seen {
+			layers[match[1]] = false
+		}
+		if !layers[match[1]] && (status == "Pull complete" || status == "Already exists") {
+			layers[match[1]] = true
+			done++
+		}
+		if onDetail != nil && done != reported {
+			onDetail(fmt.Sprintf("%s on %s: %d/%d layers", image, hostLabel(hostID), done, len(layers)))
+			reported = done
+		}
+	}
+	scanErr := scanner.Err()
+
+	// 输出流结束不代表拉取成功（远程主机的流不返回退出码），以镜像是否存在为准
+	if _, err := m.runDocker(hostID, dockerTimeout, "image", "inspect", image); err != nil {
+		if scanErr != nil {
+			return fmt.Errorf("failed to pull image %s: %v", image, scanErr)
+		}
+		if ctx.Err() != nil {
+			return fmt.Errorf("failed to pull image %s: %v", image, ctx.Err())
+		}
+		return fmt.Errorf("failed to pull image %s: %s", image, lastLine)
+	}
+	slog.Info("Worker image pulled", "image", image, "host_id", hostID, "layers", len(layers))
+	return nil
+}
+
+// workerImage 当前使用的Worker镜像，滚动升级会修改它
+func (m *Manager) workerImage() string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return m.GetConfig().Worker.Image
+}
+
+// hostLabel 进度说明中显示的主机名，本机为 local
+func hostLabel(hostID string) string {
+	if hostID == "" {
+		return model.LocalHostID
+	}
+	return hostID
+}
+
+// PullImage 异步在主机上预拉取Worker镜像，未指定镜像时使用当前Worker镜像，未指定主机时拉取到所有在线主机
+func (m *Manager) PullImage(req *model.PullImageRequest) (*model.Job, error) {
+	image := strings.TrimSpace(req.Image)
+	if image == "" {
+		image = m.workerImage()
+	}
+
+	hostIDs := req.HostIDs
+	if len(hostIDs) == 0 {
+		hostIDs = m.reachableHosts()
+	} else {
+		m.hostMutex.RLock()
+		for _, id := range hostIDs {
+			if _, exists := m.hosts[id]; !exists {
+				m.hostMutex.RUnlock()
+				return nil, fmt.Errorf("host %s not found", id)
+			}
+		}
+		m.hostMutex.RUnlock()
+	}
+	if len(hostIDs) == 0 {
+		return nil, fmt.Errorf("no reachable hosts")
+	}
+
+	return m.startJob(JobPullImage, fmt.Sprintf("pull %s on %d hosts", image, len(hostIDs)), len(hostIDs), func(r *jobRun) error {
+		result := &model.PullImageResult{Image: image, Pulled: []string{}, Failed: map[string]string{}, ImagesRemoved: []string{}}
+		defer r.SetResult(result)
+
+		for _, hostID := range hostIDs {
+			if r.Cancelled() {
+				return nil
+			}
+			r.SetStage(fmt.Sprintf("%s on %s", SpawnStagePullingImage, hostID))
+			if err := m.pullImage(r.Context(), hostID, image); err != nil {
+				slog.Error("Failed to pre-pull worker image", "image", image, "host_id", hostID, "error", err)
+				result.Failed[hostID] = err.Error()
+				r.Advance(1)
+				continue
+			}
+			result.Pulled = append(result.Pulled, hostID)
+			if req.Prune {
+				removed, err := m.pruneWorkerImages(hostID, image, false)
+				if err != nil {
+					slog.Warn("Failed to prune worker images", "host_id", hostID, "error", err)
+				}
+				result.ImagesRemoved = append(result.ImagesRemoved, removed...)
+			}
+			r.Advance(1)
+		}
+		if len(result.Failed) > 0 {
+			return fmt.Errorf("failed to pull %s on %d of %d hosts", image, len(result.Failed), len(hostIDs))
+		}
+		return nil
+	})
+}
+
+// pruneWorkerImages 删除主机上同一仓库的旧版本Worker镜像（保留keep和最近的 Janitor.KeepImages 个版本）以及悬空的Worker镜像，
+// 仍被容器使用的镜像会被docker拒绝删除并跳过；返回删除的镜像（主机ID/镜像），dryRun时只列出不删除
+func (m *Manager) pruneWorkerImages(hostID, keep string, dryRun bool) ([]string, error) {
+	removed := []string{}
+	repo := imageRepository(keep)
+	output, err := m.runDocker(hostID, dockerTimeout, "images",
+		"--filter", "reference="+repo,
+		"--format", "{{.Repository}}:{{.Tag}}")
+	if err != nil {
+		return removed, fmt.Errorf("failed to list images on host %s: %v", hostID, err)
+	}
+
+	// docker images 按创建时间从新到旧输出
+	retained := 0
+	for _, ref := range strings.Split(strings.TrimSpace(output), "\n") {
+		ref = strings.TrimSpace(ref)
+		if ref == "" || ref == keep || strings.HasSuffix(ref, ":<none>") {
+			continue
+		}
+		if retained < m.GetConfig().Janitor.KeepImages {
+			retained++
+			continue
+		}
+		if dryRun {
+			removed = append(removed, hostLabel(hostID)+"/"+ref)
+			continue
+		}
+		if _, err := m.runDocker(hostID, dockerTimeout, "rmi", ref); err != nil {
+			if !strings.Contains(err.Error(), "conflict") {
+				slog.Warn("Failed to remove worker image", "host_id", hostID, "image", ref, "error", err)
+			}
+			continue
+		}
+		slog.Info("Removed old worker image", "host_id", hostID, "image", ref)
+		removed = append(removed, hostLabel(hostID)+"/"+ref)
+	}
+
+	output, err = m.runDocker(hostID, dockerTimeout, "images",
+		"--filter", "dangling=true",
+		"--filter", "label="+fleetImageLabel+"=true",
+		"--format", "{{.ID}}")
+	if err != nil {
+		return removed, fmt.Errorf("failed to list dangling images on host %s: %v", hostID, err)
+	}
+	for _, id := range strings.Fields(output) {
+		if dryRun {
+			removed = append(removed, hostLabel(hostID)+"/"+id)
+			continue
+		}
+		if _, err := m.runDocker(hostID, dockerTimeout, "rmi", id); err != nil {
+			if !strings.Contains(err.Error(), "conflict") {
+				slog.Warn("Failed to remove dangling worker image", "host_id", hostID, "image", id, "error", err)
+			}
+			continue
+		}
+		slog.Info("Removed dangling worker image", "host_id", hostID, "image", id)
+		removed = append(removed, hostLabel(hostID)+"/"+id)
+	}
+	return removed, nil
+}
+
+// imageRepository 去掉镜像引用中的标签和摘要，registry端口中的冒号保留
+func imageRepository(image string) string {
+	if i := strings.Index(image, "@"); i >= 0 {
+		image = image[:i]
+	}
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	return image
+}
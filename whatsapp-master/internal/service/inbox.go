@@ -0,0 +1,166 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// inboxColumns 收件箱允许过滤和排序的字段
+var inboxColumns = map[string]string{
+	"id":         "id",
+	"account_id": "account_id",
+	"contact":    "contact",
+	"type":       "type",
+	"timestamp":  "timestamp",
+	"created_at": "created_at",
+}
+
+// StartInboxCollector 定期从已登录账号的Worker拉取入站消息写入数据库
+func (m *Manager) StartInboxCollector() {
+	cfg := m.GetConfig().Inbox
+	if !cfg.CollectEnabled {
+		return
+	}
+
+	interval := time.Duration(cfg.CollectInterval) * time.Second
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	slog.Info("Inbox collector enabled", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				if !m.IsLeader() {
+					continue
+				}
+				m.collectInbox()
+			}
+		}
+	}()
+}
+
+// collectInbox 并发拉取所有已登录账号的入站消息
+func (m *Manager) collectInbox() {
+	m.mutex.RLock()
+	accountIDs := make([]string, 0)
+	for _, account := range m.accounts {
+		if account.Status == "logged_in" {
+			accountIDs = append(accountIDs, account.ID)
+		}
+	}
+	concurrency := m.GetConfig().Inbox.CollectConcurrency
+	m.mutex.RUnlock()
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	var added int
+	var addedMutex sync.Mutex
+	for _, accountID := range accountIDs {
+		if m.shuttingDown() {
+			break
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(accountID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+			defer cancel()
+			n, err := m.SyncMessages(ctx, accountID)
+			if err != nil {
+				// Worker不可达时由健康检查告警，这里只记录调试日志
+				slog.Debug("Failed to collect inbound messages", "account_id", accountID, "error", err)
+				return
+			}
+			addedMutex.Lock()
+			added += n
+			addedMutex.Unlock()
+		}(accountID)
+	}
+	wg.Wait()
+
+	if added > 0 {
+		slog.Info("Inbound messages collected", "messages", added, "accounts", len(accountIDs))
+	}
+}
+
+// messageSyncLock 同一账号的消息同步串行执行，避免收集任务和接口同时写入重复消息
+func (m *Manager) messageSyncLock(accountID string) *sync.Mutex {
+	lock, _ := m.messageSyncs.LoadOrStore(accountID, &sync.Mutex{})
+	return lock.(*sync.Mutex)
+}
+
+// ListInbox 跨账号查询已收集的入站消息
+func (m *Manager) ListInbox(filter *model.InboxFilter, q *model.ListQuery) ([]*model.Message, int64, error) {
+	db := m.db.Model(&model.Message{}).Where("direction = ?", "inbound")
+	if filter.AccountIDs != nil {
+		db = db.Where("account_id IN ?", filter.AccountIDs)
+	}
+	if contact := strings.TrimSpace(filter.Contact); contact != "" {
+		db = db.Where("contact LIKE ?", "%"+contact+"%")
+	}
+	if filter.Unread {
+		db = db.Where("read_at IS NULL")
+	}
+	if filter.Since != nil {
+		db = db.Where("timestamp >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		db = db.Where("timestamp < ?", *filter.Until)
+	}
+
+	messages := make([]*model.Message, 0)
+	total, err := findWithListQuery(db, q, inboxColumns, "-timestamp", &messages)
+	if err != nil {
+		return nil, 0, err
+	}
+	return messages, total, nil
+}
+
+// MarkInboxRead 标记入站消息为已读，accountIDs 不为nil时只标记这些账号的消息
+func (m *Manager) MarkInboxRead(req *model.MarkInboxReadRequest, accountIDs []string) (*model.MarkInboxReadResult, error) {
+	if len(req.MessageIDs) == 0 && req.AccountID == "" && req.Contact == "" && req.Until == nil {
+		return nil, fmt.Errorf("one of message_ids, account_id, contact or until is required")
+	}
+
+	db := m.db.Model(&model.Message{}).Where("direction = ? AND read_at IS NULL", "inbound")
+	if accountIDs != nil {
+		db = db.Where("account_id IN ?", accountIDs)
+	}
+	if len(req.MessageIDs) > 0 {
+		db = db.Where("id IN ?", req.MessageIDs)
+	}
+	if req.AccountID != "" {
+		db = db.Where("account_id = ?", req.AccountID)
+	}
+	if req.Contact != "" {
+		db = db.Where("contact = ?", req.Contact)
+	}
+	if req.Until != nil {
+		db = db.Where("timestamp < ?", *req.Until)
+	}
+
+	res := db.Update("read_at", time.Now())
+	if res.Error != nil {
+		return nil, fmt.Errorf("failed to mark messages read: %v", res.Error)
+	}
+	return &model.MarkInboxReadResult{Marked: res.RowsAffected}, nil
+}
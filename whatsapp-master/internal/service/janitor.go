@@ -0,0 +1,258 @@
+package service
+
+import (
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// Worker容器标签，用于识别本服务创建的容器
+const (
+	fleetManagedLabel = "whatsapp-fleet.managed"
+	fleetAccountLabel = "whatsapp-fleet.account"
+)
+
+// sessionDir 返回账号在宿主机上的会话目录
+func (m *Manager) sessionDir(accountID string) string {
+	return filepath.Join(m.GetConfig().Worker.SessionDir, accountID)
+}
+
+// StartJanitor 启动定期清理任务
+func (m *Manager) StartJanitor() {
+	cfg := m.GetConfig().Janitor
+	if !cfg.Enabled {
+		return
+	}
+
+	interval := time.Duration(cfg.Interval) * time.Minute
+	if interval <= 0 {
+		interval = 6 * time.Hour
+	}
+	slog.Info("Janitor enabled", "interval", interval, "retention_days", cfg.RetentionDays, "session_retention_days", cfg.SessionRetentionDays)
+
+	ticker := time.NewTicker(interval)
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				if !m.IsLeader() {
+					continue
+				}
+				m.startJanitorJob()
+			}
+		}
+	}()
+}
+
+// startJanitorJob 以后台任务执行一次定期清理
+func (m *Manager) startJanitorJob() {
+	_, err := m.startJob(JobJanitor, "scheduled janitor run", 0, func(r *jobRun) error {
+		report, err := m.RunJanitor(false)
+		if err != nil {
+			return err
+		}
+		r.SetResult(report)
+		if len(report.Errors) > 0 {
+			return fmt.Errorf("%d cleanup errors", len(report.Errors))
+		}
+		return nil
+	})
+	if err != nil {
+		slog.Info("Janitor skipped", "reason", err)
+	}
+}
+
+// RunJanitor 清理已退出的Worker容器、已删除账号和孤立的会话目录，dryRun时只统计不删除
+func (m *Manager) RunJanitor(dryRun bool) (*model.JanitorReport, error) {
+	if !m.janitorRun.TryLock() {
+		return nil, fmt.Errorf("janitor is already running")
+	}
+	defer m.janitorRun.Unlock()
+
+	report := &model.JanitorReport{
+		StartedAt:         time.Now(),
+		DryRun:            dryRun,
+		ContainersRemoved: make([]string, 0),
+		SessionsRemoved:   make([]string, 0),
+		BundlesRemoved:    make([]string, 0),
+		ImagesRemoved:     make([]string, 0),
+	}
+
+	m.cleanExitedContainers(report)
+	m.purgeDeletedSessions(report)
+	m.cleanStaleSessions(report)
+	m.cleanExpiredDiagnostics(report)
+	m.cleanExpiredMedia(report)
+	m.cleanExpiredAudit(report)
+	m.cleanWorkerCalls(report)
+	m.cleanExpiredIdempotencyKeys(report)
+	m.cleanResolvedDeadLetters(report)
+	if m.GetConfig().Janitor.ImageCleanup {
+		m.cleanWorkerImages(report)
+	}
+
+	finished := time.Now()
+	report.FinishedAt = &finished
+	slog.Info("Janitor finished", "dry_run", dryRun, "containers", len(report.ContainersRemoved), "sessions", len(report.SessionsRemoved),
+		"bundles", len(report.BundlesRemoved), "media_files", report.MediaRemoved, "images", len(report.ImagesRemoved), "audit_entries", report.AuditRemoved, "worker_calls", report.WorkerCalls, "idempotency_keys", report.IdempotencyKeys, "dead_letters", report.DeadLetters, "reclaimed_bytes", report.ReclaimedBytes)
+
+	if !dryRun {
+		m.janitorMutex.Lock()
+		m.janitorLast = report
+		m.janitorRuns++
+		m.janitorReclaimed += report.ReclaimedBytes
+		m.janitorMutex.Unlock()
+	}
+	return report, nil
+}
+
+// GetJanitorStatus 获取清理任务状态
+func (m *Manager) GetJanitorStatus() *model.JanitorStatus {
+	m.janitorMutex.Lock()
+	defer m.janitorMutex.Unlock()
+
+	cfg := m.GetConfig().Janitor
+	return &model.JanitorStatus{
+		Enabled:              cfg.Enabled,
+		IntervalMinutes:      cfg.Interval,
+		RetentionDays:        cfg.RetentionDays,
+		SessionRetentionDays: cfg.SessionRetentionDays,
+		Runs:                 m.janitorRuns,
+		TotalReclaimedBytes:  m.janitorReclaimed,
+		LastRun:              m.janitorLast,
+		LastReconcile:        m.reconcileLast,
+	}
+}
+
+// cleanExitedContainers 删除每台未离线主机上带有fleet标签且已退出的容器
+func (m *Manager) cleanExitedContainers(report *model.JanitorReport) {
+	for _, hostID := range m.reachableHosts() {
+		m.cleanExitedContainersOn(hostID, report)
+	}
+}
+
+// cleanExitedContainersOn 删除主机上带有fleet标签且已退出的容器
+func (m *Manager) cleanExitedContainersOn(hostID string, report *model.JanitorReport) {
+	runtime := m.hostRuntime(hostID)
+	containers, err := runtime.List(workerContainerPrefix)
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to list containers on host %s: %v", hostID, err))
+		return
+	}
+
+	for _, container := range containers {
+		if !container.Managed || container.State != "exited" {
+			continue
+		}
+		name := container.Name
+
+		// 容器可写层大小即删除后回收的空间
+		var size int64
+		if m.dockerAvailable(hostID) {
+			if raw, err := m.runDocker(hostID, dockerTimeout, "inspect", "--size", "--format", "{{.SizeRw}}", name); err == nil {
+				size, _ = strconv.ParseInt(strings.TrimSpace(raw), 10, 64)
+			}
+		}
+
+		if !report.DryRun {
+			if err := runtime.Stop(name); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("failed to remove container %s: %v", name, err))
+				continue
+			}
+		}
+		report.ContainersRemoved = append(report.ContainersRemoved, name)
+		report.ReclaimedBytes += size
+	}
+}
+
+// cleanWorkerImages 删除每台未离线主机上Worker镜像的旧版本和悬空镜像，当前镜像和最近的 JANITOR_KEEP_IMAGES 个版本保留用于回滚
+func (m *Manager) cleanWorkerImages(report *model.JanitorReport) {
+	image := m.workerImage()
+	for _, hostID := range m.reachableHosts() {
+		if !m.dockerAvailable(hostID) {
+			continue
+		}
+		removed, err := m.pruneWorkerImages(hostID, image, report.DryRun)
+		if err != nil {
+			report.Errors = append(report.Errors, err.Error())
+		}
+		report.ImagesRemoved = append(report.ImagesRemoved, removed...)
+	}
+}
+
+// cleanStaleSessions 删除本机上没有对应账号记录且长期未修改的会话目录和加密归档，已删除账号的会话由 purgeDeletedSessions 清除
+func (m *Manager) cleanStaleSessions(report *model.JanitorReport) {
+	entries, err := os.ReadDir(m.GetConfig().Worker.SessionDir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to read session dir: %v", err))
+		}
+		return
+	}
+
+	cutoff := time.Now().AddDate(0, 0, -m.GetConfig().Janitor.RetentionDays)
+	for _, entry := range entries {
+		// 开启会话加密后会话以 <账号ID>.enc 文件保存
+		accountID, sealed := strings.CutSuffix(entry.Name(), sealedSessionSuffix)
+		if entry.IsDir() == sealed || accountID == "" {
+			continue
+		}
+		if !m.sessionExpired(accountID, entry, cutoff) {
+			continue
+		}
+
+		path := filepath.Join(m.GetConfig().Worker.SessionDir, entry.Name())
+		size := dirSize(path)
+		if !report.DryRun {
+			if err := os.RemoveAll(path); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("failed to remove session %s: %v", accountID, err))
+				continue
+			}
+		}
+		report.SessionsRemoved = append(report.SessionsRemoved, accountID)
+		report.ReclaimedBytes += size
+	}
+}
+
+// sessionExpired 判断会话目录是否可以清理
+func (m *Manager) sessionExpired(accountID string, entry fs.DirEntry, cutoff time.Time) bool {
+	if _, err := m.GetAccount(accountID); err == nil {
+		return false
+	}
+
+	var account model.Account
+	if err := m.db.Unscoped().Where("id = ?", accountID).First(&account).Error; err == nil {
+		return false
+	}
+
+	// 没有对应账号记录的孤立目录，按最后修改时间判断
+	info, err := entry.Info()
+	return err == nil && info.ModTime().Before(cutoff)
+}
+
+// dirSize 统计目录占用的字节数
+func dirSize(path string) int64 {
+	var size int64
+	filepath.WalkDir(path, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && !d.IsDir() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// 任务状态
+const (
+	JobRunning     = "running"
+	JobSucceeded   = "succeeded"
+	JobFailed      = "failed"
+	JobCancelled   = "cancelled"
+	JobInterrupted = "interrupted" // 服务关闭或重启导致未完成
+)
+
+// 任务类型
+const (
+	JobRestartWorkers = "restart_workers"
+	JobRestartAccount = "restart_account"
+	JobBulkSend       = "bulk_send"
+	JobMessageRetry   = "message_retry"
+	JobCampaign       = "campaign"
+	JobJanitor        = "janitor"
+	JobCreateAccount  = "create_account"
+	JobCreateAccounts = "create_accounts"
+	JobUpgradeWorkers = "upgrade_workers"
+	JobPullImage      = "pull_image"
+	JobBackup         = "backup"
+)
+
+// jobRunKey 上下文中保存当前任务的键，同步流程通过它汇报执行阶段
+type jobRunKey struct{}
+
+// jobColumns 任务列表允许过滤和排序的字段
+var jobColumns = map[string]string{
+	"id":          "id",
+	"type":        "type",
+	"status":      "status",
+	"created_at":  "created_at",
+	"finished_at": "finished_at",
+}
+
+// jobRun 任务执行上下文，异步操作通过它汇报进度和结果
+type jobRun struct {
+	m   *Manager
+	job *model.Job
+	ctx context.Context
+}
+
+// Context 任务被取消时结束的上下文
+func (r *jobRun) Context() context.Context {
+	return r.ctx
+}
+
+// Cancelled 任务是否已被取消
+func (r *jobRun) Cancelled() bool {
+	return r.ctx.Err() != nil
+}
+
+// SetTotal 设置工作项总数
+func (r *jobRun) SetTotal(total int) {
+	r.m.jobMutex.Lock()
+	defer r.m.jobMutex.Unlock()
+
+	r.job.Total = total
+	r.m.db.Model(r.job).UpdateColumns(map[string]interface{}{"total": total, "updated_at": time.Now()})
+}
+
+// Advance 完成n个工作项
+func (r *jobRun) Advance(n int) {
+	r.m.jobMutex.Lock()
+	defer r.m.jobMutex.Unlock()
+
+	r.job.Progress += n
+	r.m.db.Model(r.job).UpdateColumns(map[string]interface{}{"progress": r.job.Progress, "updated_at": time.Now()})
+}
+
+// SetStage 记录任务当前执行阶段，并清除上一阶段的进度说明
+func (r *jobRun) SetStage(stage string) {
+	r.m.jobMutex.Lock()
+	defer r.m.jobMutex.Unlock()
+
+	r.job.Stage = stage
+	r.job.Detail = ""
+	r.m.db.Model(r.job).UpdateColumns(map[string]interface{}{"stage": stage, "detail": "", "updated_at": time.Now()})
+}
+
+// SetDetail 记录当前阶段的进度说明
+func (r *jobRun) SetDetail(detail string) {
+	r.m.jobMutex.Lock()
+	defer r.m.jobMutex.Unlock()
+
+	r.job.Detail = detail
+	r.m.db.Model(r.job).UpdateColumns(map[string]interface{}{"detail": detail, "updated_at": time.Now()})
+}
+
+// jobStageReporter 返回ctx所属任务的阶段回调，不在任务中执行时返回nil
+func jobStageReporter(ctx context.Context) func(string) {
+	if r, ok := ctx.Value(jobRunKey{}).(*jobRun); ok {
+		return r.SetStage
+	}
+	return nil
+}
+
+// jobDetailReporter 返回ctx所属任务的进度说明回调，不在任务中执行时返回nil
+func jobDetailReporter(ctx context.Context) func(string) {
+	if r, ok := ctx.Value(jobRunKey{}).(*jobRun); ok {
+		return r.SetDetail
+	}
+	return nil
+}
+
+// SetResult 记录任务结果，结束时随状态一起保存
+func (r *jobRun) SetResult(result interface{}) {
+	b, err := json.Marshal(result)
+	if err != nil {
+		return
+	}
+
+	r.m.jobMutex.Lock()
+	defer r.m.jobMutex.Unlock()
+	r.job.Result = model.RawJSON(b)
+}
+
+// startJob 创建任务记录并在后台执行run，run返回后根据错误、取消和关闭状态确定任务结果
+func (m *Manager) startJob(jobType, description string, total int, run func(*jobRun) error) (*model.Job, error) {
+	return m.startTenantJob("", jobType, description, total, run)
+}
+
+// startTenantJob 创建属于租户的任务，租户可以查询自己的任务
+func (m *Manager) startTenantJob(tenantID, jobType, description string, total int, run func(*jobRun) error) (*model.Job, error) {
+	job := &model.Job{
+		ID:          generateID("job"),
+		Type:        jobType,
+		Status:      JobRunning,
+		Description: description,
+		Total:       total,
+		TenantID:    tenantID,
+	}
+	if err := m.db.Create(job).Error; err != nil {
+		return nil, fmt.Errorf("failed to create job: %v", err)
+	}
+	snapshot := *job
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.jobMutex.Lock()
+	m.jobCancels[job.ID] = cancel
+	m.jobMutex.Unlock()
+
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer cancel()
+
+		r := &jobRun{m: m, job: job}
+		r.ctx = context.WithValue(ctx, jobRunKey{}, r)
+		m.finishJob(r, run(r))
+	}()
+
+	return &snapshot, nil
+}
+
+// finishJob 保存任务的最终状态
+func (m *Manager) finishJob(r *jobRun, err error) {
+	m.jobMutex.Lock()
+	defer m.jobMutex.Unlock()
+
+	delete(m.jobCancels, r.job.ID)
+
+	job := r.job
+	incomplete := job.Total > 0 && job.Progress < job.Total
+	switch {
+	case r.Cancelled() && !m.IsLeader():
+		// 失去Leader身份时取消的任务，由新Leader接管
+		job.Status = JobInterrupted
+	case r.Cancelled():
+		job.Status = JobCancelled
+	case m.shuttingDown() && (err != nil || incomplete):
+		job.Status = JobInterrupted
+	case err != nil:
+		job.Status = JobFailed
+	default:
+		job.Status = JobSucceeded
+	}
+	if err != nil {
+		job.Error = err.Error()
+	}
+
+	now := time.Now()
+	job.FinishedAt = &now
+	if saveErr := m.db.Save(job).Error; saveErr != nil {
+		slog.Error("Failed to save job", "job_id", job.ID, "error", saveErr)
+	}
+
+	slog.Info("Job finished", "job_id", job.ID, "type", job.Type, "status", job.Status, "progress", job.Progress, "total", job.Total)
+	m.emit(EventJobFinished, "", map[string]interface{}{
+		"job_id": job.ID,
+		"type":   job.Type,
+		"status": job.Status,
+		"error":  job.Error,
+	})
+}
+
+// GetJob 获取任务
+func (m *Manager) GetJob(jobID string) (*model.Job, error) {
+	var job model.Job
+	if err := m.db.Where("id = ?", jobID).First(&job).Error; err != nil {
+		return nil, fmt.Errorf("job %s not found", jobID)
+	}
+	return &job, nil
+}
+
+// JobInTenant 任务是否属于租户
+func (m *Manager) JobInTenant(jobID, tenantID string) bool {
+	job, err := m.GetJob(jobID)
+	return err == nil && job.TenantID == tenantID
+}
+
+// ListJobs 分页查询任务
+func (m *Manager) ListJobs(q *model.ListQuery) ([]*model.Job, int64, error) {
+	jobs := make([]*model.Job, 0)
+	total, err := findWithListQuery(m.db.Model(&model.Job{}), q, jobColumns, "-created_at", &jobs)
+	if err != nil {
+		return nil, 0, err
+	}
+	return jobs, total, nil
+}
+
+// CancelJob 请求取消运行中的任务，正在处理的工作项完成后任务结束
+func (m *Manager) CancelJob(jobID string) (*model.Job, error) {
+	job, err := m.GetJob(jobID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.jobMutex.Lock()
+	cancel, running := m.jobCancels[jobID]
+	m.jobMutex.Unlock()
+	if !running {
+		return nil, fmt.Errorf("job %s is %s", jobID, job.Status)
+	}
+
+	cancel()
+	slog.Info("Job cancellation requested", "job_id", jobID, "type", job.Type)
+	return job, nil
+}
+
+// markInterruptedJobs 成为Leader时将上次未结束的任务标记为中断
+func (m *Manager) markInterruptedJobs() {
+	now := time.Now()
+	res := m.db.Model(&model.Job{}).
+		Where("status = ?", JobRunning).
+		Updates(map[string]interface{}{"status": JobInterrupted, "error": "interrupted by restart", "finished_at": &now})
+	if res.RowsAffected > 0 {
+		slog.Warn("Marked unfinished jobs as interrupted", "count", res.RowsAffected)
+	}
+}
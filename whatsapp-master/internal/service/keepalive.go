@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// keepAliveConcurrency 保活任务同时检查的Worker数量
+const keepAliveConcurrency = 8
+
+// StartSessionKeepAlive 定期检查已登录Worker的登录状态，Worker报告掉线或会话即将过期时自动刷新会话
+func (m *Manager) StartSessionKeepAlive() {
+	if m.GetConfig().Session.KeepAliveInterval <= 0 {
+		return
+	}
+
+	interval := time.Duration(m.GetConfig().Session.KeepAliveInterval) * time.Second
+	slog.Info("Session keep-alive enabled", "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				if !m.IsLeader() {
+					continue
+				}
+				m.keepAliveSessions()
+			}
+		}
+	}()
+}
+
+// keepAliveSessions 检查所有参与保活的已登录或已掉线账号，掉线账号的Worker仍在运行，可从缓存会话恢复
+func (m *Manager) keepAliveSessions() {
+	m.mutex.RLock()
+	accounts := make([]*model.Account, 0)
+	for _, account := range m.accounts {
+		if (account.Status == "logged_in" || account.Status == "disconnected") && !account.KeepAliveOff {
+			accounts = append(accounts, account)
+		}
+	}
+	m.mutex.RUnlock()
+
+	sem := make(chan struct{}, keepAliveConcurrency)
+	var wg sync.WaitGroup
+	for _, account := range accounts {
+		if m.shuttingDown() {
+			break
+		}
+		sem <- struct{}{}
+		wg.Add(1)
+		go func(account *model.Account) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			m.keepAliveSession(account)
+		}(account)
+	}
+	wg.Wait()
+}
+
+// keepAliveSession 查询Worker登录状态，需要时刷新会话；Worker不可达时交给状态轮询和自动恢复处理
+func (m *Manager) keepAliveSession(account *model.Account) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	status, err := m.FetchFromWorker(ctx, account.ID, "/api/login/status")
+	cancel()
+	if err != nil {
+		slog.Debug("Keep-alive status check failed", "account_id", account.ID, "error", err)
+		return
+	}
+
+	now := time.Now()
+	cooldown := time.Duration(m.GetConfig().Session.KeepAliveRefreshCooldown) * time.Minute
+	workerStatus, _ := status["status"].(string)
+
+	m.mutex.RLock()
+	refreshedAt := account.SessionRefreshAt
+	health := m.predictSession(account, now)
+	m.mutex.RUnlock()
+
+	if refreshedAt != nil && now.Sub(*refreshedAt) < cooldown {
+		return
+	}
+
+	var reason string
+	switch {
+	case workerStatus == "disconnected":
+		reason = "worker reported disconnected"
+	case health.ReloginRecommended && (refreshedAt == nil || now.Sub(*refreshedAt) >= sessionRefreshCooldown):
+		reason = health.Reason
+	default:
+		return
+	}
+
+	if err := m.refreshSession(account, "keepalive", reason); err != nil {
+		slog.Warn("Keep-alive session refresh failed", "account_id", account.ID, "reason", reason, "error", err)
+		return
+	}
+	slog.Info("Keep-alive refreshed session", "account_id", account.ID, "reason", reason)
+}
+
+// SetAccountKeepAlive 开启或关闭账号的会话保活，关闭后也不参与静默时段的主动刷新
+func (m *Manager) SetAccountKeepAlive(accountID string, enabled bool) (*model.Account, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return nil, fmt.Errorf("account %s not found", accountID)
+	}
+	if err := m.db.Model(account).Update("keep_alive_off", !enabled).Error; err != nil {
+		return nil, fmt.Errorf("failed to update account keep-alive: %v", err)
+	}
+	account.KeepAliveOff = !enabled
+	return account.Clone(), nil
+}
@@ -0,0 +1,354 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// Leader选举方式
+const (
+	LeaderElectionOff = "off"
+	LeaderElectionDB  = "db"
+)
+
+// leaderLeaseName Master副本竞选的租约名称
+const leaderLeaseName = "master"
+
+// ErrNoLeader 当前没有有效的Leader，Follower无法转发写请求
+var ErrNoLeader = errors.New("no leader elected")
+
+// validateLeaderConfig 校验选举配置
+func validateLeaderConfig(election string, leaseSeconds, renewSeconds int) error {
+	switch election {
+	case LeaderElectionOff:
+		return nil
+	case LeaderElectionDB:
+	default:
+		return fmt.Errorf("invalid LEADER_ELECTION %q: must be off or db", election)
+	}
+	if renewSeconds <= 0 || leaseSeconds <= renewSeconds {
+		return fmt.Errorf("LEADER_RENEW_SECONDS (%d) must be positive and less than LEADER_LEASE_SECONDS (%d)", renewSeconds, leaseSeconds)
+	}
+	return nil
+}
+
+// defaultReplicaID 未配置 REPLICA_ID 时使用主机名，Kubernetes中即Pod名
+func defaultReplicaID() string {
+	if hostname, err := os.Hostname(); err == nil && hostname != "" {
+		return hostname
+	}
+	return generateID("replica")
+}
+
+// replicaAdvertiseURL 其他副本转发写请求到本副本的地址
+func (m *Manager) replicaAdvertiseURL() string {
+	if m.GetConfig().Leader.AdvertiseURL != "" {
+		return m.GetConfig().Leader.AdvertiseURL
+	}
+	return fmt.Sprintf("http://%s:%d", m.replicaID, m.GetConfig().Server.Port)
+}
+
+// IsLeader 本副本是否为Leader，只有Leader启动Worker和执行轮询、监督等后台任务
+func (m *Manager) IsLeader() bool {
+	m.leaderMutex.RLock()
+	defer m.leaderMutex.RUnlock()
+	return m.leader
+}
+
+// ReplicaID 本副本的标识
+func (m *Manager) ReplicaID() string {
+	return m.replicaID
+}
+
+// LeaderURL Follower转发写请求的目标地址，没有有效的Leader时返回 ErrNoLeader
+func (m *Manager) LeaderURL() (string, error) {
+	m.leaderMutex.RLock()
+	defer m.leaderMutex.RUnlock()
+	lease := m.leaderLease
+	if lease == nil || lease.HolderID == "" || lease.HolderURL == "" || time.Now().After(lease.ExpiresAt) {
+		return "", ErrNoLeader
+	}
+	return lease.HolderURL, nil
+}
+
+// LeaderStatus 本副本的选举状态
+func (m *Manager) LeaderStatus() *model.LeaderStatus {
+	m.leaderMutex.RLock()
+	defer m.leaderMutex.RUnlock()
+
+	status := &model.LeaderStatus{
+		Election:    m.GetConfig().Leader.Election,
+		ReplicaID:   m.replicaID,
+		IsLeader:    m.leader,
+		LeaderSince: m.leaderSince,
+		LastSyncAt:  m.leaderSyncAt,
+	}
+	if m.GetConfig().Leader.Election != LeaderElectionDB {
+		status.LeaderID = m.replicaID
+		return status
+	}
+	if lease := m.leaderLease; lease != nil && lease.HolderID != "" {
+		expiresAt := lease.ExpiresAt
+		status.LeaderID = lease.HolderID
+		status.LeaderURL = lease.HolderURL
+		status.LeaseExpiresAt = &expiresAt
+	}
+	return status
+}
+
+// StartLeaderElection 开始Leader选举：LEADER_ELECTION=off 时本实例直接成为Leader；
+// db 时立即竞选一次，之后定期续约或竞选数据库租约，Follower同时从数据库同步账号。
+// 成为Leader时执行启动对账并继续之前的活动
+func (m *Manager) StartLeaderElection() {
+	cfg := m.GetConfig().Leader
+	if cfg.Election != LeaderElectionDB {
+		m.becomeLeader(nil)
+		return
+	}
+	slog.Info("Leader election enabled", "replica_id", m.replicaID, "advertise_url", m.replicaAdvertiseURL(),
+		"lease_seconds", cfg.LeaseSeconds, "renew_seconds", cfg.RenewSeconds)
+	m.campaignLeadership()
+
+	ticker := time.NewTicker(time.Duration(cfg.RenewSeconds) * time.Second)
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stopCh:
+				m.releaseLeadership()
+				return
+			case <-ticker.C:
+				m.campaignLeadership()
+			}
+		}
+	}()
+}
+
+// campaignLeadership 续约或竞选租约，并根据结果切换角色；Follower每次都从数据库同步状态
+func (m *Manager) campaignLeadership() {
+	now := time.Now()
+	lease, err := m.acquireLease(now, time.Duration(m.GetConfig().Leader.LeaseSeconds)*time.Second)
+	if err != nil {
+		slog.Warn("Leader lease renewal failed", "replica_id", m.replicaID, "error", err)
+		// 数据库不可用时租约仍在有效期内则保持Leader，过期后其他副本可能已接管
+		m.leaderMutex.RLock()
+		expired := m.leader && (m.leaderLease == nil || now.After(m.leaderLease.ExpiresAt))
+		m.leaderMutex.RUnlock()
+		if expired {
+			m.stepDown("lease expired without renewal")
+		}
+		return
+	}
+
+	if lease.HolderID == m.replicaID {
+		if !m.IsLeader() {
+			m.becomeLeader(lease)
+			return
+		}
+		m.leaderMutex.Lock()
+		m.leaderLease = lease
+		m.leaderMutex.Unlock()
+		return
+	}
+
+	m.leaderMutex.Lock()
+	m.leaderLease = lease
+	m.leaderMutex.Unlock()
+	if m.IsLeader() {
+		m.stepDown("lease taken by " + lease.HolderID)
+	}
+	if err := m.syncFromDatabase(); err != nil {
+		slog.Warn("Follower sync failed", "replica_id", m.replicaID, "error", err)
+		return
+	}
+	m.leaderMutex.Lock()
+	m.leaderSyncAt = &now
+	m.leaderMutex.Unlock()
+}
+
+// acquireLease 租约由本副本持有或已过期时更新为本副本持有，返回更新后的租约。
+// 以条件更新实现互斥，各副本的时钟需同步
+func (m *Manager) acquireLease(now time.Time, ttl time.Duration) (*model.LeaderLease, error) {
+	res := m.db.Model(&model.LeaderLease{}).
+		Where("name = ? AND (holder_id = ? OR expires_at < ?)", leaderLeaseName, m.replicaID, now).
+		Updates(map[string]interface{}{
+			// 按字段名排序执行，acquired_at 在 holder_id 修改前求值
+			"acquired_at": gorm.Expr("CASE WHEN holder_id = ? THEN acquired_at ELSE ? END", m.replicaID, now),
+			"holder_id":   m.replicaID,
+			"holder_url":  m.replicaAdvertiseURL(),
+			"expires_at":  now.Add(ttl),
+		})
+	if res.Error != nil {
+		return nil, res.Error
+	}
+
+	var lease model.LeaderLease
+	err := m.db.Where("name = ?", leaderLeaseName).First(&lease).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		// 第一次竞选时创建租约，多个副本同时创建时只有一个成功
+		lease = model.LeaderLease{Name: leaderLeaseName, HolderID: m.replicaID, HolderURL: m.replicaAdvertiseURL(), AcquiredAt: now, ExpiresAt: now.Add(ttl)}
+		if err := m.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&lease).Error; err != nil {
+			return nil, err
+		}
+		err = m.db.Where("name = ?", leaderLeaseName).First(&lease).Error
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &lease, nil
+}
+
+// becomeLeader 成为Leader：从数据库同步Follower期间的变化，标记上任Leader未完成的任务，
+// 按配置对账Worker并继续之前的活动。lease为nil表示未开启选举
+func (m *Manager) becomeLeader(lease *model.LeaderLease) {
+	if lease != nil {
+		if err := m.syncFromDatabase(); err != nil {
+			slog.Warn("Failed to sync state before taking leadership", "replica_id", m.replicaID, "error", err)
+		}
+		if err := m.syncHosts(); err != nil {
+			slog.Warn("Failed to reload hosts before taking leadership", "replica_id", m.replicaID, "error", err)
+		}
+	}
+
+	now := time.Now()
+	m.leaderMutex.Lock()
+	m.leader = true
+	m.leaderSince = &now
+	m.leaderLease = lease
+	m.leaderMutex.Unlock()
+	if lease != nil {
+		slog.Info("Became leader", "replica_id", m.replicaID)
+		m.emit(EventLeaderElected, "", map[string]string{"replica_id": m.replicaID, "url": lease.HolderURL})
+	}
+
+	m.markInterruptedJobs()
+	if m.GetConfig().Janitor.ReconcileOnStartup {
+		if _, err := m.ReconcileWorkers(); err != nil {
+			slog.Warn("Worker reconciliation skipped", "error", err)
+		}
+	}
+	m.ResumeCampaigns()
+}
+
+// stepDown 失去Leader身份：后台任务在下一次检查时停止，运行中的活动被取消并保持running，由新Leader继续
+func (m *Manager) stepDown(reason string) {
+	m.leaderMutex.Lock()
+	m.leader = false
+	m.leaderSince = nil
+	m.leaderMutex.Unlock()
+	slog.Warn("Lost leadership", "replica_id", m.replicaID, "reason", reason)
+	m.emit(EventLeaderLost, "", map[string]string{"replica_id": m.replicaID, "reason": reason})
+
+	m.campaignMutex.Lock()
+	jobIDs := make([]string, 0, len(m.campaignRuns))
+	for _, jobID := range m.campaignRuns {
+		jobIDs = append(jobIDs, jobID)
+	}
+	m.campaignMutex.Unlock()
+
+	m.jobMutex.Lock()
+	for _, jobID := range jobIDs {
+		if cancel, running := m.jobCancels[jobID]; running {
+			cancel()
+		}
+	}
+	m.jobMutex.Unlock()
+}
+
+// releaseLeadership 关闭时让出租约，其他副本在下一次竞选时即可接管
+func (m *Manager) releaseLeadership() {
+	if !m.IsLeader() {
+		return
+	}
+	err := m.db.Model(&model.LeaderLease{}).
+		Where("name = ? AND holder_id = ?", leaderLeaseName, m.replicaID).
+		Update("expires_at", time.Now()).Error
+	if err != nil {
+		slog.Warn("Failed to release leader lease", "replica_id", m.replicaID, "error", err)
+		return
+	}
+	slog.Info("Leader lease released", "replica_id", m.replicaID)
+}
+
+// syncFromDatabase 以数据库为准刷新内存中的账号、Webhook和告警路由。
+// Follower不修改这些数据，写请求由Leader处理，Follower据此跟随
+func (m *Manager) syncFromDatabase() error {
+	var accounts []*model.Account
+	if err := m.db.Find(&accounts).Error; err != nil {
+		return fmt.Errorf("failed to load accounts: %v", err)
+	}
+
+	m.mutex.Lock()
+	current := make(map[string]bool, len(accounts))
+	for _, account := range accounts {
+		current[account.ID] = true
+		existing, exists := m.accounts[account.ID]
+		if !exists {
+			m.accounts[account.ID] = account
+			m.portPool.Reserve(account.Port)
+			continue
+		}
+		if existing.Port != account.Port {
+			m.portPool.Release(existing.Port)
+			m.portPool.Reserve(account.Port)
+		}
+		// 原地更新，持有账号指针的请求看到最新状态
+		*existing = *account
+	}
+	for accountID, account := range m.accounts {
+		if !current[accountID] {
+			m.portPool.Release(account.Port)
+			delete(m.accounts, accountID)
+		}
+	}
+	m.mutex.Unlock()
+
+	if err := m.loadWebhooks(); err != nil {
+		return fmt.Errorf("failed to load webhooks: %v", err)
+	}
+	if err := m.loadAlertRouting(); err != nil {
+		return fmt.Errorf("failed to load alert routing: %v", err)
+	}
+	return nil
+}
+
+// syncHosts 重新加载远程主机和账号所在主机，本机保持不变，主机状态由下一次心跳确定
+func (m *Manager) syncHosts() error {
+	var hosts []*model.Host
+	if err := m.db.Find(&hosts).Error; err != nil {
+		return err
+	}
+
+	m.mutex.RLock()
+	placements := make(map[string]string, len(m.accounts))
+	for _, account := range m.accounts {
+		placements[account.ID] = valueOrDefault(account.HostID, model.LocalHostID)
+	}
+	m.mutex.RUnlock()
+
+	m.hostMutex.Lock()
+	defer m.hostMutex.Unlock()
+	loaded := map[string]*model.Host{model.LocalHostID: m.hosts[model.LocalHostID]}
+	for _, host := range hosts {
+		if existing, exists := m.hosts[host.ID]; exists {
+			host.Status = existing.Status
+			host.LastSeenAt = existing.LastSeenAt
+		} else {
+			host.Status = model.HostStatusUnknown
+		}
+		loaded[host.ID] = host
+	}
+	m.hosts = loaded
+	m.placements = placements
+	return nil
+}
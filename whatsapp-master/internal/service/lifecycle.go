@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// Shutdown 优雅关闭管理器：停止状态轮询和批量发送，等待后台任务完成，
+// 按配置停止或保留Worker，最后关闭数据库。ctx到期后不再等待后台任务。
+// Worker属于Leader，Follower只释放选举状态，不停止任何Worker
+func (m *Manager) Shutdown(ctx context.Context) error {
+	// 关闭 stopCh 后选举协程会释放租约，先记录关闭前的角色
+	leader := m.IsLeader()
+	m.stopOnce.Do(func() {
+		close(m.stopCh)
+	})
+	m.events.Close()
+
+	// 等待后台任务退出
+	done := make(chan struct{})
+	go func() {
+		m.background.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		slog.Info("Background tasks drained")
+	case <-ctx.Done():
+		slog.Warn("Drain timeout reached, abandoning background tasks", "error", ctx.Err())
+	}
+
+	switch {
+	case !leader:
+		slog.Info("Follower shutting down, leaving workers to the leader")
+	case m.GetConfig().Worker.StopOnShutdown:
+		m.stopAllWorkers()
+	default:
+		slog.Info("Leaving workers running (WORKER_STOP_ON_SHUTDOWN=false)")
+	}
+
+	return m.Close()
+}
+
+// shuttingDown 是否正在关闭
+func (m *Manager) shuttingDown() bool {
+	select {
+	case <-m.stopCh:
+		return true
+	default:
+		return false
+	}
+}
+
+// stopAllWorkers 停止所有运行中的Worker，并将账号标记为stopped
+func (m *Manager) stopAllWorkers() {
+	m.mutex.RLock()
+	accounts := make([]*model.Account, 0, len(m.accounts))
+	for _, acc := range m.accounts {
+		if acc.Status != "stopped" && acc.Status != "error" {
+			accounts = append(accounts, acc)
+		}
+	}
+	m.mutex.RUnlock()
+
+	for _, acc := range accounts {
+		slog.Info("Stopping worker", "account_id", acc.ID)
+		m.gracefulStop(acc)
+
+		containerName := fmt.Sprintf("whatsapp-worker-%s", acc.ID)
+		m.sealBeforeRemove(acc.HostID, acc.ID)
+		m.hostRuntime(acc.HostID).Stop(containerName)
+
+		m.mutex.Lock()
+		m.UpdateAccountStatus(acc.ID, "stopped", StatusCause{Source: StatusSourceShutdown, Reason: "master shutting down"})
+		m.mutex.Unlock()
+	}
+}
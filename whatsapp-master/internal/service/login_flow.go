@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+var (
+	// ErrLoginFlowNotFound 账号没有手机号登录流程
+	ErrLoginFlowNotFound = errors.New("no phone login flow for account")
+	// ErrLoginFlowFinished 登录流程已结束，不能再提交操作
+	ErrLoginFlowFinished = errors.New("phone login flow already finished")
+	// ErrPairingCodeUnavailable Worker当前没有配对码
+	ErrPairingCodeUnavailable = errors.New("pairing code not available")
+)
+
+// loginFlow 一个账号的配对码登录流程。调用Worker时不持有锁，结果只应用到发起调用的那次尝试
+type loginFlow struct {
+	mutex      sync.Mutex
+	flow       model.LoginFlow
+	stateSince time.Time     // 进入当前状态的时间
+	wake       chan struct{} // 提交操作后立即检查一次，不等下一次轮询
+}
+
+// setStateLocked 切换状态并记录时间，调用方需持有锁
+func (lf *loginFlow) setStateLocked(state string, now time.Time) {
+	lf.flow.State = state
+	lf.flow.UpdatedAt = now
+	lf.stateSince = now
+}
+
+// snapshotLocked 返回流程的副本，调用方需持有锁
+func (lf *loginFlow) snapshotLocked() *model.LoginFlow {
+	flow := lf.flow
+	return &flow
+}
+
+// pairingCodeOf 从Worker响应中取出配对码，兼容 pairing_code、pairingCode 和 data 中的两种写法
+func pairingCodeOf(result map[string]interface{}) string {
+	for _, key := range []string{"pairing_code", "pairingCode"} {
+		if code, ok := result[key].(string); ok && code != "" {
+			return code
+		}
+	}
+	if data, ok := result["data"].(map[string]interface{}); ok {
+		return pairingCodeOf(data)
+	}
+	return ""
+}
+
+// codeTimeout 配对码的有效时间
+func (m *Manager) codeTimeout() time.Duration {
+	return time.Duration(max(m.GetConfig().PhoneLogin.CodeTimeoutSeconds, 10)) * time.Second
+}
+
+// startLoginFlow Worker接受手机号登录后开始跟踪配对码登录流程，同一账号之前未结束的流程被取消
+func (m *Manager) startLoginFlow(accountID, phone string, result map[string]interface{}) {
+	cfg := m.GetConfig().PhoneLogin
+	now := time.Now()
+	lf := &loginFlow{
+		flow: model.LoginFlow{
+			AccountID:   accountID,
+			Phone:       phone,
+			State:       model.LoginFlowRequestingCode,
+			Attempt:     1,
+			MaxAttempts: max(cfg.MaxAttempts, 1),
+			Deadline:    now.Add(time.Duration(cfg.TimeoutSeconds) * time.Second),
+			StartedAt:   now,
+			UpdatedAt:   now,
+		},
+		stateSince: now,
+		wake:       make(chan struct{}, 1),
+	}
+	lf.mutex.Lock()
+	m.applyPairingCodeLocked(lf, pairingCodeOf(result), now)
+	lf.mutex.Unlock()
+
+	if previous, loaded := m.loginFlows.Swap(accountID, lf); loaded {
+		prev := previous.(*loginFlow)
+		prev.mutex.Lock()
+		if !prev.flow.Finished() {
+			m.finishLoginFlowLocked(prev, model.LoginFlowCancelled, "replaced by a new phone login")
+		}
+		prev.mutex.Unlock()
+	}
+
+	m.background.Add(1)
+	go m.runLoginFlow(lf)
+}
+
+// runLoginFlow 轮询Worker登录状态直到登录成功、失败、取消或Master关闭
+func (m *Manager) runLoginFlow(lf *loginFlow) {
+	defer m.background.Done()
+
+	interval := time.Duration(m.GetConfig().PhoneLogin.PollSeconds) * time.Second
+	if interval <= 0 {
+		interval = 3 * time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-m.stopCh:
+			return
+		case <-ticker.C:
+		case <-lf.wake:
+		}
+		if m.stepLoginFlow(lf) {
+			return
+		}
+	}
+}
+
+// stepLoginFlow 检查一次Worker登录状态并推进流程，返回流程是否已结束
+func (m *Manager) stepLoginFlow(lf *loginFlow) bool {
+	lf.mutex.Lock()
+	if lf.flow.Finished() {
+		lf.mutex.Unlock()
+		return true
+	}
+	accountID := lf.flow.AccountID
+	lf.mutex.Unlock()
+
+	account, err := m.GetAccount(accountID)
+	if err != nil {
+		lf.mutex.Lock()
+		m.finishLoginFlowLocked(lf, model.LoginFlowFailed, "account was deleted")
+		lf.mutex.Unlock()
+		return true
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
+	defer cancel()
+	status, statusErr := m.callWorker(ctx, account, http.MethodGet, "/api/login/status", nil)
+
+	now := time.Now()
+	lf.mutex.Lock()
+	if lf.flow.Finished() {
+		lf.mutex.Unlock()
+		return true
+	}
+	var retryReason string
+	switch {
+	case statusErr != nil:
+		// Worker暂时不可达时保留当前状态，由超时判断决定是否重试
+		lf.flow.Error = statusErr.Error()
+	case status["is_logged_in"] == true:
+		m.finishLoginFlowLocked(lf, model.LoginFlowLoggedIn, "")
+		lf.mutex.Unlock()
+		m.checkWorkerStatus(account)
+		return true
+	default:
+		lf.flow.Error = ""
+		m.applyPairingCodeLocked(lf, pairingCodeOf(status), now)
+		if workerStatus, _ := status["status"].(string); workerStatus == "init_failed" {
+			retryReason = "worker failed to initialize the WhatsApp client"
+		}
+	}
+
+	if retryReason == "" {
+		switch lf.flow.State {
+		case model.LoginFlowWaitingForCode, model.LoginFlowVerifying:
+			if lf.flow.CodeExpiresAt != nil && now.After(*lf.flow.CodeExpiresAt) {
+				retryReason = "pairing code expired"
+			}
+		case model.LoginFlowRequestingCode:
+			if now.Sub(lf.stateSince) > m.codeTimeout() {
+				retryReason = "no pairing code received"
+			}
+		}
+	}
+	if now.After(lf.flow.Deadline) {
+		m.finishLoginFlowLocked(lf, model.LoginFlowFailed, "phone login timed out")
+		lf.mutex.Unlock()
+		return true
+	}
+
+	attempt := 0
+	if retryReason != "" {
+		attempt = m.beginRetryLocked(lf, retryReason, now)
+	}
+	finished := lf.flow.Finished()
+	phone := lf.flow.Phone
+	lf.mutex.Unlock()
+
+	if attempt > 0 {
+		m.requestPairingCode(ctx, lf, account, phone, attempt)
+	}
+	return finished
+}
+
+// beginRetryLocked 进入下一次尝试并返回尝试序号；次数用完时流程失败，返回0。调用方需持有锁
+func (m *Manager) beginRetryLocked(lf *loginFlow, reason string, now time.Time) int {
+	if lf.flow.Attempt >= lf.flow.MaxAttempts {
+		m.finishLoginFlowLocked(lf, model.LoginFlowFailed, fmt.Sprintf("%s after %d attempts", reason, lf.flow.Attempt))
+		return 0
+	}
+	lf.flow.Attempt++
+	lf.flow.PairingCode = ""
+	lf.flow.CodeIssuedAt = nil
+	lf.flow.CodeExpiresAt = nil
+	lf.flow.Error = reason
+	lf.setStateLocked(model.LoginFlowRequestingCode, now)
+	slog.Info("Requesting new pairing code", "account_id", lf.flow.AccountID, "attempt", lf.flow.Attempt, "reason", reason)
+	return lf.flow.Attempt
+}
+
+// requestPairingCode 不持有锁请求Worker生成新的配对码，Worker的客户端未启动或初始化失败时会重新发起手机号登录
+func (m *Manager) requestPairingCode(ctx context.Context, lf *loginFlow, account *model.Account, phone string, attempt int) {
+	result, err := m.postToWorker(ctx, account, "/api/pairing-code", map[string]string{"phone": phone})
+
+	lf.mutex.Lock()
+	defer lf.mutex.Unlock()
+	if lf.flow.Finished() || lf.flow.Attempt != attempt {
+		return
+	}
+	if err != nil {
+		lf.flow.Error = fmt.Sprintf("failed to request pairing code: %v", err)
+		lf.flow.UpdatedAt = time.Now()
+		return
+	}
+	m.applyPairingCodeLocked(lf, pairingCodeOf(result), time.Now())
+}
+
+// applyPairingCodeLocked Worker生成新的配对码时重新计算有效期并发布事件，调用方需持有锁
+func (m *Manager) applyPairingCodeLocked(lf *loginFlow, code string, now time.Time) {
+	if code == "" || code == lf.flow.PairingCode {
+		return
+	}
+	expiresAt := now.Add(m.codeTimeout())
+	lf.flow.PairingCode = code
+	lf.flow.CodeIssuedAt = &now
+	lf.flow.CodeExpiresAt = &expiresAt
+	lf.flow.Error = ""
+	lf.setStateLocked(model.LoginFlowWaitingForCode, now)
+
+	slog.Info("Pairing code issued", "account_id", lf.flow.AccountID, "attempt", lf.flow.Attempt)
+	m.emit(EventLoginCodeIssued, lf.flow.AccountID, map[string]interface{}{
+		"phone":        lf.flow.Phone,
+		"pairing_code": code,
+		"attempt":      lf.flow.Attempt,
+		"expires_at":   expiresAt,
+	})
+}
+
+// finishLoginFlowLocked 结束流程，失败时发布 login.failed，调用方需持有锁
+func (m *Manager) finishLoginFlowLocked(lf *loginFlow, state, reason string) {
+	now := time.Now()
+	lf.setStateLocked(state, now)
+	lf.flow.FinishedAt = &now
+	lf.flow.Error = reason
+	if state == model.LoginFlowLoggedIn {
+		lf.flow.PairingCode = ""
+		lf.flow.CodeExpiresAt = nil
+	}
+
+	slog.Info("Phone login flow finished", "account_id", lf.flow.AccountID, "state", state, "attempts", lf.flow.Attempt, "reason", reason)
+	if state == model.LoginFlowFailed {
+		m.emit(EventLoginFailed, lf.flow.AccountID, map[string]interface{}{
+			"phone":    lf.flow.Phone,
+			"attempts": lf.flow.Attempt,
+			"error":    reason,
+		})
+	}
+}
+
+// loginFlow 返回账号最近一次登录流程
+func (m *Manager) loginFlow(accountID string) *loginFlow {
+	if value, ok := m.loginFlows.Load(accountID); ok {
+		return value.(*loginFlow)
+	}
+	return nil
+}
+
+// GetLoginFlow 获取账号最近一次手机号登录流程，结束的流程保留到下一次手机号登录
+func (m *Manager) GetLoginFlow(accountID string) (*model.LoginFlow, error) {
+	lf := m.loginFlow(accountID)
+	if lf == nil {
+		return nil, ErrLoginFlowNotFound
+	}
+	lf.mutex.Lock()
+	defer lf.mutex.Unlock()
+	return lf.snapshotLocked(), nil
+}
+
+// GetPairingCode 获取账号当前的配对码，有进行中的登录流程时附带有效期和尝试次数，否则直接读取Worker的登录状态
+func (m *Manager) GetPairingCode(ctx context.Context, accountID string) (*model.PairingCode, error) {
+	if lf := m.loginFlow(accountID); lf != nil {
+		lf.mutex.Lock()
+		flow := lf.snapshotLocked()
+		lf.mutex.Unlock()
+		if !flow.Finished() && flow.PairingCode != "" {
+			return &model.PairingCode{
+				AccountID:   accountID,
+				Phone:       flow.Phone,
+				Code:        flow.PairingCode,
+				State:       flow.State,
+				ExpiresAt:   flow.CodeExpiresAt,
+				Attempt:     flow.Attempt,
+				MaxAttempts: flow.MaxAttempts,
+			}, nil
+		}
+	}
+
+	status, err := m.FetchFromWorker(ctx, accountID, "/api/login/status")
+	if err != nil {
+		return nil, err
+	}
+	code := pairingCodeOf(status)
+	workerStatus, _ := status["status"].(string)
+	if code == "" {
+		return nil, fmt.Errorf("%w, worker status is %s", ErrPairingCodeUnavailable, workerStatus)
+	}
+	return &model.PairingCode{AccountID: accountID, Code: code, State: workerStatus}, nil
+}
+
+// SubmitLoginVerification 处理登录流程中的操作：entered 进入 verifying 并重新计算有效期，resend 立即请求新的配对码，cancel 结束流程
+func (m *Manager) SubmitLoginVerification(ctx context.Context, accountID string, req *model.LoginVerifyRequest) (*model.LoginFlow, error) {
+	lf := m.loginFlow(accountID)
+	if lf == nil {
+		return nil, ErrLoginFlowNotFound
+	}
+	account, err := m.GetAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	lf.mutex.Lock()
+	if lf.flow.Finished() {
+		state := lf.flow.State
+		lf.mutex.Unlock()
+		return nil, fmt.Errorf("%w: %s", ErrLoginFlowFinished, state)
+	}
+
+	attempt := 0
+	switch req.Action {
+	case "entered":
+		if lf.flow.State != model.LoginFlowWaitingForCode {
+			state := lf.flow.State
+			lf.mutex.Unlock()
+			return nil, fmt.Errorf("no pairing code to confirm, login flow is %s", state)
+		}
+		// 给WhatsApp确认配对的时间，期间不会因配对码过期而请求新的配对码
+		expiresAt := now.Add(m.codeTimeout())
+		lf.flow.CodeExpiresAt = &expiresAt
+		lf.setStateLocked(model.LoginFlowVerifying, now)
+	case "resend":
+		if lf.flow.Attempt >= lf.flow.MaxAttempts {
+			used := lf.flow.Attempt
+			lf.mutex.Unlock()
+			return nil, fmt.Errorf("all %d pairing code attempts have been used", used)
+		}
+		attempt = m.beginRetryLocked(lf, "pairing code resend requested", now)
+	case "cancel":
+		m.finishLoginFlowLocked(lf, model.LoginFlowCancelled, "cancelled by user")
+	}
+	phone := lf.flow.Phone
+	lf.mutex.Unlock()
+
+	if attempt > 0 {
+		m.requestPairingCode(ctx, lf, account, phone, attempt)
+	}
+	select {
+	case lf.wake <- struct{}{}:
+	default:
+	}
+	return m.GetLoginFlow(accountID)
+}
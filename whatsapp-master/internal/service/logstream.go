@@ -0,0 +1,120 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// defaultLogTail 日志流默认先输出的行数
+const defaultLogTail = 100
+
+// ErrInvalidLogSince since 参数格式错误
+var ErrInvalidLogSince = errors.New("since must be an RFC3339 time or a duration like 10m")
+
+// OpenWorkerLogs 通过Worker运行时（docker logs）打开账号Worker容器的日志流（合并标准输出和标准错误），
+// follow 时持续输出直到ctx取消或容器退出。远程主机上的容器通过 fleet-agent 读取，调用方负责关闭
+func (m *Manager) OpenWorkerLogs(ctx context.Context, accountID string, query *model.LogStreamQuery) (io.ReadCloser, error) {
+	account, err := m.GetAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateLogSince(query.Since); err != nil {
+		return nil, err
+	}
+	return m.hostRuntime(account.HostID).Logs(ctx, workerContainerPrefix+account.ID, query)
+}
+
+// dockerLogsArgs 构建 docker logs 参数并校验 since
+func dockerLogsArgs(containerName string, query *model.LogStreamQuery) ([]string, error) {
+	tail := defaultLogTail
+	if query.Tail != nil {
+		tail = *query.Tail
+	}
+	args := []string{"logs", "--timestamps", "--tail", strconv.Itoa(tail)}
+
+	if query.Since != "" {
+		if err := validateLogSince(query.Since); err != nil {
+			return nil, err
+		}
+		args = append(args, "--since", query.Since)
+	}
+	if query.Follow == nil || *query.Follow {
+		args = append(args, "--follow")
+	}
+	return append(args, containerName), nil
+}
+
+// validateLogSince 校验 since：RFC3339 时间或 10m 这样的时长，为空时不限制
+func validateLogSince(since string) error {
+	if since == "" {
+		return nil
+	}
+	if _, err := time.Parse(time.RFC3339, since); err != nil {
+		if _, err := time.ParseDuration(since); err != nil {
+			return ErrInvalidLogSince
+		}
+	}
+	return nil
+}
+
+// startLocalDockerStream 在本机执行docker命令，返回合并的标准输出和标准错误，命令结束时流关闭
+func startLocalDockerStream(ctx context.Context, args []string) (io.ReadCloser, error) {
+	reader, writer := io.Pipe()
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	cmd.Stdout = writer
+	cmd.Stderr = writer
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to run docker: %v", err)
+	}
+	go func() {
+		err := cmd.Wait()
+		if err != nil && ctx.Err() == nil {
+			writer.CloseWithError(fmt.Errorf("docker %s: %v", args[0], err))
+			return
+		}
+		writer.Close()
+	}()
+	return reader, nil
+}
+
+// startAgentDockerStream 通过 fleet-agent 在远程主机上执行docker命令并返回其输出流
+func (m *Manager) startAgentDockerStream(ctx context.Context, hostID string, args []string) (io.ReadCloser, error) {
+	agentURL, token, err := m.hostAgent(hostID)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(model.AgentDockerRequest{Args: args})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %v", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, agentURL+"/docker/stream", bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Agent-Token", token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("host %s: agent unreachable: %v", hostID, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		return nil, fmt.Errorf("host %s: agent returned status %d: %s", hostID, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return resp.Body, nil
+}
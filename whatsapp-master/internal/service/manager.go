@@ -6,61 +6,327 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/exec"
+	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	mysqldriver "github.com/go-sql-driver/mysql"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 
+	"whatsapp-aggregator/internal/backup"
 	"whatsapp-aggregator/internal/config"
+	"whatsapp-aggregator/internal/logging"
 	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/storage"
+	"whatsapp-aggregator/internal/tracing"
 )
 
 // Manager 服务管理器
 type Manager struct {
-	config    *config.Config
+	config    atomic.Pointer[config.Config] // 当前配置，热更新时整体替换为修改后的副本
 	db        *gorm.DB
 	portPool  *PortPool
 	accounts  map[string]*model.Account
 	processes map[string]*exec.Cmd
 	mutex     sync.RWMutex
 	startTime time.Time
+
+	events     *EventBus
+	qrCodes    sync.Map // accountID -> 最近一次推送的二维码
+	qrWatching sync.Map // accountID -> 正在轮询扫码结果
+	loginFlows sync.Map // accountID -> 手机号配对码登录流程
+
+	sessionKeys  *sessionKeyring // 会话加密密钥，未开启会话加密时为nil
+	sessionSeals sync.Map        // accountID -> 会话加密和解密锁
+
+	statusEventAt  sync.Map // accountID -> Worker最近推送的状态事件时间（毫秒）
+	statusPolledAt sync.Map // accountID -> 最近一次轮询Worker状态的时间
+	statusPolling  sync.Map // accountID -> 正在轮询Worker状态
+
+	messageSyncs sync.Map // accountID -> 消息同步锁
+	operations   sync.Map // accountID/操作名 -> 进行中的账号操作
+
+	stopCh     chan struct{} // 关闭时close，通知后台任务退出
+	stopOnce   sync.Once
+	background sync.WaitGroup // 需要在关闭时等待的后台任务
+
+	bulkBatches map[string]*model.BulkBatch
+	bulkMutex   sync.RWMutex
+
+	chaosFaults map[string]*model.ChaosFault
+	chaosMutex  sync.Mutex
+
+	autoReplies chan *model.AutoReplyLog // 待发送的自动回复
+	workerCalls chan *model.WorkerCall   // 待写入的Worker调用记录
+
+	sendWindows   map[string]*sendWindow
+	tenantWindows map[string]*sendWindow // 租户每日发送计数
+	quotaMutex    sync.Mutex
+
+	globalBucket   *tokenBucket
+	accountBuckets map[string]*tokenBucket
+	bucketMutex    sync.Mutex
+
+	supervised      map[string]*supervisorState
+	supervisorMutex sync.Mutex
+
+	breakers     map[string]*circuitBreaker // Worker代理熔断器，只保存失败过的账号
+	breakerMutex sync.Mutex
+
+	workerSlots     map[string]*workerSemaphore // 每个账号转发中和排队的Worker代理请求
+	workerSlotMutex sync.Mutex
+
+	upgradeRun sync.Mutex // 保证同一时间只有一个滚动升级
+
+	janitorRun       sync.Mutex // 保证同一时间只有一个清理任务
+	janitorMutex     sync.Mutex
+	janitorLast      *model.JanitorReport
+	janitorRuns      int
+	janitorReclaimed int64
+	reconcileLast    *model.ReconcileReport
+
+	backupSchedule *cronSchedule // BACKUP_SCHEDULE，为nil时不定期备份
+	backupStore    backup.Store  // 目的地配置错误时为nil
+	objectStore    storage.Store // STORAGE_DESTINATION 配置错误时为nil
+	backupRun      sync.Mutex    // 保证同一时间只有一个备份任务
+	backupMutex    sync.Mutex
+	backupNext     *time.Time
+	backupLast     *model.BackupRunResult
+	backupLastAt   *time.Time
+
+	campaignRuns  map[string]string // 正在执行的活动 -> 任务ID
+	campaignMutex sync.Mutex
+
+	jobCancels map[string]context.CancelFunc // 运行中的任务
+	jobMutex   sync.Mutex
+
+	hosts        map[string]*model.Host // 包括本机，心跳状态只保存在内存中
+	placements   map[string]string      // accountID -> 账号Worker所在主机
+	hostFailures map[string]int         // 主机心跳连续失败次数
+	hostMutex    sync.RWMutex
+
+	webhooks     map[string]*model.Webhook
+	webhookMutex sync.RWMutex
+
+	alertChannels map[string]*model.AlertChannel
+	alertRules    map[string]*model.AlertRule
+	alertMutex    sync.RWMutex
+
+	baseConfig      config.Config     // 环境变量中的配置，删除覆盖项时恢复为该值
+	configOverrides map[string]string // 已应用的持久化配置项 -> JSON值，用于检测其他实例的修改
+
+	workerTransport http.RoundTripper // 调用Worker共用的连接池
+	workerHTTP      *http.Client      // 使用 workerTransport，带 WORKER_HTTP_TIMEOUT_SECONDS 超时
+	workerConns     *workerConnStats
+	mediaHTTP       *http.Client       // 下载媒体URL，拒绝内网地址
+	mockWorkers     *mockWorkerRuntime // WORKER_MODE=mock 时的进程内Worker，否则为nil
+
+	replicaID    string
+	leader       bool
+	leaderSince  *time.Time
+	leaderLease  *model.LeaderLease // 最近一次读取的租约，未开启选举时为nil
+	leaderSyncAt *time.Time         // 作为Follower最近一次从数据库同步的时间
+	leaderMutex  sync.RWMutex
 }
 
 // NewManager 创建服务管理器
 func NewManager(cfg *config.Config) (*Manager, error) {
+	if err := validateLeaderConfig(cfg.Leader.Election, cfg.Leader.LeaseSeconds, cfg.Leader.RenewSeconds); err != nil {
+		return nil, err
+	}
+	if err := validateRegionAffinity(cfg.Scheduler.RegionAffinity); err != nil {
+		return nil, err
+	}
+	if cfg.Storage.SigningKey == "" {
+		// 未配置时随机生成，重启后之前签发的本地对象下载链接失效
+		cfg.Storage.SigningKey = randomHex(32)
+		slog.Warn("STORAGE_SIGNING_KEY not set, generated a random key for this process")
+	}
+	objectStore := openObjectStore(cfg.Storage)
+	backupSchedule, backupStore, err := openBackupStore(cfg.Backup, objectStore)
+	if err != nil {
+		return nil, err
+	}
+
 	// 初始化数据库
 	db, err := initDB(cfg.DB)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize database: %v", err)
 	}
+	if err := configureSecrets(db, cfg.Secrets); err != nil {
+		return nil, err
+	}
+	sessionKeys, err := loadSessionKeys(cfg.SessionSeal)
+	if err != nil {
+		return nil, err
+	}
+
+	// 持久化的配置项覆盖环境变量，需在创建端口池之前应用
+	baseConfig := *cfg
+	overrides, err := applyConfigOverrides(db, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Media.SigningKey == "" {
+		// 未配置时随机生成，重启后之前签发的媒体链接失效
+		cfg.Media.SigningKey = randomHex(32)
+		slog.Warn("MEDIA_SIGNING_KEY not set, generated a random key for this process")
+	}
+	if _, exists := cfg.Warmup.Profiles[cfg.Warmup.DefaultProfile]; cfg.Warmup.Enabled && !exists {
+		slog.Warn("Warmup default profile not found, accounts without a profile are not limited", "profile", cfg.Warmup.DefaultProfile)
+	}
 
 	// 创建端口池
-	portPool := NewPortPool(cfg.Worker.BasePort, cfg.Worker.BasePort+cfg.Worker.PortRange-1)
+	portRanges, err := workerPortRanges(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("invalid worker port ranges: %v", err)
+	}
+	portPool := NewPortPool(portRanges)
+
+	workerConns := &workerConnStats{}
+	workerTransport, workerHTTP, err := newWorkerHTTP(cfg.WorkerHTTP, workerConns)
+	if err != nil {
+		return nil, fmt.Errorf("invalid worker HTTP settings: %v", err)
+	}
 
 	manager := &Manager{
-		config:    cfg,
 		db:        db,
 		portPool:  portPool,
 		accounts:  make(map[string]*model.Account),
 		processes: make(map[string]*exec.Cmd),
 		startTime: time.Now(),
+		events:    NewEventBus(),
+		stopCh:    make(chan struct{}),
+
+		bulkBatches: make(map[string]*model.BulkBatch),
+		chaosFaults: make(map[string]*model.ChaosFault),
+		autoReplies: make(chan *model.AutoReplyLog, max(cfg.AutoReply.QueueSize, 1)),
+		workerCalls: make(chan *model.WorkerCall, workerCallQueueSize),
+		sendWindows: make(map[string]*sendWindow),
+		supervised:  make(map[string]*supervisorState),
+		breakers:    make(map[string]*circuitBreaker),
+		workerSlots: make(map[string]*workerSemaphore),
+
+		tenantWindows:  make(map[string]*sendWindow),
+		accountBuckets: make(map[string]*tokenBucket),
+
+		campaignRuns: make(map[string]string),
+		jobCancels:   make(map[string]context.CancelFunc),
+
+		hosts:        make(map[string]*model.Host),
+		placements:   make(map[string]string),
+		hostFailures: make(map[string]int),
+
+		webhooks: make(map[string]*model.Webhook),
+
+		alertChannels: make(map[string]*model.AlertChannel),
+		alertRules:    make(map[string]*model.AlertRule),
+
+		baseConfig:      baseConfig,
+		configOverrides: overrides,
+
+		workerTransport: workerTransport,
+		workerHTTP:      workerHTTP,
+		workerConns:     workerConns,
+		mediaHTTP:       newMediaHTTP(cfg.Media.AllowPrivateURLs),
+		backupSchedule:  backupSchedule,
+		backupStore:     backupStore,
+		objectStore:     objectStore,
+		sessionKeys:     sessionKeys,
+
+		replicaID: valueOrDefault(cfg.Leader.ReplicaID, defaultReplicaID()),
+	}
+	manager.config.Store(cfg)
+
+	if cfg.Worker.Mode == WorkerModeMock {
+		manager.mockWorkers = newMockWorkerRuntime(manager)
+		slog.Warn("WORKER_MODE=mock: workers are simulated in-process and never contact WhatsApp")
+		if sessionKeys != nil {
+			slog.Warn("Session encryption has no effect with WORKER_MODE=mock, simulated workers have no session directory")
+		}
 	}
 
 	// 加载现有账号
 	if err := manager.loadExistingAccounts(); err != nil {
-		log.Printf("Warning: Failed to load existing accounts: %v", err)
+		slog.Warn("Failed to load existing accounts", "error", err)
+	}
+	if err := manager.loadHosts(); err != nil {
+		slog.Warn("Failed to load hosts", "error", err)
+	}
+	if err := manager.loadWebhooks(); err != nil {
+		slog.Warn("Failed to load webhooks", "error", err)
+	}
+	if err := manager.loadAlertRouting(); err != nil {
+		slog.Warn("Failed to load alert channels", "error", err)
 	}
 
 	return manager, nil
 }
 
 // CreateAccount 创建账号
-func (m *Manager) CreateAccount(ctx context.Context, req *model.LoginRequest) (*model.Account, error) {
+func (m *Manager) CreateAccount(ctx context.Context, req *model.LoginRequest) (_ *model.Account, err error) {
+	ctx, span := tracing.Start(ctx, "Manager.CreateAccount", tracing.KindInternal, tracing.String("account.id", req.AccountID))
+	defer func() { span.Finish(err) }()
+
+	ctx, err = m.checkCreateAccount(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	account, err := m.reserveAccount(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	return m.launchAccount(ctx, account)
+}
+
+// checkCreateAccount 校验创建请求，指定恢复备份时返回带有备份记录的上下文
+func (m *Manager) checkCreateAccount(ctx context.Context, req *model.LoginRequest) (context.Context, error) {
+	if req.Owner != nil {
+		if err := validateAccountOwner(req.Owner); err != nil {
+			return nil, err
+		}
+	}
+	if req.Resources != nil {
+		if err := validateWorkerResources(req.Resources); err != nil {
+			return nil, err
+		}
+	}
+	if req.Runtime != nil {
+		if err := validateWorkerRuntime(req.Runtime); err != nil {
+			return nil, err
+		}
+	}
+	if req.HostID != "" {
+		if err := m.checkHostPin(req.HostID); err != nil {
+			return nil, err
+		}
+	}
+	if err := m.checkHardwareProfile(req.HardwareProfileID); err != nil {
+		return nil, err
+	}
+	if req.RestoreBackup {
+		record, err := m.latestBackup(req.AccountID)
+		if err != nil {
+			return nil, err
+		}
+		ctx = withRestoreBackup(ctx, record)
+	}
+	return ctx, nil
+}
+
+// reserveAccount 在内存和数据库中登记状态为creating的账号并分配端口，同ID的并发创建会失败。
+// 只在登记期间持有 m.mutex，启动Worker由 launchAccount 在锁外完成
+func (m *Manager) reserveAccount(ctx context.Context, req *model.LoginRequest) (*model.Account, error) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
@@ -68,13 +334,22 @@ func (m *Manager) CreateAccount(ctx context.Context, req *model.LoginRequest) (*
 	if _, exists := m.accounts[req.AccountID]; exists {
 		return nil, fmt.Errorf("account %s already exists", req.AccountID)
 	}
+	if req.TenantID != "" {
+		if err := m.checkTenantWorkerLimitLocked(req.TenantID); err != nil {
+			return nil, err
+		}
+	}
 
 	var account *model.Account
 
 	// 检查数据库中是否存在（即使内存中没有）
 	var dbAccount model.Account
 	if err := m.db.Unscoped().Where("id = ?", req.AccountID).First(&dbAccount).Error; err == nil {
-		log.Printf("Account %s found in DB but not in memory, recovering...", req.AccountID)
+		// 其他租户的已删除账号不能被恢复
+		if req.TenantID != "" && dbAccount.TenantID != req.TenantID {
+			return nil, fmt.Errorf("account %s already exists", req.AccountID)
+		}
+		logging.FromContext(ctx).Info("Account found in DB but not in memory, recovering", "account_id", req.AccountID)
 		account = &dbAccount
 
 		// 恢复软删除
@@ -82,6 +357,8 @@ func (m *Manager) CreateAccount(ctx context.Context, req *model.LoginRequest) (*
 			account.DeletedAt = gorm.DeletedAt{}
 			m.db.Unscoped().Model(account).Update("deleted_at", nil)
 		}
+		// 再次删除时重新按保留策略清除会话
+		account.SessionPurgedAt = nil
 
 		// 更新状态和信息
 		account.Status = "creating"
@@ -89,6 +366,7 @@ func (m *Manager) CreateAccount(ctx context.Context, req *model.LoginRequest) (*
 		if req.Phone != "" {
 			account.Phone = req.Phone
 		}
+		applyAccountLabels(account, req)
 
 		if err := m.db.Save(account).Error; err != nil {
 			return nil, fmt.Errorf("failed to update account: %v", err)
@@ -100,7 +378,7 @@ func (m *Manager) CreateAccount(ctx context.Context, req *model.LoginRequest) (*
 		// 分配端口
 		port, err := m.portPool.Allocate()
 		if err != nil {
-			return nil, fmt.Errorf("failed to allocate port: %v", err)
+			return nil, fmt.Errorf("failed to allocate port: %w", err)
 		}
 
 		// 创建账号记录
@@ -110,10 +388,11 @@ func (m *Manager) CreateAccount(ctx context.Context, req *model.LoginRequest) (*
 			Phone:      req.Phone,
 			Status:     "creating",
 			Port:       port,
-			ServiceURL: fmt.Sprintf("http://localhost:%d", port),
+			ServiceURL: fmt.Sprintf("%s://localhost:%d", m.workerScheme(), port),
 			CreatedAt:  time.Now(),
 			UpdatedAt:  time.Now(),
 		}
+		applyAccountLabels(account, req)
 
 		// 保存到数据库
 		if err := m.db.Create(account).Error; err != nil {
@@ -122,26 +401,131 @@ func (m *Manager) CreateAccount(ctx context.Context, req *model.LoginRequest) (*
 		}
 	}
 
+	if req.HostID != "" {
+		account.HostID = req.HostID
+	}
+
 	// 添加到内存
 	m.accounts[req.AccountID] = account
+	return account, nil
+}
 
-	// 启动服务实例
-	if err := m.spawnWorker(account); err != nil {
-		m.portPool.Release(account.Port)
-		delete(m.accounts, req.AccountID)
-		// 标记为错误状态而不是删除，以便后续可以重试或排查
-		account.Status = "error"
-		m.db.Save(account)
-		return nil, fmt.Errorf("failed to spawn worker: %v", err)
+// launchAccount 启动已登记账号的Worker。镜像拉取、容器启动和等待就绪可能耗时数分钟，期间不持有 m.mutex，
+// 其他请求照常读取账号；完成后重新加锁记录结果，账号在启动期间被删除时清理刚启动的容器
+func (m *Manager) launchAccount(ctx context.Context, account *model.Account) (*model.Account, error) {
+	accountID := account.ID
+	spawnErr := m.spawnWorker(ctx, account, jobStageReporter(ctx))
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	if m.accounts[accountID] != account {
+		// DeleteAccount 已释放端口和位置
+		if spawnErr == nil {
+			m.hostRuntime(account.HostID).Stop(workerContainerPrefix + accountID)
+		}
+		return nil, fmt.Errorf("account %s was deleted while its worker was starting", accountID)
 	}
 
-	m.UpdateAccountStatus(req.AccountID, "running")
-	log.Printf("Account %s started on port %d", req.AccountID, account.Port)
+	if spawnErr != nil {
+		m.abandonAccountLocked(ctx, account, fmt.Sprintf("failed to spawn worker: %v", spawnErr), spawnErr)
+		return nil, fmt.Errorf("failed to spawn worker: %w", spawnErr)
+	}
 
-	return account, nil
+	// 启动期间Worker已推送的登录状态比running更准确，保留
+	if account.Status == "creating" {
+		m.UpdateAccountStatus(accountID, "running", apiCause(ctx, "account created"))
+	}
+	logging.FromContext(ctx).Info("Account started", "account_id", accountID, "port", account.Port)
+
+	return account.Clone(), nil
+}
+
+// abandonAccountLocked 放弃创建中的账号：释放端口和位置并从内存移除，调用方需持有 m.mutex。
+// 数据库中标记为错误状态而不是删除，以便后续可以重试或排查
+func (m *Manager) abandonAccountLocked(ctx context.Context, account *model.Account, reason string, err error) {
+	m.portPool.Release(account.Port)
+	m.releasePlacement(account.ID)
+	delete(m.accounts, account.ID)
+	previous := account.Status
+	account.Status = "error"
+	m.db.Save(account)
+	m.emitStatusChange(account.ID, previous, account.Status, apiCause(ctx, reason).withError(err))
+}
+
+// CreateAccountAsync 校验请求并登记账号后在后台任务中启动Worker，任务记录Worker启动阶段，结果为创建的账号。
+// 返回时账号已以creating状态出现在账号列表中，同ID的并发创建立即失败
+func (m *Manager) CreateAccountAsync(ctx context.Context, req *model.LoginRequest) (*model.Job, error) {
+	ctx, err := m.checkCreateAccount(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkPortCapacity(1); err != nil {
+		return nil, err
+	}
+	account, err := m.reserveAccount(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	requestID := logging.RequestID(ctx)
+	record := restoreBackupFrom(ctx)
+	job, err := m.startTenantJob(req.TenantID, JobCreateAccount, "create account "+req.AccountID, 1, func(r *jobRun) error {
+		jobCtx := logging.WithRequestID(tracing.ContinueFrom(r.Context(), ctx), requestID)
+		if record != nil {
+			jobCtx = withRestoreBackup(jobCtx, record)
+		}
+		account, err := m.launchAccount(jobCtx, account)
+		if err != nil {
+			return err
+		}
+		r.SetResult(account)
+		r.Advance(1)
+		return nil
+	})
+	if err != nil {
+		m.mutex.Lock()
+		if m.accounts[account.ID] == account {
+			m.abandonAccountLocked(ctx, account, fmt.Sprintf("failed to start create job: %v", err), err)
+		}
+		m.mutex.Unlock()
+		return nil, err
+	}
+	return job, nil
 }
 
-// GetAccount 获取账号
+// applyAccountLabels 将创建请求中的标签、池、代理、负责人和硬件指纹配置写入账号
+func applyAccountLabels(account *model.Account, req *model.LoginRequest) {
+	if len(req.Tags) > 0 {
+		account.Tags = model.StringList(req.Tags)
+	}
+	if req.Pool != "" {
+		account.Pool = req.Pool
+	}
+	if req.ProxyConfig != nil && req.ProxyConfig.Region != "" {
+		account.ProxyRegion = req.ProxyConfig.Region
+	}
+	if proxy, ok := accountProxyFromConfig(req.ProxyConfig); ok {
+		account.Proxy = proxy
+	}
+	if req.Owner != nil {
+		applyAccountOwner(account, req.Owner)
+	}
+	if req.Resources != nil {
+		applyWorkerResources(account, req.Resources)
+	}
+	if req.Runtime != nil {
+		applyWorkerRuntime(account, req.Runtime)
+	}
+	if req.TenantID != "" {
+		account.TenantID = req.TenantID
+	}
+	if req.HardwareProfileID != "" {
+		account.HardwareProfileID = req.HardwareProfileID
+	}
+}
+
+// GetAccount 获取账号的副本
 func (m *Manager) GetAccount(accountID string) (*model.Account, error) {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
@@ -151,17 +535,17 @@ func (m *Manager) GetAccount(accountID string) (*model.Account, error) {
 		return nil, fmt.Errorf("account %s not found", accountID)
 	}
 
-	return account, nil
+	return account.Clone(), nil
 }
 
-// ListAccounts 列出所有账号
+// ListAccounts 列出所有账号的副本
 func (m *Manager) ListAccounts() []*model.Account {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
 	accounts := make([]*model.Account, 0, len(m.accounts))
 	for _, account := range m.accounts {
-		accounts = append(accounts, account)
+		accounts = append(accounts, account.Clone())
 	}
 
 	return accounts
@@ -178,9 +562,11 @@ func (m *Manager) StopAccount(ctx context.Context, accountID string) error {
 	}
 
 	containerName := fmt.Sprintf("whatsapp-worker-%s", account.ID)
-	exec.Command("docker", "rm", "-f", containerName).Run()
+	m.sealBeforeRemove(account.HostID, account.ID)
+	m.hostRuntime(account.HostID).Stop(containerName)
 
 	// 更新状态为stopped
+	previous := account.Status
 	account.Status = "stopped"
 	account.UpdatedAt = time.Now()
 
@@ -191,40 +577,65 @@ func (m *Manager) StopAccount(ctx context.Context, accountID string) error {
 	}).Error; err != nil {
 		return fmt.Errorf("failed to update account status: %v", err)
 	}
+	m.emitStatusChange(accountID, previous, account.Status, apiCause(ctx, "account stopped"))
+	m.resetSupervisor(accountID)
 
-	log.Printf("Account %s stopped successfully", accountID)
+	logging.FromContext(ctx).Info("Account stopped", "account_id", accountID)
 	return nil
 }
 
 // DeleteAccount 删除账号
-func (m *Manager) DeleteAccount(ctx context.Context, accountID string) error {
+func (m *Manager) DeleteAccount(ctx context.Context, accountID string, purgeSession bool) (*model.DeleteAccountResult, error) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
 
 	account, exists := m.accounts[accountID]
 	if !exists {
-		return fmt.Errorf("account %s not found", accountID)
+		return nil, fmt.Errorf("account %s not found", accountID)
+	}
+	if err := m.runHook(ctx, HookPreDelete, account); err != nil {
+		return nil, err
 	}
 
 	// 优雅停止
 	m.gracefulStop(account)
 
 	containerName := fmt.Sprintf("whatsapp-worker-%s", account.ID)
-	exec.Command("docker", "rm", "-f", containerName).Run()
+	if !purgeSession {
+		// 保留的会话按 SESSION_RETENTION_DAYS 保存，以便恢复账号
+		m.sealBeforeRemove(account.HostID, account.ID)
+	}
+	m.hostRuntime(account.HostID).Stop(containerName)
 
-	// 释放端口
+	// 释放端口和主机上的位置
 	m.portPool.Release(account.Port)
+	m.releasePlacement(accountID)
 
 	// 从数据库删除
 	if err := m.db.Delete(account).Error; err != nil {
-		return fmt.Errorf("failed to delete account from database: %v", err)
+		return nil, fmt.Errorf("failed to delete account from database: %v", err)
 	}
 
 	// 从内存删除
 	delete(m.accounts, accountID)
+	m.statusPolledAt.Delete(accountID)
 
-	log.Printf("Account %s deleted successfully", accountID)
-	return nil
+	result := &model.DeleteAccountResult{AccountID: accountID}
+	if purgeSession {
+		// 清除失败时会话目录保留，由清理任务按 SESSION_RETENTION_DAYS 重试
+		size, err := m.purgeSession(account.HostID, accountID)
+		if err != nil {
+			logging.FromContext(ctx).Warn("Failed to purge account session", "account_id", accountID, "error", err)
+			result.PurgeError = err.Error()
+		} else {
+			m.markSessionPurged(accountID)
+			result.SessionPurged = true
+			result.ReclaimedBytes = size
+		}
+	}
+
+	logging.FromContext(ctx).Info("Account deleted", "account_id", accountID, "session_purged", result.SessionPurged)
+	return result, nil
 }
 
 // gracefulStop 尝试优雅停止Worker
@@ -236,34 +647,8 @@ func (m *Manager) gracefulStop(account *model.Account) {
 	defer cancel()
 
 	req, _ := http.NewRequestWithContext(ctx, "POST", fmt.Sprintf("%s/api/close", account.ServiceURL), nil)
-	http.DefaultClient.Do(req)
-}
-
-// StartStatusPoller 启动状态轮询
-func (m *Manager) StartStatusPoller(interval time.Duration) {
-	// 启动时立即执行一次状态检查
-	go m.updateAllAccountStatuses()
-
-	ticker := time.NewTicker(interval)
-	go func() {
-		for range ticker.C {
-			m.updateAllAccountStatuses()
-		}
-	}()
-}
-
-func (m *Manager) updateAllAccountStatuses() {
-	m.mutex.RLock()
-	accounts := make([]*model.Account, 0)
-	for _, acc := range m.accounts {
-		if acc.Status != "stopped" && acc.Status != "error" {
-			accounts = append(accounts, acc)
-		}
-	}
-	m.mutex.RUnlock()
-
-	for _, acc := range accounts {
-		go m.checkWorkerStatus(acc)
+	if resp, err := m.workerHTTP.Do(req); err == nil {
+		resp.Body.Close()
 	}
 }
 
@@ -273,7 +658,7 @@ func (m *Manager) checkWorkerStatus(acc *model.Account) {
 	defer cancel()
 
 	req, _ := http.NewRequestWithContext(ctx, "GET", workerURL, nil)
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := m.workerHTTP.Do(req)
 	if err != nil {
 		// Connection failed, log it but don't stop immediately unless repeated failures?
 		// For now, ignore. The process monitor handles process death.
@@ -290,18 +675,32 @@ func (m *Manager) checkWorkerStatus(acc *model.Account) {
 		return
 	}
 
+	if qrCode, ok := result["qr_code"].(string); ok {
+		m.emitQRCode(acc.ID, qrCode)
+	}
+
+	// Worker可能在Master之外被重建，每次轮询都更新版本
+	version := workerVersion{}
+	version.Version, _ = result["version"].(string)
+	if apiVersion, ok := result["api_version"].(float64); ok {
+		version.APIVersion = int(apiVersion)
+	}
+	m.mutex.Lock()
+	m.recordWorkerVersion(acc, version)
+	m.mutex.Unlock()
+
 	// Check status in response
 	if statusRaw, ok := result["status"]; ok {
 		statusStr, ok := statusRaw.(string)
 		if ok && statusStr != "" && statusStr != acc.Status {
 			// Avoid updating timestamp if status hasn't changed effectively (e.g. logging noise)
-			m.UpdateAccountStatusSafe(acc.ID, statusStr)
+			m.UpdateAccountStatusSafe(acc.ID, statusStr, StatusCause{Source: StatusSourceWorker, Reason: "reported by worker status poll"})
 		}
 	}
 }
 
-// UpdateAccountStatus 更新账号状态
-func (m *Manager) UpdateAccountStatus(accountID, status string) {
+// UpdateAccountStatus 更新账号状态，cause记录在状态历史中
+func (m *Manager) UpdateAccountStatus(accountID, status string, cause StatusCause) {
 	// 注意：调用此方法前通常需要持有锁，或者在此方法内加锁
 	// 由于此方法在其他加锁方法中调用，这里我们假设调用者已经处理好锁的问题
 	// 或者我们修改它只在需要时加锁。为安全起见，这里检查一下是否递归锁（Go不支持）。
@@ -320,6 +719,7 @@ func (m *Manager) UpdateAccountStatus(accountID, status string) {
 	// 我应该在CreateAccount中直接更新内存和DB，不调用UpdateAccountStatus。
 
 	if account, exists := m.accounts[accountID]; exists {
+		previous := account.Status
 		account.Status = status
 		account.UpdatedAt = time.Now()
 
@@ -328,85 +728,115 @@ func (m *Manager) UpdateAccountStatus(accountID, status string) {
 			"status":     status,
 			"updated_at": account.UpdatedAt,
 		})
+		m.trackSession(account, previous, status)
+		m.emitStatusChange(accountID, previous, status, cause)
 	}
 }
 
 // UpdateAccountStatusSafe 线程安全的更新状态
-func (m *Manager) UpdateAccountStatusSafe(accountID, status string) {
+func (m *Manager) UpdateAccountStatusSafe(accountID, status string, cause StatusCause) {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
-	m.UpdateAccountStatus(accountID, status)
+	m.UpdateAccountStatus(accountID, status, cause)
 }
 
-// GetHealthStatus 获取健康状态
-func (m *Manager) GetHealthStatus() *model.HealthStatus {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
+// Worker启动阶段，异步创建账号时记录在任务上
+const (
+	SpawnStagePullingImage = "pulling_image"
+	SpawnStageStarting     = "starting"
+	SpawnStageWaitingReady = "waiting_ready"
+	SpawnStageRestoring    = "restoring_backup"
+	SpawnStageUnsealing    = "unsealing_session"
+)
 
-	accounts := make([]*model.Account, 0, len(m.accounts))
-	runningCount := 0
-	loggedInCount := 0
+// spawnWorker 启动Worker，onStage不为nil时在进入每个启动阶段时回调
+func (m *Manager) spawnWorker(ctx context.Context, account *model.Account, onStage func(string)) (err error) {
+	ctx, span := tracing.Start(ctx, "Manager.spawnWorker", tracing.KindInternal,
+		tracing.String("account.id", account.ID),
+		tracing.String("worker.image", m.GetConfig().Worker.Image),
+	)
+	defer func() { span.Finish(err) }()
 
-	for _, account := range m.accounts {
-		accounts = append(accounts, account)
-		if account.Status == "running" {
-			runningCount++
+	if onStage == nil {
+		onStage = func(string) {}
+	}
+	if err := m.runHook(ctx, HookPreSpawn, account); err != nil {
+		return err
+	}
+	if err := m.startWorker(ctx, account, onStage); err != nil {
+		return err
+	}
+	m.resetBreaker(account.ID)
+	m.runHookAsync(HookPostReady, account)
+	return nil
+}
+
+// startWorker 在调度器选定的主机上通过其Worker运行时启动Worker，并等待 /api/status 就绪
+func (m *Manager) startWorker(ctx context.Context, account *model.Account, onStage func(string)) error {
+	containerName := workerContainerPrefix + account.ID
+
+	host, err := m.placeWorker(account)
+	if err != nil {
+		return err
+	}
+	runtime := m.hostRuntime(host.ID)
+	restore := restoreBackupFrom(ctx)
+
+	// Remove existing container
+	if containers, _ := runtime.List(containerName); slices.ContainsFunc(containers, func(c WorkerContainer) bool { return c.Name == containerName }) {
+		// 恢复备份时会话将被替换，不再保存旧容器的会话
+		if restore == nil {
+			m.sealBeforeRemove(host.ID, account.ID)
 		}
-		if account.Status == "logged_in" {
-			loggedInCount++
+		runtime.Stop(containerName)
+	}
+
+	// 启动对账时容器已不存在的账号端口已被释放，需要重新分配
+	if account.Port == 0 {
+		port, err := m.portPool.Allocate()
+		if err != nil {
+			return fmt.Errorf("failed to allocate port: %w", err)
 		}
+		account.Port = port
 	}
 
-	return &model.HealthStatus{
-		Status:        "healthy",
-		Uptime:        time.Since(m.startTime).String(),
-		Accounts:      accounts,
-		TotalCount:    len(accounts),
-		RunningCount:  runningCount,
-		LoggedInCount: loggedInCount,
-		SystemInfo: model.SystemInfo{
-			WorkerMode:  m.config.Worker.Mode,
-			Environment: "development",
-			Version:     "1.0.0",
-		},
+	// Worker回调Master的凭证，重建容器时保持不变
+	if account.WorkerToken == "" {
+		account.WorkerToken = model.Secret(randomHex(24))
 	}
-}
 
-// spawnWorker 启动Worker
-func (m *Manager) spawnWorker(account *model.Account) error {
-	return m.spawnWorkerDocker(account)
-}
+	if err := runtime.Spawn(ctx, &WorkerSpec{Name: containerName, Account: account, Restore: restore, OnStage: onStage}); err != nil {
+		return err
+	}
 
-// spawnWorkerDocker 启动Docker Worker
-func (m *Manager) spawnWorkerDocker(account *model.Account) error {
-	containerName := fmt.Sprintf("whatsapp-worker-%s", account.ID)
+	account.ServiceURL = m.workerServiceURL(host.ID, containerName, account.Port)
 
-	// Check if container exists
-	checkCmd := exec.Command("docker", "ps", "-a", "--filter", fmt.Sprintf("name=^/%s$", containerName), "--format", "{{.ID}}")
-	output, _ := checkCmd.Output()
+	slog.Info("Worker spawned", "account_id", account.ID, "host_id", host.ID, "service_url", account.ServiceURL)
 
-	if len(output) > 0 {
-		// Remove existing container
-		exec.Command("docker", "rm", "-f", containerName).Run()
-	}
+	account.ContainerID = containerName // Store name as ID for now
+	m.db.Save(account)
 
-	// Prepare Docker run command
-	args := []string{
-		"run", "-d",
-		"--name", containerName,
-		"--network", m.config.Worker.Network,
-		"-e", fmt.Sprintf("PORT=%d", m.config.Worker.BasePort), // Internal port is usually fixed
-		"-e", fmt.Sprintf("ACCOUNT_ID=%s", account.ID),
-		"-p", fmt.Sprintf("%d:%d", account.Port, m.config.Worker.BasePort), // Map external port to internal
-		// Mount session directory
-		"-v", fmt.Sprintf("%s/whatsapp-session/%s:/app/whatsapp-session/%s", os.Getenv("PWD"), account.ID, account.ID),
-		m.config.Worker.Image,
+	// Wait for worker to be ready by polling health endpoint
+	onStage(SpawnStageWaitingReady)
+	version, err := m.waitForWorkerReady(ctx, account.ServiceURL, m.containerExited(host.ID, containerName))
+	if err != nil {
+		return fmt.Errorf("worker failed to become ready: %w", m.workerStartupError(host.ID, containerName, err))
+	}
+	if !m.recordWorkerVersion(account, version) && m.GetConfig().Worker.VersionCheck == WorkerVersionCheckBlock {
+		runtime.Stop(containerName)
+		return fmt.Errorf("%w: worker %s reports API version %d, supported %s", ErrIncompatibleWorker,
+			m.GetConfig().Worker.Image, version.APIVersion, m.supportedWorkerAPIVersions())
 	}
+	return nil
+}
 
-	log.Printf("Starting container %s with image %s", containerName, m.config.Worker.Image)
-	cmd := exec.Command("docker", args...)
-	if combinedOutput, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("failed to start docker container: %v, output: %s", err, string(combinedOutput))
+// workerServiceURL Master访问Worker容器的地址，远程主机上的Worker通过主机地址和映射端口访问
+func (m *Manager) workerServiceURL(hostID, containerName string, port int) string {
+	if m.mockWorkers != nil {
+		return fmt.Sprintf("http://127.0.0.1:%d", port)
+	}
+	if address := m.hostAddress(hostID); address != "" {
+		return fmt.Sprintf("%s://%s:%d", m.workerScheme(), address, port)
 	}
 
 	// Update service URL - for Docker bridge network, localhost + mapped port works for Master outside container
@@ -416,67 +846,77 @@ func (m *Manager) spawnWorkerDocker(account *model.Account) error {
 
 	// Refine Service URL logic based on deployment
 	// If Master is in Docker container in the same network:
-	if os.Getenv("DOCKER_ENABLED") == "true" { // or check m.config.Worker.Mode == "docker"
-		account.ServiceURL = fmt.Sprintf("http://%s:%d", containerName, m.config.Worker.BasePort)
-	} else {
-		// Master is local, connect via localhost mapped port
-		account.ServiceURL = fmt.Sprintf("http://localhost:%d", account.Port)
+	if os.Getenv("DOCKER_ENABLED") == "true" { // or check m.GetConfig().Worker.Mode == "docker"
+		return fmt.Sprintf("%s://%s:%d", m.workerScheme(), containerName, m.GetConfig().Worker.BasePort)
 	}
-
-	log.Printf("Worker spawned for account %s, ServiceURL: %s", account.ID, account.ServiceURL)
-
-	account.ContainerID = containerName // Store name as ID for now
-	m.db.Save(account)
-
-	// Wait for startup
-	// time.Sleep(5 * time.Second)
-	// Wait for worker to be ready by polling health endpoint
-	if err := m.waitForWorkerReady(account.ServiceURL); err != nil {
-		return fmt.Errorf("worker failed to become ready: %v", err)
-	}
-	return nil
+	// Master is local, connect via localhost mapped port
+	return fmt.Sprintf("%s://localhost:%d", m.workerScheme(), port)
 }
 
-// waitForWorkerReady 轮询等待Worker准备就绪
-func (m *Manager) waitForWorkerReady(serviceURL string) error {
-	timeout := time.After(60 * time.Second) // 增加超时时间到 60s，适应 Docker + Proxy 启动慢的情况
+// waitForWorkerReady 轮询等待Worker准备就绪，返回Worker上报的版本。
+// 超时时间由 WORKER_READY_TIMEOUT_SECONDS 配置；exited 不为空时定期检查容器，容器已退出时不再等待
+func (m *Manager) waitForWorkerReady(ctx context.Context, serviceURL string, exited func() bool) (_ workerVersion, err error) {
+	_, span := tracing.Start(ctx, "Manager.waitForWorkerReady", tracing.KindInternal, tracing.String("worker.url", serviceURL))
+	polls := 0
+	defer func() {
+		span.SetAttributes(tracing.Int("worker.ready_polls", polls))
+		span.Finish(err)
+	}()
+
+	readyTimeout := m.readyTimeout()
+	timeout := time.After(readyTimeout)
 	ticker := time.NewTicker(1 * time.Second)
 	defer ticker.Stop()
 
-	log.Printf("Waiting for worker at %s to be ready...", serviceURL)
+	slog.Debug("Waiting for worker to be ready", "service_url", serviceURL)
 
 	for {
 		select {
 		case <-timeout:
-			log.Printf("Timeout waiting for worker %s to be ready", serviceURL)
-			return fmt.Errorf("timeout waiting for worker to be ready")
+			slog.Warn("Timeout waiting for worker to be ready", "service_url", serviceURL)
+			return workerVersion{}, fmt.Errorf("timeout waiting for worker to be ready after %s", readyTimeout)
 		case <-ticker.C:
-			resp, err := http.Get(fmt.Sprintf("%s/api/status", serviceURL))
+			polls++
+			if exited != nil && polls%5 == 0 && exited() {
+				slog.Warn("Worker container exited before becoming ready", "service_url", serviceURL)
+				return workerVersion{}, fmt.Errorf("worker container exited before becoming ready")
+			}
+			resp, err := m.workerHTTP.Get(fmt.Sprintf("%s/api/status", serviceURL))
 			if err == nil {
+				var version workerVersion
+				json.NewDecoder(resp.Body).Decode(&version)
 				resp.Body.Close()
 				if resp.StatusCode == 200 {
-					log.Printf("Worker at %s is ready!", serviceURL)
-					return nil
+					slog.Info("Worker is ready", "service_url", serviceURL, "version", version.Version, "api_version", version.APIVersion)
+					return version, nil
 				}
-				log.Printf("Worker at %s returned status %d", serviceURL, resp.StatusCode)
+				slog.Debug("Worker not ready yet", "service_url", serviceURL, "status", resp.StatusCode)
 			} else {
-				// log.Printf("Worker at %s not ready yet: %v", serviceURL, err) // Optional debug log
+				slog.Debug("Worker not ready yet", "service_url", serviceURL, "error", err)
 			}
 		}
 	}
 }
 
 // StartAccount 启动账号
-func (m *Manager) StartAccount(ctx context.Context, accountID string, req *model.PhoneLoginRequest) error {
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
+func (m *Manager) StartAccount(ctx context.Context, accountID string, req *model.PhoneLoginRequest) (err error) {
+	ctx, span := tracing.Start(ctx, "Manager.StartAccount", tracing.KindInternal, tracing.String("account.id", accountID))
+	defer func() { span.Finish(err) }()
 
+	// 只在登记状态时持有锁，拉取镜像和等待Worker就绪在锁外进行，避免阻塞其他账号的操作
+	m.mutex.Lock()
 	account, exists := m.accounts[accountID]
 	if !exists {
+		m.mutex.Unlock()
 		return fmt.Errorf("account %s not found", accountID)
 	}
+	if account.Status == "starting" || account.Status == "creating" {
+		m.mutex.Unlock()
+		return fmt.Errorf("account %s is already %s", accountID, account.Status)
+	}
 
 	// 更新账号状态为启动中
+	previous := account.Status
 	account.Status = "starting"
 	account.UpdatedAt = time.Now()
 
@@ -485,12 +925,25 @@ func (m *Manager) StartAccount(ctx context.Context, accountID string, req *model
 		"status":     account.Status,
 		"updated_at": account.UpdatedAt,
 	})
+	m.mutex.Unlock()
 
 	// 启动Worker实例
-	if err := m.spawnWorker(account); err != nil {
+	spawnErr := m.spawnWorker(ctx, account, nil)
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	if m.accounts[accountID] != account {
+		// DeleteAccount 已释放端口和位置
+		if spawnErr == nil {
+			m.hostRuntime(account.HostID).Stop(workerContainerPrefix + accountID)
+		}
+		return fmt.Errorf("account %s was deleted while its worker was starting", accountID)
+	}
+	if spawnErr != nil {
 		account.Status = "error"
 		m.db.Model(account).Updates(map[string]interface{}{"status": "error"})
-		return fmt.Errorf("failed to start worker: %v", err)
+		m.emitStatusChange(accountID, previous, account.Status, apiCause(ctx, fmt.Sprintf("failed to start worker: %v", spawnErr)).withError(spawnErr))
+		return fmt.Errorf("failed to start worker: %w", spawnErr)
 	}
 
 	account.Status = "running"
@@ -499,13 +952,39 @@ func (m *Manager) StartAccount(ctx context.Context, accountID string, req *model
 		"status":     account.Status,
 		"updated_at": account.UpdatedAt,
 	})
-	log.Printf("Account %s started successfully on port %d", accountID, account.Port)
+	m.emitStatusChange(accountID, previous, account.Status, apiCause(ctx, "account started"))
+	m.resetSupervisor(accountID)
+	logging.FromContext(ctx).Info("Account started", "account_id", accountID, "port", account.Port)
 
 	return nil
 }
 
+// workerLoginRequest 构造Worker登录接口的请求体
+func workerLoginRequest(account *model.Account, req *model.PhoneLoginRequest, proxy model.AccountProxy, hardware model.HardwareInfo) map[string]interface{} {
+	loginMethod := "qr"
+	if req.SigninType == signinTypePhone {
+		loginMethod = "phone"
+	}
+	return map[string]interface{}{
+		"account_id":          account.ID,
+		"signin_type":         req.SigninType,
+		"login_phone":         req.LoginPhone,
+		"login_method":        loginMethod,
+		"is_cache_login":      req.CacheLogin,
+		"hardware_info":       hardware,
+		"socks5":              workerSocks5(proxy),
+		"disable_qr_fallback": true,
+	}
+}
+
 // LoginToWorker 调用Worker的登录接口
-func (m *Manager) LoginToWorker(ctx context.Context, account *model.Account, req *model.PhoneLoginRequest) (map[string]interface{}, error) {
+func (m *Manager) LoginToWorker(ctx context.Context, account *model.Account, req *model.PhoneLoginRequest) (_ map[string]interface{}, err error) {
+	ctx, span := tracing.Start(ctx, "Manager.LoginToWorker", tracing.KindInternal,
+		tracing.String("account.id", account.ID),
+		tracing.Int("login.signin_type", req.SigninType),
+	)
+	defer func() { span.Finish(err) }()
+
 	// 检查Worker是否存活，如果死了尝试重启
 	// 注意：这里我们使用一个较短的超时来检查，避免长时间阻塞
 	checkCtx, checkCancel := context.WithTimeout(ctx, 2*time.Second)
@@ -517,20 +996,20 @@ func (m *Manager) LoginToWorker(ctx context.Context, account *model.Account, req
 
 	// 如果账号状态显示已停止或错误，强制重启
 	if account.Status == "stopped" || account.Status == "error" {
-		log.Printf("Account %s is in %s state, restarting worker...", account.ID, account.Status)
-		if err := m.spawnWorker(account); err != nil {
-			return nil, fmt.Errorf("failed to restart worker: %v", err)
+		logging.FromContext(ctx).Info("Restarting worker before login", "account_id", account.ID, "status", account.Status)
+		if err := m.spawnWorker(ctx, account, nil); err != nil {
+			return nil, fmt.Errorf("failed to restart worker: %w", err)
 		}
 	} else {
 		// 即使状态是 running，也可能容器已经挂了（手动杀掉的情况）
 		// 尝试发一个简单的健康检查请求，如果失败则重启
 		healthURL := fmt.Sprintf("%s/api/status", account.ServiceURL)
 		healthReq, _ := http.NewRequestWithContext(checkCtx, "GET", healthURL, nil)
-		healthResp, err := http.DefaultClient.Do(healthReq)
+		healthResp, err := m.workerHTTP.Do(healthReq)
 		if err != nil {
-			log.Printf("Worker %s health check failed (%v), restarting...", account.ID, err)
-			if err := m.spawnWorker(account); err != nil {
-				return nil, fmt.Errorf("failed to restart dead worker: %v", err)
+			logging.FromContext(ctx).Warn("Worker health check failed, restarting", "account_id", account.ID, "error", err)
+			if err := m.spawnWorker(ctx, account, nil); err != nil {
+				return nil, fmt.Errorf("failed to restart dead worker: %w", err)
 			}
 		} else {
 			healthResp.Body.Close()
@@ -538,23 +1017,9 @@ func (m *Manager) LoginToWorker(ctx context.Context, account *model.Account, req
 	}
 
 	// 构造Worker登录请求
-	workerReq := map[string]interface{}{
-		"account_id":  account.ID,
-		"signin_type": req.SigninType,
-		"login_phone": req.LoginPhone,
-		"login_method": func() string {
-			if req.SigninType == 40 {
-				return "phone"
-			}
-			return "qr"
-		}(),
-		"is_cache_login":      req.CacheLogin,
-		"hardware_info":       req.HardwareInfo,
-		"socks5":              req.ProxyConfig,
-		"disable_qr_fallback": true,
-	}
+	workerReq := workerLoginRequest(account, req, m.loginProxy(ctx, account, &req.ProxyConfig), m.loginHardware(account, req.HardwareInfo))
 
-	log.Printf("Connecting to worker API: %s/api/login", account.ServiceURL)
+	logging.FromContext(ctx).Debug("Connecting to worker login API", "account_id", account.ID, "service_url", account.ServiceURL)
 
 	// 序列化请求
 	reqBody, err := json.Marshal(workerReq)
@@ -567,6 +1032,11 @@ func (m *Manager) LoginToWorker(ctx context.Context, account *model.Account, req
 	// 重试机制，因为进程启动可能需要时间
 	var resp *http.Response
 	var lastErr error
+	started := time.Now()
+	status := 0
+	defer func() {
+		m.recordMasterCall(ctx, account.ID, http.MethodPost, "/api/login", reqBody, status, started, err)
+	}()
 
 	// 增加重试次数和间隔，总共等待约 15秒 (之前是 5秒)
 	for i := 0; i < 15; i++ {
@@ -575,9 +1045,10 @@ func (m *Manager) LoginToWorker(ctx context.Context, account *model.Account, req
 			return nil, fmt.Errorf("failed to create request: %v", err)
 		}
 		httpReq.Header.Set("Content-Type", "application/json")
+		logging.InjectRequestID(httpReq)
+		tracing.Inject(ctx, httpReq.Header)
 
-		client := &http.Client{Timeout: 60 * time.Second} // 增加请求超时时间
-		resp, err = client.Do(httpReq)
+		resp, err = m.workerHTTP.Do(httpReq)
 		if err == nil {
 			break
 		}
@@ -589,6 +1060,7 @@ func (m *Manager) LoginToWorker(ctx context.Context, account *model.Account, req
 		return nil, fmt.Errorf("failed to call worker login API after retries: %v", lastErr)
 	}
 	defer resp.Body.Close()
+	status = resp.StatusCode
 
 	// 读取响应
 	respBody, err := io.ReadAll(resp.Body)
@@ -596,7 +1068,7 @@ func (m *Manager) LoginToWorker(ctx context.Context, account *model.Account, req
 		return nil, fmt.Errorf("failed to read response body: %v", err)
 	}
 
-	fmt.Printf("[LoginToWorker] Response from %s: %s\n", workerURL, string(respBody))
+	logging.FromContext(ctx).Debug("Worker login response", "account_id", account.ID, "status", resp.StatusCode, "body", string(logging.RedactJSON(respBody)))
 
 	var result map[string]interface{}
 	if err := json.Unmarshal(respBody, &result); err != nil {
@@ -608,23 +1080,29 @@ func (m *Manager) LoginToWorker(ctx context.Context, account *model.Account, req
 		return result, fmt.Errorf("worker login failed with status %d", resp.StatusCode)
 	}
 
-	// 更新账号状态
+	// 更新账号状态；手机号登录在Worker报告已登录前由登录流程跟踪，直到在手机上输入配对码
 	if success, ok := result["success"].(bool); ok && success {
-		m.UpdateAccountStatusSafe(account.ID, "logged_in")
+		loggedIn := result["is_logged_in"] == true || result["status"] == "already_logged_in" || result["status"] == "logged_in"
+		if req.SigninType != signinTypePhone || loggedIn {
+			m.UpdateAccountStatusSafe(account.ID, "logged_in", apiCause(ctx, "worker login succeeded"))
+		}
+		if req.SigninType == signinTypePhone {
+			m.startLoginFlow(account.ID, req.LoginPhone, result)
+		}
 	}
 
 	return result, nil
 }
 
-// FindAvailableWorker 查找可用的Worker
-func (m *Manager) FindAvailableWorker() *model.Account {
+// FindAvailableWorker 查找租户下可用的Worker
+func (m *Manager) FindAvailableWorker(tenantID string) *model.Account {
 	m.mutex.RLock()
 	defer m.mutex.RUnlock()
 
 	for _, account := range m.accounts {
 		// 查找没有绑定手机号的运行中的Worker
-		if account.Status == "running" && account.Phone == "" {
-			return account
+		if account.Status == "running" && account.Phone == "" && account.TenantID == tenantID && !account.Disabled {
+			return account.Clone()
 		}
 	}
 	return nil
@@ -653,6 +1131,7 @@ func (m *Manager) ReuseWorkerForPhone(ctx context.Context, workerID, phone strin
 		Status:     worker.Status,
 		Port:       worker.Port,
 		ServiceURL: worker.ServiceURL,
+		TenantID:   worker.TenantID,
 		CreatedAt:  time.Now(),
 		UpdatedAt:  time.Now(),
 	}
@@ -667,12 +1146,12 @@ func (m *Manager) ReuseWorkerForPhone(ctx context.Context, workerID, phone strin
 	// 添加到内存
 	m.accounts[phone] = newAccount
 
-	log.Printf("Worker %s reused for phone %s on port %d", workerID, phone, newAccount.Port)
-	return newAccount, nil
+	logging.FromContext(ctx).Info("Worker reused for phone", "worker_id", workerID, "phone", phone, "port", newAccount.Port)
+	return newAccount.Clone(), nil
 }
 
-// RestartWorkers 重启所有运行中或指定状态的Worker
-func (m *Manager) RestartWorkers(ctx context.Context) error {
+// RestartWorkers 在后台任务中重启所有账号的Worker
+func (m *Manager) RestartWorkers() (*model.Job, error) {
 	m.mutex.RLock()
 	accounts := make([]*model.Account, 0)
 	for _, acc := range m.accounts {
@@ -681,114 +1160,102 @@ func (m *Manager) RestartWorkers(ctx context.Context) error {
 	}
 	m.mutex.RUnlock()
 
-	log.Printf("Restarting %d workers...", len(accounts))
+	slog.Info("Restarting workers", "count", len(accounts))
 	for _, acc := range accounts {
-		log.Printf("Queuing restart for account %s (current status: %s)", acc.ID, acc.Status)
+		slog.Debug("Queuing worker restart", "account_id", acc.ID, "status", acc.Status)
 	}
 
-	for _, acc := range accounts {
-		// 异步并发重启，避免一个卡住影响所有
-		go func(account *model.Account) {
-			log.Printf("Restarting worker for account %s...", account.ID)
-
-			// 启动（spawnWorker 会自动处理旧容器清理）
-			if err := m.spawnWorker(account); err != nil {
-				log.Printf("Failed to restart worker %s: %v", account.ID, err)
-				// 标记为错误
-				m.UpdateAccountStatusSafe(account.ID, "error")
-			} else {
-				// 如果成功，spawnWorker 内部可能还没有更新状态为 running (它在 LoginToWorker 或 轮询中更新)
-				// 但 spawnWorkerDocker 调用了 waitForWorkerReady，如果返回 nil 说明服务已就绪
-				// 我们可以安全地标记为 running (或者保持原有状态，等待轮询更新)
-				// 简单起见，如果 waitForWorkerReady 通过，它就是 running
-				m.UpdateAccountStatusSafe(account.ID, "running")
-			}
-		}(acc)
-	}
-	return nil
+	return m.startJob(JobRestartWorkers, fmt.Sprintf("restart %d workers", len(accounts)), len(accounts), func(r *jobRun) error {
+		var wg sync.WaitGroup
+		var failedMutex sync.Mutex
+		failed := make([]string, 0)
+
+		for _, acc := range accounts {
+			// 并发重启，避免一个卡住影响所有
+			wg.Add(1)
+			go func(account *model.Account) {
+				defer wg.Done()
+				if r.Cancelled() || m.shuttingDown() {
+					return
+				}
+				slog.Info("Restarting worker", "account_id", account.ID)
+				if err := m.restartAccountWorker(account, "restart all workers"); err != nil {
+					slog.Error("Failed to restart worker", "account_id", account.ID, "error", err)
+					failedMutex.Lock()
+					failed = append(failed, account.ID)
+					failedMutex.Unlock()
+				}
+				r.Advance(1)
+			}(acc)
+		}
+		wg.Wait()
+
+		r.SetResult(map[string]interface{}{"restarted": len(accounts) - len(failed), "failed": failed})
+		if len(failed) > 0 {
+			return fmt.Errorf("%d of %d workers failed to restart", len(failed), len(accounts))
+		}
+		return nil
+	})
 }
 
-// RestartAccount 重启单个账号的Worker（用于更新镜像或容器重建）
-func (m *Manager) RestartAccount(ctx context.Context, accountID string) error {
+// RestartAccount 在后台任务中重启单个账号的Worker（用于更新镜像或容器重建）
+func (m *Manager) RestartAccount(accountID string) (*model.Job, error) {
 	m.mutex.RLock()
 	account, exists := m.accounts[accountID]
 	m.mutex.RUnlock()
 	if !exists {
-		return fmt.Errorf("account %s not found", accountID)
+		return nil, fmt.Errorf("account %s not found", accountID)
 	}
 
+	return m.startJob(JobRestartAccount, "restart account "+accountID, 1, func(r *jobRun) error {
+		if err := m.restartAccountWorker(account, "restart account"); err != nil {
+			return err
+		}
+		r.Advance(1)
+		return nil
+	})
+}
+
+// restartAccountWorker 重建账号的Worker并更新状态，reason记录在状态历史中
+func (m *Manager) restartAccountWorker(account *model.Account, reason string) error {
 	// 直接调用 spawnWorker，它会清理旧容器并重新启动
-	if err := m.spawnWorker(account); err != nil {
-		m.UpdateAccountStatusSafe(account.ID, "error")
-		return fmt.Errorf("failed to restart worker %s: %v", account.ID, err)
+	if err := m.spawnWorker(context.Background(), account, nil); err != nil {
+		m.UpdateAccountStatusSafe(account.ID, "error", StatusCause{Source: StatusSourceAPI, Reason: fmt.Sprintf("%s: %v", reason, err)}.withError(err))
+		return fmt.Errorf("failed to restart worker %s: %w", account.ID, err)
 	}
 
-	// 标记为运行中
-	m.UpdateAccountStatusSafe(account.ID, "running")
+	// spawnWorker 返回 nil 说明服务已就绪，标记为运行中
+	m.UpdateAccountStatusSafe(account.ID, "running", StatusCause{Source: StatusSourceAPI, Reason: reason})
+	m.resetSupervisor(account.ID)
 	return nil
 }
 
-// Close 关闭管理器
+// Close 关闭管理器，释放数据库连接
 func (m *Manager) Close() error {
-	m.mutex.Lock()
-	defer m.mutex.Unlock()
-	log.Println("Manager closed successfully")
-	return nil
-}
-
-// GetConfig 返回当前配置
-func (m *Manager) GetConfig() *config.Config {
-	m.mutex.RLock()
-	defer m.mutex.RUnlock()
-	return m.config
-}
+	if m.mockWorkers != nil {
+		m.mockWorkers.close()
+	}
 
-// UpdateConfig 更新配置（仅内存）
-func (m *Manager) UpdateConfig(input map[string]interface{}) error {
 	m.mutex.Lock()
 	defer m.mutex.Unlock()
-	if input == nil {
-		return nil
-	}
-	if serverRaw, ok := input["server"].(map[string]interface{}); ok {
-		if host, ok := serverRaw["host"].(string); ok {
-			m.config.Server.Host = host
-		}
-		if port, ok := serverRaw["port"].(float64); ok {
-			m.config.Server.Port = int(port)
-		}
-	}
-	if dockerRaw, ok := input["worker"].(map[string]interface{}); ok {
-		if mode, ok := dockerRaw["mode"].(string); ok {
-			m.config.Worker.Mode = mode
-		}
-		if network, ok := dockerRaw["network"].(string); ok {
-			m.config.Worker.Network = network
-		}
-		if image, ok := dockerRaw["image"].(string); ok {
-			m.config.Worker.Image = image
-		}
-		if basePort, ok := dockerRaw["basePort"].(float64); ok {
-			m.config.Worker.BasePort = int(basePort)
-		}
-		if portRange, ok := dockerRaw["portRange"].(float64); ok {
-			m.config.Worker.PortRange = int(portRange)
-		}
-		if namespace, ok := dockerRaw["namespace"].(string); ok {
-			m.config.Worker.Namespace = namespace
-		}
+
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database handle: %v", err)
 	}
-	if dbRaw, ok := input["db"].(map[string]interface{}); ok {
-		if typ, ok := dbRaw["type"].(string); ok {
-			m.config.DB.Type = typ
-		}
-		if name, ok := dbRaw["name"].(string); ok {
-			m.config.DB.Name = name
-		}
+	if err := sqlDB.Close(); err != nil {
+		return fmt.Errorf("failed to close database: %v", err)
 	}
+
+	slog.Info("Manager closed")
 	return nil
 }
 
+// GetConfig 返回当前配置的快照。快照发布后不再修改，热更新会换成新的快照，调用方不得修改返回值
+func (m *Manager) GetConfig() *config.Config {
+	return m.config.Load()
+}
+
 // loadExistingAccounts 加载现有账号
 func (m *Manager) loadExistingAccounts() error {
 	var accounts []*model.Account
@@ -796,9 +1263,9 @@ func (m *Manager) loadExistingAccounts() error {
 		return err
 	}
 
-	log.Printf("Loaded %d existing accounts:", len(accounts))
+	slog.Info("Loaded existing accounts", "count", len(accounts))
 	for _, account := range accounts {
-		log.Printf(" - Account %s (Status: %s)", account.ID, account.Status)
+		slog.Debug("Loaded account", "account_id", account.ID, "status", account.Status)
 		// 不重置状态为stopped，保留原始状态，以便Master重启后可以通过轮询恢复连接
 		// account.Status = "stopped"
 		// m.db.Model(account).Update("status", "stopped")
@@ -812,12 +1279,37 @@ func (m *Manager) loadExistingAccounts() error {
 
 // initDB 初始化数据库
 func initDB(cfg config.DBConfig) (*gorm.DB, error) {
+	db, err := OpenDatabase(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// 检查数据库版本并执行迁移
+	if err := prepareSchema(db, cfg.AutoMigrate); err != nil {
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	return db, nil
+}
+
+// OpenDatabase 连接数据库，不执行迁移
+func OpenDatabase(cfg config.DBConfig) (*gorm.DB, error) {
 	var db *gorm.DB
 	var err error
 
+	// 把各数据库的唯一键冲突等错误转换为 gorm.ErrDuplicatedKey 等统一错误
+	gormConfig := &gorm.Config{TranslateError: true}
 	switch cfg.Type {
 	case "sqlite":
-		db, err = gorm.Open(sqlite.Open(cfg.Name), &gorm.Config{})
+		db, err = gorm.Open(sqlite.Open(cfg.Name), gormConfig)
+	case "postgres", "postgresql":
+		db, err = gorm.Open(postgres.Open(postgresDSN(cfg)), gormConfig)
+	case "mysql":
+		db, err = gorm.Open(mysql.New(mysql.Config{
+			DSN: mysqlDSN(cfg),
+			// 带索引的字符串字段需要定长，长文本字段显式声明为text
+			DefaultStringSize: 191,
+		}), gormConfig)
 	default:
 		return nil, fmt.Errorf("unsupported database type: %s", cfg.Type)
 	}
@@ -826,10 +1318,61 @@ func initDB(cfg config.DBConfig) (*gorm.DB, error) {
 		return nil, err
 	}
 
-	// 自动迁移
-	if err := db.AutoMigrate(&model.Account{}); err != nil {
-		return nil, fmt.Errorf("failed to migrate database: %v", err)
+	// 多副本共享数据库时限制连接数
+	if cfg.Type != "sqlite" {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get database handle: %v", err)
+		}
+		sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+		sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+		sqlDB.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetime) * time.Minute)
 	}
 
 	return db, nil
 }
+
+// postgresDSN 生成PostgreSQL连接串
+func postgresDSN(cfg config.DBConfig) string {
+	if cfg.DSN != "" {
+		return cfg.DSN
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = 5432
+	}
+	parts := []string{fmt.Sprintf("host=%s port=%d", cfg.Host, port)}
+	quote := strings.NewReplacer(`\`, `\\`, `'`, `\'`)
+	for _, kv := range [][2]string{
+		{"user", cfg.User},
+		{"password", cfg.Password},
+		{"dbname", cfg.Name},
+		{"sslmode", cfg.SSLMode},
+	} {
+		if kv[1] != "" {
+			parts = append(parts, fmt.Sprintf("%s='%s'", kv[0], quote.Replace(kv[1])))
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// mysqlDSN 生成MySQL连接串
+func mysqlDSN(cfg config.DBConfig) string {
+	if cfg.DSN != "" {
+		return cfg.DSN
+	}
+	port := cfg.Port
+	if port == 0 {
+		port = 3306
+	}
+	dsn := mysqldriver.NewConfig()
+	dsn.User = cfg.User
+	dsn.Passwd = cfg.Password
+	dsn.Net = "tcp"
+	dsn.Addr = fmt.Sprintf("%s:%d", cfg.Host, port)
+	dsn.DBName = cfg.Name
+	dsn.ParseTime = true
+	dsn.Loc = time.Local
+	dsn.Params = map[string]string{"charset": "utf8mb4"}
+	return dsn.FormatDSN()
+}
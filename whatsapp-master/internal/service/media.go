@@ -0,0 +1,346 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"whatsapp-aggregator/internal/logging"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/tracing"
+)
+
+// MaxMediaSize 返回允许的单个媒体文件大小（字节）
+func (m *Manager) MaxMediaSize() int64 {
+	return int64(m.GetConfig().Media.MaxSizeMB) * 1024 * 1024
+}
+
+// errMediaURLForbidden 媒体URL指向不允许访问的地址
+var errMediaURLForbidden = errors.New("media url is not allowed")
+
+// mediaDownloadTimeout 下载媒体URL的总超时
+const mediaDownloadTimeout = 60 * time.Second
+
+// cgnatPrefix 运营商级NAT共享地址段（RFC 6598），与私有地址一样视为内网
+var cgnatPrefix = netip.MustParsePrefix("100.64.0.0/10")
+
+// newMediaHTTP 构造下载媒体URL的HTTP客户端。只允许http/https，连接建立前检查解析后的IP，
+// 因此重定向和DNS重绑定同样无法访问回环、私有、链路本地（含云元数据地址）和未指定地址；allowPrivate时不检查IP
+func newMediaHTTP(allowPrivate bool) *http.Client {
+	dialer := &net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			addr, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			if !publicMediaAddr(addr) {
+				return fmt.Errorf("%w: %s is not a public address", errMediaURLForbidden, addr)
+			}
+			return nil
+		}
+	}
+
+	return &http.Client{
+		Timeout: mediaDownloadTimeout,
+		Transport: &http.Transport{
+			// 不走环境变量中的代理，否则连接检查的是代理地址而不是目标地址
+			Proxy:                 nil,
+			DialContext:           dialer.DialContext,
+			ForceAttemptHTTP2:     true,
+			MaxIdleConns:          10,
+			IdleConnTimeout:       90 * time.Second,
+			TLSHandshakeTimeout:   10 * time.Second,
+			ResponseHeaderTimeout: 30 * time.Second,
+		},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return errors.New("stopped after 5 redirects")
+			}
+			return checkMediaURLScheme(req.URL)
+		},
+	}
+}
+
+// publicMediaAddr 地址是否为可从公网访问的单播地址
+func publicMediaAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !cgnatPrefix.Contains(addr)
+}
+
+// checkMediaURLScheme 只允许http和https协议的媒体URL
+func checkMediaURLScheme(u *url.URL) error {
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("%w: scheme %q", errMediaURLForbidden, u.Scheme)
+	}
+	return nil
+}
+
+// DownloadMedia 从URL下载媒体文件，不允许访问内网地址（MEDIA_ALLOW_PRIVATE_URLS 除外）
+func (m *Manager) DownloadMedia(ctx context.Context, mediaURL string) ([]byte, string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", mediaURL, nil)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("invalid media url: %v", err)
+	}
+	if err := checkMediaURLScheme(req.URL); err != nil {
+		return nil, "", "", err
+	}
+
+	resp, err := m.mediaHTTP.Do(req)
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to download media: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, "", "", fmt.Errorf("failed to download media: status %d", resp.StatusCode)
+	}
+
+	maxSize := m.MaxMediaSize()
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxSize+1))
+	if err != nil {
+		return nil, "", "", fmt.Errorf("failed to read media: %v", err)
+	}
+	if int64(len(data)) > maxSize {
+		return nil, "", "", fmt.Errorf("media exceeds max size of %d MB", m.GetConfig().Media.MaxSizeMB)
+	}
+
+	return data, resp.Header.Get("Content-Type"), path.Base(req.URL.Path), nil
+}
+
+// SendMedia 保存媒体文件并转发到Worker的媒体接口
+func (m *Manager) SendMedia(ctx context.Context, req *model.MediaMessageRequest, data []byte) (_ map[string]interface{}, err error) {
+	ctx, span := tracing.Start(ctx, "Manager.SendMedia", tracing.KindInternal,
+		tracing.String("account.id", req.AccountID),
+		tracing.String("message.type", req.MediaType),
+		tracing.Int("media.size", len(data)),
+	)
+	defer func() { span.Finish(err) }()
+
+	account, err := m.GetAccount(req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkAccountEnabled(req.AccountID); err != nil {
+		return nil, err
+	}
+	if err := m.checkSuppressed(req.Contact); err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > m.MaxMediaSize() {
+		return nil, fmt.Errorf("media exceeds max size of %d MB", m.GetConfig().Media.MaxSizeMB)
+	}
+	if err := m.enforceCaptionPolicies(req); err != nil {
+		return nil, err
+	}
+	if err := m.reserveSend(req.AccountID); err != nil {
+		return nil, err
+	}
+
+	mimeType := req.MimeType
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+
+	record := &model.Message{
+		ID:        generateID("msg"),
+		AccountID: req.AccountID,
+		Direction: "outbound",
+		Contact:   req.Contact,
+		Type:      req.MediaType,
+		Body:      req.Caption,
+		Status:    "sent",
+		MimeType:  mimeType,
+		FileName:  req.Filename,
+		MediaSize: int64(len(data)),
+	}
+
+	// 保存到本地，用于消息预览
+	mediaPath, err := m.storeMedia(req.AccountID, record.ID, data)
+	if err != nil {
+		return nil, err
+	}
+	record.MediaPath = mediaPath
+
+	result, err := m.deliverMedia(ctx, account, record, data, req.Voice)
+	m.recordMessage(record)
+
+	if err != nil {
+		m.deadLetter(record, req.Voice, err)
+		return result, err
+	}
+
+	m.recordMediaSent(req.AccountID, int64(len(data)))
+	withMessageID(result, record.ID)
+	logging.FromContext(ctx).Info("Media sent", "account_id", req.AccountID, "media_type", req.MediaType, "mime_type", mimeType, "bytes", len(data))
+	return result, nil
+}
+
+// deliverMedia 通过Worker投递媒体消息（失败时按策略重试），并更新消息记录的状态
+func (m *Manager) deliverMedia(ctx context.Context, account *model.Account, record *model.Message, data []byte, voice bool) (map[string]interface{}, error) {
+	result, err := m.postWithRetry(ctx, account, "/api/send-media", map[string]interface{}{
+		"contact":    record.Contact,
+		"caption":    record.Body,
+		"media_type": record.Type,
+		"media": map[string]interface{}{
+			"mimetype": record.MimeType,
+			"data":     base64.StdEncoding.EncodeToString(data),
+			"filename": record.FileName,
+			"voice":    voice,
+		},
+	}, record)
+	if err != nil {
+		record.Status = "failed"
+		record.Error = err.Error()
+		return result, err
+	}
+	record.Status = "sent"
+	record.Error = ""
+	record.WorkerMessageID = workerMessageID(result)
+	return result, nil
+}
+
+// deliverStoredMedia 读取已保存的媒体文件重新投递，用于失败消息重发
+func (m *Manager) deliverStoredMedia(ctx context.Context, account *model.Account, record *model.Message, voice bool) (map[string]interface{}, error) {
+	data, err := os.ReadFile(record.MediaPath)
+	if err != nil {
+		err = fmt.Errorf("media file is no longer available: %v", err)
+		record.Status = "failed"
+		record.Error = err.Error()
+		return nil, err
+	}
+	return m.deliverMedia(ctx, account, record, data, voice)
+}
+
+// storeMedia 将媒体按消息ID写入存储目录
+func (m *Manager) storeMedia(accountID, messageID string, data []byte) (string, error) {
+	dir := filepath.Join(m.GetConfig().Media.Dir, accountID)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create media dir: %v", err)
+	}
+
+	mediaPath := filepath.Join(dir, messageID)
+	if err := os.WriteFile(mediaPath, data, 0o600); err != nil {
+		return "", fmt.Errorf("failed to store media: %v", err)
+	}
+	return mediaPath, nil
+}
+
+// recordMediaSent 更新账号的媒体发送统计
+func (m *Manager) recordMediaSent(accountID string, size int64) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return
+	}
+
+	now := time.Now()
+	account.MessagesSent++
+	account.MediaSent++
+	account.MediaBytesSent += size
+	account.LastActivity = &now
+
+	m.db.Model(account).Updates(map[string]interface{}{
+		"messages_sent":    account.MessagesSent,
+		"media_sent":       account.MediaSent,
+		"media_bytes_sent": account.MediaBytesSent,
+		"last_activity":    account.LastActivity,
+	})
+}
+
+// mediaAccountDir 账号的媒体存储目录
+func (m *Manager) mediaAccountDir(accountID string) (string, error) {
+	if accountID == "" || accountID != filepath.Base(accountID) || strings.HasPrefix(accountID, ".") {
+		return "", fmt.Errorf("invalid account id %q", accountID)
+	}
+	return filepath.Join(m.GetConfig().Media.Dir, accountID), nil
+}
+
+// purgeMedia 删除账号保存的全部媒体文件，返回回收的字节数
+func (m *Manager) purgeMedia(accountID string) (int64, error) {
+	dir, err := m.mediaAccountDir(accountID)
+	if err != nil {
+		return 0, err
+	}
+	size := dirSize(dir)
+	if err := os.RemoveAll(dir); err != nil {
+		return 0, fmt.Errorf("failed to remove media of %s: %v", accountID, err)
+	}
+	return size, nil
+}
+
+// cleanExpiredMedia 删除保存超过 MEDIA_RETENTION_DAYS 天的媒体文件，之后这些消息无法预览或重发
+func (m *Manager) cleanExpiredMedia(report *model.JanitorReport) {
+	days := m.GetConfig().Media.RetentionDays
+	if days <= 0 {
+		return
+	}
+	root := m.GetConfig().Media.Dir
+	cutoff := time.Now().AddDate(0, 0, -days)
+
+	err := filepath.WalkDir(root, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			return nil
+		}
+		if !report.DryRun {
+			if err := os.Remove(p); err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("failed to remove media %s: %v", p, err))
+				return nil
+			}
+		}
+		report.MediaRemoved++
+		report.ReclaimedBytes += info.Size()
+		return nil
+	})
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to scan media dir: %v", err))
+		return
+	}
+
+	if report.DryRun {
+		return
+	}
+	// 删除已清空的账号目录，仍有文件的目录删除失败即保留
+	entries, err := os.ReadDir(root)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if entry.IsDir() {
+			if err := os.Remove(filepath.Join(root, entry.Name())); err == nil {
+				slog.Debug("Removed empty media dir", "account_id", entry.Name())
+			}
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/tracing"
+)
+
+// messageColumns 消息列表允许过滤和排序的字段
+var messageColumns = map[string]string{
+	"id":         "id",
+	"direction":  "direction",
+	"contact":    "contact",
+	"type":       "type",
+	"status":     "status",
+	"campaign":   "campaign",
+	"timestamp":  "timestamp",
+	"created_at": "created_at",
+}
+
+// SendMessage 通过指定账号的Worker发送文本消息
+func (m *Manager) SendMessage(ctx context.Context, req *model.MessageRequest) (_ map[string]interface{}, err error) {
+	ctx, span := tracing.Start(ctx, "Manager.SendMessage", tracing.KindInternal, tracing.String("account.id", req.AccountID))
+	defer func() { span.Finish(err) }()
+
+	account, err := m.GetAccount(req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkAccountEnabled(req.AccountID); err != nil {
+		return nil, err
+	}
+	if err := m.checkSuppressed(req.Contact); err != nil {
+		return nil, err
+	}
+	body := renderTemplate(req.Message, model.BulkRecipient{Contact: req.Contact, Variables: req.Variables})
+	if err := m.enforceContentPolicies(ctx, req, body); err != nil {
+		return nil, err
+	}
+	if err := m.reserveSend(req.AccountID); err != nil {
+		return nil, err
+	}
+	m.simulateTyping(ctx, account, req.Contact, body)
+
+	record := &model.Message{
+		ID:        generateID("msg"),
+		AccountID: req.AccountID,
+		Direction: "outbound",
+		Contact:   req.Contact,
+		Type:      "chat",
+		Body:      body,
+		Status:    "sent",
+		Campaign:  req.Campaign,
+	}
+
+	result, err := m.deliverText(ctx, account, record, req.TrackLinks)
+	m.recordMessage(record)
+
+	if err != nil {
+		m.deadLetter(record, false, err)
+		return result, err
+	}
+
+	m.recordMessageSent(req.AccountID)
+	withMessageID(result, record.ID)
+	return result, nil
+}
+
+// PreviewMessage 模拟发送：与 SendMessage 做相同的账号、停用和配额检查并渲染模板，
+// 不联系Worker、不占用额度也不保存消息记录
+func (m *Manager) PreviewMessage(req *model.MessageRequest) (*model.SendPreview, error) {
+	account, err := m.GetAccount(req.AccountID)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.checkAccountEnabled(req.AccountID); err != nil {
+		return nil, err
+	}
+	if err := m.checkSuppressed(req.Contact); err != nil {
+		return nil, err
+	}
+	if err := m.checkSendQuota(req.AccountID, false); err != nil {
+		return nil, err
+	}
+
+	m.mutex.RLock()
+	status := account.Status
+	m.mutex.RUnlock()
+
+	body := renderTemplate(req.Message, model.BulkRecipient{Contact: req.Contact, Variables: req.Variables})
+	preview := &model.SendPreview{
+		DryRun:              true,
+		AccountID:           req.AccountID,
+		AccountStatus:       status,
+		Contact:             req.Contact,
+		Message:             body,
+		Campaign:            req.Campaign,
+		UnresolvedVariables: templatePlaceholder.FindAllString(body, -1),
+	}
+	violations, err := m.CheckContent(body)
+	if err != nil {
+		return nil, err
+	}
+	preview.ContentViolations = violations
+	if m.shouldTrackLinks(req.TrackLinks) {
+		for _, link := range linkPattern.FindAllString(body, -1) {
+			preview.TrackedLinks = append(preview.TrackedLinks, strings.TrimRight(link, ".,;:!?)"))
+		}
+	}
+	if quota, err := m.GetSendQuota(req.AccountID); err == nil {
+		preview.Quota = quota
+	}
+	return preview, nil
+}
+
+// deliverText 通过Worker投递文本消息（失败时按策略重试），并更新消息记录的状态
+func (m *Manager) deliverText(ctx context.Context, account *model.Account, record *model.Message, trackLinks *bool) (map[string]interface{}, error) {
+	// 按需改写链接用于点击跟踪
+	body := record.Body
+	var links []*model.TrackedLink
+	if m.shouldTrackLinks(trackLinks) {
+		body, links = m.rewriteLinks(record, body)
+	}
+
+	result, err := m.postWithRetry(ctx, account, "/api/send-message", map[string]string{
+		"contact": record.Contact,
+		"message": body,
+	}, record)
+	if err != nil {
+		record.Status = "failed"
+		record.Error = err.Error()
+		m.discardTrackedLinks(links)
+		return result, err
+	}
+
+	record.Status = "sent"
+	record.Error = ""
+	record.WorkerMessageID = workerMessageID(result)
+	return result, nil
+}
+
+// ListMessages 从数据库分页查询账号的消息历史
+func (m *Manager) ListMessages(accountID string, q *model.ListQuery) ([]*model.Message, int64, error) {
+	if _, err := m.GetAccount(accountID); err != nil {
+		return nil, 0, err
+	}
+
+	messages := make([]*model.Message, 0)
+	db := m.db.Model(&model.Message{}).Where("account_id = ?", accountID)
+	total, err := findWithListQuery(db, q, messageColumns, "-timestamp", &messages)
+	if err != nil {
+		return nil, 0, err
+	}
+	return messages, total, nil
+}
+
+// SyncMessages 从Worker拉取最近的入站消息并去重写入数据库，返回新增条数
+func (m *Manager) SyncMessages(ctx context.Context, accountID string) (int, error) {
+	result, err := m.FetchFromWorker(ctx, accountID, "/api/messages")
+	if err != nil {
+		return 0, err
+	}
+
+	items, _ := result["data"].([]interface{})
+	messages := make([]model.WorkerMessage, 0, len(items))
+	for _, item := range items {
+		raw, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		msg := model.WorkerMessage{}
+		msg.ID, _ = raw["id"].(string)
+		msg.From, _ = raw["from"].(string)
+		msg.Body, _ = raw["body"].(string)
+		msg.Type, _ = raw["type"].(string)
+		if ts, ok := raw["timestamp"].(float64); ok {
+			msg.Timestamp = int64(ts)
+		}
+		messages = append(messages, msg)
+	}
+	return m.storeInboundMessages(accountID, messages), nil
+}
+
+// storeInboundMessages 按Worker消息ID去重保存入站消息，检测退订并匹配自动回复，返回新增条数；
+// 轮询和Worker回调收到的同一条消息只保存一次
+func (m *Manager) storeInboundMessages(accountID string, messages []model.WorkerMessage) int {
+	lock := m.messageSyncLock(accountID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	added := 0
+	for _, msg := range messages {
+		timestamp := time.Now()
+		if msg.Timestamp > 0 {
+			timestamp = time.UnixMilli(msg.Timestamp)
+		}
+		workerID := msg.ID
+		if workerID == "" {
+			workerID = fmt.Sprintf("%s-%d", msg.From, timestamp.UnixMilli())
+		}
+
+		var count int64
+		m.db.Model(&model.Message{}).
+			Where("account_id = ? AND worker_message_id = ?", accountID, workerID).
+			Count(&count)
+		if count > 0 {
+			continue
+		}
+
+		inbound := &model.Message{
+			AccountID:       accountID,
+			Direction:       "inbound",
+			Contact:         msg.From,
+			Type:            msg.Type,
+			Body:            msg.Body,
+			Status:          "received",
+			WorkerMessageID: workerID,
+			Timestamp:       timestamp,
+		}
+		m.recordMessage(inbound)
+		m.detectOptOut(inbound)
+		m.evaluateAutoReply(inbound)
+		added++
+	}
+
+	if added > 0 {
+		m.recordMessagesReceived(accountID, added)
+	}
+	return added
+}
+
+// recordMessage 保存消息记录
+func (m *Manager) recordMessage(msg *model.Message) {
+	if msg.ID == "" {
+		msg.ID = generateID("msg")
+	}
+	if msg.Timestamp.IsZero() {
+		msg.Timestamp = time.Now()
+	}
+	msg.Preview = messagePreview(msg.Type, msg.Body)
+
+	if err := m.db.Create(msg).Error; err != nil {
+		slog.Error("Failed to record message", "account_id", msg.AccountID, "error", err)
+	}
+	m.recordDailyMessage(msg)
+	m.emitMessage(msg)
+}
+
+// recordMessageSent 更新账号的发送统计
+func (m *Manager) recordMessageSent(accountID string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return
+	}
+
+	now := time.Now()
+	account.MessagesSent++
+	account.LastActivity = &now
+
+	m.db.Model(account).Updates(map[string]interface{}{
+		"messages_sent": account.MessagesSent,
+		"last_activity": account.LastActivity,
+	})
+}
+
+// recordMessagesReceived 更新账号的接收统计
+func (m *Manager) recordMessagesReceived(accountID string, count int) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return
+	}
+
+	now := time.Now()
+	account.MessagesReceived += count
+	account.LastActivity = &now
+
+	m.db.Model(account).Updates(map[string]interface{}{
+		"messages_received": account.MessagesReceived,
+		"last_activity":     account.LastActivity,
+	})
+}
+
+// workerMessageID 从Worker发送结果中提取消息ID
+func workerMessageID(result map[string]interface{}) string {
+	data, ok := result["data"].(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	if id, ok := data["id"].(map[string]interface{}); ok {
+		if serialized, ok := id["_serialized"].(string); ok {
+			return serialized
+		}
+	}
+	if id, ok := data["id"].(string); ok {
+		return id
+	}
+	return ""
+}
+
+// withMessageID 在Worker的发送结果中附上Master分配的消息ID，用于查询投递状态
+func withMessageID(result map[string]interface{}, messageID string) {
+	if data, ok := result["data"].(map[string]interface{}); ok {
+		data["message_id"] = messageID
+		return
+	}
+	if result != nil {
+		result["data"] = map[string]interface{}{"message_id": messageID}
+	}
+}
+
+// messagePreview 生成消息预览文本
+func messagePreview(msgType, body string) string {
+	const maxLen = 100
+
+	preview := body
+	if utf8.RuneCountInString(preview) > maxLen {
+		preview = string([]rune(preview)[:maxLen]) + "..."
+	}
+	if msgType != "" && msgType != "chat" {
+		if preview == "" {
+			return fmt.Sprintf("[%s]", msgType)
+		}
+		return fmt.Sprintf("[%s] %s", msgType, preview)
+	}
+	return preview
+}
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"sort"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// CampaignMetrics 汇总各活动的排队、发送中、已发送、失败和退订数
+func (m *Manager) CampaignMetrics() ([]*model.CampaignMetrics, error) {
+	byCampaign := make(map[string]*model.CampaignMetrics)
+	get := func(campaign string) *model.CampaignMetrics {
+		metrics, exists := byCampaign[campaign]
+		if !exists {
+			metrics = &model.CampaignMetrics{Campaign: campaign}
+			byCampaign[campaign] = metrics
+		}
+		return metrics
+	}
+
+	// 运行中批次的实时进度
+	m.bulkMutex.RLock()
+	for _, batch := range m.bulkBatches {
+		if batch.Status != "running" || batch.Campaign == "" {
+			continue
+		}
+		metrics := get(batch.Campaign)
+		for _, result := range batch.Results {
+			switch result.Status {
+			case "pending":
+				metrics.Queued++
+			case "sending":
+				metrics.InFlight++
+			}
+		}
+	}
+	m.bulkMutex.RUnlock()
+
+	// 运行中活动的排队和发送中收件人
+	var queued []struct {
+		Name   string
+		Status string
+		Count  int64
+	}
+	if err := m.db.Model(&model.CampaignRecipient{}).
+		Select("campaigns.name AS name, campaign_recipients.status AS status, COUNT(*) AS count").
+		Joins("JOIN campaigns ON campaigns.id = campaign_recipients.campaign_id").
+		Where("campaigns.status = ? AND campaign_recipients.status IN ?", CampaignRunning, []string{"pending", "sending"}).
+		Group("campaigns.name, campaign_recipients.status").
+		Scan(&queued).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range queued {
+		if row.Status == "pending" {
+			get(row.Name).Queued += int(row.Count)
+		} else {
+			get(row.Name).InFlight += int(row.Count)
+		}
+	}
+
+	// 发送结果来自消息历史，重启后不丢失
+	var rows []struct {
+		Campaign string
+		Status   string
+		Count    int64
+	}
+	if err := m.db.Model(&model.Message{}).
+		Select("campaign, status, COUNT(*) AS count").
+		Where("direction = ? AND campaign <> ?", "outbound", "").
+		Group("campaign, status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		switch row.Status {
+		case "sent":
+			get(row.Campaign).Sent += row.Count
+		case "failed":
+			get(row.Campaign).Failed += row.Count
+		}
+	}
+
+	var optOuts []struct {
+		Campaign string
+		Count    int64
+	}
+	if err := m.db.Model(&model.OptOut{}).
+		Select("campaign, COUNT(*) AS count").
+		Where("campaign <> ?", "").
+		Group("campaign").
+		Scan(&optOuts).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range optOuts {
+		get(row.Campaign).OptOuts = row.Count
+	}
+
+	result := make([]*model.CampaignMetrics, 0, len(byCampaign))
+	for _, metrics := range byCampaign {
+		result = append(result, metrics)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Campaign < result[j].Campaign
+	})
+	return result, nil
+}
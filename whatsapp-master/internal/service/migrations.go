@@ -0,0 +1,502 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-gormigrate/gormigrate/v2"
+	"gorm.io/gorm"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// ErrSchemaTooNew 数据库已由更新版本的程序迁移，当前程序不能在其上运行
+var ErrSchemaTooNew = errors.New("database schema is newer than this build")
+
+// migration 一个版本化的数据库迁移；Down 为空表示不可回滚
+//
+// 迁移由 gormigrate 按顺序执行，每个迁移与其记录在同一事务中提交。新增表或字段时在 migrations 末尾追加版本，
+// 不要修改已发布的迁移。迁移只使用 migrations_schema.go 中该版本的表结构快照或显式SQL，不引用 internal/model 的模型，
+// 这样新库和逐版本升级的旧库得到相同的表结构。改用快照之前的新库由 baseline 按当时的模型建表，
+// 后续迁移的表或字段可能已存在，因此仍使用 createTables、addColumns 等可重复执行的写法。
+type migration struct {
+	Version int
+	Name    string
+	Up      func(tx *gorm.DB) error
+	Down    func(tx *gorm.DB) error
+}
+
+// id gormigrate 中的迁移ID
+func (mig migration) id() string {
+	return fmt.Sprintf("%04d_%s", mig.Version, mig.Name)
+}
+
+// migrations 按版本递增排列
+var migrations = []migration{
+	{
+		Version: 1,
+		Name:    "baseline",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx,
+				&accountV1{},
+				&messageV1{},
+				&trackedLinkV1{},
+				&linkClickV1{},
+				&conversationV1{},
+				&contactV1{},
+				&groupV1{},
+				&statusTransitionV1{},
+				&auditEntryV1{},
+				&optOutV1{},
+				&campaignV1{},
+				&campaignRecipientV1{},
+				&jobV1{},
+				&tenantV1{},
+				&tenantAPIKeyV1{},
+				&diagnosticBundleV1{},
+				&diagnosticFileV1{},
+				&hostV1{},
+				&configOverrideV1{},
+				&webhookV1{},
+				&alertChannelV1{},
+				&alertRuleV1{},
+				&accountDailyStatsV1{},
+				&idempotencyKeyV1{},
+				&deadLetterV1{},
+			)
+		},
+	},
+	{
+		Version: 2,
+		Name:    "auto_replies",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &autoReplyRuleV2{}, &autoReplyLogV2{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&autoReplyLogV2{}, &autoReplyRuleV2{})
+		},
+	},
+	{
+		Version: 3,
+		Name:    "account_status_poll_seconds",
+		Up: func(tx *gorm.DB) error {
+			return addColumns(tx, &accountV3{}, "status_poll_seconds")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&accountV3{}, "status_poll_seconds")
+		},
+	},
+	{
+		Version: 4,
+		Name:    "webhook_filters",
+		Up: func(tx *gorm.DB) error {
+			return addColumns(tx, &webhookV4{}, webhookFilterColumns...)
+		},
+		Down: func(tx *gorm.DB) error {
+			for _, column := range webhookFilterColumns {
+				if err := tx.Migrator().DropColumn(&webhookV4{}, column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 5,
+		Name:    "leader_leases",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &leaderLeaseV5{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&leaderLeaseV5{})
+		},
+	},
+	{
+		Version: 6,
+		Name:    "host_region",
+		Up: func(tx *gorm.DB) error {
+			return addColumns(tx, &hostV6{}, "region")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&hostV6{}, "region")
+		},
+	},
+	{
+		Version: 7,
+		Name:    "ban_records",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &banRecordV7{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&banRecordV7{})
+		},
+	},
+	{
+		Version: 8,
+		Name:    "session_backups",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &sessionBackupV8{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&sessionBackupV8{})
+		},
+	},
+	{
+		Version: 9,
+		Name:    "content_policies",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &contentPolicyV9{}, &moderationItemV9{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&contentPolicyV9{}, &moderationItemV9{})
+		},
+	},
+	{
+		Version: 10,
+		Name:    "approvals",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &approvalV10{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&approvalV10{})
+		},
+	},
+	{
+		Version: 11,
+		Name:    "opt_out_source",
+		Up: func(tx *gorm.DB) error {
+			if err := addColumns(tx, &optOutV11{}, "source", "reason"); err != nil {
+				return err
+			}
+			// AddColumn 不建索引，baseline 按模型建表的库已有该索引
+			if !tx.Migrator().HasIndex(&optOutV11{}, "idx_opt_outs_source") {
+				if err := tx.Migrator().CreateIndex(&optOutV11{}, "idx_opt_outs_source"); err != nil {
+					return err
+				}
+			}
+			// 之前的退订记录都来自关键字回复
+			return tx.Exec("UPDATE opt_outs SET source = ? WHERE source IS NULL OR source = ?", "keyword", "").Error
+		},
+		Down: func(tx *gorm.DB) error {
+			if tx.Migrator().HasIndex(&optOutV11{}, "idx_opt_outs_source") {
+				if err := tx.Migrator().DropIndex(&optOutV11{}, "idx_opt_outs_source"); err != nil {
+					return err
+				}
+			}
+			for _, column := range []string{"source", "reason"} {
+				if err := tx.Migrator().DropColumn(&optOutV11{}, column); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	},
+	{
+		Version: 12,
+		Name:    "status_history_details",
+		Up: func(tx *gorm.DB) error {
+			return addColumns(tx, &statusTransitionV12{}, "details")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&statusTransitionV12{}, "details")
+		},
+	},
+	{
+		Version: 13,
+		Name:    "worker_calls",
+		Up: func(tx *gorm.DB) error {
+			return createTables(tx, &workerCallV13{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&workerCallV13{})
+		},
+	},
+	{
+		Version: 14,
+		Name:    "hardware_profiles",
+		Up: func(tx *gorm.DB) error {
+			if err := createTables(tx, &hardwareProfileV14{}); err != nil {
+				return err
+			}
+			return addColumns(tx, &accountV14{}, "hardware_profile_id")
+		},
+		Down: func(tx *gorm.DB) error {
+			if err := tx.Migrator().DropColumn(&accountV14{}, "hardware_profile_id"); err != nil {
+				return err
+			}
+			return tx.Migrator().DropTable(&hardwareProfileV14{})
+		},
+	},
+}
+
+// webhookFilterColumns 迁移4为Webhook添加的过滤、媒体和重试字段
+var webhookFilterColumns = []string{
+	"filter_account_ids", "filter_directions", "filter_contacts", "filter_keywords",
+	"include_media", "retry_max_attempts", "retry_backoff_ms", "retry_max_backoff_ms",
+}
+
+// migrateMutex 同一进程内串行执行迁移；多副本同时迁移时由 schema_versions 主键冲突使后执行的事务回滚
+var migrateMutex sync.Mutex
+
+// createTables 创建不存在的表
+func createTables(tx *gorm.DB, models ...interface{}) error {
+	for _, value := range models {
+		if tx.Migrator().HasTable(value) {
+			continue
+		}
+		if err := tx.Migrator().CreateTable(value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addColumns 为表添加不存在的字段，fields 为快照的字段名或列名
+func addColumns(tx *gorm.DB, value interface{}, fields ...string) error {
+	for _, field := range fields {
+		if tx.Migrator().HasColumn(value, field) {
+			continue
+		}
+		if err := tx.Migrator().AddColumn(value, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// latestMigrationVersion 当前程序包含的最高迁移版本
+func latestMigrationVersion() int {
+	return migrations[len(migrations)-1].Version
+}
+
+// newGormigrate 按 migrations 构造 gormigrate，每次执行或回滚使用单独的事务
+func newGormigrate(db *gorm.DB) *gormigrate.Gormigrate {
+	list := make([]*gormigrate.Migration, 0, len(migrations))
+	for _, mig := range migrations {
+		list = append(list, &gormigrate.Migration{ID: mig.id(), Migrate: mig.Up, Rollback: mig.Down})
+	}
+	return gormigrate.New(db, &gormigrate.Options{
+		TableName:      model.SchemaMigration{}.TableName(),
+		IDColumnName:   "id",
+		IDColumnSize:   255,
+		UseTransaction: true,
+	}, list)
+}
+
+// migrationVersion 从迁移ID中解析版本号，不是本程序格式的ID返回false
+func migrationVersion(id string) (int, bool) {
+	prefix, _, ok := strings.Cut(id, "_")
+	if !ok {
+		return 0, false
+	}
+	version, err := strconv.Atoi(prefix)
+	return version, err == nil
+}
+
+// ensureMigrationTable 创建迁移记录表，并导入改用 gormigrate 之前 schema_migrations 中的记录
+func ensureMigrationTable(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if migrator.HasTable(&model.SchemaMigration{}) {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Migrator().CreateTable(&model.SchemaMigration{}); err != nil {
+			return err
+		}
+		if !tx.Migrator().HasTable(&model.LegacySchemaMigration{}) {
+			return nil
+		}
+		var legacy []model.LegacySchemaMigration
+		if err := tx.Order("version ASC").Find(&legacy).Error; err != nil {
+			return err
+		}
+		for _, row := range legacy {
+			appliedAt := row.AppliedAt
+			id := migration{Version: row.Version, Name: row.Name}.id()
+			if err := tx.Create(&model.SchemaMigration{ID: id, AppliedAt: &appliedAt}).Error; err != nil {
+				return err
+			}
+		}
+		if len(legacy) > 0 {
+			slog.Info("Imported migration history from schema_migrations", "migrations", len(legacy))
+		}
+		return nil
+	})
+}
+
+// appliedMigrations 读取已执行的迁移，按版本索引
+func appliedMigrations(db *gorm.DB) (map[int]model.SchemaMigration, error) {
+	if err := ensureMigrationTable(db); err != nil {
+		return nil, fmt.Errorf("failed to create migrations table: %v", err)
+	}
+	var rows []model.SchemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to read migrations table: %v", err)
+	}
+	applied := make(map[int]model.SchemaMigration, len(rows))
+	for _, row := range rows {
+		if version, ok := migrationVersion(row.ID); ok {
+			applied[version] = row
+		}
+	}
+	return applied, nil
+}
+
+// schemaStatus 汇总已执行和待执行的迁移
+func schemaStatus(applied map[int]model.SchemaMigration) *model.SchemaStatus {
+	status := &model.SchemaStatus{LatestVersion: latestMigrationVersion(), Migrations: make([]model.MigrationStatus, 0, len(migrations))}
+	for version := range applied {
+		status.CurrentVersion = max(status.CurrentVersion, version)
+	}
+	for _, mig := range migrations {
+		item := model.MigrationStatus{Version: mig.Version, Name: mig.Name, Reversible: mig.Down != nil}
+		if row, ok := applied[mig.Version]; ok {
+			item.Applied = true
+			item.AppliedAt = row.AppliedAt
+		} else {
+			status.Pending++
+		}
+		status.Migrations = append(status.Migrations, item)
+	}
+	return status
+}
+
+// checkSchemaVersion 数据库版本高于当前程序时返回 ErrSchemaTooNew
+func checkSchemaVersion(status *model.SchemaStatus) error {
+	if status.CurrentVersion > status.LatestVersion {
+		return fmt.Errorf("%w: database is at version %d, this build supports up to %d", ErrSchemaTooNew, status.CurrentVersion, status.LatestVersion)
+	}
+	return nil
+}
+
+// SchemaStatusOf 查询数据库的迁移状态
+func SchemaStatusOf(db *gorm.DB) (*model.SchemaStatus, error) {
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+	return schemaStatus(applied), nil
+}
+
+// MigrateUp 按版本顺序执行所有待执行的迁移，每个迁移与其版本记录在同一事务中提交；
+// 数据库版本高于当前程序时拒绝执行
+func MigrateUp(db *gorm.DB) ([]model.MigrationStatus, error) {
+	migrateMutex.Lock()
+	defer migrateMutex.Unlock()
+
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSchemaVersion(schemaStatus(applied)); err != nil {
+		return nil, err
+	}
+
+	g := newGormigrate(db)
+	done := make([]model.MigrationStatus, 0)
+	for _, mig := range migrations {
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+		start := time.Now()
+		if err := g.MigrateTo(mig.id()); err != nil {
+			return done, fmt.Errorf("migration %d (%s) failed: %v", mig.Version, mig.Name, err)
+		}
+		if err := db.Model(&model.SchemaMigration{}).Where("id = ?", mig.id()).Update("applied_at", start).Error; err != nil {
+			slog.Warn("Failed to record migration time", "version", mig.Version, "error", err)
+		}
+		slog.Info("Database migration applied", "version", mig.Version, "name", mig.Name, "duration", time.Since(start))
+		done = append(done, model.MigrationStatus{Version: mig.Version, Name: mig.Name, Applied: true, AppliedAt: &start, Reversible: mig.Down != nil})
+	}
+	return done, nil
+}
+
+// MigrateDown 从最高版本开始回滚 steps 个已执行的迁移，遇到不可回滚的迁移时停止
+func MigrateDown(db *gorm.DB, steps int) ([]model.MigrationStatus, error) {
+	migrateMutex.Lock()
+	defer migrateMutex.Unlock()
+
+	applied, err := appliedMigrations(db)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSchemaVersion(schemaStatus(applied)); err != nil {
+		return nil, err
+	}
+
+	g := newGormigrate(db)
+	done := make([]model.MigrationStatus, 0)
+	for i := len(migrations) - 1; i >= 0 && len(done) < max(steps, 1); i-- {
+		mig := migrations[i]
+		if _, ok := applied[mig.Version]; !ok {
+			continue
+		}
+		if mig.Down == nil {
+			return done, fmt.Errorf("migration %d (%s) cannot be rolled back", mig.Version, mig.Name)
+		}
+		err := g.RollbackMigration(&gormigrate.Migration{ID: mig.id(), Rollback: mig.Down})
+		if err != nil {
+			return done, fmt.Errorf("rollback of migration %d (%s) failed: %v", mig.Version, mig.Name, err)
+		}
+		slog.Warn("Database migration rolled back", "version", mig.Version, "name", mig.Name)
+		done = append(done, model.MigrationStatus{Version: mig.Version, Name: mig.Name, Reversible: true})
+	}
+	return done, nil
+}
+
+// prepareSchema 启动时检查数据库版本：高于当前程序时拒绝启动，DB_AUTO_MIGRATE 开启时执行待执行的迁移，否则只记录警告
+func prepareSchema(db *gorm.DB, autoMigrate bool) error {
+	status, err := SchemaStatusOf(db)
+	if err != nil {
+		return err
+	}
+	if err := checkSchemaVersion(status); err != nil {
+		return err
+	}
+	if status.Pending == 0 {
+		return nil
+	}
+	if !autoMigrate {
+		slog.Warn("Database has pending migrations, run `server migrate up` or POST /api/v1/system/migrations/up",
+			"current_version", status.CurrentVersion, "latest_version", status.LatestVersion, "pending", status.Pending)
+		return nil
+	}
+	_, err = MigrateUp(db)
+	return err
+}
+
+// SchemaStatus 查询数据库的迁移状态
+func (m *Manager) SchemaStatus() (*model.SchemaStatus, error) {
+	return SchemaStatusOf(m.db)
+}
+
+// ApplyMigrations 执行待执行的迁移
+func (m *Manager) ApplyMigrations() (*model.MigrationResult, error) {
+	applied, err := MigrateUp(m.db)
+	if err != nil {
+		return nil, err
+	}
+	status, err := m.SchemaStatus()
+	if err != nil {
+		return nil, err
+	}
+	return &model.MigrationResult{Applied: applied, Schema: status}, nil
+}
+
+// RollbackMigrations 回滚最近执行的迁移，回滚后运行中的程序可能访问已删除的表或字段
+func (m *Manager) RollbackMigrations(steps int) (*model.MigrationResult, error) {
+	rolledBack, err := MigrateDown(m.db, steps)
+	if err != nil {
+		return nil, err
+	}
+	status, err := m.SchemaStatus()
+	if err != nil {
+		return nil, err
+	}
+	return &model.MigrationResult{RolledBack: rolledBack, Schema: status}, nil
+}
@@ -0,0 +1,809 @@
+package service
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// 各版本迁移使用的表结构快照。
+//
+// 迁移只能引用这里的快照而不是 internal/model 中的模型，否则修改模型会改变已发布迁移在新库上建出的表。
+// 快照与其迁移一起冻结，新增字段或表时追加新版本的快照，不要修改已有的快照。
+
+// accountV1 迁移1中的accounts表
+type accountV1 struct {
+	ID                  string         `gorm:"column:id;primaryKey"`
+	Name                string         `gorm:"column:name"`
+	Notes               string         `gorm:"column:notes;type:text"`
+	Phone               string         `gorm:"column:phone"`
+	Status              string         `gorm:"column:status"`
+	ServiceURL          string         `gorm:"column:service_url"`
+	ContainerID         string         `gorm:"column:container_id"`
+	PodName             string         `gorm:"column:pod_name"`
+	HostID              string         `gorm:"column:host_id;index"`
+	Port                int            `gorm:"column:port"`
+	Tags                string         `gorm:"column:tags;type:text"`
+	Pool                string         `gorm:"column:pool;index"`
+	TenantID            string         `gorm:"column:tenant_id;index"`
+	ProxyRegion         string         `gorm:"column:proxy_region"`
+	ProxyIP             string         `gorm:"column:proxy_ip"`
+	ProxyPort           int            `gorm:"column:proxy_port"`
+	ProxyUsername       string         `gorm:"column:proxy_username"`
+	ProxyPassword       string         `gorm:"column:proxy_password;size:512"`
+	ProxyProtocol       string         `gorm:"column:proxy_protocol"`
+	OwnerOperator       string         `gorm:"column:owner_operator;index"`
+	OwnerTeam           string         `gorm:"column:owner_team;index"`
+	OwnerEmail          string         `gorm:"column:owner_email"`
+	OwnerChannel        string         `gorm:"column:owner_channel"`
+	MessagesSent        int            `gorm:"column:messages_sent"`
+	MessagesReceived    int            `gorm:"column:messages_received"`
+	MediaSent           int            `gorm:"column:media_sent"`
+	MediaBytesSent      int64          `gorm:"column:media_bytes_sent"`
+	LastActivity        *time.Time     `gorm:"column:last_activity"`
+	SessionStartedAt    *time.Time     `gorm:"column:session_started_at"`
+	SessionDrops        int            `gorm:"column:session_drops"`
+	AvgSessionHours     float64        `gorm:"column:avg_session_hours"`
+	SessionRefreshAt    *time.Time     `gorm:"column:session_refresh_at"`
+	KeepAliveOff        bool           `gorm:"column:keep_alive_off"`
+	RestartCount        int            `gorm:"column:restart_count"`
+	LastRestartAt       *time.Time     `gorm:"column:last_restart_at"`
+	WorkerToken         string         `gorm:"column:worker_token;size:512"`
+	WorkerVersion       string         `gorm:"column:worker_version"`
+	WorkerAPIVersion    int            `gorm:"column:worker_api_version"`
+	WorkerIncompatible  bool           `gorm:"column:worker_incompatible"`
+	SendLimit           int            `gorm:"column:send_limit"`
+	SendLimitBurst      int            `gorm:"column:send_limit_burst"`
+	WorkerMemory        string         `gorm:"column:worker_memory"`
+	WorkerCpus          string         `gorm:"column:worker_cpus"`
+	WorkerPidsLimit     int            `gorm:"column:worker_pids_limit"`
+	WorkerRestartPolicy string         `gorm:"column:worker_restart_policy"`
+	WorkerEnv           string         `gorm:"column:worker_env;type:text"`
+	WorkerVolumes       string         `gorm:"column:worker_volumes;type:text"`
+	WorkerDns           string         `gorm:"column:worker_dns;type:text"`
+	Disabled            bool           `gorm:"column:disabled;index"`
+	DisabledReason      string         `gorm:"column:disabled_reason"`
+	DisabledAt          *time.Time     `gorm:"column:disabled_at"`
+	WarmupProfile       string         `gorm:"column:warmup_profile"`
+	WarmupStartedAt     *time.Time     `gorm:"column:warmup_started_at"`
+	TypingSimulation    bool           `gorm:"column:typing_simulation"`
+	SessionPurgedAt     *time.Time     `gorm:"column:session_purged_at"`
+	CreatedAt           time.Time      `gorm:"column:created_at"`
+	UpdatedAt           time.Time      `gorm:"column:updated_at"`
+	DeletedAt           gorm.DeletedAt `gorm:"column:deleted_at;index"`
+}
+
+// TableName 指定表名
+func (accountV1) TableName() string {
+	return "accounts"
+}
+
+// messageV1 迁移1中的messages表
+type messageV1 struct {
+	ID              string     `gorm:"column:id;primaryKey"`
+	AccountID       string     `gorm:"column:account_id;index"`
+	Direction       string     `gorm:"column:direction;index"`
+	Contact         string     `gorm:"column:contact;index"`
+	Type            string     `gorm:"column:type"`
+	Body            string     `gorm:"column:body;type:text"`
+	Preview         string     `gorm:"column:preview"`
+	MimeType        string     `gorm:"column:mime_type"`
+	FileName        string     `gorm:"column:file_name"`
+	MediaSize       int64      `gorm:"column:media_size"`
+	MediaPath       string     `gorm:"column:media_path"`
+	Status          string     `gorm:"column:status;index"`
+	Campaign        string     `gorm:"column:campaign;index"`
+	Error           string     `gorm:"column:error;type:text"`
+	Attempts        int        `gorm:"column:attempts"`
+	WorkerMessageID string     `gorm:"column:worker_message_id;index"`
+	Timestamp       time.Time  `gorm:"column:timestamp;index"`
+	ReadAt          *time.Time `gorm:"column:read_at;index"`
+	DeliveredAt     *time.Time `gorm:"column:delivered_at"`
+	SeenAt          *time.Time `gorm:"column:seen_at"`
+	CreatedAt       time.Time  `gorm:"column:created_at"`
+	UpdatedAt       time.Time  `gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (messageV1) TableName() string {
+	return "messages"
+}
+
+// trackedLinkV1 迁移1中的tracked_links表
+type trackedLinkV1 struct {
+	ID             string     `gorm:"column:id;primaryKey"`
+	MessageID      string     `gorm:"column:message_id;index"`
+	AccountID      string     `gorm:"column:account_id;index"`
+	Campaign       string     `gorm:"column:campaign;index"`
+	Contact        string     `gorm:"column:contact"`
+	URL            string     `gorm:"column:url;type:text"`
+	Clicks         int        `gorm:"column:clicks"`
+	FirstClickedAt *time.Time `gorm:"column:first_clicked_at"`
+	LastClickedAt  *time.Time `gorm:"column:last_clicked_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at"`
+}
+
+// TableName 指定表名
+func (trackedLinkV1) TableName() string {
+	return "tracked_links"
+}
+
+// linkClickV1 迁移1中的link_clicks表
+type linkClickV1 struct {
+	ID        uint      `gorm:"column:id;primaryKey"`
+	LinkID    string    `gorm:"column:link_id;index"`
+	IP        string    `gorm:"column:ip"`
+	UserAgent string    `gorm:"column:user_agent;type:text"`
+	ClickedAt time.Time `gorm:"column:clicked_at"`
+}
+
+// TableName 指定表名
+func (linkClickV1) TableName() string {
+	return "link_clicks"
+}
+
+// conversationV1 迁移1中的conversations表
+type conversationV1 struct {
+	ID         uint       `gorm:"column:id;primaryKey"`
+	AccountID  string     `gorm:"column:account_id;uniqueIndex:idx_conversation_account_contact"`
+	Contact    string     `gorm:"column:contact;uniqueIndex:idx_conversation_account_contact"`
+	HandledBy  string     `gorm:"column:handled_by;index"`
+	AgentID    string     `gorm:"column:agent_id;index"`
+	Note       string     `gorm:"column:note;type:text"`
+	AssignedAt *time.Time `gorm:"column:assigned_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (conversationV1) TableName() string {
+	return "conversations"
+}
+
+// contactV1 迁移1中的contacts表
+type contactV1 struct {
+	ID          uint      `gorm:"column:id;primaryKey"`
+	AccountID   string    `gorm:"column:account_id;uniqueIndex:idx_contact_account_wid"`
+	Wid         string    `gorm:"column:wid;uniqueIndex:idx_contact_account_wid"`
+	Number      string    `gorm:"column:number;index"`
+	Name        string    `gorm:"column:name"`
+	PushName    string    `gorm:"column:push_name"`
+	IsGroup     bool      `gorm:"column:is_group"`
+	IsMyContact bool      `gorm:"column:is_my_contact"`
+	SyncedAt    time.Time `gorm:"column:synced_at"`
+	CreatedAt   time.Time `gorm:"column:created_at"`
+	UpdatedAt   time.Time `gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (contactV1) TableName() string {
+	return "contacts"
+}
+
+// groupV1 迁移1中的groups表
+type groupV1 struct {
+	ID               uint      `gorm:"column:id;primaryKey"`
+	AccountID        string    `gorm:"column:account_id;uniqueIndex:idx_group_account_gid"`
+	Gid              string    `gorm:"column:gid;uniqueIndex:idx_group_account_gid"`
+	Name             string    `gorm:"column:name"`
+	Description      string    `gorm:"column:description;type:text"`
+	Owner            string    `gorm:"column:owner"`
+	Participants     string    `gorm:"column:participants;type:text"`
+	ParticipantCount int       `gorm:"column:participant_count"`
+	InviteLink       string    `gorm:"column:invite_link"`
+	SyncedAt         time.Time `gorm:"column:synced_at"`
+	CreatedAt        time.Time `gorm:"column:created_at"`
+	UpdatedAt        time.Time `gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (groupV1) TableName() string {
+	return "groups"
+}
+
+// statusTransitionV1 迁移1中的status_history表
+type statusTransitionV1 struct {
+	ID        uint      `gorm:"column:id;primaryKey"`
+	AccountID string    `gorm:"column:account_id;index"`
+	Previous  string    `gorm:"column:previous"`
+	Status    string    `gorm:"column:status"`
+	Source    string    `gorm:"column:source"`
+	Reason    string    `gorm:"column:reason"`
+	RequestID string    `gorm:"column:request_id"`
+	CreatedAt time.Time `gorm:"column:created_at;index"`
+}
+
+// TableName 指定表名
+func (statusTransitionV1) TableName() string {
+	return "status_history"
+}
+
+// auditEntryV1 迁移1中的audit_log表
+type auditEntryV1 struct {
+	ID         uint      `gorm:"column:id;primaryKey"`
+	RequestID  string    `gorm:"column:request_id;index"`
+	Actor      string    `gorm:"column:actor;index"`
+	APIKeyID   string    `gorm:"column:api_key_id;index"`
+	TenantID   string    `gorm:"column:tenant_id;index"`
+	Method     string    `gorm:"column:method"`
+	Route      string    `gorm:"column:route;index"`
+	Path       string    `gorm:"column:path"`
+	AccountID  string    `gorm:"column:account_id;index"`
+	Request    string    `gorm:"column:request;type:text"`
+	Status     int       `gorm:"column:status"`
+	Success    bool      `gorm:"column:success"`
+	Result     string    `gorm:"column:result"`
+	ClientIP   string    `gorm:"column:client_ip"`
+	DurationMs int64     `gorm:"column:duration_ms"`
+	CreatedAt  time.Time `gorm:"column:created_at;index"`
+}
+
+// TableName 指定表名
+func (auditEntryV1) TableName() string {
+	return "audit_log"
+}
+
+// optOutV1 迁移1中的opt_outs表
+type optOutV1 struct {
+	ID        uint      `gorm:"column:id;primaryKey"`
+	AccountID string    `gorm:"column:account_id;index"`
+	Contact   string    `gorm:"column:contact;index"`
+	Campaign  string    `gorm:"column:campaign;index"`
+	MessageID string    `gorm:"column:message_id"`
+	Keyword   string    `gorm:"column:keyword"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+// TableName 指定表名
+func (optOutV1) TableName() string {
+	return "opt_outs"
+}
+
+// campaignV1 迁移1中的campaigns表
+type campaignV1 struct {
+	ID         string     `gorm:"column:id;primaryKey"`
+	Name       string     `gorm:"column:name;uniqueIndex"`
+	Status     string     `gorm:"column:status;index"`
+	AccountIds string     `gorm:"column:account_ids;type:text"`
+	Message    string     `gorm:"column:message;type:text"`
+	IntervalMs int        `gorm:"column:interval_ms"`
+	TrackLinks *bool      `gorm:"column:track_links"`
+	LastError  string     `gorm:"column:last_error;type:text"`
+	JobID      string     `gorm:"column:job_id"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at"`
+	StartedAt  *time.Time `gorm:"column:started_at"`
+	FinishedAt *time.Time `gorm:"column:finished_at"`
+}
+
+// TableName 指定表名
+func (campaignV1) TableName() string {
+	return "campaigns"
+}
+
+// campaignRecipientV1 迁移1中的campaign_recipients表
+type campaignRecipientV1 struct {
+	ID              uint       `gorm:"column:id;primaryKey"`
+	CampaignID      string     `gorm:"column:campaign_id;index"`
+	Contact         string     `gorm:"column:contact"`
+	Variables       string     `gorm:"column:variables;type:text"`
+	Status          string     `gorm:"column:status;index"`
+	AccountID       string     `gorm:"column:account_id"`
+	WorkerMessageID string     `gorm:"column:worker_message_id"`
+	Error           string     `gorm:"column:error;type:text"`
+	SentAt          *time.Time `gorm:"column:sent_at"`
+}
+
+// TableName 指定表名
+func (campaignRecipientV1) TableName() string {
+	return "campaign_recipients"
+}
+
+// jobV1 迁移1中的jobs表
+type jobV1 struct {
+	ID          string     `gorm:"column:id;primaryKey"`
+	Type        string     `gorm:"column:type;index"`
+	Status      string     `gorm:"column:status;index"`
+	Stage       string     `gorm:"column:stage"`
+	Detail      string     `gorm:"column:detail"`
+	TenantID    string     `gorm:"column:tenant_id;index"`
+	Description string     `gorm:"column:description"`
+	Progress    int        `gorm:"column:progress"`
+	Total       int        `gorm:"column:total"`
+	Result      string     `gorm:"column:result;type:text"`
+	Error       string     `gorm:"column:error;type:text"`
+	CreatedAt   time.Time  `gorm:"column:created_at"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at"`
+	FinishedAt  *time.Time `gorm:"column:finished_at"`
+}
+
+// TableName 指定表名
+func (jobV1) TableName() string {
+	return "jobs"
+}
+
+// tenantV1 迁移1中的tenants表
+type tenantV1 struct {
+	ID                string    `gorm:"column:id;primaryKey"`
+	Name              string    `gorm:"column:name"`
+	MaxWorkers        int       `gorm:"column:max_workers"`
+	MaxMessagesPerDay int       `gorm:"column:max_messages_per_day"`
+	CreatedAt         time.Time `gorm:"column:created_at"`
+	UpdatedAt         time.Time `gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (tenantV1) TableName() string {
+	return "tenants"
+}
+
+// tenantAPIKeyV1 迁移1中的tenant_api_keys表
+type tenantAPIKeyV1 struct {
+	ID         string     `gorm:"column:id;primaryKey"`
+	TenantID   string     `gorm:"column:tenant_id;index"`
+	Name       string     `gorm:"column:name"`
+	Owner      string     `gorm:"column:owner"`
+	Prefix     string     `gorm:"column:prefix"`
+	KeyHash    string     `gorm:"column:key_hash;uniqueIndex"`
+	LastUsedAt *time.Time `gorm:"column:last_used_at"`
+	RevokedAt  *time.Time `gorm:"column:revoked_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+}
+
+// TableName 指定表名
+func (tenantAPIKeyV1) TableName() string {
+	return "tenant_api_keys"
+}
+
+// diagnosticBundleV1 迁移1中的diagnostic_bundles表
+type diagnosticBundleV1 struct {
+	ID         string    `gorm:"column:id;primaryKey"`
+	AccountID  string    `gorm:"column:account_id;index"`
+	IncidentID string    `gorm:"column:incident_id;index"`
+	Kind       string    `gorm:"column:kind"`
+	Note       string    `gorm:"column:note;type:text"`
+	Size       int64     `gorm:"column:size"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+	ExpiresAt  time.Time `gorm:"column:expires_at;index"`
+}
+
+// TableName 指定表名
+func (diagnosticBundleV1) TableName() string {
+	return "diagnostic_bundles"
+}
+
+// diagnosticFileV1 迁移1中的diagnostic_files表
+type diagnosticFileV1 struct {
+	ID       uint   `gorm:"column:id;primaryKey"`
+	BundleID string `gorm:"column:bundle_id;index"`
+	Name     string `gorm:"column:name"`
+	MimeType string `gorm:"column:mime_type"`
+	Size     int64  `gorm:"column:size"`
+}
+
+// TableName 指定表名
+func (diagnosticFileV1) TableName() string {
+	return "diagnostic_files"
+}
+
+// hostV1 迁移1中的hosts表
+type hostV1 struct {
+	ID         string    `gorm:"column:id;primaryKey"`
+	Name       string    `gorm:"column:name"`
+	Address    string    `gorm:"column:address"`
+	AgentURL   string    `gorm:"column:agent_url"`
+	Token      string    `gorm:"column:token;size:512"`
+	MaxWorkers int       `gorm:"column:max_workers"`
+	Cordoned   bool      `gorm:"column:cordoned"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+	UpdatedAt  time.Time `gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (hostV1) TableName() string {
+	return "hosts"
+}
+
+// configOverrideV1 迁移1中的config_overrides表
+type configOverrideV1 struct {
+	ConfigKey string    `gorm:"column:config_key;primaryKey"`
+	Value     string    `gorm:"column:value;type:text"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (configOverrideV1) TableName() string {
+	return "config_overrides"
+}
+
+// webhookV1 迁移1中的webhooks表
+type webhookV1 struct {
+	ID        string    `gorm:"column:id;primaryKey"`
+	URL       string    `gorm:"column:url"`
+	Events    string    `gorm:"column:events;type:text"`
+	Secret    string    `gorm:"column:secret;size:512"`
+	TenantID  string    `gorm:"column:tenant_id;index"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (webhookV1) TableName() string {
+	return "webhooks"
+}
+
+// alertChannelV1 迁移1中的alert_channels表
+type alertChannelV1 struct {
+	ID           string    `gorm:"column:id;primaryKey"`
+	Name         string    `gorm:"column:name"`
+	Type         string    `gorm:"column:type"`
+	Enabled      bool      `gorm:"column:enabled"`
+	URL          string    `gorm:"column:url"`
+	BotToken     string    `gorm:"column:bot_token;size:512"`
+	ChatID       string    `gorm:"column:chat_id"`
+	SmtpHost     string    `gorm:"column:smtp_host"`
+	SmtpPort     int       `gorm:"column:smtp_port"`
+	SmtpUsername string    `gorm:"column:smtp_username"`
+	SmtpPassword string    `gorm:"column:smtp_password;size:512"`
+	From         string    `gorm:"column:from"`
+	To           string    `gorm:"column:to;type:text"`
+	CreatedAt    time.Time `gorm:"column:created_at"`
+	UpdatedAt    time.Time `gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (alertChannelV1) TableName() string {
+	return "alert_channels"
+}
+
+// alertRuleV1 迁移1中的alert_rules表
+type alertRuleV1 struct {
+	ID         string    `gorm:"column:id;primaryKey"`
+	Name       string    `gorm:"column:name"`
+	Events     string    `gorm:"column:events;type:text"`
+	AccountIds string    `gorm:"column:account_ids;type:text"`
+	Pool       string    `gorm:"column:pool"`
+	OwnerTeam  string    `gorm:"column:owner_team"`
+	Channels   string    `gorm:"column:channels;type:text"`
+	Enabled    bool      `gorm:"column:enabled"`
+	CreatedAt  time.Time `gorm:"column:created_at"`
+	UpdatedAt  time.Time `gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (alertRuleV1) TableName() string {
+	return "alert_rules"
+}
+
+// accountDailyStatsV1 迁移1中的account_daily_stats表
+type accountDailyStatsV1 struct {
+	AccountID     string    `gorm:"column:account_id;primaryKey"`
+	Day           string    `gorm:"column:day;primaryKey;size:10"`
+	Sent          int       `gorm:"column:sent"`
+	Received      int       `gorm:"column:received"`
+	Failed        int       `gorm:"column:failed"`
+	UptimeSeconds int64     `gorm:"column:uptime_seconds"`
+	UpdatedAt     time.Time `gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (accountDailyStatsV1) TableName() string {
+	return "account_daily_stats"
+}
+
+// idempotencyKeyV1 迁移1中的idempotency_keys表
+type idempotencyKeyV1 struct {
+	IdempotencyKey string    `gorm:"column:idempotency_key;primaryKey;size:255"`
+	RequestHash    string    `gorm:"column:request_hash;size:64"`
+	Status         int       `gorm:"column:status"`
+	Response       string    `gorm:"column:response;type:text"`
+	CreatedAt      time.Time `gorm:"column:created_at"`
+	ExpiresAt      time.Time `gorm:"column:expires_at;index"`
+}
+
+// TableName 指定表名
+func (idempotencyKeyV1) TableName() string {
+	return "idempotency_keys"
+}
+
+// deadLetterV1 迁移1中的dead_letters表
+type deadLetterV1 struct {
+	ID          string     `gorm:"column:id;primaryKey"`
+	MessageID   string     `gorm:"column:message_id;uniqueIndex"`
+	AccountID   string     `gorm:"column:account_id;index"`
+	Contact     string     `gorm:"column:contact"`
+	Type        string     `gorm:"column:type"`
+	Body        string     `gorm:"column:body;type:text"`
+	Voice       bool       `gorm:"column:voice"`
+	Campaign    string     `gorm:"column:campaign"`
+	Error       string     `gorm:"column:error;type:text"`
+	Retries     int        `gorm:"column:retries"`
+	Status      string     `gorm:"column:status;index"`
+	NextRetryAt *time.Time `gorm:"column:next_retry_at;index"`
+	LastRetryAt *time.Time `gorm:"column:last_retry_at"`
+	ResolvedAt  *time.Time `gorm:"column:resolved_at;index"`
+	CreatedAt   time.Time  `gorm:"column:created_at"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (deadLetterV1) TableName() string {
+	return "dead_letters"
+}
+
+// autoReplyRuleV2 迁移2中的auto_reply_rules表
+type autoReplyRuleV2 struct {
+	ID                   string    `gorm:"column:id;primaryKey"`
+	AccountID            string    `gorm:"column:account_id;index"`
+	Name                 string    `gorm:"column:name"`
+	MatchType            string    `gorm:"column:match_type"`
+	Keywords             string    `gorm:"column:keywords;type:text"`
+	Pattern              string    `gorm:"column:pattern"`
+	Reply                string    `gorm:"column:reply;type:text"`
+	Priority             int       `gorm:"column:priority"`
+	QuietHourStart       int       `gorm:"column:quiet_hour_start"`
+	QuietHourEnd         int       `gorm:"column:quiet_hour_end"`
+	MaxRepliesPerContact int       `gorm:"column:max_replies_per_contact"`
+	IncludeGroups        bool      `gorm:"column:include_groups"`
+	Enabled              bool      `gorm:"column:enabled"`
+	CreatedAt            time.Time `gorm:"column:created_at"`
+	UpdatedAt            time.Time `gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (autoReplyRuleV2) TableName() string {
+	return "auto_reply_rules"
+}
+
+// autoReplyLogV2 迁移2中的auto_reply_logs表
+type autoReplyLogV2 struct {
+	ID               string     `gorm:"column:id;primaryKey"`
+	RuleID           string     `gorm:"column:rule_id;index"`
+	AccountID        string     `gorm:"column:account_id;index:idx_auto_reply_contact"`
+	Contact          string     `gorm:"column:contact;index:idx_auto_reply_contact"`
+	InboundMessageID string     `gorm:"column:inbound_message_id"`
+	Reply            string     `gorm:"column:reply;type:text"`
+	Status           string     `gorm:"column:status"`
+	MessageID        string     `gorm:"column:message_id"`
+	Error            string     `gorm:"column:error;type:text"`
+	CreatedAt        time.Time  `gorm:"column:created_at;index:idx_auto_reply_contact"`
+	SentAt           *time.Time `gorm:"column:sent_at"`
+}
+
+// TableName 指定表名
+func (autoReplyLogV2) TableName() string {
+	return "auto_reply_logs"
+}
+
+// accountV3 迁移3中的accounts表
+type accountV3 struct {
+	StatusPollSeconds int `gorm:"column:status_poll_seconds"`
+}
+
+// TableName 指定表名
+func (accountV3) TableName() string {
+	return "accounts"
+}
+
+// webhookV4 迁移4中的webhooks表
+type webhookV4 struct {
+	FilterAccountIds  string `gorm:"column:filter_account_ids;type:text"`
+	FilterDirections  string `gorm:"column:filter_directions;type:text"`
+	FilterContacts    string `gorm:"column:filter_contacts;type:text"`
+	FilterKeywords    string `gorm:"column:filter_keywords;type:text"`
+	IncludeMedia      bool   `gorm:"column:include_media"`
+	RetryMaxAttempts  int    `gorm:"column:retry_max_attempts"`
+	RetryBackoffMs    int    `gorm:"column:retry_backoff_ms"`
+	RetryMaxBackoffMs int    `gorm:"column:retry_max_backoff_ms"`
+}
+
+// TableName 指定表名
+func (webhookV4) TableName() string {
+	return "webhooks"
+}
+
+// leaderLeaseV5 迁移5中的leader_leases表
+type leaderLeaseV5 struct {
+	Name       string    `gorm:"column:name;primaryKey"`
+	HolderID   string    `gorm:"column:holder_id"`
+	HolderURL  string    `gorm:"column:holder_url"`
+	AcquiredAt time.Time `gorm:"column:acquired_at"`
+	ExpiresAt  time.Time `gorm:"column:expires_at;index"`
+}
+
+// TableName 指定表名
+func (leaderLeaseV5) TableName() string {
+	return "leader_leases"
+}
+
+// hostV6 迁移6中的hosts表
+type hostV6 struct {
+	Region string `gorm:"column:region"`
+}
+
+// TableName 指定表名
+func (hostV6) TableName() string {
+	return "hosts"
+}
+
+// banRecordV7 迁移7中的ban_records表
+type banRecordV7 struct {
+	ID             string     `gorm:"column:id;primaryKey"`
+	AccountID      string     `gorm:"column:account_id;index"`
+	Phone          string     `gorm:"column:phone"`
+	Source         string     `gorm:"column:source"`
+	Signal         string     `gorm:"column:signal"`
+	Evidence       string     `gorm:"column:evidence;type:text"`
+	PreviousStatus string     `gorm:"column:previous_status"`
+	WasDisabled    bool       `gorm:"column:was_disabled"`
+	Status         string     `gorm:"column:status;index"`
+	ReleaseNote    string     `gorm:"column:release_note;type:text"`
+	ReleasedAt     *time.Time `gorm:"column:released_at"`
+	CreatedAt      time.Time  `gorm:"column:created_at"`
+	UpdatedAt      time.Time  `gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (banRecordV7) TableName() string {
+	return "ban_records"
+}
+
+// sessionBackupV8 迁移8中的session_backups表
+type sessionBackupV8 struct {
+	ID            string    `gorm:"column:id;primaryKey"`
+	AccountID     string    `gorm:"column:account_id;index"`
+	HostID        string    `gorm:"column:host_id"`
+	Destination   string    `gorm:"column:destination"`
+	ObjectKey     string    `gorm:"column:object_key"`
+	SizeBytes     int64     `gorm:"column:size_bytes"`
+	Sha256        string    `gorm:"column:sha256"`
+	BackupTrigger string    `gorm:"column:backup_trigger"`
+	Status        string    `gorm:"column:status;index"`
+	Error         string    `gorm:"column:error;type:text"`
+	CreatedAt     time.Time `gorm:"column:created_at;index"`
+}
+
+// TableName 指定表名
+func (sessionBackupV8) TableName() string {
+	return "session_backups"
+}
+
+// contentPolicyV9 迁移9中的content_policies表
+type contentPolicyV9 struct {
+	ID        string    `gorm:"column:id;primaryKey"`
+	Name      string    `gorm:"column:name"`
+	Type      string    `gorm:"column:type"`
+	Keywords  string    `gorm:"column:keywords;type:text"`
+	Domains   string    `gorm:"column:domains;type:text"`
+	MaxLinks  int       `gorm:"column:max_links"`
+	Patterns  string    `gorm:"column:patterns;type:text"`
+	Action    string    `gorm:"column:action"`
+	Enabled   bool      `gorm:"column:enabled"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (contentPolicyV9) TableName() string {
+	return "content_policies"
+}
+
+// moderationItemV9 迁移9中的moderation_queue表
+type moderationItemV9 struct {
+	ID         string     `gorm:"column:id;primaryKey"`
+	AccountID  string     `gorm:"column:account_id;index"`
+	Contact    string     `gorm:"column:contact"`
+	Message    string     `gorm:"column:message;type:text"`
+	Campaign   string     `gorm:"column:campaign"`
+	TrackLinks *bool      `gorm:"column:track_links"`
+	Violations string     `gorm:"column:violations;type:text"`
+	Status     string     `gorm:"column:status;index"`
+	Note       string     `gorm:"column:note;type:text"`
+	MessageID  string     `gorm:"column:message_id"`
+	Error      string     `gorm:"column:error;type:text"`
+	ReviewedAt *time.Time `gorm:"column:reviewed_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (moderationItemV9) TableName() string {
+	return "moderation_queue"
+}
+
+// approvalV10 迁移10中的approvals表
+type approvalV10 struct {
+	ID         string     `gorm:"column:id;primaryKey"`
+	Kind       string     `gorm:"column:kind;index"`
+	CampaignID string     `gorm:"column:campaign_id;index"`
+	Campaign   string     `gorm:"column:campaign"`
+	AccountIds string     `gorm:"column:account_ids;type:text"`
+	Recipients int        `gorm:"column:recipients"`
+	Message    string     `gorm:"column:message;type:text"`
+	Sample     string     `gorm:"column:sample;type:text"`
+	Request    string     `gorm:"column:request;type:text"`
+	Status     string     `gorm:"column:status;index"`
+	Note       string     `gorm:"column:note;type:text"`
+	BatchID    string     `gorm:"column:batch_id"`
+	Error      string     `gorm:"column:error;type:text"`
+	ReviewedAt *time.Time `gorm:"column:reviewed_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (approvalV10) TableName() string {
+	return "approvals"
+}
+
+// optOutV11 迁移11中的opt_outs表
+type optOutV11 struct {
+	Source string `gorm:"column:source;index"`
+	Reason string `gorm:"column:reason;type:text"`
+}
+
+// TableName 指定表名
+func (optOutV11) TableName() string {
+	return "opt_outs"
+}
+
+// statusTransitionV12 迁移12中的status_history表
+type statusTransitionV12 struct {
+	Details string `gorm:"column:details;type:text"`
+}
+
+// TableName 指定表名
+func (statusTransitionV12) TableName() string {
+	return "status_history"
+}
+
+// workerCallV13 迁移13中的worker_calls表
+type workerCallV13 struct {
+	ID          uint      `gorm:"column:id;primaryKey"`
+	AccountID   string    `gorm:"column:account_id;index"`
+	RequestID   string    `gorm:"column:request_id;index"`
+	Source      string    `gorm:"column:source;index"`
+	Method      string    `gorm:"column:method"`
+	Path        string    `gorm:"column:path;index"`
+	Status      int       `gorm:"column:status"`
+	LatencyMs   int64     `gorm:"column:latency_ms"`
+	PayloadSize int64     `gorm:"column:payload_size"`
+	PayloadHash string    `gorm:"column:payload_hash"`
+	Error       string    `gorm:"column:error;type:text"`
+	CreatedAt   time.Time `gorm:"column:created_at;index"`
+}
+
+// TableName 指定表名
+func (workerCallV13) TableName() string {
+	return "worker_calls"
+}
+
+// hardwareProfileV14 迁移14中的hardware_profiles表
+type hardwareProfileV14 struct {
+	ID           string    `gorm:"column:id;primaryKey"`
+	Name         string    `gorm:"column:name;uniqueIndex"`
+	Os           string    `gorm:"column:os"`
+	Browser      string    `gorm:"column:browser"`
+	UserAgent    string    `gorm:"column:user_agent"`
+	ScreenWidth  int       `gorm:"column:screen_width"`
+	ScreenHeight int       `gorm:"column:screen_height"`
+	Locale       string    `gorm:"column:locale"`
+	Timezone     string    `gorm:"column:timezone"`
+	CreatedAt    time.Time `gorm:"column:created_at"`
+	UpdatedAt    time.Time `gorm:"column:updated_at"`
+}
+
+// TableName 指定表名
+func (hardwareProfileV14) TableName() string {
+	return "hardware_profiles"
+}
+
+// accountV14 迁移14中的accounts表
+type accountV14 struct {
+	HardwareProfileID string `gorm:"column:hardware_profile_id"`
+}
+
+// TableName 指定表名
+func (accountV14) TableName() string {
+	return "accounts"
+}
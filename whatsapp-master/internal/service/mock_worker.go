@@ -0,0 +1,745 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// WorkerModeMock 在Master进程内模拟Worker，不需要Docker和WhatsApp，用于演示和集成测试
+const WorkerModeMock = "mock"
+
+const (
+	// mockWorkerVersion 模拟Worker在 /api/status 中上报的版本
+	mockWorkerVersion = "mock"
+	// mockWorkerLogLines 每个模拟Worker保留的日志行数
+	mockWorkerLogLines = 500
+	// mockDeliveredAfter、mockReadAfter 模拟消息从发送到送达、已读的时间
+	mockDeliveredAfter = 2 * time.Second
+	mockReadAfter      = 5 * time.Second
+)
+
+// 消息回执的ack值，与 whatsapp-web.js 一致
+const (
+	mockAckSent      = 1
+	mockAckDelivered = 2
+	mockAckRead      = 3
+)
+
+// mockWorkerRuntime WORKER_MODE=mock 时本机的Worker运行时：每个Worker是本机端口上的一个HTTP服务，
+// 按真实Worker的接口响应
+type mockWorkerRuntime struct {
+	manager    *Manager
+	loginDelay time.Duration
+	workers    map[string]*mockWorker // 容器名 -> Worker，被kill的Worker保留为已退出状态直到rm
+	mutex      sync.Mutex
+}
+
+// mockWorker 一个模拟Worker：登录在延迟后总是成功，发送的消息记录在内存中
+type mockWorker struct {
+	runtime   *mockWorkerRuntime
+	name      string
+	accountID string
+	port      int
+	server    *http.Server
+
+	mutex       sync.Mutex
+	running     bool
+	status      string
+	selfID      string
+	qrCode      string
+	pairingCode string
+	loginTimer  *time.Timer
+	proxy       *model.WorkerProxyConfig
+	messages    []mockMessage
+	contacts    map[string]model.WorkerContact
+	logs        []string
+}
+
+// mockMessage 模拟Worker发送过的消息
+type mockMessage struct {
+	ID     string
+	ChatID string
+	Body   string
+	Type   string
+	SentAt time.Time
+}
+
+func newMockWorkerRuntime(m *Manager) *mockWorkerRuntime {
+	return &mockWorkerRuntime{
+		manager:    m,
+		loginDelay: time.Duration(m.GetConfig().Worker.MockLoginDelayMs) * time.Millisecond,
+		workers:    make(map[string]*mockWorker),
+	}
+}
+
+// Spawn 在账号端口上启动模拟Worker，模拟Worker没有镜像和会话目录，忽略要恢复的备份
+func (r *mockWorkerRuntime) Spawn(_ context.Context, spec *WorkerSpec) error {
+	account := spec.Account
+	spec.OnStage(SpawnStageStarting)
+	listener, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", account.Port))
+	if err != nil {
+		return fmt.Errorf("failed to start mock worker: %v", err)
+	}
+
+	worker := &mockWorker{
+		runtime:   r,
+		name:      spec.Name,
+		accountID: account.ID,
+		port:      account.Port,
+		running:   true,
+		status:    "idle",
+		selfID:    chatID(valueOrDefault(account.Phone, account.ID)),
+		contacts:  make(map[string]model.WorkerContact),
+	}
+	if account.Proxy.IP != "" {
+		worker.proxy = &model.WorkerProxyConfig{
+			IP:     account.Proxy.IP,
+			Port:   strconv.Itoa(account.Proxy.Port),
+			User:   account.Proxy.Username,
+			Scheme: valueOrDefault(account.Proxy.Protocol, "socks5"),
+		}
+	}
+	worker.server = &http.Server{Handler: worker.routes(), ReadHeaderTimeout: 10 * time.Second}
+	worker.log("Mock worker listening on port %d for account %s", worker.port, account.ID)
+
+	r.mutex.Lock()
+	r.workers[spec.Name] = worker
+	r.mutex.Unlock()
+
+	go func() {
+		if err := worker.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			slog.Warn("Mock worker stopped", "account_id", account.ID, "error", err)
+		}
+	}()
+	return nil
+}
+
+// Stop 停止并删除模拟Worker
+func (r *mockWorkerRuntime) Stop(name string) error {
+	r.mutex.Lock()
+	worker, exists := r.workers[name]
+	delete(r.workers, name)
+	r.mutex.Unlock()
+	if !exists {
+		return fmt.Errorf("no such container: %s", name)
+	}
+	worker.stop()
+	return nil
+}
+
+// Kill 停止模拟Worker但保留为已退出状态，与 docker kill 一致
+func (r *mockWorkerRuntime) Kill(name string) error {
+	r.mutex.Lock()
+	worker, exists := r.workers[name]
+	r.mutex.Unlock()
+	if !exists {
+		return fmt.Errorf("no such container: %s", name)
+	}
+	worker.stop()
+	return nil
+}
+
+// List 列出模拟Worker，模拟Worker都带有fleet标签且没有健康检查
+func (r *mockWorkerRuntime) List(prefix string) ([]WorkerContainer, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var containers []WorkerContainer
+	for name, worker := range r.workers {
+		if !strings.HasPrefix(name, prefix) {
+			continue
+		}
+		worker.mutex.Lock()
+		running := worker.running
+		worker.mutex.Unlock()
+
+		container := WorkerContainer{Name: name, State: "exited", Managed: true}
+		if running {
+			container.State = "running"
+			container.Port = worker.port
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+// close 停止所有模拟Worker
+func (r *mockWorkerRuntime) close() {
+	r.mutex.Lock()
+	workers := make([]*mockWorker, 0, len(r.workers))
+	for _, worker := range r.workers {
+		workers = append(workers, worker)
+	}
+	r.workers = make(map[string]*mockWorker)
+	r.mutex.Unlock()
+
+	for _, worker := range workers {
+		worker.stop()
+	}
+}
+
+// Logs 模拟Worker当前的日志，最多 query.Tail 行，不支持 since 和 follow
+func (r *mockWorkerRuntime) Logs(_ context.Context, name string, query *model.LogStreamQuery) (io.ReadCloser, error) {
+	tail := defaultLogTail
+	if query.Tail != nil {
+		tail = *query.Tail
+	}
+	r.mutex.Lock()
+	worker, exists := r.workers[name]
+	r.mutex.Unlock()
+	if !exists {
+		return nil, fmt.Errorf("no such container: %s", name)
+	}
+
+	worker.mutex.Lock()
+	lines := worker.logs
+	if tail >= 0 && len(lines) > tail {
+		lines = lines[len(lines)-tail:]
+	}
+	text := strings.Join(lines, "")
+	worker.mutex.Unlock()
+	return io.NopCloser(strings.NewReader(text)), nil
+}
+
+func (w *mockWorker) stop() {
+	w.mutex.Lock()
+	w.running = false
+	if w.loginTimer != nil {
+		w.loginTimer.Stop()
+	}
+	w.mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	w.server.Shutdown(ctx)
+}
+
+// log 追加一行日志，格式与 docker logs --timestamps 一致
+func (w *mockWorker) log(format string, args ...interface{}) {
+	line := time.Now().UTC().Format(time.RFC3339Nano) + " " + fmt.Sprintf(format, args...) + "\n"
+	w.mutex.Lock()
+	w.logs = append(w.logs, line)
+	if len(w.logs) > mockWorkerLogLines {
+		w.logs = w.logs[len(w.logs)-mockWorkerLogLines:]
+	}
+	w.mutex.Unlock()
+}
+
+// setStatus 更新状态并像真实Worker一样推送事件给Master，调用方需持有锁
+func (w *mockWorker) setStatusLocked(status string) {
+	w.status = status
+	events := []model.WorkerEvent{{Type: model.WorkerEventStatus, Status: status, Timestamp: time.Now().UnixMilli()}}
+	if status != "waiting_for_scan" {
+		w.qrCode = ""
+	}
+	events = append(events, model.WorkerEvent{Type: model.WorkerEventQRCode, QRCode: w.qrCode})
+	go w.runtime.manager.HandleWorkerEvents(w.accountID, events)
+}
+
+func (w *mockWorker) statusResponse() map[string]interface{} {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	var pairingCode, qrCode interface{}
+	if w.pairingCode != "" {
+		pairingCode = w.pairingCode
+	}
+	if w.qrCode != "" {
+		qrCode = w.qrCode
+	}
+	return map[string]interface{}{
+		"success":      true,
+		"is_logged_in": w.status == "logged_in",
+		"status":       w.status,
+		"qr_code":      qrCode,
+		"pairing_code": pairingCode,
+		"last_error":   nil,
+	}
+}
+
+func (w *mockWorker) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/status", func(rw http.ResponseWriter, r *http.Request) {
+		status := w.statusResponse()
+		status["account_id"] = w.accountID
+		status["version"] = mockWorkerVersion
+		status["api_version"] = max(w.runtime.manager.GetConfig().Worker.APIVersionMax, w.runtime.manager.GetConfig().Worker.APIVersionMin)
+		writeMockJSON(rw, http.StatusOK, status)
+	})
+	mux.HandleFunc("GET /api/login/status", func(rw http.ResponseWriter, r *http.Request) {
+		writeMockJSON(rw, http.StatusOK, w.statusResponse())
+	})
+	mux.HandleFunc("GET /api/health", func(rw http.ResponseWriter, r *http.Request) {
+		w.mutex.Lock()
+		status := w.status
+		w.mutex.Unlock()
+		writeMockJSON(rw, http.StatusOK, map[string]interface{}{"success": true, "status": status})
+	})
+	mux.HandleFunc("POST /api/login", w.handleLogin)
+	mux.HandleFunc("POST /api/pairing-code", w.handlePairingCode)
+	mux.HandleFunc("POST /api/login/refresh", func(rw http.ResponseWriter, r *http.Request) {
+		w.log("Refreshing session")
+		writeMockJSON(rw, http.StatusOK, map[string]interface{}{"success": true, "message": "Session refresh started", "data": w.statusResponse()})
+	})
+	mux.HandleFunc("GET /api/qr-code", func(rw http.ResponseWriter, r *http.Request) {
+		w.mutex.Lock()
+		qrCode := w.qrCode
+		w.mutex.Unlock()
+		if qrCode == "" {
+			writeMockJSON(rw, http.StatusOK, map[string]interface{}{"success": false, "message": "No QR code available"})
+			return
+		}
+		writeMockJSON(rw, http.StatusOK, map[string]interface{}{"success": true, "qr_code": qrCode})
+	})
+	mux.HandleFunc("POST /api/logout", func(rw http.ResponseWriter, r *http.Request) {
+		w.mutex.Lock()
+		w.setStatusLocked("disconnected")
+		w.mutex.Unlock()
+		w.log("Logged out")
+		writeMockJSON(rw, http.StatusOK, map[string]interface{}{"success": true, "message": "Logged out successfully"})
+	})
+	mux.HandleFunc("POST /api/close", func(rw http.ResponseWriter, r *http.Request) {
+		w.mutex.Lock()
+		if w.loginTimer != nil {
+			w.loginTimer.Stop()
+		}
+		w.status = "idle"
+		w.mutex.Unlock()
+		w.log("Service stopped (session preserved)")
+		writeMockJSON(rw, http.StatusOK, map[string]interface{}{"success": true, "message": "Service stopped (session preserved)"})
+	})
+
+	mux.HandleFunc("POST /api/send-message", func(rw http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Phone   string `json:"phone"`
+			Contact string `json:"contact"`
+			Message string `json:"message"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		w.handleSend(rw, valueOrDefault(req.Phone, req.Contact), req.Message, "chat")
+	})
+	mux.HandleFunc("POST /api/send-media", func(rw http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Phone     string `json:"phone"`
+			Contact   string `json:"contact"`
+			Caption   string `json:"caption"`
+			MediaType string `json:"media_type"`
+			Media     *struct {
+				Data     string `json:"data"`
+				Mimetype string `json:"mimetype"`
+			} `json:"media"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Media == nil || req.Media.Data == "" || req.Media.Mimetype == "" {
+			writeMockError(rw, http.StatusBadRequest, "Missing recipient or media")
+			return
+		}
+		w.handleSend(rw, valueOrDefault(req.Phone, req.Contact), req.Caption, valueOrDefault(req.MediaType, "document"))
+	})
+	mux.HandleFunc("GET /api/messages", w.handleRecentMessages)
+	mux.HandleFunc("GET /api/messages/recent", w.handleRecentMessages)
+	mux.HandleFunc("GET /api/messages/acks", w.handleAcks)
+	mux.HandleFunc("GET /api/chats/messages", w.handleChatHistory)
+
+	mux.HandleFunc("GET /api/contacts", func(rw http.ResponseWriter, r *http.Request) {
+		w.mutex.Lock()
+		contacts := make([]model.WorkerContact, 0, len(w.contacts))
+		if w.status == "logged_in" {
+			for _, contact := range w.contacts {
+				contacts = append(contacts, contact)
+			}
+		}
+		w.mutex.Unlock()
+		writeMockJSON(rw, http.StatusOK, map[string]interface{}{"success": true, "data": contacts})
+	})
+	mux.HandleFunc("POST /api/contacts/add", func(rw http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Phone     string `json:"phone"`
+			FirstName string `json:"firstName"`
+			LastName  string `json:"lastName"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Phone == "" {
+			writeMockError(rw, http.StatusBadRequest, "Missing phone number")
+			return
+		}
+		id := chatID(req.Phone)
+		contact := model.WorkerContact{ID: id, Name: strings.TrimSpace(req.FirstName + " " + req.LastName), Number: strings.TrimSuffix(id, "@c.us")}
+		w.mutex.Lock()
+		if req.FirstName != "" {
+			contact.IsMyContact = true
+			w.contacts[id] = contact
+		} else if saved, exists := w.contacts[id]; exists {
+			contact = saved
+		} else {
+			contact.Name = contact.Number
+		}
+		w.mutex.Unlock()
+		writeMockJSON(rw, http.StatusOK, map[string]interface{}{"success": true, "data": contact})
+	})
+	mux.HandleFunc("POST /api/typing", func(rw http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Contact    string `json:"contact"`
+			State      string `json:"state"`
+			DurationMs int    `json:"duration_ms"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		state := valueOrDefault(req.State, "typing")
+		if state == "paused" {
+			req.DurationMs = 0
+		}
+		w.respondLoggedIn(rw, req.Contact, func(chat string) interface{} {
+			return map[string]interface{}{"chat_id": chat, "state": state, "duration_ms": req.DurationMs}
+		})
+	})
+	mux.HandleFunc("GET /api/presence", func(rw http.ResponseWriter, r *http.Request) {
+		w.respondLoggedIn(rw, r.URL.Query().Get("contact"), func(chat string) interface{} {
+			return map[string]interface{}{"chat_id": chat, "online": nil, "state": nil, "last_seen": nil}
+		})
+	})
+
+	mux.HandleFunc("GET /api/proxy/status", func(rw http.ResponseWriter, r *http.Request) {
+		w.mutex.Lock()
+		status := model.ProxyStatus{Success: true, Enabled: w.proxy != nil, Config: w.proxy}
+		w.mutex.Unlock()
+		writeMockJSON(rw, http.StatusOK, status)
+	})
+	mux.HandleFunc("GET /api/proxy/external-ip", func(rw http.ResponseWriter, r *http.Request) {
+		writeMockJSON(rw, http.StatusOK, model.ExternalIPResult{Success: true, IP: w.externalIP()})
+	})
+	mux.HandleFunc("GET /api/proxy/detect", func(rw http.ResponseWriter, r *http.Request) {
+		writeMockJSON(rw, http.StatusOK, map[string]interface{}{"success": true, "detected": true, "ip": w.externalIP()})
+	})
+	mux.HandleFunc("POST /api/proxy/switch", func(rw http.ResponseWriter, r *http.Request) {
+		var req map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&req)
+		proxy := mockProxyConfig(req)
+		if proxy == nil {
+			writeMockError(rw, http.StatusBadRequest, "Invalid proxy config: missing host/ip or port")
+			return
+		}
+		w.mutex.Lock()
+		w.proxy = proxy
+		w.mutex.Unlock()
+		w.log("Switching proxy to: %s:%s", proxy.IP, proxy.Port)
+		writeMockJSON(rw, http.StatusOK, map[string]interface{}{"success": true, "message": "Proxy switched and service restarted", "data": w.statusResponse()})
+	})
+
+	// 群组等其他接口模拟Worker不支持
+	mux.HandleFunc("/", func(rw http.ResponseWriter, r *http.Request) {
+		writeMockError(rw, http.StatusNotImplemented, fmt.Sprintf("%s %s is not supported by the mock worker", r.Method, r.URL.Path))
+	})
+	return mux
+}
+
+// handleLogin 开始登录：扫码登录生成二维码，手机号登录生成配对码，WORKER_MOCK_LOGIN_DELAY_MS 后登录成功
+func (w *mockWorker) handleLogin(rw http.ResponseWriter, r *http.Request) {
+	var req struct {
+		LoginMethod string                 `json:"login_method"`
+		Phone       string                 `json:"phone"`
+		LoginPhone  string                 `json:"login_phone"`
+		SigninType  int                    `json:"signin_type"`
+		Socks5      map[string]interface{} `json:"socks5"`
+	}
+	json.NewDecoder(r.Body).Decode(&req)
+	method := req.LoginMethod
+	if method == "" {
+		method = "qr"
+		if req.SigninType == 40 {
+			method = "phone"
+		}
+	}
+	phone := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, valueOrDefault(req.Phone, req.LoginPhone))
+	if method == "phone" && phone == "" {
+		writeMockError(rw, http.StatusBadRequest, "Missing phone number for phone login")
+		return
+	}
+	w.log("Login request: Account=%s, Method=%s, Phone=%s", w.accountID, method, phone)
+
+	w.mutex.Lock()
+	if proxy := mockProxyConfig(req.Socks5); proxy != nil {
+		w.proxy = proxy
+	}
+	if w.status != "logged_in" {
+		if phone != "" {
+			w.selfID = chatID(phone)
+		}
+		w.pairingCode = ""
+		if method == "phone" {
+			code := strings.ToUpper(randomHex(4))
+			w.pairingCode = code[:4] + "-" + code[4:]
+			w.setStatusLocked("waiting_for_code")
+		} else {
+			w.qrCode = fmt.Sprintf("2@mock%s,%s,%s", randomHex(16), w.accountID, strconv.FormatInt(time.Now().UnixMilli(), 10))
+			w.setStatusLocked("waiting_for_scan")
+		}
+		if w.loginTimer != nil {
+			w.loginTimer.Stop()
+		}
+		w.loginTimer = time.AfterFunc(w.runtime.loginDelay, w.completeLogin)
+	}
+	w.mutex.Unlock()
+
+	writeMockJSON(rw, http.StatusOK, w.statusResponse())
+}
+
+// handlePairingCode 为进行中的手机号登录生成新的配对码，登录成功的计时重新开始
+func (w *mockWorker) handlePairingCode(rw http.ResponseWriter, r *http.Request) {
+	w.mutex.Lock()
+	if w.status == "logged_in" {
+		w.mutex.Unlock()
+		writeMockError(rw, http.StatusConflict, "Already logged in")
+		return
+	}
+	code := strings.ToUpper(randomHex(4))
+	w.pairingCode = code[:4] + "-" + code[4:]
+	w.setStatusLocked("waiting_for_code")
+	if w.loginTimer != nil {
+		w.loginTimer.Stop()
+	}
+	w.loginTimer = time.AfterFunc(w.runtime.loginDelay, w.completeLogin)
+	w.mutex.Unlock()
+	w.log("Pairing Code Received: %s", w.pairingCode)
+
+	writeMockJSON(rw, http.StatusOK, w.statusResponse())
+}
+
+// completeLogin 模拟扫码或输入配对码后登录成功
+func (w *mockWorker) completeLogin() {
+	w.mutex.Lock()
+	if !w.running || (w.status != "waiting_for_scan" && w.status != "waiting_for_code") {
+		w.mutex.Unlock()
+		return
+	}
+	w.pairingCode = ""
+	w.setStatusLocked("logged_in")
+	w.mutex.Unlock()
+	w.log("Client is ready")
+}
+
+// handleSend 记录发送的消息，返回与 whatsapp-web.js 的消息对象相同结构的结果
+func (w *mockWorker) handleSend(rw http.ResponseWriter, recipient, body, msgType string) {
+	recipient = strings.TrimSpace(recipient)
+	if recipient == "" || (msgType == "chat" && body == "") {
+		writeMockError(rw, http.StatusBadRequest, "Missing recipient or message")
+		return
+	}
+	w.respondLoggedIn(rw, recipient, func(chat string) interface{} {
+		msg := mockMessage{
+			ID:     fmt.Sprintf("true_%s_3EB0%s", chat, strings.ToUpper(randomHex(8))),
+			ChatID: chat,
+			Body:   body,
+			Type:   msgType,
+			SentAt: time.Now(),
+		}
+		w.mutex.Lock()
+		w.messages = append(w.messages, msg)
+		if _, exists := w.contacts[chat]; !exists {
+			number := strings.TrimSuffix(chat, "@c.us")
+			w.contacts[chat] = model.WorkerContact{ID: chat, Name: number, Number: number}
+		}
+		w.mutex.Unlock()
+		w.log("Message %s sent to %s", msg.ID, chat)
+
+		return map[string]interface{}{
+			"id":        map[string]interface{}{"fromMe": true, "remote": chat, "id": strings.TrimPrefix(msg.ID, "true_"+chat+"_"), "_serialized": msg.ID},
+			"ack":       mockAckSent,
+			"body":      body,
+			"type":      msgType,
+			"from":      w.selfID,
+			"to":        chat,
+			"timestamp": msg.SentAt.Unix(),
+			"fromMe":    true,
+		}
+	})
+}
+
+// respondLoggedIn 已登录时解析联系人并返回 fn 的结果，未登录或联系人无效时与真实Worker一样返回500
+func (w *mockWorker) respondLoggedIn(rw http.ResponseWriter, contact string, fn func(chat string) interface{}) {
+	contact = strings.TrimSpace(contact)
+	if contact == "" {
+		writeMockError(rw, http.StatusBadRequest, "Missing contact")
+		return
+	}
+	w.mutex.Lock()
+	loggedIn := w.status == "logged_in"
+	chat, ok := w.resolveChatLocked(contact)
+	w.mutex.Unlock()
+	if !loggedIn {
+		writeMockError(rw, http.StatusInternalServerError, "Not logged in")
+		return
+	}
+	if !ok {
+		writeMockError(rw, http.StatusInternalServerError, fmt.Sprintf("Contact '%s' not found. Please use a valid phone number or exact contact name.", contact))
+		return
+	}
+	writeMockJSON(rw, http.StatusOK, map[string]interface{}{"success": true, "data": fn(chat)})
+}
+
+// resolveChatLocked 与真实Worker一样解析收件人：WhatsApp ID原样使用，纯数字补 @c.us，其他按联系人名称查找
+func (w *mockWorker) resolveChatLocked(contact string) (string, bool) {
+	if strings.Contains(contact, "@") {
+		return contact, true
+	}
+	if _, err := strconv.ParseUint(contact, 10, 64); err == nil {
+		return contact + "@c.us", true
+	}
+	for id, saved := range w.contacts {
+		if saved.Name == contact || saved.PushName == contact || saved.Number == contact {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// handleRecentMessages 模拟Worker不会收到入站消息
+func (w *mockWorker) handleRecentMessages(rw http.ResponseWriter, r *http.Request) {
+	writeMockJSON(rw, http.StatusOK, map[string]interface{}{"success": true, "data": []interface{}{}})
+}
+
+// handleAcks 按发送后经过的时间返回回执：mockDeliveredAfter 后送达，mockReadAfter 后已读
+func (w *mockWorker) handleAcks(rw http.ResponseWriter, r *http.Request) {
+	wanted := make(map[string]bool)
+	for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			wanted[id] = true
+		}
+	}
+
+	receipts := make([]model.MessageReceipt, 0, len(wanted))
+	w.mutex.Lock()
+	for _, msg := range w.messages {
+		if wanted[msg.ID] {
+			receipts = append(receipts, model.MessageReceipt{ID: msg.ID, Ack: msg.ack()})
+		}
+	}
+	w.mutex.Unlock()
+	writeMockJSON(rw, http.StatusOK, map[string]interface{}{"success": true, "data": receipts})
+}
+
+// handleChatHistory 返回与联系人的已发送消息，按时间从旧到新排列
+func (w *mockWorker) handleChatHistory(rw http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	contact := strings.TrimSpace(query.Get("contact"))
+	if contact == "" {
+		writeMockError(rw, http.StatusBadRequest, "contact is required")
+		return
+	}
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit <= 0 {
+		limit = 50
+	}
+	limit = min(limit, 500)
+	before, _ := strconv.ParseInt(query.Get("before"), 10, 64)
+
+	w.mutex.Lock()
+	loggedIn := w.status == "logged_in"
+	chat, ok := w.resolveChatLocked(contact)
+	var page []model.WorkerChatMessage
+	for _, msg := range w.messages {
+		if msg.ChatID != chat || (before > 0 && msg.SentAt.UnixMilli() >= before) {
+			continue
+		}
+		page = append(page, model.WorkerChatMessage{
+			ID:        msg.ID,
+			From:      w.selfID,
+			To:        msg.ChatID,
+			FromMe:    true,
+			Body:      msg.Body,
+			Timestamp: msg.SentAt.UnixMilli(),
+			Type:      msg.Type,
+			Ack:       msg.ack(),
+		})
+	}
+	w.mutex.Unlock()
+	if !loggedIn || !ok {
+		writeMockError(rw, http.StatusInternalServerError, "Client not logged in")
+		return
+	}
+
+	history := model.WorkerChatHistory{Messages: page, HasMore: len(page) > limit}
+	if history.HasMore {
+		history.Messages = page[len(page)-limit:]
+	}
+	if history.Messages == nil {
+		history.Messages = []model.WorkerChatMessage{}
+	}
+	writeMockJSON(rw, http.StatusOK, map[string]interface{}{"success": true, "data": history})
+}
+
+// externalIP 通过代理时返回代理地址，否则返回本机地址
+func (w *mockWorker) externalIP() string {
+	w.mutex.Lock()
+	defer w.mutex.Unlock()
+	if w.proxy != nil {
+		return w.proxy.IP
+	}
+	return "127.0.0.1"
+}
+
+func (msg mockMessage) ack() int {
+	age := time.Since(msg.SentAt)
+	switch {
+	case age >= mockReadAfter:
+		return mockAckRead
+	case age >= mockDeliveredAfter:
+		return mockAckDelivered
+	}
+	return mockAckSent
+}
+
+// mockProxyConfig 解析登录请求的 socks5 或切换代理请求，缺少地址或端口时返回nil
+func mockProxyConfig(raw map[string]interface{}) *model.WorkerProxyConfig {
+	text := func(keys ...string) string {
+		for _, key := range keys {
+			if value, ok := raw[key]; ok && value != nil {
+				return fmt.Sprint(value)
+			}
+		}
+		return ""
+	}
+	proxy := &model.WorkerProxyConfig{
+		IP:     text("ip", "host"),
+		Port:   text("port"),
+		User:   text("username", "user"),
+		Scheme: valueOrDefault(text("protocol", "scheme"), "socks5"),
+	}
+	if proxy.IP == "" || proxy.Port == "" {
+		return nil
+	}
+	return proxy
+}
+
+// chatID 号码对应的WhatsApp个人会话ID
+func chatID(phone string) string {
+	if strings.Contains(phone, "@") {
+		return phone
+	}
+	return strings.TrimPrefix(phone, "+") + "@c.us"
+}
+
+func writeMockJSON(rw http.ResponseWriter, status int, v interface{}) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	json.NewEncoder(rw).Encode(v)
+}
+
+func writeMockError(rw http.ResponseWriter, status int, message string) {
+	writeMockJSON(rw, status, map[string]interface{}{"success": false, "error": message})
+}
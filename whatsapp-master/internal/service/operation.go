@@ -0,0 +1,41 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// accountOperation 账号上进行中的操作，完成后关闭done
+type accountOperation struct {
+	done   chan struct{}
+	result interface{}
+	err    error
+}
+
+// RunAccountOperation 对同一账号的同名操作去重：没有进行中的操作时执行fn；
+// 已有进行中的操作时不再执行fn，而是等待它完成并返回相同的结果（joined为true）。
+// ctx只控制等待，取消后返回ctx的错误，进行中的操作不受影响
+func (m *Manager) RunAccountOperation(ctx context.Context, accountID, name string, fn func() (interface{}, error)) (result interface{}, joined bool, err error) {
+	key := accountID + "/" + name
+	op := &accountOperation{done: make(chan struct{})}
+	if existing, loaded := m.operations.LoadOrStore(key, op); loaded {
+		running := existing.(*accountOperation)
+		slog.Info("Joining in-flight account operation", "account_id", accountID, "operation", name)
+		select {
+		case <-running.done:
+			return running.result, true, running.err
+		case <-ctx.Done():
+			return nil, true, ctx.Err()
+		}
+	}
+
+	defer func() {
+		m.operations.Delete(key)
+		close(op.done)
+	}()
+	// fn panic 时等待方也能得到错误
+	op.err = fmt.Errorf("%s on account %s did not complete", name, accountID)
+	op.result, op.err = fn()
+	return op.result, false, op.err
+}
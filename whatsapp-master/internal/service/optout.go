@@ -0,0 +1,154 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// ErrSuppressionNotFound 号码不在抑制名单中
+var ErrSuppressionNotFound = errors.New("contact is not suppressed")
+
+// suppressionColumns 抑制名单列表允许过滤和排序的字段
+var suppressionColumns = map[string]string{
+	"id":         "id",
+	"account_id": "account_id",
+	"contact":    "contact",
+	"campaign":   "campaign",
+	"source":     "source",
+	"created_at": "created_at",
+}
+
+// SuppressedContactError 收件人在抑制名单中，拒绝发送
+type SuppressedContactError struct {
+	Contact string
+}
+
+func (e *SuppressedContactError) Error() string {
+	return fmt.Sprintf("contact %s is on the suppression list", e.Contact)
+}
+
+// isOptOutKeyword 入站消息是否完全匹配配置的退订关键字（不区分大小写）
+func (m *Manager) isOptOutKeyword(keyword string) bool {
+	for _, k := range m.GetConfig().Contact.OptOutKeywords {
+		if strings.EqualFold(strings.TrimSpace(k), keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// detectOptOut 检查入站消息是否为退订回复，是则加入抑制名单并归属到该联系人最近一次活动
+func (m *Manager) detectOptOut(msg *model.Message) {
+	keyword := strings.ToLower(strings.TrimSpace(msg.Body))
+	if keyword == "" || !m.isOptOutKeyword(keyword) {
+		return
+	}
+
+	var last model.Message
+	m.db.Where("account_id = ? AND contact = ? AND direction = ? AND campaign <> ?", msg.AccountID, msg.Contact, "outbound", "").
+		Order("timestamp DESC").
+		Limit(1).
+		Find(&last)
+
+	optOut := &model.OptOut{
+		AccountID: msg.AccountID,
+		Contact:   msg.Contact,
+		Campaign:  last.Campaign,
+		MessageID: msg.ID,
+		Keyword:   keyword,
+		Source:    model.OptOutSourceKeyword,
+	}
+	if err := m.db.Create(optOut).Error; err != nil {
+		slog.Error("Failed to record opt-out", "contact", msg.Contact, "account_id", msg.AccountID, "error", err)
+		return
+	}
+
+	slog.Info("Contact opted out", "contact", msg.Contact, "account_id", msg.AccountID, "campaign", optOut.Campaign)
+	m.emit(EventContactOptedOut, msg.AccountID, optOut)
+}
+
+// suppressionContacts 号码在退订记录中可能的写法（带或不带 @c.us 后缀）
+func suppressionContacts(contact string) []string {
+	number := strings.TrimSuffix(contact, "@c.us")
+	return []string{number, number + "@c.us"}
+}
+
+// isOptedOut 联系人是否在抑制名单中
+func (m *Manager) isOptedOut(contact string) bool {
+	var count int64
+	m.db.Model(&model.OptOut{}).
+		Where("contact IN ?", suppressionContacts(contact)).
+		Count(&count)
+	return count > 0
+}
+
+// checkSuppressed 发送前检查收件人，在抑制名单中时返回 *SuppressedContactError
+func (m *Manager) checkSuppressed(contact string) error {
+	if m.isOptedOut(contact) {
+		return &SuppressedContactError{Contact: contact}
+	}
+	return nil
+}
+
+// ListSuppressions 分页查询抑制名单，默认按加入时间倒序
+func (m *Manager) ListSuppressions(q *model.ListQuery) ([]*model.OptOut, int64, error) {
+	entries := make([]*model.OptOut, 0)
+	total, err := findWithListQuery(m.db.Model(&model.OptOut{}), q, suppressionColumns, "-created_at", &entries)
+	if err != nil {
+		return nil, 0, err
+	}
+	return entries, total, nil
+}
+
+// GetSuppression 查询号码是否在抑制名单中
+func (m *Manager) GetSuppression(contact string) (*model.SuppressionStatus, error) {
+	entries := make([]*model.OptOut, 0)
+	if err := m.db.Where("contact IN ?", suppressionContacts(contact)).Order("created_at ASC").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to query suppression list: %v", err)
+	}
+	return &model.SuppressionStatus{Contact: contact, Suppressed: len(entries) > 0, Entries: entries}, nil
+}
+
+// AddSuppressions 手动将号码加入抑制名单，已在名单中的号码跳过，返回新加入的记录
+func (m *Manager) AddSuppressions(contacts []string, reason string) ([]*model.OptOut, error) {
+	added := make([]*model.OptOut, 0, len(contacts))
+	seen := make(map[string]bool, len(contacts))
+	for _, contact := range contacts {
+		number := strings.TrimSuffix(contact, "@c.us")
+		if seen[number] || m.isOptedOut(number) {
+			continue
+		}
+		seen[number] = true
+
+		optOut := &model.OptOut{
+			Contact: number,
+			Source:  model.OptOutSourceManual,
+			Reason:  reason,
+		}
+		if err := m.db.Create(optOut).Error; err != nil {
+			return added, fmt.Errorf("failed to add %s to suppression list: %v", number, err)
+		}
+		added = append(added, optOut)
+		m.emit(EventContactOptedOut, "", optOut)
+	}
+	slog.Info("Contacts added to suppression list", "requested", len(contacts), "added", len(added))
+	return added, nil
+}
+
+// RemoveSuppression 删除号码的全部退订记录，号码可以重新收到消息
+func (m *Manager) RemoveSuppression(contact string) (int64, error) {
+	res := m.db.Where("contact IN ?", suppressionContacts(contact)).Delete(&model.OptOut{})
+	if res.Error != nil {
+		return 0, fmt.Errorf("failed to remove %s from suppression list: %v", contact, res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return 0, ErrSuppressionNotFound
+	}
+	slog.Info("Contact removed from suppression list", "contact", contact, "entries", res.RowsAffected)
+	m.emit(EventContactOptedIn, "", map[string]interface{}{"contact": contact, "entries": res.RowsAffected})
+	return res.RowsAffected, nil
+}
@@ -0,0 +1,247 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"net/url"
+	"sort"
+	"strings"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// SetAccountOwner 设置账号负责人信息
+func (m *Manager) SetAccountOwner(accountID string, owner *model.AccountOwner) (*model.Account, error) {
+	if err := validateAccountOwner(owner); err != nil {
+		return nil, err
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return nil, fmt.Errorf("account %s not found", accountID)
+	}
+
+	applyAccountOwner(account, owner)
+	if err := m.db.Model(account).Updates(map[string]interface{}{
+		"owner_operator": account.OwnerOperator,
+		"owner_team":     account.OwnerTeam,
+		"owner_email":    account.OwnerEmail,
+		"owner_channel":  account.OwnerChannel,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("failed to update account owner: %v", err)
+	}
+	return account.Clone(), nil
+}
+
+// AssignAccounts 批量将账号分配给运营人员或团队，只修改请求中提供的字段；任一账号不存在时不做修改
+func (m *Manager) AssignAccounts(req *model.AssignAccountsRequest) ([]*model.Account, error) {
+	if req.Operator == nil && req.Team == nil {
+		return nil, fmt.Errorf("operator or team is required")
+	}
+	updates := make(map[string]interface{})
+	if req.Operator != nil {
+		updates["owner_operator"] = strings.TrimSpace(*req.Operator)
+	}
+	if req.Team != nil {
+		updates["owner_team"] = strings.TrimSpace(*req.Team)
+	}
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	accounts := make([]*model.Account, 0, len(req.AccountIDs))
+	for _, accountID := range req.AccountIDs {
+		account, exists := m.accounts[accountID]
+		if !exists {
+			return nil, fmt.Errorf("account %s not found", accountID)
+		}
+		accounts = append(accounts, account)
+	}
+
+	if err := m.db.Model(&model.Account{}).Where("id IN ?", req.AccountIDs).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("failed to assign accounts: %v", err)
+	}
+	for i, account := range accounts {
+		if req.Operator != nil {
+			account.OwnerOperator = updates["owner_operator"].(string)
+		}
+		if req.Team != nil {
+			account.OwnerTeam = updates["owner_team"].(string)
+		}
+		accounts[i] = account.Clone()
+	}
+	slog.Info("Accounts assigned", "count", len(accounts), "operator", updates["owner_operator"], "team", updates["owner_team"])
+	return accounts, nil
+}
+
+// ListOwners 列出账号的运营人员和团队及其名下账号数，tenantID不为空时只统计该租户的账号
+func (m *Manager) ListOwners(tenantID string) []*model.OwnerSummary {
+	m.mutex.RLock()
+	counts := make(map[[2]string]int)
+	for _, account := range m.accounts {
+		if tenantID != "" && account.TenantID != tenantID {
+			continue
+		}
+		if account.OwnerOperator != "" {
+			counts[[2]string{"operator", account.OwnerOperator}]++
+		}
+		if account.OwnerTeam != "" {
+			counts[[2]string{"team", account.OwnerTeam}]++
+		}
+	}
+	m.mutex.RUnlock()
+
+	owners := make([]*model.OwnerSummary, 0, len(counts))
+	for key, count := range counts {
+		owners = append(owners, &model.OwnerSummary{Kind: key[0], Owner: key[1], Accounts: count})
+	}
+	sort.Slice(owners, func(i, j int) bool {
+		if owners[i].Kind != owners[j].Kind {
+			return owners[i].Kind < owners[j].Kind
+		}
+		return owners[i].Owner < owners[j].Owner
+	})
+	return owners
+}
+
+// AccountOwnedBy 账号是否分配给了指定的运营人员或团队
+func (m *Manager) AccountOwnedBy(accountID, owner string) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	account, exists := m.accounts[accountID]
+	return exists && owner != "" && (account.OwnerOperator == owner || account.OwnerTeam == owner)
+}
+
+// validateAccountOwner 校验负责人信息，告警通道必须是http(s)地址
+func validateAccountOwner(owner *model.AccountOwner) error {
+	if owner.Channel == "" {
+		return nil
+	}
+	u, err := url.Parse(owner.Channel)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("invalid owner channel %q: must be an http(s) webhook URL", owner.Channel)
+	}
+	return nil
+}
+
+// applyAccountOwner 将负责人信息写入账号
+func applyAccountOwner(account *model.Account, owner *model.AccountOwner) {
+	account.OwnerOperator = strings.TrimSpace(owner.Operator)
+	account.OwnerTeam = strings.TrimSpace(owner.Team)
+	account.OwnerEmail = strings.TrimSpace(owner.Email)
+	account.OwnerChannel = strings.TrimSpace(owner.Channel)
+}
+
+// StartAlerter 订阅故障事件，按账号负责人的告警通道和告警路由规则发送通知
+func (m *Manager) StartAlerter() {
+	alertEvents := make(map[string]bool)
+	for _, eventType := range m.GetConfig().Alert.Events {
+		alertEvents[eventType] = true
+	}
+
+	events, unsubscribe := m.events.Subscribe(64)
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer unsubscribe()
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				m.routeIncident(event, alertEvents[event.Type])
+			}
+		}
+	}()
+}
+
+// routeIncident 将事件转为故障通知：ALERT_EVENTS 中的事件优先发往账号负责人的通道，未设置时使用全局Webhook；
+// 另外发往所有匹配的告警路由规则的通道
+func (m *Manager) routeIncident(event *model.Event, alerting bool) {
+	incident := &model.Incident{
+		Event:     event.Type,
+		AccountID: event.AccountID,
+		Data:      event.Data,
+		Timestamp: event.Timestamp,
+	}
+
+	var account *model.Account
+	channel := m.GetConfig().Alert.WebhookURL
+	m.mutex.RLock()
+	if current, exists := m.accounts[event.AccountID]; exists {
+		copied := *current
+		account = &copied
+		incident.Status = account.Status
+		incident.OwnerTeam = account.OwnerTeam
+		incident.OwnerEmail = account.OwnerEmail
+		if account.OwnerChannel != "" {
+			channel = account.OwnerChannel
+		}
+	}
+	m.mutex.RUnlock()
+
+	if !alerting {
+		channel = ""
+	}
+	channels := m.matchAlertChannels(event.Type, alerting, account)
+	if channel == "" && len(channels) == 0 {
+		return
+	}
+
+	incident.Text = fmt.Sprintf("[%s] account %s: %s (status: %s)", event.Type, event.AccountID, incidentSummary(event), incident.Status)
+	if event.AccountID == "" {
+		// 主机等不属于单个账号的事件
+		incident.Text = fmt.Sprintf("[%s] %s", event.Type, incidentSummary(event))
+	}
+	if incident.OwnerTeam != "" || incident.OwnerEmail != "" {
+		incident.Text += fmt.Sprintf(", owner: %s", strings.Trim(incident.OwnerTeam+" "+incident.OwnerEmail, " "))
+	}
+
+	if channel != "" {
+		go m.deliverIncident(channel, incident)
+	}
+	for _, target := range channels {
+		go m.notifyChannel(target, incident)
+	}
+}
+
+// incidentSummary 生成故障通知的简要说明
+func incidentSummary(event *model.Event) string {
+	switch event.Type {
+	case EventWorkerCrashLooping:
+		return "worker keeps crashing, automatic restarts stopped"
+	case EventWorkerRestartFailed:
+		return "worker restart failed"
+	case EventAccountLoggedOut:
+		return "WhatsApp session lost"
+	case EventAccountBanned:
+		if data, ok := event.Data.(map[string]string); ok {
+			return fmt.Sprintf("number looks banned (%s: %s), account quarantined and sends stopped", data["source"], data["signal"])
+		}
+	case EventHostOffline:
+		if data, ok := event.Data.(map[string]interface{}); ok {
+			return fmt.Sprintf("host %v stopped responding", data["host_id"])
+		}
+	case EventProxyDown:
+		return "proxy is not reachable, the worker has no working exit IP"
+	case EventDiskLow:
+		if data, ok := event.Data.(map[string]interface{}); ok {
+			return fmt.Sprintf("only %.1f%% disk space free on %v", data["free_percent"], data["path"])
+		}
+	}
+	return event.Type
+}
+
+// deliverIncident 发送故障通知到Webhook
+func (m *Manager) deliverIncident(channel string, incident *model.Incident) {
+	if err := postAlert(channel, incident); err != nil {
+		slog.Warn("Failed to deliver incident", "event", incident.Event, "account_id", incident.AccountID, "error", err)
+	}
+}
@@ -1,40 +1,190 @@
 package service
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
+
+	"whatsapp-aggregator/internal/config"
+	"whatsapp-aggregator/internal/model"
 )
 
-// PortPool 端口池管理器
+// ErrPortPoolExhausted 端口池没有足够的可用端口启动新的Worker
+var ErrPortPoolExhausted = errors.New("port pool exhausted")
+
+// PortPool 端口池管理器，可由多段端口组成，运行时可通过配置接口调整
 type PortPool struct {
-	startPort int
-	endPort   int
-	used      map[int]bool
-	mutex     sync.Mutex
+	ranges   []model.PortRange
+	used     map[int]bool
+	occupied map[int]time.Time // 分配时探测到被其他进程占用的端口 -> 探测时间
+	mutex    sync.Mutex
 }
 
 // NewPortPool 创建端口池
-func NewPortPool(startPort, endPort int) *PortPool {
+func NewPortPool(ranges []model.PortRange) *PortPool {
 	return &PortPool{
-		startPort: startPort,
-		endPort:   endPort,
-		used:      make(map[int]bool),
+		ranges:   ranges,
+		used:     make(map[int]bool),
+		occupied: make(map[int]time.Time),
 	}
 }
 
-// Allocate 分配一个可用端口
+// parsePortRanges 解析端口段配置，如 4000-4999,6000-6499，单个端口可写作 7000；端口段不能重叠
+func parsePortRanges(spec string) ([]model.PortRange, error) {
+	var ranges []model.PortRange
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		startRaw, endRaw, isRange := strings.Cut(part, "-")
+		if !isRange {
+			endRaw = startRaw
+		}
+		start, err1 := strconv.Atoi(strings.TrimSpace(startRaw))
+		end, err2 := strconv.Atoi(strings.TrimSpace(endRaw))
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("invalid port range %q", part)
+		}
+		if start < 1 || end > 65535 || start > end {
+			return nil, fmt.Errorf("invalid port range %q: ports must be within 1-65535 and start must not exceed end", part)
+		}
+		ranges = append(ranges, model.PortRange{Start: start, End: end})
+	}
+	if len(ranges) == 0 {
+		return nil, fmt.Errorf("no port ranges configured")
+	}
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+	for i := 1; i < len(ranges); i++ {
+		if ranges[i].Start <= ranges[i-1].End {
+			return nil, fmt.Errorf("port ranges %s and %s overlap", formatPortRange(ranges[i-1]), formatPortRange(ranges[i]))
+		}
+	}
+	return ranges, nil
+}
+
+// formatPortRange 端口段的文本形式
+func formatPortRange(r model.PortRange) string {
+	if r.Start == r.End {
+		return strconv.Itoa(r.Start)
+	}
+	return fmt.Sprintf("%d-%d", r.Start, r.End)
+}
+
+// formatPortRanges 多段端口的文本形式，与 WORKER_PORT_RANGES 格式相同
+func formatPortRanges(ranges []model.PortRange) string {
+	parts := make([]string, len(ranges))
+	for i, r := range ranges {
+		parts[i] = formatPortRange(r)
+	}
+	return strings.Join(parts, ",")
+}
+
+// workerPortRanges Worker端口段：配置了 WORKER_PORT_RANGES 时使用该配置，否则为 WORKER_BASE_PORT 起的 WORKER_PORT_RANGE 个端口
+func workerPortRanges(cfg *config.Config) ([]model.PortRange, error) {
+	if strings.TrimSpace(cfg.Worker.PortRanges) != "" {
+		return parsePortRanges(cfg.Worker.PortRanges)
+	}
+	if cfg.Worker.PortRange < 1 {
+		return nil, fmt.Errorf("invalid WORKER_PORT_RANGE %d", cfg.Worker.PortRange)
+	}
+	return parsePortRanges(fmt.Sprintf("%d-%d", cfg.Worker.BasePort, cfg.Worker.BasePort+cfg.Worker.PortRange-1))
+}
+
+// SetRanges 替换端口段。已分配的端口即使不在新的端口段内也保留记录，直到账号删除时释放，但不会再被分配
+func (p *PortPool) SetRanges(ranges []model.PortRange) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.ranges = ranges
+	for port := range p.occupied {
+		if !p.containsLocked(port) {
+			delete(p.occupied, port)
+		}
+	}
+}
+
+// Ranges 当前的端口段
+func (p *PortPool) Ranges() []model.PortRange {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	return append([]model.PortRange(nil), p.ranges...)
+}
+
+// containsLocked 端口是否在端口段内，调用方需持有mutex
+func (p *PortPool) containsLocked(port int) bool {
+	for _, r := range p.ranges {
+		if port >= r.Start && port <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
+// totalLocked 端口段内的端口总数，调用方需持有mutex
+func (p *PortPool) totalLocked() int {
+	total := 0
+	for _, r := range p.ranges {
+		total += r.End - r.Start + 1
+	}
+	return total
+}
+
+// countsLocked 端口段内已分配、被占用和可用的端口数，调用方需持有mutex
+func (p *PortPool) countsLocked() (allocated, occupied, available int) {
+	for port := range p.used {
+		if p.containsLocked(port) {
+			allocated++
+		}
+	}
+	occupied = len(p.occupied)
+	available = max(p.totalLocked()-allocated-occupied, 0)
+	return allocated, occupied, available
+}
+
+// Allocate 按端口段顺序分配一个可用端口，没有可用端口时返回 ErrPortPoolExhausted
 func (p *PortPool) Allocate() (int, error) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
-	for port := p.startPort; port <= p.endPort; port++ {
-		if !p.used[port] {
+	for _, r := range p.ranges {
+		for port := r.Start; port <= r.End; port++ {
+			if p.used[port] {
+				continue
+			}
+			// 端口池只记录自己分配的端口，分配前确认端口没有被其他进程占用
+			if !portBindable(port) {
+				p.occupied[port] = time.Now()
+				continue
+			}
+			delete(p.occupied, port)
 			p.used[port] = true
 			return port, nil
 		}
 	}
 
-	return 0, fmt.Errorf("no available ports in range %d-%d", p.startPort, p.endPort)
+	if len(p.occupied) > 0 {
+		return 0, fmt.Errorf("%w: no available ports in %s (%d occupied by other processes)", ErrPortPoolExhausted, formatPortRanges(p.ranges), len(p.occupied))
+	}
+	return 0, fmt.Errorf("%w: no available ports in %s", ErrPortPoolExhausted, formatPortRanges(p.ranges))
+}
+
+// portBindable 探测端口当前是否可以绑定
+func portBindable(port int) bool {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		return false
+	}
+	listener.Close()
+	return true
 }
 
 // Release 释放端口
@@ -50,8 +200,9 @@ func (p *PortPool) Reserve(port int) {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
-	if port >= p.startPort && port <= p.endPort {
+	if p.containsLocked(port) {
 		p.used[port] = true
+		delete(p.occupied, port)
 	}
 }
 
@@ -76,11 +227,95 @@ func (p *PortPool) GetUsedPorts() []int {
 	return ports
 }
 
-// GetAvailableCount 获取可用端口数量
+// GetAvailableCount 获取可用端口数量，不含已分配和探测到被其他进程占用的端口
 func (p *PortPool) GetAvailableCount() int {
 	p.mutex.Lock()
 	defer p.mutex.Unlock()
 
-	total := p.endPort - p.startPort + 1
-	return total - len(p.used)
+	_, _, available := p.countsLocked()
+	return available
+}
+
+// Probe 探测所有未分配端口，刷新被其他进程占用的端口记录
+func (p *PortPool) Probe() {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	for _, r := range p.ranges {
+		for port := r.Start; port <= r.End; port++ {
+			if p.used[port] {
+				continue
+			}
+			if portBindable(port) {
+				delete(p.occupied, port)
+			} else {
+				p.occupied[port] = now
+			}
+		}
+	}
+}
+
+// syncPortRangesLocked 端口段配置热更新后替换端口池的端口段，调用方需持有mutex
+func (m *Manager) syncPortRangesLocked() {
+	ranges, err := workerPortRanges(m.GetConfig())
+	if err != nil {
+		slog.Warn("Ignoring invalid worker port ranges", "error", err)
+		return
+	}
+	m.portPool.SetRanges(ranges)
+	slog.Info("Worker port ranges updated", "ranges", formatPortRanges(ranges))
+}
+
+// checkPortCapacity 创建前的准入检查：端口池剩余端口不足n个时返回 ErrPortPoolExhausted，
+// 避免任务启动后才因分配端口失败；不足时先重新探测，被其他进程占用的端口可能已经释放
+func (m *Manager) checkPortCapacity(n int) error {
+	if m.portPool.GetAvailableCount() >= n {
+		return nil
+	}
+	m.portPool.Probe()
+	if free := m.portPool.GetAvailableCount(); free < n {
+		return fmt.Errorf("%w: %d free ports in %s, %d needed; extend worker.portRanges", ErrPortPoolExhausted, free, formatPortRanges(m.portPool.Ranges()), n)
+	}
+	return nil
+}
+
+// GetPortStatus 获取端口池分配状态，probe为true时先探测所有未分配端口
+func (m *Manager) GetPortStatus(probe bool) *model.PortPoolStatus {
+	if probe {
+		m.portPool.Probe()
+	}
+
+	m.mutex.RLock()
+	owners := make(map[int]string, len(m.accounts))
+	for _, account := range m.accounts {
+		if account.Port != 0 {
+			owners[account.Port] = account.ID
+		}
+	}
+	m.mutex.RUnlock()
+
+	p := m.portPool
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	status := &model.PortPoolStatus{
+		Ranges: append([]model.PortRange(nil), p.ranges...),
+		Total:  p.totalLocked(),
+		Ports:  []model.PortAllocation{},
+	}
+	if len(p.ranges) > 0 {
+		status.StartPort = p.ranges[0].Start
+		status.EndPort = p.ranges[len(p.ranges)-1].End
+	}
+	status.Allocated, status.Occupied, status.Available = p.countsLocked()
+
+	for port := range p.used {
+		status.Ports = append(status.Ports, model.PortAllocation{Port: port, State: model.PortStateAllocated, AccountID: owners[port]})
+	}
+	for port, checkedAt := range p.occupied {
+		status.Ports = append(status.Ports, model.PortAllocation{Port: port, State: model.PortStateOccupied, CheckedAt: &checkedAt})
+	}
+	sort.Slice(status.Ports, func(i, j int) bool { return status.Ports[i].Port < status.Ports[j].Port })
+	return status
 }
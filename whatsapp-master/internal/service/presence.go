@@ -0,0 +1,125 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"net/url"
+	"time"
+	"unicode/utf8"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// 输入状态，与Worker的 /api/typing 对应
+const (
+	ChatStateTyping    = "typing"
+	ChatStateRecording = "recording"
+	ChatStatePaused    = "paused"
+)
+
+// SendTyping 在与联系人的会话中显示正在输入或录音，paused 清除状态
+func (m *Manager) SendTyping(ctx context.Context, accountID string, req *model.TypingRequest) (*model.ChatState, error) {
+	account, err := m.GetAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	state := req.State
+	if state == "" {
+		state = ChatStateTyping
+	}
+	if state == ChatStatePaused {
+		req.DurationMs = 0
+	}
+
+	if _, err := m.postToWorker(ctx, account, "/api/typing", map[string]interface{}{
+		"contact":     req.Contact,
+		"state":       state,
+		"duration_ms": req.DurationMs,
+	}); err != nil {
+		return nil, err
+	}
+	return &model.ChatState{AccountID: accountID, Contact: req.Contact, State: state, DurationMs: req.DurationMs}, nil
+}
+
+// GetPresence 通过Worker查询联系人的在线状态
+func (m *Manager) GetPresence(ctx context.Context, accountID, contact string) (*model.ContactPresence, error) {
+	result, err := m.FetchFromWorker(ctx, accountID, "/api/presence?contact="+url.QueryEscape(contact))
+	if err != nil {
+		return nil, err
+	}
+	var data model.WorkerPresence
+	if err := decodeWorkerData(result, &data); err != nil {
+		return nil, err
+	}
+
+	presence := &model.ContactPresence{
+		AccountID: accountID,
+		Contact:   contact,
+		Online:    data.Online,
+		State:     data.State,
+		CheckedAt: time.Now(),
+	}
+	if data.LastSeen > 0 {
+		lastSeen := time.UnixMilli(data.LastSeen)
+		presence.LastSeen = &lastSeen
+	}
+	return presence, nil
+}
+
+// SetAccountTypingSimulation 开启或关闭账号发送文本前的模拟输入
+func (m *Manager) SetAccountTypingSimulation(accountID string, enabled bool) (*model.Account, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return nil, fmt.Errorf("account %s not found", accountID)
+	}
+	if err := m.db.Model(account).Update("typing_simulation", enabled).Error; err != nil {
+		return nil, fmt.Errorf("failed to update account typing simulation: %v", err)
+	}
+	account.TypingSimulation = enabled
+	return account.Clone(), nil
+}
+
+// typingDelay 按消息长度和输入速度计算模拟输入时长，限制在最短和最长时长之间并加入随机浮动
+func (m *Manager) typingDelay(message string) time.Duration {
+	cfg := m.GetConfig().Typing
+	delay := float64(utf8.RuneCountInString(message)) / float64(max(cfg.CharsPerSecond, 1)) * 1000
+	if cfg.JitterPercent > 0 {
+		delay *= 1 + (rand.Float64()*2-1)*float64(cfg.JitterPercent)/100
+	}
+	delay = max(delay, float64(cfg.MinDelayMs))
+	if cfg.MaxDelayMs > 0 {
+		delay = min(delay, float64(cfg.MaxDelayMs))
+	}
+	return time.Duration(delay) * time.Millisecond
+}
+
+// simulateTyping 账号开启模拟输入时，先显示正在输入再等待按消息长度计算的时长，发送消息后WhatsApp自动清除输入状态；
+// 输入状态发送失败只记录日志，不影响消息发送
+func (m *Manager) simulateTyping(ctx context.Context, account *model.Account, contact, message string) {
+	m.mutex.RLock()
+	enabled := account.TypingSimulation
+	m.mutex.RUnlock()
+	if !enabled {
+		return
+	}
+
+	delay := m.typingDelay(message)
+	if _, err := m.postToWorker(ctx, account, "/api/typing", map[string]interface{}{
+		"contact": contact,
+		"state":   ChatStateTyping,
+	}); err != nil {
+		slog.Debug("Failed to send typing state", "account_id", account.ID, "error", err)
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
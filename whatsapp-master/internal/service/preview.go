@@ -0,0 +1,225 @@
+package service
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"html"
+	"image"
+	_ "image/gif" // 注册GIF解码
+	"image/jpeg"
+	_ "image/png" // 注册PNG解码
+	"net/url"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// 媒体链接变体
+const (
+	MediaVariantOriginal  = "original"
+	MediaVariantThumbnail = "thumbnail"
+)
+
+// thumbnailMaxSize 缩略图最长边像素
+const thumbnailMaxSize = 320
+
+var (
+	previewCodeBlock = regexp.MustCompile("(?s)```(.+?)```")
+	previewCode      = regexp.MustCompile("`([^`\n]+)`")
+	previewLink      = regexp.MustCompile(`https?://[^\s<]+[^\s<.,;:!?)\]'"]`)
+	previewBold      = regexp.MustCompile(`(^|[\s(])\*([^\s*](?:[^*\n]*[^\s*])?)\*`)
+	previewItalic    = regexp.MustCompile(`(^|[\s(])_([^\s_](?:[^_\n]*[^\s_])?)_`)
+	previewStrike    = regexp.MustCompile(`(^|[\s(])~([^\s~](?:[^~\n]*[^\s~])?)~`)
+	previewToken     = regexp.MustCompile("\x00(\\d+)\x00")
+)
+
+// GetMessagePreview 生成消息的HTML预览，媒体附带签名链接
+func (m *Manager) GetMessagePreview(messageID string) (*model.MessagePreview, error) {
+	var msg model.Message
+	if err := m.db.Where("id = ?", messageID).First(&msg).Error; err != nil {
+		return nil, fmt.Errorf("message %s not found", messageID)
+	}
+
+	preview := &model.MessagePreview{
+		ID:        msg.ID,
+		AccountID: msg.AccountID,
+		Direction: msg.Direction,
+		Contact:   msg.Contact,
+		Type:      msg.Type,
+		Text:      messagePreview(msg.Type, msg.Body),
+		MimeType:  msg.MimeType,
+		FileName:  msg.FileName,
+		Timestamp: msg.Timestamp,
+	}
+
+	var media string
+	if msg.MediaPath != "" {
+		expires := time.Now().Add(time.Duration(m.GetConfig().Media.URLTTLMinutes) * time.Minute)
+		preview.URLExpiresAt = &expires
+		preview.MediaURL = m.signedMediaURL(msg.ID, MediaVariantOriginal, expires)
+		if msg.Type == "image" {
+			preview.ThumbnailURL = m.signedMediaURL(msg.ID, MediaVariantThumbnail, expires)
+		}
+		media = renderMediaHTML(&msg, preview)
+	} else if msg.Type != "" && msg.Type != "chat" {
+		media = fmt.Sprintf(`<div class="wa-media wa-media-unavailable">[%s]</div>`, html.EscapeString(msg.Type))
+	}
+
+	preview.HTML = media + renderWhatsAppMarkup(msg.Body)
+	return preview, nil
+}
+
+// renderMediaHTML 生成媒体部分的HTML
+func renderMediaHTML(msg *model.Message, preview *model.MessagePreview) string {
+	src := html.EscapeString(preview.MediaURL)
+	name := html.EscapeString(valueOrDefault(msg.FileName, msg.Type))
+
+	switch msg.Type {
+	case "image":
+		return fmt.Sprintf(`<figure class="wa-media wa-image"><a href="%s" target="_blank" rel="noopener noreferrer"><img src="%s" alt="%s" loading="lazy"></a></figure>`,
+			src, html.EscapeString(preview.ThumbnailURL), name)
+	case "audio":
+		return fmt.Sprintf(`<div class="wa-media wa-audio"><audio controls preload="none" src="%s"></audio></div>`, src)
+	default:
+		return fmt.Sprintf(`<div class="wa-media wa-document"><a href="%s" target="_blank" rel="noopener noreferrer" download="%s">%s</a></div>`,
+			src, name, name)
+	}
+}
+
+// renderWhatsAppMarkup 将WhatsApp格式（*粗体* _斜体_ ~删除线~ ```等宽``` `代码` > 引用）转换为安全的HTML
+func renderWhatsAppMarkup(body string) string {
+	if body == "" {
+		return ""
+	}
+
+	// 先转义，再用占位符保护代码和链接，避免其内容被继续解析
+	text := html.EscapeString(body)
+	var tokens []string
+	protect := func(fragment string) string {
+		tokens = append(tokens, fragment)
+		return fmt.Sprintf("\x00%d\x00", len(tokens)-1)
+	}
+
+	text = previewCodeBlock.ReplaceAllStringFunc(text, func(s string) string {
+		return protect("<pre>" + previewCodeBlock.FindStringSubmatch(s)[1] + "</pre>")
+	})
+	text = previewCode.ReplaceAllStringFunc(text, func(s string) string {
+		return protect("<code>" + previewCode.FindStringSubmatch(s)[1] + "</code>")
+	})
+	text = previewLink.ReplaceAllStringFunc(text, func(s string) string {
+		return protect(fmt.Sprintf(`<a href="%s" target="_blank" rel="noopener noreferrer">%s</a>`, s, s))
+	})
+
+	text = previewBold.ReplaceAllString(text, "$1<strong>$2</strong>")
+	text = previewItalic.ReplaceAllString(text, "$1<em>$2</em>")
+	text = previewStrike.ReplaceAllString(text, "$1<del>$2</del>")
+
+	// 引用行与换行
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, "&gt; ") {
+			lines[i] = "<blockquote>" + strings.TrimPrefix(line, "&gt; ") + "</blockquote>"
+		}
+	}
+	text = strings.Join(lines, "<br>")
+	text = strings.ReplaceAll(text, "</blockquote><br>", "</blockquote>")
+
+	text = previewToken.ReplaceAllStringFunc(text, func(s string) string {
+		idx, _ := strconv.Atoi(previewToken.FindStringSubmatch(s)[1])
+		return tokens[idx]
+	})
+
+	return `<div class="wa-text">` + text + `</div>`
+}
+
+// signedMediaURL 生成带过期时间和签名的媒体访问链接
+func (m *Manager) signedMediaURL(messageID, variant string, expires time.Time) string {
+	exp := strconv.FormatInt(expires.Unix(), 10)
+	query := url.Values{}
+	query.Set("variant", variant)
+	query.Set("expires", exp)
+	query.Set("sig", m.mediaSignature(messageID, variant, exp))
+	return fmt.Sprintf("%s/media/%s?%s", strings.TrimRight(m.GetConfig().Media.BaseURL, "/"), url.PathEscape(messageID), query.Encode())
+}
+
+// mediaSignature 计算媒体链接签名
+func (m *Manager) mediaSignature(messageID, variant, expires string) string {
+	mac := hmac.New(sha256.New, []byte(m.GetConfig().Media.SigningKey))
+	mac.Write([]byte(messageID + "|" + variant + "|" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// OpenSignedMedia 校验签名并读取媒体内容，缩略图按需生成
+func (m *Manager) OpenSignedMedia(messageID, variant, expires, sig string) ([]byte, string, error) {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > exp {
+		return nil, "", fmt.Errorf("media link expired")
+	}
+	if !hmac.Equal([]byte(sig), []byte(m.mediaSignature(messageID, variant, expires))) {
+		return nil, "", fmt.Errorf("invalid media signature")
+	}
+
+	var msg model.Message
+	if err := m.db.Where("id = ?", messageID).First(&msg).Error; err != nil || msg.MediaPath == "" {
+		return nil, "", fmt.Errorf("media not found")
+	}
+
+	data, err := os.ReadFile(msg.MediaPath)
+	if err != nil {
+		return nil, "", fmt.Errorf("media not found")
+	}
+
+	if variant == MediaVariantThumbnail {
+		if thumb, err := makeThumbnail(data); err == nil {
+			return thumb, "image/jpeg", nil
+		}
+	}
+	return data, valueOrDefault(msg.MimeType, "application/octet-stream"), nil
+}
+
+// makeThumbnail 将图片等比缩小到最长边不超过thumbnailMaxSize
+func makeThumbnail(data []byte) ([]byte, error) {
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	bounds := src.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w > thumbnailMaxSize || h > thumbnailMaxSize {
+		if w >= h {
+			w, h = thumbnailMaxSize, h*thumbnailMaxSize/w
+		} else {
+			w, h = w*thumbnailMaxSize/h, thumbnailMaxSize
+		}
+	}
+	if w < 1 {
+		w = 1
+	}
+	if h < 1 {
+		h = 1
+	}
+
+	// 最近邻缩放
+	dst := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		sy := bounds.Min.Y + y*bounds.Dy()/h
+		for x := 0; x < w; x++ {
+			sx := bounds.Min.X + x*bounds.Dx()/w
+			dst.Set(x, y, src.At(sx, sy))
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"whatsapp-aggregator/internal/logging"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/tracing"
+)
+
+// 批量创建中单个账号的状态
+const (
+	provisionPending = "pending"
+	provisionCreated = "created"
+	provisionFailed  = "failed"
+	provisionSkipped = "skipped"
+)
+
+// CreateAccountsBulk 为每个号码创建账号：已存在或重复的号码跳过，其余在后台任务中以有限并发启动Worker，
+// 端口池剩余端口不足时拒绝整个请求
+func (m *Manager) CreateAccountsBulk(ctx context.Context, req *model.BulkCreateAccountsRequest) (*model.BulkCreateAccountsResult, error) {
+	if req.Owner != nil {
+		if err := validateAccountOwner(req.Owner); err != nil {
+			return nil, err
+		}
+	}
+	if req.Resources != nil {
+		if err := validateWorkerResources(req.Resources); err != nil {
+			return nil, err
+		}
+	}
+	if req.Runtime != nil {
+		if err := validateWorkerRuntime(req.Runtime); err != nil {
+			return nil, err
+		}
+	}
+	if req.HostID != "" {
+		if err := m.checkHostPin(req.HostID); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &model.BulkCreateAccountsResult{Accounts: make([]*model.BulkAccountResult, 0, len(req.Phones))}
+	pending := make([]*model.BulkAccountResult, 0, len(req.Phones))
+	seen := make(map[string]bool)
+	m.mutex.RLock()
+	for _, phone := range req.Phones {
+		item := &model.BulkAccountResult{Phone: phone, AccountID: phone, Status: provisionPending}
+		switch {
+		case seen[phone]:
+			item.Status, item.Error = provisionSkipped, "duplicate phone number"
+		case m.accounts[phone] != nil:
+			item.Status, item.Error = provisionSkipped, fmt.Sprintf("account %s already exists", phone)
+		default:
+			pending = append(pending, item)
+		}
+		seen[phone] = true
+		result.Accounts = append(result.Accounts, item)
+	}
+	concurrency := m.GetConfig().Worker.ProvisionConcurrency
+	m.mutex.RUnlock()
+	result.Skipped = len(result.Accounts) - len(pending)
+
+	if len(pending) == 0 {
+		return nil, fmt.Errorf("all accounts already exist")
+	}
+	if err := m.checkPortCapacity(len(pending)); err != nil {
+		return nil, err
+	}
+	if req.Concurrency > 0 && (concurrency <= 0 || req.Concurrency < concurrency) {
+		concurrency = req.Concurrency
+	}
+	concurrency = max(concurrency, 1)
+
+	// 任务在后台修改result，返回给调用方的是启动时的副本
+	response := &model.BulkCreateAccountsResult{Skipped: result.Skipped, Accounts: make([]*model.BulkAccountResult, 0, len(result.Accounts))}
+	for _, item := range result.Accounts {
+		copied := *item
+		response.Accounts = append(response.Accounts, &copied)
+	}
+
+	requestID := logging.RequestID(ctx)
+	job, err := m.startTenantJob(req.TenantID, JobCreateAccounts, fmt.Sprintf("create %d accounts", len(pending)), len(pending), func(r *jobRun) error {
+		var resultMutex sync.Mutex
+		defer func() {
+			resultMutex.Lock()
+			r.SetResult(result)
+			resultMutex.Unlock()
+		}()
+
+		sem := make(chan struct{}, concurrency)
+		var wg sync.WaitGroup
+		for i, item := range pending {
+			sem <- struct{}{}
+			if r.Cancelled() || m.shuttingDown() {
+				<-sem
+				resultMutex.Lock()
+				item.Status, item.Error = provisionSkipped, "job cancelled"
+				result.Skipped++
+				resultMutex.Unlock()
+				continue
+			}
+
+			wg.Add(1)
+			go func(i int, item *model.BulkAccountResult) {
+				defer wg.Done()
+				defer func() { <-sem }()
+
+				accountCtx := logging.WithRequestID(tracing.ContinueFrom(r.Context(), ctx), requestID)
+				account, err := m.CreateAccount(accountCtx, bulkLoginRequest(req, item, i))
+
+				resultMutex.Lock()
+				if err != nil {
+					slog.Warn("Bulk account creation failed", "account_id", item.AccountID, "error", err)
+					item.Status, item.Error = provisionFailed, err.Error()
+					result.Failed++
+				} else {
+					item.Status, item.Account = provisionCreated, account
+					result.Created++
+				}
+				resultMutex.Unlock()
+				r.Advance(1)
+			}(i, item)
+		}
+		wg.Wait()
+
+		slog.Info("Bulk account creation finished", "created", result.Created, "failed", result.Failed, "skipped", result.Skipped)
+		if result.Failed > 0 {
+			return fmt.Errorf("%d of %d accounts failed", result.Failed, len(pending))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	response.Job = job
+	return response, nil
+}
+
+// bulkLoginRequest 用批量请求的共用设置构造单个账号的创建请求，代理按顺序轮流分配
+func bulkLoginRequest(req *model.BulkCreateAccountsRequest, item *model.BulkAccountResult, index int) *model.LoginRequest {
+	loginReq := &model.LoginRequest{
+		AccountID:         item.AccountID,
+		LoginMethod:       "phone",
+		Phone:             item.Phone,
+		HardwareInfo:      req.HardwareInfo,
+		HardwareProfileID: req.HardwareProfileID,
+		Tags:              req.Tags,
+		Pool:              req.Pool,
+		Owner:             req.Owner,
+		Resources:         req.Resources,
+		Runtime:           req.Runtime,
+		HostID:            req.HostID,
+		TenantID:          req.TenantID,
+	}
+	if len(req.Proxies) > 0 {
+		proxy := req.Proxies[index%len(req.Proxies)]
+		loginReq.ProxyConfig = &proxy
+	}
+	return loginReq
+}
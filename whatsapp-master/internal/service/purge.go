@@ -0,0 +1,169 @@
+package service
+
+import (
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// shredChunk 覆写会话文件时每次写入的字节数
+const shredChunk = 64 << 10
+
+// purgeSession 覆写并删除账号的会话目录和加密归档，并删除账号保存的媒体文件，返回回收的字节数；
+// 远程主机上的会话通过一次性容器执行，无法统计大小
+func (m *Manager) purgeSession(hostID, accountID string) (int64, error) {
+	size, err := m.shredSessionPaths(hostID, accountID, accountID+sealedSessionSuffix)
+	if err != nil {
+		return size, err
+	}
+	mediaSize, err := m.purgeMedia(accountID)
+	return size + mediaSize, err
+}
+
+// shredPlainSession 覆写并删除账号的明文会话目录，保留加密归档
+func (m *Manager) shredPlainSession(hostID, accountID string) error {
+	_, err := m.shredSessionPaths(hostID, accountID)
+	return err
+}
+
+// shredSessionPaths 覆写并删除账号的会话目录，以及会话根目录下names中的其他文件（如加密归档）
+func (m *Manager) shredSessionPaths(hostID, accountID string, names ...string) (int64, error) {
+	if accountID == "" || accountID != filepath.Base(accountID) || strings.HasPrefix(accountID, ".") {
+		return 0, fmt.Errorf("invalid account id %q", accountID)
+	}
+	names = append([]string{accountID}, names...)
+
+	if hostID == "" || hostID == model.LocalHostID {
+		var size int64
+		for _, name := range names {
+			path := filepath.Join(m.GetConfig().Worker.SessionDir, name)
+			size += dirSize(path)
+			if err := shredDir(path); err != nil {
+				return 0, err
+			}
+		}
+		return size, nil
+	}
+
+	// 路径作为位置参数传入，避免拼接进shell命令
+	args := []string{"run", "--rm",
+		"-v", m.GetConfig().Worker.SessionDir + ":/sessions",
+		"--entrypoint", "sh",
+		m.GetConfig().Worker.Image,
+		"-c", `for p in "$@"; do [ ! -e "$p" ] || { find "$p" -type f -exec shred -zu {} + && rm -rf "$p"; } || exit 1; done`, "sh"}
+	for _, name := range names {
+		args = append(args, "/sessions/"+name)
+	}
+	if _, err := m.runDocker(hostID, dockerTimeout, args...); err != nil {
+		return 0, fmt.Errorf("failed to purge session on host %s: %v", hostID, err)
+	}
+	return 0, nil
+}
+
+// shredDir 用零覆写目录下的所有文件后删除整个目录，path为文件时覆写后删除该文件，不存在时直接返回
+func shredDir(path string) error {
+	if _, err := os.Lstat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	err := filepath.WalkDir(path, func(file string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return nil
+		}
+		return shredFile(file)
+	})
+	if err != nil {
+		return err
+	}
+	if err := os.RemoveAll(path); err != nil {
+		return fmt.Errorf("failed to remove session dir: %v", err)
+	}
+	return nil
+}
+
+// shredFile 用零覆写文件内容并落盘
+func shredFile(path string) error {
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat %s: %v", path, err)
+	}
+	zeros := make([]byte, shredChunk)
+	for remaining := info.Size(); remaining > 0; {
+		n := min(remaining, int64(len(zeros)))
+		if _, err := f.Write(zeros[:n]); err != nil {
+			return fmt.Errorf("failed to overwrite %s: %v", path, err)
+		}
+		remaining -= n
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("failed to sync %s: %v", path, err)
+	}
+	return nil
+}
+
+// markSessionPurged 记录已删除账号的会话数据已清除
+func (m *Manager) markSessionPurged(accountID string) {
+	if err := m.db.Unscoped().Model(&model.Account{}).Where("id = ?", accountID).Update("session_purged_at", time.Now()).Error; err != nil {
+		slog.Warn("Failed to record session purge", "account_id", accountID, "error", err)
+	}
+}
+
+// purgeDeletedSessions 清除删除超过 SESSION_RETENTION_DAYS 天的账号的会话数据，包括远程主机上的会话
+func (m *Manager) purgeDeletedSessions(report *model.JanitorReport) {
+	days := m.GetConfig().Janitor.SessionRetentionDays
+	if days < 0 {
+		return
+	}
+
+	var accounts []model.Account
+	cutoff := time.Now().AddDate(0, 0, -days)
+	err := m.db.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ? AND session_purged_at IS NULL", cutoff).
+		Find(&accounts).Error
+	if err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to list deleted accounts: %v", err))
+		return
+	}
+
+	for _, account := range accounts {
+		// 账号ID已被新账号复用时会话目录属于新账号
+		if _, err := m.GetAccount(account.ID); err == nil {
+			if !report.DryRun {
+				m.markSessionPurged(account.ID)
+			}
+			continue
+		}
+
+		if report.DryRun {
+			if account.HostID == "" || account.HostID == model.LocalHostID {
+				report.ReclaimedBytes += dirSize(m.sessionDir(account.ID)) + dirSize(m.sealedSessionPath(account.ID))
+			}
+			if dir, err := m.mediaAccountDir(account.ID); err == nil {
+				report.ReclaimedBytes += dirSize(dir)
+			}
+			report.SessionsRemoved = append(report.SessionsRemoved, account.ID)
+			continue
+		}
+
+		size, err := m.purgeSession(account.HostID, account.ID)
+		if err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to purge session %s: %v", account.ID, err))
+			continue
+		}
+		m.markSessionPurged(account.ID)
+		report.SessionsRemoved = append(report.SessionsRemoved, account.ID)
+		report.ReclaimedBytes += size
+	}
+}
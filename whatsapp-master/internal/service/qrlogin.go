@@ -0,0 +1,188 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	qrcode "github.com/skip2/go-qrcode"
+
+	"whatsapp-aggregator/internal/logging"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/tracing"
+)
+
+// 登录的签到类型，与 PhoneLoginRequest.SigninType 对应
+const (
+	signinTypeQR    = 30
+	signinTypePhone = 40
+)
+
+// StartQRLogin 为扫码登录分配Worker并发起登录，后台轮询Worker直到账号登录或超时
+func (m *Manager) StartQRLogin(ctx context.Context, req *model.QRLoginRequest) (_ *model.QRLoginResult, err error) {
+	ctx, span := tracing.Start(ctx, "Manager.StartQRLogin", tracing.KindInternal, tracing.String("account.id", req.AccountID))
+	defer func() { span.Finish(err) }()
+
+	account, err := m.assignQRWorker(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	loginReq := &model.PhoneLoginRequest{
+		SigninType:   signinTypeQR,
+		HardwareInfo: req.HardwareInfo,
+		CacheLogin:   req.CacheLogin,
+		ProxyConfig:  req.ProxyConfig,
+	}
+	result, err := m.postToWorker(ctx, account, "/api/login", workerLoginRequest(account, loginReq, m.loginProxy(ctx, account, &req.ProxyConfig), m.loginHardware(account, req.HardwareInfo)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to start QR login: %v", err)
+	}
+
+	m.watchQRLogin(account.ID)
+	logging.FromContext(ctx).Info("QR login initiated", "account_id", account.ID)
+	return &model.QRLoginResult{
+		Account:     account,
+		LoginResult: result,
+		QRCodeURL:   fmt.Sprintf("/api/v1/accounts/%s/qr-code.png", account.ID),
+	}, nil
+}
+
+// assignQRWorker 获取扫码登录使用的账号：指定账号则启动它，否则复用空闲Worker或创建新账号
+func (m *Manager) assignQRWorker(ctx context.Context, req *model.QRLoginRequest) (*model.Account, error) {
+	if req.AccountID != "" {
+		if account, err := m.GetAccount(req.AccountID); err == nil {
+			m.mutex.RLock()
+			status := account.Status
+			m.mutex.RUnlock()
+
+			switch status {
+			case "logged_in":
+				return nil, fmt.Errorf("account %s is already logged in", account.ID)
+			case "stopped", "error", "crash_looping":
+				if err := m.StartAccount(ctx, account.ID, &model.PhoneLoginRequest{SigninType: signinTypeQR}); err != nil {
+					return nil, err
+				}
+			}
+			return account, nil
+		}
+	} else if account := m.FindAvailableWorker(req.TenantID); account != nil {
+		return account, nil
+	}
+
+	accountID := req.AccountID
+	if accountID == "" {
+		accountID = generateID("acc")
+	}
+	return m.CreateAccount(ctx, &model.LoginRequest{
+		AccountID:   accountID,
+		LoginMethod: "qr",
+		HardwareInfo: map[string]interface{}{
+			"os":      req.HardwareInfo.OS,
+			"browser": req.HardwareInfo.Browser,
+		},
+		HardwareProfileID: req.HardwareProfileID,
+		CacheLogin:        req.CacheLogin,
+		ProxyConfig:       &req.ProxyConfig,
+		Tags:              req.Tags,
+		Pool:              req.Pool,
+		Owner:             req.Owner,
+		TenantID:          req.TenantID,
+	})
+}
+
+// watchQRLogin 后台轮询Worker状态，同步二维码和登录状态，登录成功、超时或关闭时结束
+func (m *Manager) watchQRLogin(accountID string) {
+	if _, watching := m.qrWatching.LoadOrStore(accountID, true); watching {
+		return
+	}
+
+	interval := time.Duration(m.GetConfig().QRLogin.PollSeconds) * time.Second
+	if interval <= 0 {
+		interval = 3 * time.Second
+	}
+	deadline := time.Now().Add(time.Duration(m.GetConfig().QRLogin.TimeoutSeconds) * time.Second)
+
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer m.qrWatching.Delete(accountID)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+			}
+
+			account, err := m.GetAccount(accountID)
+			if err != nil {
+				return
+			}
+			m.checkWorkerStatus(account)
+
+			m.mutex.RLock()
+			status := account.Status
+			m.mutex.RUnlock()
+			if status == "logged_in" {
+				slog.Info("QR login completed", "account_id", accountID)
+				return
+			}
+			if time.Now().After(deadline) {
+				slog.Warn("QR login timed out", "account_id", accountID, "status", status)
+				return
+			}
+		}
+	}()
+}
+
+// QRCodePNG 从Worker获取账号当前的登录二维码并渲染为PNG
+func (m *Manager) QRCodePNG(ctx context.Context, accountID string, size int) ([]byte, error) {
+	account, err := m.GetAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.RLock()
+	status := account.Status
+	m.mutex.RUnlock()
+	if status == "logged_in" {
+		return nil, fmt.Errorf("account %s is already logged in", accountID)
+	}
+
+	var payload string
+	if result, err := m.FetchFromWorker(ctx, accountID, "/api/qr-code"); err == nil {
+		payload = qrPayload(result)
+		m.emitQRCode(accountID, payload)
+	} else if cached, ok := m.qrCodes.Load(accountID); ok {
+		// Worker暂时不可达时使用最近一次推送的二维码
+		payload = cached.(string)
+	} else {
+		return nil, err
+	}
+	if payload == "" {
+		return nil, fmt.Errorf("no QR code available for account %s", accountID)
+	}
+
+	png, err := qrcode.Encode(payload, qrcode.Medium, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render QR code: %v", err)
+	}
+	return png, nil
+}
+
+// qrPayload 从Worker响应中取出二维码内容，兼容 qr_code 和 data.qr_code 两种格式
+func qrPayload(result map[string]interface{}) string {
+	if qr, ok := result["qr_code"].(string); ok {
+		return qr
+	}
+	if data, ok := result["data"].(map[string]interface{}); ok {
+		if qr, ok := data["qr_code"].(string); ok {
+			return qr
+		}
+	}
+	return ""
+}
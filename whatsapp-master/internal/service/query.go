@@ -0,0 +1,77 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// findWithListQuery 在数据库层应用通用列表参数（字段需在白名单中），返回过滤后的总数
+func findWithListQuery(db *gorm.DB, q *model.ListQuery, columns map[string]string, defaultSort string, dest interface{}) (int64, error) {
+	if q == nil {
+		q = &model.ListQuery{}
+	}
+
+	for field, raw := range q.Filters {
+		column, ok := columns[field]
+		if !ok {
+			return 0, fmt.Errorf("unsupported filter field: %s", field)
+		}
+		values := make([]interface{}, 0)
+		matchNull := false
+		for _, v := range strings.Split(raw, ",") {
+			v = strings.TrimSpace(v)
+			// 布尔列在SQLite中存为0/1，true/false需按布尔值比较
+			if b, err := strconv.ParseBool(v); err == nil && (v == "true" || v == "false") {
+				values = append(values, b)
+				// 后加的布尔列在已有行中为NULL，视为false
+				matchNull = matchNull || !b
+				continue
+			}
+			values = append(values, v)
+		}
+		if matchNull {
+			db = db.Where(fmt.Sprintf("(%s IN ? OR %s IS NULL)", column, column), values)
+			continue
+		}
+		db = db.Where(fmt.Sprintf("%s IN ?", column), values)
+	}
+
+	var total int64
+	if err := db.Count(&total).Error; err != nil {
+		return 0, err
+	}
+
+	sortFields := q.Sort
+	if len(sortFields) == 0 && defaultSort != "" {
+		sortFields = []string{defaultSort}
+	}
+	for _, field := range sortFields {
+		direction := "ASC"
+		if strings.HasPrefix(field, "-") {
+			direction = "DESC"
+			field = strings.TrimPrefix(field, "-")
+		}
+		column, ok := columns[field]
+		if !ok {
+			return 0, fmt.Errorf("unsupported sort field: %s", field)
+		}
+		db = db.Order(fmt.Sprintf("%s %s", column, direction))
+	}
+
+	if q.Limit > 0 {
+		db = db.Limit(q.Limit)
+	}
+	if q.Offset > 0 {
+		db = db.Offset(q.Offset)
+	}
+
+	if err := db.Find(dest).Error; err != nil {
+		return 0, err
+	}
+	return total, nil
+}
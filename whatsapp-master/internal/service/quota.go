@@ -0,0 +1,236 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// 超限类型
+const (
+	QuotaScopeRate   = "rate"         // 每分钟发送速率
+	QuotaScopeDaily  = "daily"        // 每日配额
+	QuotaScopeTenant = "tenant_daily" // 租户每日配额
+	QuotaScopeWarmup = "warmup"       // 新账号预热阶段的每日上限
+
+	QuotaScopeGlobalLimit  = "global_limit"  // 发送接口全局令牌桶
+	QuotaScopeAccountLimit = "account_limit" // 发送接口账号令牌桶
+)
+
+// QuotaExceededError 账号发送超过限流或配额
+type QuotaExceededError struct {
+	Scope      string
+	Limit      int
+	RetryAfter time.Duration
+}
+
+func (e *QuotaExceededError) Error() string {
+	switch e.Scope {
+	case QuotaScopeDaily:
+		return fmt.Sprintf("daily quota of %d messages exceeded", e.Limit)
+	case QuotaScopeTenant:
+		return fmt.Sprintf("tenant daily quota of %d messages exceeded", e.Limit)
+	case QuotaScopeWarmup:
+		return fmt.Sprintf("warmup allowance of %d messages per day exceeded", e.Limit)
+	case QuotaScopeGlobalLimit:
+		return fmt.Sprintf("global send limit of %d requests per minute exceeded", e.Limit)
+	case QuotaScopeAccountLimit:
+		return fmt.Sprintf("account send limit of %d requests per minute exceeded", e.Limit)
+	}
+	return fmt.Sprintf("rate limit of %d messages per minute exceeded", e.Limit)
+}
+
+// sendWindow 单个账号的发送计数
+type sendWindow struct {
+	recent []time.Time // 最近一分钟内的发送时间，按时间升序
+	day    string      // daily 对应的本地日期
+	daily  int         // 当日已发送条数
+}
+
+// reserveSend 为账号占用一次发送额度，超限时返回 *QuotaExceededError
+func (m *Manager) reserveSend(accountID string) error {
+	return m.checkSendQuota(accountID, true)
+}
+
+// checkSendQuota 检查账号和租户的发送额度，reserve为false时只检查不占用（模拟发送）
+func (m *Manager) checkSendQuota(accountID string, reserve bool) error {
+	var tenantID string
+	var tenantLimit int
+	if account, err := m.GetAccount(accountID); err == nil && account.TenantID != "" {
+		tenantID = account.TenantID
+		tenantLimit = m.tenantDailyLimit(tenantID)
+	}
+	now := time.Now()
+	warmupLimit := m.warmupLimit(accountID, now)
+
+	m.quotaMutex.Lock()
+	defer m.quotaMutex.Unlock()
+
+	w := m.sendWindowLocked(accountID, now)
+
+	limits := m.GetConfig().Quota
+	if limits.RatePerMinute > 0 && len(w.recent) >= limits.RatePerMinute {
+		return &QuotaExceededError{
+			Scope:      QuotaScopeRate,
+			Limit:      limits.RatePerMinute,
+			RetryAfter: w.recent[0].Add(time.Minute).Sub(now),
+		}
+	}
+	if limits.DailyQuota > 0 && w.daily >= limits.DailyQuota {
+		return &QuotaExceededError{
+			Scope:      QuotaScopeDaily,
+			Limit:      limits.DailyQuota,
+			RetryAfter: nextMidnight(now).Sub(now),
+		}
+	}
+	if warmupLimit > 0 && w.daily >= warmupLimit {
+		return &QuotaExceededError{
+			Scope:      QuotaScopeWarmup,
+			Limit:      warmupLimit,
+			RetryAfter: nextMidnight(now).Sub(now),
+		}
+	}
+
+	var tw *sendWindow
+	if tenantID != "" {
+		tw = m.tenantWindowLocked(tenantID, now)
+		if tenantLimit > 0 && tw.daily >= tenantLimit {
+			return &QuotaExceededError{
+				Scope:      QuotaScopeTenant,
+				Limit:      tenantLimit,
+				RetryAfter: nextMidnight(now).Sub(now),
+			}
+		}
+	}
+
+	if !reserve {
+		return nil
+	}
+	w.recent = append(w.recent, now)
+	w.daily++
+	if tw != nil {
+		tw.daily++
+	}
+	return nil
+}
+
+// waitSendRate 等待账号的每分钟速率额度空出，关闭时返回false
+func (m *Manager) waitSendRate(accountID string) bool {
+	for {
+		m.quotaMutex.Lock()
+		now := time.Now()
+		w := m.sendWindowLocked(accountID, now)
+		var wait time.Duration
+		if limit := m.GetConfig().Quota.RatePerMinute; limit > 0 && len(w.recent) >= limit {
+			wait = w.recent[len(w.recent)-limit].Add(time.Minute).Sub(now)
+		}
+		m.quotaMutex.Unlock()
+
+		if wait <= 0 {
+			return !m.shuttingDown()
+		}
+		select {
+		case <-m.stopCh:
+			return false
+		case <-time.After(wait):
+		}
+	}
+}
+
+// GetSendQuota 获取账号当前的限流与配额状态
+func (m *Manager) GetSendQuota(accountID string) (*model.SendQuota, error) {
+	if _, err := m.GetAccount(accountID); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	warmupLimit := m.warmupLimit(accountID, now)
+
+	m.quotaMutex.Lock()
+	defer m.quotaMutex.Unlock()
+
+	w := m.sendWindowLocked(accountID, now)
+	limits := m.GetConfig().Quota
+	quota := &model.SendQuota{
+		AccountID:  accountID,
+		RateLimit:  limits.RatePerMinute,
+		DailyQuota: limits.DailyQuota,
+	}
+
+	if limits.RatePerMinute > 0 {
+		quota.RateRemaining = max(limits.RatePerMinute-len(w.recent), 0)
+		reset := now.Add(time.Minute)
+		if len(w.recent) > 0 {
+			reset = w.recent[0].Add(time.Minute)
+		}
+		quota.RateReset = &reset
+		if lowRemaining(quota.RateRemaining, limits.RatePerMinute, limits.WarnRatio) {
+			quota.Warning = fmt.Sprintf("approaching rate limit: %d of %d messages per minute remaining", quota.RateRemaining, limits.RatePerMinute)
+		}
+	}
+
+	if limits.DailyQuota > 0 {
+		quota.QuotaRemaining = max(limits.DailyQuota-w.daily, 0)
+		reset := nextMidnight(now)
+		quota.QuotaReset = &reset
+		// 每日配额的警告优先，影响时间更长
+		if lowRemaining(quota.QuotaRemaining, limits.DailyQuota, limits.WarnRatio) {
+			quota.Warning = fmt.Sprintf("approaching daily quota: %d of %d messages remaining today", quota.QuotaRemaining, limits.DailyQuota)
+		}
+	}
+
+	if warmupLimit > 0 {
+		quota.WarmupLimit = warmupLimit
+		quota.WarmupRemaining = max(warmupLimit-w.daily, 0)
+		if lowRemaining(quota.WarmupRemaining, warmupLimit, limits.WarnRatio) {
+			quota.Warning = fmt.Sprintf("approaching warmup allowance: %d of %d messages remaining today", quota.WarmupRemaining, warmupLimit)
+		}
+	}
+
+	return quota, nil
+}
+
+// sendWindowLocked 获取账号的发送计数并清理过期记录，调用方需持有quotaMutex
+func (m *Manager) sendWindowLocked(accountID string, now time.Time) *sendWindow {
+	w, exists := m.sendWindows[accountID]
+	if !exists {
+		w = &sendWindow{}
+		m.sendWindows[accountID] = w
+	}
+
+	cutoff := now.Add(-time.Minute)
+	i := 0
+	for i < len(w.recent) && !w.recent[i].After(cutoff) {
+		i++
+	}
+	w.recent = w.recent[i:]
+
+	// 跨天或首次使用时，从数据库恢复当日已发送条数，避免重启后配额归零
+	if day := now.Format("2006-01-02"); w.day != day {
+		var count int64
+		if m.GetConfig().Quota.DailyQuota > 0 || m.GetConfig().Warmup.Enabled {
+			m.db.Model(&model.Message{}).
+				Where("account_id = ? AND direction = ? AND created_at >= ?", accountID, "outbound", startOfDay(now)).
+				Count(&count)
+		}
+		w.day = day
+		w.daily = int(count)
+	}
+	return w
+}
+
+// lowRemaining 剩余额度是否低于警告阈值
+func lowRemaining(remaining, limit int, ratio float64) bool {
+	return ratio > 0 && float64(remaining) <= float64(limit)*ratio
+}
+
+// startOfDay 返回当天本地时间零点
+func startOfDay(t time.Time) time.Time {
+	y, mo, d := t.Date()
+	return time.Date(y, mo, d, 0, 0, 0, 0, t.Location())
+}
+
+// nextMidnight 返回下一个本地时间零点
+func nextMidnight(t time.Time) time.Time {
+	return startOfDay(t).AddDate(0, 0, 1)
+}
@@ -0,0 +1,139 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// tokenBucket 令牌桶，按固定速率补充令牌，容量为突发上限
+type tokenBucket struct {
+	perMinute int
+	burst     int
+	tokens    float64
+	last      time.Time
+}
+
+func newTokenBucket(perMinute, burst int, now time.Time) *tokenBucket {
+	if burst <= 0 {
+		burst = perMinute
+	}
+	return &tokenBucket{perMinute: perMinute, burst: burst, tokens: float64(burst), last: now}
+}
+
+// matches 令牌桶参数是否与当前限流设置一致
+func (b *tokenBucket) matches(perMinute, burst int) bool {
+	if burst <= 0 {
+		burst = perMinute
+	}
+	return b.perMinute == perMinute && b.burst == burst
+}
+
+// refill 按经过的时间补充令牌
+func (b *tokenBucket) refill(now time.Time) {
+	rate := float64(b.perMinute) / 60
+	b.tokens = math.Min(float64(b.burst), b.tokens+now.Sub(b.last).Seconds()*rate)
+	b.last = now
+}
+
+// wait 取得一个令牌还需等待的时间，0表示可以立即取得
+func (b *tokenBucket) wait() time.Duration {
+	if b.tokens >= 1 {
+		return 0
+	}
+	rate := float64(b.perMinute) / 60
+	return time.Duration((1 - b.tokens) / rate * float64(time.Second))
+}
+
+// AllowSend 按全局和账号令牌桶检查一次发送请求，所有桶都有令牌时才扣减，超限时返回 *QuotaExceededError
+func (m *Manager) AllowSend(accountIDs ...string) error {
+	return m.takeSendTokens(true, accountIDs)
+}
+
+// CheckSendLimit 与 AllowSend 相同但不扣减令牌，用于模拟发送
+func (m *Manager) CheckSendLimit(accountIDs ...string) error {
+	return m.takeSendTokens(false, accountIDs)
+}
+
+// takeSendTokens 检查全局和账号令牌桶，consume为true时扣减
+func (m *Manager) takeSendTokens(consume bool, accountIDs []string) error {
+	m.mutex.RLock()
+	limits := m.GetConfig().RateLimit
+	accountLimits := make(map[string]model.AccountSendLimit, len(accountIDs))
+	for _, id := range accountIDs {
+		account, exists := m.accounts[id]
+		if !exists || account.Disabled {
+			continue
+		}
+		limit := model.AccountSendLimit{PerMinute: limits.AccountPerMinute, Burst: limits.AccountBurst}
+		if account.SendLimit > 0 {
+			limit = model.AccountSendLimit{PerMinute: account.SendLimit, Burst: account.SendLimitBurst}
+		}
+		accountLimits[id] = limit
+	}
+	m.mutex.RUnlock()
+
+	m.bucketMutex.Lock()
+	defer m.bucketMutex.Unlock()
+
+	now := time.Now()
+	var buckets []*tokenBucket
+	if limits.GlobalPerMinute > 0 {
+		if m.globalBucket == nil || !m.globalBucket.matches(limits.GlobalPerMinute, limits.GlobalBurst) {
+			m.globalBucket = newTokenBucket(limits.GlobalPerMinute, limits.GlobalBurst, now)
+		}
+		m.globalBucket.refill(now)
+		if wait := m.globalBucket.wait(); wait > 0 {
+			return &QuotaExceededError{Scope: QuotaScopeGlobalLimit, Limit: limits.GlobalPerMinute, RetryAfter: wait}
+		}
+		buckets = append(buckets, m.globalBucket)
+	}
+
+	for id, limit := range accountLimits {
+		if limit.PerMinute <= 0 {
+			delete(m.accountBuckets, id)
+			continue
+		}
+		bucket, exists := m.accountBuckets[id]
+		if !exists || !bucket.matches(limit.PerMinute, limit.Burst) {
+			bucket = newTokenBucket(limit.PerMinute, limit.Burst, now)
+			m.accountBuckets[id] = bucket
+		}
+		bucket.refill(now)
+		if wait := bucket.wait(); wait > 0 {
+			return &QuotaExceededError{Scope: QuotaScopeAccountLimit, Limit: limit.PerMinute, RetryAfter: wait}
+		}
+		buckets = append(buckets, bucket)
+	}
+
+	if !consume {
+		return nil
+	}
+	for _, bucket := range buckets {
+		bucket.tokens--
+	}
+	return nil
+}
+
+// SetAccountSendLimit 设置并保存账号级发送接口限流
+func (m *Manager) SetAccountSendLimit(accountID string, limit *model.AccountSendLimit) (*model.Account, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return nil, fmt.Errorf("account %s not found", accountID)
+	}
+
+	if err := m.db.Model(account).Updates(map[string]interface{}{
+		"send_limit":       limit.PerMinute,
+		"send_limit_burst": limit.Burst,
+	}).Error; err != nil {
+		return nil, fmt.Errorf("failed to update account send limit: %v", err)
+	}
+	account.SendLimit = limit.PerMinute
+	account.SendLimitBurst = limit.Burst
+	return account.Clone(), nil
+}
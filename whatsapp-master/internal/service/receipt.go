@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// receiptPollBatch 每个账号每次轮询的最多消息数
+const receiptPollBatch = 100
+
+// receiptRank 出站消息状态的先后顺序，回执只会让状态前进
+var receiptRank = map[string]int{
+	"sent":      1,
+	"delivered": 2,
+	"read":      3,
+}
+
+// ackStatus 将whatsapp-web.js的ack值转换为消息状态，无法识别时返回空
+func ackStatus(ack int) string {
+	switch {
+	case ack >= 3:
+		return "read"
+	case ack == 2:
+		return "delivered"
+	case ack == 1:
+		return "sent"
+	}
+	return ""
+}
+
+// GetMessageStatus 获取消息的投递状态
+func (m *Manager) GetMessageStatus(messageID string) (*model.MessageStatus, error) {
+	var msg model.Message
+	if err := m.db.Where("id = ?", messageID).First(&msg).Error; err != nil {
+		return nil, fmt.Errorf("message %s not found", messageID)
+	}
+	return messageStatus(&msg), nil
+}
+
+// messageStatus 从消息记录生成投递状态
+func messageStatus(msg *model.Message) *model.MessageStatus {
+	return &model.MessageStatus{
+		ID:              msg.ID,
+		AccountID:       msg.AccountID,
+		Contact:         msg.Contact,
+		Status:          msg.Status,
+		WorkerMessageID: msg.WorkerMessageID,
+		Error:           msg.Error,
+		SentAt:          msg.Timestamp,
+		DeliveredAt:     msg.DeliveredAt,
+		SeenAt:          msg.SeenAt,
+	}
+}
+
+// ApplyReceipts 按Worker回执推进账号出站消息的状态，状态变化时发布 message.delivered / message.read 事件
+func (m *Manager) ApplyReceipts(accountID string, receipts []model.MessageReceipt) (int, error) {
+	updated := 0
+	for _, receipt := range receipts {
+		status := ackStatus(receipt.Ack)
+		if status == "" || receipt.ID == "" {
+			continue
+		}
+
+		var msg model.Message
+		err := m.db.Where("account_id = ? AND worker_message_id = ? AND direction = ?", accountID, receipt.ID, "outbound").First(&msg).Error
+		if err != nil {
+			continue
+		}
+		// 失败、重试中的消息和已到达更后状态的消息不再变化
+		current, tracked := receiptRank[msg.Status]
+		if !tracked || receiptRank[status] <= current {
+			continue
+		}
+
+		now := time.Now()
+		updates := map[string]interface{}{"status": status}
+		if msg.DeliveredAt == nil {
+			// 已读回执可能先于送达回执到达
+			msg.DeliveredAt = &now
+			updates["delivered_at"] = now
+		}
+		if status == "read" {
+			msg.SeenAt = &now
+			updates["seen_at"] = now
+		}
+		if err := m.db.Model(&msg).Updates(updates).Error; err != nil {
+			return updated, fmt.Errorf("failed to update message status: %v", err)
+		}
+		msg.Status = status
+		updated++
+
+		eventType := EventMessageDelivered
+		if status == "read" {
+			eventType = EventMessageRead
+		}
+		m.emit(eventType, accountID, messageStatus(&msg))
+	}
+	return updated, nil
+}
+
+// StartReceiptPoller 定期向Worker查询尚未已读的出站消息回执，作为Worker回调的补充
+func (m *Manager) StartReceiptPoller() {
+	cfg := m.GetConfig().Receipt
+	if cfg.PollInterval <= 0 {
+		return
+	}
+
+	interval := time.Duration(cfg.PollInterval) * time.Second
+	slog.Info("Receipt poller enabled", "interval", interval, "window_hours", cfg.PollWindow)
+
+	ticker := time.NewTicker(interval)
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				if !m.IsLeader() {
+					continue
+				}
+				m.pollReceipts()
+			}
+		}
+	}()
+}
+
+// pollReceipts 按账号查询最近发送且尚未已读的消息回执
+func (m *Manager) pollReceipts() {
+	since := time.Now().Add(-time.Duration(m.GetConfig().Receipt.PollWindow) * time.Hour)
+	var accountIDs []string
+	err := m.db.Model(&model.Message{}).
+		Where("direction = ? AND status IN ? AND worker_message_id <> '' AND timestamp >= ?", "outbound", []string{"sent", "delivered"}, since).
+		Distinct().Pluck("account_id", &accountIDs).Error
+	if err != nil {
+		slog.Warn("Failed to list messages awaiting receipts", "error", err)
+		return
+	}
+
+	for _, accountID := range accountIDs {
+		if m.shuttingDown() {
+			return
+		}
+		account, err := m.GetAccount(accountID)
+		if err != nil || account.Status != "logged_in" {
+			continue
+		}
+
+		var ids []string
+		m.db.Model(&model.Message{}).
+			Where("account_id = ? AND direction = ? AND status IN ? AND worker_message_id <> '' AND timestamp >= ?", accountID, "outbound", []string{"sent", "delivered"}, since).
+			Order("timestamp DESC").Limit(receiptPollBatch).Pluck("worker_message_id", &ids)
+		if len(ids) == 0 {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		result, err := m.FetchFromWorker(ctx, accountID, "/api/messages/acks?ids="+url.QueryEscape(strings.Join(ids, ",")))
+		cancel()
+		if err != nil {
+			slog.Debug("Failed to poll message receipts", "account_id", accountID, "error", err)
+			continue
+		}
+		var receipts []model.MessageReceipt
+		if err := decodeWorkerData(result, &receipts); err != nil {
+			slog.Debug("Failed to parse message receipts", "account_id", accountID, "error", err)
+			continue
+		}
+		if updated, err := m.ApplyReceipts(accountID, receipts); err != nil {
+			slog.Warn("Failed to apply message receipts", "account_id", accountID, "error", err)
+		} else if updated > 0 {
+			slog.Debug("Message receipts applied", "account_id", accountID, "updated", updated)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// workerContainerPrefix Worker容器名前缀，后接账号ID
+const workerContainerPrefix = "whatsapp-worker-"
+
+// hostPortPattern 从 docker ps 的端口列中解析宿主机端口，如 0.0.0.0:4001->4000/tcp
+var hostPortPattern = regexp.MustCompile(`:(\d+)->(\d+)/tcp`)
+
+// ReconcileWorkers 启动时将每台未离线主机上的 whatsapp-worker-* 容器与账号表对账：
+// 删除没有对应账号或账号已迁移到其他主机的容器，重新接管已知账号的运行中容器并恢复ContainerID和ServiceURL，
+// 容器已不存在的账号释放端口并标记为stopped
+func (m *Manager) ReconcileWorkers() (*model.ReconcileReport, error) {
+	report := &model.ReconcileReport{
+		StartedAt:      time.Now(),
+		Adopted:        []string{},
+		OrphansRemoved: []string{},
+		PortsReleased:  []string{},
+	}
+
+	hostContainers := make(map[string]map[string]WorkerContainer)
+	var listErr error
+	for _, hostID := range m.reachableHosts() {
+		containers, err := m.listWorkerContainers(hostID)
+		if err != nil {
+			// 无法列出容器的主机上的账号保持不变
+			report.Errors = append(report.Errors, err.Error())
+			listErr = err
+			continue
+		}
+		hostContainers[hostID] = containers
+	}
+	if len(hostContainers) == 0 && listErr != nil {
+		// docker不可用时无法判断容器是否存在，不修改任何账号
+		return nil, listErr
+	}
+
+	total := 0
+	m.mutex.Lock()
+	for hostID, containers := range hostContainers {
+		total += len(containers)
+		for _, container := range containers {
+			accountID := strings.TrimPrefix(container.Name, workerContainerPrefix)
+			account, exists := m.accounts[accountID]
+			if !exists || account.HostID != hostID {
+				// 账号已删除，或已迁移到其他主机
+				if err := m.hostRuntime(hostID).Stop(container.Name); err != nil {
+					report.Errors = append(report.Errors, fmt.Sprintf("failed to remove orphan container %s: %v", container.Name, err))
+					continue
+				}
+				slog.Info("Removed orphan worker container", "container", container.Name, "host_id", hostID)
+				report.OrphansRemoved = append(report.OrphansRemoved, container.Name)
+				continue
+			}
+			if !container.Running() {
+				// 已退出的容器保留给重启流程重建，端口仍属于该账号
+				continue
+			}
+
+			if container.Port != 0 && container.Port != account.Port {
+				m.portPool.Release(account.Port)
+				m.portPool.Reserve(container.Port)
+				account.Port = container.Port
+			}
+			account.ContainerID = container.Name
+			account.ServiceURL = m.workerServiceURL(hostID, container.Name, account.Port)
+			if err := m.db.Model(account).Updates(map[string]interface{}{
+				"container_id": account.ContainerID,
+				"service_url":  account.ServiceURL,
+				"port":         account.Port,
+				"host_id":      account.HostID,
+			}).Error; err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("failed to save account %s: %v", account.ID, err))
+				continue
+			}
+			slog.Info("Adopted worker container", "account_id", account.ID, "container", container.Name, "host_id", hostID, "service_url", account.ServiceURL)
+			report.Adopted = append(report.Adopted, account.ID)
+		}
+	}
+
+	var statusChanges [][3]string
+	for _, account := range m.accounts {
+		containers, listed := hostContainers[account.HostID]
+		if !listed {
+			continue
+		}
+		if _, exists := containers[workerContainerPrefix+account.ID]; exists || account.Port == 0 {
+			continue
+		}
+
+		previous := account.Status
+		m.portPool.Release(account.Port)
+		account.Port = 0
+		account.ContainerID = ""
+		account.Status = "stopped"
+		account.UpdatedAt = time.Now()
+		if err := m.db.Model(account).Updates(map[string]interface{}{
+			"port":         account.Port,
+			"container_id": account.ContainerID,
+			"status":       account.Status,
+			"updated_at":   account.UpdatedAt,
+		}).Error; err != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to save account %s: %v", account.ID, err))
+			continue
+		}
+		slog.Info("Released port of account without worker container", "account_id", account.ID, "previous_status", previous)
+		report.PortsReleased = append(report.PortsReleased, account.ID)
+		statusChanges = append(statusChanges, [3]string{account.ID, previous, account.Status})
+	}
+	m.mutex.Unlock()
+
+	for _, change := range statusChanges {
+		m.emitStatusChange(change[0], change[1], change[2], StatusCause{Source: StatusSourceReconcile, Reason: "worker container not found"})
+	}
+
+	finished := time.Now()
+	report.FinishedAt = &finished
+	slog.Info("Worker reconciliation finished", "hosts", len(hostContainers), "containers", total, "adopted", len(report.Adopted),
+		"orphans_removed", len(report.OrphansRemoved), "ports_released", len(report.PortsReleased), "errors", len(report.Errors))
+
+	m.janitorMutex.Lock()
+	m.reconcileLast = report
+	m.janitorMutex.Unlock()
+	return report, nil
+}
+
+// reachableHosts 未离线的主机ID
+func (m *Manager) reachableHosts() []string {
+	m.hostMutex.RLock()
+	defer m.hostMutex.RUnlock()
+
+	ids := make([]string, 0, len(m.hosts))
+	for id, host := range m.hosts {
+		if host.Status != model.HostStatusOffline {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// listWorkerContainers 列出主机上所有 whatsapp-worker-* 容器，按容器名索引
+func (m *Manager) listWorkerContainers(hostID string) (map[string]WorkerContainer, error) {
+	list, err := m.hostRuntime(hostID).List(workerContainerPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list worker containers on host %s: %v", hostID, err)
+	}
+	containers := make(map[string]WorkerContainer, len(list))
+	for _, container := range list {
+		containers[container.Name] = container
+	}
+	return containers, nil
+}
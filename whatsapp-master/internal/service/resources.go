@@ -0,0 +1,104 @@
+package service
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// validMemoryLimit docker --memory 接受的格式，如 512m、1.5g
+var validMemoryLimit = regexp.MustCompile(`(?i)^\d+(\.\d+)?[bkmg]?$`)
+
+// validateWorkerResources 校验Worker资源限制，避免到 docker run 时才失败
+func validateWorkerResources(r *model.WorkerResources) error {
+	if r.Memory != "" && !validMemoryLimit.MatchString(r.Memory) {
+		return fmt.Errorf("invalid memory limit %q: use a number with optional b, k, m or g suffix", r.Memory)
+	}
+	if r.CPUs != "" {
+		if cpus, err := strconv.ParseFloat(r.CPUs, 64); err != nil || cpus <= 0 {
+			return fmt.Errorf("invalid cpus limit %q: must be a positive number", r.CPUs)
+		}
+	}
+	if r.PidsLimit < 0 {
+		return fmt.Errorf("invalid pids limit %d: must not be negative", r.PidsLimit)
+	}
+	if r.RestartPolicy != "" && !validRestartPolicy(r.RestartPolicy) {
+		return fmt.Errorf("invalid restart policy %q: use no, always, unless-stopped or on-failure[:N]", r.RestartPolicy)
+	}
+	return nil
+}
+
+// validRestartPolicy 是否为docker支持的重启策略
+func validRestartPolicy(policy string) bool {
+	switch policy {
+	case "no", "always", "unless-stopped", "on-failure":
+		return true
+	}
+	retries, ok := strings.CutPrefix(policy, "on-failure:")
+	if !ok {
+		return false
+	}
+	n, err := strconv.Atoi(retries)
+	return err == nil && n > 0
+}
+
+// applyWorkerResources 将请求中设置的资源限制写入账号，未设置的项保持不变
+func applyWorkerResources(account *model.Account, r *model.WorkerResources) {
+	if r.Memory != "" {
+		account.Resources.Memory = r.Memory
+	}
+	if r.CPUs != "" {
+		account.Resources.CPUs = r.CPUs
+	}
+	if r.PidsLimit > 0 {
+		account.Resources.PidsLimit = r.PidsLimit
+	}
+	if r.RestartPolicy != "" {
+		account.Resources.RestartPolicy = r.RestartPolicy
+	}
+}
+
+// workerResources 账号Worker实际使用的资源限制：账号设置优先，其余使用全局默认
+func (m *Manager) workerResources(account *model.Account) model.WorkerResources {
+	cfg := m.GetConfig().Worker
+	resources := model.WorkerResources{
+		Memory:        cfg.Memory,
+		CPUs:          cfg.CPUs,
+		PidsLimit:     cfg.PidsLimit,
+		RestartPolicy: cfg.RestartPolicy,
+	}
+	if account.Resources.Memory != "" {
+		resources.Memory = account.Resources.Memory
+	}
+	if account.Resources.CPUs != "" {
+		resources.CPUs = account.Resources.CPUs
+	}
+	if account.Resources.PidsLimit > 0 {
+		resources.PidsLimit = account.Resources.PidsLimit
+	}
+	if account.Resources.RestartPolicy != "" {
+		resources.RestartPolicy = account.Resources.RestartPolicy
+	}
+	return resources
+}
+
+// dockerResourceArgs 资源限制对应的 docker run 参数
+func dockerResourceArgs(r model.WorkerResources) []string {
+	var args []string
+	if r.Memory != "" {
+		args = append(args, "--memory", r.Memory)
+	}
+	if r.CPUs != "" {
+		args = append(args, "--cpus", r.CPUs)
+	}
+	if r.PidsLimit > 0 {
+		args = append(args, "--pids-limit", strconv.Itoa(r.PidsLimit))
+	}
+	if r.RestartPolicy != "" {
+		args = append(args, "--restart", r.RestartPolicy)
+	}
+	return args
+}
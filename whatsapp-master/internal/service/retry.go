@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"whatsapp-aggregator/internal/logging"
+	"whatsapp-aggregator/internal/model"
+)
+
+// defaultRetryLimit 批量重试默认条数
+const defaultRetryLimit = 100
+
+// isRetryable 判断发送错误是否值得重试
+func isRetryable(err error) bool {
+	var workerErr *WorkerError
+	return errors.As(err, &workerErr) && workerErr.Retryable()
+}
+
+// retryBackoff 第attempt次重试前的等待时间（指数退避）
+func (m *Manager) retryBackoff(attempt int) time.Duration {
+	backoff := time.Duration(m.GetConfig().Retry.BackoffMs) * time.Millisecond
+	maxBackoff := time.Duration(m.GetConfig().Retry.MaxBackoffMs) * time.Millisecond
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if maxBackoff > 0 && backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
+// postWithRetry 调用Worker发送消息，遇到可重试错误时按退避策略重试，并累计记录的尝试次数
+func (m *Manager) postWithRetry(ctx context.Context, account *model.Account, workerPath string, payload interface{}, record *model.Message) (map[string]interface{}, error) {
+	maxAttempts := m.GetConfig().Retry.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		record.Attempts++
+		result, err := m.postToWorker(ctx, account, workerPath, payload)
+		if err == nil || attempt >= maxAttempts || !isRetryable(err) {
+			return result, err
+		}
+
+		backoff := m.retryBackoff(attempt)
+		logging.FromContext(ctx).Warn("Send failed, retrying", "account_id", account.ID, "attempt", attempt, "max_attempts", maxAttempts, "backoff", backoff, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return result, err
+		case <-m.stopCh:
+			return result, err
+		case <-time.After(backoff):
+		}
+	}
+}
+
+// RetryFailedMessages 将时间窗口内发送失败的文本消息重新排队发送
+func (m *Manager) RetryFailedMessages(req *model.RetryMessagesRequest) (*model.RetryMessagesResult, error) {
+	if req.AccountID != "" {
+		if _, err := m.GetAccount(req.AccountID); err != nil {
+			return nil, err
+		}
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultRetryLimit
+	}
+
+	// 媒体内容不落库，只能重试文本消息
+	db := m.db.Model(&model.Message{}).
+		Where("direction = ? AND status = ? AND type = ?", "outbound", "failed", "chat")
+	if req.AccountID != "" {
+		db = db.Where("account_id = ?", req.AccountID)
+	}
+	if req.Since != nil {
+		db = db.Where("timestamp >= ?", *req.Since)
+	}
+	if req.Until != nil {
+		db = db.Where("timestamp <= ?", *req.Until)
+	}
+
+	var candidates []*model.Message
+	if err := db.Order("timestamp ASC").Limit(limit).Find(&candidates).Error; err != nil {
+		return nil, fmt.Errorf("failed to query failed messages: %v", err)
+	}
+
+	// 标记为retrying，避免并发请求重复重试同一条消息
+	queued := make([]*model.Message, 0, len(candidates))
+	for _, msg := range candidates {
+		res := m.db.Model(&model.Message{}).
+			Where("id = ? AND status = ?", msg.ID, "failed").
+			Update("status", "retrying")
+		if res.Error == nil && res.RowsAffected == 1 {
+			msg.Status = "retrying"
+			queued = append(queued, msg)
+		}
+	}
+
+	result := &model.RetryMessagesResult{
+		Queued:     len(queued),
+		MessageIDs: make([]string, 0, len(queued)),
+	}
+	for _, msg := range queued {
+		result.MessageIDs = append(result.MessageIDs, msg.ID)
+	}
+
+	if len(queued) > 0 {
+		slog.Info("Retrying failed messages", "count", len(queued))
+		job, err := m.startJob(JobMessageRetry, fmt.Sprintf("retry %d failed messages", len(queued)), len(queued), func(r *jobRun) error {
+			m.runMessageRetries(r, queued)
+			return nil
+		})
+		if err != nil {
+			for _, msg := range queued {
+				m.db.Model(msg).Update("status", "failed")
+			}
+			return nil, err
+		}
+		result.JobID = job.ID
+	}
+	return result, nil
+}
+
+// runMessageRetries 依次重发消息，发送之间按批量发送间隔节流
+func (m *Manager) runMessageRetries(r *jobRun, messages []*model.Message) {
+	interval := time.Duration(m.GetConfig().Bulk.IntervalMs) * time.Millisecond
+
+	sent, failed := 0, 0
+	for i, msg := range messages {
+		if i > 0 && interval > 0 {
+			select {
+			case <-r.Context().Done():
+			case <-m.stopCh:
+			case <-time.After(interval):
+			}
+		}
+		if r.Cancelled() || m.shuttingDown() {
+			// 未处理的消息恢复为failed，便于下次重试
+			for _, rest := range messages[i:] {
+				m.db.Model(rest).Update("status", "failed")
+			}
+			break
+		}
+
+		m.resendMessage(msg, false)
+		if msg.Status == "failed" {
+			failed++
+		} else {
+			sent++
+			m.resolveDeadLetterForMessage(msg.ID)
+		}
+		r.Advance(1)
+	}
+	r.SetResult(map[string]int{"sent": sent, "failed": failed})
+}
+
+// resendMessage 重发单条失败消息并更新记录，媒体消息从本地保存的文件重新投递
+func (m *Manager) resendMessage(msg *model.Message, voice bool) {
+	account, err := m.GetAccount(msg.AccountID)
+	if err == nil {
+		err = m.checkAccountEnabled(msg.AccountID)
+	}
+	if err == nil {
+		err = m.checkSuppressed(msg.Contact)
+	}
+	if err == nil {
+		err = m.reserveSend(msg.AccountID)
+	}
+	if err != nil {
+		msg.Status = "failed"
+		msg.Error = err.Error()
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+		if msg.Type == "chat" {
+			_, err = m.deliverText(ctx, account, msg, nil)
+		} else {
+			_, err = m.deliverStoredMedia(ctx, account, msg, voice)
+		}
+		cancel()
+	}
+
+	if saveErr := m.db.Save(msg).Error; saveErr != nil {
+		slog.Error("Failed to update retried message", "message_id", msg.ID, "error", saveErr)
+	}
+	m.recordDailyMessage(msg)
+	m.emitMessage(msg)
+
+	if err == nil {
+		if msg.Type == "chat" {
+			m.recordMessageSent(msg.AccountID)
+		} else {
+			m.recordMediaSent(msg.AccountID, msg.MediaSize)
+		}
+	}
+}
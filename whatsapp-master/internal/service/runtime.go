@@ -0,0 +1,293 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/tracing"
+)
+
+// WorkerRuntime 管理一台主机上的Worker容器。Docker主机由 dockerWorkerRuntime 通过docker命令管理
+// （本机直接执行，远程主机经 fleet-agent 执行），WORKER_MODE=mock 时本机Worker由进程内的 mockWorkerRuntime 模拟
+type WorkerRuntime interface {
+	// Spawn 按账号的端口、令牌和运行参数启动名为 spec.Name 的Worker，同名Worker需已删除
+	Spawn(ctx context.Context, spec *WorkerSpec) error
+	// Stop 停止并删除Worker（docker rm -f）
+	Stop(name string) error
+	// Kill 强制停止Worker但保留为已退出状态（docker kill）
+	Kill(name string) error
+	// List 列出名称以prefix开头的Worker，包括已退出的
+	List(prefix string) ([]WorkerContainer, error)
+	// Logs 打开Worker的日志流（合并标准输出和标准错误），调用方负责关闭
+	Logs(ctx context.Context, name string, query *model.LogStreamQuery) (io.ReadCloser, error)
+}
+
+// WorkerSpec 启动Worker的参数
+type WorkerSpec struct {
+	Name    string
+	Account *model.Account
+	Restore *model.SessionBackup // 启动前恢复到会话目录的备份，为nil时不恢复
+	OnStage func(string)         // 进入启动阶段时回调，不为nil
+}
+
+// WorkerContainer 运行时列出的Worker
+type WorkerContainer struct {
+	Name    string
+	State   string // 容器状态，如 running、exited、dead
+	Health  string // Docker健康检查状态 starting、healthy、unhealthy，未配置健康检查时为空
+	Port    int    // 映射到Worker内部端口的宿主机端口，未运行时为0
+	Managed bool   // 是否带有 whatsapp-fleet.managed 标签
+}
+
+// Running Worker是否正在运行
+func (c WorkerContainer) Running() bool {
+	return c.State == "running"
+}
+
+// hostRuntime 返回管理主机上Worker的运行时
+func (m *Manager) hostRuntime(hostID string) WorkerRuntime {
+	if isLocalHost(hostID) && m.mockWorkers != nil {
+		return m.mockWorkers
+	}
+	return &dockerWorkerRuntime{manager: m, hostID: hostID, docker: m.dockerOn(hostID)}
+}
+
+// isLocalHost 主机ID是否指向Master所在的主机，多主机调度之前创建的账号主机ID为空
+func isLocalHost(hostID string) bool {
+	return hostID == "" || hostID == model.LocalHostID
+}
+
+// dockerAvailable 主机上是否可以执行docker命令，WORKER_MODE=mock 时本机不使用docker
+func (m *Manager) dockerAvailable(hostID string) bool {
+	return !isLocalHost(hostID) || m.mockWorkers == nil
+}
+
+// dockerClient 在一台主机上执行docker命令
+type dockerClient interface {
+	// run 执行命令并返回标准输出，stdin不为空时作为命令的标准输入
+	run(timeout time.Duration, stdin []byte, args ...string) (string, error)
+	// stream 执行命令并返回合并的标准输出和标准错误，命令结束时流关闭
+	stream(ctx context.Context, args []string) (io.ReadCloser, error)
+}
+
+// dockerOn 返回在主机上执行docker命令的客户端：本机直接执行，远程主机通过 fleet-agent 执行
+func (m *Manager) dockerOn(hostID string) dockerClient {
+	switch {
+	case !m.dockerAvailable(hostID):
+		return unavailableDocker{}
+	case isLocalHost(hostID):
+		return localDocker{}
+	default:
+		return &agentDocker{manager: m, hostID: hostID}
+	}
+}
+
+// localDocker 在本机执行docker命令
+type localDocker struct{}
+
+func (localDocker) run(timeout time.Duration, stdin []byte, args ...string) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, "docker", args...)
+	if len(stdin) > 0 {
+		cmd.Stdin = bytes.NewReader(stdin)
+	}
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return stdout.String(), fmt.Errorf("%v, output: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+func (localDocker) stream(ctx context.Context, args []string) (io.ReadCloser, error) {
+	return startLocalDockerStream(ctx, args)
+}
+
+// agentDocker 通过远程主机上的 fleet-agent 执行docker命令
+type agentDocker struct {
+	manager *Manager
+	hostID  string
+}
+
+func (a *agentDocker) run(timeout time.Duration, stdin []byte, args ...string) (string, error) {
+	agentURL, token, err := a.manager.hostAgent(a.hostID)
+	if err != nil {
+		return "", err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout+10*time.Second)
+	defer cancel()
+	var result model.AgentDockerResult
+	if err := a.manager.callAgent(ctx, agentURL, token, http.MethodPost, "/docker",
+		model.AgentDockerRequest{Args: args, TimeoutSeconds: int(timeout.Seconds()), Stdin: stdin}, &result); err != nil {
+		return "", fmt.Errorf("host %s: %v", a.hostID, err)
+	}
+	if result.Error != "" {
+		return result.Stdout, fmt.Errorf("host %s: %s", a.hostID, result.Error)
+	}
+	if result.ExitCode != 0 {
+		return result.Stdout, fmt.Errorf("host %s: exit status %d, output: %s", a.hostID, result.ExitCode, strings.TrimSpace(result.Stderr))
+	}
+	return result.Stdout, nil
+}
+
+func (a *agentDocker) stream(ctx context.Context, args []string) (io.ReadCloser, error) {
+	return a.manager.startAgentDockerStream(ctx, a.hostID, args)
+}
+
+// unavailableDocker WORKER_MODE=mock 时本机的docker客户端，镜像、会话备份等只有Docker Worker才有的操作返回错误
+type unavailableDocker struct{}
+
+func (unavailableDocker) run(_ time.Duration, _ []byte, args ...string) (string, error) {
+	return "", errDockerUnavailable(args)
+}
+
+func (unavailableDocker) stream(_ context.Context, args []string) (io.ReadCloser, error) {
+	return nil, errDockerUnavailable(args)
+}
+
+func errDockerUnavailable(args []string) error {
+	command := "command"
+	if len(args) > 0 {
+		command = args[0]
+	}
+	return fmt.Errorf("docker %s is not available with WORKER_MODE=%s", command, WorkerModeMock)
+}
+
+// dockerWorkerRuntime 用docker命令管理主机上的Worker容器
+type dockerWorkerRuntime struct {
+	manager *Manager
+	hostID  string
+	docker  dockerClient
+}
+
+// Spawn 准备镜像和会话目录后启动Worker容器，启用会话加密时把会话解密到容器内
+func (r *dockerWorkerRuntime) Spawn(ctx context.Context, spec *WorkerSpec) error {
+	m, account := r.manager, spec.Account
+	image := m.GetConfig().Worker.Image
+
+	// 镜像不在本地时先拉取，避免 docker run 隐式拉取时无法区分阶段
+	if _, err := r.docker.run(dockerTimeout, nil, "image", "inspect", image); err != nil {
+		spec.OnStage(SpawnStagePullingImage)
+		if err := m.pullImage(ctx, r.hostID, image); err != nil {
+			return err
+		}
+	}
+	// 创建账号时指定恢复备份，在容器启动前替换会话目录
+	if spec.Restore != nil {
+		spec.OnStage(SpawnStageRestoring)
+		if err := m.restoreSession(ctx, r.hostID, spec.Restore, account.ID); err != nil {
+			return err
+		}
+	}
+	spec.OnStage(SpawnStageStarting)
+
+	args := []string{
+		"run", "-d",
+		"--name", spec.Name,
+		"--network", m.GetConfig().Worker.Network,
+		"-e", fmt.Sprintf("PORT=%d", m.GetConfig().Worker.BasePort), // Internal port is usually fixed
+		"-e", fmt.Sprintf("ACCOUNT_ID=%s", account.ID),
+		"-e", fmt.Sprintf("MASTER_URL=%s", m.workerMasterURL()),
+		"-e", "WORKER_TOKEN=" + string(account.WorkerToken),
+	}
+	args = append(args, workerProxyEnv(account.Proxy)...)
+	args = append(args, workerHardwareEnv(m.accountHardware(account))...)
+	args = append(args,
+		"-p", fmt.Sprintf("%d:%d", account.Port, m.GetConfig().Worker.BasePort), // Map external port to internal
+		"--label", fleetManagedLabel+"=true",
+		"--label", fmt.Sprintf("%s=%s", fleetAccountLabel, account.ID),
+	)
+	// Mount session directory
+	args = append(args, m.sessionMountArgs(account.ID)...)
+	resources := m.workerResources(account)
+	args = append(args, dockerResourceArgs(resources)...)
+	args = append(args, dockerRuntimeArgs(m.workerRuntime(account))...)
+	args = append(args, m.dockerHealthArgs()...)
+	args = append(args, image)
+
+	slog.Info("Starting worker container", "container", spec.Name, "host_id", r.hostID, "image", image,
+		"memory", resources.Memory, "cpus", resources.CPUs, "pids_limit", resources.PidsLimit, "restart_policy", resources.RestartPolicy)
+	_, runSpan := tracing.Start(ctx, "docker run", tracing.KindInternal, tracing.String("host.id", r.hostID), tracing.String("container.name", spec.Name))
+	_, err := r.docker.run(dockerTimeout, nil, args...)
+	runSpan.Finish(err)
+	if err != nil {
+		return fmt.Errorf("failed to start docker container: %v", err)
+	}
+
+	if m.sessionSealEnabled() {
+		// 会话解密到容器的tmpfs后Worker才开始恢复会话
+		spec.OnStage(SpawnStageUnsealing)
+		if err := m.unsealSession(r.hostID, spec.Name, account.ID); err != nil {
+			r.Stop(spec.Name)
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *dockerWorkerRuntime) Stop(name string) error {
+	_, err := r.docker.run(dockerTimeout, nil, "rm", "-f", name)
+	return err
+}
+
+func (r *dockerWorkerRuntime) Kill(name string) error {
+	_, err := r.docker.run(dockerTimeout, nil, "kill", name)
+	return err
+}
+
+// List 通过 docker ps -a 列出Worker容器，从端口列解析映射到Worker内部端口的宿主机端口
+func (r *dockerWorkerRuntime) List(prefix string) ([]WorkerContainer, error) {
+	output, err := r.docker.run(dockerTimeout, nil, "ps", "-a",
+		"--filter", "name=^/"+prefix,
+		"--format", `{{.Names}}\t{{.State}}\t{{.Ports}}\t{{.Status}}\t{{.Label "`+fleetManagedLabel+`"}}`)
+	if err != nil {
+		return nil, err
+	}
+
+	basePort := r.manager.GetConfig().Worker.BasePort
+	var containers []WorkerContainer
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		fields := strings.Split(line, "\t")
+		if !strings.HasPrefix(fields[0], prefix) {
+			continue
+		}
+		for len(fields) < 5 {
+			fields = append(fields, "")
+		}
+
+		container := WorkerContainer{Name: fields[0], State: fields[1], Managed: fields[4] == "true"}
+		for _, match := range hostPortPattern.FindAllStringSubmatch(fields[2], -1) {
+			if internal, _ := strconv.Atoi(match[2]); internal == basePort {
+				container.Port, _ = strconv.Atoi(match[1])
+				break
+			}
+		}
+		// 配置了健康检查的容器状态形如 "Up 5 minutes (unhealthy)"
+		if match := dockerHealthStatus.FindStringSubmatch(fields[3]); match != nil {
+			container.Health = strings.TrimPrefix(match[1], "health: ")
+		}
+		containers = append(containers, container)
+	}
+	return containers, nil
+}
+
+func (r *dockerWorkerRuntime) Logs(ctx context.Context, name string, query *model.LogStreamQuery) (io.ReadCloser, error) {
+	args, err := dockerLogsArgs(name, query)
+	if err != nil {
+		return nil, err
+	}
+	return r.docker.stream(ctx, args)
+}
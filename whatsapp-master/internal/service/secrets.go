@@ -0,0 +1,120 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"gorm.io/gorm"
+
+	"whatsapp-aggregator/internal/config"
+	"whatsapp-aggregator/internal/model"
+)
+
+// secretColumn 以 model.Secret 保存凭证的列
+type secretColumn struct {
+	model  interface{}
+	column string
+}
+
+// secretColumns 数据库中所有加密保存的凭证
+var secretColumns = []secretColumn{
+	{&model.Account{}, "proxy_password"},
+	{&model.Account{}, "worker_token"},
+	{&model.Host{}, "token"},
+	{&model.Webhook{}, "secret"},
+	{&model.AlertChannel{}, "bot_token"},
+	{&model.AlertChannel{}, "smtp_password"},
+}
+
+// configureSecrets 加载凭证加密密钥，并用当前密钥重新加密明文或旧密钥加密的凭证
+func configureSecrets(db *gorm.DB, cfg config.SecretsConfig) error {
+	current, err := secretKey(cfg)
+	if err != nil {
+		return err
+	}
+	previous := make([][]byte, 0, len(cfg.PreviousKeys))
+	for _, value := range cfg.PreviousKeys {
+		key, err := model.ParseSecretKey(value)
+		if err != nil {
+			return fmt.Errorf("invalid SECRETS_PREVIOUS_KEYS: %v", err)
+		}
+		previous = append(previous, key)
+	}
+	if err := model.SetSecretKeys(current, previous...); err != nil {
+		return err
+	}
+
+	if current == nil {
+		slog.Warn("SECRETS_ENCRYPTION_KEY not set, credentials are stored in plaintext")
+		return checkEncryptedSecrets(db)
+	}
+	return encryptStoredSecrets(db)
+}
+
+// secretKey 读取当前密钥，KeyFile 优先，都未配置时返回nil
+func secretKey(cfg config.SecretsConfig) ([]byte, error) {
+	value := cfg.Key
+	if cfg.KeyFile != "" {
+		data, err := os.ReadFile(cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SECRETS_ENCRYPTION_KEY_FILE: %v", err)
+		}
+		value = strings.TrimSpace(string(data))
+	}
+	if value == "" {
+		return nil, nil
+	}
+	key, err := model.ParseSecretKey(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid SECRETS_ENCRYPTION_KEY: %v", err)
+	}
+	return key, nil
+}
+
+// checkEncryptedSecrets 未配置密钥时，已加密的凭证无法解密，拒绝启动
+func checkEncryptedSecrets(db *gorm.DB) error {
+	for _, col := range secretColumns {
+		var count int64
+		if err := db.Model(col.model).Where(col.column+" LIKE ?", "enc:%").Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to check encrypted credentials: %v", err)
+		}
+		if count > 0 {
+			return fmt.Errorf("%d encrypted %s values found but SECRETS_ENCRYPTION_KEY is not set: %w", count, col.column, model.ErrSecretKey)
+		}
+	}
+	return nil
+}
+
+// encryptStoredSecrets 用当前密钥加密明文保存或旧密钥加密的凭证
+func encryptStoredSecrets(db *gorm.DB) error {
+	prefix := model.SecretPrefix()
+	encrypted := 0
+	for _, col := range secretColumns {
+		var rows []struct {
+			ID     string
+			Secret model.Secret
+		}
+		err := db.Model(col.model).Select("id, "+col.column+" AS secret").
+			Where(col.column+" <> '' AND "+col.column+" NOT LIKE ?", prefix+"%").
+			Scan(&rows).Error
+		if err != nil {
+			if errors.Is(err, model.ErrSecretKey) {
+				return fmt.Errorf("failed to decrypt %s (missing key in SECRETS_PREVIOUS_KEYS?): %w", col.column, err)
+			}
+			return fmt.Errorf("failed to read %s: %v", col.column, err)
+		}
+		for _, row := range rows {
+			if err := db.Model(col.model).Where("id = ?", row.ID).UpdateColumn(col.column, row.Secret).Error; err != nil {
+				return fmt.Errorf("failed to encrypt %s of %s: %v", col.column, row.ID, err)
+			}
+			encrypted++
+		}
+	}
+	if encrypted > 0 {
+		slog.Info("Encrypted stored credentials with the current key", "count", encrypted, "key_id", model.SecretKeyID())
+	}
+	return nil
+}
@@ -0,0 +1,220 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// sessionRefreshCooldown 同一账号两次主动刷新的最小间隔
+const sessionRefreshCooldown = 24 * time.Hour
+
+// sessionLostStatuses 表示会话已失效、需要重新登录的状态
+var sessionLostStatuses = map[string]bool{
+	"disconnected": true,
+	"auth_failure": true,
+	"logged_out":   true,
+}
+
+// trackSession 根据状态变化记录会话开始和掉线，调用者需持有锁
+func (m *Manager) trackSession(account *model.Account, previous, status string) {
+	now := time.Now()
+	updates := make(map[string]interface{})
+
+	switch {
+	case status == "logged_in" && previous != "logged_in":
+		account.SessionStartedAt = &now
+		updates["session_started_at"] = now
+	case previous == "logged_in" && status != "logged_in":
+		// 更新历史会话平均时长
+		if account.SessionStartedAt != nil {
+			hours := now.Sub(*account.SessionStartedAt).Hours()
+			account.AvgSessionHours = (account.AvgSessionHours*float64(account.SessionDrops) + hours) / float64(account.SessionDrops+1)
+		}
+		account.SessionDrops++
+		account.SessionStartedAt = nil
+		updates["session_started_at"] = nil
+		updates["session_drops"] = account.SessionDrops
+		updates["avg_session_hours"] = account.AvgSessionHours
+		slog.Warn("Session dropped", "account_id", account.ID, "from", previous, "to", status, "drops", account.SessionDrops)
+	default:
+		return
+	}
+
+	m.db.Model(account).Updates(updates)
+}
+
+// predictSession 计算账号会话健康度，调用者需持有读锁
+func (m *Manager) predictSession(account *model.Account, now time.Time) *model.SessionHealth {
+	health := &model.SessionHealth{
+		AccountID:        account.ID,
+		Status:           account.Status,
+		SessionStartedAt: account.SessionStartedAt,
+		SessionDrops:     account.SessionDrops,
+		AvgSessionHours:  account.AvgSessionHours,
+		SessionRefreshAt: account.SessionRefreshAt,
+	}
+
+	if sessionLostStatuses[account.Status] {
+		health.ReloginRecommended = true
+		health.Reason = fmt.Sprintf("session lost (status: %s)", account.Status)
+		return health
+	}
+	if account.Status != "logged_in" || account.SessionStartedAt == nil {
+		return health
+	}
+
+	// 预期会话时长：取配置上限与历史平均时长中较小者
+	age := now.Sub(*account.SessionStartedAt)
+	health.SessionAgeHours = age.Hours()
+
+	expected := time.Duration(m.GetConfig().Session.MaxAgeHours) * time.Hour
+	basis := "max session age"
+	if account.SessionDrops > 0 && account.AvgSessionHours > 0 {
+		historical := time.Duration(account.AvgSessionHours * float64(time.Hour))
+		if expected <= 0 || historical < expected {
+			expected = historical
+			basis = "historical average session length"
+		}
+	}
+	if expected <= 0 {
+		return health
+	}
+
+	expiry := account.SessionStartedAt.Add(expected)
+	health.ExpectedExpiryAt = &expiry
+
+	if age.Hours() >= expected.Hours()*m.GetConfig().Session.ExpiryRatio {
+		health.ReloginRecommended = true
+		health.Reason = fmt.Sprintf("session age %.1fh is close to %s (%.1fh)", age.Hours(), basis, expected.Hours())
+	}
+	return health
+}
+
+// GetSessionHealth 获取账号会话健康度
+func (m *Manager) GetSessionHealth(accountID string) (*model.SessionHealth, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return nil, fmt.Errorf("account %s not found", accountID)
+	}
+	return m.predictSession(account, time.Now()), nil
+}
+
+// ListSessionHealth 获取所有账号的会话健康度
+func (m *Manager) ListSessionHealth() []*model.SessionHealth {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	now := time.Now()
+	sessions := make([]*model.SessionHealth, 0, len(m.accounts))
+	for _, account := range m.accounts {
+		sessions = append(sessions, m.predictSession(account, now))
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].AccountID < sessions[j].AccountID
+	})
+	return sessions
+}
+
+// StartSessionRefresher 启动会话主动刷新任务，在静默时段刷新即将过期的会话
+func (m *Manager) StartSessionRefresher() {
+	cfg := m.GetConfig().Session
+	if !cfg.RefreshEnabled {
+		return
+	}
+
+	interval := time.Duration(cfg.RefreshInterval) * time.Minute
+	if interval <= 0 {
+		interval = 30 * time.Minute
+	}
+	slog.Info("Session refresher enabled", "quiet_hours", fmt.Sprintf("%02d:00-%02d:00", cfg.QuietHourStart, cfg.QuietHourEnd), "interval", interval)
+
+	ticker := time.NewTicker(interval)
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case now := <-ticker.C:
+				if m.IsLeader() && inQuietHours(now.Hour(), cfg.QuietHourStart, cfg.QuietHourEnd) {
+					m.refreshExpiringSessions()
+				}
+			}
+		}
+	}()
+}
+
+// refreshExpiringSessions 刷新建议重新登录且仍在线的账号会话
+func (m *Manager) refreshExpiringSessions() {
+	now := time.Now()
+
+	m.mutex.RLock()
+	candidates := make([]*model.Account, 0)
+	reasons := make(map[string]string)
+	for _, account := range m.accounts {
+		if account.Status != "logged_in" || account.KeepAliveOff {
+			continue
+		}
+		if account.SessionRefreshAt != nil && now.Sub(*account.SessionRefreshAt) < sessionRefreshCooldown {
+			continue
+		}
+		if health := m.predictSession(account, now); health.ReloginRecommended {
+			candidates = append(candidates, account)
+			reasons[account.ID] = health.Reason
+		}
+	}
+	m.mutex.RUnlock()
+
+	for _, account := range candidates {
+		if m.shuttingDown() {
+			return
+		}
+		if err := m.refreshSession(account, "quiet_hours", reasons[account.ID]); err != nil {
+			slog.Warn("Proactive session refresh failed", "account_id", account.ID, "error", err)
+			continue
+		}
+		slog.Info("Proactively refreshed session", "account_id", account.ID)
+	}
+}
+
+// refreshSession 让Worker重启客户端刷新会话，记录刷新时间并发布 session.refreshed 事件
+func (m *Manager) refreshSession(account *model.Account, trigger, reason string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+	if _, err := m.postToWorker(ctx, account, "/api/login/refresh", nil); err != nil {
+		return err
+	}
+
+	m.mutex.Lock()
+	refreshedAt := time.Now()
+	account.SessionRefreshAt = &refreshedAt
+	m.db.Model(account).Update("session_refresh_at", refreshedAt)
+	m.mutex.Unlock()
+
+	m.emit(EventSessionRefreshed, account.ID, map[string]interface{}{
+		"trigger": trigger,
+		"reason":  reason,
+	})
+	return nil
+}
+
+// inQuietHours 判断小时是否落在静默时段内，支持跨零点（如22-5）
+func inQuietHours(hour, start, end int) bool {
+	if start == end {
+		return false
+	}
+	if start < end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
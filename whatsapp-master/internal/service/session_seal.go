@@ -0,0 +1,457 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"whatsapp-aggregator/internal/config"
+	"whatsapp-aggregator/internal/model"
+)
+
+const (
+	// sealedSessionMagic 加密会话归档的文件头，后接4字节密钥指纹、12字节nonce和AES-GCM密文
+	sealedSessionMagic = "WFSEAL1\n"
+	// sealedSessionSuffix 加密归档在会话目录下的文件名后缀，文件名为 <账号ID>.enc
+	sealedSessionSuffix = ".enc"
+	// sessionReadyMarker Master解密会话到容器后创建的标记文件，Worker看到它才开始恢复会话
+	sessionReadyMarker = ".fleet-session-ready"
+	// sessionKeyCommandTimeout SESSION_ENCRYPTION_KEY_COMMAND 的执行超时
+	sessionKeyCommandTimeout = 30 * time.Second
+	// sessionCheckpointDelay 登录成功后等待会话文件写完再加密保存
+	sessionCheckpointDelay = 30 * time.Second
+)
+
+// ErrSessionNotUnsealed 容器内的会话尚未解密，此时打包会用空会话覆盖加密归档
+var ErrSessionNotUnsealed = errors.New("session has not been unsealed into the worker")
+
+// sessionKeyring 会话加密密钥，第一个为当前密钥，其余只用于解密
+type sessionKeyring struct {
+	keys [][]byte
+}
+
+// loadSessionKeys 按 KeyCommand、KeyFile、Key 的顺序读取会话加密密钥，都未配置时返回nil
+func loadSessionKeys(cfg config.SessionSealConfig) (*sessionKeyring, error) {
+	value := cfg.Key
+	switch {
+	case cfg.KeyCommand != "":
+		ctx, cancel := context.WithTimeout(context.Background(), sessionKeyCommandTimeout)
+		defer cancel()
+		var stderr bytes.Buffer
+		cmd := exec.CommandContext(ctx, "sh", "-c", cfg.KeyCommand)
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			return nil, fmt.Errorf("SESSION_ENCRYPTION_KEY_COMMAND failed: %v, output: %s", err, strings.TrimSpace(stderr.String()))
+		}
+		value = strings.TrimSpace(string(output))
+	case cfg.KeyFile != "":
+		data, err := os.ReadFile(cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read SESSION_ENCRYPTION_KEY_FILE: %v", err)
+		}
+		value = strings.TrimSpace(string(data))
+	}
+	if value == "" {
+		if len(cfg.PreviousKeys) > 0 {
+			return nil, fmt.Errorf("SESSION_ENCRYPTION_PREVIOUS_KEYS requires a current session encryption key")
+		}
+		return nil, nil
+	}
+
+	current, err := model.ParseSecretKey(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid session encryption key: %v", err)
+	}
+	keyring := &sessionKeyring{keys: [][]byte{current}}
+	for _, value := range cfg.PreviousKeys {
+		key, err := model.ParseSecretKey(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SESSION_ENCRYPTION_PREVIOUS_KEYS: %v", err)
+		}
+		keyring.keys = append(keyring.keys, key)
+	}
+	return keyring, nil
+}
+
+// sessionKeyID 密钥指纹，写入归档以便轮换后找到对应的密钥
+func sessionKeyID(key []byte) []byte {
+	sum := sha256.Sum256(key)
+	return sum[:4]
+}
+
+func newSessionAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// seal 用当前密钥加密会话归档，账号ID作为附加数据，归档不能被换到其他账号下解密
+func (k *sessionKeyring) seal(accountID string, archive []byte) ([]byte, error) {
+	key := k.keys[0]
+	aead, err := newSessionAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	sealed := make([]byte, 0, len(sealedSessionMagic)+4+len(nonce)+len(archive)+aead.Overhead())
+	sealed = append(sealed, sealedSessionMagic...)
+	sealed = append(sealed, sessionKeyID(key)...)
+	sealed = append(sealed, nonce...)
+	return aead.Seal(sealed, nonce, archive, []byte(accountID)), nil
+}
+
+// open 解密会话归档，按归档中的密钥指纹选择当前或旧密钥
+func (k *sessionKeyring) open(accountID string, sealed []byte) ([]byte, error) {
+	if !bytes.HasPrefix(sealed, []byte(sealedSessionMagic)) || len(sealed) < len(sealedSessionMagic)+4 {
+		return nil, fmt.Errorf("not a sealed session archive")
+	}
+	body := sealed[len(sealedSessionMagic):]
+	keyID, body := body[:4], body[4:]
+	for _, key := range k.keys {
+		if !bytes.Equal(sessionKeyID(key), keyID) {
+			continue
+		}
+		aead, err := newSessionAEAD(key)
+		if err != nil {
+			return nil, err
+		}
+		if len(body) < aead.NonceSize() {
+			return nil, fmt.Errorf("sealed session archive is truncated")
+		}
+		archive, err := aead.Open(nil, body[:aead.NonceSize()], body[aead.NonceSize():], []byte(accountID))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt sealed session: %v", err)
+		}
+		return archive, nil
+	}
+	return nil, fmt.Errorf("sealed session was encrypted with an unknown key %x, add it to SESSION_ENCRYPTION_PREVIOUS_KEYS", keyID)
+}
+
+// sessionSealEnabled 是否加密保存会话，模拟Worker没有会话目录，不加密
+func (m *Manager) sessionSealEnabled() bool {
+	return m.sessionKeys != nil && m.mockWorkers == nil
+}
+
+// sessionSealLock 同一账号的加密和解密串行执行，避免并发写同一个归档
+func (m *Manager) sessionSealLock(accountID string) *sync.Mutex {
+	lock, _ := m.sessionSeals.LoadOrStore(accountID, &sync.Mutex{})
+	return lock.(*sync.Mutex)
+}
+
+// sealedSessionPath 本机上账号加密归档的路径
+func (m *Manager) sealedSessionPath(accountID string) string {
+	return filepath.Join(m.GetConfig().Worker.SessionDir, accountID+sealedSessionSuffix)
+}
+
+// sessionMountArgs Worker容器的会话挂载参数：未开启加密时挂载主机上的会话目录，
+// 开启时挂载只存在于内存的tmpfs，由Master在容器启动后解密写入
+func (m *Manager) sessionMountArgs(accountID string) []string {
+	target := fmt.Sprintf("%s/%s", workerSessionMountDir, accountID)
+	if !m.sessionSealEnabled() {
+		return []string{"-v", fmt.Sprintf("%s:%s", m.sessionDir(accountID), target)}
+	}
+	return []string{
+		"--tmpfs", fmt.Sprintf("%s:rw,size=%s,mode=0700", target, m.GetConfig().SessionSeal.TmpfsSize),
+		"-e", "SESSION_SEALED=true",
+	}
+}
+
+// readSealedSession 读取主机上账号的加密归档，不存在时返回nil。远程主机上在一次性容器中读取，经base64编码返回
+func (m *Manager) readSealedSession(hostID, accountID string) ([]byte, error) {
+	if hostID == "" || hostID == model.LocalHostID {
+		data, err := os.ReadFile(m.sealedSessionPath(accountID))
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return data, err
+	}
+	output, err := m.runDocker(hostID, backupTimeout, "run", "--rm",
+		"-v", m.GetConfig().Worker.SessionDir+":/sessions:ro",
+		"--entrypoint", "sh",
+		m.GetConfig().Worker.Image,
+		"-c", `[ ! -f "/sessions/$1.enc" ] || base64 "/sessions/$1.enc"`, "sh", accountID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sealed session: %v", err)
+	}
+	if strings.TrimSpace(output) == "" {
+		return nil, nil
+	}
+	data, err := base64.StdEncoding.DecodeString(strings.TrimSpace(output))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode sealed session: %v", err)
+	}
+	return data, nil
+}
+
+// writeSealedSession 先写临时文件再改名，替换主机上账号的加密归档，写入中断时旧归档保持完整
+func (m *Manager) writeSealedSession(hostID, accountID string, sealed []byte) error {
+	if hostID == "" || hostID == model.LocalHostID {
+		path := m.sealedSessionPath(accountID)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return fmt.Errorf("failed to create session dir: %v", err)
+		}
+		if err := os.WriteFile(path+".tmp", sealed, 0o600); err != nil {
+			return fmt.Errorf("failed to write sealed session: %v", err)
+		}
+		if err := os.Rename(path+".tmp", path); err != nil {
+			return fmt.Errorf("failed to write sealed session: %v", err)
+		}
+		return nil
+	}
+	if _, err := m.runDockerInput(hostID, backupTimeout, sealed, "run", "--rm", "-i",
+		"-v", m.GetConfig().Worker.SessionDir+":/sessions",
+		"--entrypoint", "sh",
+		m.GetConfig().Worker.Image,
+		"-c", `umask 077 && cat > "/sessions/$1.enc.tmp" && mv "/sessions/$1.enc.tmp" "/sessions/$1.enc"`, "sh", accountID); err != nil {
+		return fmt.Errorf("failed to write sealed session on host %s: %v", hostID, err)
+	}
+	return nil
+}
+
+// storeSealedSession 加密会话归档（tar.gz，顶层为会话目录）并写入主机
+func (m *Manager) storeSealedSession(hostID, accountID string, archive []byte) error {
+	sealed, err := m.sessionKeys.seal(accountID, archive)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt session: %v", err)
+	}
+	return m.writeSealedSession(hostID, accountID, sealed)
+}
+
+// loadSealedSession 读取并解密主机上账号的会话归档，不存在时返回nil
+func (m *Manager) loadSealedSession(hostID, accountID string) ([]byte, error) {
+	sealed, err := m.readSealedSession(hostID, accountID)
+	if err != nil || sealed == nil {
+		return nil, err
+	}
+	return m.sessionKeys.open(accountID, sealed)
+}
+
+// sealPlainSession 把开启加密前留下的明文会话目录加密保存后覆写删除，没有明文目录时不做任何事
+func (m *Manager) sealPlainSession(hostID, accountID string) error {
+	if hostID == "" || hostID == model.LocalHostID {
+		if _, err := os.Stat(m.sessionDir(accountID)); os.IsNotExist(err) {
+			return nil
+		}
+	}
+	archive, err := m.archiveSession(hostID, accountID)
+	if err != nil {
+		if strings.Contains(err.Error(), "session directory not found") {
+			return nil
+		}
+		return err
+	}
+	if err := m.storeSealedSession(hostID, accountID, archive); err != nil {
+		return err
+	}
+	if err := m.shredPlainSession(hostID, accountID); err != nil {
+		return fmt.Errorf("session sealed but the plaintext directory was not removed: %v", err)
+	}
+	slog.Info("Plaintext session directory encrypted", "account_id", accountID, "host_id", hostID)
+	return nil
+}
+
+// unsealSession 解密账号的会话写入运行中Worker容器的tmpfs，完成后创建标记文件通知Worker恢复会话；
+// 没有加密归档的新账号只创建标记文件。标记文件已存在时（重复调用）不再写入
+func (m *Manager) unsealSession(hostID, containerName, accountID string) error {
+	if err := validSessionName(accountID); err != nil {
+		return err
+	}
+	lock := m.sessionSealLock(accountID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	if err := m.sealPlainSession(hostID, accountID); err != nil {
+		return err
+	}
+	archive, err := m.loadSealedSession(hostID, accountID)
+	if err != nil {
+		return err
+	}
+	// 归档顶层是会话目录，账号克隆或从其他账号的备份恢复时目录名不同，解压时去掉顶层目录
+	hasArchive := "0"
+	if archive != nil {
+		hasArchive = "1"
+	}
+	if _, err := m.runDockerInput(hostID, backupTimeout, archive, "exec", "-i", containerName, "sh", "-c",
+		`[ ! -e "$1/`+sessionReadyMarker+`" ] || exit 0; if [ "$2" = 1 ]; then tar -C "$1" --strip-components=1 -xzf - || exit 1; fi; touch "$1/`+sessionReadyMarker+`"`,
+		"sh", workerSessionMountDir+"/"+accountID, hasArchive); err != nil {
+		return fmt.Errorf("failed to unseal session into worker: %v", err)
+	}
+	slog.Info("Session unsealed into worker", "account_id", accountID, "host_id", hostID, "size_bytes", len(archive))
+	return nil
+}
+
+// sealSession 打包运行中Worker容器内的会话，加密后替换主机上的归档，返回打包的明文归档。
+// 容器内会话尚未解密时返回 ErrSessionNotUnsealed，保留原有归档
+func (m *Manager) sealSession(hostID, containerName, accountID string) ([]byte, error) {
+	if err := validSessionName(accountID); err != nil {
+		return nil, err
+	}
+	lock := m.sessionSealLock(accountID)
+	lock.Lock()
+	defer lock.Unlock()
+
+	output, err := m.runDocker(hostID, backupTimeout, "exec", containerName, "sh", "-c",
+		`[ -e "$1/$2/`+sessionReadyMarker+`" ] || { echo "session not unsealed" >&2; exit 3; }; tar -C "$1" -czf - --exclude="$2/`+sessionReadyMarker+`" "$2" | base64`,
+		"sh", workerSessionMountDir, accountID)
+	if err != nil {
+		if strings.Contains(err.Error(), "session not unsealed") {
+			return nil, ErrSessionNotUnsealed
+		}
+		return nil, fmt.Errorf("failed to archive worker session: %v", err)
+	}
+	archive, err := base64.StdEncoding.DecodeString(strings.TrimSpace(output))
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode worker session: %v", err)
+	}
+	if err := m.storeSealedSession(hostID, accountID, archive); err != nil {
+		return nil, err
+	}
+	slog.Debug("Session sealed", "account_id", accountID, "host_id", hostID, "size_bytes", len(archive))
+	return archive, nil
+}
+
+// sealBeforeRemove 删除Worker容器前加密保存会话，容器已退出（tmpfs已丢失）、会话未解密或主机离线时保留上次的归档
+func (m *Manager) sealBeforeRemove(hostID, accountID string) {
+	if !m.sessionSealEnabled() {
+		return
+	}
+	m.hostMutex.RLock()
+	host, exists := m.hosts[valueOrDefault(hostID, model.LocalHostID)]
+	offline := exists && host.Status == model.HostStatusOffline
+	m.hostMutex.RUnlock()
+	if offline {
+		return
+	}
+	if _, err := m.sealSession(hostID, workerContainerPrefix+accountID, accountID); err != nil {
+		slog.Warn("Failed to seal session before removing worker, keeping the previous sealed session", "account_id", accountID, "host_id", hostID, "error", err)
+	}
+}
+
+// scheduleSessionCheckpoint 账号登录成功后稍等片刻加密保存会话，新登录的会话在容器意外退出时不会丢失
+func (m *Manager) scheduleSessionCheckpoint(accountID string) {
+	if !m.sessionSealEnabled() {
+		return
+	}
+	time.AfterFunc(sessionCheckpointDelay, func() {
+		m.checkpointSession(accountID)
+	})
+}
+
+// checkpointSession 加密保存运行中账号的会话，账号已停止或不存在时跳过
+func (m *Manager) checkpointSession(accountID string) {
+	m.mutex.RLock()
+	account, exists := m.accounts[accountID]
+	var hostID, status string
+	if exists {
+		hostID, status = account.HostID, account.Status
+	}
+	m.mutex.RUnlock()
+	if !exists || status == "stopped" || status == "error" || status == "creating" {
+		return
+	}
+	if _, err := m.sealSession(hostID, workerContainerPrefix+accountID, accountID); err != nil && !errors.Is(err, ErrSessionNotUnsealed) {
+		slog.Warn("Session checkpoint failed", "account_id", accountID, "host_id", hostID, "error", err)
+	}
+}
+
+// StartSessionCheckpointer 按 SESSION_CHECKPOINT_MINUTES 定期加密保存所有运行中Worker的会话
+func (m *Manager) StartSessionCheckpointer() {
+	if !m.sessionSealEnabled() {
+		return
+	}
+	if m.GetConfig().SessionSeal.CheckpointMinutes <= 0 {
+		slog.Info("Session encryption enabled, periodic checkpoints disabled")
+		return
+	}
+
+	interval := time.Duration(m.GetConfig().SessionSeal.CheckpointMinutes) * time.Minute
+	slog.Info("Session encryption enabled", "checkpoint_interval", interval, "tmpfs_size", m.GetConfig().SessionSeal.TmpfsSize)
+
+	ticker := time.NewTicker(interval)
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				if !m.IsLeader() {
+					continue
+				}
+				for _, account := range m.ListAccounts() {
+					if m.shuttingDown() {
+						break
+					}
+					m.checkpointSession(account.ID)
+				}
+			}
+		}
+	}()
+}
+
+// sessionArchive 账号会话的tar.gz归档，用于备份：开启加密时优先打包运行中Worker的最新会话（同时更新加密归档），
+// 否则解密主机上的归档；都没有时打包尚未加密的明文目录
+func (m *Manager) sessionArchive(hostID, accountID string) ([]byte, error) {
+	if !m.sessionSealEnabled() {
+		return m.archiveSession(hostID, accountID)
+	}
+	if archive, err := m.sealSession(hostID, workerContainerPrefix+accountID, accountID); err == nil {
+		return archive, nil
+	}
+	lock := m.sessionSealLock(accountID)
+	lock.Lock()
+	archive, err := m.loadSealedSession(hostID, accountID)
+	lock.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if archive != nil {
+		return archive, nil
+	}
+	return m.archiveSession(hostID, accountID)
+}
+
+// sessionWaiting Worker报告会话尚未解密（容器被Docker重启后tmpfs已清空），重新解密写入
+func (m *Manager) sessionWaiting(accountID string) {
+	if !m.sessionSealEnabled() {
+		return
+	}
+	m.mutex.RLock()
+	account, exists := m.accounts[accountID]
+	var hostID, status string
+	if exists {
+		hostID, status = account.HostID, account.Status
+	}
+	m.mutex.RUnlock()
+	if !exists || status == "creating" || status == "stopped" {
+		// 启动中的Worker由 spawnWorkerDocker 解密
+		return
+	}
+	go func() {
+		if err := m.unsealSession(hostID, workerContainerPrefix+accountID, accountID); err != nil {
+			slog.Error("Failed to unseal session for restarted worker", "account_id", accountID, "host_id", hostID, "error", err)
+		}
+	}()
+}
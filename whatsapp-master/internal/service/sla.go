@@ -0,0 +1,218 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// slaWindows 可用性统计窗口，最长的窗口决定需要加载的状态历史范围
+var slaWindows = []struct {
+	Name     string
+	Duration time.Duration
+}{
+	{"24h", 24 * time.Hour},
+	{"7d", 7 * 24 * time.Hour},
+	{"30d", 30 * 24 * time.Hour},
+}
+
+// slaPlannedStatuses 计划内的状态，不计入在线也不计入故障
+var slaPlannedStatuses = map[string]bool{
+	"creating": true,
+	"stopping": true,
+	"stopped":  true,
+}
+
+// slaSegment 账号保持同一状态的一段时间
+type slaSegment struct {
+	start, end time.Time
+	status     string
+	source     string
+	reason     string
+}
+
+// slaUp 状态是否计为在线：只有已登录的账号可以收发消息
+func slaUp(status string) bool {
+	return status == "logged_in"
+}
+
+// slaDown 状态是否计为故障
+func slaDown(status string) bool {
+	return status != "" && !slaUp(status) && !slaPlannedStatuses[status]
+}
+
+// GetAccountSLA 根据状态历史计算账号最近24小时、7天和30天的可用性以及故障时段
+func (m *Manager) GetAccountSLA(accountID string) (*model.AccountSLA, error) {
+	account, err := m.GetAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	initial, transitions, err := m.loadSLAHistory([]string{accountID}, now)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return computeSLA(account, initial[accountID], transitions[accountID], now), nil
+}
+
+// loadSLAHistory 加载最长窗口内的状态历史，以及每个账号在窗口开始前的最后一条记录（窗口开始时的状态）；
+// accountIDs 为空时加载所有账号
+func (m *Manager) loadSLAHistory(accountIDs []string, now time.Time) (map[string]*model.StatusTransition, map[string][]*model.StatusTransition, error) {
+	since := now.Add(-slaWindows[len(slaWindows)-1].Duration)
+
+	latest := m.db.Model(&model.StatusTransition{}).Select("MAX(id)").Where("created_at < ?", since).Group("account_id")
+	inWindow := m.db.Where("created_at >= ?", since).Order("created_at ASC, id ASC")
+	if len(accountIDs) > 0 {
+		latest = latest.Where("account_id IN ?", accountIDs)
+		inWindow = inWindow.Where("account_id IN ?", accountIDs)
+	}
+
+	var before []*model.StatusTransition
+	if err := m.db.Where("id IN (?)", latest).Find(&before).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to load status history: %v", err)
+	}
+	var rows []*model.StatusTransition
+	if err := inWindow.Find(&rows).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to load status history: %v", err)
+	}
+
+	initial := make(map[string]*model.StatusTransition, len(before))
+	for _, t := range before {
+		initial[t.AccountID] = t
+	}
+	transitions := make(map[string][]*model.StatusTransition)
+	for _, t := range rows {
+		transitions[t.AccountID] = append(transitions[t.AccountID], t)
+	}
+	return initial, transitions, nil
+}
+
+// computeSLA 由状态历史计算可用性，调用方需持有读锁；账号没有任何状态记录时（早于状态历史功能创建），从创建时间起按当前状态计算
+func computeSLA(account *model.Account, initial *model.StatusTransition, transitions []*model.StatusTransition, now time.Time) *model.AccountSLA {
+	if initial == nil && len(transitions) == 0 {
+		initial = &model.StatusTransition{Status: account.Status, CreatedAt: account.CreatedAt}
+	}
+	segments := slaSegments(initial, transitions, now)
+	outages := slaOutages(segments)
+
+	sla := &model.AccountSLA{
+		AccountID: account.ID,
+		Status:    account.Status,
+		Windows:   make(map[string]*model.SLAWindow, len(slaWindows)),
+		Outages:   make([]*model.SLAOutage, 0, len(outages)),
+	}
+	for _, w := range slaWindows {
+		start := now.Add(-w.Duration)
+		window := &model.SLAWindow{}
+		for _, seg := range segments {
+			seconds := int64(slaOverlap(seg.start, seg.end, start, now).Seconds())
+			switch {
+			case slaUp(seg.status):
+				window.UptimeSeconds += seconds
+			case slaDown(seg.status):
+				window.DowntimeSeconds += seconds
+			}
+		}
+		for _, outage := range outages {
+			if outage.End == nil || outage.End.After(start) {
+				window.Outages++
+			}
+		}
+		window.Availability = availabilityPercent(window.UptimeSeconds, window.DowntimeSeconds)
+		sla.Windows[w.Name] = window
+	}
+	for i := len(outages) - 1; i >= 0; i-- {
+		sla.Outages = append(sla.Outages, outages[i])
+	}
+	return sla
+}
+
+// slaSegments 把状态历史切分为按时间排列的状态时段，initial为空时第一条记录之前的时间未知、不计入
+func slaSegments(initial *model.StatusTransition, transitions []*model.StatusTransition, now time.Time) []slaSegment {
+	segments := make([]slaSegment, 0, len(transitions)+1)
+	var current *slaSegment
+	if initial != nil {
+		current = &slaSegment{start: initial.CreatedAt, status: initial.Status, source: initial.Source, reason: initial.Reason}
+	}
+	for _, t := range transitions {
+		if current != nil {
+			current.end = t.CreatedAt
+			segments = append(segments, *current)
+		}
+		current = &slaSegment{start: t.CreatedAt, status: t.Status, source: t.Source, reason: t.Reason}
+	}
+	if current != nil {
+		current.end = now
+		segments = append(segments, *current)
+	}
+	return segments
+}
+
+// slaOutages 合并连续的故障时段（如 unreachable → restarting → running），进入在线或计划内状态时故障结束
+func slaOutages(segments []slaSegment) []*model.SLAOutage {
+	outages := make([]*model.SLAOutage, 0)
+	var current *model.SLAOutage
+	for i, seg := range segments {
+		if slaDown(seg.status) {
+			if current == nil {
+				current = &model.SLAOutage{Start: seg.start, Status: seg.status, Source: seg.source, Reason: seg.reason}
+				outages = append(outages, current)
+			}
+			if i == len(segments)-1 {
+				current.DurationSeconds = int64(seg.end.Sub(current.Start).Seconds())
+			}
+			continue
+		}
+		if current != nil {
+			end := seg.start
+			current.End = &end
+			current.DurationSeconds = int64(end.Sub(current.Start).Seconds())
+			current = nil
+		}
+	}
+	return outages
+}
+
+// slaOverlap 两个时间段的重叠时长
+func slaOverlap(start, end, windowStart, windowEnd time.Time) time.Duration {
+	if start.Before(windowStart) {
+		start = windowStart
+	}
+	if end.After(windowEnd) {
+		end = windowEnd
+	}
+	if !end.After(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// availabilityPercent 在线时间占比，保留两位小数，没有可统计的时间时返回nil
+func availabilityPercent(up, down int64) *float64 {
+	if up+down == 0 {
+		return nil
+	}
+	percent := math.Round(float64(up)/float64(up+down)*10000) / 100
+	return &percent
+}
+
+// addSLAWindows 把账号的可用性累加到分组统计中
+func addSLAWindows(totals map[string]*model.SLAWindow, windows map[string]*model.SLAWindow) {
+	for name, w := range windows {
+		total, exists := totals[name]
+		if !exists {
+			total = &model.SLAWindow{}
+			totals[name] = total
+		}
+		total.UptimeSeconds += w.UptimeSeconds
+		total.DowntimeSeconds += w.DowntimeSeconds
+		total.Outages += w.Outages
+		total.Availability = availabilityPercent(total.UptimeSeconds, total.DowntimeSeconds)
+	}
+}
@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// startupDiagnosticsTimeout 采集启动诊断的最长时间，避免诊断本身卡住启动失败的返回
+const startupDiagnosticsTimeout = 15 * time.Second
+
+// startupLogLimit 诊断中保留的日志最大字节数
+const startupLogLimit = 64 * 1024
+
+// WorkerStartupError Worker容器未能在超时前就绪，带有容器状态、退出码和最近日志
+type WorkerStartupError struct {
+	Diagnostics *model.WorkerStartupDiagnostics
+	Err         error
+}
+
+func (e *WorkerStartupError) Error() string {
+	msg := e.Err.Error()
+	d := e.Diagnostics
+	if d.ExitCode != nil {
+		msg += fmt.Sprintf(" (container %s, exit code %d)", d.State, *d.ExitCode)
+	} else if d.State != "" {
+		msg += fmt.Sprintf(" (container %s)", d.State)
+	}
+	if d.OOMKilled {
+		msg += ", killed by OOM"
+	}
+	if line := lastLogLine(d.Logs); line != "" {
+		msg += ", last log: " + line
+	}
+	return msg
+}
+
+func (e *WorkerStartupError) Unwrap() error {
+	return e.Err
+}
+
+// lastLogLine 日志的最后一个非空行
+func lastLogLine(logs string) string {
+	lines := strings.Split(strings.TrimSpace(logs), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// readyTimeout 启动容器后等待Worker就绪的时间
+func (m *Manager) readyTimeout() time.Duration {
+	if m.GetConfig().Worker.ReadyTimeoutSeconds <= 0 {
+		return 60 * time.Second
+	}
+	return time.Duration(m.GetConfig().Worker.ReadyTimeoutSeconds) * time.Second
+}
+
+// containerExited 返回检查容器是否已退出的函数，供等待就绪时提前结束
+func (m *Manager) containerExited(hostID, containerName string) func() bool {
+	return func() bool {
+		out, err := m.runDocker(hostID, 10*time.Second, "inspect", "--format", "{{.State.Status}}", containerName)
+		if err != nil {
+			return false
+		}
+		status := strings.TrimSpace(out)
+		return status == "exited" || status == "dead"
+	}
+}
+
+// workerStartupError 采集容器的状态和最近日志，包装为 *WorkerStartupError
+func (m *Manager) workerStartupError(hostID, containerName string, err error) error {
+	diagnostics := m.collectStartupDiagnostics(hostID, containerName)
+	slog.Warn("Worker failed to become ready", "container", containerName, "host_id", hostID,
+		"state", diagnostics.State, "exit_code", diagnostics.ExitCode, "error", err)
+	return &WorkerStartupError{Diagnostics: diagnostics, Err: err}
+}
+
+// collectStartupDiagnostics 通过 docker inspect 和 docker logs 采集启动诊断，单项失败时记录在 errors 中
+func (m *Manager) collectStartupDiagnostics(hostID, containerName string) *model.WorkerStartupDiagnostics {
+	d := &model.WorkerStartupDiagnostics{
+		Container:      containerName,
+		HostID:         hostID,
+		TimeoutSeconds: int(m.readyTimeout() / time.Second),
+	}
+
+	out, err := m.runDocker(hostID, startupDiagnosticsTimeout, "inspect", containerName)
+	if err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("docker inspect: %v", err))
+	} else {
+		var inspect []struct {
+			State struct {
+				Status    string `json:"Status"`
+				ExitCode  int    `json:"ExitCode"`
+				OOMKilled bool   `json:"OOMKilled"`
+				Error     string `json:"Error"`
+			} `json:"State"`
+		}
+		if err := json.Unmarshal([]byte(out), &inspect); err != nil || len(inspect) == 0 {
+			d.Errors = append(d.Errors, fmt.Sprintf("docker inspect: unexpected output: %v", err))
+		} else {
+			state := inspect[0].State
+			d.State = state.Status
+			d.OOMKilled = state.OOMKilled
+			d.StateError = state.Error
+			if state.Status == "exited" || state.Status == "dead" {
+				exitCode := state.ExitCode
+				d.ExitCode = &exitCode
+			}
+			d.Inspect = model.RawJSON(strings.TrimSpace(out))
+		}
+	}
+
+	logs, err := m.readStartupLogs(hostID, containerName)
+	if err != nil {
+		d.Errors = append(d.Errors, fmt.Sprintf("docker logs: %v", err))
+	}
+	d.Logs = logs
+	return d
+}
+
+// readStartupLogs 读取容器最近的日志（合并标准输出和标准错误）
+func (m *Manager) readStartupLogs(hostID, containerName string) (string, error) {
+	tail := m.GetConfig().Worker.StartupLogLines
+	if tail <= 0 {
+		return "", nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), startupDiagnosticsTimeout)
+	defer cancel()
+
+	follow := false
+	stream, err := m.hostRuntime(hostID).Logs(ctx, containerName, &model.LogStreamQuery{Tail: &tail, Follow: &follow})
+	if err != nil {
+		return "", err
+	}
+	defer stream.Close()
+
+	data, err := io.ReadAll(io.LimitReader(stream, startupLogLimit))
+	return string(data), err
+}
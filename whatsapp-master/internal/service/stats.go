@@ -0,0 +1,103 @@
+package service
+
+import (
+	"log/slog"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// 统计分组维度
+const (
+	StatsByTag         = "tag"
+	StatsByPool        = "pool"
+	StatsByTenant      = "tenant"
+	StatsByProxyRegion = "proxy_region"
+	StatsByHost        = "host"
+)
+
+// GetStats 计算全局统计以及按标签、池、租户、代理地区、主机的分组统计，每组带有由状态历史计算的可用性
+func (m *Manager) GetStats() *model.FleetStats {
+	// 今日消息取每日统计，跨零点自动归零
+	todayMessages := m.todayMessagesSent()
+
+	now := time.Now()
+	initial, transitions, err := m.loadSLAHistory(nil, now)
+	if err != nil {
+		slog.Warn("Failed to compute availability for stats", "error", err)
+	}
+
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	stats := &model.FleetStats{
+		TodayMessages: todayMessages,
+		Breakdowns: map[string]map[string]*model.StatsBucket{
+			StatsByTag:         {},
+			StatsByPool:        {},
+			StatsByTenant:      {},
+			StatsByProxyRegion: {},
+			StatsByHost:        {},
+		},
+		Availability: map[string]*model.SLAWindow{},
+	}
+
+	for _, account := range m.accounts {
+		var availability map[string]*model.SLAWindow
+		if err == nil {
+			availability = computeSLA(account, initial[account.ID], transitions[account.ID], now).Windows
+			addSLAWindows(stats.Availability, availability)
+		}
+
+		stats.TotalWorkers++
+		if isOnlineStatus(account.Status) {
+			stats.OnlineWorkers++
+		}
+
+		tags := account.Tags
+		if len(tags) == 0 {
+			tags = model.StringList{"untagged"}
+		}
+		for _, tag := range tags {
+			addToBucket(stats.Breakdowns[StatsByTag], tag, account, availability)
+		}
+		addToBucket(stats.Breakdowns[StatsByPool], valueOrDefault(account.Pool, "default"), account, availability)
+		addToBucket(stats.Breakdowns[StatsByTenant], valueOrDefault(account.TenantID, "default"), account, availability)
+		addToBucket(stats.Breakdowns[StatsByProxyRegion], valueOrDefault(account.ProxyRegion, "unknown"), account, availability)
+		addToBucket(stats.Breakdowns[StatsByHost], hostLabel(account.HostID), account, availability)
+	}
+
+	return stats
+}
+
+// addToBucket 将账号及其可用性计入指定分组
+func addToBucket(buckets map[string]*model.StatsBucket, key string, account *model.Account, availability map[string]*model.SLAWindow) {
+	bucket, exists := buckets[key]
+	if !exists {
+		bucket = &model.StatsBucket{Availability: map[string]*model.SLAWindow{}}
+		buckets[key] = bucket
+	}
+	addSLAWindows(bucket.Availability, availability)
+	bucket.TotalWorkers++
+	if isOnlineStatus(account.Status) {
+		bucket.OnlineWorkers++
+	}
+	if account.Status == "logged_in" {
+		bucket.LoggedInWorkers++
+	}
+	bucket.MessagesSent += account.MessagesSent
+	bucket.MessagesReceived += account.MessagesReceived
+}
+
+// isOnlineStatus 判断账号状态是否在线
+func isOnlineStatus(status string) bool {
+	return status == "logged_in" || status == "running"
+}
+
+// valueOrDefault 空字符串时返回默认值
+func valueOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
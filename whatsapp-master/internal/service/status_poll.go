@@ -0,0 +1,87 @@
+package service
+
+import (
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+const (
+	statusPollTick       = time.Second // 状态轮询的检查粒度，每次只轮询到期的账号
+	minStatusPollSeconds = 5           // 账号单独设置的轮询间隔下限
+)
+
+// loginStatuses 启动和登录过程中的状态，按登录轮询间隔轮询
+var loginStatuses = map[string]bool{
+	"starting":         true,
+	"initializing":     true,
+	"waiting_for_scan": true,
+	"waiting_for_code": true,
+}
+
+// StartStatusPoller 启动状态轮询，间隔由 statusPoll 配置和账号的 status_poll_seconds 决定，修改后立即生效；只有Leader轮询
+func (m *Manager) StartStatusPoller() {
+	// 启动时立即执行一次状态检查
+	if m.IsLeader() {
+		go m.pollDueAccountStatuses()
+	}
+
+	ticker := time.NewTicker(statusPollTick)
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				if !m.IsLeader() {
+					continue
+				}
+				m.pollDueAccountStatuses()
+			}
+		}
+	}()
+}
+
+// statusPollIntervalLocked 账号的状态轮询间隔：登录过程中使用登录间隔，否则使用账号单独设置的间隔或全局间隔，调用方需持有mutex
+func (m *Manager) statusPollIntervalLocked(account *model.Account) time.Duration {
+	cfg := m.GetConfig().StatusPoll
+	seconds := cfg.IntervalSeconds
+	switch {
+	case loginStatuses[account.Status] && cfg.LoginIntervalSeconds > 0:
+		seconds = cfg.LoginIntervalSeconds
+	case account.StatusPollSeconds > 0:
+		seconds = account.StatusPollSeconds
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// pollDueAccountStatuses 轮询到期的账号，上一次轮询未结束的账号跳过
+func (m *Manager) pollDueAccountStatuses() {
+	now := time.Now()
+	m.mutex.RLock()
+	accounts := make([]*model.Account, 0)
+	for _, acc := range m.accounts {
+		if acc.Status == "stopped" || acc.Status == "error" || acc.Status == statusBanned {
+			continue
+		}
+		if last, ok := m.statusPolledAt.Load(acc.ID); ok && now.Sub(last.(time.Time)) < m.statusPollIntervalLocked(acc) {
+			continue
+		}
+		accounts = append(accounts, acc)
+	}
+	m.mutex.RUnlock()
+
+	for _, acc := range accounts {
+		if _, polling := m.statusPolling.LoadOrStore(acc.ID, true); polling {
+			continue
+		}
+		m.statusPolledAt.Store(acc.ID, now)
+		go func(acc *model.Account) {
+			defer m.statusPolling.Delete(acc.ID)
+			m.checkWorkerStatus(acc)
+		}(acc)
+	}
+}
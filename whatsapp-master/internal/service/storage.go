@@ -0,0 +1,191 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/config"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/storage"
+)
+
+// 各用途在对象存储中的键前缀，租户写入的对象另加 tenants/{租户ID}/ 前缀
+const (
+	objectPrefixUploads = "uploads"
+	objectPrefixExports = "exports"
+	objectPrefixBackups = "backups"
+)
+
+// BackupDestinationStorage BACKUP_DESTINATION 取该值时备份写入对象存储的 backups/ 下
+const BackupDestinationStorage = "storage"
+
+// objectNameUnsafe 对象键中文件名部分不允许的字符
+var objectNameUnsafe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// objectNamePrefix objectKey 加在文件名前的随机ID
+var objectNamePrefix = regexp.MustCompile(`^[0-9a-f]{12}-`)
+
+// openObjectStore 打开 STORAGE_DESTINATION，配置错误时只记录警告，使用对象存储的接口返回该错误
+func openObjectStore(cfg config.StorageConfig) storage.Store {
+	store, err := storage.Open(storage.Config{
+		Destination:       cfg.Destination,
+		S3Endpoint:        cfg.S3Endpoint,
+		S3Region:          cfg.S3Region,
+		S3AccessKeyID:     cfg.S3AccessKeyID,
+		S3SecretAccessKey: cfg.S3SecretAccessKey,
+		SigningKey:        []byte(cfg.SigningKey),
+		DownloadURL:       strings.TrimRight(cfg.BaseURL, "/") + "/storage",
+	})
+	if err != nil {
+		slog.Warn("Object storage is not available", "destination", cfg.Destination, "error", err)
+		return nil
+	}
+	return store
+}
+
+// getObjectStore 返回对象存储，未正确配置时返回错误
+func (m *Manager) getObjectStore() (storage.Store, error) {
+	if m.objectStore == nil {
+		return nil, fmt.Errorf("object storage is not configured, check STORAGE_DESTINATION")
+	}
+	return m.objectStore, nil
+}
+
+// MaxObjectUploadSize 返回 POST /storage/objects 允许的单个文件大小（字节）
+func (m *Manager) MaxObjectUploadSize() int64 {
+	return int64(m.GetConfig().Storage.MaxUploadMB) * 1024 * 1024
+}
+
+// TenantObjectPrefix 租户对象的键前缀，管理员为空
+func TenantObjectPrefix(tenantID string) string {
+	if tenantID == "" {
+		return ""
+	}
+	return "tenants/" + tenantID
+}
+
+// ObjectInTenant 租户只能访问自己前缀下的对象，管理员（tenantID为空）不受限制
+func ObjectInTenant(key, tenantID string) bool {
+	return tenantID == "" || strings.HasPrefix(key, TenantObjectPrefix(tenantID)+"/")
+}
+
+// objectKey 生成 [tenants/{租户}/]{用途}/{日期}/{随机ID}-{文件名} 形式的键，同名文件不会互相覆盖
+func objectKey(tenantID, prefix, filename string) string {
+	name := strings.Trim(objectNameUnsafe.ReplaceAllString(path.Base(filename), "_"), "._")
+	if name == "" {
+		name = "file"
+	}
+	key := fmt.Sprintf("%s/%s/%s-%s", prefix, time.Now().UTC().Format("20060102"), randomHex(6), name)
+	return storage.JoinKey(TenantObjectPrefix(tenantID), key)
+}
+
+// ObjectFilename 返回对象键中的原始文件名，去掉上传时添加的随机ID
+func ObjectFilename(key string) string {
+	return objectNamePrefix.ReplaceAllString(path.Base(key), "")
+}
+
+// putObject 写入对象并生成签名下载地址，签名失败时只返回键
+func (m *Manager) putObject(ctx context.Context, key string, data []byte) (*model.StoredObject, error) {
+	store, err := m.getObjectStore()
+	if err != nil {
+		return nil, err
+	}
+	if err := store.Put(ctx, key, data); err != nil {
+		return nil, err
+	}
+	object, err := m.SignObjectURL(key)
+	if err != nil {
+		slog.Warn("Failed to sign object url", "key", key, "error", err)
+		object = &model.StoredObject{Key: key}
+	}
+	object.Size = int64(len(data))
+	return object, nil
+}
+
+// UploadObject 保存上传的文件，之后可以通过 storage_key 发送媒体或导入联系人
+func (m *Manager) UploadObject(ctx context.Context, tenantID, filename string, data []byte) (*model.StoredObject, error) {
+	return m.putObject(ctx, objectKey(tenantID, objectPrefixUploads, filename), data)
+}
+
+// StoreExport 保存导出文件并返回签名下载地址
+func (m *Manager) StoreExport(ctx context.Context, tenantID, filename string, data []byte) (*model.StoredObject, error) {
+	return m.putObject(ctx, objectKey(tenantID, objectPrefixExports, filename), data)
+}
+
+// SignObjectURL 为对象生成 STORAGE_URL_TTL_MINUTES 内有效的下载地址，不检查对象是否存在
+func (m *Manager) SignObjectURL(key string) (*model.StoredObject, error) {
+	store, err := m.getObjectStore()
+	if err != nil {
+		return nil, err
+	}
+	ttl := time.Duration(m.GetConfig().Storage.URLTTLMinutes) * time.Minute
+	if ttl > storage.MaxSignedURLTTL {
+		ttl = storage.MaxSignedURLTTL
+	}
+	signed, err := store.SignedURL(key, ttl)
+	if err != nil {
+		return nil, err
+	}
+	expiresAt := time.Now().Add(ttl).UTC().Truncate(time.Second)
+	return &model.StoredObject{Key: key, URL: signed, ExpiresAt: &expiresAt}, nil
+}
+
+// ReadObject 读取对象，租户只能读取自己的对象，超过 maxSize 时返回错误
+func (m *Manager) ReadObject(ctx context.Context, tenantID, key string, maxSize int64) ([]byte, error) {
+	if !ObjectInTenant(key, tenantID) {
+		return nil, fmt.Errorf("storage key %s not found", key)
+	}
+	store, err := m.getObjectStore()
+	if err != nil {
+		return nil, err
+	}
+	data, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	if maxSize > 0 && int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("object exceeds max size of %d bytes", maxSize)
+	}
+	return data, nil
+}
+
+// DeleteObject 删除对象，不存在时不报错
+func (m *Manager) DeleteObject(ctx context.Context, key string) error {
+	store, err := m.getObjectStore()
+	if err != nil {
+		return err
+	}
+	return store.Delete(ctx, key)
+}
+
+// OpenSignedObject 校验本地对象下载地址的签名并读取内容
+func (m *Manager) OpenSignedObject(ctx context.Context, key, expires, sig string) ([]byte, string, error) {
+	exp, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid expires")
+	}
+	if err := storage.Verify([]byte(m.GetConfig().Storage.SigningKey), key, exp, sig, time.Now()); err != nil {
+		return nil, "", err
+	}
+	store, err := m.getObjectStore()
+	if err != nil {
+		return nil, "", err
+	}
+	data, err := store.Get(ctx, key)
+	if err != nil {
+		return nil, "", fmt.Errorf("object not found")
+	}
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = http.DetectContentType(data)
+	}
+	return data, contentType, nil
+}
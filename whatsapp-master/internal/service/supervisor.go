@@ -0,0 +1,229 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// supervisorStableWindow Worker重启后持续健康超过该时长，重启计数清零
+const supervisorStableWindow = 10 * time.Minute
+
+// supervisorState 单个账号的自动恢复状态
+type supervisorState struct {
+	failures    int       // 连续健康检查失败次数
+	restarts    int       // 连续重启次数
+	lastRestart time.Time // 最近一次重启时间
+	nextAttempt time.Time // 下一次允许重启的时间
+	busy        bool      // 正在检查或重启
+}
+
+// StartSupervisor 启动Worker自动恢复任务：健康检查连续失败时按指数退避重启，超过上限标记为crash_looping
+func (m *Manager) StartSupervisor() {
+	cfg := m.GetConfig().Supervisor
+	if !cfg.Enabled {
+		return
+	}
+
+	interval := time.Duration(cfg.Interval) * time.Second
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	slog.Info("Worker supervisor enabled", "interval", interval, "failure_threshold", cfg.FailureThreshold, "max_restarts", cfg.MaxRestarts)
+
+	ticker := time.NewTicker(interval)
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer ticker.Stop()
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case <-ticker.C:
+				if !m.IsLeader() {
+					continue
+				}
+				m.superviseWorkers()
+			}
+		}
+	}()
+}
+
+// superviseWorkers 检查所有应处于运行状态的Worker
+func (m *Manager) superviseWorkers() {
+	m.mutex.RLock()
+	accounts := make([]*model.Account, 0)
+	hostIDs := make(map[string]bool)
+	for _, acc := range m.accounts {
+		if supervisedStatus(acc.Status) && acc.ServiceURL != "" {
+			accounts = append(accounts, acc)
+			hostIDs[acc.HostID] = true
+		}
+	}
+	m.mutex.RUnlock()
+
+	// 每台主机只查询一次Docker健康状态
+	var unhealthy map[string]map[string]bool
+	if m.dockerHealthEnabled() && len(accounts) > 0 {
+		unhealthy = m.dockerUnhealthyWorkers(hostIDs)
+	}
+
+	for _, acc := range accounts {
+		state := m.supervisorStateFor(acc.ID)
+		m.supervisorMutex.Lock()
+		if state.busy {
+			m.supervisorMutex.Unlock()
+			continue
+		}
+		state.busy = true
+		m.supervisorMutex.Unlock()
+
+		go m.superviseWorker(acc, state, unhealthy[acc.HostID][workerContainerPrefix+acc.ID])
+	}
+}
+
+// supervisedStatus 该状态的账号是否需要自动恢复，主动停止、启动中和已放弃重启的账号除外
+func supervisedStatus(status string) bool {
+	switch status {
+	case "stopped", "stopping", "creating", "starting", "restarting", "crash_looping":
+		return false
+	}
+	return true
+}
+
+// superviseWorker 对单个Worker做健康检查，必要时重启；dockerUnhealthy表示Docker健康检查已判定容器不健康
+func (m *Manager) superviseWorker(acc *model.Account, state *supervisorState, dockerUnhealthy bool) {
+	defer func() {
+		m.supervisorMutex.Lock()
+		state.busy = false
+		m.supervisorMutex.Unlock()
+	}()
+
+	cfg := m.GetConfig().Supervisor
+	var err error
+	if dockerUnhealthy {
+		// HTTP可能仍可访问，以Docker的判定为准
+		err = fmt.Errorf("docker health check reported unhealthy")
+	} else {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		_, err = m.callWorker(ctx, acc, "GET", "/api/status", nil)
+		cancel()
+	}
+
+	now := time.Now()
+	m.supervisorMutex.Lock()
+	if err == nil {
+		state.failures = 0
+		if state.restarts > 0 && now.Sub(state.lastRestart) > supervisorStableWindow {
+			state.restarts = 0
+		}
+		m.supervisorMutex.Unlock()
+		// 健康检查成功说明Worker已恢复，关闭代理熔断器
+		m.RecordWorkerResult(acc.ID, nil)
+		return
+	}
+
+	state.failures++
+	if dockerUnhealthy && state.failures < cfg.FailureThreshold {
+		// Docker已按 WORKER_HEALTH_RETRIES 连续失败后才判定unhealthy，无需再累计
+		state.failures = cfg.FailureThreshold
+	}
+	if state.failures < cfg.FailureThreshold || now.Before(state.nextAttempt) {
+		m.supervisorMutex.Unlock()
+		return
+	}
+	if state.restarts >= cfg.MaxRestarts {
+		restarts := state.restarts
+		m.supervisorMutex.Unlock()
+
+		slog.Error("Worker is crash looping, giving up", "account_id", acc.ID, "restarts", restarts)
+		m.UpdateAccountStatusSafe(acc.ID, "crash_looping", StatusCause{Source: StatusSourceSupervisor, Reason: fmt.Sprintf("gave up after %d restarts: %v", restarts, err)})
+		m.emit(EventWorkerCrashLooping, acc.ID, map[string]interface{}{
+			"restarts":   restarts,
+			"last_error": err.Error(),
+		})
+		return
+	}
+
+	state.restarts++
+	state.lastRestart = now
+	state.nextAttempt = now.Add(m.restartBackoff(state.restarts))
+	attempt := state.restarts
+	m.supervisorMutex.Unlock()
+
+	if m.shuttingDown() {
+		return
+	}
+	m.restartUnhealthyWorker(acc, attempt, err)
+}
+
+// restartUnhealthyWorker 重建Worker并记录重启结果
+func (m *Manager) restartUnhealthyWorker(acc *model.Account, attempt int, cause error) {
+	slog.Warn("Worker failed health checks, restarting", "account_id", acc.ID, "cause", cause, "attempt", attempt, "max_restarts", m.GetConfig().Supervisor.MaxRestarts)
+	m.UpdateAccountStatusSafe(acc.ID, "restarting", StatusCause{Source: StatusSourceSupervisor, Reason: fmt.Sprintf("health check failed: %v", cause)})
+
+	m.mutex.Lock()
+	now := time.Now()
+	acc.RestartCount++
+	acc.LastRestartAt = &now
+	m.db.Model(acc).Updates(map[string]interface{}{
+		"restart_count":   acc.RestartCount,
+		"last_restart_at": acc.LastRestartAt,
+	})
+	m.mutex.Unlock()
+
+	if err := m.spawnWorker(context.Background(), acc, nil); err != nil {
+		slog.Error("Failed to restart worker", "account_id", acc.ID, "error", err)
+		m.UpdateAccountStatusSafe(acc.ID, "error", StatusCause{Source: StatusSourceSupervisor, Reason: fmt.Sprintf("restart failed: %v", err)}.withError(err))
+		m.emit(EventWorkerRestartFailed, acc.ID, map[string]interface{}{
+			"attempt": attempt,
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	m.UpdateAccountStatusSafe(acc.ID, "running", StatusCause{Source: StatusSourceSupervisor, Reason: fmt.Sprintf("restarted (attempt %d)", attempt)})
+	m.emit(EventWorkerRestarted, acc.ID, map[string]interface{}{
+		"attempt": attempt,
+	})
+}
+
+// supervisorStateFor 获取账号的自动恢复状态
+func (m *Manager) supervisorStateFor(accountID string) *supervisorState {
+	m.supervisorMutex.Lock()
+	defer m.supervisorMutex.Unlock()
+
+	state, exists := m.supervised[accountID]
+	if !exists {
+		state = &supervisorState{}
+		m.supervised[accountID] = state
+	}
+	return state
+}
+
+// resetSupervisor 清除账号的自动恢复状态，手动启动、重启或停止账号时调用
+func (m *Manager) resetSupervisor(accountID string) {
+	m.supervisorMutex.Lock()
+	defer m.supervisorMutex.Unlock()
+
+	if state, exists := m.supervised[accountID]; exists && !state.busy {
+		delete(m.supervised, accountID)
+	}
+}
+
+// restartBackoff 第attempt次重启后到下一次允许重启的等待时间（指数退避）
+func (m *Manager) restartBackoff(attempt int) time.Duration {
+	backoff := time.Duration(m.GetConfig().Supervisor.BackoffSeconds) * time.Second
+	maxBackoff := time.Duration(m.GetConfig().Supervisor.MaxBackoff) * time.Second
+	for i := 1; i < attempt && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if maxBackoff > 0 && backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// apiKeyPrefix 租户API Key前缀，便于在日志和配置中识别
+const apiKeyPrefix = "wft_"
+
+// apiKeyLastUsedInterval 最近使用时间的更新间隔，避免每个请求都写数据库
+const apiKeyLastUsedInterval = time.Minute
+
+// tenantColumns 租户列表允许过滤和排序的字段
+var tenantColumns = map[string]string{
+	"id":         "id",
+	"name":       "name",
+	"created_at": "created_at",
+}
+
+// TenantLimitError 租户超出账号数量上限
+type TenantLimitError struct {
+	TenantID string
+	Limit    int
+}
+
+func (e *TenantLimitError) Error() string {
+	return fmt.Sprintf("tenant %s reached its limit of %d workers", e.TenantID, e.Limit)
+}
+
+// CreateTenant 创建租户
+func (m *Manager) CreateTenant(req *model.CreateTenantRequest) (*model.Tenant, error) {
+	id := strings.TrimSpace(req.ID)
+	if id == "" {
+		return nil, fmt.Errorf("tenant id is required")
+	}
+	if req.MaxWorkers < 0 || req.MaxMessagesPerDay < 0 {
+		return nil, fmt.Errorf("tenant limits must not be negative")
+	}
+
+	var count int64
+	m.db.Model(&model.Tenant{}).Where("id = ?", id).Count(&count)
+	if count > 0 {
+		return nil, fmt.Errorf("tenant %s already exists", id)
+	}
+
+	tenant := &model.Tenant{
+		ID:                id,
+		Name:              valueOrDefault(strings.TrimSpace(req.Name), id),
+		MaxWorkers:        req.MaxWorkers,
+		MaxMessagesPerDay: req.MaxMessagesPerDay,
+	}
+	if err := m.db.Create(tenant).Error; err != nil {
+		return nil, fmt.Errorf("failed to create tenant: %v", err)
+	}
+
+	slog.Info("Tenant created", "tenant_id", tenant.ID, "max_workers", tenant.MaxWorkers, "max_messages_per_day", tenant.MaxMessagesPerDay)
+	return m.GetTenant(tenant.ID)
+}
+
+// GetTenant 获取租户及其当前用量
+func (m *Manager) GetTenant(tenantID string) (*model.Tenant, error) {
+	var tenant model.Tenant
+	if err := m.db.Where("id = ?", tenantID).First(&tenant).Error; err != nil {
+		return nil, fmt.Errorf("tenant %s not found", tenantID)
+	}
+	tenant.Usage = m.tenantUsage(tenantID)
+	return &tenant, nil
+}
+
+// ListTenants 分页查询租户
+func (m *Manager) ListTenants(q *model.ListQuery) ([]*model.Tenant, int64, error) {
+	tenants := make([]*model.Tenant, 0)
+	total, err := findWithListQuery(m.db.Model(&model.Tenant{}), q, tenantColumns, "id", &tenants)
+	if err != nil {
+		return nil, 0, err
+	}
+	for _, tenant := range tenants {
+		tenant.Usage = m.tenantUsage(tenant.ID)
+	}
+	return tenants, total, nil
+}
+
+// UpdateTenant 修改租户名称或配额
+func (m *Manager) UpdateTenant(tenantID string, req *model.UpdateTenantRequest) (*model.Tenant, error) {
+	tenant, err := m.GetTenant(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if req.Name != nil {
+		updates["name"] = valueOrDefault(strings.TrimSpace(*req.Name), tenantID)
+	}
+	if req.MaxWorkers != nil {
+		if *req.MaxWorkers < 0 {
+			return nil, fmt.Errorf("max_workers must not be negative")
+		}
+		updates["max_workers"] = *req.MaxWorkers
+	}
+	if req.MaxMessagesPerDay != nil {
+		if *req.MaxMessagesPerDay < 0 {
+			return nil, fmt.Errorf("max_messages_per_day must not be negative")
+		}
+		updates["max_messages_per_day"] = *req.MaxMessagesPerDay
+	}
+	if len(updates) == 0 {
+		return tenant, nil
+	}
+
+	if err := m.db.Model(&model.Tenant{}).Where("id = ?", tenantID).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("failed to update tenant: %v", err)
+	}
+	return m.GetTenant(tenantID)
+}
+
+// CreateTenantAPIKey 为租户生成新的API Key
+func (m *Manager) CreateTenantAPIKey(tenantID string, req *model.CreateAPIKeyRequest) (*model.CreatedAPIKey, error) {
+	if _, err := m.GetTenant(tenantID); err != nil {
+		return nil, err
+	}
+
+	key := apiKeyPrefix + randomHex(24)
+	apiKey := model.TenantAPIKey{
+		ID:       generateID("key"),
+		TenantID: tenantID,
+		Name:     strings.TrimSpace(req.Name),
+		Owner:    strings.TrimSpace(req.Owner),
+		Prefix:   key[:len(apiKeyPrefix)+8],
+		KeyHash:  hashAPIKey(key),
+	}
+	if err := m.db.Create(&apiKey).Error; err != nil {
+		return nil, fmt.Errorf("failed to create api key: %v", err)
+	}
+
+	slog.Info("API key created", "key_id", apiKey.ID, "tenant_id", tenantID)
+	return &model.CreatedAPIKey{TenantAPIKey: apiKey, Key: key}, nil
+}
+
+// ListTenantAPIKeys 列出租户的API Key（不含明文）
+func (m *Manager) ListTenantAPIKeys(tenantID string) ([]*model.TenantAPIKey, error) {
+	if _, err := m.GetTenant(tenantID); err != nil {
+		return nil, err
+	}
+
+	keys := make([]*model.TenantAPIKey, 0)
+	if err := m.db.Where("tenant_id = ?", tenantID).Order("created_at").Find(&keys).Error; err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %v", err)
+	}
+	return keys, nil
+}
+
+// RevokeTenantAPIKey 吊销租户的API Key
+func (m *Manager) RevokeTenantAPIKey(tenantID, keyID string) error {
+	now := time.Now()
+	res := m.db.Model(&model.TenantAPIKey{}).
+		Where("id = ? AND tenant_id = ? AND revoked_at IS NULL", keyID, tenantID).
+		Update("revoked_at", &now)
+	if res.Error != nil {
+		return fmt.Errorf("failed to revoke api key: %v", res.Error)
+	}
+	if res.RowsAffected == 0 {
+		return fmt.Errorf("api key %s not found", keyID)
+	}
+
+	slog.Info("API key revoked", "key_id", keyID, "tenant_id", tenantID)
+	return nil
+}
+
+// AuthenticateAPIKey 校验API Key并返回所属租户、Key ID和绑定的负责人
+func (m *Manager) AuthenticateAPIKey(key string) (string, string, string, error) {
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return "", "", "", fmt.Errorf("invalid api key")
+	}
+
+	var apiKey model.TenantAPIKey
+	if err := m.db.Where("key_hash = ? AND revoked_at IS NULL", hashAPIKey(key)).First(&apiKey).Error; err != nil {
+		return "", "", "", fmt.Errorf("invalid api key")
+	}
+
+	// 最近使用时间精确到分钟即可，距上次记录不足一分钟时不写入
+	if now := time.Now(); apiKey.LastUsedAt == nil || now.Sub(*apiKey.LastUsedAt) >= apiKeyLastUsedInterval {
+		m.db.Model(&apiKey).UpdateColumn("last_used_at", &now)
+	}
+	return apiKey.TenantID, apiKey.ID, apiKey.Owner, nil
+}
+
+// TenantAccountIDs 获取租户下的所有账号ID
+func (m *Manager) TenantAccountIDs(tenantID string) []string {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	ids := make([]string, 0)
+	for _, account := range m.accounts {
+		if account.TenantID == tenantID {
+			ids = append(ids, account.ID)
+		}
+	}
+	return ids
+}
+
+// AccountInTenant 账号是否属于指定租户
+func (m *Manager) AccountInTenant(accountID, tenantID string) bool {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	account, exists := m.accounts[accountID]
+	return exists && account.TenantID == tenantID
+}
+
+// checkTenantWorkerLimitLocked 检查租户是否还能创建账号，调用方需持有mutex
+func (m *Manager) checkTenantWorkerLimitLocked(tenantID string) error {
+	var tenant model.Tenant
+	if err := m.db.Where("id = ?", tenantID).First(&tenant).Error; err != nil {
+		return fmt.Errorf("tenant %s not found", tenantID)
+	}
+	if tenant.MaxWorkers <= 0 {
+		return nil
+	}
+
+	workers := 0
+	for _, account := range m.accounts {
+		if account.TenantID == tenantID {
+			workers++
+		}
+	}
+	if workers >= tenant.MaxWorkers {
+		return &TenantLimitError{TenantID: tenantID, Limit: tenant.MaxWorkers}
+	}
+	return nil
+}
+
+// tenantDailyLimit 获取租户每日发送上限，0表示不限制
+func (m *Manager) tenantDailyLimit(tenantID string) int {
+	var tenant model.Tenant
+	if err := m.db.Select("max_messages_per_day").Where("id = ?", tenantID).First(&tenant).Error; err != nil {
+		return 0
+	}
+	return tenant.MaxMessagesPerDay
+}
+
+// tenantUsage 统计租户的账号数和当日发送数
+func (m *Manager) tenantUsage(tenantID string) *model.TenantUsage {
+	usage := &model.TenantUsage{Workers: len(m.TenantAccountIDs(tenantID))}
+
+	m.quotaMutex.Lock()
+	usage.MessagesToday = m.tenantWindowLocked(tenantID, time.Now()).daily
+	m.quotaMutex.Unlock()
+	return usage
+}
+
+// tenantWindowLocked 获取租户当日发送计数，跨天或首次使用时从数据库恢复，调用方需持有quotaMutex
+func (m *Manager) tenantWindowLocked(tenantID string, now time.Time) *sendWindow {
+	w, exists := m.tenantWindows[tenantID]
+	if !exists {
+		w = &sendWindow{}
+		m.tenantWindows[tenantID] = w
+	}
+
+	if day := now.Format("2006-01-02"); w.day != day {
+		var count int64
+		m.db.Model(&model.Message{}).
+			Joins("JOIN accounts ON accounts.id = messages.account_id").
+			Where("accounts.tenant_id = ? AND messages.direction = ? AND messages.created_at >= ?", tenantID, "outbound", startOfDay(now)).
+			Count(&count)
+		w.day = day
+		w.daily = int(count)
+	}
+	return w
+}
+
+// hashAPIKey 计算API Key的SHA-256哈希
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
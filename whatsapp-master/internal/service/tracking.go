@@ -0,0 +1,172 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// linkPattern 匹配消息中的 http/https 链接
+var linkPattern = regexp.MustCompile(`https?://[^\s<>"']+`)
+
+// shouldTrackLinks 判断本次发送是否需要改写链接
+func (m *Manager) shouldTrackLinks(override *bool) bool {
+	if override != nil {
+		return *override
+	}
+	return m.GetConfig().Tracking.Enabled
+}
+
+// rewriteLinks 将消息中的链接替换为跟踪跳转地址
+func (m *Manager) rewriteLinks(msg *model.Message, body string) (string, []*model.TrackedLink) {
+	links := make([]*model.TrackedLink, 0)
+	baseURL := strings.TrimRight(m.GetConfig().Tracking.BaseURL, "/")
+
+	rewritten := linkPattern.ReplaceAllStringFunc(body, func(url string) string {
+		// 去掉句尾标点，避免被当作链接的一部分
+		trimmed := strings.TrimRight(url, ".,;:!?)")
+		suffix := url[len(trimmed):]
+
+		link := &model.TrackedLink{
+			ID:        randomHex(5),
+			MessageID: msg.ID,
+			AccountID: msg.AccountID,
+			Campaign:  msg.Campaign,
+			Contact:   msg.Contact,
+			URL:       trimmed,
+			CreatedAt: time.Now(),
+		}
+		if err := m.db.Create(link).Error; err != nil {
+			slog.Warn("Failed to create tracked link", "message_id", msg.ID, "error", err)
+			return url
+		}
+		links = append(links, link)
+		return fmt.Sprintf("%s/r/%s%s", baseURL, link.ID, suffix)
+	})
+
+	return rewritten, links
+}
+
+// discardTrackedLinks 删除发送失败消息的跟踪链接
+func (m *Manager) discardTrackedLinks(links []*model.TrackedLink) {
+	for _, link := range links {
+		m.db.Delete(link)
+	}
+}
+
+// RecordClick 记录一次点击并返回原始链接
+func (m *Manager) RecordClick(code, ip, userAgent string) (string, error) {
+	var link model.TrackedLink
+	if err := m.db.Where("id = ?", code).First(&link).Error; err != nil {
+		return "", fmt.Errorf("link %s not found", code)
+	}
+
+	now := time.Now()
+	updates := map[string]interface{}{
+		"clicks":          gorm.Expr("clicks + 1"),
+		"last_clicked_at": now,
+	}
+	if link.FirstClickedAt == nil {
+		updates["first_clicked_at"] = now
+	}
+	m.db.Model(&link).Updates(updates)
+
+	m.db.Create(&model.LinkClick{
+		LinkID:    link.ID,
+		IP:        ip,
+		UserAgent: userAgent,
+		ClickedAt: now,
+	})
+
+	return link.URL, nil
+}
+
+// GetClickStats 按活动和账号汇总点击统计，可按活动或账号过滤
+func (m *Manager) GetClickStats(campaign, accountID string) (*model.ClickStatsReport, error) {
+	var links []*model.TrackedLink
+	db := m.db.Model(&model.TrackedLink{})
+	if campaign != "" {
+		db = db.Where("campaign = ?", campaign)
+	}
+	if accountID != "" {
+		db = db.Where("account_id = ?", accountID)
+	}
+	if err := db.Find(&links).Error; err != nil {
+		return nil, err
+	}
+
+	report := &model.ClickStatsReport{
+		ByCampaign: make(map[string]*model.ClickStats),
+		ByAccount:  make(map[string]*model.ClickStats),
+	}
+
+	// 以消息为单位统计点击率
+	type messageClicks struct {
+		campaign  string
+		accountID string
+		links     int
+		clicks    int
+	}
+	messages := make(map[string]*messageClicks)
+	for _, link := range links {
+		mc, exists := messages[link.MessageID]
+		if !exists {
+			mc = &messageClicks{campaign: valueOrDefault(link.Campaign, "none"), accountID: link.AccountID}
+			messages[link.MessageID] = mc
+		}
+		mc.links++
+		mc.clicks += link.Clicks
+	}
+
+	report.Total = &model.ClickStats{}
+	for _, mc := range messages {
+		for _, stats := range []*model.ClickStats{
+			report.Total,
+			getClickBucket(report.ByCampaign, mc.campaign),
+			getClickBucket(report.ByAccount, mc.accountID),
+		} {
+			stats.Messages++
+			stats.Links += mc.links
+			stats.Clicks += mc.clicks
+			if mc.clicks > 0 {
+				stats.ClickedMessages++
+			}
+		}
+	}
+
+	for _, stats := range append([]*model.ClickStats{report.Total}, collectClickStats(report)...) {
+		if stats.Messages > 0 {
+			stats.ClickThroughRate = float64(stats.ClickedMessages) / float64(stats.Messages)
+		}
+	}
+
+	return report, nil
+}
+
+// getClickBucket 获取或创建分组统计
+func getClickBucket(buckets map[string]*model.ClickStats, key string) *model.ClickStats {
+	stats, exists := buckets[key]
+	if !exists {
+		stats = &model.ClickStats{}
+		buckets[key] = stats
+	}
+	return stats
+}
+
+// collectClickStats 返回所有分组统计
+func collectClickStats(report *model.ClickStatsReport) []*model.ClickStats {
+	all := make([]*model.ClickStats, 0, len(report.ByCampaign)+len(report.ByAccount))
+	for _, stats := range report.ByCampaign {
+		all = append(all, stats)
+	}
+	for _, stats := range report.ByAccount {
+		all = append(all, stats)
+	}
+	return all
+}
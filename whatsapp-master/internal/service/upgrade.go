@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// upgradeStageRollingBack 滚动升级回滚时的任务阶段
+const upgradeStageRollingBack = "rolling_back"
+
+// UpgradeWorkers 在后台任务中拉取新镜像并按批次滚动重建运行中的Worker，
+// 每批启动后等待一段时间再做健康检查，有Worker失败时停止升级并将已升级的Worker回滚到原镜像
+func (m *Manager) UpgradeWorkers(req *model.UpgradeWorkersRequest) (*model.Job, error) {
+	batchSize := req.BatchSize
+	if batchSize <= 0 {
+		batchSize = max(m.GetConfig().Upgrade.BatchSize, 1)
+	}
+	settle := req.SettleSeconds
+	if settle <= 0 {
+		settle = m.GetConfig().Upgrade.SettleSeconds
+	}
+
+	if !m.upgradeRun.TryLock() {
+		return nil, fmt.Errorf("a worker upgrade is already running")
+	}
+
+	m.mutex.RLock()
+	previous := m.GetConfig().Worker.Image
+	accounts := make([]*model.Account, 0, len(m.accounts))
+	for _, acc := range m.accounts {
+		// 已停止的账号下次启动时直接使用新镜像
+		if acc.Status != "stopped" {
+			accounts = append(accounts, acc)
+		}
+	}
+	m.mutex.RUnlock()
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].ID < accounts[j].ID })
+
+	slog.Info("Upgrading workers", "image", req.Image, "previous_image", previous, "count", len(accounts), "batch_size", batchSize)
+	job, err := m.startJob(JobUpgradeWorkers, fmt.Sprintf("upgrade %d workers to %s", len(accounts), req.Image), len(accounts), func(r *jobRun) error {
+		defer m.upgradeRun.Unlock()
+		return m.rollWorkers(r, previous, req.Image, accounts, batchSize, time.Duration(settle)*time.Second)
+	})
+	if err != nil {
+		m.upgradeRun.Unlock()
+		return nil, err
+	}
+	return job, nil
+}
+
+// rollWorkers 执行滚动升级，取消或关闭时在当前批次完成后停止
+func (m *Manager) rollWorkers(r *jobRun, previous, image string, accounts []*model.Account, batchSize int, settle time.Duration) error {
+	result := &model.UpgradeWorkersResult{PreviousImage: previous, Image: image, Upgraded: []string{}}
+	defer r.SetResult(result)
+
+	r.SetStage(SpawnStagePullingImage)
+	for _, hostID := range m.workerHosts(accounts) {
+		if err := m.pullImage(r.Context(), hostID, image); err != nil {
+			return err
+		}
+	}
+	m.setWorkerImage(image)
+
+	batches := (len(accounts) + batchSize - 1) / batchSize
+	var upgraded []*model.Account
+	for start := 0; start < len(accounts); start += batchSize {
+		if r.Cancelled() || m.shuttingDown() {
+			return nil
+		}
+
+		batch := accounts[start:min(start+batchSize, len(accounts))]
+		result.Batches++
+		r.SetStage(fmt.Sprintf("batch %d/%d", result.Batches, batches))
+		slog.Info("Upgrading worker batch", "batch", result.Batches, "batches", batches, "size", len(batch), "image", image)
+
+		failed := m.restartBatch(batch, "upgrade worker image to "+image)
+		if len(failed) == 0 {
+			select {
+			case <-time.After(settle):
+			case <-r.Context().Done():
+			case <-m.stopCh:
+			}
+			failed = m.unhealthyWorkers(batch)
+		}
+		upgraded = append(upgraded, batch...)
+
+		if len(failed) > 0 {
+			slog.Error("Worker upgrade failed, rolling back", "image", image, "previous_image", previous, "failed", failed)
+			result.Failed = failed
+			r.SetStage(upgradeStageRollingBack)
+			m.setWorkerImage(previous)
+			result.RollbackFailed = m.restartBatch(upgraded, "roll back worker image to "+previous)
+			result.RolledBack = true
+			result.Upgraded = []string{}
+			return fmt.Errorf("%d workers failed on %s, rolled back %d workers to %s", len(failed), image, len(upgraded), previous)
+		}
+
+		for _, acc := range batch {
+			result.Upgraded = append(result.Upgraded, acc.ID)
+		}
+		r.Advance(len(batch))
+	}
+
+	slog.Info("Worker upgrade finished", "image", image, "upgraded", len(result.Upgraded))
+	return nil
+}
+
+// setWorkerImage 切换新建Worker使用的镜像并保存，重启后继续使用
+func (m *Manager) setWorkerImage(image string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.setConfigValueLocked(configSettings["worker.image"], image)
+	if err := m.saveConfigOverridesLocked(map[string]interface{}{"worker.image": image}); err != nil {
+		slog.Warn("Failed to save worker image", "image", image, "error", err)
+	}
+}
+
+// restartBatch 并发重建一批Worker，返回失败的账号
+func (m *Manager) restartBatch(accounts []*model.Account, reason string) []string {
+	var wg sync.WaitGroup
+	var failedMutex sync.Mutex
+	failed := make([]string, 0)
+
+	for _, acc := range accounts {
+		wg.Add(1)
+		go func(account *model.Account) {
+			defer wg.Done()
+			if err := m.restartAccountWorker(account, reason); err != nil {
+				slog.Error("Failed to restart worker", "account_id", account.ID, "error", err)
+				failedMutex.Lock()
+				failed = append(failed, account.ID)
+				failedMutex.Unlock()
+			}
+		}(acc)
+	}
+	wg.Wait()
+
+	sort.Strings(failed)
+	return failed
+}
+
+// workerHosts 账号Worker所在的主机，没有账号时为本机
+func (m *Manager) workerHosts(accounts []*model.Account) []string {
+	m.mutex.RLock()
+	seen := make(map[string]bool)
+	for _, acc := range accounts {
+		seen[valueOrDefault(acc.HostID, model.LocalHostID)] = true
+	}
+	m.mutex.RUnlock()
+	if len(seen) == 0 {
+		return []string{model.LocalHostID}
+	}
+
+	hosts := make([]string, 0, len(seen))
+	for hostID := range seen {
+		hosts = append(hosts, hostID)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// unhealthyWorkers 检查一批Worker的状态接口，返回不健康的账号
+func (m *Manager) unhealthyWorkers(accounts []*model.Account) []string {
+	unhealthy := make([]string, 0)
+	for _, acc := range accounts {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		_, err := m.callWorker(ctx, acc, "GET", "/api/status", nil)
+		cancel()
+		if err != nil {
+			slog.Warn("Upgraded worker is unhealthy", "account_id", acc.ID, "error", err)
+			unhealthy = append(unhealthy, acc.ID)
+		}
+	}
+	return unhealthy
+}
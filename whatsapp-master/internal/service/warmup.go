@@ -0,0 +1,155 @@
+package service
+
+import (
+	"fmt"
+	"log/slog"
+	"math"
+	"time"
+
+	"whatsapp-aggregator/internal/config"
+	"whatsapp-aggregator/internal/model"
+)
+
+// warmupPlanLocked 账号使用的预热方案，未开启预热或账号不预热时阶段为空，调用方需持有mutex
+func (m *Manager) warmupPlanLocked(account *model.Account) (string, []config.WarmupStep) {
+	name := account.WarmupProfile
+	if name == "" {
+		name = m.GetConfig().Warmup.DefaultProfile
+	}
+	if !m.GetConfig().Warmup.Enabled || name == model.WarmupOff {
+		return name, nil
+	}
+	return name, m.GetConfig().Warmup.Profiles[name]
+}
+
+// warmupStart 预热起点，未重新开始过时为账号创建时间
+func warmupStart(account *model.Account) time.Time {
+	if account.WarmupStartedAt != nil {
+		return *account.WarmupStartedAt
+	}
+	return account.CreatedAt
+}
+
+// warmupDay 按本地日期计算的预热天数，起点当天为第0天
+func warmupDay(start, now time.Time) int {
+	days := startOfDay(now).Sub(startOfDay(start)).Hours() / 24
+	return max(int(math.Round(days)), 0)
+}
+
+// currentWarmupStep 当前所处阶段的下标，阶段按起始天数升序
+func currentWarmupStep(steps []config.WarmupStep, day int) int {
+	current := 0
+	for i, step := range steps {
+		if step.FromDay <= day {
+			current = i
+		}
+	}
+	return current
+}
+
+// warmupLimit 账号当日的预热发送上限，0表示不限制
+func (m *Manager) warmupLimit(accountID string, now time.Time) int {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	account, exists := m.accounts[accountID]
+	if !exists {
+		return 0
+	}
+	_, steps := m.warmupPlanLocked(account)
+	if len(steps) == 0 {
+		return 0
+	}
+	return steps[currentWarmupStep(steps, warmupDay(warmupStart(account), now))].PerDay
+}
+
+// GetWarmupStatus 获取账号的预热阶段、当日额度和用量
+func (m *Manager) GetWarmupStatus(accountID string) (*model.WarmupStatus, error) {
+	now := time.Now()
+
+	m.mutex.RLock()
+	account, exists := m.accounts[accountID]
+	if !exists {
+		m.mutex.RUnlock()
+		return nil, fmt.Errorf("account %s not found", accountID)
+	}
+	profile, steps := m.warmupPlanLocked(account)
+	start := warmupStart(account)
+	m.mutex.RUnlock()
+
+	status := &model.WarmupStatus{
+		AccountID: accountID,
+		Enabled:   len(steps) > 0,
+		Profile:   profile,
+		StartedAt: start,
+		AgeDays:   warmupDay(start, now),
+		Completed: true,
+	}
+
+	m.quotaMutex.Lock()
+	status.SentToday = m.sendWindowLocked(accountID, now).daily
+	m.quotaMutex.Unlock()
+
+	if len(steps) == 0 {
+		return status, nil
+	}
+	for _, step := range steps {
+		status.Steps = append(status.Steps, model.WarmupStep{FromDay: step.FromDay, PerDay: step.PerDay})
+	}
+
+	current := currentWarmupStep(steps, status.AgeDays)
+	status.DailyLimit = steps[current].PerDay
+	if status.DailyLimit > 0 {
+		status.Completed = false
+		status.Remaining = max(status.DailyLimit-status.SentToday, 0)
+	}
+	if current+1 < len(steps) {
+		next := steps[current+1]
+		at := startOfDay(start).AddDate(0, 0, next.FromDay)
+		status.NextStepAt = &at
+		status.NextLimit = &next.PerDay
+	}
+	return status, nil
+}
+
+// SetAccountWarmup 修改账号的预热方案，或从今天起重新开始预热
+func (m *Manager) SetAccountWarmup(accountID string, req *model.SetWarmupRequest) (*model.WarmupStatus, error) {
+	if req.Profile != nil && *req.Profile != "" && *req.Profile != model.WarmupOff {
+		if _, exists := m.GetConfig().Warmup.Profiles[*req.Profile]; !exists {
+			return nil, fmt.Errorf("unknown warmup profile %s", *req.Profile)
+		}
+	}
+
+	m.mutex.Lock()
+	account, exists := m.accounts[accountID]
+	if !exists {
+		m.mutex.Unlock()
+		return nil, fmt.Errorf("account %s not found", accountID)
+	}
+
+	updates := make(map[string]interface{})
+	if req.Profile != nil {
+		updates["warmup_profile"] = *req.Profile
+	}
+	now := time.Now()
+	if req.Restart {
+		updates["warmup_started_at"] = &now
+	}
+	if len(updates) > 0 {
+		if err := m.db.Model(account).Updates(updates).Error; err != nil {
+			m.mutex.Unlock()
+			return nil, fmt.Errorf("failed to update account warmup: %v", err)
+		}
+		if req.Profile != nil {
+			account.WarmupProfile = *req.Profile
+		}
+		if req.Restart {
+			account.WarmupStartedAt = &now
+		}
+	}
+	profile := account.WarmupProfile
+	m.mutex.Unlock()
+
+	slog.Info("Account warmup updated", "account_id", accountID, "profile", profile, "restarted", req.Restart)
+	return m.GetWarmupStatus(accountID)
+}
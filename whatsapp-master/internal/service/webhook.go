@@ -0,0 +1,307 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// WebhookSignatureHeader 事件请求体的HMAC-SHA256签名，格式为 sha256=<hex>
+const WebhookSignatureHeader = "X-Fleet-Signature"
+
+// ErrWebhookNotFound Webhook不存在或不属于调用方租户
+var ErrWebhookNotFound = errors.New("webhook not found")
+
+// loadWebhooks 从数据库加载已注册的Webhook，替换内存中的列表
+func (m *Manager) loadWebhooks() error {
+	var webhooks []*model.Webhook
+	if err := m.db.Find(&webhooks).Error; err != nil {
+		return err
+	}
+
+	loaded := make(map[string]*model.Webhook, len(webhooks))
+	for _, webhook := range webhooks {
+		loaded[webhook.ID] = webhook
+	}
+	m.webhookMutex.Lock()
+	m.webhooks = loaded
+	m.webhookMutex.Unlock()
+	return nil
+}
+
+// CreateWebhook 注册Webhook，tenantID不为空时只接收该租户账号的事件
+func (m *Manager) CreateWebhook(req *model.CreateWebhookRequest, tenantID string) (*model.CreatedWebhook, error) {
+	u, err := url.Parse(req.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("invalid webhook url %q: must be an http(s) URL", req.URL)
+	}
+	events := normalizeWebhookEvents(req.Events)
+
+	webhook := &model.Webhook{
+		ID:           generateID("wh"),
+		URL:          req.URL,
+		Events:       events,
+		IncludeMedia: req.IncludeMedia,
+		Secret:       model.Secret(valueOrDefault(req.Secret, randomHex(24))),
+		TenantID:     tenantID,
+	}
+	if req.Filter != nil {
+		if webhook.Filter, err = normalizeWebhookFilter(req.Filter); err != nil {
+			return nil, err
+		}
+	}
+	if req.Retry != nil {
+		if err := validateWebhookRetry(req.Retry); err != nil {
+			return nil, err
+		}
+		webhook.Retry = *req.Retry
+	}
+	if err := m.db.Create(webhook).Error; err != nil {
+		return nil, fmt.Errorf("failed to create webhook: %v", err)
+	}
+
+	m.webhookMutex.Lock()
+	m.webhooks[webhook.ID] = webhook
+	m.webhookMutex.Unlock()
+
+	slog.Info("Webhook registered", "webhook_id", webhook.ID, "url", webhook.URL, "events", strings.Join(events, ","), "tenant_id", tenantID)
+	return &model.CreatedWebhook{Webhook: *webhook, Secret: string(webhook.Secret)}, nil
+}
+
+// normalizeWebhookEvents 去掉空白的事件类型
+func normalizeWebhookEvents(eventTypes []string) model.StringList {
+	events := make(model.StringList, 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			events = append(events, eventType)
+		}
+	}
+	return events
+}
+
+// UpdateWebhook 修改Webhook订阅的事件、过滤条件、媒体选项和重试策略，scoped为true时只能修改该租户的Webhook。
+// 投递中的事件仍使用修改前的设置
+func (m *Manager) UpdateWebhook(id string, req *model.UpdateWebhookRequest, tenantID string, scoped bool) (*model.Webhook, error) {
+	updates := make(map[string]interface{})
+	var filter model.WebhookFilter
+	if req.Filter != nil {
+		var err error
+		if filter, err = normalizeWebhookFilter(req.Filter); err != nil {
+			return nil, err
+		}
+	}
+	if req.Retry != nil {
+		if err := validateWebhookRetry(req.Retry); err != nil {
+			return nil, err
+		}
+	}
+
+	m.webhookMutex.Lock()
+	defer m.webhookMutex.Unlock()
+
+	current, exists := m.webhooks[id]
+	if !exists || (scoped && current.TenantID != tenantID) {
+		return nil, ErrWebhookNotFound
+	}
+	// 复制后替换，正在投递的事件持有的旧对象不受影响
+	webhook := *current
+	if req.Events != nil {
+		webhook.Events = normalizeWebhookEvents(*req.Events)
+		updates["events"] = webhook.Events
+	}
+	if req.Filter != nil {
+		webhook.Filter = filter
+		updates["filter_account_ids"] = filter.AccountIDs
+		updates["filter_directions"] = filter.Directions
+		updates["filter_contacts"] = filter.Contacts
+		updates["filter_keywords"] = filter.Keywords
+	}
+	if req.IncludeMedia != nil {
+		webhook.IncludeMedia = *req.IncludeMedia
+		updates["include_media"] = webhook.IncludeMedia
+	}
+	if req.Retry != nil {
+		webhook.Retry = *req.Retry
+		updates["retry_max_attempts"] = webhook.Retry.MaxAttempts
+		updates["retry_backoff_ms"] = webhook.Retry.BackoffMs
+		updates["retry_max_backoff_ms"] = webhook.Retry.MaxBackoffMs
+	}
+	if len(updates) == 0 {
+		return current, nil
+	}
+
+	if err := m.db.Model(&webhook).Updates(updates).Error; err != nil {
+		return nil, fmt.Errorf("failed to update webhook: %v", err)
+	}
+	m.webhooks[id] = &webhook
+
+	slog.Info("Webhook updated", "webhook_id", id, "fields", sortedKeys(updates))
+	return &webhook, nil
+}
+
+// ListWebhooks 列出Webhook，scoped为true时只返回该租户的Webhook
+func (m *Manager) ListWebhooks(tenantID string, scoped bool) []*model.Webhook {
+	m.webhookMutex.RLock()
+	defer m.webhookMutex.RUnlock()
+
+	webhooks := make([]*model.Webhook, 0, len(m.webhooks))
+	for _, webhook := range m.webhooks {
+		if !scoped || webhook.TenantID == tenantID {
+			webhooks = append(webhooks, webhook)
+		}
+	}
+	return webhooks
+}
+
+// DeleteWebhook 删除Webhook，scoped为true时只能删除该租户的Webhook
+func (m *Manager) DeleteWebhook(id, tenantID string, scoped bool) error {
+	m.webhookMutex.Lock()
+	defer m.webhookMutex.Unlock()
+
+	webhook, exists := m.webhooks[id]
+	if !exists || (scoped && webhook.TenantID != tenantID) {
+		return ErrWebhookNotFound
+	}
+	if err := m.db.Delete(webhook).Error; err != nil {
+		return fmt.Errorf("failed to delete webhook: %v", err)
+	}
+	delete(m.webhooks, id)
+
+	slog.Info("Webhook deleted", "webhook_id", id)
+	return nil
+}
+
+// StartWebhookDispatcher 订阅事件总线，将事件投递到订阅了该事件的Webhook
+func (m *Manager) StartWebhookDispatcher() {
+	events, unsubscribe := m.events.Subscribe(256)
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer unsubscribe()
+		for {
+			select {
+			case <-m.stopCh:
+				return
+			case event, ok := <-events:
+				if !ok {
+					return
+				}
+				m.dispatchWebhooks(event)
+			}
+		}
+	}()
+}
+
+// dispatchWebhooks 找出订阅了事件且满足过滤条件的Webhook并异步投递，租户Webhook只接收本租户账号的事件
+func (m *Manager) dispatchWebhooks(event *model.Event) {
+	m.webhookMutex.RLock()
+	targets := make([]*model.Webhook, 0)
+	for _, webhook := range m.webhooks {
+		if webhookSubscribed(webhook, event.Type) && webhookMatches(&webhook.Filter, event) {
+			targets = append(targets, webhook)
+		}
+	}
+	m.webhookMutex.RUnlock()
+	if len(targets) == 0 {
+		return
+	}
+
+	// 同一事件按是否附带媒体链接最多编码两次
+	bodies := make(map[bool][]byte, 2)
+	for _, webhook := range targets {
+		if webhook.TenantID != "" && !m.AccountInTenant(event.AccountID, webhook.TenantID) {
+			continue
+		}
+		body, encoded := bodies[webhook.IncludeMedia]
+		if !encoded {
+			var err error
+			if body, err = m.webhookEventBody(event, webhook.IncludeMedia); err != nil {
+				slog.Error("Failed to encode webhook event", "event", event.Type, "error", err)
+				return
+			}
+			bodies[webhook.IncludeMedia] = body
+		}
+		go m.deliverWebhook(webhook, event.Type, body)
+	}
+}
+
+// webhookSubscribed Webhook是否订阅了事件类型，未指定事件时订阅所有事件
+func webhookSubscribed(webhook *model.Webhook, eventType string) bool {
+	if len(webhook.Events) == 0 {
+		return true
+	}
+	for _, subscribed := range webhook.Events {
+		if subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// deliverWebhook 投递事件，连接失败或返回5xx时按Webhook的重试策略指数退避重试
+func (m *Manager) deliverWebhook(webhook *model.Webhook, eventType string, body []byte) {
+	cfg := m.GetConfig().Webhook
+	attempts := max(cfg.MaxAttempts, 1)
+	if webhook.Retry.MaxAttempts > 0 {
+		attempts = webhook.Retry.MaxAttempts
+	}
+	mac := hmac.New(sha256.New, []byte(webhook.Secret))
+	mac.Write(body)
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	var lastErr error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-time.After(webhookBackoff(webhook.Retry, attempt)):
+			case <-m.stopCh:
+				return
+			}
+		}
+
+		retry, err := postWebhook(webhook.URL, signature, eventType, body, time.Duration(cfg.Timeout)*time.Second)
+		if err == nil {
+			return
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+	}
+	slog.Warn("Failed to deliver webhook", "webhook_id", webhook.ID, "event", eventType, "error", lastErr)
+}
+
+// postWebhook 发送一次Webhook请求，返回是否值得重试
+func postWebhook(target, signature, eventType string, body []byte, timeout time.Duration) (bool, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Fleet-Event", eventType)
+	req.Header.Set(WebhookSignatureHeader, signature)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return resp.StatusCode >= 500, fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return false, nil
+}
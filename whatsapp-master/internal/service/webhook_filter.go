@@ -0,0 +1,156 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// Webhook重试策略的取值上限
+const (
+	maxWebhookAttempts      = 20
+	maxWebhookBackoffMs     = 3600000
+	defaultWebhookBackoffMs = 1000
+)
+
+// normalizeWebhookFilter 去掉空白和重复的条件并校验方向和联系人模式
+func normalizeWebhookFilter(filter *model.WebhookFilter) (model.WebhookFilter, error) {
+	normalized := model.WebhookFilter{
+		AccountIDs: normalizeTags(filter.AccountIDs),
+		Contacts:   normalizeTags(filter.Contacts),
+		Keywords:   normalizeTags(filter.Keywords),
+	}
+	directions := make([]string, 0, len(filter.Directions))
+	for _, direction := range filter.Directions {
+		direction = strings.ToLower(strings.TrimSpace(direction))
+		if direction != "inbound" && direction != "outbound" {
+			return normalized, fmt.Errorf("invalid filter direction %q: must be inbound or outbound", direction)
+		}
+		directions = append(directions, direction)
+	}
+	normalized.Directions = normalizeTags(directions)
+	for _, pattern := range normalized.Contacts {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return normalized, fmt.Errorf("invalid filter contact pattern %q: %v", pattern, err)
+		}
+	}
+	return normalized, nil
+}
+
+// validateWebhookRetry 校验重试策略的取值范围
+func validateWebhookRetry(retry *model.WebhookRetryPolicy) error {
+	if retry.MaxAttempts < 0 || retry.MaxAttempts > maxWebhookAttempts {
+		return fmt.Errorf("invalid retry max_attempts %d: must be between 0 and %d", retry.MaxAttempts, maxWebhookAttempts)
+	}
+	if retry.BackoffMs < 0 || retry.BackoffMs > maxWebhookBackoffMs {
+		return fmt.Errorf("invalid retry backoff_ms %d: must be between 0 and %d", retry.BackoffMs, maxWebhookBackoffMs)
+	}
+	if retry.MaxBackoffMs < 0 || retry.MaxBackoffMs > maxWebhookBackoffMs {
+		return fmt.Errorf("invalid retry max_backoff_ms %d: must be between 0 and %d", retry.MaxBackoffMs, maxWebhookBackoffMs)
+	}
+	return nil
+}
+
+// webhookEventMessage 事件中可用于过滤的消息字段
+type webhookEventMessage struct {
+	direction string
+	contact   string
+	body      *string // 回执等事件不带消息内容，为nil时不按关键词过滤
+}
+
+// eventMessage 取出事件携带的消息字段，非消息事件返回nil
+func eventMessage(event *model.Event) *webhookEventMessage {
+	switch data := event.Data.(type) {
+	case *model.Message:
+		return &webhookEventMessage{direction: data.Direction, contact: data.Contact, body: &data.Body}
+	case *model.MessageStatus:
+		return &webhookEventMessage{direction: "outbound", contact: data.Contact}
+	}
+	return nil
+}
+
+// webhookMatches 事件是否满足Webhook的过滤条件
+func webhookMatches(filter *model.WebhookFilter, event *model.Event) bool {
+	if len(filter.AccountIDs) > 0 && !slices.Contains(filter.AccountIDs, event.AccountID) {
+		return false
+	}
+	msg := eventMessage(event)
+	if msg == nil {
+		return true
+	}
+	if len(filter.Directions) > 0 && !slices.Contains(filter.Directions, msg.direction) {
+		return false
+	}
+	if len(filter.Contacts) > 0 && !contactMatches(filter.Contacts, msg.contact) {
+		return false
+	}
+	if len(filter.Keywords) > 0 && msg.body != nil && !containsKeyword(*msg.body, filter.Keywords) {
+		return false
+	}
+	return true
+}
+
+// contactMatches 联系人是否匹配任一模式，模式不含 @ 时与去掉 @c.us 等后缀的号码比较
+func contactMatches(patterns []string, contact string) bool {
+	number, _, _ := strings.Cut(contact, "@")
+	for _, pattern := range patterns {
+		target := contact
+		if !strings.Contains(pattern, "@") {
+			target = number
+		}
+		if matched, _ := path.Match(pattern, target); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// containsKeyword 文本是否包含任一关键词（不区分大小写）
+func containsKeyword(text string, keywords []string) bool {
+	text = strings.ToLower(text)
+	for _, keyword := range keywords {
+		if strings.Contains(text, strings.ToLower(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookEventBody 编码投递给Webhook的事件，include_media 时消息事件附带签名的媒体链接
+func (m *Manager) webhookEventBody(event *model.Event, includeMedia bool) ([]byte, error) {
+	msg, ok := event.Data.(*model.Message)
+	if !includeMedia || !ok || msg.MediaPath == "" {
+		return json.Marshal(event)
+	}
+
+	expires := time.Now().Add(time.Duration(m.GetConfig().Media.URLTTLMinutes) * time.Minute)
+	data := &model.WebhookMessage{
+		Message:        msg,
+		MediaURL:       m.signedMediaURL(msg.ID, MediaVariantOriginal, expires),
+		MediaExpiresAt: &expires,
+	}
+	if msg.Type == "image" {
+		data.ThumbnailURL = m.signedMediaURL(msg.ID, MediaVariantThumbnail, expires)
+	}
+	withMedia := *event
+	withMedia.Data = data
+	return json.Marshal(&withMedia)
+}
+
+// webhookBackoff 第attempt次重试前的等待时间，从 backoff_ms 开始每次翻倍
+func webhookBackoff(retry model.WebhookRetryPolicy, attempt int) time.Duration {
+	backoff := defaultWebhookBackoffMs * time.Millisecond
+	if retry.BackoffMs > 0 {
+		backoff = time.Duration(retry.BackoffMs) * time.Millisecond
+	}
+	delay := backoff << min(attempt-1, 20)
+	if retry.MaxBackoffMs > 0 {
+		delay = min(delay, time.Duration(retry.MaxBackoffMs)*time.Millisecond)
+	}
+	return delay
+}
@@ -0,0 +1,148 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"whatsapp-aggregator/internal/logging"
+	"whatsapp-aggregator/internal/model"
+	"whatsapp-aggregator/internal/tracing"
+)
+
+// WorkerError Worker调用失败的错误
+type WorkerError struct {
+	StatusCode int // Worker返回的HTTP状态码，0表示无法连接Worker
+	Message    string
+}
+
+func (e *WorkerError) Error() string {
+	if e.StatusCode == 0 {
+		return fmt.Sprintf("failed to connect to worker: %s", e.Message)
+	}
+	if e.Message != "" {
+		return fmt.Sprintf("worker returned status %d: %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("worker returned status %d", e.StatusCode)
+}
+
+// Retryable 是否为可重试的错误（Worker不可达或网关类错误）
+func (e *WorkerError) Retryable() bool {
+	switch e.StatusCode {
+	case 0, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// FetchFromWorker 调用Worker的GET接口并解析JSON响应
+func (m *Manager) FetchFromWorker(ctx context.Context, accountID, workerPath string) (map[string]interface{}, error) {
+	account, err := m.GetAccount(accountID)
+	if err != nil {
+		return nil, err
+	}
+	return m.callWorker(ctx, account, "GET", workerPath, nil)
+}
+
+// FetchWorkerContacts 从Worker获取账号联系人
+func (m *Manager) FetchWorkerContacts(ctx context.Context, accountID string) ([]model.WorkerContact, error) {
+	result, err := m.FetchFromWorker(ctx, accountID, "/api/contacts")
+	if err != nil {
+		return nil, err
+	}
+	contacts := make([]model.WorkerContact, 0)
+	if err := decodeWorkerData(result, &contacts); err != nil {
+		return nil, err
+	}
+	return contacts, nil
+}
+
+// decodeWorkerData 将Worker响应的data字段解析为对应的模型
+func decodeWorkerData(result map[string]interface{}, out interface{}) error {
+	if result["data"] == nil {
+		return nil
+	}
+	raw, err := json.Marshal(result["data"])
+	if err != nil {
+		return fmt.Errorf("failed to parse worker response: %v", err)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return fmt.Errorf("failed to parse worker response: %v", err)
+	}
+	return nil
+}
+
+// postToWorker 调用Worker的POST接口并解析JSON响应
+func (m *Manager) postToWorker(ctx context.Context, account *model.Account, workerPath string, payload interface{}) (map[string]interface{}, error) {
+	return m.callWorker(ctx, account, "POST", workerPath, payload)
+}
+
+// callWorker 向Worker发送请求，非200响应返回带Worker错误信息的error
+func (m *Manager) callWorker(ctx context.Context, account *model.Account, method, workerPath string, payload interface{}) (result map[string]interface{}, err error) {
+	ctx, span := tracing.Start(ctx, "worker "+method+" "+workerPath, tracing.KindClient,
+		tracing.String("account.id", account.ID),
+		tracing.String("http.request.method", method),
+		tracing.String("url.path", workerPath),
+	)
+	defer func() { span.Finish(err) }()
+
+	if err := m.ApplyWorkerFault(ctx, account.ID); err != nil {
+		return nil, err
+	}
+
+	var body io.Reader
+	var reqBody []byte
+	if payload != nil {
+		if reqBody, err = json.Marshal(payload); err != nil {
+			return nil, fmt.Errorf("failed to marshal request: %v", err)
+		}
+		body = bytes.NewBuffer(reqBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%s%s", account.ServiceURL, workerPath), body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	logging.InjectRequestID(req)
+	tracing.Inject(ctx, req.Header)
+
+	started := time.Now()
+	status := 0
+	defer func() { m.recordMasterCall(ctx, account.ID, method, workerPath, reqBody, status, started, err) }()
+
+	resp, err := m.workerHTTP.Do(req)
+	if err != nil {
+		return nil, &WorkerError{Message: err.Error()}
+	}
+	defer resp.Body.Close()
+	status = resp.StatusCode
+	span.SetAttributes(tracing.Int("http.response.status_code", resp.StatusCode))
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %v", err)
+	}
+
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, &WorkerError{StatusCode: resp.StatusCode}
+		}
+		return nil, fmt.Errorf("failed to parse worker response: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		errMsg, _ := result["error"].(string)
+		workerErr := &WorkerError{StatusCode: resp.StatusCode, Message: errMsg}
+		m.detectWorkerErrorBan(account.ID, workerPath, workerErr)
+		return result, workerErr
+	}
+
+	return result, nil
+}
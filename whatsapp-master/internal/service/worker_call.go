@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"whatsapp-aggregator/internal/logging"
+	"whatsapp-aggregator/internal/model"
+)
+
+// Worker调用的来源
+const (
+	WorkerCallSourceProxy  = "proxy"  // 代理转发的API请求
+	WorkerCallSourceMaster = "master" // Master发起的调用，如发送消息和登录
+)
+
+const (
+	workerCallQueueSize  = 4096            // 等待写入的调用记录上限，写入跟不上时丢弃新记录
+	workerCallBatchSize  = 200             // 每次批量写入的记录数
+	workerCallFlushEvery = 2 * time.Second // 不满一批时的写入间隔
+	WorkerCallHashLimit  = 64 * 1024       // 请求体参与哈希的最大字节数
+	workerCallErrorLimit = 500             // 错误信息保留的最大长度
+)
+
+// workerCallColumns Worker调用记录允许过滤和排序的字段
+var workerCallColumns = map[string]string{
+	"id":         "id",
+	"request_id": "request_id",
+	"source":     "source",
+	"method":     "method",
+	"path":       "path",
+	"status":     "status",
+	"latency_ms": "latency_ms",
+	"created_at": "created_at",
+}
+
+// workerCallsDropped 队列已满被丢弃的调用记录数，用于限制告警日志频率
+var workerCallsDropped atomic.Int64
+
+// WorkerCallHash 请求体前 WorkerCallHashLimit 字节的SHA-256，请求体为空时返回空字符串
+func WorkerCallHash(payload []byte) string {
+	if len(payload) == 0 {
+		return ""
+	}
+	if len(payload) > WorkerCallHashLimit {
+		payload = payload[:WorkerCallHashLimit]
+	}
+	sum := sha256.Sum256(payload)
+	return hex.EncodeToString(sum[:])
+}
+
+// RecordWorkerCall 将一次Worker调用放入写入队列，不阻塞调用方；未开启 AUDIT_WORKER_CALLS 时忽略
+func (m *Manager) RecordWorkerCall(ctx context.Context, call *model.WorkerCall) {
+	if !m.GetConfig().Audit.WorkerCalls {
+		return
+	}
+	if call.RequestID == "" {
+		call.RequestID = logging.RequestID(ctx)
+	}
+	if len(call.Error) > workerCallErrorLimit {
+		call.Error = call.Error[:workerCallErrorLimit]
+	}
+	if call.CreatedAt.IsZero() {
+		call.CreatedAt = time.Now()
+	}
+	select {
+	case m.workerCalls <- call:
+	default:
+		if workerCallsDropped.Add(1)%100 == 1 {
+			slog.Warn("Worker call queue is full, dropping records", "dropped", workerCallsDropped.Load())
+		}
+	}
+}
+
+// recordMasterCall 记录Master发起的一次Worker调用
+func (m *Manager) recordMasterCall(ctx context.Context, accountID, method, workerPath string, payload []byte, status int, started time.Time, err error) {
+	call := &model.WorkerCall{
+		AccountID:   accountID,
+		Source:      WorkerCallSourceMaster,
+		Method:      method,
+		Path:        workerPath,
+		Status:      status,
+		LatencyMs:   time.Since(started).Milliseconds(),
+		PayloadSize: int64(len(payload)),
+		PayloadHash: WorkerCallHash(payload),
+		CreatedAt:   started,
+	}
+	if err != nil {
+		call.Error = err.Error()
+	}
+	m.RecordWorkerCall(ctx, call)
+}
+
+// StartWorkerCallRecorder 批量写入队列中的Worker调用记录，关闭时写入剩余记录
+func (m *Manager) StartWorkerCallRecorder() {
+	if !m.GetConfig().Audit.WorkerCalls {
+		return
+	}
+	ticker := time.NewTicker(workerCallFlushEvery)
+	m.background.Add(1)
+	go func() {
+		defer m.background.Done()
+		defer ticker.Stop()
+
+		batch := make([]*model.WorkerCall, 0, workerCallBatchSize)
+		flush := func() {
+			if len(batch) == 0 {
+				return
+			}
+			if err := m.db.CreateInBatches(batch, workerCallBatchSize).Error; err != nil {
+				slog.Warn("Failed to record worker calls", "count", len(batch), "error", err)
+			}
+			batch = batch[:0]
+		}
+		for {
+			select {
+			case <-m.stopCh:
+				for {
+					select {
+					case call := <-m.workerCalls:
+						batch = append(batch, call)
+					default:
+						flush()
+						return
+					}
+				}
+			case call := <-m.workerCalls:
+				batch = append(batch, call)
+				if len(batch) >= workerCallBatchSize {
+					flush()
+				}
+			case <-ticker.C:
+				flush()
+			}
+		}
+	}()
+}
+
+// ListWorkerCalls 分页查询账号的Worker调用记录，since/until 限定时间范围，默认按时间倒序
+func (m *Manager) ListWorkerCalls(accountID string, q *model.ListQuery, since, until *time.Time) ([]*model.WorkerCall, int64, error) {
+	db := m.db.Model(&model.WorkerCall{}).Where("account_id = ?", accountID)
+	if since != nil {
+		db = db.Where("created_at >= ?", *since)
+	}
+	if until != nil {
+		db = db.Where("created_at < ?", *until)
+	}
+
+	calls := make([]*model.WorkerCall, 0)
+	total, err := findWithListQuery(db, q, workerCallColumns, "-created_at", &calls)
+	if err != nil {
+		return nil, 0, err
+	}
+	return calls, total, nil
+}
+
+// cleanWorkerCalls 删除超过保留期的Worker调用记录，并将每个账号的记录数限制在 AUDIT_WORKER_CALL_MAX_PER_ACCOUNT 以内
+func (m *Manager) cleanWorkerCalls(report *model.JanitorReport) {
+	cfg := m.GetConfig().Audit
+	if cfg.WorkerCallRetentionDays > 0 {
+		db := m.db.Where("created_at < ?", time.Now().AddDate(0, 0, -cfg.WorkerCallRetentionDays))
+		if report.DryRun {
+			var count int64
+			if err := db.Model(&model.WorkerCall{}).Count(&count).Error; err != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("failed to count expired worker calls: %v", err))
+			}
+			report.WorkerCalls += count
+		} else {
+			result := db.Delete(&model.WorkerCall{})
+			if result.Error != nil {
+				report.Errors = append(report.Errors, fmt.Sprintf("failed to delete expired worker calls: %v", result.Error))
+			}
+			report.WorkerCalls += result.RowsAffected
+		}
+	}
+
+	if cfg.WorkerCallMaxPerAccount <= 0 {
+		return
+	}
+	var accounts []struct {
+		AccountID string
+		Count     int64
+	}
+	if err := m.db.Model(&model.WorkerCall{}).Select("account_id, COUNT(*) AS count").
+		Group("account_id").Having("COUNT(*) > ?", cfg.WorkerCallMaxPerAccount).Scan(&accounts).Error; err != nil {
+		report.Errors = append(report.Errors, fmt.Sprintf("failed to count worker calls per account: %v", err))
+		return
+	}
+	for _, account := range accounts {
+		excess := account.Count - int64(cfg.WorkerCallMaxPerAccount)
+		if report.DryRun {
+			report.WorkerCalls += excess
+			continue
+		}
+		// 保留最新的记录：找到第 max+1 新的记录，删除它及更早的记录
+		var cutoff model.WorkerCall
+		if err := m.db.Where("account_id = ?", account.AccountID).Order("id DESC").
+			Offset(cfg.WorkerCallMaxPerAccount).Limit(1).Find(&cutoff).Error; err != nil || cutoff.ID == 0 {
+			continue
+		}
+		result := m.db.Where("account_id = ? AND id <= ?", account.AccountID, cutoff.ID).Delete(&model.WorkerCall{})
+		if result.Error != nil {
+			report.Errors = append(report.Errors, fmt.Sprintf("failed to trim worker calls of %s: %v", account.AccountID, result.Error))
+			continue
+		}
+		report.WorkerCalls += result.RowsAffected
+	}
+}
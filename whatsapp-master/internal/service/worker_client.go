@@ -0,0 +1,116 @@
+package service
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"whatsapp-aggregator/internal/config"
+	"whatsapp-aggregator/internal/model"
+)
+
+// workerConnStats Worker连接的复用统计
+type workerConnStats struct {
+	reused atomic.Int64
+	opened atomic.Int64
+}
+
+// countingTransport 在共享连接池上统计连接是否复用
+type countingTransport struct {
+	base  *http.Transport
+	stats *workerConnStats
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	trace := &httptrace.ClientTrace{
+		GotConn: func(info httptrace.GotConnInfo) {
+			if info.Reused {
+				t.stats.reused.Add(1)
+			} else {
+				t.stats.opened.Add(1)
+			}
+		},
+	}
+	return t.base.RoundTrip(req.WithContext(httptrace.WithClientTrace(req.Context(), trace)))
+}
+
+// newWorkerTransport 按 WORKER_HTTP_* 和 WORKER_TLS_* 创建调用Worker的连接池
+func newWorkerTransport(cfg config.WorkerHTTPConfig) (*http.Transport, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   time.Duration(cfg.DialTimeoutSeconds) * time.Second,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.MaxIdleConns = cfg.MaxIdleConns
+	transport.MaxIdleConnsPerHost = cfg.MaxIdleConnsPerHost
+	transport.MaxConnsPerHost = cfg.MaxConnsPerHost
+	transport.IdleConnTimeout = time.Duration(cfg.IdleConnTimeoutSeconds) * time.Second
+	// Worker在Master的内网中，不经过环境变量中的HTTP代理
+	transport.Proxy = nil
+
+	if !cfg.TLSEnabled {
+		return transport, nil
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         cfg.TLSServerName,
+		InsecureSkipVerify: cfg.TLSInsecureSkipVerify,
+	}
+	if cfg.TLSCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLSCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read WORKER_TLS_CA_FILE: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("WORKER_TLS_CA_FILE %s contains no PEM certificates", cfg.TLSCAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if cfg.TLSCertFile != "" || cfg.TLSKeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(cfg.TLSCertFile, cfg.TLSKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load WORKER_TLS_CERT_FILE and WORKER_TLS_KEY_FILE: %v", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// newWorkerHTTP 创建Master调用Worker共用的连接池和HTTP客户端
+func newWorkerHTTP(cfg config.WorkerHTTPConfig, stats *workerConnStats) (http.RoundTripper, *http.Client, error) {
+	base, err := newWorkerTransport(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	transport := &countingTransport{base: base, stats: stats}
+	return transport, &http.Client{Transport: transport, Timeout: time.Duration(cfg.TimeoutSeconds) * time.Second}, nil
+}
+
+// WorkerTransport 代理请求到Worker使用的共享连接池
+func (m *Manager) WorkerTransport() http.RoundTripper {
+	return m.workerTransport
+}
+
+// workerScheme Worker服务地址的协议，开启 WORKER_TLS_ENABLED 时为https
+func (m *Manager) workerScheme() string {
+	if m.GetConfig().WorkerHTTP.TLSEnabled {
+		return "https"
+	}
+	return "http"
+}
+
+// WorkerConnectionMetrics Worker连接池的连接复用统计
+func (m *Manager) WorkerConnectionMetrics() model.WorkerConnectionMetrics {
+	return model.WorkerConnectionMetrics{
+		Reused: m.workerConns.reused.Load(),
+		Opened: m.workerConns.opened.Load(),
+	}
+}
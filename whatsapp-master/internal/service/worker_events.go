@@ -0,0 +1,83 @@
+package service
+
+import (
+	"log/slog"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// HandleWorkerEvents 处理Worker推送的状态、二维码、消息、注销和等待会话解密事件。
+// 状态和注销事件按发生时间排序，早于已处理事件的视为过期并忽略；消息与轮询共用去重逻辑
+func (m *Manager) HandleWorkerEvents(accountID string, events []model.WorkerEvent) (*model.WorkerEventsResult, error) {
+	if _, err := m.GetAccount(accountID); err != nil {
+		return nil, err
+	}
+
+	result := &model.WorkerEventsResult{}
+	messages := make([]model.WorkerMessage, 0)
+	for _, event := range events {
+		switch event.Type {
+		case model.WorkerEventStatus:
+			if !m.acceptStatusEvent(accountID, event.Timestamp) {
+				result.Ignored++
+				continue
+			}
+			m.applyWorkerStatus(accountID, event.Status, "reported by worker callback")
+		case model.WorkerEventLogout:
+			if !m.acceptStatusEvent(accountID, event.Timestamp) {
+				result.Ignored++
+				continue
+			}
+			m.emitQRCode(accountID, "")
+			reason := "logged out, reported by worker callback"
+			if event.Reason != "" {
+				reason = "logged out by worker: " + event.Reason
+			}
+			m.applyWorkerStatus(accountID, "logged_out", reason)
+			m.detectLogoutBan(accountID, event.Reason)
+		case model.WorkerEventQRCode:
+			m.emitQRCode(accountID, event.QRCode)
+		case model.WorkerEventMessage:
+			messages = append(messages, *event.Message)
+		case model.WorkerEventSessionWaiting:
+			m.sessionWaiting(accountID)
+		}
+		result.Accepted++
+	}
+
+	if len(messages) > 0 {
+		result.MessagesAdded = m.storeInboundMessages(accountID, messages)
+	}
+	slog.Debug("Worker events handled", "account_id", accountID, "accepted", result.Accepted, "ignored", result.Ignored, "messages_added", result.MessagesAdded)
+	return result, nil
+}
+
+// acceptStatusEvent 记录状态事件时间，早于已处理事件的返回false；未带时间的事件总是接受
+func (m *Manager) acceptStatusEvent(accountID string, timestamp int64) bool {
+	if timestamp <= 0 {
+		return true
+	}
+	for {
+		previous, loaded := m.statusEventAt.LoadOrStore(accountID, timestamp)
+		if !loaded {
+			return true
+		}
+		if previous.(int64) > timestamp {
+			return false
+		}
+		if m.statusEventAt.CompareAndSwap(accountID, previous, timestamp) {
+			return true
+		}
+	}
+}
+
+// applyWorkerStatus 应用Worker上报的状态，与当前状态相同时不记录；与状态轮询一致，已停止、出错或隔离中的账号不更新
+func (m *Manager) applyWorkerStatus(accountID, status, reason string) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	account, exists := m.accounts[accountID]
+	if !exists || account.Status == status || account.Status == "stopped" || account.Status == "error" || account.Status == statusBanned {
+		return
+	}
+	m.UpdateAccountStatus(accountID, status, StatusCause{Source: StatusSourceWorker, Reason: reason})
+}
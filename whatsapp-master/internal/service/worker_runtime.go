@@ -0,0 +1,161 @@
+package service
+
+import (
+	"fmt"
+	"net"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// workerSessionMountDir Worker容器内会话目录，由Master按账号挂载
+const workerSessionMountDir = "/app/whatsapp-session"
+
+// validEnvName 环境变量名
+var validEnvName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// reservedWorkerEnv Master为每个Worker设置的环境变量，不允许覆盖
+var reservedWorkerEnv = map[string]bool{
+	"PORT":           true,
+	"ACCOUNT_ID":     true,
+	"MASTER_URL":     true,
+	"WORKER_TOKEN":   true,
+	"PROXY_IP":       true,
+	"PROXY_PORT":     true,
+	"PROXY_USERNAME": true,
+	"PROXY_PASSWORD": true,
+	"PROXY_PROTOCOL": true,
+	"HW_OS":          true,
+	"HW_BROWSER":     true,
+	"HW_USER_AGENT":  true,
+	"HW_SCREEN":      true,
+	"HW_LOCALE":      true,
+	"HW_TIMEZONE":    true,
+}
+
+// validateWorkerRuntime 校验Worker额外的环境变量、挂载卷和DNS，避免到 docker run 时才失败
+func validateWorkerRuntime(r *model.WorkerRuntime) error {
+	for name := range r.Env {
+		if !validEnvName.MatchString(name) {
+			return fmt.Errorf("invalid env name %q", name)
+		}
+		if reservedWorkerEnv[name] {
+			return fmt.Errorf("env %s is set by the master and cannot be overridden", name)
+		}
+	}
+	for _, volume := range r.Volumes {
+		if _, err := volumeTarget(volume); err != nil {
+			return err
+		}
+	}
+	for _, server := range r.DNS {
+		if net.ParseIP(server) == nil {
+			return fmt.Errorf("invalid dns server %q: must be an IP address", server)
+		}
+	}
+	return nil
+}
+
+// volumeTarget 解析 docker -v 格式的挂载卷并返回容器内路径
+func volumeTarget(volume string) (string, error) {
+	parts := strings.Split(volume, ":")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" {
+		return "", fmt.Errorf("invalid volume %q: use SOURCE:CONTAINER_PATH[:ro|rw]", volume)
+	}
+	target := path.Clean(parts[1])
+	if !path.IsAbs(target) {
+		return "", fmt.Errorf("invalid volume %q: container path must be absolute", volume)
+	}
+	if target == workerSessionMountDir || strings.HasPrefix(target, workerSessionMountDir+"/") {
+		return "", fmt.Errorf("invalid volume %q: %s is managed by the master", volume, workerSessionMountDir)
+	}
+	if len(parts) == 3 && parts[2] != "ro" && parts[2] != "rw" {
+		return "", fmt.Errorf("invalid volume %q: mode must be ro or rw", volume)
+	}
+	return target, nil
+}
+
+// applyWorkerRuntime 将请求中的运行参数写入账号：环境变量按名称合并，挂载卷按容器路径合并，DNS整体替换
+func applyWorkerRuntime(account *model.Account, r *model.WorkerRuntime) {
+	if len(r.Env) > 0 {
+		env := model.StringMap{}
+		for name, value := range account.Runtime.Env {
+			env[name] = value
+		}
+		for name, value := range r.Env {
+			env[name] = value
+		}
+		account.Runtime.Env = env
+	}
+	if len(r.Volumes) > 0 {
+		account.Runtime.Volumes = mergeVolumes(account.Runtime.Volumes, r.Volumes)
+	}
+	if len(r.DNS) > 0 {
+		account.Runtime.DNS = model.StringList(r.DNS)
+	}
+}
+
+// mergeVolumes 合并挂载卷，后者与前者容器路径相同时替换前者
+func mergeVolumes(base, overrides []string) model.StringList {
+	merged := make(model.StringList, 0, len(base)+len(overrides))
+	targets := make(map[string]int)
+	for _, volume := range append(append([]string{}, base...), overrides...) {
+		target, err := volumeTarget(volume)
+		if err != nil {
+			continue
+		}
+		if i, exists := targets[target]; exists {
+			merged[i] = volume
+			continue
+		}
+		targets[target] = len(merged)
+		merged = append(merged, volume)
+	}
+	return merged
+}
+
+// workerRuntime 账号Worker实际使用的运行参数：全局 WORKER_ENV、WORKER_VOLUMES、WORKER_DNS 与账号设置合并
+func (m *Manager) workerRuntime(account *model.Account) model.WorkerRuntime {
+	cfg := m.GetConfig().Worker
+	runtime := model.WorkerRuntime{Env: model.StringMap{}}
+	for _, item := range cfg.Env {
+		name, value, ok := strings.Cut(item, "=")
+		if !ok || !validEnvName.MatchString(name) || reservedWorkerEnv[name] {
+			continue
+		}
+		runtime.Env[name] = value
+	}
+	for name, value := range account.Runtime.Env {
+		runtime.Env[name] = value
+	}
+	runtime.Volumes = mergeVolumes(cfg.Volumes, account.Runtime.Volumes)
+	runtime.DNS = model.StringList(cfg.DNS)
+	if len(account.Runtime.DNS) > 0 {
+		runtime.DNS = account.Runtime.DNS
+	}
+	return runtime
+}
+
+// dockerRuntimeArgs 运行参数对应的 docker run 参数
+func dockerRuntimeArgs(r model.WorkerRuntime) []string {
+	names := make([]string, 0, len(r.Env))
+	for name := range r.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var args []string
+	for _, name := range names {
+		args = append(args, "-e", name+"="+r.Env[name])
+	}
+	for _, volume := range r.Volumes {
+		args = append(args, "-v", volume)
+	}
+	for _, server := range r.DNS {
+		args = append(args, "--dns", server)
+	}
+	return args
+}
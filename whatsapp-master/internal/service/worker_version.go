@@ -0,0 +1,107 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"whatsapp-aggregator/internal/model"
+)
+
+// Worker版本检查方式
+const (
+	WorkerVersionCheckWarn  = "warn"
+	WorkerVersionCheckBlock = "block"
+	WorkerVersionCheckOff   = "off"
+)
+
+// ErrIncompatibleWorker Worker的API版本不在Master支持的范围内
+var ErrIncompatibleWorker = errors.New("incompatible worker version")
+
+// workerVersion Worker在 /api/status 中上报的版本
+type workerVersion struct {
+	Version    string `json:"version"`
+	APIVersion int    `json:"api_version"`
+}
+
+// workerVersionCompatible API版本是否在 WORKER_API_VERSION_MIN 和 WORKER_API_VERSION_MAX 之间，
+// 未上报版本的旧Worker视为不兼容
+func (m *Manager) workerVersionCompatible(apiVersion int) bool {
+	if m.GetConfig().Worker.VersionCheck == WorkerVersionCheckOff {
+		return true
+	}
+	return apiVersion > 0 && apiVersion >= m.GetConfig().Worker.APIVersionMin &&
+		(m.GetConfig().Worker.APIVersionMax <= 0 || apiVersion <= m.GetConfig().Worker.APIVersionMax)
+}
+
+// recordWorkerVersion 保存Worker上报的版本，变为不兼容时告警并发出事件，返回是否兼容。调用方需持有锁
+func (m *Manager) recordWorkerVersion(account *model.Account, version workerVersion) bool {
+	compatible := m.workerVersionCompatible(version.APIVersion)
+	if account.WorkerVersion == version.Version && account.WorkerAPIVersion == version.APIVersion && account.WorkerIncompatible == !compatible {
+		return compatible
+	}
+
+	account.WorkerVersion = version.Version
+	account.WorkerAPIVersion = version.APIVersion
+	account.WorkerIncompatible = !compatible
+	if err := m.db.Model(account).Updates(map[string]interface{}{
+		"worker_version":      account.WorkerVersion,
+		"worker_api_version":  account.WorkerAPIVersion,
+		"worker_incompatible": account.WorkerIncompatible,
+	}).Error; err != nil {
+		slog.Warn("Failed to save worker version", "account_id", account.ID, "error", err)
+	}
+
+	if !compatible {
+		slog.Warn("Worker version is incompatible", "account_id", account.ID, "version", version.Version, "api_version", version.APIVersion,
+			"supported", m.supportedWorkerAPIVersions())
+		m.emit(EventWorkerIncompatible, account.ID, map[string]interface{}{
+			"version":     version.Version,
+			"api_version": version.APIVersion,
+			"supported":   m.supportedWorkerAPIVersions(),
+			"blocked":     m.GetConfig().Worker.VersionCheck == WorkerVersionCheckBlock,
+		})
+	}
+	return compatible
+}
+
+// supportedWorkerAPIVersions Master支持的Worker API版本范围，用于日志和错误信息
+func (m *Manager) supportedWorkerAPIVersions() string {
+	if m.GetConfig().Worker.APIVersionMax <= 0 {
+		return fmt.Sprintf(">=%d", m.GetConfig().Worker.APIVersionMin)
+	}
+	return fmt.Sprintf("%d-%d", m.GetConfig().Worker.APIVersionMin, m.GetConfig().Worker.APIVersionMax)
+}
+
+// checkWorkerVersions 是否有Worker的API版本不兼容，不兼容的Worker可能无法正常发送或登录
+func (m *Manager) checkWorkerVersions(check *model.HealthCheck) {
+	m.mutex.RLock()
+	versions := make(map[string]int)
+	incompatible := make([]string, 0)
+	for _, account := range m.accounts {
+		if account.WorkerVersion == "" && account.WorkerAPIVersion == 0 && !account.WorkerIncompatible {
+			continue
+		}
+		version := account.WorkerVersion
+		if version == "" {
+			version = "unknown"
+		}
+		versions[version]++
+		if account.WorkerIncompatible {
+			incompatible = append(incompatible, account.ID)
+		}
+	}
+	m.mutex.RUnlock()
+	sort.Strings(incompatible)
+
+	check.Details["versions"] = versions
+	check.Details["incompatible"] = incompatible
+	check.Details["supported_api_versions"] = m.supportedWorkerAPIVersions()
+
+	if len(incompatible) > 0 {
+		check.Status = HealthDegraded
+		check.Message = fmt.Sprintf("%d workers run an incompatible version: %s", len(incompatible), strings.Join(incompatible, ", "))
+	}
+}
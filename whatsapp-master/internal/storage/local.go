@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// localStore 对象保存在Master所在机器的目录中
+type localStore struct {
+	dir         string
+	signingKey  []byte
+	downloadURL string
+}
+
+func newLocalStore(dir string, cfg Config) (*localStore, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("storage directory is empty")
+	}
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage directory: %v", err)
+	}
+	return &localStore{dir: abs, signingKey: cfg.SigningKey, downloadURL: cfg.DownloadURL}, nil
+}
+
+func (s *localStore) path(key string) (string, error) {
+	if err := ValidKey(key); err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, filepath.FromSlash(key)), nil
+}
+
+// Put 先写入临时文件再重命名，中断时不会留下不完整的对象
+func (s *localStore) Put(_ context.Context, key string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create storage directory: %v", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o600); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write object: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write object: %v", err)
+	}
+	return nil
+}
+
+func (s *localStore) Get(_ context.Context, key string) ([]byte, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read object: %v", err)
+	}
+	return data, nil
+}
+
+func (s *localStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to delete object: %v", err)
+	}
+	// 所在目录为空时一并删除，忽略非空的错误
+	os.Remove(filepath.Dir(path))
+	return nil
+}
+
+// SignedURL 指向Master下载接口 /storage/{key} 的地址，签名覆盖键和过期时间
+func (s *localStore) SignedURL(key string, ttl time.Duration) (string, error) {
+	if err := ValidKey(key); err != nil {
+		return "", err
+	}
+	if len(s.signingKey) == 0 || s.downloadURL == "" {
+		return "", fmt.Errorf("signed urls are not available for this storage")
+	}
+	expires := time.Now().Add(ttl).Unix()
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("sig", Sign(s.signingKey, key, expires))
+	return fmt.Sprintf("%s/%s?%s", strings.TrimRight(s.downloadURL, "/"), escapePath(key), query.Encode()), nil
+}
+
+func (s *localStore) String() string {
+	return s.dir
+}
+
+// escapePath 对路径逐段进行RFC 3986编码，保留分隔符 /
+func escapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if c == '/' || c == '-' || c == '_' || c == '.' || c == '~' ||
+			('A' <= c && c <= 'Z') || ('a' <= c && c <= 'z') || ('0' <= c && c <= '9') {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
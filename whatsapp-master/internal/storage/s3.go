@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// s3Timeout 单次对象上传或下载的超时
+const s3Timeout = 5 * time.Minute
+
+// s3Store 对象保存在S3兼容的服务（AWS S3、MinIO等）中，通过 minio-go 使用路径风格的地址访问
+type s3Store struct {
+	client *minio.Client
+	bucket string
+	prefix string
+}
+
+func newS3Store(u *url.URL, cfg Config) (*s3Store, error) {
+	if u.Host == "" {
+		return nil, fmt.Errorf("s3 storage destination needs a bucket, e.g. s3://bucket/prefix")
+	}
+	if cfg.S3AccessKeyID == "" || cfg.S3SecretAccessKey == "" {
+		return nil, fmt.Errorf("%s_S3_ACCESS_KEY_ID and %s_S3_SECRET_ACCESS_KEY are required for s3 storage", cfg.EnvPrefix, cfg.EnvPrefix)
+	}
+	region := cfg.S3Region
+	if region == "" {
+		region = "us-east-1"
+	}
+	rawEndpoint := cfg.S3Endpoint
+	if rawEndpoint == "" {
+		rawEndpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", region)
+	}
+	// minio-go 只接受主机和端口，端点不能带路径
+	endpoint, err := url.Parse(strings.TrimRight(rawEndpoint, "/"))
+	if err != nil || endpoint.Host == "" || endpoint.Path != "" || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+		return nil, fmt.Errorf("invalid %s_S3_ENDPOINT %q", cfg.EnvPrefix, rawEndpoint)
+	}
+
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds:        credentials.NewStaticV4(cfg.S3AccessKeyID, cfg.S3SecretAccessKey, ""),
+		Secure:       endpoint.Scheme == "https",
+		Region:       region,
+		BucketLookup: minio.BucketLookupPath,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s_S3_ENDPOINT %q: %v", cfg.EnvPrefix, rawEndpoint, err)
+	}
+	return &s3Store{
+		client: client,
+		bucket: u.Host,
+		prefix: strings.Trim(u.Path, "/"),
+	}, nil
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, data []byte) error {
+	if err := ValidKey(key); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, s3Timeout)
+	defer cancel()
+	if _, err := s.client.PutObject(ctx, s.bucket, JoinKey(s.prefix, key), bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{}); err != nil {
+		return s3Error("upload", err)
+	}
+	return nil
+}
+
+func (s *s3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	if err := ValidKey(key); err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(ctx, s3Timeout)
+	defer cancel()
+	object, err := s.client.GetObject(ctx, s.bucket, JoinKey(s.prefix, key), minio.GetObjectOptions{})
+	if err != nil {
+		return nil, s3Error("download", err)
+	}
+	defer object.Close()
+	// GetObject 不发请求，读取时才返回对象不存在等错误
+	data, err := io.ReadAll(object)
+	if err != nil {
+		return nil, s3Error("download", err)
+	}
+	return data, nil
+}
+
+// Delete 删除对象，S3对不存在的对象同样返回成功
+func (s *s3Store) Delete(ctx context.Context, key string) error {
+	if err := ValidKey(key); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(ctx, s3Timeout)
+	defer cancel()
+	if err := s.client.RemoveObject(ctx, s.bucket, JoinKey(s.prefix, key), minio.RemoveObjectOptions{}); err != nil {
+		return s3Error("delete", err)
+	}
+	return nil
+}
+
+func (s *s3Store) String() string {
+	return "s3://" + JoinKey(s.bucket, s.prefix)
+}
+
+// SignedURL 生成 AWS Signature Version 4 预签名的GET地址，最长有效7天
+func (s *s3Store) SignedURL(key string, ttl time.Duration) (string, error) {
+	if err := ValidKey(key); err != nil {
+		return "", err
+	}
+	if ttl <= 0 || ttl > MaxSignedURLTTL {
+		return "", fmt.Errorf("signed url ttl must be between 1s and %s", MaxSignedURLTTL)
+	}
+	// 预签名在本地计算，不发请求
+	signed, err := s.client.PresignedGetObject(context.Background(), s.bucket, JoinKey(s.prefix, key), ttl, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign object url: %v", err)
+	}
+	return signed.String(), nil
+}
+
+// s3Error 带上S3返回的状态码和错误信息
+func s3Error(action string, err error) error {
+	if resp := minio.ToErrorResponse(err); resp.StatusCode != 0 {
+		return fmt.Errorf("failed to %s object: s3 returned status %d: %s %s", action, resp.StatusCode, resp.Code, resp.Message)
+	}
+	return fmt.Errorf("failed to %s object: %v", action, err)
+}
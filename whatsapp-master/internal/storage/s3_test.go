@@ -0,0 +1,168 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+)
+
+func TestSignedURL(t *testing.T) {
+	store, err := Open(Config{
+		Destination:       "s3://media/tenants/a b",
+		S3Endpoint:        "http://localhost:9000/",
+		S3AccessKeyID:     "key",
+		S3SecretAccessKey: "secret",
+	})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	signed, err := store.SignedURL("x/ü+$ 1.txt", time.Hour)
+	if err != nil {
+		t.Fatalf("signed url: %v", err)
+	}
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatalf("parse %s: %v", signed, err)
+	}
+	// 路径风格地址，键中的特殊字符需编码
+	if got, want := u.Scheme+"://"+u.Host+u.EscapedPath(), "http://localhost:9000/media/tenants/a%20b/x/%C3%BC%2B%24%201.txt"; got != want {
+		t.Errorf("object url = %s, want %s", got, want)
+	}
+	query := u.Query()
+	if query.Get("X-Amz-Algorithm") != "AWS4-HMAC-SHA256" || query.Get("X-Amz-Expires") != "3600" ||
+		!strings.HasPrefix(query.Get("X-Amz-Credential"), "key/") || query.Get("X-Amz-Signature") == "" {
+		t.Errorf("presign query = %v", query)
+	}
+
+	for _, ttl := range []time.Duration{0, MaxSignedURLTTL + time.Second} {
+		if _, err := store.SignedURL("x.txt", ttl); err == nil {
+			t.Errorf("ttl %s accepted", ttl)
+		}
+	}
+	if _, err := store.SignedURL("../x.txt", time.Hour); err == nil {
+		t.Error("invalid key accepted")
+	}
+}
+
+func TestOpenS3Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{"missing bucket", Config{Destination: "s3:///prefix", S3AccessKeyID: "k", S3SecretAccessKey: "s"}, "needs a bucket"},
+		{"missing credentials", Config{Destination: "s3://bucket", EnvPrefix: "BACKUP"}, "BACKUP_S3_ACCESS_KEY_ID and BACKUP_S3_SECRET_ACCESS_KEY are required"},
+		{"invalid endpoint", Config{Destination: "s3://bucket", S3Endpoint: "localhost", S3AccessKeyID: "k", S3SecretAccessKey: "s"}, "invalid STORAGE_S3_ENDPOINT"},
+		{"endpoint with path", Config{Destination: "s3://bucket", S3Endpoint: "http://localhost:9000/s3", S3AccessKeyID: "k", S3SecretAccessKey: "s"}, "invalid STORAGE_S3_ENDPOINT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Open(tt.cfg)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestS3RoundTrip 对真实的S3兼容服务读写对象，需设置 S3_TEST_ENDPOINT（如 http://localhost:9000）、
+// S3_TEST_ACCESS_KEY_ID 和 S3_TEST_SECRET_ACCESS_KEY，可选 S3_TEST_BUCKET 和 S3_TEST_REGION；
+// 本地可用 docker run -p 9000:9000 minio/minio server /data 启动 MinIO（默认凭证 minioadmin）
+func TestS3RoundTrip(t *testing.T) {
+	endpoint := os.Getenv("S3_TEST_ENDPOINT")
+	if endpoint == "" {
+		t.Skip("S3_TEST_ENDPOINT not set")
+	}
+	bucket := os.Getenv("S3_TEST_BUCKET")
+	if bucket == "" {
+		bucket = "whatsapp-aggregator-test"
+	}
+	store, err := Open(Config{
+		Destination:       "s3://" + bucket + "/round trip",
+		S3Endpoint:        endpoint,
+		S3Region:          os.Getenv("S3_TEST_REGION"),
+		S3AccessKeyID:     os.Getenv("S3_TEST_ACCESS_KEY_ID"),
+		S3SecretAccessKey: os.Getenv("S3_TEST_SECRET_ACCESS_KEY"),
+	})
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	s3 := store.(*s3Store)
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	createBucket(t, ctx, s3)
+
+	key := fmt.Sprintf("objects/%d/ü+$ 1.txt", time.Now().UnixNano())
+	data := []byte("hello from the round trip test\n")
+	if err := store.Put(ctx, key, data); err != nil {
+		t.Fatalf("put: %v", err)
+	}
+	got, err := store.Get(ctx, key)
+	if err != nil {
+		t.Fatalf("get: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("get = %q, want %q", got, data)
+	}
+
+	signed, err := store.SignedURL(key, time.Minute)
+	if err != nil {
+		t.Fatalf("signed url: %v", err)
+	}
+	resp, err := http.Get(signed)
+	if err != nil {
+		t.Fatalf("download signed url: %v", err)
+	}
+	body, _ := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || !bytes.Equal(body, data) {
+		t.Errorf("signed url download = %d %q", resp.StatusCode, body)
+	}
+
+	// 篡改签名后必须被拒绝，确认服务端确实校验了签名
+	tampered := signed[:len(signed)-1] + "0"
+	if strings.HasSuffix(signed, "0") {
+		tampered = signed[:len(signed)-1] + "1"
+	}
+	resp, err = http.Get(tampered)
+	if err != nil {
+		t.Fatalf("download tampered url: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("tampered signed url status = %d, want 403", resp.StatusCode)
+	}
+
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	if _, err := store.Get(ctx, key); err == nil || !strings.Contains(err.Error(), "status 404") {
+		t.Errorf("get after delete error = %v, want 404", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Errorf("deleting a missing object: %v", err)
+	}
+}
+
+// createBucket 创建测试桶，已存在时忽略
+func createBucket(t *testing.T, ctx context.Context, s *s3Store) {
+	t.Helper()
+	exists, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		t.Fatalf("check bucket: %v", err)
+	}
+	if !exists {
+		if err := s.client.MakeBucket(ctx, s.bucket, minio.MakeBucketOptions{}); err != nil {
+			t.Fatalf("create bucket: %v", err)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Package storage 对象存储：本地目录和S3兼容服务（AWS S3、MinIO等）。
+// 媒体发送、会话备份、聊天导出和联系人导入文件按键整体写入和读取，下载地址通过限时签名URL提供
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MaxSignedURLTTL S3预签名地址的最长有效期
+const MaxSignedURLTTL = 7 * 24 * time.Hour
+
+// Config 对象存储配置
+type Config struct {
+	Destination string // 本地目录或 s3://bucket/prefix
+
+	S3Endpoint        string // S3兼容服务地址，为空时使用AWS区域地址
+	S3Region          string
+	S3AccessKeyID     string
+	S3SecretAccessKey string
+
+	EnvPrefix string // 错误提示中的环境变量前缀，如 STORAGE、BACKUP
+
+	// 本地目录没有自己的下载服务，签名地址指向Master的下载接口，如 http://host:8080/storage
+	SigningKey  []byte
+	DownloadURL string
+}
+
+// Store 对象存储
+type Store interface {
+	// Put 写入对象，已存在时覆盖
+	Put(ctx context.Context, key string, data []byte) error
+	// Get 读取对象
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete 删除对象，不存在时不报错
+	Delete(ctx context.Context, key string) error
+	// SignedURL 生成在ttl内有效、无需其他凭证即可下载对象的地址
+	SignedURL(key string, ttl time.Duration) (string, error)
+	// String 不含凭证的目的地描述
+	String() string
+}
+
+// Open 按 Destination 的协议创建存储，没有协议时视为本地目录
+func Open(cfg Config) (Store, error) {
+	dest := strings.TrimSpace(cfg.Destination)
+	if dest == "" {
+		return nil, fmt.Errorf("storage destination is empty")
+	}
+	if cfg.EnvPrefix == "" {
+		cfg.EnvPrefix = "STORAGE"
+	}
+	if !strings.Contains(dest, "://") {
+		return newLocalStore(dest, cfg)
+	}
+
+	u, err := url.Parse(dest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage destination: %v", err)
+	}
+	switch u.Scheme {
+	case "file":
+		return newLocalStore(u.Path, cfg)
+	case "s3":
+		return newS3Store(u, cfg)
+	default:
+		return nil, fmt.Errorf("unsupported storage destination scheme %q, expected a directory or s3://", u.Scheme)
+	}
+}
+
+// ValidKey 键只能包含相对路径，不能跳出目的地
+func ValidKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid object key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid object key %q", key)
+		}
+	}
+	return nil
+}
+
+// JoinKey 拼接前缀和键
+func JoinKey(prefix, key string) string {
+	prefix = strings.Trim(prefix, "/")
+	if prefix == "" {
+		return key
+	}
+	return prefix + "/" + key
+}
+
+// WithPrefix 返回只读写 prefix 下对象的视图，多个用途共用一个存储时互不干扰
+func WithPrefix(store Store, prefix string) Store {
+	return &prefixStore{store: store, prefix: strings.Trim(prefix, "/")}
+}
+
+type prefixStore struct {
+	store  Store
+	prefix string
+}
+
+func (s *prefixStore) Put(ctx context.Context, key string, data []byte) error {
+	return s.store.Put(ctx, JoinKey(s.prefix, key), data)
+}
+
+func (s *prefixStore) Get(ctx context.Context, key string) ([]byte, error) {
+	return s.store.Get(ctx, JoinKey(s.prefix, key))
+}
+
+func (s *prefixStore) Delete(ctx context.Context, key string) error {
+	return s.store.Delete(ctx, JoinKey(s.prefix, key))
+}
+
+func (s *prefixStore) SignedURL(key string, ttl time.Duration) (string, error) {
+	return s.store.SignedURL(JoinKey(s.prefix, key), ttl)
+}
+
+func (s *prefixStore) String() string {
+	return strings.TrimRight(s.store.String(), "/") + "/" + s.prefix
+}
+
+// Sign 计算本地对象下载地址的签名，覆盖键和过期时间
+func Sign(signingKey []byte, key string, expires int64) string {
+	mac := hmac.New(sha256.New, signingKey)
+	mac.Write([]byte(key + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify 校验本地对象下载地址的签名和有效期
+func Verify(signingKey []byte, key string, expires int64, signature string, now time.Time) error {
+	if len(signingKey) == 0 {
+		return fmt.Errorf("signed downloads are not enabled")
+	}
+	if !hmac.Equal([]byte(Sign(signingKey, key, expires)), []byte(strings.ToLower(signature))) {
+		return fmt.Errorf("invalid signature")
+	}
+	if now.Unix() > expires {
+		return fmt.Errorf("signed url expired at %s", time.Unix(expires, 0).UTC().Format(time.RFC3339))
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	exportInterval  = 5 * time.Second  // 定时导出间隔
+	exportBatchSize = 512              // 攒够该数量立即导出
+	exportQueueSize = 2048             // 待导出队列上限，超出时丢弃Span
+	exportTimeout   = 10 * time.Second // 单次导出请求超时
+)
+
+// provider 已启用的全局 TracerProvider
+var provider atomic.Pointer[sdktrace.TracerProvider]
+
+// Setup 启用追踪：创建 OTLP/HTTP 导出器和批量处理的 TracerProvider，并注册为 OpenTelemetry 全局实现
+func Setup(cfg Config) error {
+	exporter, err := otlptracehttp.New(context.Background(),
+		otlptracehttp.WithEndpointURL(cfg.Endpoint),
+		otlptracehttp.WithHeaders(cfg.Headers),
+		otlptracehttp.WithTimeout(exportTimeout),
+	)
+	if err != nil {
+		return fmt.Errorf("create OTLP exporter: %w", err)
+	}
+
+	res := resource.NewSchemaless(
+		attribute.String("service.name", cfg.ServiceName),
+		attribute.String("service.version", cfg.ServiceVersion),
+	)
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		// 根Span按比例采样，有父Span时沿用父Span的采样标记
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+		// 队列满时丢弃Span而不阻塞业务
+		sdktrace.WithBatcher(exporter,
+			sdktrace.WithBatchTimeout(exportInterval),
+			sdktrace.WithMaxExportBatchSize(exportBatchSize),
+			sdktrace.WithMaxQueueSize(exportQueueSize),
+			sdktrace.WithExportTimeout(exportTimeout),
+		),
+	)
+
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		slog.Warn("Tracing error", "error", err)
+	}))
+	otel.SetTracerProvider(tp)
+	otel.SetTextMapPropagator(propagator)
+	if old := provider.Swap(tp); old != nil {
+		old.Shutdown(context.Background())
+	}
+	slog.Info("Tracing enabled", "endpoint", cfg.Endpoint, "service", cfg.ServiceName, "sample_ratio", cfg.SampleRatio)
+	return nil
+}
+
+// Shutdown 停止追踪并导出剩余的Span
+func Shutdown(ctx context.Context) error {
+	tp := provider.Swap(nil)
+	if tp == nil {
+		return nil
+	}
+	return tp.Shutdown(ctx)
+}
+
+// ParseHeaders 解析 OTEL_EXPORTER_OTLP_HEADERS 格式（key1=value1,key2=value2，值可URL编码）
+func ParseHeaders(value string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(pair, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid header %q: expected key=value", pair)
+		}
+		decoded, err := url.PathUnescape(strings.TrimSpace(val))
+		if err != nil {
+			return nil, fmt.Errorf("invalid header %q: %v", pair, err)
+		}
+		headers[key] = decoded
+	}
+	return headers, nil
+}
@@ -0,0 +1,147 @@
+// Package tracing 基于 OpenTelemetry 的链路追踪：W3C Trace Context 传播，
+// 通过 OTLP/HTTP 把Span导出到 OpenTelemetry Collector、Jaeger、Tempo 等后端。
+// 对业务代码只暴露 Start/Finish 等少量封装，未启用追踪时所有操作都是空操作
+package tracing
+
+import (
+	"context"
+	"net/http"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TraceParentHeader W3C Trace Context 传播头，Master转发给Worker时携带
+const TraceParentHeader = "traceparent"
+
+// instrumentationName 本服务创建Span使用的 Tracer 名称
+const instrumentationName = "whatsapp-aggregator"
+
+// Kind Span类型
+type Kind = trace.SpanKind
+
+const (
+	KindInternal = trace.SpanKindInternal
+	KindServer   = trace.SpanKindServer
+	KindClient   = trace.SpanKindClient
+)
+
+// Config 追踪配置
+type Config struct {
+	Endpoint       string            // OTLP/HTTP traces 接收地址，如 http://localhost:4318/v1/traces
+	Headers        map[string]string // 导出请求附加的头，如鉴权
+	ServiceName    string
+	ServiceVersion string
+	SampleRatio    float64 // 根Span采样比例（0-1）
+}
+
+// Attr Span属性
+type Attr = attribute.KeyValue
+
+// String 字符串属性
+func String(key, value string) Attr { return attribute.String(key, value) }
+
+// Int 整数属性
+func Int(key string, value int) Attr { return attribute.Int(key, value) }
+
+// Bool 布尔属性
+func Bool(key string, value bool) Attr { return attribute.Bool(key, value) }
+
+// propagator 读写 traceparent 请求头
+var propagator = propagation.TraceContext{}
+
+// Span 一次操作的计时记录，为nil时所有方法都是空操作
+type Span struct {
+	span trace.Span
+}
+
+// Enabled 是否已启用追踪
+func Enabled() bool {
+	return provider.Load() != nil
+}
+
+// Start 以上下文中的Span（或传入的远端父Span）为父创建新Span，
+// 未启用追踪时返回原上下文和nil
+func Start(ctx context.Context, name string, kind Kind, attrs ...Attr) (context.Context, *Span) {
+	tp := provider.Load()
+	if tp == nil {
+		return ctx, nil
+	}
+	ctx, span := tp.Tracer(instrumentationName).Start(ctx, name, trace.WithSpanKind(kind), trace.WithAttributes(attrs...))
+	return ctx, &Span{span: span}
+}
+
+// SetAttributes 追加属性
+func (s *Span) SetAttributes(attrs ...Attr) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(attrs...)
+}
+
+// RecordError 将Span标记为失败并记录错误事件，err为nil时忽略
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// SetError 以描述信息将Span标记为失败
+func (s *Span) SetError(message string) {
+	if s == nil {
+		return
+	}
+	s.span.SetStatus(codes.Error, message)
+}
+
+// Finish 记录错误（为nil时忽略）并结束Span，配合命名返回值在defer中使用
+func (s *Span) Finish(err error) {
+	s.RecordError(err)
+	s.End()
+}
+
+// End 结束Span并交给导出器，重复调用无效
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.span.End()
+}
+
+// TraceID 上下文中当前链路的ID，用于关联日志，不存在时返回空字符串
+func TraceID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+// ContinueFrom 以parent中的当前Span为父在ctx中继续链路，用于请求返回后仍在后台执行的任务
+func ContinueFrom(ctx, parent context.Context) context.Context {
+	sc := trace.SpanContextFromContext(parent)
+	if !sc.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithSpanContext(ctx, sc)
+}
+
+// Extract 从请求头解析 traceparent 作为后续Span的远端父Span，无效时忽略
+func Extract(ctx context.Context, header http.Header) context.Context {
+	if !Enabled() {
+		return ctx
+	}
+	return propagator.Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// Inject 将上下文中的Span写入发往Worker的 traceparent 请求头
+func Inject(ctx context.Context, header http.Header) {
+	propagator.Inject(ctx, propagation.HeaderCarrier(header))
+}
@@ -0,0 +1,180 @@
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// JSONFieldName 校验错误中使用请求JSON中的字段名，注册到gin的校验器上
+func JSONFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name := strings.SplitN(field.Tag.Get(tag), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if name != "" {
+			return name
+		}
+	}
+	return field.Name
+}
+
+// FromBindError 将请求绑定错误转换为字段错误：校验规则失败时每个字段一条，包含规则、参数和收到的值类型；
+// JSON类型不匹配时规则为type；其他错误（JSON格式错误、空请求体）作为整个请求的错误
+func FromBindError(err error) Errors {
+	var errs Errors
+
+	var validationErrs validator.ValidationErrors
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &validationErrs):
+		for _, fieldErr := range validationErrs {
+			errs = append(errs, &FieldError{
+				Field:      fieldPath(fieldErr.Namespace()),
+				Message:    constraintMessage(fieldErr),
+				Constraint: fieldErr.Tag(),
+				Param:      fieldErr.Param(),
+				Type:       jsonType(fieldErr.Value()),
+			})
+		}
+	case errors.As(err, &typeErr):
+		errs = append(errs, &FieldError{
+			Field:      typeErr.Field,
+			Message:    fmt.Sprintf("must be %s", article(jsonKind(typeErr.Type))),
+			Constraint: "type",
+			Param:      jsonKind(typeErr.Type),
+			Type:       jsonValueName(typeErr.Value),
+		})
+	case errors.As(err, &syntaxErr):
+		errs = append(errs, &FieldError{Message: fmt.Sprintf("malformed JSON at offset %d: %v", syntaxErr.Offset, syntaxErr), Constraint: "json"})
+	case errors.Is(err, io.EOF):
+		errs = append(errs, &FieldError{Message: "request body is empty", Constraint: "required"})
+	default:
+		errs = append(errs, &FieldError{Message: err.Error()})
+	}
+	return errs
+}
+
+// fieldPath 去掉命名空间开头的结构体名，如 BulkMessageRequest.recipients[2].contact 得到 recipients[2].contact
+func fieldPath(namespace string) string {
+	if i := strings.Index(namespace, "."); i >= 0 {
+		return namespace[i+1:]
+	}
+	return namespace
+}
+
+// constraintMessage 生成校验规则失败的说明
+func constraintMessage(fieldErr validator.FieldError) string {
+	param := fieldErr.Param()
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "required_without":
+		return fmt.Sprintf("is required when %s is not set", param)
+	case "required_if":
+		if parts := strings.Fields(param); len(parts) == 2 {
+			return fmt.Sprintf("is required when %s is %s", strings.ToLower(parts[0]), parts[1])
+		}
+		return "is required"
+	case "oneof":
+		return "must be one of: " + strings.Join(strings.Fields(param), ", ")
+	case "email":
+		return "must be a valid email address"
+	case "url":
+		return "must be a valid URL"
+	case "len":
+		return fmt.Sprintf("must have length %s", param)
+	case "min", "gte":
+		if unit := sizeUnit(fieldErr.Kind()); unit != "" {
+			return fmt.Sprintf("must have at least %s %s", param, unit)
+		}
+		return "must be at least " + param
+	case "max", "lte":
+		if unit := sizeUnit(fieldErr.Kind()); unit != "" {
+			return fmt.Sprintf("must have at most %s %s", param, unit)
+		}
+		return "must be at most " + param
+	case "gt":
+		return "must be greater than " + param
+	case "lt":
+		return "must be less than " + param
+	}
+	if param != "" {
+		return fmt.Sprintf("failed the %s=%s rule", fieldErr.Tag(), param)
+	}
+	return fmt.Sprintf("failed the %s rule", fieldErr.Tag())
+}
+
+// sizeUnit min/max 对字符串限制字符数，对数组和对象限制元素数，对数值为空
+func sizeUnit(kind reflect.Kind) string {
+	switch kind {
+	case reflect.String:
+		return "characters"
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return "items"
+	}
+	return ""
+}
+
+// jsonType 收到的值对应的JSON类型
+func jsonType(value interface{}) string {
+	if value == nil {
+		return "null"
+	}
+	v := reflect.ValueOf(value)
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "null"
+		}
+		v = v.Elem()
+	}
+	return jsonKind(v.Type())
+}
+
+// jsonKind Go类型对应的JSON类型名
+func jsonKind(t reflect.Type) string {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return "string"
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return t.Kind().String()
+}
+
+// jsonValueName 将 encoding/json 描述的值（bool、number 1.5）转换为JSON类型名
+func jsonValueName(value string) string {
+	switch name, _, _ := strings.Cut(value, " "); name {
+	case "bool":
+		return "boolean"
+	default:
+		return name
+	}
+}
+
+// article 为类型名加上不定冠词
+func article(kind string) string {
+	if strings.IndexAny(kind[:1], "aeiou") >= 0 {
+		return "an " + kind
+	}
+	return "a " + kind
+}
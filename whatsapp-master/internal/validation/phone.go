@@ -0,0 +1,89 @@
+package validation
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// NormalizePhone 将号码规范化为 E.164（+ 和8到15位数字），允许空格、横线、括号、点号和斜线分隔，忽略 @c.us 后缀。
+// 以 + 或 00 开头的号码视为国际号码。其他号码在countryCode不为空时：以0开头的视为本地号码，去掉0后加上国家代码；
+// 已以国家代码开头的视为已包含国家代码；其余加上国家代码。countryCode为空时号码必须已包含国家代码
+func NormalizePhone(raw, countryCode string) (string, error) {
+	number := strings.TrimSuffix(strings.TrimSpace(raw), "@c.us")
+	if number == "" {
+		return "", fmt.Errorf("phone number is empty")
+	}
+	countryCode = strings.TrimPrefix(countryCode, "+")
+
+	international := false
+	if strings.HasPrefix(number, "+") {
+		international = true
+		number = number[1:]
+	}
+	number = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '-', '(', ')', '.', '/':
+			return -1
+		}
+		return r
+	}, number)
+	if !IsDigits(number) {
+		return "", fmt.Errorf("phone number %q contains invalid characters", raw)
+	}
+	if !international && strings.HasPrefix(number, "00") {
+		international = true
+		number = number[2:]
+	}
+	if !international && countryCode != "" {
+		switch {
+		case strings.HasPrefix(number, "0"):
+			number = countryCode + strings.TrimLeft(number, "0")
+		case !strings.HasPrefix(number, countryCode):
+			number = countryCode + number
+		}
+	}
+
+	if strings.HasPrefix(number, "0") {
+		return "", fmt.Errorf("phone number %q has no country code", raw)
+	}
+	if len(number) < 8 || len(number) > 15 {
+		return "", fmt.Errorf("phone number %q must have 8 to 15 digits including the country code", raw)
+	}
+	return "+" + number, nil
+}
+
+// WhatsAppNumber 规范化号码并去掉 +，得到WhatsApp和Worker使用的纯数字形式，如 8613800138000
+func WhatsAppNumber(raw, countryCode string) (string, error) {
+	number, err := NormalizePhone(raw, countryCode)
+	if err != nil {
+		return "", err
+	}
+	return number[1:], nil
+}
+
+// NormalizeContact 规范化消息收件人：群组等WhatsApp ID（含 @ 但不是 @c.us）和联系人名称（含字母且不以 + 开头）保持不变，
+// 号码规范化为纯数字形式，格式错误时返回错误
+func NormalizeContact(contact, countryCode string) (string, error) {
+	contact = strings.TrimSpace(contact)
+	if strings.Contains(contact, "@") && !strings.HasSuffix(contact, "@c.us") {
+		return contact, nil
+	}
+	if !strings.HasPrefix(contact, "+") && strings.IndexFunc(contact, unicode.IsLetter) >= 0 {
+		return contact, nil
+	}
+	return WhatsAppNumber(contact, countryCode)
+}
+
+// IsDigits 字符串是否非空且只包含数字
+func IsDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,42 @@
+// Package validation 请求模型的字段校验和规范化，在请求到达Worker之前拒绝格式错误的输入
+package validation
+
+import "strings"
+
+// FieldError 单个字段的校验错误
+type FieldError struct {
+	Field      string `json:"field"` // 字段路径，如 recipients[2].contact，为空表示整个请求
+	Value      string `json:"value,omitempty"`
+	Message    string `json:"message"`
+	Constraint string `json:"constraint,omitempty"` // 失败的校验规则，如 required、max、oneof、type
+	Param      string `json:"param,omitempty"`      // 规则参数，如 max=100 中的 100
+	Type       string `json:"type,omitempty"`       // 收到的值的JSON类型
+}
+
+// Errors 一次请求中所有字段的校验错误
+type Errors []*FieldError
+
+// Add 记录一个字段错误
+func (e *Errors) Add(field, value string, err error) {
+	*e = append(*e, &FieldError{Field: field, Value: value, Message: err.Error()})
+}
+
+// Err 没有错误时返回nil，避免返回非nil的空切片
+func (e Errors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+func (e Errors) Error() string {
+	messages := make([]string, 0, len(e))
+	for _, fieldErr := range e {
+		if fieldErr.Field == "" {
+			messages = append(messages, fieldErr.Message)
+			continue
+		}
+		messages = append(messages, fieldErr.Field+": "+fieldErr.Message)
+	}
+	return strings.Join(messages, "; ")
+}
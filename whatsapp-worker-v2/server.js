@@ -1,7 +1,12 @@
 const express = require('express');
 const bodyParser = require('body-parser');
 const path = require('path');
+const fs = require('fs');
 const WhatsAppService = require('./src/WhatsAppService');
+const { version: workerVersion } = require('./package.json');
+
+// Worker HTTP API 版本，接口有不兼容变更时递增；Master 据此检查是否在支持范围内
+const WORKER_API_VERSION = 1;
 
 const app = express();
 const port = process.env.PORT || 4000;
@@ -13,14 +18,90 @@ const service = new WhatsAppService(sessionDir, accountID);
 // Proactive cleanup on server start
 service.killZombieBrowser().catch(e => console.error("Startup cleanup failed:", e));
 
+// Master 通过 PROXY_* 环境变量传入账号绑定的代理；未设置时使用会话目录中保存的代理
+const envProxy = process.env.PROXY_IP && process.env.PROXY_PORT ? {
+    ip: process.env.PROXY_IP,
+    port: process.env.PROXY_PORT,
+    user: process.env.PROXY_USERNAME || undefined,
+    pwd: process.env.PROXY_PASSWORD || undefined,
+    scheme: process.env.PROXY_PROTOCOL || 'socks5'
+} : null;
+
+// 将 Master 传入的硬件指纹规范化，只有影响浏览器的字段时返回非空
+function normalizeHardware(info) {
+    if (!info || typeof info !== 'object') return null;
+    const hw = {
+        os: info.os || undefined,
+        browser: info.browser || undefined,
+        userAgent: info.user_agent || undefined,
+        screenWidth: Number(info.screen_width) || undefined,
+        screenHeight: Number(info.screen_height) || undefined,
+        locale: info.locale || undefined,
+        timezone: info.timezone || undefined
+    };
+    if (!hw.userAgent && !(hw.screenWidth && hw.screenHeight) && !hw.locale && !hw.timezone) return null;
+    return hw;
+}
+
+// Master 通过 HW_* 环境变量传入账号绑定的硬件指纹配置，HW_SCREEN 格式为 宽x高
+const [envScreenWidth, envScreenHeight] = (process.env.HW_SCREEN || '').split('x');
+service.setHardwareProfile(normalizeHardware({
+    os: process.env.HW_OS,
+    browser: process.env.HW_BROWSER,
+    user_agent: process.env.HW_USER_AGENT,
+    screen_width: envScreenWidth,
+    screen_height: envScreenHeight,
+    locale: process.env.HW_LOCALE,
+    timezone: process.env.HW_TIMEZONE
+}));
+
+// 开启会话加密时会话目录是 tmpfs，Master 解密写入后创建标记文件，在此之前不能恢复或创建会话
+const sessionSealed = process.env.SESSION_SEALED === 'true';
+const sessionReadyMarker = path.join(sessionDir, '.fleet-session-ready');
+let sessionReady = null;
+
+function waitForSession() {
+    if (!sessionSealed || fs.existsSync(sessionReadyMarker)) return Promise.resolve();
+    if (!sessionReady) {
+        sessionReady = new Promise((resolve) => {
+            const startedAt = Date.now();
+            let reportedAt = 0;
+            const timer = setInterval(() => {
+                if (fs.existsSync(sessionReadyMarker)) {
+                    clearInterval(timer);
+                    console.log("Session unsealed by master");
+                    resolve();
+                    return;
+                }
+                // 容器被 Docker 重启后 tmpfs 已清空，Master 不知道需要重新解密，等待一段时间后通知它
+                const now = Date.now();
+                if (now - startedAt >= 10000 && now - reportedAt >= 30000) {
+                    reportedAt = now;
+                    console.log("Waiting for master to unseal the session");
+                    pushEvent({ type: 'session_waiting' });
+                }
+            }, 500);
+        });
+    }
+    return sessionReady;
+}
+
+// 在 timeoutMs 内会话是否已可用
+function sessionUnsealed(timeoutMs) {
+    return Promise.race([
+        waitForSession().then(() => true),
+        new Promise((resolve) => setTimeout(() => resolve(false), timeoutMs))
+    ]);
+}
+
 // 自动尝试初始化 (如果存在session)
 // 延迟一点启动，确保HTTP服务先就绪
-setTimeout(() => {
+setTimeout(async () => {
+    await waitForSession();
     console.log("Checking for existing session to auto-start...");
     // 尝试用 phone 模式启动 (传入 accountID 作为手机号)
     // 如果有 session 它会自动恢复；如果没有，会请求配对码
-    // 注意：这里没有代理配置，如果依赖代理才能上网，可能会失败。
-    service.startLogin("phone", accountID)
+    service.startLogin("phone", accountID, "+86", envProxy)
         .then(status => {
             console.log("Auto-start initiation complete. Status:", status.status);
         })
@@ -29,12 +110,92 @@ setTimeout(() => {
         });
 }, 3000);
 
-app.use(bodyParser.json());
+// 回执变化时回调 Master，MASTER_URL 或 WORKER_TOKEN 未设置时只能由 Master 轮询
+const masterURL = process.env.MASTER_URL;
+const workerToken = process.env.WORKER_TOKEN;
+if (masterURL && workerToken) {
+    service.events.on('ack', (receipt) => {
+        fetch(`${masterURL}/api/v1/worker/accounts/${encodeURIComponent(accountID)}/receipts`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json', 'X-Worker-Token': workerToken },
+            body: JSON.stringify({ receipts: [receipt] }),
+        }).catch(err => console.warn(`[Receipts] Failed to report ack for ${receipt.id}: ${err.message}`));
+    });
+}
+
+// 状态、二维码、入站消息和注销事件推送给 Master，短时间内的事件合并为一次请求；
+// 网络错误或 5xx 时保留事件稍后重试，最多保留 MAX_PENDING_EVENTS 条
+const MAX_PENDING_EVENTS = 500;
+const pendingEvents = [];
+let eventFlushTimer = null;
+
+function pushEvent(event) {
+    if (!masterURL || !workerToken) return;
+    pendingEvents.push({ ...event, timestamp: Date.now() });
+    if (pendingEvents.length > MAX_PENDING_EVENTS) {
+        pendingEvents.splice(0, pendingEvents.length - MAX_PENDING_EVENTS);
+    }
+    scheduleEventFlush(200);
+}
+
+function scheduleEventFlush(delayMs) {
+    if (!eventFlushTimer) {
+        eventFlushTimer = setTimeout(flushEvents, delayMs);
+    }
+}
+
+async function flushEvents() {
+    eventFlushTimer = null;
+    const batch = pendingEvents.splice(0, MAX_PENDING_EVENTS);
+    if (batch.length === 0) return;
+    try {
+        const res = await fetch(`${masterURL}/internal/v1/workers/${encodeURIComponent(accountID)}/events`, {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json', 'X-Worker-Token': workerToken },
+            body: JSON.stringify({ events: batch }),
+        });
+        if (res.status >= 500) {
+            throw new Error(`master returned ${res.status}`);
+        }
+        if (!res.ok) {
+            // 请求被拒绝（凭证错误或格式错误），重试也不会成功
+            console.warn(`[Events] Master rejected ${batch.length} events with status ${res.status}`);
+        }
+    } catch (err) {
+        console.warn(`[Events] Failed to push ${batch.length} events, retrying: ${err.message}`);
+        pendingEvents.unshift(...batch.slice(-(MAX_PENDING_EVENTS - pendingEvents.length)));
+        scheduleEventFlush(5000);
+        return;
+    }
+    if (pendingEvents.length > 0) {
+        scheduleEventFlush(0);
+    }
+}
+
+service.events.on('status', (status) => pushEvent({ type: 'status', status }));
+service.events.on('qr', (qrCode) => pushEvent({ type: 'qr', qr_code: qrCode || '' }));
+service.events.on('logout', (reason) => pushEvent({ type: 'logout', reason }));
+service.events.on('message', (message) => pushEvent({
+    type: 'message',
+    message: {
+        id: message.id,
+        from: message.from,
+        to: message.to,
+        body: message.body || '',
+        type: message.type,
+        timestamp: message.timestamp,
+    },
+}));
+
+app.use(bodyParser.json({ limit: '64mb' }));
 app.use(express.static(path.join(__dirname, 'public')));
 
 app.post('/api/login', async (req, res) => {
     try {
-        const { login_method, phone, login_phone, signin_type, socks5, is_cache_login, disable_qr_fallback, downgrade_timeout_ms } = req.body;
+        if (!(await sessionUnsealed(30000))) {
+            return res.status(503).json({ success: false, error: "Session has not been unsealed by the master yet" });
+        }
+        const { login_method, phone, login_phone, signin_type, socks5, is_cache_login, disable_qr_fallback, downgrade_timeout_ms, hardware_info } = req.body;
         let method = login_method || (signin_type === 40 ? "phone" : "qr");
         let phoneNumber = (phone || login_phone || "").trim();
         if (method === 'phone' && phoneNumber) {
@@ -59,12 +220,19 @@ app.post('/api/login', async (req, res) => {
                      ip: socksObj.ip,
                      port: socksObj.port,
                      user: socksObj.user || socksObj.username,
-                     pwd: socksObj.pwd || socksObj.password
+                     pwd: socksObj.pwd || socksObj.password,
+                     scheme: socksObj.scheme || socksObj.protocol
                  };
              }
         }
 
         console.log(`Login request: Account=${accountID}, Method=${method}, Phone=${phoneNumber}`);
+
+        // 登录请求携带硬件指纹时替换启动时的配置，浏览器重启后生效
+        const hardware = normalizeHardware(hardware_info);
+        if (hardware) {
+            service.setHardwareProfile(hardware);
+        }
         
         // Check if initialization is already in progress
         if (!service.isLoggedIn && ['initializing','waiting_for_code','waiting_for_scan'].includes(service.status)) {
@@ -112,12 +280,46 @@ app.get('/api/login/status', async (req, res) => {
     }
 });
 
+// Master 的登录流程在配对码过期或初始化失败时请求新的配对码
+app.post('/api/pairing-code', async (req, res) => {
+    try {
+        const code = await service.requestPairingCode(req.body && req.body.phone);
+        res.json({ success: true, status: service.status, pairing_code: code });
+    } catch (error) {
+        console.error("Request pairing code failed:", error);
+        const status = service.isLoggedIn ? 409 : 500;
+        res.status(status).json({ success: false, error: error.message });
+    }
+});
+
+app.post('/api/login/refresh', async (req, res) => {
+    try {
+        console.log("Refreshing session");
+        const result = await service.refreshSession();
+        res.json({ success: true, message: "Session refresh started", data: result });
+    } catch (error) {
+        console.error("Refresh session failed:", error);
+        res.status(500).json({ success: false, error: error.message });
+    }
+});
+
+// Docker健康检查使用：HTTP服务正常但浏览器卡死时返回503
+app.get('/api/health', async (req, res) => {
+    const problem = await service.checkHealth();
+    if (problem) {
+        return res.status(503).json({ success: false, status: service.status, error: problem });
+    }
+    res.json({ success: true, status: service.status });
+});
+
 app.get('/api/status', async (req, res) => {
     try {
         const status = await service.getStatusResponse();
         res.json({
             success: true,
             account_id: accountID,
+            version: workerVersion,
+            api_version: WORKER_API_VERSION,
             ...status
         });
     } catch (error) {
@@ -174,6 +376,33 @@ app.get('/api/messages', async (req, res) => {
     }
 });
 
+// 查询出站消息的回执状态，ids 以逗号分隔
+app.get('/api/messages/acks', async (req, res) => {
+    try {
+        const ids = String(req.query.ids || '').split(',').map(id => id.trim()).filter(id => id);
+        const list = await service.getMessageAcks(ids);
+        res.json({ success: true, data: list });
+    } catch (error) {
+        res.status(500).json({ success: false, error: error.message });
+    }
+});
+
+// 抓取与某个联系人的会话历史，before 为毫秒时间戳，用于向前翻页
+app.get('/api/chats/messages', async (req, res) => {
+    try {
+        const contact = String(req.query.contact || '').trim();
+        if (!contact) {
+            return res.status(400).json({ success: false, error: 'contact is required' });
+        }
+        const limit = Math.min(Math.max(parseInt(req.query.limit, 10) || 50, 1), 500);
+        const before = parseInt(req.query.before, 10) || 0;
+        const data = await service.getChatHistory(contact, limit, before);
+        res.json({ success: true, data });
+    } catch (error) {
+        res.status(500).json({ success: false, error: error.message });
+    }
+});
+
 app.get('/api/messages/stream', (req, res) => {
     res.setHeader('Content-Type', 'text/event-stream');
     res.setHeader('Cache-Control', 'no-cache');
@@ -212,6 +441,53 @@ app.post('/api/send-message', async (req, res) => {
     }
 });
 
+// 显示正在输入/录音状态，state 为 typing、recording 或 paused
+app.post('/api/typing', async (req, res) => {
+    try {
+        const { contact, state, duration_ms } = req.body;
+        const recipient = String(contact || '').trim();
+        if (!recipient) {
+            return res.status(400).json({ success: false, error: "Missing contact" });
+        }
+        const chatState = state || 'typing';
+        if (!['typing', 'recording', 'paused'].includes(chatState)) {
+            return res.status(400).json({ success: false, error: "state must be typing, recording or paused" });
+        }
+        const duration = Math.min(Math.max(parseInt(duration_ms, 10) || 0, 0), 60000);
+        const data = await service.sendChatState(recipient, chatState, duration);
+        res.json({ success: true, data });
+    } catch (error) {
+        res.status(500).json({ success: false, error: error.message });
+    }
+});
+
+app.get('/api/presence', async (req, res) => {
+    try {
+        const contact = String(req.query.contact || '').trim();
+        if (!contact) {
+            return res.status(400).json({ success: false, error: 'contact is required' });
+        }
+        const data = await service.getPresence(contact);
+        res.json({ success: true, data });
+    } catch (error) {
+        res.status(500).json({ success: false, error: error.message });
+    }
+});
+
+app.post('/api/send-media', async (req, res) => {
+    try {
+        const { phone, contact, caption, media_type, media } = req.body;
+        const recipient = (phone || contact || '').trim();
+        if (!recipient || !media || !media.data || !media.mimetype) {
+            return res.status(400).json({ success: false, error: "Missing recipient or media" });
+        }
+        const result = await service.sendMedia(recipient, media, caption, media_type);
+        res.json({ success: true, data: result });
+    } catch (error) {
+        res.status(500).json({ success: false, error: error.message });
+    }
+});
+
 app.post('/api/logout', async (req, res) => {
     try {
         await service.logout();
@@ -331,6 +607,75 @@ app.post('/api/groups/participants/add', async (req, res) => {
     }
 });
 
+app.get('/api/groups', async (req, res) => {
+    try {
+        const groups = await service.getGroups();
+        res.json({ success: true, data: groups });
+    } catch (error) {
+        res.status(500).json({ success: false, error: error.message });
+    }
+});
+
+app.get('/api/groups/info', async (req, res) => {
+    try {
+        const { groupId } = req.query;
+        if (!groupId) {
+            return res.status(400).json({ success: false, error: "Missing groupId" });
+        }
+        const result = await service.getGroupInfo(groupId);
+        res.json({ success: true, data: result });
+    } catch (error) {
+        res.status(500).json({ success: false, error: error.message });
+    }
+});
+
+// 移除成员、设置/取消管理员
+const groupParticipantActions = {
+    remove: 'removeParticipants',
+    promote: 'promoteParticipants',
+    demote: 'demoteParticipants'
+};
+Object.entries(groupParticipantActions).forEach(([action, method]) => {
+    app.post(`/api/groups/participants/${action}`, async (req, res) => {
+        try {
+            const { groupId, participants } = req.body;
+            if (!groupId || !participants || !Array.isArray(participants)) {
+                return res.status(400).json({ success: false, error: "Invalid parameters" });
+            }
+            const result = await service[method](groupId, participants);
+            res.json({ success: true, data: result });
+        } catch (error) {
+            res.status(500).json({ success: false, error: error.message });
+        }
+    });
+});
+
+app.post('/api/groups/subject', async (req, res) => {
+    try {
+        const { groupId, name } = req.body;
+        if (!groupId || !name) {
+            return res.status(400).json({ success: false, error: "Invalid parameters" });
+        }
+        const result = await service.setGroupSubject(groupId, name);
+        res.json({ success: true, data: result });
+    } catch (error) {
+        res.status(500).json({ success: false, error: error.message });
+    }
+});
+
+app.get('/api/groups/invite', async (req, res) => {
+    try {
+        const { groupId } = req.query;
+        if (!groupId) {
+            return res.status(400).json({ success: false, error: "Missing groupId" });
+        }
+        const result = await service.getGroupInviteCode(groupId);
+        res.json({ success: true, data: result });
+    } catch (error) {
+        res.status(500).json({ success: false, error: error.message });
+    }
+});
+
 app.listen(port, () => {
     console.log(`Worker V2 listening on port ${port} for account ${accountID}`);
 });
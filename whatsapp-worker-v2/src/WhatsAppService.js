@@ -1,4 +1,4 @@
-const { Client, LocalAuth } = require('whatsapp-web.js');
+const { Client, LocalAuth, MessageMedia } = require('whatsapp-web.js');
 const qrcode = require('qrcode');
 const fs = require('fs-extra');
 const path = require('path');
@@ -22,10 +22,13 @@ class WhatsAppService {
         this.localProxyUrl = null;
         this.events = new EventEmitter();
         this.recentMessages = [];
+        this.messageAcks = new Map(); // 出站消息ID -> ack
         this.lastError = null;
         this.eventLog = [];
         this.currentProxyConfig = null;
         this.initMethod = null; // Track initialization method (qr/phone)
+        this.hardwareProfile = null; // 账号绑定的硬件指纹（UA、屏幕、语言、时区），每次启动浏览器时使用
+        this.pairingPhone = null; // 最近一次手机号登录的号码，请求新的配对码时使用
         
         // Proactive cleanup of SingletonLock on startup
         try {
@@ -183,6 +186,9 @@ class WhatsAppService {
 
         // Set init method
         this.initMethod = method;
+        if (method === 'phone' && phoneNumber) {
+            this.pairingPhone = String(phoneNumber).replace(/\D/g, '');
+        }
 
         // Proactive cleanup before starting new client
         this.cleanupSession();
@@ -234,11 +240,16 @@ class WhatsAppService {
              }
          }
  
+        this.applyHardwareProfile(puppeteerOptions);
+
         const clientOptions = {
             authStrategy: new LocalAuth({ dataPath: this.sessionDir, clientId: this.accountId }),
             puppeteer: puppeteerOptions,
             authTimeoutMs: 0 // Disable internal auth timeout, we handle it in startLogin
         };
+        if (this.hardwareProfile && this.hardwareProfile.userAgent) {
+            clientOptions.userAgent = this.hardwareProfile.userAgent;
+        }
 
         if (method === 'phone' && phoneNumber) {
             phoneNumber = String(phoneNumber).replace(/\D/g, '');
@@ -381,6 +392,8 @@ class WhatsAppService {
             }
             this.status = 'waiting_for_scan';
             this.eventLog.push({ ts: Date.now(), level: 'info', msg: 'qr_received' });
+            this.events.emit('qr', this.qrCode);
+            this.events.emit('status', this.status);
         });
 
         this.client.on('code', (code) => {
@@ -388,6 +401,7 @@ class WhatsAppService {
             this.pairingCode = code;
             this.status = 'waiting_for_code';
             this.eventLog.push({ ts: Date.now(), level: 'info', msg: 'pairing_code', detail: code });
+            this.events.emit('status', this.status);
         });
 
         this.client.on('ready', () => {
@@ -397,6 +411,8 @@ class WhatsAppService {
             this.qrCode = null;
             this.pairingCode = null;
             this.eventLog.push({ ts: Date.now(), level: 'info', msg: 'ready' });
+            this.events.emit('qr', null);
+            this.events.emit('status', this.status);
         });
 
         this.client.on('authenticated', () => {
@@ -404,6 +420,7 @@ class WhatsAppService {
             this.isLoggedIn = true;
             this.status = 'logged_in';
             this.eventLog.push({ ts: Date.now(), level: 'info', msg: 'authenticated' });
+            this.events.emit('status', this.status);
         });
         this.client.on('message', (msg) => {
             // Filter system messages
@@ -430,12 +447,23 @@ class WhatsAppService {
             this.events.emit('message', data);
         });
         
+        this.client.on('message_ack', (msg, ack) => {
+            const id = msg.id && msg.id._serialized ? msg.id._serialized : undefined;
+            if (!id || !msg.fromMe) return;
+            this.messageAcks.set(id, ack);
+            if (this.messageAcks.size > 1000) {
+                this.messageAcks.delete(this.messageAcks.keys().next().value);
+            }
+            this.events.emit('ack', { id, ack });
+        });
+
         this.client.on('auth_failure', (msg) => {
             console.error('Auth failure:', msg);
             this.isLoggedIn = false;
             this.status = 'auth_failure';
             this.lastError = typeof msg === 'string' ? msg : JSON.stringify(msg);
             this.eventLog.push({ ts: Date.now(), level: 'error', msg: 'auth_failure', detail: this.lastError });
+            this.events.emit('status', this.status);
         });
         
         this.client.on('disconnected', (reason) => {
@@ -444,6 +472,7 @@ class WhatsAppService {
             this.status = 'disconnected';
             this.client = null;
             this.eventLog.push({ ts: Date.now(), level: 'warn', msg: 'disconnected', detail: reason });
+            this.events.emit('logout', String(reason || ''));
         });
     }
 
@@ -639,6 +668,73 @@ class WhatsAppService {
         }
     }
 
+    async getGroupChat(groupId) {
+        if (!this.client || !this.isLoggedIn) throw new Error("Not logged in");
+        const chat = await this.client.getChatById(groupId);
+        if (!chat || !chat.isGroup) throw new Error("Target chat is not a group");
+        return chat;
+    }
+
+    formatGroup(chat) {
+        const meta = chat.groupMetadata || {};
+        return {
+            id: chat.id._serialized,
+            name: chat.name,
+            description: meta.desc || '',
+            owner: meta.owner ? meta.owner._serialized : '',
+            participants: (chat.participants || []).map(p => ({
+                id: p.id._serialized,
+                isAdmin: !!p.isAdmin,
+                isSuperAdmin: !!p.isSuperAdmin
+            }))
+        };
+    }
+
+    async getGroups() {
+        if (!this.client || !this.isLoggedIn) return [];
+        try {
+            const chats = await this.client.getChats();
+            return chats.filter(c => c.isGroup).map(c => this.formatGroup(c));
+        } catch (err) {
+            console.error("Get groups failed:", err);
+            this.handleCriticalError(err);
+            return [];
+        }
+    }
+
+    async getGroupInfo(groupId) {
+        const chat = await this.getGroupChat(groupId);
+        return this.formatGroup(chat);
+    }
+
+    async removeParticipants(groupId, participants) {
+        const chat = await this.getGroupChat(groupId);
+        return await chat.removeParticipants(participants);
+    }
+
+    async promoteParticipants(groupId, participants) {
+        const chat = await this.getGroupChat(groupId);
+        return await chat.promoteParticipants(participants);
+    }
+
+    async demoteParticipants(groupId, participants) {
+        const chat = await this.getGroupChat(groupId);
+        return await chat.demoteParticipants(participants);
+    }
+
+    async setGroupSubject(groupId, name) {
+        const chat = await this.getGroupChat(groupId);
+        const ok = await chat.setSubject(name);
+        if (!ok) throw new Error("Failed to rename group (admin rights required)");
+        return this.formatGroup(await this.getGroupChat(groupId));
+    }
+
+    async getGroupInviteCode(groupId) {
+        const chat = await this.getGroupChat(groupId);
+        const code = await chat.getInviteCode();
+        return { code, link: `https://chat.whatsapp.com/${code}` };
+    }
+
 
     async getExternalIp() {
         if (!this.client) return "Unknown (Client not ready)";
@@ -728,6 +824,78 @@ class WhatsAppService {
 
     async sendMessage(to, message) {
         if (!this.client || !this.isLoggedIn) throw new Error("Not logged in");
+        const chatId = await this.resolveChatId(to);
+        try {
+            return await this.client.sendMessage(chatId, message);
+        } catch (err) {
+            const errMsg = err.message || String(err);
+            if (errMsg === 't' || !errMsg) {
+                throw new Error("Failed to send message: Internal protocol error");
+            }
+            throw err;
+        }
+    }
+
+    async sendMedia(to, media, caption = "", mediaType = "document") {
+        if (!this.client || !this.isLoggedIn) throw new Error("Not logged in");
+        const chatId = await this.resolveChatId(to);
+        const messageMedia = new MessageMedia(media.mimetype, media.data, media.filename || null);
+        const options = {};
+        if (caption) options.caption = caption;
+        if (mediaType === 'document') options.sendMediaAsDocument = true;
+        if (mediaType === 'audio' && media.voice) options.sendAudioAsVoice = true;
+        try {
+            return await this.client.sendMessage(chatId, messageMedia, options);
+        } catch (err) {
+            const errMsg = err.message || String(err);
+            if (errMsg === 't' || !errMsg) {
+                throw new Error("Failed to send media: Internal protocol error");
+            }
+            throw err;
+        }
+    }
+
+    // 在会话中显示正在输入/录音状态，paused 清除状态；durationMs 大于0时到时自动清除
+    async sendChatState(to, state = "typing", durationMs = 0) {
+        if (!this.client || !this.isLoggedIn) throw new Error("Not logged in");
+        const chat = await this.client.getChatById(await this.resolveChatId(to));
+        if (state === "recording") {
+            await chat.sendStateRecording();
+        } else if (state === "paused") {
+            await chat.clearState();
+        } else {
+            await chat.sendStateTyping();
+        }
+        if (state !== "paused" && durationMs > 0) {
+            setTimeout(() => chat.clearState().catch(() => {}), durationMs);
+        }
+        return { chat_id: chat.id._serialized, state, duration_ms: state === "paused" ? 0 : durationMs };
+    }
+
+    // 查询联系人的在线状态：订阅对方的 presence 后读取，对方隐藏了在线状态时 online 为 null
+    async getPresence(to) {
+        if (!this.client || !this.isLoggedIn) throw new Error("Not logged in");
+        const chatId = await this.resolveChatId(to);
+        const presence = await this.client.pupPage.evaluate(async (id) => {
+            const collection = window.Store.PresenceCollection || window.Store.Presence;
+            if (!collection) return null;
+            const wid = window.Store.WidFactory.createWid(id);
+            const model = collection.get(wid) || (typeof collection.find === 'function' ? await collection.find(wid) : null);
+            if (!model) return null;
+            if (typeof model.subscribe === 'function') {
+                try { await model.subscribe(); } catch (e) { /* 订阅失败时返回已知状态 */ }
+            }
+            const chatstate = model.chatstate || (model.chatstates && model.chatstates.getModelsArray ? model.chatstates.getModelsArray()[0] : null);
+            return {
+                online: typeof model.isOnline === 'boolean' ? model.isOnline : null,
+                state: chatstate && chatstate.type ? chatstate.type : null,
+                last_seen: chatstate && chatstate.t ? chatstate.t * 1000 : null
+            };
+        }, chatId);
+        return { chat_id: chatId, ...(presence || { online: null, state: null, last_seen: null }) };
+    }
+
+    async resolveChatId(to) {
         let chatId = to;
         if (!chatId.includes('@')) {
             if (/^\d+$/.test(chatId)) {
@@ -746,15 +914,7 @@ class WhatsAppService {
                 }
             }
         }
-        try {
-            return await this.client.sendMessage(chatId, message);
-        } catch (err) {
-            const errMsg = err.message || String(err);
-            if (errMsg === 't' || !errMsg) {
-                throw new Error("Failed to send message: Internal protocol error");
-            }
-            throw err;
-        }
+        return chatId;
     }
     
     async getStatusResponse() {
@@ -773,10 +933,88 @@ class WhatsAppService {
          };
     }
     
+    // 健康检查：浏览器已断开或页面在超时内无响应时返回错误原因，未启动客户端时视为健康
+    async checkHealth(timeoutMs = 5000) {
+        if (!this.client || !this.client.pupPage) {
+            return null;
+        }
+        const browser = this.client.pupBrowser || this.client.pupPage.browser();
+        if (browser && !browser.isConnected()) {
+            return "browser disconnected";
+        }
+        let timer;
+        try {
+            await Promise.race([
+                this.client.pupPage.evaluate(() => true),
+                new Promise((_, reject) => { timer = setTimeout(() => reject(new Error("page not responding")), timeoutMs); })
+            ]);
+        } catch (e) {
+            return e.message;
+        } finally {
+            clearTimeout(timer);
+        }
+        return null;
+    }
+
     async getRecentMessages() {
         return this.recentMessages.slice(-100);
     }
     
+    async getMessageAcks(ids) {
+        const result = [];
+        for (const id of ids) {
+            let ack = this.messageAcks.get(id);
+            if (ack === undefined && this.client && this.isLoggedIn) {
+                try {
+                    const msg = await this.client.getMessageById(id);
+                    if (msg) ack = msg.ack;
+                } catch (e) {
+                    // 消息可能已不在本地存储中
+                }
+            }
+            if (ack !== undefined) result.push({ id, ack });
+        }
+        return result;
+    }
+
+    // 抓取会话历史：返回早于 before（毫秒时间戳）的最近 limit 条消息，按时间从旧到新排列，
+    // 本地不够时逐步扩大 fetchMessages 的数量让 WhatsApp Web 加载更早的消息
+    async getChatHistory(to, limit = 50, before = 0) {
+        if (!this.client || !this.isLoggedIn) {
+            throw new Error("Client not logged in");
+        }
+        const maxFetch = 5000;
+        const allowedTypes = ['chat', 'image', 'video', 'audio', 'ptt', 'document', 'sticker', 'location', 'vcard'];
+        const chat = await this.client.getChatById(await this.resolveChatId(to));
+
+        let fetchLimit = Math.min(limit * 2, maxFetch);
+        let messages = [];
+        let exhausted = false;
+        for (;;) {
+            const batch = await chat.fetchMessages({ limit: fetchLimit });
+            exhausted = batch.length < fetchLimit;
+            messages = batch.filter(msg => allowedTypes.includes(msg.type) && (!before || msg.timestamp * 1000 < before));
+            if (messages.length > limit || exhausted || fetchLimit >= maxFetch) break;
+            fetchLimit = Math.min(fetchLimit * 2, maxFetch);
+        }
+
+        const page = messages.slice(-limit);
+        return {
+            messages: page.map(msg => ({
+                id: msg.id && msg.id._serialized ? msg.id._serialized : undefined,
+                from: msg.from,
+                to: msg.to,
+                from_me: msg.fromMe,
+                body: msg.body,
+                timestamp: msg.timestamp * 1000,
+                type: msg.type,
+                author: msg.author,
+                ack: msg.ack
+            })),
+            has_more: messages.length > limit || !exhausted
+        };
+    }
+
     async getDebugInfo() {
         return {
             success: true,
@@ -852,6 +1090,67 @@ class WhatsAppService {
         await this.killZombieBrowser();
      }
 
+    // 为进行中的手机号登录请求新的配对码；客户端未启动或初始化失败时重新发起手机号登录
+    async requestPairingCode(phoneNumber) {
+        if (this.isLoggedIn) {
+            throw new Error("Already logged in");
+        }
+        const phone = String(phoneNumber || this.pairingPhone || '').replace(/\D/g, '');
+        if (!phone) {
+            throw new Error("Missing phone number for pairing code");
+        }
+
+        if (!this.client || !this.client.pupPage || this.status === 'init_failed') {
+            console.log(`[Pairing] Client not ready (${this.status}), restarting phone login`);
+            const result = await this.startLogin('phone', phone, '+86', this.currentProxyConfig, { disable_qr_fallback: true });
+            return result.pairingCode || this.pairingCode;
+        }
+
+        const code = await this.client.requestPairingCode(phone, true);
+        this.pairingPhone = phone;
+        this.pairingCode = code;
+        this.status = 'waiting_for_code';
+        this.eventLog.push({ ts: Date.now(), level: 'info', msg: 'pairing_code_requested', detail: code });
+        this.events.emit('status', this.status);
+        return code;
+    }
+
+    // 设置硬件指纹，下次启动浏览器时生效；传入 null 恢复默认指纹
+    setHardwareProfile(profile) {
+        this.hardwareProfile = profile || null;
+    }
+
+    // 按硬件指纹设置浏览器的UA、窗口大小、语言和时区
+    applyHardwareProfile(puppeteerOptions) {
+        const hw = this.hardwareProfile;
+        if (!hw) return;
+        if (hw.userAgent) {
+            puppeteerOptions.args.push(`--user-agent=${hw.userAgent}`);
+        }
+        if (hw.screenWidth && hw.screenHeight) {
+            puppeteerOptions.args.push(`--window-size=${hw.screenWidth},${hw.screenHeight}`);
+            puppeteerOptions.defaultViewport = { width: hw.screenWidth, height: hw.screenHeight };
+        }
+        const env = { ...process.env };
+        if (hw.locale) {
+            puppeteerOptions.args.push(`--lang=${hw.locale}`);
+            env.LANGUAGE = hw.locale.replace('-', '_');
+        }
+        if (hw.timezone) {
+            env.TZ = hw.timezone;
+        }
+        puppeteerOptions.env = env;
+        console.log(`[Hardware] Using fingerprint: UA=${hw.userAgent || 'default'}, screen=${hw.screenWidth && hw.screenHeight ? `${hw.screenWidth}x${hw.screenHeight}` : 'default'}, locale=${hw.locale || 'default'}, timezone=${hw.timezone || 'default'}`);
+    }
+
+    // 重启客户端以刷新会话：保留本地会话缓存和代理配置，whatsapp-web.js 会从缓存恢复登录
+    async refreshSession() {
+        const proxyConfig = this.currentProxyConfig;
+        await this.destroy();
+        await new Promise(r => setTimeout(r, 1200));
+        return this.startLogin("qr", null, "+86", proxyConfig);
+    }
+
     async logout() {
         if (this.client && this.isLoggedIn) {
             // Update status immediately before async logout
@@ -866,6 +1165,7 @@ class WhatsAppService {
             this.status = 'disconnected'; // Or 'idle'
             this.qrCode = null;
             this.pairingCode = null;
+            this.events.emit('logout', 'logout requested');
         }
     }
 }